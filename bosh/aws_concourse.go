@@ -1,6 +1,8 @@
 package bosh
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -11,34 +13,93 @@ import (
 	"github.com/apparentlymart/go-cidr/cidr"
 )
 
-func (client *AWSClient) deployConcourse(creds []byte, detach bool) ([]byte, error) {
+func (client *AWSClient) deployConcourse(ctx context.Context, creds []byte, detach bool) ([]byte, error) {
+	flagFiles, vs, err := client.concourseDeployFlags(creds)
+	if err != nil {
+		return creds, err
+	}
+
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return creds, fmt.Errorf("failed to retrieve director IP: [%v]", err)
+	}
+
+	err = client.boshCLI.RunAuthenticatedCommand(
+		ctx,
+		"deploy",
+		directorPublicIP,
+		client.config.GetDirectorPassword(),
+		client.config.GetDirectorCACert(),
+		detach,
+		os.Stdout,
+		append(flagFiles, vs...)...)
+	if err != nil {
+		return creds, fmt.Errorf("failed to run bosh deploy with commands %+v: [%v]", flagFiles, err)
+	}
+
+	return ioutil.ReadFile(client.workingdir.PathInWorkingDir(credsFilename))
+}
+
+// Diff returns the changes that deploying the concourse manifest would make, without deploying it
+func (client *AWSClient) Diff(ctx context.Context, creds []byte) (string, error) {
+	flagFiles, vs, err := client.concourseDeployFlags(creds)
+	if err != nil {
+		return "", err
+	}
+
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve director IP: [%v]", err)
+	}
+
+	var diff bytes.Buffer
+	// Copy flagFiles, so that appending cannot write into its backing array. Secrets stay redacted,
+	// as the diff is printed to the terminal and CI logs.
+	flags := append([]string{}, flagFiles...)
+	flags = append(flags, "--dry-run")
+	err = client.boshCLI.RunAuthenticatedCommand(
+		ctx,
+		"deploy",
+		directorPublicIP,
+		client.config.GetDirectorPassword(),
+		client.config.GetDirectorCACert(),
+		false,
+		&diff,
+		append(flags, vs...)...)
+	if err != nil {
+		return "", fmt.Errorf("failed to run bosh deploy --dry-run with commands %+v: [%v]", flagFiles, err)
+	}
+
+	return diff.String(), nil
+}
 
+func (client *AWSClient) concourseDeployFlags(creds []byte) ([]string, []string, error) {
 	err := saveFilesToWorkingDir(client.workingdir, client.provider, creds)
 	if err != nil {
-		return creds, fmt.Errorf("failed saving files to working directory in deployConcourse: [%v]", err)
+		return nil, nil, fmt.Errorf("failed saving files to working directory in deployConcourse: [%v]", err)
 	}
 
 	boshDBAddress, err := client.outputs.Get("BoshDBAddress")
 	if err != nil {
-		return creds, err
+		return nil, nil, err
 	}
 	boshDBPort, err := client.outputs.Get("BoshDBPort")
 	if err != nil {
-		return creds, err
+		return nil, nil, err
 	}
 	atcPublicIP, err := client.outputs.Get("ATCPublicIP")
 	if err != nil {
-		return creds, err
+		return nil, nil, err
 	}
 
 	publicCIDR := client.config.GetPublicCIDR()
 	_, pubCIDR, err1 := net.ParseCIDR(publicCIDR)
 	if err1 != nil {
-		return creds, err
+		return nil, nil, err1
 	}
 	atcPrivateIP, err := cidr.Host(pubCIDR, 8)
 	if err != nil {
-		return creds, err
+		return nil, nil, err
 	}
 
 	vmap := map[string]interface{}{
@@ -89,31 +150,18 @@ func (client *AWSClient) deployConcourse(creds []byte, detach bool) ([]byte, err
 
 	t, err1 := client.buildTagsYaml(vmap["project"], "concourse")
 	if err1 != nil {
-		return creds, err
+		return nil, nil, err1
 	}
 	vmap["tags"] = t
 	flagFiles = append(flagFiles, "--ops-file", client.workingdir.PathInWorkingDir(extraTagsFilename))
 
-	vs := vars(vmap)
-
-	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	workerPoolsFlags, err := workerPoolsOpsFlags(client.workingdir, client.config.GetWorkerPools(), flagFiles)
 	if err != nil {
-		return creds, fmt.Errorf("failed to retrieve director IP: [%v]", err)
+		return nil, nil, err
 	}
+	flagFiles = append(flagFiles, workerPoolsFlags...)
 
-	err = client.boshCLI.RunAuthenticatedCommand(
-		"deploy",
-		directorPublicIP,
-		client.config.GetDirectorPassword(),
-		client.config.GetDirectorCACert(),
-		detach,
-		os.Stdout,
-		append(flagFiles, vs...)...)
-	if err != nil {
-		return creds, fmt.Errorf("failed to run bosh deploy with commands %+v: [%v]", flagFiles, err)
-	}
-
-	return ioutil.ReadFile(client.workingdir.PathInWorkingDir(credsFilename))
+	return flagFiles, vars(vmap), nil
 }
 
 func (client *AWSClient) buildTagsYaml(project interface{}, component string) (string, error) {
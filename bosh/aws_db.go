@@ -19,6 +19,7 @@ import (
 // Opener will open new connections to a given database name.
 type Opener interface {
 	Open(name string) (*sql.DB, error)
+	URI(name string) (string, error)
 	Close() error
 }
 
@@ -30,20 +31,28 @@ type proxyOpener struct {
 }
 
 func (p *proxyOpener) Open(dbName string) (*sql.DB, error) {
-	p.start()
-	u, err := url.Parse(p.baseURI)
+	newURI, err := p.URI(dbName)
 	if err != nil {
 		return nil, err
 	}
-	u.Path = dbName
-	u.Host = p.l.Addr().String()
-	newURI := u.String()
 	connector := connectorFunc(func(_ context.Context) (driver.Conn, error) {
 		return p.d.Open(newURI)
 	})
 	return sql.OpenDB(connector), nil
 }
 
+// URI returns a connection string for the given database which goes through the proxy
+func (p *proxyOpener) URI(dbName string) (string, error) {
+	p.start()
+	u, err := url.Parse(p.baseURI)
+	if err != nil {
+		return "", err
+	}
+	u.Path = dbName
+	u.Host = p.l.Addr().String()
+	return u.String(), nil
+}
+
 func (p *proxyOpener) Close() error {
 	p.start()
 	return p.l.Close()
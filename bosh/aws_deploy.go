@@ -1,6 +1,7 @@
 package bosh
 
 import (
+	"context"
 	"net"
 
 	"github.com/EngineerBetter/control-tower/bosh/internal/boshcli"
@@ -9,23 +10,29 @@ import (
 )
 
 // Deploy implements deploy for AWS client
-func (client *AWSClient) Deploy(state, creds []byte, detach bool) (newState, newCreds []byte, err error) {
-	state, creds, err = client.CreateEnv(state, creds, "")
+func (client *AWSClient) Deploy(ctx context.Context, state, creds []byte, detach bool, checkpoint Checkpoint) (newState, newCreds []byte, err error) {
+	err = runPhase(checkpoint, CreateEnvPhase, func() error {
+		state, creds, err = client.CreateEnv(ctx, state, creds, "")
+		return err
+	})
 	if err != nil {
 		return state, creds, err
 	}
 
-	if err = client.updateCloudConfig(client.boshCLI); err != nil {
+	if err = runPhase(checkpoint, CloudConfigPhase, func() error { return client.updateCloudConfig(ctx, client.boshCLI) }); err != nil {
 		return state, creds, err
 	}
-	if err = client.uploadConcourseStemcell(client.boshCLI); err != nil {
+	if err = runPhase(checkpoint, StemcellPhase, func() error { return client.uploadConcourseStemcell(ctx, client.boshCLI) }); err != nil {
 		return state, creds, err
 	}
-	if err = client.createDefaultDatabases(); err != nil {
+	if err = runPhase(checkpoint, DatabasesPhase, client.createDefaultDatabases); err != nil {
 		return state, creds, err
 	}
 
-	creds, err = client.deployConcourse(creds, detach)
+	err = runPhase(checkpoint, ConcoursePhase, func() error {
+		creds, err = client.deployConcourse(ctx, creds, detach)
+		return err
+	})
 	if err != nil {
 		return state, creds, err
 	}
@@ -34,19 +41,19 @@ func (client *AWSClient) Deploy(state, creds []byte, detach bool) (newState, new
 }
 
 // Locks implements locks for AWS client
-func (client *AWSClient) Locks() ([]byte, error) {
+func (client *AWSClient) Locks(ctx context.Context) ([]byte, error) {
 	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
 	if err != nil {
 		return nil, err
 	}
-	return client.boshCLI.Locks(boshcli.AWSEnvironment{
+	return client.boshCLI.Locks(ctx, boshcli.AWSEnvironment{
 		ExternalIP: directorPublicIP,
 	}, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert())
 
 }
 
 // CreateEnv exposes bosh create-env functionality
-func (client *AWSClient) CreateEnv(state, creds []byte, customOps string) (newState, newCreds []byte, err error) {
+func (client *AWSClient) CreateEnv(ctx context.Context, state, creds []byte, customOps string) (newState, newCreds []byte, err error) {
 	tags, err := splitTags(client.config.GetTags())
 	if err != nil {
 		return state, creds, err
@@ -125,7 +132,7 @@ func (client *AWSClient) CreateEnv(state, creds []byte, customOps string) (newSt
 		return state, creds, err1
 	}
 
-	createEnvFiles, err1 := client.boshCLI.CreateEnv(&boshcli.CreateEnvFiles{StateFileContents: state, VarsFileContents: creds}, boshcli.AWSEnvironment{
+	createEnvFiles, err1 := client.boshCLI.CreateEnv(ctx, &boshcli.CreateEnvFiles{StateFileContents: state, VarsFileContents: creds}, boshcli.AWSEnvironment{
 		InternalCIDR:    client.config.GetPublicCIDR(),
 		InternalGateway: internalGateway.String(),
 		InternalIP:      directorInternalIP.String(),
@@ -165,17 +172,17 @@ func (client *AWSClient) CreateEnv(state, creds []byte, customOps string) (newSt
 }
 
 // Recreate exposes BOSH recreate
-func (client *AWSClient) Recreate() error {
+func (client *AWSClient) Recreate(ctx context.Context) error {
 	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
 	if err != nil {
 		return err
 	}
-	return client.boshCLI.Recreate(boshcli.AWSEnvironment{
+	return client.boshCLI.Recreate(ctx, boshcli.AWSEnvironment{
 		ExternalIP: directorPublicIP,
 	}, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert())
 }
 
-func (client *AWSClient) updateCloudConfig(bosh boshcli.ICLI) error {
+func (client *AWSClient) updateCloudConfig(ctx context.Context, bosh boshcli.ICLI) error {
 	publicSubnetID, err := client.outputs.Get("PublicSubnetID")
 	if err != nil {
 		return err
@@ -231,13 +238,14 @@ func (client *AWSClient) updateCloudConfig(bosh boshcli.ICLI) error {
 		return err
 	}
 
-	return bosh.UpdateCloudConfig(boshcli.AWSEnvironment{
+	return bosh.UpdateCloudConfig(ctx, boshcli.AWSEnvironment{
 		AZ:                  client.config.GetAvailabilityZone(),
 		PublicSubnetID:      publicSubnetID,
 		PrivateSubnetID:     privateSubnetID,
 		ATCSecurityGroup:    aTCSecurityGroupID,
 		VMSecurityGroup:     vMsSecurityGroupID,
 		Spot:                client.config.IsSpot(),
+		WorkerPools:         client.config.GetWorkerPools(),
 		ExternalIP:          directorPublicIP,
 		WorkerType:          client.config.GetWorkerType(),
 		PublicCIDR:          publicCIDR,
@@ -249,12 +257,12 @@ func (client *AWSClient) updateCloudConfig(bosh boshcli.ICLI) error {
 		PrivateCIDRReserved: privateCIDRReserved,
 	}, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert())
 }
-func (client *AWSClient) uploadConcourseStemcell(bosh boshcli.ICLI) error {
+func (client *AWSClient) uploadConcourseStemcell(ctx context.Context, bosh boshcli.ICLI) error {
 	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
 	if err != nil {
 		return err
 	}
-	return bosh.UploadConcourseStemcell(boshcli.AWSEnvironment{
+	return bosh.UploadConcourseStemcell(ctx, boshcli.AWSEnvironment{
 		ExternalIP: directorPublicIP,
 	}, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert())
 }
@@ -1,15 +1,20 @@
 package bosh
 
-import "fmt"
+import (
+	"context"
+
+	"fmt"
+)
 
 // Instances returns the list of Concourse VMs
-func (client *AWSClient) Instances() ([]Instance, error) {
+func (client *AWSClient) Instances(ctx context.Context) ([]Instance, error) {
 	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve director IP: [%v]", err)
 	}
 
 	return instances(
+		ctx,
 		client.boshCLI,
 		directorPublicIP,
 		client.config.GetDirectorPassword(),
@@ -0,0 +1,42 @@
+package bosh
+
+import (
+	"io"
+
+	"github.com/EngineerBetter/control-tower/bosh/internal/boshcli"
+	"github.com/EngineerBetter/control-tower/bosh/internal/workingdir"
+	"github.com/EngineerBetter/control-tower/config"
+	"github.com/EngineerBetter/control-tower/iaas"
+	"github.com/EngineerBetter/control-tower/terraform"
+)
+
+//AzureClient is an Azure specific implementation of IClient
+type AzureClient struct {
+	config      config.ConfigView
+	outputs     terraform.Outputs
+	workingdir  workingdir.IClient
+	stdout      io.Writer
+	stderr      io.Writer
+	provider    iaas.Provider
+	boshCLI     boshcli.ICLI
+	versionFile []byte
+}
+
+//NewAzureClient returns an Azure specific implementation of IClient
+func NewAzureClient(config config.ConfigView, outputs terraform.Outputs, workingdir workingdir.IClient, stdout, stderr io.Writer, provider iaas.Provider, boshCLI boshcli.ICLI, versionFile []byte) (IClient, error) {
+	return &AzureClient{
+		config:      config,
+		outputs:     outputs,
+		workingdir:  workingdir,
+		stdout:      stdout,
+		stderr:      stderr,
+		provider:    provider,
+		boshCLI:     boshCLI,
+		versionFile: versionFile,
+	}, nil
+}
+
+//Cleanup is Azure specific implementation of Cleanup
+func (client *AzureClient) Cleanup() error {
+	return client.workingdir.Cleanup()
+}
@@ -0,0 +1,194 @@
+package bosh
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/EngineerBetter/control-tower/db"
+	"github.com/apparentlymart/go-cidr/cidr"
+)
+
+func (client *AzureClient) deployConcourse(ctx context.Context, creds []byte, detach bool) ([]byte, error) {
+	flagFiles, vs, err := client.concourseDeployFlags(creds)
+	if err != nil {
+		return creds, err
+	}
+
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return creds, fmt.Errorf("failed to retrieve director IP: [%v]", err)
+	}
+
+	err = client.boshCLI.RunAuthenticatedCommand(
+		ctx,
+		"deploy",
+		directorPublicIP,
+		client.config.GetDirectorPassword(),
+		client.config.GetDirectorCACert(),
+		detach,
+		os.Stdout,
+		append(flagFiles, vs...)...)
+	if err != nil {
+		return creds, fmt.Errorf("failed to run bosh deploy with commands %+v: [%v]", flagFiles, err)
+	}
+
+	return ioutil.ReadFile(client.workingdir.PathInWorkingDir(credsFilename))
+}
+
+// Diff returns the changes that deploying the concourse manifest would make, without deploying it
+func (client *AzureClient) Diff(ctx context.Context, creds []byte) (string, error) {
+	flagFiles, vs, err := client.concourseDeployFlags(creds)
+	if err != nil {
+		return "", err
+	}
+
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve director IP: [%v]", err)
+	}
+
+	var diff bytes.Buffer
+	// Copy flagFiles, so that appending cannot write into its backing array. Secrets stay redacted,
+	// as the diff is printed to the terminal and CI logs.
+	flags := append([]string{}, flagFiles...)
+	flags = append(flags, "--dry-run")
+	err = client.boshCLI.RunAuthenticatedCommand(
+		ctx,
+		"deploy",
+		directorPublicIP,
+		client.config.GetDirectorPassword(),
+		client.config.GetDirectorCACert(),
+		false,
+		&diff,
+		append(flags, vs...)...)
+	if err != nil {
+		return "", fmt.Errorf("failed to run bosh deploy --dry-run with commands %+v: [%v]", flagFiles, err)
+	}
+
+	return diff.String(), nil
+}
+
+func (client *AzureClient) concourseDeployFlags(creds []byte) ([]string, []string, error) {
+	err := saveFilesToWorkingDir(client.workingdir, client.provider, creds)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed saving files to working directory in deployConcourse: [%v]", err)
+	}
+
+	boshDBAddress, err := client.outputs.Get("BoshDBAddress")
+	if err != nil {
+		return nil, nil, err
+	}
+	atcPublicIP, err := client.outputs.Get("ATCPublicIP")
+	if err != nil {
+		return nil, nil, err
+	}
+	networkName, err := client.outputs.Get("Network")
+	if err != nil {
+		return nil, nil, err
+	}
+	dbLogin, err := client.azureDBLogin()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	uaaCertPath, err := client.workingdir.SaveFileToWorkingDir(uaaCertFilename, uaaCert)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	publicCIDR := client.config.GetPublicCIDR()
+	_, pubCIDR, err1 := net.ParseCIDR(publicCIDR)
+	if err1 != nil {
+		return nil, nil, err1
+	}
+	atcPrivateIP, err := cidr.Host(pubCIDR, 7)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	vmap := map[string]interface{}{
+		"deployment_name":          concourseDeploymentName,
+		"domain":                   client.config.GetDomain(),
+		"project":                  client.config.GetProject(),
+		"web_network_name":         "public",
+		"worker_network_name":      "private",
+		"postgres_host":            boshDBAddress,
+		"postgres_role":            dbLogin,
+		"postgres_port":            "5432",
+		"postgres_password":        client.config.GetRDSPassword(),
+		"postgres_ca_cert":         db.AzureRootCert,
+		"web_vm_type":              "concourse-web-" + client.config.GetConcourseWebSize(),
+		"worker_vm_type":           "concourse-" + client.config.GetConcourseWorkerSize(),
+		"worker_count":             client.config.GetConcourseWorkerCount(),
+		"atc_eip":                  atcPublicIP,
+		"external_tls.certificate": client.config.GetConcourseCert(),
+		"external_tls.private_key": client.config.GetConcourseKey(),
+		"atc_encryption_key":       client.config.GetEncryptionKey(),
+		"network_name":             networkName,
+		"web_static_ip":            atcPrivateIP.String(),
+		"enable_global_resources":  client.config.GetEnableGlobalResources(),
+	}
+
+	flagFiles := []string{
+		client.workingdir.PathInWorkingDir(concourseManifestFilename),
+		"--vars-store",
+		client.workingdir.PathInWorkingDir(credsFilename),
+		"--ops-file",
+		client.workingdir.PathInWorkingDir(concourseVersionsFilename),
+		"--ops-file",
+		client.workingdir.PathInWorkingDir(concourseSHAsFilename),
+		"--ops-file",
+		client.workingdir.PathInWorkingDir(concourseCompatibilityFilename),
+		"--ops-file",
+		uaaCertPath,
+		"--vars-file",
+		client.workingdir.PathInWorkingDir(concourseGrafanaFilename),
+	}
+
+	if client.config.GetConcoursePassword() != "" {
+		vmap["atc_password"] = client.config.GetConcoursePassword()
+	}
+
+	if client.config.IsGithubAuthSet() {
+		vmap["github_client_id"] = client.config.GetGithubClientID()
+		vmap["github_client_secret"] = client.config.GetGithubClientSecret()
+		flagFiles = append(flagFiles, "--ops-file", client.workingdir.PathInWorkingDir(concourseGitHubAuthFilename))
+	}
+
+	t, err1 := client.buildTagsYaml(vmap["project"], "concourse")
+	if err1 != nil {
+		return nil, nil, err1
+	}
+	vmap["tags"] = t
+	flagFiles = append(flagFiles, "--ops-file", client.workingdir.PathInWorkingDir(extraTagsFilename))
+
+	workerPoolsFlags, err := workerPoolsOpsFlags(client.workingdir, client.config.GetWorkerPools(), flagFiles)
+	if err != nil {
+		return nil, nil, err
+	}
+	flagFiles = append(flagFiles, workerPoolsFlags...)
+
+	return flagFiles, vars(vmap), nil
+}
+
+func (client *AzureClient) buildTagsYaml(project interface{}, component string) (string, error) {
+	var b strings.Builder
+
+	for _, e := range client.config.GetTags() {
+		kv := strings.Join(strings.Split(e, "="), ": ")
+		_, err := fmt.Fprintf(&b, "%s,", kv)
+		if err != nil {
+			return "", err
+		}
+	}
+	cProjectTag := fmt.Sprintf("control-tower-project: %v,", project)
+	b.WriteString(cProjectTag)
+	cComponentTag := fmt.Sprintf("control-tower-component: %s", component)
+	b.WriteString(cComponentTag)
+	return fmt.Sprintf("{%s}", b.String()), nil
+}
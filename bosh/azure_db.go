@@ -0,0 +1,61 @@
+package bosh
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/lib/pq"
+	"golang.org/x/crypto/ssh"
+)
+
+// azureDefaultDatabase is the maintenance database every Azure Database for PostgreSQL server has
+const azureDefaultDatabase = "postgres"
+
+// azureDBLogin returns the login for the Azure Database for PostgreSQL server, which
+// must be qualified with the name of the server
+func (client *AzureClient) azureDBLogin() (string, error) {
+	dbName, err := client.outputs.Get("DBName")
+	if err != nil {
+		return "", fmt.Errorf("failed to get DBName from terraform outputs: [%v]", err)
+	}
+	return fmt.Sprintf("%s@%s", client.config.GetRDSUsername(), dbName), nil
+}
+
+// openDB proxies connections to Azure Database for PostgreSQL through the director
+func (client *AzureClient) openDB() (Opener, error) {
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get DirectorPublicIP from terraform outputs: [%v]", err)
+	}
+	key, err := ssh.ParsePrivateKey([]byte(client.config.GetPrivateKey()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key for bosh: [%v]", err)
+	}
+	conf := &ssh.ClientConfig{
+		User:            GatewayUser(client.provider),
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(key)},
+	}
+	boshDBAddress, err := client.outputs.Get("BoshDBAddress")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get BoshDBAddress from terraform outputs: [%v]", err)
+	}
+	login, err := client.azureDBLogin()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := newProxyOpener(net.JoinHostPort(directorPublicIP, "22"), conf, &pq.Driver{},
+		fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=require",
+			url.QueryEscape(login),
+			client.config.GetRDSPassword(),
+			net.JoinHostPort(boshDBAddress, "5432"),
+			azureDefaultDatabase,
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create db proxyOpener: [%v]", err)
+	}
+	return db, nil
+}
@@ -0,0 +1,238 @@
+package bosh
+
+import (
+	"context"
+	"net"
+
+	"github.com/EngineerBetter/control-tower/bosh/internal/boshcli"
+	"github.com/apparentlymart/go-cidr/cidr"
+)
+
+// Deploy deploys a new Bosh director or converges an existing deployment
+// Returns new contents of bosh state file
+func (client *AzureClient) Deploy(ctx context.Context, state, creds []byte, detach bool, checkpoint Checkpoint) (newState, newCreds []byte, err error) {
+	err = runPhase(checkpoint, CreateEnvPhase, func() error {
+		state, creds, err = client.CreateEnv(ctx, state, creds, "")
+		return err
+	})
+	if err != nil {
+		return state, creds, err
+	}
+
+	if err = runPhase(checkpoint, CloudConfigPhase, func() error { return client.updateCloudConfig(ctx, client.boshCLI) }); err != nil {
+		return state, creds, err
+	}
+	if err = runPhase(checkpoint, StemcellPhase, func() error { return client.uploadConcourseStemcell(ctx, client.boshCLI) }); err != nil {
+		return state, creds, err
+	}
+
+	err = runPhase(checkpoint, ConcoursePhase, func() error {
+		creds, err = client.deployConcourse(ctx, creds, detach)
+		return err
+	})
+	if err != nil {
+		return state, creds, err
+	}
+
+	return state, creds, err
+}
+
+// CreateEnv exposes bosh create-env functionality
+func (client *AzureClient) CreateEnv(ctx context.Context, state, creds []byte, customOps string) (newState, newCreds []byte, err error) {
+	tags, err := splitTags(client.config.GetTags())
+	if err != nil {
+		return state, creds, err
+	}
+	tags["control-tower-project"] = client.config.GetProject()
+	tags["control-tower-component"] = "concourse"
+
+	network, err1 := client.outputs.Get("Network")
+	if err1 != nil {
+		return state, creds, err1
+	}
+	networkResourceGroup, err1 := client.outputs.Get("NetworkResourceGroup")
+	if err1 != nil {
+		return state, creds, err1
+	}
+	vmsResourceGroup, err1 := client.outputs.Get("VMsResourceGroup")
+	if err1 != nil {
+		return state, creds, err1
+	}
+	publicSubnetwork, err1 := client.outputs.Get("PublicSubnetworkName")
+	if err1 != nil {
+		return state, creds, err1
+	}
+	directorSecurityGroup, err1 := client.outputs.Get("DirectorSecurityGroupName")
+	if err1 != nil {
+		return state, creds, err1
+	}
+	vmsSecurityGroup, err1 := client.outputs.Get("VMsSecurityGroupName")
+	if err1 != nil {
+		return state, creds, err1
+	}
+	directorPublicIP, err1 := client.outputs.Get("DirectorPublicIP")
+	if err1 != nil {
+		return state, creds, err1
+	}
+
+	attrs := map[string]string{}
+	for _, attr := range []string{"subscription_id", "tenant_id", "client_id", "client_secret"} {
+		attrs[attr], err1 = client.provider.Attr(attr)
+		if err1 != nil {
+			return state, creds, err1
+		}
+	}
+
+	publicCIDR := client.config.GetPublicCIDR()
+	_, pubCIDR, err1 := net.ParseCIDR(publicCIDR)
+	if err1 != nil {
+		return state, creds, err1
+	}
+	internalGateway, err1 := cidr.Host(pubCIDR, 1)
+	if err1 != nil {
+		return state, creds, err1
+	}
+	directorInternalIP, err1 := cidr.Host(pubCIDR, 6)
+	if err1 != nil {
+		return state, creds, err1
+	}
+
+	createEnvFiles, err1 := client.boshCLI.CreateEnv(ctx, &boshcli.CreateEnvFiles{StateFileContents: state, VarsFileContents: creds}, boshcli.AzureEnvironment{
+		ClientID:              attrs["client_id"],
+		ClientSecret:          attrs["client_secret"],
+		CustomOperations:      customOps,
+		DirectorName:          "bosh",
+		DirectorSecurityGroup: directorSecurityGroup,
+		ExternalIP:            directorPublicIP,
+		InternalCIDR:          client.config.GetPublicCIDR(),
+		InternalGW:            internalGateway.String(),
+		InternalIP:            directorInternalIP.String(),
+		Network:               network,
+		NetworkResourceGroup:  networkResourceGroup,
+		PrivateKey:            client.config.GetPrivateKey(),
+		PublicKey:             client.config.GetPublicKey(),
+		PublicSubnetwork:      publicSubnetwork,
+		SubscriptionID:        attrs["subscription_id"],
+		TenantID:              attrs["tenant_id"],
+		VersionFile:           client.versionFile,
+		VMsResourceGroup:      vmsResourceGroup,
+		VMsSecurityGroup:      vmsSecurityGroup,
+	}, client.config.GetDirectorPassword(), client.config.GetDirectorCert(), client.config.GetDirectorKey(), client.config.GetDirectorCACert(), tags)
+	if err1 != nil {
+		return createEnvFiles.StateFileContents, createEnvFiles.VarsFileContents, err1
+	}
+	return createEnvFiles.StateFileContents, createEnvFiles.VarsFileContents, err
+}
+
+// Recreate exposes BOSH recreate
+func (client *AzureClient) Recreate(ctx context.Context) error {
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return err
+	}
+	return client.boshCLI.Recreate(ctx, boshcli.AzureEnvironment{
+		ExternalIP: directorPublicIP,
+	}, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert())
+}
+
+// Locks implements locks for Azure client
+func (client *AzureClient) Locks(ctx context.Context) ([]byte, error) {
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return nil, err
+	}
+	return client.boshCLI.Locks(ctx, boshcli.AzureEnvironment{
+		ExternalIP: directorPublicIP,
+	}, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert())
+}
+
+func (client *AzureClient) updateCloudConfig(ctx context.Context, bosh boshcli.ICLI) error {
+	privateSubnetwork, err := client.outputs.Get("PrivateSubnetworkName")
+	if err != nil {
+		return err
+	}
+	publicSubnetwork, err := client.outputs.Get("PublicSubnetworkName")
+	if err != nil {
+		return err
+	}
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return err
+	}
+	network, err := client.outputs.Get("Network")
+	if err != nil {
+		return err
+	}
+	networkResourceGroup, err := client.outputs.Get("NetworkResourceGroup")
+	if err != nil {
+		return err
+	}
+	vmsResourceGroup, err := client.outputs.Get("VMsResourceGroup")
+	if err != nil {
+		return err
+	}
+	atcSecurityGroup, err := client.outputs.Get("ATCSecurityGroupName")
+	if err != nil {
+		return err
+	}
+
+	publicCIDR := client.config.GetPublicCIDR()
+	_, pubCIDR, err := net.ParseCIDR(publicCIDR)
+	if err != nil {
+		return err
+	}
+	pubGateway, err := cidr.Host(pubCIDR, 1)
+	if err != nil {
+		return err
+	}
+	publicCIDRStatic, err := formatIPRange(publicCIDR, ", ", []int{7})
+	if err != nil {
+		return err
+	}
+	publicCIDRReserved, err := formatIPRange(publicCIDR, "-", []int{1, 5})
+	if err != nil {
+		return err
+	}
+
+	privateCIDR := client.config.GetPrivateCIDR()
+	_, privCIDR, err := net.ParseCIDR(privateCIDR)
+	if err != nil {
+		return err
+	}
+	privGateway, err := cidr.Host(privCIDR, 1)
+	if err != nil {
+		return err
+	}
+	privateCIDRReserved, err := formatIPRange(privateCIDR, "-", []int{1, 5})
+	if err != nil {
+		return err
+	}
+
+	return bosh.UpdateCloudConfig(ctx, boshcli.AzureEnvironment{
+		ATCSecurityGroup:     atcSecurityGroup,
+		Network:              network,
+		NetworkResourceGroup: networkResourceGroup,
+		PrivateCIDR:          privateCIDR,
+		PrivateCIDRGateway:   privGateway.String(),
+		PrivateCIDRReserved:  privateCIDRReserved,
+		PrivateSubnetwork:    privateSubnetwork,
+		PublicCIDR:           publicCIDR,
+		PublicCIDRGateway:    pubGateway.String(),
+		PublicCIDRReserved:   publicCIDRReserved,
+		PublicCIDRStatic:     publicCIDRStatic,
+		PublicSubnetwork:     publicSubnetwork,
+		VMsResourceGroup:     vmsResourceGroup,
+		WorkerPools:          client.config.GetWorkerPools(),
+		Zone:                 client.provider.Zone(client.config.GetAvailabilityZone(), ""),
+	}, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert())
+}
+
+func (client *AzureClient) uploadConcourseStemcell(ctx context.Context, bosh boshcli.ICLI) error {
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return err
+	}
+	return bosh.UploadConcourseStemcell(ctx, boshcli.AzureEnvironment{
+		ExternalIP: directorPublicIP,
+	}, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert())
+}
@@ -0,0 +1,23 @@
+package bosh
+
+import (
+	"context"
+
+	"fmt"
+)
+
+// Instances returns the list of Concourse VMs
+func (client *AzureClient) Instances(ctx context.Context) ([]Instance, error) {
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve director IP: [%v]", err)
+	}
+
+	return instances(
+		ctx,
+		client.boshCLI,
+		directorPublicIP,
+		client.config.GetDirectorPassword(),
+		client.config.GetDirectorCACert(),
+	)
+}
@@ -0,0 +1,154 @@
+package bosh
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/EngineerBetter/control-tower/bosh/internal/boshcli"
+	"github.com/EngineerBetter/control-tower/util"
+)
+
+// BackupDatabaseNames are the databases captured by a backup
+var BackupDatabaseNames = []string{"concourse_atc", "credhub", "uaa"}
+
+var execCommand = util.CommandContext
+
+func dumpDatabases(ctx context.Context, db Opener) (map[string][]byte, error) {
+	dumps := make(map[string][]byte)
+	for _, dbName := range BackupDatabaseNames {
+		uri, err := db.URI(dbName)
+		if err != nil {
+			return nil, err
+		}
+
+		var stdout, stderr bytes.Buffer
+		cmd := execCommand(ctx, "pg_dump", "--format=custom", "--no-owner", "--no-acl", "--dbname", uri)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("failed to dump database %s: [%v] %s", dbName, err, stderr.String())
+		}
+		dumps[dbName] = stdout.Bytes()
+	}
+	return dumps, nil
+}
+
+func restoreDatabases(ctx context.Context, db Opener, dumps map[string][]byte) error {
+	for _, dbName := range BackupDatabaseNames {
+		if _, ok := dumps[dbName]; !ok {
+			return fmt.Errorf("backup does not contain database %s", dbName)
+		}
+	}
+
+	for _, dbName := range BackupDatabaseNames {
+		uri, err := db.URI(dbName)
+		if err != nil {
+			return err
+		}
+
+		var stderr bytes.Buffer
+		cmd := execCommand(ctx, "pg_restore", "--clean", "--if-exists", "--no-owner", "--no-acl", "--single-transaction", "--dbname", uri)
+		cmd.Stdin = bytes.NewReader(dumps[dbName])
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to restore database %s: [%v] %s", dbName, err, stderr.String())
+		}
+	}
+	return nil
+}
+
+// withWebStopped stops the web instances for the duration of fn, starting them again even if fn fails
+func withWebStopped(ctx context.Context, boshCLI boshcli.ICLI, ip, password, ca string, stdout io.Writer, fn func() error) error {
+	err := boshCLI.RunAuthenticatedCommand(ctx, "stop", ip, password, ca, false, stdout, "web")
+	if err != nil {
+		return fmt.Errorf("failed to stop web instances: [%v]", err)
+	}
+
+	err = fn()
+
+	err1 := boshCLI.RunAuthenticatedCommand(ctx, "start", ip, password, ca, false, stdout, "web")
+	if err1 != nil {
+		err1 = fmt.Errorf("failed to start web instances: [%v]", err1)
+	}
+	if err == nil {
+		err = err1
+	}
+	return err
+}
+
+// BackupDatabases dumps the Concourse, CredHub and UAA databases
+func (client *AWSClient) BackupDatabases(ctx context.Context) (map[string][]byte, error) {
+	return dumpDatabases(ctx, client.db)
+}
+
+// RestoreDatabases loads database dumps while the web instances are stopped
+func (client *AWSClient) RestoreDatabases(ctx context.Context, dumps map[string][]byte) error {
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve director IP: [%v]", err)
+	}
+
+	return withWebStopped(ctx, client.boshCLI, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert(), client.stdout, func() error {
+		return restoreDatabases(ctx, client.db, dumps)
+	})
+}
+
+// BackupDatabases dumps the Concourse, CredHub and UAA databases
+func (client *GCPClient) BackupDatabases(ctx context.Context) (map[string][]byte, error) {
+	db, err := client.openDB()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	return dumpDatabases(ctx, db)
+}
+
+// RestoreDatabases loads database dumps while the web instances are stopped
+func (client *GCPClient) RestoreDatabases(ctx context.Context, dumps map[string][]byte) error {
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve director IP: [%v]", err)
+	}
+
+	db, err := client.openDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return withWebStopped(ctx, client.boshCLI, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert(), client.stdout, func() error {
+		return restoreDatabases(ctx, db, dumps)
+	})
+}
+
+// BackupDatabases dumps the Concourse, CredHub and UAA databases
+func (client *AzureClient) BackupDatabases(ctx context.Context) (map[string][]byte, error) {
+	db, err := client.openDB()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	return dumpDatabases(ctx, db)
+}
+
+// RestoreDatabases loads database dumps while the web instances are stopped
+func (client *AzureClient) RestoreDatabases(ctx context.Context, dumps map[string][]byte) error {
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve director IP: [%v]", err)
+	}
+
+	db, err := client.openDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return withWebStopped(ctx, client.boshCLI, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert(), client.stdout, func() error {
+		return restoreDatabases(ctx, db, dumps)
+	})
+}
@@ -0,0 +1,68 @@
+package bosh
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/EngineerBetter/control-tower/internal/fakeexec"
+	"github.com/EngineerBetter/control-tower/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecCommandHelper(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	fmt.Print(os.Getenv("STDOUT"))
+	i, _ := strconv.Atoi(os.Getenv("EXIT_STATUS"))
+	os.Exit(i)
+}
+
+func fakeBackupOpener() fakeOpener {
+	return fakeOpener{
+		"concourse_atc": &sql.DB{},
+		"credhub":       &sql.DB{},
+		"uaa":           &sql.DB{},
+	}
+}
+
+func TestDumpDatabases(t *testing.T) {
+	e := fakeexec.New(t)
+	defer e.Finish()
+	execCommand = e.CmdContext()
+	defer func() { execCommand = util.CommandContext }()
+
+	for _, dbName := range BackupDatabaseNames {
+		e.Expect("pg_dump", "--format=custom", "--no-owner", "--no-acl", "--dbname", "postgres://fake/"+dbName).Outputs("dump of " + dbName)
+	}
+
+	dumps, err := dumpDatabases(context.Background(), fakeBackupOpener())
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{
+		"concourse_atc": []byte("dump of concourse_atc"),
+		"credhub":       []byte("dump of credhub"),
+		"uaa":           []byte("dump of uaa"),
+	}, dumps)
+}
+
+func TestDumpDatabasesFailure(t *testing.T) {
+	e := fakeexec.New(t)
+	defer e.Finish()
+	execCommand = e.CmdContext()
+	defer func() { execCommand = util.CommandContext }()
+
+	e.Expect("pg_dump", "--format=custom", "--no-owner", "--no-acl", "--dbname", "postgres://fake/concourse_atc").Exits(1)
+
+	_, err := dumpDatabases(context.Background(), fakeBackupOpener())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to dump database concourse_atc")
+}
+
+func TestRestoreDatabasesRequiresEveryDatabase(t *testing.T) {
+	err := restoreDatabases(context.Background(), fakeBackupOpener(), map[string][]byte{"concourse_atc": []byte("dump")})
+	require.EqualError(t, err, "backup does not contain database credhub")
+}
@@ -2,12 +2,27 @@
 package boshfakes
 
 import (
+	"context"
+	"io"
 	"sync"
 
 	"github.com/EngineerBetter/control-tower/bosh"
 )
 
 type FakeIClient struct {
+	BackupDatabasesStub        func(context.Context) (map[string][]byte, error)
+	backupDatabasesMutex       sync.RWMutex
+	backupDatabasesArgsForCall []struct {
+		arg1 context.Context
+	}
+	backupDatabasesReturns struct {
+		result1 map[string][]byte
+		result2 error
+	}
+	backupDatabasesReturnsOnCall map[int]struct {
+		result1 map[string][]byte
+		result2 error
+	}
 	CleanupStub        func() error
 	cleanupMutex       sync.RWMutex
 	cleanupArgsForCall []struct {
@@ -18,12 +33,13 @@ type FakeIClient struct {
 	cleanupReturnsOnCall map[int]struct {
 		result1 error
 	}
-	CreateEnvStub        func([]byte, []byte, string) ([]byte, []byte, error)
+	CreateEnvStub        func(context.Context, []byte, []byte, string) ([]byte, []byte, error)
 	createEnvMutex       sync.RWMutex
 	createEnvArgsForCall []struct {
-		arg1 []byte
+		arg1 context.Context
 		arg2 []byte
-		arg3 string
+		arg3 []byte
+		arg4 string
 	}
 	createEnvReturns struct {
 		result1 []byte
@@ -35,12 +51,14 @@ type FakeIClient struct {
 		result2 []byte
 		result3 error
 	}
-	DeployStub        func([]byte, []byte, bool) ([]byte, []byte, error)
+	DeployStub        func(context.Context, []byte, []byte, bool, bosh.Checkpoint) ([]byte, []byte, error)
 	deployMutex       sync.RWMutex
 	deployArgsForCall []struct {
-		arg1 []byte
+		arg1 context.Context
 		arg2 []byte
-		arg3 bool
+		arg3 []byte
+		arg4 bool
+		arg5 bosh.Checkpoint
 	}
 	deployReturns struct {
 		result1 []byte
@@ -52,9 +70,24 @@ type FakeIClient struct {
 		result2 []byte
 		result3 error
 	}
-	InstancesStub        func() ([]bosh.Instance, error)
+	DiffStub        func(context.Context, []byte) (string, error)
+	diffMutex       sync.RWMutex
+	diffArgsForCall []struct {
+		arg1 context.Context
+		arg2 []byte
+	}
+	diffReturns struct {
+		result1 string
+		result2 error
+	}
+	diffReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	InstancesStub        func(context.Context) ([]bosh.Instance, error)
 	instancesMutex       sync.RWMutex
 	instancesArgsForCall []struct {
+		arg1 context.Context
 	}
 	instancesReturns struct {
 		result1 []bosh.Instance
@@ -64,9 +97,22 @@ type FakeIClient struct {
 		result1 []bosh.Instance
 		result2 error
 	}
-	LocksStub        func() ([]byte, error)
+	LandWorkersStub        func(context.Context, int) error
+	landWorkersMutex       sync.RWMutex
+	landWorkersArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+	}
+	landWorkersReturns struct {
+		result1 error
+	}
+	landWorkersReturnsOnCall map[int]struct {
+		result1 error
+	}
+	LocksStub        func(context.Context) ([]byte, error)
 	locksMutex       sync.RWMutex
 	locksArgsForCall []struct {
+		arg1 context.Context
 	}
 	locksReturns struct {
 		result1 []byte
@@ -76,9 +122,25 @@ type FakeIClient struct {
 		result1 []byte
 		result2 error
 	}
-	RecreateStub        func() error
+	LogsStub        func(context.Context, string, string, bool, string) error
+	logsMutex       sync.RWMutex
+	logsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 bool
+		arg5 string
+	}
+	logsReturns struct {
+		result1 error
+	}
+	logsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RecreateStub        func(context.Context) error
 	recreateMutex       sync.RWMutex
 	recreateArgsForCall []struct {
+		arg1 context.Context
 	}
 	recreateReturns struct {
 		result1 error
@@ -86,10 +148,138 @@ type FakeIClient struct {
 	recreateReturnsOnCall map[int]struct {
 		result1 error
 	}
+	RestoreDatabasesStub        func(context.Context, map[string][]byte) error
+	restoreDatabasesMutex       sync.RWMutex
+	restoreDatabasesArgsForCall []struct {
+		arg1 context.Context
+		arg2 map[string][]byte
+	}
+	restoreDatabasesReturns struct {
+		result1 error
+	}
+	restoreDatabasesReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RetireWorkersStub        func(context.Context, string, int) error
+	retireWorkersMutex       sync.RWMutex
+	retireWorkersArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+	}
+	retireWorkersReturns struct {
+		result1 error
+	}
+	retireWorkersReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SSHStub        func(context.Context, string, string, io.Reader) error
+	sSHMutex       sync.RWMutex
+	sSHArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 io.Reader
+	}
+	sSHReturns struct {
+		result1 error
+	}
+	sSHReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ScaleWorkersStub        func(context.Context, []byte) ([]byte, error)
+	scaleWorkersMutex       sync.RWMutex
+	scaleWorkersArgsForCall []struct {
+		arg1 context.Context
+		arg2 []byte
+	}
+	scaleWorkersReturns struct {
+		result1 []byte
+		result2 error
+	}
+	scaleWorkersReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
+	UnlandWorkersStub        func(context.Context, int) error
+	unlandWorkersMutex       sync.RWMutex
+	unlandWorkersArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+	}
+	unlandWorkersReturns struct {
+		result1 error
+	}
+	unlandWorkersReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeIClient) BackupDatabases(arg1 context.Context) (map[string][]byte, error) {
+	fake.backupDatabasesMutex.Lock()
+	ret, specificReturn := fake.backupDatabasesReturnsOnCall[len(fake.backupDatabasesArgsForCall)]
+	fake.backupDatabasesArgsForCall = append(fake.backupDatabasesArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("BackupDatabases", []interface{}{arg1})
+	fake.backupDatabasesMutex.Unlock()
+	if fake.BackupDatabasesStub != nil {
+		return fake.BackupDatabasesStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.backupDatabasesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeIClient) BackupDatabasesCallCount() int {
+	fake.backupDatabasesMutex.RLock()
+	defer fake.backupDatabasesMutex.RUnlock()
+	return len(fake.backupDatabasesArgsForCall)
+}
+
+func (fake *FakeIClient) BackupDatabasesCalls(stub func(context.Context) (map[string][]byte, error)) {
+	fake.backupDatabasesMutex.Lock()
+	defer fake.backupDatabasesMutex.Unlock()
+	fake.BackupDatabasesStub = stub
+}
+
+func (fake *FakeIClient) BackupDatabasesArgsForCall(i int) context.Context {
+	fake.backupDatabasesMutex.RLock()
+	defer fake.backupDatabasesMutex.RUnlock()
+	argsForCall := fake.backupDatabasesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeIClient) BackupDatabasesReturns(result1 map[string][]byte, result2 error) {
+	fake.backupDatabasesMutex.Lock()
+	defer fake.backupDatabasesMutex.Unlock()
+	fake.BackupDatabasesStub = nil
+	fake.backupDatabasesReturns = struct {
+		result1 map[string][]byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeIClient) BackupDatabasesReturnsOnCall(i int, result1 map[string][]byte, result2 error) {
+	fake.backupDatabasesMutex.Lock()
+	defer fake.backupDatabasesMutex.Unlock()
+	fake.BackupDatabasesStub = nil
+	if fake.backupDatabasesReturnsOnCall == nil {
+		fake.backupDatabasesReturnsOnCall = make(map[int]struct {
+			result1 map[string][]byte
+			result2 error
+		})
+	}
+	fake.backupDatabasesReturnsOnCall[i] = struct {
+		result1 map[string][]byte
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeIClient) Cleanup() error {
 	fake.cleanupMutex.Lock()
 	ret, specificReturn := fake.cleanupReturnsOnCall[len(fake.cleanupArgsForCall)]
@@ -142,28 +332,29 @@ func (fake *FakeIClient) CleanupReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeIClient) CreateEnv(arg1 []byte, arg2 []byte, arg3 string) ([]byte, []byte, error) {
-	var arg1Copy []byte
-	if arg1 != nil {
-		arg1Copy = make([]byte, len(arg1))
-		copy(arg1Copy, arg1)
-	}
+func (fake *FakeIClient) CreateEnv(arg1 context.Context, arg2 []byte, arg3 []byte, arg4 string) ([]byte, []byte, error) {
 	var arg2Copy []byte
 	if arg2 != nil {
 		arg2Copy = make([]byte, len(arg2))
 		copy(arg2Copy, arg2)
 	}
+	var arg3Copy []byte
+	if arg3 != nil {
+		arg3Copy = make([]byte, len(arg3))
+		copy(arg3Copy, arg3)
+	}
 	fake.createEnvMutex.Lock()
 	ret, specificReturn := fake.createEnvReturnsOnCall[len(fake.createEnvArgsForCall)]
 	fake.createEnvArgsForCall = append(fake.createEnvArgsForCall, struct {
-		arg1 []byte
+		arg1 context.Context
 		arg2 []byte
-		arg3 string
-	}{arg1Copy, arg2Copy, arg3})
-	fake.recordInvocation("CreateEnv", []interface{}{arg1Copy, arg2Copy, arg3})
+		arg3 []byte
+		arg4 string
+	}{arg1, arg2Copy, arg3Copy, arg4})
+	fake.recordInvocation("CreateEnv", []interface{}{arg1, arg2Copy, arg3Copy, arg4})
 	fake.createEnvMutex.Unlock()
 	if fake.CreateEnvStub != nil {
-		return fake.CreateEnvStub(arg1, arg2, arg3)
+		return fake.CreateEnvStub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
@@ -178,17 +369,17 @@ func (fake *FakeIClient) CreateEnvCallCount() int {
 	return len(fake.createEnvArgsForCall)
 }
 
-func (fake *FakeIClient) CreateEnvCalls(stub func([]byte, []byte, string) ([]byte, []byte, error)) {
+func (fake *FakeIClient) CreateEnvCalls(stub func(context.Context, []byte, []byte, string) ([]byte, []byte, error)) {
 	fake.createEnvMutex.Lock()
 	defer fake.createEnvMutex.Unlock()
 	fake.CreateEnvStub = stub
 }
 
-func (fake *FakeIClient) CreateEnvArgsForCall(i int) ([]byte, []byte, string) {
+func (fake *FakeIClient) CreateEnvArgsForCall(i int) (context.Context, []byte, []byte, string) {
 	fake.createEnvMutex.RLock()
 	defer fake.createEnvMutex.RUnlock()
 	argsForCall := fake.createEnvArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeIClient) CreateEnvReturns(result1 []byte, result2 []byte, result3 error) {
@@ -220,28 +411,30 @@ func (fake *FakeIClient) CreateEnvReturnsOnCall(i int, result1 []byte, result2 [
 	}{result1, result2, result3}
 }
 
-func (fake *FakeIClient) Deploy(arg1 []byte, arg2 []byte, arg3 bool) ([]byte, []byte, error) {
-	var arg1Copy []byte
-	if arg1 != nil {
-		arg1Copy = make([]byte, len(arg1))
-		copy(arg1Copy, arg1)
-	}
+func (fake *FakeIClient) Deploy(arg1 context.Context, arg2 []byte, arg3 []byte, arg4 bool, arg5 bosh.Checkpoint) ([]byte, []byte, error) {
 	var arg2Copy []byte
 	if arg2 != nil {
 		arg2Copy = make([]byte, len(arg2))
 		copy(arg2Copy, arg2)
 	}
+	var arg3Copy []byte
+	if arg3 != nil {
+		arg3Copy = make([]byte, len(arg3))
+		copy(arg3Copy, arg3)
+	}
 	fake.deployMutex.Lock()
 	ret, specificReturn := fake.deployReturnsOnCall[len(fake.deployArgsForCall)]
 	fake.deployArgsForCall = append(fake.deployArgsForCall, struct {
-		arg1 []byte
+		arg1 context.Context
 		arg2 []byte
-		arg3 bool
-	}{arg1Copy, arg2Copy, arg3})
-	fake.recordInvocation("Deploy", []interface{}{arg1Copy, arg2Copy, arg3})
+		arg3 []byte
+		arg4 bool
+		arg5 bosh.Checkpoint
+	}{arg1, arg2Copy, arg3Copy, arg4, arg5})
+	fake.recordInvocation("Deploy", []interface{}{arg1, arg2Copy, arg3Copy, arg4, arg5})
 	fake.deployMutex.Unlock()
 	if fake.DeployStub != nil {
-		return fake.DeployStub(arg1, arg2, arg3)
+		return fake.DeployStub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
@@ -256,17 +449,17 @@ func (fake *FakeIClient) DeployCallCount() int {
 	return len(fake.deployArgsForCall)
 }
 
-func (fake *FakeIClient) DeployCalls(stub func([]byte, []byte, bool) ([]byte, []byte, error)) {
+func (fake *FakeIClient) DeployCalls(stub func(context.Context, []byte, []byte, bool, bosh.Checkpoint) ([]byte, []byte, error)) {
 	fake.deployMutex.Lock()
 	defer fake.deployMutex.Unlock()
 	fake.DeployStub = stub
 }
 
-func (fake *FakeIClient) DeployArgsForCall(i int) ([]byte, []byte, bool) {
+func (fake *FakeIClient) DeployArgsForCall(i int) (context.Context, []byte, []byte, bool, bosh.Checkpoint) {
 	fake.deployMutex.RLock()
 	defer fake.deployMutex.RUnlock()
 	argsForCall := fake.deployArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
 func (fake *FakeIClient) DeployReturns(result1 []byte, result2 []byte, result3 error) {
@@ -298,15 +491,85 @@ func (fake *FakeIClient) DeployReturnsOnCall(i int, result1 []byte, result2 []by
 	}{result1, result2, result3}
 }
 
-func (fake *FakeIClient) Instances() ([]bosh.Instance, error) {
+func (fake *FakeIClient) Diff(arg1 context.Context, arg2 []byte) (string, error) {
+	var arg2Copy []byte
+	if arg2 != nil {
+		arg2Copy = make([]byte, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.diffMutex.Lock()
+	ret, specificReturn := fake.diffReturnsOnCall[len(fake.diffArgsForCall)]
+	fake.diffArgsForCall = append(fake.diffArgsForCall, struct {
+		arg1 context.Context
+		arg2 []byte
+	}{arg1, arg2Copy})
+	fake.recordInvocation("Diff", []interface{}{arg1, arg2Copy})
+	fake.diffMutex.Unlock()
+	if fake.DiffStub != nil {
+		return fake.DiffStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.diffReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeIClient) DiffCallCount() int {
+	fake.diffMutex.RLock()
+	defer fake.diffMutex.RUnlock()
+	return len(fake.diffArgsForCall)
+}
+
+func (fake *FakeIClient) DiffCalls(stub func(context.Context, []byte) (string, error)) {
+	fake.diffMutex.Lock()
+	defer fake.diffMutex.Unlock()
+	fake.DiffStub = stub
+}
+
+func (fake *FakeIClient) DiffArgsForCall(i int) (context.Context, []byte) {
+	fake.diffMutex.RLock()
+	defer fake.diffMutex.RUnlock()
+	argsForCall := fake.diffArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeIClient) DiffReturns(result1 string, result2 error) {
+	fake.diffMutex.Lock()
+	defer fake.diffMutex.Unlock()
+	fake.DiffStub = nil
+	fake.diffReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeIClient) DiffReturnsOnCall(i int, result1 string, result2 error) {
+	fake.diffMutex.Lock()
+	defer fake.diffMutex.Unlock()
+	fake.DiffStub = nil
+	if fake.diffReturnsOnCall == nil {
+		fake.diffReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.diffReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeIClient) Instances(arg1 context.Context) ([]bosh.Instance, error) {
 	fake.instancesMutex.Lock()
 	ret, specificReturn := fake.instancesReturnsOnCall[len(fake.instancesArgsForCall)]
 	fake.instancesArgsForCall = append(fake.instancesArgsForCall, struct {
-	}{})
-	fake.recordInvocation("Instances", []interface{}{})
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("Instances", []interface{}{arg1})
 	fake.instancesMutex.Unlock()
 	if fake.InstancesStub != nil {
-		return fake.InstancesStub()
+		return fake.InstancesStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -321,12 +584,19 @@ func (fake *FakeIClient) InstancesCallCount() int {
 	return len(fake.instancesArgsForCall)
 }
 
-func (fake *FakeIClient) InstancesCalls(stub func() ([]bosh.Instance, error)) {
+func (fake *FakeIClient) InstancesCalls(stub func(context.Context) ([]bosh.Instance, error)) {
 	fake.instancesMutex.Lock()
 	defer fake.instancesMutex.Unlock()
 	fake.InstancesStub = stub
 }
 
+func (fake *FakeIClient) InstancesArgsForCall(i int) context.Context {
+	fake.instancesMutex.RLock()
+	defer fake.instancesMutex.RUnlock()
+	argsForCall := fake.instancesArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeIClient) InstancesReturns(result1 []bosh.Instance, result2 error) {
 	fake.instancesMutex.Lock()
 	defer fake.instancesMutex.Unlock()
@@ -353,15 +623,77 @@ func (fake *FakeIClient) InstancesReturnsOnCall(i int, result1 []bosh.Instance,
 	}{result1, result2}
 }
 
-func (fake *FakeIClient) Locks() ([]byte, error) {
+func (fake *FakeIClient) LandWorkers(arg1 context.Context, arg2 int) error {
+	fake.landWorkersMutex.Lock()
+	ret, specificReturn := fake.landWorkersReturnsOnCall[len(fake.landWorkersArgsForCall)]
+	fake.landWorkersArgsForCall = append(fake.landWorkersArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+	}{arg1, arg2})
+	fake.recordInvocation("LandWorkers", []interface{}{arg1, arg2})
+	fake.landWorkersMutex.Unlock()
+	if fake.LandWorkersStub != nil {
+		return fake.LandWorkersStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.landWorkersReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeIClient) LandWorkersCallCount() int {
+	fake.landWorkersMutex.RLock()
+	defer fake.landWorkersMutex.RUnlock()
+	return len(fake.landWorkersArgsForCall)
+}
+
+func (fake *FakeIClient) LandWorkersCalls(stub func(context.Context, int) error) {
+	fake.landWorkersMutex.Lock()
+	defer fake.landWorkersMutex.Unlock()
+	fake.LandWorkersStub = stub
+}
+
+func (fake *FakeIClient) LandWorkersArgsForCall(i int) (context.Context, int) {
+	fake.landWorkersMutex.RLock()
+	defer fake.landWorkersMutex.RUnlock()
+	argsForCall := fake.landWorkersArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeIClient) LandWorkersReturns(result1 error) {
+	fake.landWorkersMutex.Lock()
+	defer fake.landWorkersMutex.Unlock()
+	fake.LandWorkersStub = nil
+	fake.landWorkersReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeIClient) LandWorkersReturnsOnCall(i int, result1 error) {
+	fake.landWorkersMutex.Lock()
+	defer fake.landWorkersMutex.Unlock()
+	fake.LandWorkersStub = nil
+	if fake.landWorkersReturnsOnCall == nil {
+		fake.landWorkersReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.landWorkersReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeIClient) Locks(arg1 context.Context) ([]byte, error) {
 	fake.locksMutex.Lock()
 	ret, specificReturn := fake.locksReturnsOnCall[len(fake.locksArgsForCall)]
 	fake.locksArgsForCall = append(fake.locksArgsForCall, struct {
-	}{})
-	fake.recordInvocation("Locks", []interface{}{})
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("Locks", []interface{}{arg1})
 	fake.locksMutex.Unlock()
 	if fake.LocksStub != nil {
-		return fake.LocksStub()
+		return fake.LocksStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -376,12 +708,19 @@ func (fake *FakeIClient) LocksCallCount() int {
 	return len(fake.locksArgsForCall)
 }
 
-func (fake *FakeIClient) LocksCalls(stub func() ([]byte, error)) {
+func (fake *FakeIClient) LocksCalls(stub func(context.Context) ([]byte, error)) {
 	fake.locksMutex.Lock()
 	defer fake.locksMutex.Unlock()
 	fake.LocksStub = stub
 }
 
+func (fake *FakeIClient) LocksArgsForCall(i int) context.Context {
+	fake.locksMutex.RLock()
+	defer fake.locksMutex.RUnlock()
+	argsForCall := fake.locksArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeIClient) LocksReturns(result1 []byte, result2 error) {
 	fake.locksMutex.Lock()
 	defer fake.locksMutex.Unlock()
@@ -408,15 +747,80 @@ func (fake *FakeIClient) LocksReturnsOnCall(i int, result1 []byte, result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeIClient) Recreate() error {
+func (fake *FakeIClient) Logs(arg1 context.Context, arg2 string, arg3 string, arg4 bool, arg5 string) error {
+	fake.logsMutex.Lock()
+	ret, specificReturn := fake.logsReturnsOnCall[len(fake.logsArgsForCall)]
+	fake.logsArgsForCall = append(fake.logsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 bool
+		arg5 string
+	}{arg1, arg2, arg3, arg4, arg5})
+	fake.recordInvocation("Logs", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.logsMutex.Unlock()
+	if fake.LogsStub != nil {
+		return fake.LogsStub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.logsReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeIClient) LogsCallCount() int {
+	fake.logsMutex.RLock()
+	defer fake.logsMutex.RUnlock()
+	return len(fake.logsArgsForCall)
+}
+
+func (fake *FakeIClient) LogsCalls(stub func(context.Context, string, string, bool, string) error) {
+	fake.logsMutex.Lock()
+	defer fake.logsMutex.Unlock()
+	fake.LogsStub = stub
+}
+
+func (fake *FakeIClient) LogsArgsForCall(i int) (context.Context, string, string, bool, string) {
+	fake.logsMutex.RLock()
+	defer fake.logsMutex.RUnlock()
+	argsForCall := fake.logsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeIClient) LogsReturns(result1 error) {
+	fake.logsMutex.Lock()
+	defer fake.logsMutex.Unlock()
+	fake.LogsStub = nil
+	fake.logsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeIClient) LogsReturnsOnCall(i int, result1 error) {
+	fake.logsMutex.Lock()
+	defer fake.logsMutex.Unlock()
+	fake.LogsStub = nil
+	if fake.logsReturnsOnCall == nil {
+		fake.logsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.logsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeIClient) Recreate(arg1 context.Context) error {
 	fake.recreateMutex.Lock()
 	ret, specificReturn := fake.recreateReturnsOnCall[len(fake.recreateArgsForCall)]
 	fake.recreateArgsForCall = append(fake.recreateArgsForCall, struct {
-	}{})
-	fake.recordInvocation("Recreate", []interface{}{})
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("Recreate", []interface{}{arg1})
 	fake.recreateMutex.Unlock()
 	if fake.RecreateStub != nil {
-		return fake.RecreateStub()
+		return fake.RecreateStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
@@ -431,12 +835,19 @@ func (fake *FakeIClient) RecreateCallCount() int {
 	return len(fake.recreateArgsForCall)
 }
 
-func (fake *FakeIClient) RecreateCalls(stub func() error) {
+func (fake *FakeIClient) RecreateCalls(stub func(context.Context) error) {
 	fake.recreateMutex.Lock()
 	defer fake.recreateMutex.Unlock()
 	fake.RecreateStub = stub
 }
 
+func (fake *FakeIClient) RecreateArgsForCall(i int) context.Context {
+	fake.recreateMutex.RLock()
+	defer fake.recreateMutex.RUnlock()
+	argsForCall := fake.recreateArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeIClient) RecreateReturns(result1 error) {
 	fake.recreateMutex.Lock()
 	defer fake.recreateMutex.Unlock()
@@ -460,21 +871,355 @@ func (fake *FakeIClient) RecreateReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeIClient) RestoreDatabases(arg1 context.Context, arg2 map[string][]byte) error {
+	fake.restoreDatabasesMutex.Lock()
+	ret, specificReturn := fake.restoreDatabasesReturnsOnCall[len(fake.restoreDatabasesArgsForCall)]
+	fake.restoreDatabasesArgsForCall = append(fake.restoreDatabasesArgsForCall, struct {
+		arg1 context.Context
+		arg2 map[string][]byte
+	}{arg1, arg2})
+	fake.recordInvocation("RestoreDatabases", []interface{}{arg1, arg2})
+	fake.restoreDatabasesMutex.Unlock()
+	if fake.RestoreDatabasesStub != nil {
+		return fake.RestoreDatabasesStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.restoreDatabasesReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeIClient) RestoreDatabasesCallCount() int {
+	fake.restoreDatabasesMutex.RLock()
+	defer fake.restoreDatabasesMutex.RUnlock()
+	return len(fake.restoreDatabasesArgsForCall)
+}
+
+func (fake *FakeIClient) RestoreDatabasesCalls(stub func(context.Context, map[string][]byte) error) {
+	fake.restoreDatabasesMutex.Lock()
+	defer fake.restoreDatabasesMutex.Unlock()
+	fake.RestoreDatabasesStub = stub
+}
+
+func (fake *FakeIClient) RestoreDatabasesArgsForCall(i int) (context.Context, map[string][]byte) {
+	fake.restoreDatabasesMutex.RLock()
+	defer fake.restoreDatabasesMutex.RUnlock()
+	argsForCall := fake.restoreDatabasesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeIClient) RestoreDatabasesReturns(result1 error) {
+	fake.restoreDatabasesMutex.Lock()
+	defer fake.restoreDatabasesMutex.Unlock()
+	fake.RestoreDatabasesStub = nil
+	fake.restoreDatabasesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeIClient) RestoreDatabasesReturnsOnCall(i int, result1 error) {
+	fake.restoreDatabasesMutex.Lock()
+	defer fake.restoreDatabasesMutex.Unlock()
+	fake.RestoreDatabasesStub = nil
+	if fake.restoreDatabasesReturnsOnCall == nil {
+		fake.restoreDatabasesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.restoreDatabasesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeIClient) RetireWorkers(arg1 context.Context, arg2 string, arg3 int) error {
+	fake.retireWorkersMutex.Lock()
+	ret, specificReturn := fake.retireWorkersReturnsOnCall[len(fake.retireWorkersArgsForCall)]
+	fake.retireWorkersArgsForCall = append(fake.retireWorkersArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("RetireWorkers", []interface{}{arg1, arg2, arg3})
+	fake.retireWorkersMutex.Unlock()
+	if fake.RetireWorkersStub != nil {
+		return fake.RetireWorkersStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.retireWorkersReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeIClient) RetireWorkersCallCount() int {
+	fake.retireWorkersMutex.RLock()
+	defer fake.retireWorkersMutex.RUnlock()
+	return len(fake.retireWorkersArgsForCall)
+}
+
+func (fake *FakeIClient) RetireWorkersCalls(stub func(context.Context, string, int) error) {
+	fake.retireWorkersMutex.Lock()
+	defer fake.retireWorkersMutex.Unlock()
+	fake.RetireWorkersStub = stub
+}
+
+func (fake *FakeIClient) RetireWorkersArgsForCall(i int) (context.Context, string, int) {
+	fake.retireWorkersMutex.RLock()
+	defer fake.retireWorkersMutex.RUnlock()
+	argsForCall := fake.retireWorkersArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeIClient) RetireWorkersReturns(result1 error) {
+	fake.retireWorkersMutex.Lock()
+	defer fake.retireWorkersMutex.Unlock()
+	fake.RetireWorkersStub = nil
+	fake.retireWorkersReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeIClient) RetireWorkersReturnsOnCall(i int, result1 error) {
+	fake.retireWorkersMutex.Lock()
+	defer fake.retireWorkersMutex.Unlock()
+	fake.RetireWorkersStub = nil
+	if fake.retireWorkersReturnsOnCall == nil {
+		fake.retireWorkersReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.retireWorkersReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeIClient) SSH(arg1 context.Context, arg2 string, arg3 string, arg4 io.Reader) error {
+	fake.sSHMutex.Lock()
+	ret, specificReturn := fake.sSHReturnsOnCall[len(fake.sSHArgsForCall)]
+	fake.sSHArgsForCall = append(fake.sSHArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 io.Reader
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("SSH", []interface{}{arg1, arg2, arg3, arg4})
+	fake.sSHMutex.Unlock()
+	if fake.SSHStub != nil {
+		return fake.SSHStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.sSHReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeIClient) SSHCallCount() int {
+	fake.sSHMutex.RLock()
+	defer fake.sSHMutex.RUnlock()
+	return len(fake.sSHArgsForCall)
+}
+
+func (fake *FakeIClient) SSHCalls(stub func(context.Context, string, string, io.Reader) error) {
+	fake.sSHMutex.Lock()
+	defer fake.sSHMutex.Unlock()
+	fake.SSHStub = stub
+}
+
+func (fake *FakeIClient) SSHArgsForCall(i int) (context.Context, string, string, io.Reader) {
+	fake.sSHMutex.RLock()
+	defer fake.sSHMutex.RUnlock()
+	argsForCall := fake.sSHArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeIClient) SSHReturns(result1 error) {
+	fake.sSHMutex.Lock()
+	defer fake.sSHMutex.Unlock()
+	fake.SSHStub = nil
+	fake.sSHReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeIClient) SSHReturnsOnCall(i int, result1 error) {
+	fake.sSHMutex.Lock()
+	defer fake.sSHMutex.Unlock()
+	fake.SSHStub = nil
+	if fake.sSHReturnsOnCall == nil {
+		fake.sSHReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.sSHReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeIClient) ScaleWorkers(arg1 context.Context, arg2 []byte) ([]byte, error) {
+	var arg2Copy []byte
+	if arg2 != nil {
+		arg2Copy = make([]byte, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.scaleWorkersMutex.Lock()
+	ret, specificReturn := fake.scaleWorkersReturnsOnCall[len(fake.scaleWorkersArgsForCall)]
+	fake.scaleWorkersArgsForCall = append(fake.scaleWorkersArgsForCall, struct {
+		arg1 context.Context
+		arg2 []byte
+	}{arg1, arg2Copy})
+	fake.recordInvocation("ScaleWorkers", []interface{}{arg1, arg2Copy})
+	fake.scaleWorkersMutex.Unlock()
+	if fake.ScaleWorkersStub != nil {
+		return fake.ScaleWorkersStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.scaleWorkersReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeIClient) ScaleWorkersCallCount() int {
+	fake.scaleWorkersMutex.RLock()
+	defer fake.scaleWorkersMutex.RUnlock()
+	return len(fake.scaleWorkersArgsForCall)
+}
+
+func (fake *FakeIClient) ScaleWorkersCalls(stub func(context.Context, []byte) ([]byte, error)) {
+	fake.scaleWorkersMutex.Lock()
+	defer fake.scaleWorkersMutex.Unlock()
+	fake.ScaleWorkersStub = stub
+}
+
+func (fake *FakeIClient) ScaleWorkersArgsForCall(i int) (context.Context, []byte) {
+	fake.scaleWorkersMutex.RLock()
+	defer fake.scaleWorkersMutex.RUnlock()
+	argsForCall := fake.scaleWorkersArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeIClient) ScaleWorkersReturns(result1 []byte, result2 error) {
+	fake.scaleWorkersMutex.Lock()
+	defer fake.scaleWorkersMutex.Unlock()
+	fake.ScaleWorkersStub = nil
+	fake.scaleWorkersReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeIClient) ScaleWorkersReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.scaleWorkersMutex.Lock()
+	defer fake.scaleWorkersMutex.Unlock()
+	fake.ScaleWorkersStub = nil
+	if fake.scaleWorkersReturnsOnCall == nil {
+		fake.scaleWorkersReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.scaleWorkersReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeIClient) UnlandWorkers(arg1 context.Context, arg2 int) error {
+	fake.unlandWorkersMutex.Lock()
+	ret, specificReturn := fake.unlandWorkersReturnsOnCall[len(fake.unlandWorkersArgsForCall)]
+	fake.unlandWorkersArgsForCall = append(fake.unlandWorkersArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+	}{arg1, arg2})
+	fake.recordInvocation("UnlandWorkers", []interface{}{arg1, arg2})
+	fake.unlandWorkersMutex.Unlock()
+	if fake.UnlandWorkersStub != nil {
+		return fake.UnlandWorkersStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.unlandWorkersReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeIClient) UnlandWorkersCallCount() int {
+	fake.unlandWorkersMutex.RLock()
+	defer fake.unlandWorkersMutex.RUnlock()
+	return len(fake.unlandWorkersArgsForCall)
+}
+
+func (fake *FakeIClient) UnlandWorkersCalls(stub func(context.Context, int) error) {
+	fake.unlandWorkersMutex.Lock()
+	defer fake.unlandWorkersMutex.Unlock()
+	fake.UnlandWorkersStub = stub
+}
+
+func (fake *FakeIClient) UnlandWorkersArgsForCall(i int) (context.Context, int) {
+	fake.unlandWorkersMutex.RLock()
+	defer fake.unlandWorkersMutex.RUnlock()
+	argsForCall := fake.unlandWorkersArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeIClient) UnlandWorkersReturns(result1 error) {
+	fake.unlandWorkersMutex.Lock()
+	defer fake.unlandWorkersMutex.Unlock()
+	fake.UnlandWorkersStub = nil
+	fake.unlandWorkersReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeIClient) UnlandWorkersReturnsOnCall(i int, result1 error) {
+	fake.unlandWorkersMutex.Lock()
+	defer fake.unlandWorkersMutex.Unlock()
+	fake.UnlandWorkersStub = nil
+	if fake.unlandWorkersReturnsOnCall == nil {
+		fake.unlandWorkersReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.unlandWorkersReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeIClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.backupDatabasesMutex.RLock()
+	defer fake.backupDatabasesMutex.RUnlock()
 	fake.cleanupMutex.RLock()
 	defer fake.cleanupMutex.RUnlock()
 	fake.createEnvMutex.RLock()
 	defer fake.createEnvMutex.RUnlock()
 	fake.deployMutex.RLock()
 	defer fake.deployMutex.RUnlock()
+	fake.diffMutex.RLock()
+	defer fake.diffMutex.RUnlock()
 	fake.instancesMutex.RLock()
 	defer fake.instancesMutex.RUnlock()
+	fake.landWorkersMutex.RLock()
+	defer fake.landWorkersMutex.RUnlock()
 	fake.locksMutex.RLock()
 	defer fake.locksMutex.RUnlock()
+	fake.logsMutex.RLock()
+	defer fake.logsMutex.RUnlock()
 	fake.recreateMutex.RLock()
 	defer fake.recreateMutex.RUnlock()
+	fake.restoreDatabasesMutex.RLock()
+	defer fake.restoreDatabasesMutex.RUnlock()
+	fake.retireWorkersMutex.RLock()
+	defer fake.retireWorkersMutex.RUnlock()
+	fake.sSHMutex.RLock()
+	defer fake.sSHMutex.RUnlock()
+	fake.scaleWorkersMutex.RLock()
+	defer fake.scaleWorkersMutex.RUnlock()
+	fake.unlandWorkersMutex.RLock()
+	defer fake.unlandWorkersMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
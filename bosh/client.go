@@ -2,10 +2,10 @@ package bosh
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"os/exec"
 
 	"github.com/EngineerBetter/control-tower/iaas"
 
@@ -28,12 +28,21 @@ const CredsFilename = "director-creds.yml"
 //counterfeiter:generate . IClient
 // IClient is a client for performing bosh-init commands
 type IClient interface {
-	Deploy([]byte, []byte, bool) ([]byte, []byte, error)
+	Deploy(context.Context, []byte, []byte, bool, Checkpoint) ([]byte, []byte, error)
+	Diff(context.Context, []byte) (string, error)
 	Cleanup() error
-	Instances() ([]Instance, error)
-	CreateEnv([]byte, []byte, string) ([]byte, []byte, error)
-	Recreate() error
-	Locks() ([]byte, error)
+	Instances(context.Context) ([]Instance, error)
+	CreateEnv(context.Context, []byte, []byte, string) ([]byte, []byte, error)
+	Recreate(context.Context) error
+	Locks(context.Context) ([]byte, error)
+	BackupDatabases(context.Context) (map[string][]byte, error)
+	RestoreDatabases(context.Context, map[string][]byte) error
+	ScaleWorkers(context.Context, []byte) ([]byte, error)
+	RetireWorkers(context.Context, string, int) error
+	LandWorkers(context.Context, int) error
+	UnlandWorkers(context.Context, int) error
+	SSH(context.Context, string, string, io.Reader) error
+	Logs(context.Context, string, string, bool, string) error
 }
 
 // Instance represents a vm deployed by BOSH
@@ -65,21 +74,24 @@ func New(config config.ConfigView, outputs terraform.Outputs, stdout, stderr io.
 		return nil, fmt.Errorf("failed to determine BOSH CLI path: [%v]", err)
 	}
 
-	boshCLI := boshcli.New(boshCLIPath, exec.Command)
+	boshCLI := boshcli.New(boshCLIPath, util.CommandContext)
 
 	switch provider.IAAS() {
 	case iaas.AWS:
 		return NewAWSClient(config, outputs, workingdir, stdout, stderr, provider, boshCLI, versionFile)
 	case iaas.GCP:
 		return NewGCPClient(config, outputs, workingdir, stdout, stderr, provider, boshCLI, versionFile)
+	case iaas.Azure:
+		return NewAzureClient(config, outputs, workingdir, stdout, stderr, provider, boshCLI, versionFile)
 	}
 	return nil, fmt.Errorf("IAAS not supported: %s", provider.IAAS())
 }
 
-func instances(boshCLI boshcli.ICLI, ip, password, ca string) ([]Instance, error) {
+func instances(ctx context.Context, boshCLI boshcli.ICLI, ip, password, ca string) ([]Instance, error) {
 	output := new(bytes.Buffer)
 
 	if err := boshCLI.RunAuthenticatedCommand(
+		ctx,
 		"instances",
 		ip,
 		password,
@@ -120,15 +132,35 @@ func instances(boshCLI boshcli.ICLI, ip, password, ca string) ([]Instance, error
 	return instances, nil
 }
 
-func saveFilesToWorkingDir(workingdir workingdir.IClient, provider iaas.Provider, creds []byte) error {
-	concourseVersionsContents, _ := provider.Choose(iaas.Choice{
+func concourseVersionsAndSHAs(provider iaas.Provider) ([]byte, []byte, error) {
+	if provider.IAAS() == iaas.Azure {
+		versions, err := Asset(azureConcourseVersionsAsset)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load Azure release versions: [%v]", err)
+		}
+		shas, err := Asset(azureConcourseSHAsAsset)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load Azure release SHAs: [%v]", err)
+		}
+		return versions, shas, nil
+	}
+
+	versions, _ := provider.Choose(iaas.Choice{
 		AWS: awsConcourseVersions,
 		GCP: gcpConcourseVersions,
 	}).([]byte)
-	concourseSHAsContents, _ := provider.Choose(iaas.Choice{
+	shas, _ := provider.Choose(iaas.Choice{
 		AWS: awsConcourseSHAs,
 		GCP: gcpConcourseSHAs,
 	}).([]byte)
+	return versions, shas, nil
+}
+
+func saveFilesToWorkingDir(workingdir workingdir.IClient, provider iaas.Provider, creds []byte) error {
+	concourseVersionsContents, concourseSHAsContents, err := concourseVersionsAndSHAs(provider)
+	if err != nil {
+		return err
+	}
 
 	filesToSave := map[string][]byte{
 		concourseVersionsFilename:      concourseVersionsContents,
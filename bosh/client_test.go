@@ -1,6 +1,7 @@
 package bosh_test
 
 import (
+	"context"
 	"errors"
 	"io"
 
@@ -239,7 +240,7 @@ sWbB3FCIsym1FXB+eRnVF3Y15RwBWWKA5RfwUNpEXFxtv24tQ8jrdA==
 			})
 			Context("When instances are found", func() {
 				JustBeforeEach(func() {
-					boshCLI.RunAuthenticatedCommandStub = func(action, ip, password, ca string, detach bool, stdout io.Writer, flags ...string) error {
+					boshCLI.RunAuthenticatedCommandStub = func(ctx context.Context, action, ip, password, ca string, detach bool, stdout io.Writer, flags ...string) error {
 						stdout.Write([]byte("{\"Tables\":[{\"Rows\": [{\"instance\": \"foo\",\"ips\": \"1.2.3.4\", \"process_state\": \"bar\"}]}]}"))
 						return nil
 					}
@@ -252,7 +253,7 @@ sWbB3FCIsym1FXB+eRnVF3Y15RwBWWKA5RfwUNpEXFxtv24tQ8jrdA==
 					}
 
 					client := buildClient()
-					instances, err := client.Instances()
+					instances, err := client.Instances(context.Background())
 					Expect(err).ToNot(HaveOccurred())
 
 					Expect(instances).To(Equal([]bosh.Instance{expectedInstance}))
@@ -21,4 +21,9 @@ var awsConcourseVersions = MustAsset("../../control-tower-ops/ops/versions-aws.j
 var awsConcourseSHAs = MustAsset("../../control-tower-ops/ops/shas-aws.json")
 var gcpConcourseVersions = MustAsset("../../control-tower-ops/ops/versions-gcp.json")
 var gcpConcourseSHAs = MustAsset("../../control-tower-ops/ops/shas-gcp.json")
+
+// The Azure ops files are loaded when an Azure deployment needs them, rather than at init,
+// so that a control-tower-ops without them only fails Azure deployments
+const azureConcourseVersionsAsset = "../../control-tower-ops/ops/versions-azure.json"
+const azureConcourseSHAsAsset = "../../control-tower-ops/ops/shas-azure.json"
 var uaaCert = MustAsset("../resource/assets/gcp/uaa-cert.yml")
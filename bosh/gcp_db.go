@@ -1,5 +1,47 @@
 package bosh
 
+import (
+	"fmt"
+	"net"
+
+	"github.com/lib/pq"
+	"golang.org/x/crypto/ssh"
+)
+
 func (client *GCPClient) createDefaultDatabases() error {
 	return client.provider.CreateDatabases(client.config.GetRDSDefaultDatabaseName(), client.config.GetRDSUsername(), client.config.GetRDSPassword())
 }
+
+// openDB proxies connections to Cloud SQL through the director's jumpbox user
+func (client *GCPClient) openDB() (Opener, error) {
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get DirectorPublicIP from terraform outputs: [%v]", err)
+	}
+	key, err := ssh.ParsePrivateKey([]byte(client.config.GetPrivateKey()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key for bosh: [%v]", err)
+	}
+	conf := &ssh.ClientConfig{
+		User:            "jumpbox",
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(key)},
+	}
+	boshDBAddress, err := client.outputs.Get("BoshDBAddress")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get BoshDBAddress from terraform outputs: [%v]", err)
+	}
+
+	db, err := newProxyOpener(net.JoinHostPort(directorPublicIP, "22"), conf, &pq.Driver{},
+		fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=disable",
+			client.config.GetRDSUsername(),
+			client.config.GetRDSPassword(),
+			net.JoinHostPort(boshDBAddress, "5432"),
+			client.config.GetRDSDefaultDatabaseName(),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create db proxyOpener: [%v]", err)
+	}
+	return db, nil
+}
@@ -1,6 +1,7 @@
 package bosh
 
 import (
+	"context"
 	"net"
 
 	"github.com/EngineerBetter/control-tower/bosh/internal/boshcli"
@@ -9,27 +10,33 @@ import (
 
 // Deploy deploys a new Bosh director or converges an existing deployment
 // Returns new contents of bosh state file
-func (client *GCPClient) Deploy(state, creds []byte, detach bool) (newState, newCreds []byte, err error) {
+func (client *GCPClient) Deploy(ctx context.Context, state, creds []byte, detach bool, checkpoint Checkpoint) (newState, newCreds []byte, err error) {
 	if err != nil {
 		return state, creds, err
 	}
 
-	state, creds, err = client.CreateEnv(state, creds, "")
+	err = runPhase(checkpoint, CreateEnvPhase, func() error {
+		state, creds, err = client.CreateEnv(ctx, state, creds, "")
+		return err
+	})
 	if err != nil {
 		return state, creds, err
 	}
 
-	if err = client.updateCloudConfig(client.boshCLI); err != nil {
+	if err = runPhase(checkpoint, CloudConfigPhase, func() error { return client.updateCloudConfig(ctx, client.boshCLI) }); err != nil {
 		return state, creds, err
 	}
-	if err = client.uploadConcourseStemcell(client.boshCLI); err != nil {
+	if err = runPhase(checkpoint, StemcellPhase, func() error { return client.uploadConcourseStemcell(ctx, client.boshCLI) }); err != nil {
 		return state, creds, err
 	}
-	if err = client.createDefaultDatabases(); err != nil {
+	if err = runPhase(checkpoint, DatabasesPhase, client.createDefaultDatabases); err != nil {
 		return state, creds, err
 	}
 
-	creds, err = client.deployConcourse(creds, detach)
+	err = runPhase(checkpoint, ConcoursePhase, func() error {
+		creds, err = client.deployConcourse(ctx, creds, detach)
+		return err
+	})
 	if err != nil {
 		return state, creds, err
 	}
@@ -38,7 +45,7 @@ func (client *GCPClient) Deploy(state, creds []byte, detach bool) (newState, new
 }
 
 // CreateEnv exposes bosh create-env functionality
-func (client *GCPClient) CreateEnv(state, creds []byte, customOps string) (newState, newCreds []byte, err error) {
+func (client *GCPClient) CreateEnv(ctx context.Context, state, creds []byte, customOps string) (newState, newCreds []byte, err error) {
 	tags, err := splitTags(client.config.GetTags())
 	if err != nil {
 		return state, creds, err
@@ -85,7 +92,7 @@ func (client *GCPClient) CreateEnv(state, creds []byte, customOps string) (newSt
 		return state, creds, err1
 	}
 
-	createEnvFiles, err1 := client.boshCLI.CreateEnv(&boshcli.CreateEnvFiles{StateFileContents: state, VarsFileContents: creds}, boshcli.GCPEnvironment{
+	createEnvFiles, err1 := client.boshCLI.CreateEnv(ctx, &boshcli.CreateEnvFiles{StateFileContents: state, VarsFileContents: creds}, boshcli.GCPEnvironment{
 		InternalCIDR:       client.config.GetPublicCIDR(),
 		InternalGW:         internalGateway.String(),
 		InternalIP:         directorInternalIP.String(),
@@ -110,29 +117,29 @@ func (client *GCPClient) CreateEnv(state, creds []byte, customOps string) (newSt
 }
 
 // Recreate exposes BOSH recreate
-func (client *GCPClient) Recreate() error {
+func (client *GCPClient) Recreate(ctx context.Context) error {
 	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
 	if err != nil {
 		return err
 	}
-	return client.boshCLI.Recreate(boshcli.GCPEnvironment{
+	return client.boshCLI.Recreate(ctx, boshcli.GCPEnvironment{
 		ExternalIP: directorPublicIP,
 	}, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert())
 }
 
 // Locks implements locks for GCP client
-func (client *GCPClient) Locks() ([]byte, error) {
+func (client *GCPClient) Locks(ctx context.Context) ([]byte, error) {
 	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
 	if err != nil {
 		return nil, err
 	}
-	return client.boshCLI.Locks(boshcli.GCPEnvironment{
+	return client.boshCLI.Locks(ctx, boshcli.GCPEnvironment{
 		ExternalIP: directorPublicIP,
 	}, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert())
 
 }
 
-func (client *GCPClient) updateCloudConfig(bosh boshcli.ICLI) error {
+func (client *GCPClient) updateCloudConfig(ctx context.Context, bosh boshcli.ICLI) error {
 
 	privateSubnetwork, err := client.outputs.Get("PrivateSubnetworkName")
 	if err != nil {
@@ -192,7 +199,7 @@ func (client *GCPClient) updateCloudConfig(bosh boshcli.ICLI) error {
 		return err
 	}
 
-	return bosh.UpdateCloudConfig(boshcli.GCPEnvironment{
+	return bosh.UpdateCloudConfig(ctx, boshcli.GCPEnvironment{
 		PublicCIDR:          client.config.GetPublicCIDR(),
 		PublicCIDRGateway:   publicCIDRGateway,
 		PublicCIDRStatic:    publicCIDRStatic,
@@ -201,18 +208,19 @@ func (client *GCPClient) updateCloudConfig(bosh boshcli.ICLI) error {
 		PrivateCIDRReserved: privateCIDRReserved,
 		PrivateCIDR:         client.config.GetPrivateCIDR(),
 		Spot:                client.config.IsSpot(),
+		WorkerPools:         client.config.GetWorkerPools(),
 		PublicSubnetwork:    publicSubnetwork,
 		PrivateSubnetwork:   privateSubnetwork,
 		Zone:                zone,
 		Network:             network,
 	}, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert())
 }
-func (client *GCPClient) uploadConcourseStemcell(bosh boshcli.ICLI) error {
+func (client *GCPClient) uploadConcourseStemcell(ctx context.Context, bosh boshcli.ICLI) error {
 	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
 	if err != nil {
 		return err
 	}
-	return bosh.UploadConcourseStemcell(boshcli.GCPEnvironment{
+	return bosh.UploadConcourseStemcell(ctx, boshcli.GCPEnvironment{
 		ExternalIP: directorPublicIP,
 	}, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert())
 }
@@ -1,6 +1,7 @@
 package boshcli
 
 import (
+	"github.com/EngineerBetter/control-tower/config"
 	"github.com/EngineerBetter/control-tower/resource"
 	"github.com/EngineerBetter/control-tower/util"
 	"github.com/EngineerBetter/control-tower/util/yaml"
@@ -42,6 +43,7 @@ type AWSEnvironment struct {
 	Spot                  bool
 	VersionFile           []byte
 	VMSecurityGroup       string
+	WorkerPools           []config.WorkerPool
 	WorkerType            string
 }
 
@@ -134,7 +136,16 @@ func (e AWSEnvironment) ConfigureDirectorCloudConfig() (string, error) {
 	if cc == nil {
 		return "", err
 	}
-	return string(cc), err
+	if err != nil {
+		return string(cc), err
+	}
+
+	return addWorkerPoolVMTypes(string(cc), e.WorkerPools, func(spot bool) (string, error) {
+		poolParams := templateParams
+		poolParams.Spot = spot
+		poolCC, err := util.RenderTemplate("cloud-config", resource.AWSDirectorCloudConfig, poolParams)
+		return string(poolCC), err
+	})
 }
 
 func (e AWSEnvironment) ConcourseStemcellURL() (string, error) {
@@ -0,0 +1,147 @@
+package boshcli
+
+import (
+	"fmt"
+
+	"github.com/EngineerBetter/control-tower/config"
+	"github.com/EngineerBetter/control-tower/resource"
+	"github.com/EngineerBetter/control-tower/util"
+	"github.com/EngineerBetter/control-tower/util/yaml"
+)
+
+// AzureEnvironment holds all the parameters Azure IAAS needs
+type AzureEnvironment struct {
+	ATCSecurityGroup      string
+	ClientID              string
+	ClientSecret          string
+	CustomOperations      string
+	DirectorName          string
+	DirectorSecurityGroup string
+	ExternalIP            string
+	InternalCIDR          string
+	InternalGW            string
+	InternalIP            string
+	Network               string
+	NetworkResourceGroup  string
+	PrivateCIDR           string
+	PrivateCIDRGateway    string
+	PrivateCIDRReserved   string
+	PrivateKey            string
+	PrivateSubnetwork     string
+	PublicCIDR            string
+	PublicCIDRGateway     string
+	PublicCIDRReserved    string
+	PublicCIDRStatic      string
+	PublicKey             string
+	PublicSubnetwork      string
+	SubscriptionID        string
+	TenantID              string
+	VersionFile           []byte
+	VMsResourceGroup      string
+	VMsSecurityGroup      string
+	WorkerPools           []config.WorkerPool
+	Zone                  string
+}
+
+func (e AzureEnvironment) ExtractBOSHandBPM() (util.Resource, util.Resource, error) {
+	resources := util.ParseVersionResources(e.VersionFile)
+
+	boshRelease := util.GetResource("bosh", resources)
+	bpmRelease := util.GetResource("bpm", resources)
+
+	return boshRelease, bpmRelease, nil
+}
+
+// ConfigureDirectorManifestCPI interpolates all the Environment parameters and
+// required release versions into ready to use Director manifest
+func (e AzureEnvironment) ConfigureDirectorManifestCPI() (string, error) {
+	resources := util.ParseVersionResources(e.VersionFile)
+
+	cpiResource := util.GetResource("cpi", resources)
+	stemcellResource := util.GetResource("stemcell", resources)
+
+	var allOperations = resource.AzureCPIOps + resource.AzureExternalIPOps + resource.AzureDirectorCustomOps
+
+	return yaml.Interpolate(resource.DirectorManifest, allOperations+e.CustomOperations, map[string]interface{}{
+		"cpi_url":                 cpiResource.URL,
+		"cpi_version":             cpiResource.Version,
+		"cpi_sha1":                cpiResource.SHA1,
+		"stemcell_url":            stemcellResource.URL,
+		"stemcell_sha1":           stemcellResource.SHA1,
+		"internal_cidr":           e.InternalCIDR,
+		"internal_gw":             e.InternalGW,
+		"internal_ip":             e.InternalIP,
+		"director_name":           e.DirectorName,
+		"network":                 e.Network,
+		"subnetwork":              e.PublicSubnetwork,
+		"network_resource_group":  e.NetworkResourceGroup,
+		"vms_resource_group":      e.VMsResourceGroup,
+		"director_security_group": e.DirectorSecurityGroup,
+		"vms_security_group":      e.VMsSecurityGroup,
+		"subscription_id":         e.SubscriptionID,
+		"tenant_id":               e.TenantID,
+		"client_id":               e.ClientID,
+		"client_secret":           e.ClientSecret,
+		"external_ip":             e.ExternalIP,
+		"public_key":              e.PublicKey,
+		"private_key":             e.PrivateKey,
+	})
+}
+
+type azureCloudConfigParams struct {
+	ATCSecurityGroup     string
+	Network              string
+	NetworkResourceGroup string
+	PrivateCIDR          string
+	PrivateCIDRGateway   string
+	PrivateCIDRReserved  string
+	PrivateSubnetwork    string
+	PublicCIDR           string
+	PublicCIDRGateway    string
+	PublicCIDRReserved   string
+	PublicCIDRStatic     string
+	PublicSubnetwork     string
+	VMsResourceGroup     string
+	Zone                 string
+}
+
+// ConfigureDirectorCloudConfig inserts values from the environment into the config template passed as argument
+func (e AzureEnvironment) ConfigureDirectorCloudConfig() (string, error) {
+	templateParams := azureCloudConfigParams{
+		ATCSecurityGroup:     e.ATCSecurityGroup,
+		Network:              e.Network,
+		NetworkResourceGroup: e.NetworkResourceGroup,
+		PrivateCIDR:          e.PrivateCIDR,
+		PrivateCIDRGateway:   e.PrivateCIDRGateway,
+		PrivateCIDRReserved:  e.PrivateCIDRReserved,
+		PrivateSubnetwork:    e.PrivateSubnetwork,
+		PublicCIDR:           e.PublicCIDR,
+		PublicCIDRGateway:    e.PublicCIDRGateway,
+		PublicCIDRReserved:   e.PublicCIDRReserved,
+		PublicCIDRStatic:     e.PublicCIDRStatic,
+		PublicSubnetwork:     e.PublicSubnetwork,
+		VMsResourceGroup:     e.VMsResourceGroup,
+		Zone:                 e.Zone,
+	}
+
+	cc, err := util.RenderTemplate("cloud-config", resource.AzureDirectorCloudConfig, templateParams)
+	if cc == nil {
+		return "", err
+	}
+	if err != nil {
+		return string(cc), err
+	}
+
+	// Azure has no spot instances so every pool renders the same VM types
+	return addWorkerPoolVMTypes(string(cc), e.WorkerPools, func(bool) (string, error) {
+		return string(cc), nil
+	})
+}
+
+func (e AzureEnvironment) ConcourseStemcellURL() (string, error) {
+	versions, err := resource.AzureReleaseVersions()
+	if err != nil {
+		return "", fmt.Errorf("failed to load Azure release versions: [%v]", err)
+	}
+	return concourseStemcellURL(versions, "https://s3.amazonaws.com/bosh-azure-stemcells/%s/bosh-stemcell-%s-azure-hyperv-ubuntu-xenial-go_agent.tgz")
+}
@@ -0,0 +1,93 @@
+package boshcli
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/EngineerBetter/control-tower/config"
+	"github.com/EngineerBetter/control-tower/resource"
+)
+
+func TestAzureEnvironment_ConfigureConcourseStemcell(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+		fixture string
+	}{
+		{
+			name:    "parse versions and provide a valid stemcell url",
+			want:    "https://s3.amazonaws.com/bosh-azure-stemcells/5/bosh-stemcell-5-azure-hyperv-ubuntu-xenial-go_agent.tgz",
+			wantErr: false,
+			fixture: "stemcell_version",
+		},
+		{
+			name:    "parse versions and indicate no stemcell was found",
+			want:    "",
+			wantErr: true,
+			fixture: "invalid_stemcell_version",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := AzureEnvironment{}
+			resource.AzureReleaseVersions = func() (string, error) { return getStemcellFixture(tt.fixture), nil }
+			got, err := e.ConcourseStemcellURL()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Environment.ConcourseStemcellURL() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Environment.ConcourseStemcellURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAzureEnvironment_ConfigureDirectorCloudConfig(t *testing.T) {
+	environment := AzureEnvironment{
+		ATCSecurityGroup:     "atc_security_group",
+		Network:              "network",
+		NetworkResourceGroup: "network_resource_group",
+		PrivateCIDR:          "10.0.1.0/24",
+		PrivateCIDRGateway:   "10.0.1.1",
+		PrivateCIDRReserved:  "10.0.1.1-10.0.1.5",
+		PrivateSubnetwork:    "private_subnetwork",
+		PublicCIDR:           "10.0.0.0/24",
+		PublicCIDRGateway:    "10.0.0.1",
+		PublicCIDRReserved:   "10.0.0.1-10.0.0.5",
+		PublicCIDRStatic:     "10.0.0.7",
+		PublicSubnetwork:     "public_subnetwork",
+		VMsResourceGroup:     "vms_resource_group",
+		WorkerPools:          []config.WorkerPool{{Name: "big", Size: "xlarge"}},
+	}
+
+	actual, err := environment.ConfigureDirectorCloudConfig()
+	if err != nil {
+		t.Fatalf("ConfigureDirectorCloudConfig() error = %v", err)
+	}
+	for _, want := range []string{"resource_group_name: vms_resource_group", "security_group: atc_security_group", "name: concourse-pool-big", "instance_type: Standard_D4s_v3"} {
+		if !strings.Contains(actual, want) {
+			t.Errorf("ConfigureDirectorCloudConfig() does not contain %q", want)
+		}
+	}
+	if strings.Contains(actual, "availability_zone") {
+		t.Error("ConfigureDirectorCloudConfig() should not set an availability zone when none is given")
+	}
+}
+
+func Test_AzureCloudConfigStructureTest(t *testing.T) {
+	t.Run("validating structure", func(t *testing.T) {
+		templ, err := template.New("template").Option("missingkey=error").Parse(resource.AzureDirectorCloudConfig)
+		if err != nil {
+			t.Errorf("cannot parse the template")
+		}
+		emptyAzureCloudConfigParams := azureCloudConfigParams{}
+		for k, v := range matchStructFields(emptyAzureCloudConfigParams, listTemplFields(templ)) {
+			if v < 2 {
+				t.Errorf("Field with key name %s is not mapped properly", k)
+			}
+		}
+	})
+}
@@ -3,6 +3,7 @@ package boshcli
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -19,12 +20,13 @@ import (
 
 //counterfeiter:generate . ICLI
 type ICLI interface {
-	CreateEnv(createEnvFiles *CreateEnvFiles, config IAASEnvironment, password, cert, key, ca string, tags map[string]string) (*CreateEnvFiles, error)
-	RunAuthenticatedCommand(action, ip, password, ca string, detach bool, stdout io.Writer, flags ...string) error
-	Locks(config IAASEnvironment, ip, password, ca string) ([]byte, error)
-	Recreate(config IAASEnvironment, ip, password, ca string) error
-	UpdateCloudConfig(config IAASEnvironment, ip, password, ca string) error
-	UploadConcourseStemcell(config IAASEnvironment, ip, password, ca string) error
+	CreateEnv(ctx context.Context, createEnvFiles *CreateEnvFiles, config IAASEnvironment, password, cert, key, ca string, tags map[string]string) (*CreateEnvFiles, error)
+	RunAuthenticatedCommand(ctx context.Context, action, ip, password, ca string, detach bool, stdout io.Writer, flags ...string) error
+	RunInteractiveCommand(ctx context.Context, action, ip, password, ca string, stdin io.Reader, stdout io.Writer, flags ...string) error
+	Locks(ctx context.Context, config IAASEnvironment, ip, password, ca string) ([]byte, error)
+	Recreate(ctx context.Context, config IAASEnvironment, ip, password, ca string) error
+	UpdateCloudConfig(ctx context.Context, config IAASEnvironment, ip, password, ca string) error
+	UploadConcourseStemcell(ctx context.Context, config IAASEnvironment, ip, password, ca string) error
 }
 
 type CreateEnvFiles struct {
@@ -34,12 +36,12 @@ type CreateEnvFiles struct {
 
 // CLI struct holds the abstraction of execCmd
 type CLI struct {
-	execCmd  func(string, ...string) *exec.Cmd
+	execCmd  func(context.Context, string, ...string) *exec.Cmd
 	boshPath string
 }
 
 // New provides a new CLI
-func New(boshPath string, execCmdFunc func(string, ...string) *exec.Cmd) ICLI {
+func New(boshPath string, execCmdFunc func(context.Context, string, ...string) *exec.Cmd) ICLI {
 	return &CLI{
 		execCmd:  execCmdFunc,
 		boshPath: boshPath,
@@ -80,7 +82,7 @@ func concourseStemcellURL(releaseVersionsFile, urlFormat string) (string, error)
 }
 
 // UpdateCloudConfig generates cloud config from template and use it to update bosh cloud config
-func (c *CLI) UpdateCloudConfig(config IAASEnvironment, ip, password, ca string) error {
+func (c *CLI) UpdateCloudConfig(ctx context.Context, config IAASEnvironment, ip, password, ca string) error {
 	var cloudConfig string
 	var err error
 
@@ -99,21 +101,21 @@ func (c *CLI) UpdateCloudConfig(config IAASEnvironment, ip, password, ca string)
 	}
 	defer os.Remove(caPath)
 	ip = fmt.Sprintf("https://%s", ip)
-	cmd := c.execCmd(c.boshPath, "--non-interactive", "--environment", ip, "--ca-cert", caPath, "--client", "admin", "--client-secret", password, "update-cloud-config", cloudConfigPath)
+	cmd := c.execCmd(ctx, c.boshPath, "--non-interactive", "--environment", ip, "--ca-cert", caPath, "--client", "admin", "--client-secret", password, "update-cloud-config", cloudConfigPath)
 	cmd.Stderr = os.Stderr
 	cmd.Stdout = os.Stdout
 	return cmd.Run()
 }
 
 // Locks runs bosh locks
-func (c *CLI) Locks(config IAASEnvironment, ip, password, ca string) ([]byte, error) {
+func (c *CLI) Locks(ctx context.Context, config IAASEnvironment, ip, password, ca string) ([]byte, error) {
 	var out bytes.Buffer
 	caPath, err := writeTempFile([]byte(ca))
 	if err != nil {
 		return nil, err
 	}
 	defer os.Remove(caPath)
-	cmd := c.execCmd(c.boshPath, "--environment", ip, "--ca-cert", caPath, "--client", "admin", "--client-secret", password, "locks", "--json")
+	cmd := c.execCmd(ctx, c.boshPath, "--environment", ip, "--ca-cert", caPath, "--client", "admin", "--client-secret", password, "locks", "--json")
 	cmd.Stdout = &out
 	err = cmd.Run()
 	if err != nil {
@@ -123,7 +125,7 @@ func (c *CLI) Locks(config IAASEnvironment, ip, password, ca string) ([]byte, er
 }
 
 // UploadConcourseStemcell uploads a stemcell for the chosen IAAS
-func (c *CLI) UploadConcourseStemcell(config IAASEnvironment, ip, password, ca string) error {
+func (c *CLI) UploadConcourseStemcell(ctx context.Context, config IAASEnvironment, ip, password, ca string) error {
 	var (
 		stemcell string
 		err      error
@@ -140,27 +142,27 @@ func (c *CLI) UploadConcourseStemcell(config IAASEnvironment, ip, password, ca s
 	}
 	defer os.Remove(caPath)
 	ip = fmt.Sprintf("https://%s", ip)
-	cmd := c.execCmd(c.boshPath, "--non-interactive", "--environment", ip, "--ca-cert", caPath, "--client", "admin", "--client-secret", password, "upload-stemcell", stemcell)
+	cmd := c.execCmd(ctx, c.boshPath, "--non-interactive", "--environment", ip, "--ca-cert", caPath, "--client", "admin", "--client-secret", password, "upload-stemcell", stemcell)
 	cmd.Stderr = os.Stderr
 	cmd.Stdout = os.Stdout
 	return cmd.Run()
 }
 
 // Recreate runs BOSH recreate
-func (c *CLI) Recreate(config IAASEnvironment, ip, password, ca string) error {
+func (c *CLI) Recreate(ctx context.Context, config IAASEnvironment, ip, password, ca string) error {
 	caPath, err := writeTempFile([]byte(ca))
 	if err != nil {
 		return err
 	}
 	defer os.Remove(caPath)
 	ip = fmt.Sprintf("https://%s", ip)
-	cmd := c.execCmd(c.boshPath, "--non-interactive", "--environment", ip, "--ca-cert", caPath, "--client", "admin", "--client-secret", password, "--deployment", "concourse", "recreate")
+	cmd := c.execCmd(ctx, c.boshPath, "--non-interactive", "--environment", ip, "--ca-cert", caPath, "--client", "admin", "--client-secret", password, "--deployment", "concourse", "recreate")
 	cmd.Stderr = os.Stderr
 	cmd.Stdout = os.Stdout
 	return cmd.Run()
 }
 
-func (c *CLI) CreateEnv(createEnvFiles *CreateEnvFiles, config IAASEnvironment, password, cert, key, ca string, tags map[string]string) (*CreateEnvFiles, error) {
+func (c *CLI) CreateEnv(ctx context.Context, createEnvFiles *CreateEnvFiles, config IAASEnvironment, password, cert, key, ca string, tags map[string]string) (*CreateEnvFiles, error) {
 	manifest, err := config.ConfigureDirectorManifestCPI()
 	if err != nil {
 		return &CreateEnvFiles{}, err
@@ -203,7 +205,7 @@ func (c *CLI) CreateEnv(createEnvFiles *CreateEnvFiles, config IAASEnvironment,
 	}
 	defer os.Remove(manifestPath)
 
-	cmd := c.execCmd(c.boshPath, "create-env", "--state="+statePath, "--vars-store="+varsPath, manifestPath)
+	cmd := c.execCmd(ctx, c.boshPath, "create-env", "--state="+statePath, "--vars-store="+varsPath, manifestPath)
 	cmd.Stderr = os.Stderr
 	cmd.Stdout = os.Stdout
 
@@ -229,31 +231,50 @@ func (c *CLI) CreateEnv(createEnvFiles *CreateEnvFiles, config IAASEnvironment,
 // RunAuthenticatedCommand runs the bosh command `action` with flags `flags`
 // specifying `detach` will cause the task to detach once a deployment starts
 // `detach` is currently only implemented with the action `deploy`
-func (c *CLI) RunAuthenticatedCommand(action, ip, password, ca string, detach bool, stdout io.Writer, flags ...string) error {
+func (c *CLI) RunAuthenticatedCommand(ctx context.Context, action, ip, password, ca string, detach bool, stdout io.Writer, flags ...string) error {
 	caPath, err := writeTempFile([]byte(ca))
 	if err != nil {
 		return err
 	}
 	defer os.Remove(caPath)
-	ip = fmt.Sprintf("https://%s", ip)
 
-	authFlags := []string{"--non-interactive", "--environment", ip, "--ca-cert", caPath, "--client", "admin", "--client-secret", password, "--deployment", "concourse", action}
-	flags = append(authFlags, flags...)
+	flags = append(authFlags(action, ip, password, caPath), flags...)
 	if detach && action == "deploy" {
-		return c.detachedBoshCommand(stdout, flags...)
+		return c.detachedBoshCommand(ctx, stdout, flags...)
+	}
+	return c.boshCommand(ctx, stdout, flags...)
+}
+
+// RunInteractiveCommand runs the bosh command `action` with flags `flags`
+// connecting `stdin` to the command so that the user can interact with it
+func (c *CLI) RunInteractiveCommand(ctx context.Context, action, ip, password, ca string, stdin io.Reader, stdout io.Writer, flags ...string) error {
+	caPath, err := writeTempFile([]byte(ca))
+	if err != nil {
+		return err
 	}
-	return c.boshCommand(stdout, flags...)
+	defer os.Remove(caPath)
+
+	cmd := c.execCmd(ctx, c.boshPath, append(authFlags(action, ip, password, caPath), flags...)...)
+	util.Interactive(cmd)
+	cmd.Stdin = stdin
+	cmd.Stderr = os.Stderr
+	cmd.Stdout = stdout
+	return cmd.Run()
+}
+
+func authFlags(action, ip, password, caPath string) []string {
+	return []string{"--non-interactive", "--environment", fmt.Sprintf("https://%s", ip), "--ca-cert", caPath, "--client", "admin", "--client-secret", password, "--deployment", "concourse", action}
 }
 
-func (c *CLI) boshCommand(stdout io.Writer, flags ...string) error {
-	cmd := c.execCmd(c.boshPath, flags...)
+func (c *CLI) boshCommand(ctx context.Context, stdout io.Writer, flags ...string) error {
+	cmd := c.execCmd(ctx, c.boshPath, flags...)
 	cmd.Stderr = os.Stderr
 	cmd.Stdout = stdout
 	return cmd.Run()
 }
 
-func (c *CLI) detachedBoshCommand(stdout io.Writer, flags ...string) error {
-	cmd := c.execCmd(c.boshPath, flags...)
+func (c *CLI) detachedBoshCommand(ctx context.Context, stdout io.Writer, flags ...string) error {
+	cmd := c.execCmd(ctx, c.boshPath, flags...)
 	cmd.Stderr = os.Stderr
 
 	cmdReader, err := cmd.StdoutPipe()
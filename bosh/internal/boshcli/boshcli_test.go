@@ -1,6 +1,7 @@
 package boshcli_test
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strconv"
@@ -43,19 +44,19 @@ func (c mockIAASConfig) ExtractBOSHandBPM() (util.Resource, util.Resource, error
 func TestCLI_CreateEnv(t *testing.T) {
 	e := fakeexec.New(t)
 	defer e.Finish()
-	c := boshcli.New("bosh", e.Cmd())
+	c := boshcli.New("bosh", e.CmdContext())
 	config := mockIAASConfig{}
 	e.ExpectFunc(func(t testing.TB, command string, args ...string) {
 		require.Equal(t, "bosh", command)
 		require.Equal(t, "create-env", args[0])
 	})
-	c.CreateEnv(&boshcli.CreateEnvFiles{}, config, "password", "cert", "key", "ca", map[string]string{})
+	c.CreateEnv(context.Background(), &boshcli.CreateEnvFiles{}, config, "password", "cert", "key", "ca", map[string]string{})
 }
 
 func TestCLI_UpdateCloudConfig(t *testing.T) {
 	e := fakeexec.New(t)
 	defer e.Finish()
-	c := boshcli.New("bosh", e.Cmd())
+	c := boshcli.New("bosh", e.CmdContext())
 	config := mockIAASConfig{}
 	e.ExpectFunc(func(t testing.TB, command string, args ...string) {
 		require.Equal(t, "bosh", command)
@@ -67,14 +68,14 @@ func TestCLI_UpdateCloudConfig(t *testing.T) {
 		require.Equal(t, "password", args[8])
 		require.Equal(t, "update-cloud-config", args[9])
 	})
-	err := c.UpdateCloudConfig(config, "ip", "password", "ca")
+	err := c.UpdateCloudConfig(context.Background(), config, "ip", "password", "ca")
 	require.NoError(t, err)
 }
 
 func TestCLI_UploadConcourseStemcell(t *testing.T) {
 	e := fakeexec.New(t)
 	defer e.Finish()
-	c := boshcli.New("bosh", e.Cmd())
+	c := boshcli.New("bosh", e.CmdContext())
 	config := mockIAASConfig{}
 	e.ExpectFunc(func(t testing.TB, command string, args ...string) {
 		require.Equal(t, "bosh", command)
@@ -86,7 +87,7 @@ func TestCLI_UploadConcourseStemcell(t *testing.T) {
 		require.Equal(t, "password", args[8])
 		require.Equal(t, "upload-stemcell", args[9])
 	})
-	err := c.UploadConcourseStemcell(config, "ip", "password", "ca")
+	err := c.UploadConcourseStemcell(context.Background(), config, "ip", "password", "ca")
 	require.NoError(t, err)
 
 }
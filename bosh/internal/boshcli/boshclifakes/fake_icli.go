@@ -2,6 +2,7 @@
 package boshclifakes
 
 import (
+	"context"
 	"io"
 	"sync"
 
@@ -9,16 +10,17 @@ import (
 )
 
 type FakeICLI struct {
-	CreateEnvStub        func(*boshcli.CreateEnvFiles, boshcli.IAASEnvironment, string, string, string, string, map[string]string) (*boshcli.CreateEnvFiles, error)
+	CreateEnvStub        func(context.Context, *boshcli.CreateEnvFiles, boshcli.IAASEnvironment, string, string, string, string, map[string]string) (*boshcli.CreateEnvFiles, error)
 	createEnvMutex       sync.RWMutex
 	createEnvArgsForCall []struct {
-		arg1 *boshcli.CreateEnvFiles
-		arg2 boshcli.IAASEnvironment
-		arg3 string
+		arg1 context.Context
+		arg2 *boshcli.CreateEnvFiles
+		arg3 boshcli.IAASEnvironment
 		arg4 string
 		arg5 string
 		arg6 string
-		arg7 map[string]string
+		arg7 string
+		arg8 map[string]string
 	}
 	createEnvReturns struct {
 		result1 *boshcli.CreateEnvFiles
@@ -28,13 +30,14 @@ type FakeICLI struct {
 		result1 *boshcli.CreateEnvFiles
 		result2 error
 	}
-	LocksStub        func(boshcli.IAASEnvironment, string, string, string) ([]byte, error)
+	LocksStub        func(context.Context, boshcli.IAASEnvironment, string, string, string) ([]byte, error)
 	locksMutex       sync.RWMutex
 	locksArgsForCall []struct {
-		arg1 boshcli.IAASEnvironment
-		arg2 string
+		arg1 context.Context
+		arg2 boshcli.IAASEnvironment
 		arg3 string
 		arg4 string
+		arg5 string
 	}
 	locksReturns struct {
 		result1 []byte
@@ -44,13 +47,14 @@ type FakeICLI struct {
 		result1 []byte
 		result2 error
 	}
-	RecreateStub        func(boshcli.IAASEnvironment, string, string, string) error
+	RecreateStub        func(context.Context, boshcli.IAASEnvironment, string, string, string) error
 	recreateMutex       sync.RWMutex
 	recreateArgsForCall []struct {
-		arg1 boshcli.IAASEnvironment
-		arg2 string
+		arg1 context.Context
+		arg2 boshcli.IAASEnvironment
 		arg3 string
 		arg4 string
+		arg5 string
 	}
 	recreateReturns struct {
 		result1 error
@@ -58,16 +62,17 @@ type FakeICLI struct {
 	recreateReturnsOnCall map[int]struct {
 		result1 error
 	}
-	RunAuthenticatedCommandStub        func(string, string, string, string, bool, io.Writer, ...string) error
+	RunAuthenticatedCommandStub        func(context.Context, string, string, string, string, bool, io.Writer, ...string) error
 	runAuthenticatedCommandMutex       sync.RWMutex
 	runAuthenticatedCommandArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
 		arg3 string
 		arg4 string
-		arg5 bool
-		arg6 io.Writer
-		arg7 []string
+		arg5 string
+		arg6 bool
+		arg7 io.Writer
+		arg8 []string
 	}
 	runAuthenticatedCommandReturns struct {
 		result1 error
@@ -75,13 +80,32 @@ type FakeICLI struct {
 	runAuthenticatedCommandReturnsOnCall map[int]struct {
 		result1 error
 	}
-	UpdateCloudConfigStub        func(boshcli.IAASEnvironment, string, string, string) error
+	RunInteractiveCommandStub        func(context.Context, string, string, string, string, io.Reader, io.Writer, ...string) error
+	runInteractiveCommandMutex       sync.RWMutex
+	runInteractiveCommandArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 string
+		arg6 io.Reader
+		arg7 io.Writer
+		arg8 []string
+	}
+	runInteractiveCommandReturns struct {
+		result1 error
+	}
+	runInteractiveCommandReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateCloudConfigStub        func(context.Context, boshcli.IAASEnvironment, string, string, string) error
 	updateCloudConfigMutex       sync.RWMutex
 	updateCloudConfigArgsForCall []struct {
-		arg1 boshcli.IAASEnvironment
-		arg2 string
+		arg1 context.Context
+		arg2 boshcli.IAASEnvironment
 		arg3 string
 		arg4 string
+		arg5 string
 	}
 	updateCloudConfigReturns struct {
 		result1 error
@@ -89,13 +113,14 @@ type FakeICLI struct {
 	updateCloudConfigReturnsOnCall map[int]struct {
 		result1 error
 	}
-	UploadConcourseStemcellStub        func(boshcli.IAASEnvironment, string, string, string) error
+	UploadConcourseStemcellStub        func(context.Context, boshcli.IAASEnvironment, string, string, string) error
 	uploadConcourseStemcellMutex       sync.RWMutex
 	uploadConcourseStemcellArgsForCall []struct {
-		arg1 boshcli.IAASEnvironment
-		arg2 string
+		arg1 context.Context
+		arg2 boshcli.IAASEnvironment
 		arg3 string
 		arg4 string
+		arg5 string
 	}
 	uploadConcourseStemcellReturns struct {
 		result1 error
@@ -107,22 +132,23 @@ type FakeICLI struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeICLI) CreateEnv(arg1 *boshcli.CreateEnvFiles, arg2 boshcli.IAASEnvironment, arg3 string, arg4 string, arg5 string, arg6 string, arg7 map[string]string) (*boshcli.CreateEnvFiles, error) {
+func (fake *FakeICLI) CreateEnv(arg1 context.Context, arg2 *boshcli.CreateEnvFiles, arg3 boshcli.IAASEnvironment, arg4 string, arg5 string, arg6 string, arg7 string, arg8 map[string]string) (*boshcli.CreateEnvFiles, error) {
 	fake.createEnvMutex.Lock()
 	ret, specificReturn := fake.createEnvReturnsOnCall[len(fake.createEnvArgsForCall)]
 	fake.createEnvArgsForCall = append(fake.createEnvArgsForCall, struct {
-		arg1 *boshcli.CreateEnvFiles
-		arg2 boshcli.IAASEnvironment
-		arg3 string
+		arg1 context.Context
+		arg2 *boshcli.CreateEnvFiles
+		arg3 boshcli.IAASEnvironment
 		arg4 string
 		arg5 string
 		arg6 string
-		arg7 map[string]string
-	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7})
-	fake.recordInvocation("CreateEnv", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7})
+		arg7 string
+		arg8 map[string]string
+	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8})
+	fake.recordInvocation("CreateEnv", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8})
 	fake.createEnvMutex.Unlock()
 	if fake.CreateEnvStub != nil {
-		return fake.CreateEnvStub(arg1, arg2, arg3, arg4, arg5, arg6, arg7)
+		return fake.CreateEnvStub(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -137,17 +163,17 @@ func (fake *FakeICLI) CreateEnvCallCount() int {
 	return len(fake.createEnvArgsForCall)
 }
 
-func (fake *FakeICLI) CreateEnvCalls(stub func(*boshcli.CreateEnvFiles, boshcli.IAASEnvironment, string, string, string, string, map[string]string) (*boshcli.CreateEnvFiles, error)) {
+func (fake *FakeICLI) CreateEnvCalls(stub func(context.Context, *boshcli.CreateEnvFiles, boshcli.IAASEnvironment, string, string, string, string, map[string]string) (*boshcli.CreateEnvFiles, error)) {
 	fake.createEnvMutex.Lock()
 	defer fake.createEnvMutex.Unlock()
 	fake.CreateEnvStub = stub
 }
 
-func (fake *FakeICLI) CreateEnvArgsForCall(i int) (*boshcli.CreateEnvFiles, boshcli.IAASEnvironment, string, string, string, string, map[string]string) {
+func (fake *FakeICLI) CreateEnvArgsForCall(i int) (context.Context, *boshcli.CreateEnvFiles, boshcli.IAASEnvironment, string, string, string, string, map[string]string) {
 	fake.createEnvMutex.RLock()
 	defer fake.createEnvMutex.RUnlock()
 	argsForCall := fake.createEnvArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7, argsForCall.arg8
 }
 
 func (fake *FakeICLI) CreateEnvReturns(result1 *boshcli.CreateEnvFiles, result2 error) {
@@ -176,19 +202,20 @@ func (fake *FakeICLI) CreateEnvReturnsOnCall(i int, result1 *boshcli.CreateEnvFi
 	}{result1, result2}
 }
 
-func (fake *FakeICLI) Locks(arg1 boshcli.IAASEnvironment, arg2 string, arg3 string, arg4 string) ([]byte, error) {
+func (fake *FakeICLI) Locks(arg1 context.Context, arg2 boshcli.IAASEnvironment, arg3 string, arg4 string, arg5 string) ([]byte, error) {
 	fake.locksMutex.Lock()
 	ret, specificReturn := fake.locksReturnsOnCall[len(fake.locksArgsForCall)]
 	fake.locksArgsForCall = append(fake.locksArgsForCall, struct {
-		arg1 boshcli.IAASEnvironment
-		arg2 string
+		arg1 context.Context
+		arg2 boshcli.IAASEnvironment
 		arg3 string
 		arg4 string
-	}{arg1, arg2, arg3, arg4})
-	fake.recordInvocation("Locks", []interface{}{arg1, arg2, arg3, arg4})
+		arg5 string
+	}{arg1, arg2, arg3, arg4, arg5})
+	fake.recordInvocation("Locks", []interface{}{arg1, arg2, arg3, arg4, arg5})
 	fake.locksMutex.Unlock()
 	if fake.LocksStub != nil {
-		return fake.LocksStub(arg1, arg2, arg3, arg4)
+		return fake.LocksStub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -203,17 +230,17 @@ func (fake *FakeICLI) LocksCallCount() int {
 	return len(fake.locksArgsForCall)
 }
 
-func (fake *FakeICLI) LocksCalls(stub func(boshcli.IAASEnvironment, string, string, string) ([]byte, error)) {
+func (fake *FakeICLI) LocksCalls(stub func(context.Context, boshcli.IAASEnvironment, string, string, string) ([]byte, error)) {
 	fake.locksMutex.Lock()
 	defer fake.locksMutex.Unlock()
 	fake.LocksStub = stub
 }
 
-func (fake *FakeICLI) LocksArgsForCall(i int) (boshcli.IAASEnvironment, string, string, string) {
+func (fake *FakeICLI) LocksArgsForCall(i int) (context.Context, boshcli.IAASEnvironment, string, string, string) {
 	fake.locksMutex.RLock()
 	defer fake.locksMutex.RUnlock()
 	argsForCall := fake.locksArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
 func (fake *FakeICLI) LocksReturns(result1 []byte, result2 error) {
@@ -242,19 +269,20 @@ func (fake *FakeICLI) LocksReturnsOnCall(i int, result1 []byte, result2 error) {
 	}{result1, result2}
 }
 
-func (fake *FakeICLI) Recreate(arg1 boshcli.IAASEnvironment, arg2 string, arg3 string, arg4 string) error {
+func (fake *FakeICLI) Recreate(arg1 context.Context, arg2 boshcli.IAASEnvironment, arg3 string, arg4 string, arg5 string) error {
 	fake.recreateMutex.Lock()
 	ret, specificReturn := fake.recreateReturnsOnCall[len(fake.recreateArgsForCall)]
 	fake.recreateArgsForCall = append(fake.recreateArgsForCall, struct {
-		arg1 boshcli.IAASEnvironment
-		arg2 string
+		arg1 context.Context
+		arg2 boshcli.IAASEnvironment
 		arg3 string
 		arg4 string
-	}{arg1, arg2, arg3, arg4})
-	fake.recordInvocation("Recreate", []interface{}{arg1, arg2, arg3, arg4})
+		arg5 string
+	}{arg1, arg2, arg3, arg4, arg5})
+	fake.recordInvocation("Recreate", []interface{}{arg1, arg2, arg3, arg4, arg5})
 	fake.recreateMutex.Unlock()
 	if fake.RecreateStub != nil {
-		return fake.RecreateStub(arg1, arg2, arg3, arg4)
+		return fake.RecreateStub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1
@@ -269,17 +297,17 @@ func (fake *FakeICLI) RecreateCallCount() int {
 	return len(fake.recreateArgsForCall)
 }
 
-func (fake *FakeICLI) RecreateCalls(stub func(boshcli.IAASEnvironment, string, string, string) error) {
+func (fake *FakeICLI) RecreateCalls(stub func(context.Context, boshcli.IAASEnvironment, string, string, string) error) {
 	fake.recreateMutex.Lock()
 	defer fake.recreateMutex.Unlock()
 	fake.RecreateStub = stub
 }
 
-func (fake *FakeICLI) RecreateArgsForCall(i int) (boshcli.IAASEnvironment, string, string, string) {
+func (fake *FakeICLI) RecreateArgsForCall(i int) (context.Context, boshcli.IAASEnvironment, string, string, string) {
 	fake.recreateMutex.RLock()
 	defer fake.recreateMutex.RUnlock()
 	argsForCall := fake.recreateArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
 func (fake *FakeICLI) RecreateReturns(result1 error) {
@@ -305,22 +333,23 @@ func (fake *FakeICLI) RecreateReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeICLI) RunAuthenticatedCommand(arg1 string, arg2 string, arg3 string, arg4 string, arg5 bool, arg6 io.Writer, arg7 ...string) error {
+func (fake *FakeICLI) RunAuthenticatedCommand(arg1 context.Context, arg2 string, arg3 string, arg4 string, arg5 string, arg6 bool, arg7 io.Writer, arg8 ...string) error {
 	fake.runAuthenticatedCommandMutex.Lock()
 	ret, specificReturn := fake.runAuthenticatedCommandReturnsOnCall[len(fake.runAuthenticatedCommandArgsForCall)]
 	fake.runAuthenticatedCommandArgsForCall = append(fake.runAuthenticatedCommandArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
 		arg3 string
 		arg4 string
-		arg5 bool
-		arg6 io.Writer
-		arg7 []string
-	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7})
-	fake.recordInvocation("RunAuthenticatedCommand", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7})
+		arg5 string
+		arg6 bool
+		arg7 io.Writer
+		arg8 []string
+	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8})
+	fake.recordInvocation("RunAuthenticatedCommand", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8})
 	fake.runAuthenticatedCommandMutex.Unlock()
 	if fake.RunAuthenticatedCommandStub != nil {
-		return fake.RunAuthenticatedCommandStub(arg1, arg2, arg3, arg4, arg5, arg6, arg7...)
+		return fake.RunAuthenticatedCommandStub(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8...)
 	}
 	if specificReturn {
 		return ret.result1
@@ -335,17 +364,17 @@ func (fake *FakeICLI) RunAuthenticatedCommandCallCount() int {
 	return len(fake.runAuthenticatedCommandArgsForCall)
 }
 
-func (fake *FakeICLI) RunAuthenticatedCommandCalls(stub func(string, string, string, string, bool, io.Writer, ...string) error) {
+func (fake *FakeICLI) RunAuthenticatedCommandCalls(stub func(context.Context, string, string, string, string, bool, io.Writer, ...string) error) {
 	fake.runAuthenticatedCommandMutex.Lock()
 	defer fake.runAuthenticatedCommandMutex.Unlock()
 	fake.RunAuthenticatedCommandStub = stub
 }
 
-func (fake *FakeICLI) RunAuthenticatedCommandArgsForCall(i int) (string, string, string, string, bool, io.Writer, []string) {
+func (fake *FakeICLI) RunAuthenticatedCommandArgsForCall(i int) (context.Context, string, string, string, string, bool, io.Writer, []string) {
 	fake.runAuthenticatedCommandMutex.RLock()
 	defer fake.runAuthenticatedCommandMutex.RUnlock()
 	argsForCall := fake.runAuthenticatedCommandArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7, argsForCall.arg8
 }
 
 func (fake *FakeICLI) RunAuthenticatedCommandReturns(result1 error) {
@@ -371,19 +400,87 @@ func (fake *FakeICLI) RunAuthenticatedCommandReturnsOnCall(i int, result1 error)
 	}{result1}
 }
 
-func (fake *FakeICLI) UpdateCloudConfig(arg1 boshcli.IAASEnvironment, arg2 string, arg3 string, arg4 string) error {
+func (fake *FakeICLI) RunInteractiveCommand(arg1 context.Context, arg2 string, arg3 string, arg4 string, arg5 string, arg6 io.Reader, arg7 io.Writer, arg8 ...string) error {
+	fake.runInteractiveCommandMutex.Lock()
+	ret, specificReturn := fake.runInteractiveCommandReturnsOnCall[len(fake.runInteractiveCommandArgsForCall)]
+	fake.runInteractiveCommandArgsForCall = append(fake.runInteractiveCommandArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 string
+		arg6 io.Reader
+		arg7 io.Writer
+		arg8 []string
+	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8})
+	fake.recordInvocation("RunInteractiveCommand", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8})
+	fake.runInteractiveCommandMutex.Unlock()
+	if fake.RunInteractiveCommandStub != nil {
+		return fake.RunInteractiveCommandStub(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8...)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.runInteractiveCommandReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeICLI) RunInteractiveCommandCallCount() int {
+	fake.runInteractiveCommandMutex.RLock()
+	defer fake.runInteractiveCommandMutex.RUnlock()
+	return len(fake.runInteractiveCommandArgsForCall)
+}
+
+func (fake *FakeICLI) RunInteractiveCommandCalls(stub func(context.Context, string, string, string, string, io.Reader, io.Writer, ...string) error) {
+	fake.runInteractiveCommandMutex.Lock()
+	defer fake.runInteractiveCommandMutex.Unlock()
+	fake.RunInteractiveCommandStub = stub
+}
+
+func (fake *FakeICLI) RunInteractiveCommandArgsForCall(i int) (context.Context, string, string, string, string, io.Reader, io.Writer, []string) {
+	fake.runInteractiveCommandMutex.RLock()
+	defer fake.runInteractiveCommandMutex.RUnlock()
+	argsForCall := fake.runInteractiveCommandArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7, argsForCall.arg8
+}
+
+func (fake *FakeICLI) RunInteractiveCommandReturns(result1 error) {
+	fake.runInteractiveCommandMutex.Lock()
+	defer fake.runInteractiveCommandMutex.Unlock()
+	fake.RunInteractiveCommandStub = nil
+	fake.runInteractiveCommandReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeICLI) RunInteractiveCommandReturnsOnCall(i int, result1 error) {
+	fake.runInteractiveCommandMutex.Lock()
+	defer fake.runInteractiveCommandMutex.Unlock()
+	fake.RunInteractiveCommandStub = nil
+	if fake.runInteractiveCommandReturnsOnCall == nil {
+		fake.runInteractiveCommandReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.runInteractiveCommandReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeICLI) UpdateCloudConfig(arg1 context.Context, arg2 boshcli.IAASEnvironment, arg3 string, arg4 string, arg5 string) error {
 	fake.updateCloudConfigMutex.Lock()
 	ret, specificReturn := fake.updateCloudConfigReturnsOnCall[len(fake.updateCloudConfigArgsForCall)]
 	fake.updateCloudConfigArgsForCall = append(fake.updateCloudConfigArgsForCall, struct {
-		arg1 boshcli.IAASEnvironment
-		arg2 string
+		arg1 context.Context
+		arg2 boshcli.IAASEnvironment
 		arg3 string
 		arg4 string
-	}{arg1, arg2, arg3, arg4})
-	fake.recordInvocation("UpdateCloudConfig", []interface{}{arg1, arg2, arg3, arg4})
+		arg5 string
+	}{arg1, arg2, arg3, arg4, arg5})
+	fake.recordInvocation("UpdateCloudConfig", []interface{}{arg1, arg2, arg3, arg4, arg5})
 	fake.updateCloudConfigMutex.Unlock()
 	if fake.UpdateCloudConfigStub != nil {
-		return fake.UpdateCloudConfigStub(arg1, arg2, arg3, arg4)
+		return fake.UpdateCloudConfigStub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1
@@ -398,17 +495,17 @@ func (fake *FakeICLI) UpdateCloudConfigCallCount() int {
 	return len(fake.updateCloudConfigArgsForCall)
 }
 
-func (fake *FakeICLI) UpdateCloudConfigCalls(stub func(boshcli.IAASEnvironment, string, string, string) error) {
+func (fake *FakeICLI) UpdateCloudConfigCalls(stub func(context.Context, boshcli.IAASEnvironment, string, string, string) error) {
 	fake.updateCloudConfigMutex.Lock()
 	defer fake.updateCloudConfigMutex.Unlock()
 	fake.UpdateCloudConfigStub = stub
 }
 
-func (fake *FakeICLI) UpdateCloudConfigArgsForCall(i int) (boshcli.IAASEnvironment, string, string, string) {
+func (fake *FakeICLI) UpdateCloudConfigArgsForCall(i int) (context.Context, boshcli.IAASEnvironment, string, string, string) {
 	fake.updateCloudConfigMutex.RLock()
 	defer fake.updateCloudConfigMutex.RUnlock()
 	argsForCall := fake.updateCloudConfigArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
 func (fake *FakeICLI) UpdateCloudConfigReturns(result1 error) {
@@ -434,19 +531,20 @@ func (fake *FakeICLI) UpdateCloudConfigReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeICLI) UploadConcourseStemcell(arg1 boshcli.IAASEnvironment, arg2 string, arg3 string, arg4 string) error {
+func (fake *FakeICLI) UploadConcourseStemcell(arg1 context.Context, arg2 boshcli.IAASEnvironment, arg3 string, arg4 string, arg5 string) error {
 	fake.uploadConcourseStemcellMutex.Lock()
 	ret, specificReturn := fake.uploadConcourseStemcellReturnsOnCall[len(fake.uploadConcourseStemcellArgsForCall)]
 	fake.uploadConcourseStemcellArgsForCall = append(fake.uploadConcourseStemcellArgsForCall, struct {
-		arg1 boshcli.IAASEnvironment
-		arg2 string
+		arg1 context.Context
+		arg2 boshcli.IAASEnvironment
 		arg3 string
 		arg4 string
-	}{arg1, arg2, arg3, arg4})
-	fake.recordInvocation("UploadConcourseStemcell", []interface{}{arg1, arg2, arg3, arg4})
+		arg5 string
+	}{arg1, arg2, arg3, arg4, arg5})
+	fake.recordInvocation("UploadConcourseStemcell", []interface{}{arg1, arg2, arg3, arg4, arg5})
 	fake.uploadConcourseStemcellMutex.Unlock()
 	if fake.UploadConcourseStemcellStub != nil {
-		return fake.UploadConcourseStemcellStub(arg1, arg2, arg3, arg4)
+		return fake.UploadConcourseStemcellStub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1
@@ -461,17 +559,17 @@ func (fake *FakeICLI) UploadConcourseStemcellCallCount() int {
 	return len(fake.uploadConcourseStemcellArgsForCall)
 }
 
-func (fake *FakeICLI) UploadConcourseStemcellCalls(stub func(boshcli.IAASEnvironment, string, string, string) error) {
+func (fake *FakeICLI) UploadConcourseStemcellCalls(stub func(context.Context, boshcli.IAASEnvironment, string, string, string) error) {
 	fake.uploadConcourseStemcellMutex.Lock()
 	defer fake.uploadConcourseStemcellMutex.Unlock()
 	fake.UploadConcourseStemcellStub = stub
 }
 
-func (fake *FakeICLI) UploadConcourseStemcellArgsForCall(i int) (boshcli.IAASEnvironment, string, string, string) {
+func (fake *FakeICLI) UploadConcourseStemcellArgsForCall(i int) (context.Context, boshcli.IAASEnvironment, string, string, string) {
 	fake.uploadConcourseStemcellMutex.RLock()
 	defer fake.uploadConcourseStemcellMutex.RUnlock()
 	argsForCall := fake.uploadConcourseStemcellArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
 func (fake *FakeICLI) UploadConcourseStemcellReturns(result1 error) {
@@ -508,6 +606,8 @@ func (fake *FakeICLI) Invocations() map[string][][]interface{} {
 	defer fake.recreateMutex.RUnlock()
 	fake.runAuthenticatedCommandMutex.RLock()
 	defer fake.runAuthenticatedCommandMutex.RUnlock()
+	fake.runInteractiveCommandMutex.RLock()
+	defer fake.runInteractiveCommandMutex.RUnlock()
 	fake.updateCloudConfigMutex.RLock()
 	defer fake.updateCloudConfigMutex.RUnlock()
 	fake.uploadConcourseStemcellMutex.RLock()
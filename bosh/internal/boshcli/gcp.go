@@ -3,6 +3,7 @@ package boshcli
 import (
 	"io/ioutil"
 
+	"github.com/EngineerBetter/control-tower/config"
 	"github.com/EngineerBetter/control-tower/resource"
 	"github.com/EngineerBetter/control-tower/util"
 	"github.com/EngineerBetter/control-tower/util/yaml"
@@ -32,6 +33,7 @@ type GCPEnvironment struct {
 	Spot                bool
 	Tags                string
 	VersionFile         []byte
+	WorkerPools         []config.WorkerPool
 	Zone                string
 }
 
@@ -116,7 +118,16 @@ func (e GCPEnvironment) ConfigureDirectorCloudConfig() (string, error) {
 	if cc == nil {
 		return "", err
 	}
-	return string(cc), err
+	if err != nil {
+		return string(cc), err
+	}
+
+	return addWorkerPoolVMTypes(string(cc), e.WorkerPools, func(spot bool) (string, error) {
+		poolParams := templateParams
+		poolParams.Spot = spot
+		poolCC, err := util.RenderTemplate("cloud-config", resource.GCPDirectorCloudConfig, poolParams)
+		return string(poolCC), err
+	})
 }
 
 func (e GCPEnvironment) ConcourseStemcellURL() (string, error) {
@@ -0,0 +1,76 @@
+package boshcli
+
+import (
+	"fmt"
+
+	"github.com/EngineerBetter/control-tower/config"
+	"github.com/EngineerBetter/control-tower/util/yaml"
+	yamlenc "gopkg.in/yaml.v2"
+)
+
+// WorkerPoolVMType is the name of the cloud-config VM type used by a worker pool
+func WorkerPoolVMType(poolName string) string {
+	return "concourse-pool-" + poolName
+}
+
+type cloudConfigVMTypes struct {
+	VMTypes []struct {
+		Name            string                 `yaml:"name"`
+		CloudProperties map[string]interface{} `yaml:"cloud_properties"`
+	} `yaml:"vm_types"`
+}
+
+// addWorkerPoolVMTypes adds a VM type for each worker pool to cloudConfig. As spot and
+// preemptible settings apply to every worker VM type in the template, render is called
+// with each setting and the pool's VM type is copied from the matching output
+func addWorkerPoolVMTypes(cloudConfig string, pools []config.WorkerPool, render func(spot bool) (string, error)) (string, error) {
+	if len(pools) == 0 {
+		return cloudConfig, nil
+	}
+
+	rendered := map[bool]cloudConfigVMTypes{}
+	for _, spot := range []bool{true, false} {
+		cc, err := render(spot)
+		if err != nil {
+			return "", err
+		}
+		var vmTypes cloudConfigVMTypes
+		if err = yamlenc.Unmarshal([]byte(cc), &vmTypes); err != nil {
+			return "", fmt.Errorf("failed to parse cloud config: [%v]", err)
+		}
+		rendered[spot] = vmTypes
+	}
+
+	var ops []map[string]interface{}
+	for _, pool := range pools {
+		cloudProperties, err := workerCloudProperties(rendered[pool.Spot], pool.Size)
+		if err != nil {
+			return "", fmt.Errorf("failed to add VM type for worker pool %s: [%v]", pool.Name, err)
+		}
+		ops = append(ops, map[string]interface{}{
+			"type": "replace",
+			"path": "/vm_types/-",
+			"value": map[string]interface{}{
+				"name":             WorkerPoolVMType(pool.Name),
+				"cloud_properties": cloudProperties,
+			},
+		})
+	}
+
+	opsBytes, err := yamlenc.Marshal(ops)
+	if err != nil {
+		return "", err
+	}
+
+	return yaml.Interpolate(cloudConfig, string(opsBytes), nil)
+}
+
+func workerCloudProperties(vmTypes cloudConfigVMTypes, size string) (map[string]interface{}, error) {
+	name := "concourse-" + size
+	for _, vmType := range vmTypes.VMTypes {
+		if vmType.Name == name {
+			return vmType.CloudProperties, nil
+		}
+	}
+	return nil, fmt.Errorf("VM type %s not found", name)
+}
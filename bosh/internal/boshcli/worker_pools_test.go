@@ -0,0 +1,64 @@
+package boshcli
+
+import (
+	"testing"
+
+	"github.com/EngineerBetter/control-tower/config"
+	yamlenc "gopkg.in/yaml.v2"
+)
+
+func TestAWSEnvironment_ConfigureDirectorCloudConfigWithWorkerPools(t *testing.T) {
+	env := AWSEnvironment{
+		Spot:       true,
+		WorkerType: "m4",
+		WorkerPools: []config.WorkerPool{
+			{Name: "gpu", Count: 1, Size: "xlarge", Spot: false, Tags: []string{"gpu"}},
+			{Name: "cheap", Count: 2, Size: "large", Spot: true, Tags: []string{"cheap"}},
+		},
+	}
+
+	cc, err := env.ConfigureDirectorCloudConfig()
+	if err != nil {
+		t.Fatalf("ConfigureDirectorCloudConfig() error = %v", err)
+	}
+
+	var vmTypes cloudConfigVMTypes
+	if err = yamlenc.Unmarshal([]byte(cc), &vmTypes); err != nil {
+		t.Fatalf("failed to parse cloud config: %v", err)
+	}
+
+	tests := []struct {
+		vmType       string
+		instanceType string
+		spot         bool
+	}{
+		{"concourse-xlarge", "m4.xlarge", true},
+		{"concourse-pool-gpu", "m4.xlarge", false},
+		{"concourse-pool-cheap", "m4.large", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.vmType, func(t *testing.T) {
+			cloudProperties, err := workerCloudProperties(vmTypes, tt.vmType[len("concourse-"):])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cloudProperties["instance_type"] != tt.instanceType {
+				t.Errorf("instance_type = %v, want %v", cloudProperties["instance_type"], tt.instanceType)
+			}
+			if _, spot := cloudProperties["spot_bid_price"]; spot != tt.spot {
+				t.Errorf("spot = %v, want %v", spot, tt.spot)
+			}
+		})
+	}
+}
+
+func TestGCPEnvironment_ConfigureDirectorCloudConfigWithUnknownPoolSize(t *testing.T) {
+	env := GCPEnvironment{
+		WorkerPools: []config.WorkerPool{{Name: "huge", Count: 1, Size: "48xlarge", Tags: []string{"huge"}}},
+	}
+
+	_, err := env.ConfigureDirectorCloudConfig()
+	if err == nil {
+		t.Fatal("expected an error for an unknown worker size")
+	}
+}
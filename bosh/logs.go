@@ -0,0 +1,123 @@
+package bosh
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/EngineerBetter/control-tower/bosh/internal/boshcli"
+	"github.com/EngineerBetter/control-tower/bosh/internal/workingdir"
+)
+
+const directorLogDir = "/var/vcap/sys/log"
+
+// directorJobPattern matches the names BOSH allows for jobs. The job of the director's logs is
+// interpolated into a remote shell command, so nothing else is accepted
+var directorJobPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Logs downloads the logs of target, which is either an instance group or instance of the concourse
+// deployment or the director, into dir. An empty target fetches logs for the whole deployment and
+// an empty job fetches logs for every job. If follow is set the logs are streamed to stdout instead
+func (client *AWSClient) Logs(ctx context.Context, target, job string, follow bool, dir string) error {
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve director IP: [%v]", err)
+	}
+
+	return fetchLogs(ctx, client.boshCLI, client.workingdir, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert(), client.config.GetPrivateKey(), GatewayUser(client.provider), target, job, follow, dir, client.stdout)
+}
+
+// Logs downloads the logs of target, which is either an instance group or instance of the concourse
+// deployment or the director, into dir. An empty target fetches logs for the whole deployment and
+// an empty job fetches logs for every job. If follow is set the logs are streamed to stdout instead
+func (client *GCPClient) Logs(ctx context.Context, target, job string, follow bool, dir string) error {
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve director IP: [%v]", err)
+	}
+
+	return fetchLogs(ctx, client.boshCLI, client.workingdir, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert(), client.config.GetPrivateKey(), GatewayUser(client.provider), target, job, follow, dir, client.stdout)
+}
+
+// Logs downloads the logs of target, which is either an instance group or instance of the concourse
+// deployment or the director, into dir. An empty target fetches logs for the whole deployment and
+// an empty job fetches logs for every job. If follow is set the logs are streamed to stdout instead
+func (client *AzureClient) Logs(ctx context.Context, target, job string, follow bool, dir string) error {
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve director IP: [%v]", err)
+	}
+
+	return fetchLogs(ctx, client.boshCLI, client.workingdir, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert(), client.config.GetPrivateKey(), GatewayUser(client.provider), target, job, follow, dir, client.stdout)
+}
+
+func fetchLogs(ctx context.Context, boshCLI boshcli.ICLI, workingdir workingdir.IClient, ip, password, ca, privateKey, gatewayUser, target, job string, follow bool, dir string, stdout io.Writer) error {
+	if target == DirectorSSHTarget {
+		return fetchDirectorLogs(ctx, workingdir, ip, privateKey, gatewayUser, job, follow, dir, stdout)
+	}
+
+	var flags []string
+	if target != "" {
+		flags = append(flags, target)
+	}
+	if job != "" {
+		flags = append(flags, "--job", job)
+	}
+
+	if !follow {
+		return boshCLI.RunAuthenticatedCommand(ctx, "logs", ip, password, ca, false, stdout, append(flags, "--dir", dir)...)
+	}
+
+	keyPath, err := saveJumpboxKey(workingdir, privateKey)
+	if err != nil {
+		return err
+	}
+	flags = append(flags, "--follow", "--gw-host", ip, "--gw-user", gatewayUser, "--gw-private-key", keyPath)
+	return boshCLI.RunAuthenticatedCommand(ctx, "logs", ip, password, ca, false, stdout, flags...)
+}
+
+// fetchDirectorLogs reads the director's logs over the gateway SSH connection,
+// as the director is not part of a deployment that bosh logs can reach
+func fetchDirectorLogs(ctx context.Context, workingdir workingdir.IClient, ip, privateKey, gatewayUser, job string, follow bool, dir string, stdout io.Writer) error {
+	if job != "" && !directorJobPattern.MatchString(job) {
+		return fmt.Errorf("invalid job name %q, job names can only contain letters, numbers, underscores and hyphens", job)
+	}
+
+	keyPath, err := saveJumpboxKey(workingdir, privateKey)
+	if err != nil {
+		return err
+	}
+
+	if follow {
+		logs := path.Join(directorLogDir, "*", "*.log")
+		if job != "" {
+			logs = path.Join(directorLogDir, job, "*.log")
+		}
+		return directorSSH(ctx, keyPath, gatewayUser, ip, fmt.Sprintf("sudo bash -c 'tail -n 20 -F %s'", logs), nil, stdout)
+	}
+
+	logs := "."
+	if job != "" {
+		logs = job
+	}
+	tarballPath := filepath.Join(dir, fmt.Sprintf("director-%s.tgz", time.Now().UTC().Format("20060102-150405")))
+	tarball, err := os.Create(tarballPath)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: [%v]", tarballPath, err)
+	}
+	defer tarball.Close()
+
+	err = directorSSH(ctx, keyPath, gatewayUser, ip, fmt.Sprintf("sudo tar -czf - -C %s %s", directorLogDir, logs), nil, tarball)
+	if err != nil {
+		os.Remove(tarballPath)
+		return fmt.Errorf("failed to download director logs: [%v]", err)
+	}
+
+	_, err = fmt.Fprintf(stdout, "Downloaded director logs to %s\n", tarballPath)
+	return err
+}
@@ -0,0 +1,105 @@
+package bosh
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/EngineerBetter/control-tower/bosh/internal/boshcli/boshclifakes"
+	"github.com/EngineerBetter/control-tower/bosh/internal/workingdir/workingdirfakes"
+	"github.com/EngineerBetter/control-tower/internal/fakeexec"
+	"github.com/EngineerBetter/control-tower/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchLogs(t *testing.T) {
+	boshCLI := &boshclifakes.FakeICLI{}
+	workingdir := &workingdirfakes.FakeIClient{}
+
+	err := fetchLogs(context.Background(), boshCLI, workingdir, "1.2.3.4", "password", "ca", "key", "vcap", "web", "atc", false, "/tmp/logs", ioutil.Discard)
+	require.NoError(t, err)
+
+	_, action, ip, password, ca, detach, _, flags := boshCLI.RunAuthenticatedCommandArgsForCall(0)
+	require.Equal(t, "logs", action)
+	require.Equal(t, "1.2.3.4", ip)
+	require.Equal(t, "password", password)
+	require.Equal(t, "ca", ca)
+	require.False(t, detach)
+	require.Equal(t, []string{"web", "--job", "atc", "--dir", "/tmp/logs"}, flags)
+	require.Equal(t, 0, workingdir.SaveFileToWorkingDirCallCount())
+}
+
+func TestFetchLogsFollow(t *testing.T) {
+	boshCLI := &boshclifakes.FakeICLI{}
+	workingdir := &workingdirfakes.FakeIClient{}
+	workingdir.SaveFileToWorkingDirReturns("/tmp/jumpbox.key", nil)
+
+	err := fetchLogs(context.Background(), boshCLI, workingdir, "1.2.3.4", "password", "ca", "key", "jumpbox", "", "", true, ".", ioutil.Discard)
+	require.NoError(t, err)
+
+	_, _, _, _, _, _, _, flags := boshCLI.RunAuthenticatedCommandArgsForCall(0)
+	require.Equal(t, []string{"--follow", "--gw-host", "1.2.3.4", "--gw-user", "jumpbox", "--gw-private-key", "/tmp/jumpbox.key"}, flags)
+}
+
+func TestFetchDirectorLogs(t *testing.T) {
+	e := fakeexec.New(t)
+	defer e.Finish()
+	execCommand = e.CmdContext()
+	defer func() { execCommand = util.CommandContext }()
+
+	e.Expect("ssh", "-i", "/tmp/jumpbox.key", "-o", "StrictHostKeyChecking=no", "-o", "UserKnownHostsFile=/dev/null", "vcap@1.2.3.4", "sudo tar -czf - -C /var/vcap/sys/log director").Outputs("tarball")
+
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	boshCLI := &boshclifakes.FakeICLI{}
+	workingdir := &workingdirfakes.FakeIClient{}
+	workingdir.SaveFileToWorkingDirReturns("/tmp/jumpbox.key", nil)
+
+	err = fetchLogs(context.Background(), boshCLI, workingdir, "1.2.3.4", "password", "ca", "key", "vcap", DirectorSSHTarget, "director", false, dir, ioutil.Discard)
+	require.NoError(t, err)
+	require.Equal(t, 0, boshCLI.RunAuthenticatedCommandCallCount())
+
+	tarballs, err := filepath.Glob(filepath.Join(dir, "director-*.tgz"))
+	require.NoError(t, err)
+	require.Len(t, tarballs, 1)
+	contents, err := ioutil.ReadFile(tarballs[0])
+	require.NoError(t, err)
+	require.Equal(t, "tarball", string(contents))
+}
+
+func TestFetchDirectorLogsFollow(t *testing.T) {
+	e := fakeexec.New(t)
+	defer e.Finish()
+	execCommand = e.CmdContext()
+	defer func() { execCommand = util.CommandContext }()
+
+	e.Expect("ssh", "-i", "/tmp/jumpbox.key", "-o", "StrictHostKeyChecking=no", "-o", "UserKnownHostsFile=/dev/null", "vcap@1.2.3.4", "sudo bash -c 'tail -n 20 -F /var/vcap/sys/log/*/*.log'")
+
+	workingdir := &workingdirfakes.FakeIClient{}
+	workingdir.SaveFileToWorkingDirReturns("/tmp/jumpbox.key", nil)
+
+	err := fetchLogs(context.Background(), &boshclifakes.FakeICLI{}, workingdir, "1.2.3.4", "password", "ca", "key", "vcap", DirectorSSHTarget, "", true, ".", ioutil.Discard)
+	require.NoError(t, err)
+}
+
+func TestFetchDirectorLogsInvalidJob(t *testing.T) {
+	e := fakeexec.New(t)
+	defer e.Finish()
+	execCommand = e.CmdContext()
+	defer func() { execCommand = util.CommandContext }()
+
+	workingdir := &workingdirfakes.FakeIClient{}
+
+	for _, job := range []string{"director; rm -rf /", "../..", "$(id)", "director'"} {
+		for _, follow := range []bool{false, true} {
+			err := fetchLogs(context.Background(), &boshclifakes.FakeICLI{}, workingdir, "1.2.3.4", "password", "ca", "key", "vcap", DirectorSSHTarget, job, follow, ".", ioutil.Discard)
+			require.EqualError(t, err, fmt.Sprintf("invalid job name %q, job names can only contain letters, numbers, underscores and hyphens", job))
+		}
+	}
+	require.Equal(t, 0, workingdir.SaveFileToWorkingDirCallCount())
+}
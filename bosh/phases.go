@@ -0,0 +1,25 @@
+package bosh
+
+// Phases of Deploy, in the order they run
+const (
+	CreateEnvPhase   = "create-env"
+	CloudConfigPhase = "cloud-config"
+	StemcellPhase    = "stemcell"
+	DatabasesPhase   = "databases"
+	ConcoursePhase   = "concourse"
+)
+
+// Checkpoint runs the phases of a deploy, so that a deploy which failed can skip the phases that
+// already completed
+type Checkpoint interface {
+	// RunPhase runs phase unless it has already completed, and records it as complete if it succeeds
+	RunPhase(phase string, run func() error) error
+}
+
+// runPhase runs phase through checkpoint, or unconditionally when checkpoint is nil
+func runPhase(checkpoint Checkpoint, phase string, run func() error) error {
+	if checkpoint == nil {
+		return run()
+	}
+	return checkpoint.RunPhase(phase, run)
+}
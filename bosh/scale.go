@@ -0,0 +1,259 @@
+package bosh
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/EngineerBetter/control-tower/bosh/internal/boshcli"
+	"github.com/EngineerBetter/control-tower/bosh/internal/workingdir"
+)
+
+const workerInstanceGroup = "worker"
+
+const jumpboxKeyFilename = "jumpbox.key"
+
+// retireWorkerCommand retires the worker using the same TSA settings as the worker job,
+// blocking new builds from being scheduled on it while letting running builds finish
+const retireWorkerCommand = "sudo bash -c 'source /var/vcap/jobs/worker/config/env.sh && /var/vcap/packages/concourse/bin/concourse retire-worker'"
+
+// landWorkerCommand lands the worker so that no new builds are scheduled on it. Unlike a
+// retired worker, a landing worker stays registered until its running builds have finished
+const landWorkerCommand = "sudo bash -c 'source /var/vcap/jobs/worker/config/env.sh && /var/vcap/packages/concourse/bin/concourse land-worker'"
+
+// unlandWorkerCommand restarts the worker, which registers a landed worker as running again
+const unlandWorkerCommand = "sudo /var/vcap/bosh/bin/monit restart worker"
+
+// ScaleWorkers deploys concourse with the new worker count and size. Workers that will be removed
+// should first be retired with RetireWorkers
+func (client *AWSClient) ScaleWorkers(ctx context.Context, creds []byte) ([]byte, error) {
+	return client.deployConcourse(ctx, creds, false)
+}
+
+// ScaleWorkers deploys concourse with the new worker count and size. Workers that will be removed
+// should first be retired with RetireWorkers
+func (client *GCPClient) ScaleWorkers(ctx context.Context, creds []byte) ([]byte, error) {
+	return client.deployConcourse(ctx, creds, false)
+}
+
+// ScaleWorkers deploys concourse with the new worker count and size. Workers that will be removed
+// should first be retired with RetireWorkers
+func (client *AzureClient) ScaleWorkers(ctx context.Context, creds []byte) ([]byte, error) {
+	return client.deployConcourse(ctx, creds, false)
+}
+
+// RetireWorkers retires the workers that would be removed by scaling pool, or the default workers
+// when pool is empty, down to workerCount
+func (client *AWSClient) RetireWorkers(ctx context.Context, pool string, workerCount int) error {
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve director IP: [%v]", err)
+	}
+
+	return retireWorkers(ctx, client.boshCLI, client.workingdir, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert(), client.config.GetPrivateKey(), GatewayUser(client.provider), client.stdout, poolInstanceGroup(pool), workerCount)
+}
+
+// LandWorkers lands the workers that would be removed by scaling down to workerCount
+func (client *AWSClient) LandWorkers(ctx context.Context, workerCount int) error {
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve director IP: [%v]", err)
+	}
+
+	return landWorkers(ctx, client.boshCLI, client.workingdir, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert(), client.config.GetPrivateKey(), GatewayUser(client.provider), client.stdout, workerCount)
+}
+
+// UnlandWorkers restarts the workers landed by LandWorkers so that they take builds again
+func (client *AWSClient) UnlandWorkers(ctx context.Context, workerCount int) error {
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve director IP: [%v]", err)
+	}
+
+	return unlandWorkers(ctx, client.boshCLI, client.workingdir, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert(), client.config.GetPrivateKey(), GatewayUser(client.provider), client.stdout, workerCount)
+}
+
+// RetireWorkers retires the workers that would be removed by scaling pool, or the default workers
+// when pool is empty, down to workerCount
+func (client *GCPClient) RetireWorkers(ctx context.Context, pool string, workerCount int) error {
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve director IP: [%v]", err)
+	}
+
+	return retireWorkers(ctx, client.boshCLI, client.workingdir, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert(), client.config.GetPrivateKey(), GatewayUser(client.provider), client.stdout, poolInstanceGroup(pool), workerCount)
+}
+
+// LandWorkers lands the workers that would be removed by scaling down to workerCount
+func (client *GCPClient) LandWorkers(ctx context.Context, workerCount int) error {
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve director IP: [%v]", err)
+	}
+
+	return landWorkers(ctx, client.boshCLI, client.workingdir, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert(), client.config.GetPrivateKey(), GatewayUser(client.provider), client.stdout, workerCount)
+}
+
+// UnlandWorkers restarts the workers landed by LandWorkers so that they take builds again
+func (client *GCPClient) UnlandWorkers(ctx context.Context, workerCount int) error {
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve director IP: [%v]", err)
+	}
+
+	return unlandWorkers(ctx, client.boshCLI, client.workingdir, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert(), client.config.GetPrivateKey(), GatewayUser(client.provider), client.stdout, workerCount)
+}
+
+// RetireWorkers retires the workers that would be removed by scaling pool, or the default workers
+// when pool is empty, down to workerCount
+func (client *AzureClient) RetireWorkers(ctx context.Context, pool string, workerCount int) error {
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve director IP: [%v]", err)
+	}
+
+	return retireWorkers(ctx, client.boshCLI, client.workingdir, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert(), client.config.GetPrivateKey(), GatewayUser(client.provider), client.stdout, poolInstanceGroup(pool), workerCount)
+}
+
+// LandWorkers lands the workers that would be removed by scaling down to workerCount
+func (client *AzureClient) LandWorkers(ctx context.Context, workerCount int) error {
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve director IP: [%v]", err)
+	}
+
+	return landWorkers(ctx, client.boshCLI, client.workingdir, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert(), client.config.GetPrivateKey(), GatewayUser(client.provider), client.stdout, workerCount)
+}
+
+// UnlandWorkers restarts the workers landed by LandWorkers so that they take builds again
+func (client *AzureClient) UnlandWorkers(ctx context.Context, workerCount int) error {
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve director IP: [%v]", err)
+	}
+
+	return unlandWorkers(ctx, client.boshCLI, client.workingdir, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert(), client.config.GetPrivateKey(), GatewayUser(client.provider), client.stdout, workerCount)
+}
+
+// poolInstanceGroup returns the instance group of pool, or of the default workers when pool is empty
+func poolInstanceGroup(pool string) string {
+	if pool == "" {
+		return workerInstanceGroup
+	}
+	return WorkerPoolInstanceGroup(pool)
+}
+
+// retireWorkers retires the workers of instanceGroup BOSH will delete when scaling down to workerCount,
+// which are the ones with the highest indexes
+func retireWorkers(ctx context.Context, boshCLI boshcli.ICLI, workingdir workingdir.IClient, ip, password, ca, privateKey, gatewayUser string, stdout io.Writer, instanceGroup string, workerCount int) error {
+	return runOnRemovedWorkers(ctx, boshCLI, workingdir, ip, password, ca, privateKey, gatewayUser, stdout, instanceGroup, workerCount, "Retiring", retireWorkerCommand)
+}
+
+// landWorkers lands the workers BOSH will delete when scaling down to workerCount
+func landWorkers(ctx context.Context, boshCLI boshcli.ICLI, workingdir workingdir.IClient, ip, password, ca, privateKey, gatewayUser string, stdout io.Writer, workerCount int) error {
+	return runOnRemovedWorkers(ctx, boshCLI, workingdir, ip, password, ca, privateKey, gatewayUser, stdout, workerInstanceGroup, workerCount, "Landing", landWorkerCommand)
+}
+
+// unlandWorkers restarts the workers landed by landWorkers
+func unlandWorkers(ctx context.Context, boshCLI boshcli.ICLI, workingdir workingdir.IClient, ip, password, ca, privateKey, gatewayUser string, stdout io.Writer, workerCount int) error {
+	return runOnRemovedWorkers(ctx, boshCLI, workingdir, ip, password, ca, privateKey, gatewayUser, stdout, workerInstanceGroup, workerCount, "Unlanding", unlandWorkerCommand)
+}
+
+func runOnRemovedWorkers(ctx context.Context, boshCLI boshcli.ICLI, workingdir workingdir.IClient, ip, password, ca, privateKey, gatewayUser string, stdout io.Writer, instanceGroup string, workerCount int, action, command string) error {
+	workers, err := workerIndexes(ctx, boshCLI, ip, password, ca, instanceGroup)
+	if err != nil {
+		return err
+	}
+
+	var retiring []string
+	for instance, index := range workers {
+		if index >= workerCount {
+			retiring = append(retiring, instance)
+		}
+	}
+	if len(retiring) == 0 {
+		return nil
+	}
+	sort.Strings(retiring)
+
+	keyPath, err := saveJumpboxKey(workingdir, privateKey)
+	if err != nil {
+		return err
+	}
+
+	for _, instance := range retiring {
+		fmt.Fprintf(stdout, "%s %s\n", action, instance)
+		err = boshCLI.RunAuthenticatedCommand(
+			ctx,
+			"ssh",
+			ip,
+			password,
+			ca,
+			false,
+			stdout,
+			instance,
+			"--command", command,
+			"--gw-host", ip,
+			"--gw-user", gatewayUser,
+			"--gw-private-key", keyPath,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to run `%s` on %s: [%v]", command, instance, err)
+		}
+	}
+
+	return nil
+}
+
+// workerIndexes returns the index of each instance of instanceGroup keyed by instance name
+func workerIndexes(ctx context.Context, boshCLI boshcli.ICLI, ip, password, ca, instanceGroup string) (map[string]int, error) {
+	output := new(bytes.Buffer)
+
+	if err := boshCLI.RunAuthenticatedCommand(
+		ctx,
+		"instances",
+		ip,
+		password,
+		ca,
+		false,
+		output,
+		"--details",
+		"--json",
+	); err != nil {
+		return nil, fmt.Errorf("Error [%s] running `bosh instances`. stdout: [%s]", err, output.String())
+	}
+
+	jsonOutput := struct {
+		Tables []struct {
+			Rows []struct {
+				Instance string `json:"instance"`
+				Index    string `json:"index"`
+			} `json:"Rows"`
+		} `json:"Tables"`
+	}{}
+
+	if err := json.NewDecoder(output).Decode(&jsonOutput); err != nil {
+		return nil, err
+	}
+
+	workers := map[string]int{}
+	for _, table := range jsonOutput.Tables {
+		for _, row := range table.Rows {
+			if !strings.HasPrefix(row.Instance, instanceGroup+"/") {
+				continue
+			}
+			index, err := strconv.Atoi(row.Index)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse index of %s: [%v]", row.Instance, err)
+			}
+			workers[row.Instance] = index
+		}
+	}
+
+	return workers, nil
+}
@@ -0,0 +1,79 @@
+package bosh
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/EngineerBetter/control-tower/bosh/internal/boshcli/boshclifakes"
+	"github.com/EngineerBetter/control-tower/bosh/internal/workingdir/workingdirfakes"
+	"github.com/stretchr/testify/require"
+)
+
+const instancesOutput = `{"Tables":[{"Rows":[
+{"instance":"web/aaa","index":"0"},
+{"instance":"worker/bbb","index":"0"},
+{"instance":"worker/ccc","index":"1"},
+{"instance":"worker/ddd","index":"2"}
+]}]}`
+
+func TestRetireWorkers(t *testing.T) {
+	boshCLI := &boshclifakes.FakeICLI{}
+	var retired []string
+	boshCLI.RunAuthenticatedCommandStub = func(ctx context.Context, action, ip, password, ca string, detach bool, stdout io.Writer, flags ...string) error {
+		switch action {
+		case "instances":
+			_, err := stdout.Write([]byte(instancesOutput))
+			return err
+		case "ssh":
+			retired = append(retired, flags[0])
+		}
+		return nil
+	}
+	workingdir := &workingdirfakes.FakeIClient{}
+	workingdir.SaveFileToWorkingDirReturns("/tmp/jumpbox.key", nil)
+
+	err := retireWorkers(context.Background(), boshCLI, workingdir, "1.2.3.4", "password", "ca", "key", "vcap", ioutil.Discard, "worker", 1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"worker/ccc", "worker/ddd"}, retired)
+
+	_, _, _, _, _, _, _, flags := boshCLI.RunAuthenticatedCommandArgsForCall(1)
+	require.Contains(t, flags, "/tmp/jumpbox.key")
+	require.Contains(t, flags, "vcap")
+	require.NotContains(t, flags, "jumpbox")
+}
+
+func TestRetireWorkersScalingUp(t *testing.T) {
+	boshCLI := &boshclifakes.FakeICLI{}
+	boshCLI.RunAuthenticatedCommandStub = func(ctx context.Context, action, ip, password, ca string, detach bool, stdout io.Writer, flags ...string) error {
+		_, err := stdout.Write([]byte(instancesOutput))
+		return err
+	}
+	workingdir := &workingdirfakes.FakeIClient{}
+
+	err := retireWorkers(context.Background(), boshCLI, workingdir, "1.2.3.4", "password", "ca", "key", "vcap", ioutil.Discard, "worker", 5)
+	require.NoError(t, err)
+	require.Equal(t, 1, boshCLI.RunAuthenticatedCommandCallCount())
+	require.Equal(t, 0, workingdir.SaveFileToWorkingDirCallCount())
+}
+
+func TestLandWorkers(t *testing.T) {
+	boshCLI := &boshclifakes.FakeICLI{}
+	var commands []string
+	boshCLI.RunAuthenticatedCommandStub = func(ctx context.Context, action, ip, password, ca string, detach bool, stdout io.Writer, flags ...string) error {
+		switch action {
+		case "instances":
+			_, err := stdout.Write([]byte(instancesOutput))
+			return err
+		case "ssh":
+			commands = append(commands, flags[0]+" "+flags[2])
+		}
+		return nil
+	}
+	workingdir := &workingdirfakes.FakeIClient{}
+
+	err := landWorkers(context.Background(), boshCLI, workingdir, "1.2.3.4", "password", "ca", "key", "vcap", ioutil.Discard, 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"worker/ddd " + landWorkerCommand}, commands)
+}
@@ -0,0 +1,98 @@
+package bosh
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/EngineerBetter/control-tower/bosh/internal/boshcli"
+	"github.com/EngineerBetter/control-tower/bosh/internal/workingdir"
+	"github.com/EngineerBetter/control-tower/iaas"
+	"github.com/EngineerBetter/control-tower/util"
+)
+
+// DirectorSSHTarget is the SSH target for the director VM, which is also the gateway to the concourse VMs
+const DirectorSSHTarget = "director"
+
+// GatewayUser returns the user that is allowed to SSH onto the director
+func GatewayUser(provider iaas.Provider) string {
+	gatewayUser, _ := provider.Choose(iaas.Choice{
+		AWS:   "vcap",
+		GCP:   "jumpbox",
+		Azure: "vcap",
+	}).(string)
+	return gatewayUser
+}
+
+// SSH opens a session on target, which is either an instance of the concourse deployment or the director.
+// If command is empty the session is interactive
+func (client *AWSClient) SSH(ctx context.Context, target, command string, stdin io.Reader) error {
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve director IP: [%v]", err)
+	}
+
+	return openSSHSession(ctx, client.boshCLI, client.workingdir, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert(), client.config.GetPrivateKey(), GatewayUser(client.provider), target, command, stdin, client.stdout)
+}
+
+// SSH opens a session on target, which is either an instance of the concourse deployment or the director.
+// If command is empty the session is interactive
+func (client *GCPClient) SSH(ctx context.Context, target, command string, stdin io.Reader) error {
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve director IP: [%v]", err)
+	}
+
+	return openSSHSession(ctx, client.boshCLI, client.workingdir, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert(), client.config.GetPrivateKey(), GatewayUser(client.provider), target, command, stdin, client.stdout)
+}
+
+// SSH opens a session on target, which is either an instance of the concourse deployment or the director.
+// If command is empty the session is interactive
+func (client *AzureClient) SSH(ctx context.Context, target, command string, stdin io.Reader) error {
+	directorPublicIP, err := client.outputs.Get("DirectorPublicIP")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve director IP: [%v]", err)
+	}
+
+	return openSSHSession(ctx, client.boshCLI, client.workingdir, directorPublicIP, client.config.GetDirectorPassword(), client.config.GetDirectorCACert(), client.config.GetPrivateKey(), GatewayUser(client.provider), target, command, stdin, client.stdout)
+}
+
+func openSSHSession(ctx context.Context, boshCLI boshcli.ICLI, workingdir workingdir.IClient, ip, password, ca, privateKey, gatewayUser, target, command string, stdin io.Reader, stdout io.Writer) error {
+	keyPath, err := saveJumpboxKey(workingdir, privateKey)
+	if err != nil {
+		return err
+	}
+
+	if target == DirectorSSHTarget {
+		return directorSSH(ctx, keyPath, gatewayUser, ip, command, stdin, stdout)
+	}
+
+	flags := []string{target, "--gw-host", ip, "--gw-user", gatewayUser, "--gw-private-key", keyPath}
+	if command != "" {
+		flags = append(flags, "--command", command)
+	}
+	return boshCLI.RunInteractiveCommand(ctx, "ssh", ip, password, ca, stdin, stdout, flags...)
+}
+
+// directorSSH connects to the director as the gateway user, running command if it is not empty
+func directorSSH(ctx context.Context, keyPath, gatewayUser, ip, command string, stdin io.Reader, stdout io.Writer) error {
+	args := []string{"-i", keyPath, "-o", "StrictHostKeyChecking=no", "-o", "UserKnownHostsFile=/dev/null", fmt.Sprintf("%s@%s", gatewayUser, ip)}
+	if command != "" {
+		args = append(args, command)
+	}
+	cmd := execCommand(ctx, "ssh", args...)
+	util.Interactive(cmd)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func saveJumpboxKey(workingdir workingdir.IClient, privateKey string) (string, error) {
+	keyPath, err := workingdir.SaveFileToWorkingDir(jumpboxKeyFilename, []byte(privateKey))
+	if err != nil {
+		return "", fmt.Errorf("failed to save jumpbox key to working directory: [%v]", err)
+	}
+	return keyPath, nil
+}
@@ -0,0 +1,68 @@
+package bosh
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/EngineerBetter/control-tower/bosh/internal/boshcli/boshclifakes"
+	"github.com/EngineerBetter/control-tower/bosh/internal/workingdir/workingdirfakes"
+	"github.com/EngineerBetter/control-tower/internal/fakeexec"
+	"github.com/EngineerBetter/control-tower/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSHInstance(t *testing.T) {
+	boshCLI := &boshclifakes.FakeICLI{}
+	workingdir := &workingdirfakes.FakeIClient{}
+	workingdir.SaveFileToWorkingDirReturns("/tmp/jumpbox.key", nil)
+	stdin := strings.NewReader("")
+
+	err := openSSHSession(context.Background(), boshCLI, workingdir, "1.2.3.4", "password", "ca", "key", "vcap", "worker/0", "", stdin, ioutil.Discard)
+	require.NoError(t, err)
+
+	filename, contents := workingdir.SaveFileToWorkingDirArgsForCall(0)
+	require.Equal(t, jumpboxKeyFilename, filename)
+	require.Equal(t, "key", string(contents))
+
+	_, action, ip, password, ca, actualStdin, _, flags := boshCLI.RunInteractiveCommandArgsForCall(0)
+	require.Equal(t, "ssh", action)
+	require.Equal(t, "1.2.3.4", ip)
+	require.Equal(t, "password", password)
+	require.Equal(t, "ca", ca)
+	require.Equal(t, stdin, actualStdin)
+	require.Equal(t, []string{"worker/0", "--gw-host", "1.2.3.4", "--gw-user", "vcap", "--gw-private-key", "/tmp/jumpbox.key"}, flags)
+}
+
+func TestSSHInstanceCommand(t *testing.T) {
+	boshCLI := &boshclifakes.FakeICLI{}
+	workingdir := &workingdirfakes.FakeIClient{}
+	workingdir.SaveFileToWorkingDirReturns("/tmp/jumpbox.key", nil)
+
+	err := openSSHSession(context.Background(), boshCLI, workingdir, "1.2.3.4", "password", "ca", "key", "jumpbox", "web/0", "uptime", nil, ioutil.Discard)
+	require.NoError(t, err)
+
+	_, _, _, _, _, _, _, flags := boshCLI.RunInteractiveCommandArgsForCall(0)
+	require.Equal(t, []string{"web/0", "--gw-host", "1.2.3.4", "--gw-user", "jumpbox", "--gw-private-key", "/tmp/jumpbox.key", "--command", "uptime"}, flags)
+}
+
+func TestSSHDirector(t *testing.T) {
+	e := fakeexec.New(t)
+	defer e.Finish()
+	execCommand = e.CmdContext()
+	defer func() { execCommand = util.CommandContext }()
+
+	e.Expect("ssh", "-i", "/tmp/jumpbox.key", "-o", "StrictHostKeyChecking=no", "-o", "UserKnownHostsFile=/dev/null", "vcap@1.2.3.4", "uptime").Outputs("up 3 days")
+
+	boshCLI := &boshclifakes.FakeICLI{}
+	workingdir := &workingdirfakes.FakeIClient{}
+	workingdir.SaveFileToWorkingDirReturns("/tmp/jumpbox.key", nil)
+	var stdout bytes.Buffer
+
+	err := openSSHSession(context.Background(), boshCLI, workingdir, "1.2.3.4", "password", "ca", "key", "vcap", DirectorSSHTarget, "uptime", nil, &stdout)
+	require.NoError(t, err)
+	require.Equal(t, "up 3 days", stdout.String())
+	require.Equal(t, 0, boshCLI.RunInteractiveCommandCallCount())
+}
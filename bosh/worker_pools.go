@@ -0,0 +1,143 @@
+package bosh
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/EngineerBetter/control-tower/bosh/internal/boshcli"
+	"github.com/EngineerBetter/control-tower/bosh/internal/workingdir"
+	"github.com/EngineerBetter/control-tower/config"
+	"github.com/EngineerBetter/control-tower/util/yaml"
+	yamlenc "gopkg.in/yaml.v2"
+)
+
+const workerPoolsFilename = "worker-pools.yml"
+
+// WorkerPoolInstanceGroup is the name of the instance group deployed for a worker pool
+func WorkerPoolInstanceGroup(poolName string) string {
+	return workerInstanceGroup + "-" + poolName
+}
+
+// workerPoolsOpsFlags saves an ops file adding an instance group for each worker pool and
+// returns the flags needed to use it, or no flags when there are no pools. deployFlags start
+// with the path of the manifest, and the returned flags must be given after them as the pools
+// are copied from the worker instance group produced by the ops files in deployFlags
+func workerPoolsOpsFlags(workingdir workingdir.IClient, pools []config.WorkerPool, deployFlags []string) ([]string, error) {
+	if len(pools) == 0 {
+		return nil, nil
+	}
+
+	manifest, err := ioutil.ReadFile(deployFlags[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest %s: [%v]", deployFlags[0], err)
+	}
+
+	var opsFiles [][]byte
+	for i, flag := range deployFlags {
+		if flag != "--ops-file" || i+1 == len(deployFlags) {
+			continue
+		}
+		contents, err := ioutil.ReadFile(deployFlags[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ops file %s: [%v]", deployFlags[i+1], err)
+		}
+		opsFiles = append(opsFiles, contents)
+	}
+
+	ops, err := workerPoolsOps(pools, manifest, opsFiles...)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := workingdir.SaveFileToWorkingDir(workerPoolsFilename, ops)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save %s to working directory: [%v]", workerPoolsFilename, err)
+	}
+
+	return []string{"--ops-file", path}, nil
+}
+
+// workerPoolsOps builds an ops file adding an instance group for each worker pool. go-patch
+// cannot copy an instance group, so each pool's instance group is copied from the worker
+// instance group of the manifest after opsFiles have been applied to it
+func workerPoolsOps(pools []config.WorkerPool, manifest []byte, opsFiles ...[]byte) ([]byte, error) {
+	interpolated := string(manifest)
+	for _, ops := range opsFiles {
+		var err error
+		interpolated, err = yaml.Interpolate(interpolated, string(ops), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply ops to concourse manifest: [%v]", err)
+		}
+	}
+
+	var parsed struct {
+		InstanceGroups []map[string]interface{} `yaml:"instance_groups"`
+	}
+	if err := yamlenc.Unmarshal([]byte(interpolated), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse concourse manifest: [%v]", err)
+	}
+
+	var worker []byte
+	for _, ig := range parsed.InstanceGroups {
+		if ig["name"] == workerInstanceGroup {
+			var err error
+			worker, err = yamlenc.Marshal(ig)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if worker == nil {
+		return nil, fmt.Errorf("concourse manifest has no %s instance group", workerInstanceGroup)
+	}
+
+	var ops []map[string]interface{}
+	for _, pool := range pools {
+		var ig map[string]interface{}
+		if err := yamlenc.Unmarshal(worker, &ig); err != nil {
+			return nil, err
+		}
+		ig["name"] = WorkerPoolInstanceGroup(pool.Name)
+		ig["instances"] = pool.Count
+		ig["vm_type"] = boshcli.WorkerPoolVMType(pool.Name)
+
+		if err := setWorkerJobProperties(ig, pool); err != nil {
+			return nil, fmt.Errorf("failed to configure worker pool %s: [%v]", pool.Name, err)
+		}
+
+		ops = append(ops, map[string]interface{}{
+			"type":  "replace",
+			"path":  "/instance_groups/-",
+			"value": ig,
+		})
+	}
+
+	return yamlenc.Marshal(ops)
+}
+
+// setWorkerJobProperties sets the tags and team of the worker job so that only matching
+// steps are scheduled on the pool
+func setWorkerJobProperties(ig map[string]interface{}, pool config.WorkerPool) error {
+	jobs, _ := ig["jobs"].([]interface{})
+	for _, j := range jobs {
+		job, ok := j.(map[interface{}]interface{})
+		if !ok || job["name"] != "worker" {
+			continue
+		}
+
+		properties, _ := job["properties"].(map[interface{}]interface{})
+		if properties == nil {
+			properties = map[interface{}]interface{}{}
+			job["properties"] = properties
+		}
+		if len(pool.Tags) > 0 {
+			properties["tags"] = pool.Tags
+		}
+		if pool.Team != "" {
+			properties["team"] = pool.Team
+		}
+		return nil
+	}
+
+	return fmt.Errorf("%s instance group has no worker job", workerInstanceGroup)
+}
@@ -0,0 +1,120 @@
+package bosh
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/EngineerBetter/control-tower/bosh/internal/workingdir/workingdirfakes"
+	"github.com/EngineerBetter/control-tower/config"
+	"github.com/EngineerBetter/control-tower/util/yaml"
+	"github.com/stretchr/testify/require"
+	yamlenc "gopkg.in/yaml.v2"
+)
+
+const workerPoolsManifest = `---
+name: concourse
+instance_groups:
+- name: web
+  instances: 1
+  vm_type: ((web_vm_type))
+  jobs:
+  - name: web
+- name: worker
+  instances: ((worker_count))
+  vm_type: ((worker_vm_type))
+  networks:
+  - name: ((worker_network_name))
+  jobs:
+  - name: worker
+    release: concourse
+    properties:
+      drain_timeout: 10m
+`
+
+const workerPoolsVersionOps = `[{"type":"replace","path":"/instance_groups/name=worker/jobs/-","value":{"name":"node_exporter"}}]`
+
+func TestWorkerPoolsOps(t *testing.T) {
+	pools := []config.WorkerPool{
+		{Name: "gpu", Count: 2, Size: "xlarge", Tags: []string{"gpu", "cuda"}},
+		{Name: "ml", Count: 1, Size: "large", Team: "ml"},
+	}
+
+	ops, err := workerPoolsOps(pools, []byte(workerPoolsManifest), []byte(workerPoolsVersionOps))
+	require.NoError(t, err)
+
+	manifest, err := yaml.Interpolate(workerPoolsManifest, string(ops), nil)
+	require.NoError(t, err)
+
+	var parsed struct {
+		InstanceGroups []struct {
+			Name      string      `yaml:"name"`
+			Instances interface{} `yaml:"instances"`
+			VMType    string      `yaml:"vm_type"`
+			Networks  []struct {
+				Name string `yaml:"name"`
+			} `yaml:"networks"`
+			Jobs []struct {
+				Name       string                 `yaml:"name"`
+				Properties map[string]interface{} `yaml:"properties"`
+			} `yaml:"jobs"`
+		} `yaml:"instance_groups"`
+	}
+	require.NoError(t, yamlenc.Unmarshal([]byte(manifest), &parsed))
+	require.Len(t, parsed.InstanceGroups, 4)
+
+	worker, gpu, ml := parsed.InstanceGroups[1], parsed.InstanceGroups[2], parsed.InstanceGroups[3]
+	require.Equal(t, "((worker_vm_type))", worker.VMType)
+	require.NotContains(t, worker.Jobs[0].Properties, "tags")
+
+	require.Equal(t, "worker-gpu", gpu.Name)
+	require.Equal(t, 2, gpu.Instances)
+	require.Equal(t, "concourse-pool-gpu", gpu.VMType)
+	require.Equal(t, "((worker_network_name))", gpu.Networks[0].Name)
+	require.Len(t, gpu.Jobs, 2)
+	require.Equal(t, "node_exporter", gpu.Jobs[1].Name)
+	require.Equal(t, "10m", gpu.Jobs[0].Properties["drain_timeout"])
+	require.Equal(t, []interface{}{"gpu", "cuda"}, gpu.Jobs[0].Properties["tags"])
+	require.NotContains(t, gpu.Jobs[0].Properties, "team")
+
+	require.Equal(t, "worker-ml", ml.Name)
+	require.Equal(t, "ml", ml.Jobs[0].Properties["team"])
+	require.NotContains(t, ml.Jobs[0].Properties, "tags")
+}
+
+func TestWorkerPoolsOpsWithoutWorkerInstanceGroup(t *testing.T) {
+	_, err := workerPoolsOps([]config.WorkerPool{{Name: "gpu"}}, []byte("instance_groups:\n- name: web\n"))
+	require.EqualError(t, err, "concourse manifest has no worker instance group")
+}
+
+func TestWorkerPoolsOpsFlagsAppliesEarlierOpsFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	manifestPath := filepath.Join(dir, "manifest.yml")
+	require.NoError(t, ioutil.WriteFile(manifestPath, []byte(workerPoolsManifest), 0600))
+	opsPath := filepath.Join(dir, "extra.yml")
+	require.NoError(t, ioutil.WriteFile(opsPath, []byte(`[{"type":"replace","path":"/instance_groups/name=worker/env?/extra","value":"from-ops-file"}]`), 0600))
+
+	workingdir := &workingdirfakes.FakeIClient{}
+	workingdir.SaveFileToWorkingDirReturns("/tmp/worker-pools.yml", nil)
+
+	flags, err := workerPoolsOpsFlags(workingdir, []config.WorkerPool{{Name: "gpu", Count: 1, Tags: []string{"gpu"}}}, []string{manifestPath, "--vars-store", "creds.yml", "--ops-file", opsPath})
+	require.NoError(t, err)
+	require.Equal(t, []string{"--ops-file", "/tmp/worker-pools.yml"}, flags)
+
+	name, ops := workingdir.SaveFileToWorkingDirArgsForCall(0)
+	require.Equal(t, workerPoolsFilename, name)
+	require.Contains(t, string(ops), "from-ops-file")
+}
+
+func TestWorkerPoolsOpsFlagsWithoutPools(t *testing.T) {
+	workingdir := &workingdirfakes.FakeIClient{}
+
+	flags, err := workerPoolsOpsFlags(workingdir, nil, []string{"--ops-file", "/does/not/exist.yml"})
+	require.NoError(t, err)
+	require.Empty(t, flags)
+	require.Equal(t, 0, workingdir.SaveFileToWorkingDirCallCount())
+}
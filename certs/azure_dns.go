@@ -0,0 +1,105 @@
+package certs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/EngineerBetter/control-tower/iaas"
+	"github.com/xenolf/lego/challenge/dns01"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const azureDNSAPIVersion = "2018-05-01"
+
+// azureDNSProvider solves DNS-01 challenges by adding TXT records to the Azure DNS zone found for the domain
+type azureDNSProvider struct {
+	client        *http.Client
+	managementURL string
+	provider      iaas.Provider
+}
+
+func newAzureDNSProvider(provider iaas.Provider) (*azureDNSProvider, error) {
+	attrs := map[string]string{}
+	for _, attr := range []string{"tenant_id", "client_id", "client_secret"} {
+		value, err := provider.Attr(attr)
+		if err != nil {
+			return nil, fmt.Errorf("azure dns: [%v]", err)
+		}
+		attrs[attr] = value
+	}
+
+	conf := clientcredentials.Config{
+		ClientID:     attrs["client_id"],
+		ClientSecret: attrs["client_secret"],
+		TokenURL:     fmt.Sprintf("https://login.microsoftonline.com/%s/oauth2/v2.0/token", attrs["tenant_id"]),
+		Scopes:       []string{"https://management.azure.com/.default"},
+	}
+
+	return &azureDNSProvider{
+		client:        conf.Client(context.Background()),
+		managementURL: "https://management.azure.com",
+		provider:      provider,
+	}, nil
+}
+
+// Present creates the TXT record that fulfils the challenge
+func (d *azureDNSProvider) Present(domain, token, keyAuth string) error {
+	fqdn, value := dns01.GetRecord(domain, keyAuth)
+
+	body, err := json.Marshal(map[string]interface{}{
+		"properties": map[string]interface{}{
+			"TTL":        60,
+			"TXTRecords": []map[string][]string{{"value": {value}}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return d.do(http.MethodPut, fqdn, body)
+}
+
+// CleanUp removes the TXT record created by Present
+func (d *azureDNSProvider) CleanUp(domain, token, keyAuth string) error {
+	fqdn, _ := dns01.GetRecord(domain, keyAuth)
+	return d.do(http.MethodDelete, fqdn, nil)
+}
+
+// Timeout returns how long to wait for the record to propagate and how often to check it
+func (d *azureDNSProvider) Timeout() (timeout, interval time.Duration) {
+	return 10 * time.Minute, 30 * time.Second
+}
+
+func (d *azureDNSProvider) do(method, fqdn string, body []byte) error {
+	name := dns01.UnFqdn(fqdn)
+	zoneName, zoneID, err := d.provider.FindLongestMatchingHostedZone(name)
+	if err != nil {
+		return fmt.Errorf("azure dns: failed to find zone for %s: [%v]", name, err)
+	}
+	relative := strings.TrimSuffix(name, "."+zoneName)
+
+	url := fmt.Sprintf("%s%s/TXT/%s?api-version=%s", d.managementURL, zoneID, relative, azureDNSAPIVersion)
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("azure dns: [%v]", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 && !(method == http.MethodDelete && resp.StatusCode == http.StatusNotFound) {
+		respBody, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("azure dns: %s %s returned %s: %s", method, relative, resp.Status, respBody)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package certs
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/EngineerBetter/control-tower/iaas/iaasfakes"
+)
+
+func TestAzureDNSProvider(t *testing.T) {
+	const zoneID = "/subscriptions/sub/resourceGroups/dns/providers/Microsoft.Network/dnszones/example.com"
+	records := map[string]string{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("api-version") != azureDNSAPIVersion {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			var body struct {
+				Properties struct {
+					TXTRecords []struct {
+						Value []string `json:"value"`
+					}
+				} `json:"properties"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.Properties.TXTRecords) != 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			records[r.URL.Path] = body.Properties.TXTRecords[0].Value[0]
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodDelete:
+			delete(records, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	provider := &iaasfakes.FakeProvider{}
+	provider.FindLongestMatchingHostedZoneReturns("example.com", zoneID, nil)
+
+	d := &azureDNSProvider{
+		client:        server.Client(),
+		managementURL: server.URL,
+		provider:      provider,
+	}
+
+	if err := d.Present("ci.example.com", "token", "keyAuth"); err != nil {
+		t.Fatalf("Present() error = %v", err)
+	}
+	if got := provider.FindLongestMatchingHostedZoneArgsForCall(0); got != "_acme-challenge.ci.example.com" {
+		t.Errorf("FindLongestMatchingHostedZone() called with %s", got)
+	}
+	path := zoneID + "/TXT/_acme-challenge.ci"
+	if records[path] == "" {
+		t.Fatalf("Present() did not create %s, records = %v", path, records)
+	}
+
+	if err := d.CleanUp("ci.example.com", "token", "keyAuth"); err != nil {
+		t.Fatalf("CleanUp() error = %v", err)
+	}
+	if len(records) != 0 {
+		t.Errorf("CleanUp() left records %v", records)
+	}
+}
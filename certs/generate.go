@@ -110,12 +110,33 @@ func customNewDNSProviderServiceAccount(saFile string, config *gcloud.Config) (*
 	return gcloud.NewDNSProviderConfig(config)
 }
 
-// Generate generates certs for use in a bosh director manifest
-func Generate(constructor func(u *User) (*lego.Client, error), caName string, provider iaas.Provider, ipOrDomains ...string) (*Certs, error) {
-
+// Generate generates certs for use in a bosh director manifest. Obtaining a certificate from
+// Let's Encrypt can take several minutes, so it is abandoned if ctx is cancelled
+func Generate(ctx context.Context, constructor func(u *User) (*lego.Client, error), caName string, provider iaas.Provider, ipOrDomains ...string) (*Certs, error) {
 	if hasIP(ipOrDomains) {
 		return generateSelfSigned(caName, ipOrDomains...)
 	}
+
+	type result struct {
+		certs *Certs
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		certs, err := obtain(constructor, provider, ipOrDomains...)
+		done <- result{certs, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-done:
+		return r.certs, r.err
+	}
+}
+
+// obtain requests a certificate for domains from the ACME server, using a DNS challenge
+func obtain(constructor func(u *User) (*lego.Client, error), provider iaas.Provider, ipOrDomains ...string) (*Certs, error) {
 	u := &User{}
 
 	c, err := constructor(u)
@@ -152,6 +173,15 @@ func Generate(constructor func(u *User) (*lego.Client, error), caName string, pr
 		if err1 != nil {
 			return nil, err1
 		}
+	case iaas.Azure:
+		dnsProvider, err1 := newAzureDNSProvider(provider)
+		if err1 != nil {
+			return nil, err1
+		}
+		err1 = c.Challenge.SetDNS01Provider(dnsProvider)
+		if err1 != nil {
+			return nil, err1
+		}
 	}
 	u.r, err = c.Registration.Register(registration.RegisterOptions{TermsOfServiceAgreed: true})
 	if err != nil {
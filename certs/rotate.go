@@ -0,0 +1,163 @@
+package certs
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const defaultRotatedKeyBits = 2048
+
+// RenewCA generates a new self-signed CA with the same subject, constraints and lifetime as the PEM encoded caCert
+func RenewCA(caCert string) (certPEM, keyPEM []byte, err error) {
+	old, err := ParseCertificate(caCert)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !old.IsCA {
+		return nil, nil, fmt.Errorf("certificate for [%v] is not a CA", old.Subject.CommonName)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, keyBits(old))
+	if err != nil {
+		return nil, nil, err
+	}
+	template, err := renewedTemplate(old)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return encodeCertificate(der), encodeKey(key), nil
+}
+
+// Reissue generates a new key and a certificate for it with the same subject, names, usages and lifetime
+// as the PEM encoded cert, signed by the given CA
+func Reissue(cert, caCert, caKey string) (certPEM, keyPEM []byte, err error) {
+	old, err := ParseCertificate(cert)
+	if err != nil {
+		return nil, nil, err
+	}
+	ca, err := ParseCertificate(caCert)
+	if err != nil {
+		return nil, nil, err
+	}
+	signer, err := parsePrivateKey(caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, keyBits(old))
+	if err != nil {
+		return nil, nil, err
+	}
+	template, err := renewedTemplate(old)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, signer)
+	if err != nil {
+		return nil, nil, err
+	}
+	return encodeCertificate(der), encodeKey(key), nil
+}
+
+// ParseCertificate parses the first certificate in a PEM encoded string
+func ParseCertificate(cert string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(cert))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no PEM encoded certificate found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// SplitCertificates returns each PEM encoded certificate in bundle, in order
+func SplitCertificates(bundle string) []string {
+	var certs []string
+	rest := []byte(bundle)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return certs
+		}
+		if block.Type == "CERTIFICATE" {
+			certs = append(certs, string(pem.EncodeToMemory(block)))
+		}
+	}
+}
+
+// BundleCertificates joins PEM encoded certificates into a single bundle
+func BundleCertificates(certs ...string) string {
+	var bundle strings.Builder
+	for _, cert := range certs {
+		bundle.WriteString(strings.TrimSpace(cert))
+		bundle.WriteString("\n")
+	}
+	return bundle.String()
+}
+
+// renewedTemplate copies the identity of old into a template valid from now for the same length of time
+func renewedTemplate(old *x509.Certificate) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	return &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               old.Subject,
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(old.NotAfter.Sub(old.NotBefore)),
+		KeyUsage:              old.KeyUsage,
+		ExtKeyUsage:           old.ExtKeyUsage,
+		BasicConstraintsValid: old.BasicConstraintsValid,
+		IsCA:                  old.IsCA,
+		MaxPathLen:            old.MaxPathLen,
+		MaxPathLenZero:        old.MaxPathLenZero,
+		DNSNames:              old.DNSNames,
+		IPAddresses:           old.IPAddresses,
+		EmailAddresses:        old.EmailAddresses,
+		URIs:                  old.URIs,
+	}, nil
+}
+
+// keyBits returns the size of the RSA key of cert, so that a replacement is at least as strong
+func keyBits(cert *x509.Certificate) int {
+	if key, ok := cert.PublicKey.(*rsa.PublicKey); ok && key.N.BitLen() > defaultRotatedKeyBits {
+		return key.N.BitLen()
+	}
+	return defaultRotatedKeyBits
+}
+
+func parsePrivateKey(key string) (interface{}, error) {
+	block, _ := pem.Decode([]byte(key))
+	if block == nil {
+		return nil, fmt.Errorf("no PEM encoded private key found")
+	}
+	if rsaKey, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return rsaKey, nil
+	}
+	if ecKey, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return ecKey, nil
+	}
+	return x509.ParsePKCS8PrivateKey(block.Bytes)
+}
+
+func encodeCertificate(der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func encodeKey(key *rsa.PrivateKey) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
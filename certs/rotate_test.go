@@ -0,0 +1,75 @@
+package certs
+
+import (
+	"crypto/x509"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRenewCAAndReissue(t *testing.T) {
+	generated, err := generateSelfSigned("control-tower-test", "10.0.0.6")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldCA, _ := ParseCertificate(string(generated.CACert))
+	oldLeaf, _ := ParseCertificate(string(generated.Cert))
+
+	caPEM, caKeyPEM, err := RenewCA(string(generated.CACert))
+	if err != nil {
+		t.Fatalf("RenewCA() error = %v", err)
+	}
+	ca, err := ParseCertificate(string(caPEM))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ca.IsCA || ca.Subject.CommonName != oldCA.Subject.CommonName {
+		t.Errorf("RenewCA() = CA %v named %s, want a CA named %s", ca.IsCA, ca.Subject.CommonName, oldCA.Subject.CommonName)
+	}
+	if ca.Equal(oldCA) {
+		t.Errorf("RenewCA() returned the old CA")
+	}
+	if lifetime := ca.NotAfter.Sub(ca.NotBefore); lifetime < oldCA.NotAfter.Sub(oldCA.NotBefore) {
+		t.Errorf("RenewCA() lifetime = %v, want at least %v", lifetime, oldCA.NotAfter.Sub(oldCA.NotBefore))
+	}
+
+	leafPEM, _, err := Reissue(string(generated.Cert), string(caPEM), string(caKeyPEM))
+	if err != nil {
+		t.Fatalf("Reissue() error = %v", err)
+	}
+	leaf, err := ParseCertificate(string(leafPEM))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(leaf.IPAddresses, oldLeaf.IPAddresses) || !leaf.IPAddresses[0].Equal(net.ParseIP("10.0.0.6")) {
+		t.Errorf("Reissue() IPs = %v, want %v", leaf.IPAddresses, oldLeaf.IPAddresses)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	if _, err = leaf.Verify(x509.VerifyOptions{Roots: roots, CurrentTime: time.Now(), KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}}); err != nil {
+		t.Errorf("reissued certificate is not signed by the new CA: %v", err)
+	}
+
+	if _, _, err = RenewCA(string(generated.Cert)); err == nil {
+		t.Errorf("RenewCA() of a leaf certificate should fail")
+	}
+}
+
+func TestSplitAndBundleCertificates(t *testing.T) {
+	first, _ := generateSelfSigned("first", "10.0.0.6")
+	second, _ := generateSelfSigned("second", "10.0.0.6")
+
+	bundle := BundleCertificates(string(first.CACert), string(second.CACert))
+	got := SplitCertificates(bundle)
+	if len(got) != 2 {
+		t.Fatalf("SplitCertificates() returned %d certificates", len(got))
+	}
+	if got[1] != BundleCertificates(string(second.CACert)) {
+		t.Errorf("SplitCertificates()[1] = %s, want %s", got[1], second.CACert)
+	}
+	if len(SplitCertificates("not a certificate")) != 0 {
+		t.Errorf("SplitCertificates() found certificates in plain text")
+	}
+}
@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/EngineerBetter/control-tower/commands/autoscale"
+	"github.com/EngineerBetter/control-tower/events"
+	"github.com/EngineerBetter/control-tower/iaas"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var initialAutoscaleArgs autoscale.Args
+
+var autoscaleFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:        "region",
+		Usage:       "(optional) AWS region",
+		EnvVar:      "AWS_REGION",
+		Destination: &initialAutoscaleArgs.Region,
+	},
+	cli.StringFlag{
+		Name:        "iaas",
+		Usage:       "(required) IAAS, can be AWS, GCP or Azure",
+		EnvVar:      "IAAS",
+		Destination: &initialAutoscaleArgs.IAAS,
+	},
+	cli.StringFlag{
+		Name:        "namespace",
+		Usage:       "(optional) Specify a namespace for deployments in order to group them in a meaningful way",
+		EnvVar:      "NAMESPACE",
+		Destination: &initialAutoscaleArgs.Namespace,
+	},
+	cli.IntFlag{
+		Name:        "min-workers",
+		Usage:       "(optional) Fewest Concourse workers to scale in to",
+		EnvVar:      "MIN_WORKERS",
+		Value:       1,
+		Destination: &initialAutoscaleArgs.MinWorkers,
+	},
+	cli.IntFlag{
+		Name:        "max-workers",
+		Usage:       "(required) Most Concourse workers to scale out to",
+		EnvVar:      "MAX_WORKERS",
+		Destination: &initialAutoscaleArgs.MaxWorkers,
+	},
+	cli.IntFlag{
+		Name:        "containers-per-worker",
+		Usage:       "(optional) Number of containers each worker should run before another worker is added",
+		EnvVar:      "CONTAINERS_PER_WORKER",
+		Value:       150,
+		Destination: &initialAutoscaleArgs.ContainersPerWorker,
+	},
+	cli.DurationFlag{
+		Name:        "cooldown",
+		Usage:       "(optional) Time to wait after scaling before scaling again",
+		EnvVar:      "COOLDOWN",
+		Value:       10 * time.Minute,
+		Destination: &initialAutoscaleArgs.Cooldown,
+	},
+	cli.DurationFlag{
+		Name:        "interval",
+		Usage:       "(optional) Time between checks of the worker load",
+		EnvVar:      "INTERVAL",
+		Value:       time.Minute,
+		Destination: &initialAutoscaleArgs.Interval,
+	},
+	cli.DurationFlag{
+		Name:        "land-timeout",
+		Usage:       "(optional) Longest time to wait for landing workers to finish their builds before scaling in",
+		EnvVar:      "LAND_TIMEOUT",
+		Value:       time.Hour,
+		Destination: &initialAutoscaleArgs.LandTimeout,
+	},
+	cli.BoolFlag{
+		Name:        "once",
+		Usage:       "(optional) Check the worker load and scale once, rather than running until interrupted",
+		EnvVar:      "ONCE",
+		Destination: &initialAutoscaleArgs.Once,
+	},
+}
+
+func autoscaleAction(c *cli.Context, autoscaleArgs autoscale.Args, provider iaas.Provider) error {
+	name := c.Args().Get(0)
+	if name == "" {
+		return errors.New("Usage is `control-tower autoscale <name> --max-workers <count>`")
+	}
+
+	version := c.App.Version
+
+	client, err := buildExistingDeploymentClient(name, version, autoscaleArgs.Namespace, provider)
+	if err != nil {
+		return err
+	}
+
+	return client.Autoscale(commandContext, autoscaleArgs)
+}
+
+func validateAutoscaleArgs(c *cli.Context, autoscaleArgs autoscale.Args) (autoscale.Args, error) {
+	err := autoscaleArgs.MarkSetFlags(c)
+	if err != nil {
+		return autoscaleArgs, fmt.Errorf("failed to mark set Autoscale flags: [%v]", err)
+	}
+
+	if err = autoscaleArgs.Validate(); err != nil {
+		return autoscaleArgs, fmt.Errorf("failed to validate Autoscale flags: [%v]", err)
+	}
+
+	return autoscaleArgs, nil
+}
+
+var autoscaleCmd = cli.Command{
+	Name:      "autoscale",
+	Usage:     "Scales the Concourse workers between bounds based on their load",
+	ArgsUsage: "<name>",
+	Flags:     autoscaleFlags,
+	Action: func(c *cli.Context) error {
+		autoscaleArgs, err := validateAutoscaleArgs(c, initialAutoscaleArgs)
+		if err != nil {
+			return events.Categorize(fmt.Errorf("Error validating args on autoscale: [%v]", err), events.UsageCategory)
+		}
+		iaasName, err := iaas.Validate(autoscaleArgs.IAAS)
+		if err != nil {
+			return fmt.Errorf("Error mapping to supported IAASes on autoscale: [%v]", err)
+		}
+		provider, err := iaas.New(iaasName, autoscaleArgs.Region)
+		if err != nil {
+			return fmt.Errorf("Error creating IAAS provider on autoscale: [%v]", err)
+		}
+		return autoscaleAction(c, autoscaleArgs, provider)
+	},
+}
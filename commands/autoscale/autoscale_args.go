@@ -0,0 +1,116 @@
+package autoscale
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+// Args are arguments passed to the autoscale command
+type Args struct {
+	Region                   string
+	RegionIsSet              bool
+	Namespace                string
+	NamespaceIsSet           bool
+	IAAS                     string
+	IAASIsSet                bool
+	MinWorkers               int
+	MinWorkersIsSet          bool
+	MaxWorkers               int
+	MaxWorkersIsSet          bool
+	ContainersPerWorker      int
+	ContainersPerWorkerIsSet bool
+	Cooldown                 time.Duration
+	CooldownIsSet            bool
+	Interval                 time.Duration
+	IntervalIsSet            bool
+	LandTimeout              time.Duration
+	LandTimeoutIsSet         bool
+	Once                     bool
+	OnceIsSet                bool
+}
+
+//MarkSetFlags is marking which autoscale Args have been set
+func (a *Args) MarkSetFlags(c FlagSetChecker) error {
+	for _, f := range c.FlagNames() {
+		if c.IsSet(f) {
+			switch f {
+			case "region":
+				a.RegionIsSet = true
+			case "namespace":
+				a.NamespaceIsSet = true
+			case "iaas":
+				a.IAASIsSet = true
+			case "min-workers":
+				a.MinWorkersIsSet = true
+			case "max-workers":
+				a.MaxWorkersIsSet = true
+			case "containers-per-worker":
+				a.ContainersPerWorkerIsSet = true
+			case "cooldown":
+				a.CooldownIsSet = true
+			case "interval":
+				a.IntervalIsSet = true
+			case "land-timeout":
+				a.LandTimeoutIsSet = true
+			case "once":
+				a.OnceIsSet = true
+			default:
+				return fmt.Errorf("flag %q is not supported by autoscale flags", f)
+			}
+		}
+	}
+	return nil
+}
+
+// Validate checks that the worker bounds and timings make sense
+func (a *Args) Validate() error {
+	if !a.IAASIsSet {
+		return fmt.Errorf("--iaas flag not set")
+	}
+	if !a.MaxWorkersIsSet {
+		return errors.New("--max-workers flag not set")
+	}
+	if a.MinWorkers < 1 {
+		return errors.New("minimum number of workers is 1")
+	}
+	if a.MaxWorkers < a.MinWorkers {
+		return errors.New("--max-workers cannot be less than --min-workers")
+	}
+	if a.ContainersPerWorker < 1 {
+		return errors.New("--containers-per-worker must be at least 1")
+	}
+	if a.Cooldown < 0 {
+		return errors.New("--cooldown cannot be negative")
+	}
+	if a.Interval <= 0 {
+		return errors.New("--interval must be greater than zero")
+	}
+	if a.LandTimeout <= 0 {
+		return errors.New("--land-timeout must be greater than zero")
+	}
+	return nil
+}
+
+// FlagSetChecker allows us to find out if flags were set, adn what the names of all flags are
+type FlagSetChecker interface {
+	IsSet(name string) bool
+	FlagNames() (names []string)
+}
+
+// ContextWrapper wraps a CLI context for testing
+type ContextWrapper struct {
+	c *cli.Context
+}
+
+// IsSet tells you if a user provided a flag
+func (t *ContextWrapper) IsSet(name string) bool {
+	return t.c.IsSet(name)
+}
+
+// FlagNames lists all flags it's possible for a user to provide
+func (t *ContextWrapper) FlagNames() (names []string) {
+	return t.c.FlagNames()
+}
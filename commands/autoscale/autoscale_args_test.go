@@ -0,0 +1,122 @@
+package autoscale_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/EngineerBetter/control-tower/commands/autoscale"
+)
+
+func TestAutoscaleArgs_Validate(t *testing.T) {
+	defaultFields := Args{
+		Region:              "eu-west-1",
+		IAAS:                "AWS",
+		IAASIsSet:           true,
+		MinWorkers:          1,
+		MaxWorkers:          6,
+		MaxWorkersIsSet:     true,
+		ContainersPerWorker: 150,
+		Cooldown:            10 * time.Minute,
+		Interval:            time.Minute,
+		LandTimeout:         time.Hour,
+	}
+	tests := []struct {
+		name         string
+		modification func() Args
+		wantErr      bool
+		expectedErr  string
+	}{
+		{
+			name: "Default args",
+			modification: func() Args {
+				return defaultFields
+			},
+			wantErr: false,
+		},
+		{
+			name: "IAAS not set",
+			modification: func() Args {
+				args := defaultFields
+				args.IAASIsSet = false
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "--iaas flag not set",
+		},
+		{
+			name: "Max workers not set",
+			modification: func() Args {
+				args := defaultFields
+				args.MaxWorkersIsSet = false
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "--max-workers flag not set",
+		},
+		{
+			name: "Min workers less than 1",
+			modification: func() Args {
+				args := defaultFields
+				args.MinWorkers = 0
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "minimum number of workers is 1",
+		},
+		{
+			name: "Max workers less than min workers",
+			modification: func() Args {
+				args := defaultFields
+				args.MinWorkers = 4
+				args.MaxWorkers = 2
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "--max-workers cannot be less than --min-workers",
+		},
+		{
+			name: "Containers per worker less than 1",
+			modification: func() Args {
+				args := defaultFields
+				args.ContainersPerWorker = 0
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "--containers-per-worker must be at least 1",
+		},
+		{
+			name: "Zero interval",
+			modification: func() Args {
+				args := defaultFields
+				args.Interval = 0
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "--interval must be greater than zero",
+		},
+		{
+			name: "Negative cooldown",
+			modification: func() Args {
+				args := defaultFields
+				args.Cooldown = -time.Minute
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "--cooldown cannot be negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := tt.modification()
+			err := args.Validate()
+			if (err != nil) != tt.wantErr || (err != nil && tt.wantErr && !strings.Contains(err.Error(), tt.expectedErr)) {
+				if err != nil {
+					t.Errorf("AutoscaleArgs.Validate() %v test failed.\nFailed with error = %v,\nExpected error = %v,\nShould fail %v\nWith args: %#v", tt.name, err.Error(), tt.expectedErr, tt.wantErr, args)
+				} else {
+					t.Errorf("AutoscaleArgs.Validate() %v test failed.\nShould fail %v\nWith args: %#v", tt.name, tt.wantErr, args)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/EngineerBetter/control-tower/commands/backup"
+	"github.com/EngineerBetter/control-tower/events"
+	"github.com/EngineerBetter/control-tower/iaas"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var initialBackupArgs backup.Args
+
+var backupFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:        "region",
+		Usage:       "(optional) AWS region",
+		EnvVar:      "AWS_REGION",
+		Destination: &initialBackupArgs.Region,
+	},
+	cli.StringFlag{
+		Name:        "iaas",
+		Usage:       "(required) IAAS, can be AWS, GCP or Azure",
+		EnvVar:      "IAAS",
+		Destination: &initialBackupArgs.IAAS,
+	},
+	cli.StringFlag{
+		Name:        "namespace",
+		Usage:       "(optional) Specify a namespace for deployments in order to group them in a meaningful way",
+		EnvVar:      "NAMESPACE",
+		Destination: &initialBackupArgs.Namespace,
+	},
+}
+
+func backupAction(c *cli.Context, backupArgs backup.Args, provider iaas.Provider) error {
+	name := c.Args().Get(0)
+	if name == "" {
+		return errors.New("Usage is `control-tower backup <name>`")
+	}
+
+	version := c.App.Version
+
+	client, err := buildExistingDeploymentClient(name, version, backupArgs.Namespace, provider)
+	if err != nil {
+		return err
+	}
+
+	metadata, err := client.Backup(commandContext)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(os.Stdout, "Backup %s stored in the config bucket. Restore it with `control-tower restore %s --from %s`\n", metadata.ID, name, metadata.ID)
+	return err
+}
+
+func validateBackupArgs(c *cli.Context, backupArgs backup.Args) (backup.Args, error) {
+	err := backupArgs.MarkSetFlags(c)
+	if err != nil {
+		return backupArgs, fmt.Errorf("failed to mark set Backup flags: [%v]", err)
+	}
+
+	if err = backupArgs.Validate(); err != nil {
+		return backupArgs, fmt.Errorf("failed to validate Backup flags: [%v]", err)
+	}
+
+	return backupArgs, nil
+}
+
+var backupCmd = cli.Command{
+	Name:      "backup",
+	Usage:     "Backs up the Concourse, CredHub and UAA databases",
+	ArgsUsage: "<name>",
+	Flags:     backupFlags,
+	Action: func(c *cli.Context) error {
+		backupArgs, err := validateBackupArgs(c, initialBackupArgs)
+		if err != nil {
+			return events.Categorize(fmt.Errorf("Error validating args on backup: [%v]", err), events.UsageCategory)
+		}
+		iaasName, err := iaas.Validate(backupArgs.IAAS)
+		if err != nil {
+			return fmt.Errorf("Error mapping to supported IAASes on backup: [%v]", err)
+		}
+		provider, err := iaas.New(iaasName, backupArgs.Region)
+		if err != nil {
+			return fmt.Errorf("Error creating IAAS provider on backup: [%v]", err)
+		}
+		return backupAction(c, backupArgs, provider)
+	},
+}
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/EngineerBetter/control-tower/bosh"
+	"github.com/EngineerBetter/control-tower/certs"
+	"github.com/EngineerBetter/control-tower/concourse"
+	"github.com/EngineerBetter/control-tower/iaas"
+	"github.com/EngineerBetter/control-tower/resource"
+	"github.com/EngineerBetter/control-tower/terraform"
+	"github.com/EngineerBetter/control-tower/util"
+)
+
+// loadVersionFile returns the createenv dependencies and CLI versions for the provider
+func loadVersionFile(provider iaas.Provider) ([]byte, error) {
+	if provider.IAAS() == iaas.Azure {
+		versionFile, err := resource.AzureVersionFile()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load Azure dependency versions: [%v]", err)
+		}
+		return versionFile, nil
+	}
+
+	versionFile, _ := provider.Choose(iaas.Choice{
+		AWS: resource.AWSVersionFile,
+		GCP: resource.GCPVersionFile,
+	}).([]byte)
+	return versionFile, nil
+}
+
+// buildExistingDeploymentClient builds a client for commands which operate on an existing deployment without deploy args
+func buildExistingDeploymentClient(name, version, namespace string, provider iaas.Provider) (*concourse.Client, error) {
+	versionFile, err := loadVersionFile(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	stateStore, err := newStateStore(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	configClient, err := newConfigClient(provider, stateStore, name, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	terraformClient, err := terraform.New(provider.IAAS(), terraform.DownloadTerraform(versionFile), terraformCache(provider, name, namespace), terraform.EnsureLock(configClient.EnsureTerraformLock))
+	if err != nil {
+		return nil, err
+	}
+
+	tfInputVarsFactory, err := concourse.NewTFInputVarsFactory(provider, stateStore)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating TFInputVarsFactory [%v]", err)
+	}
+
+	client := concourse.NewClient(
+		provider,
+		terraformClient,
+		tfInputVarsFactory,
+		bosh.New,
+		newFlyClient,
+		certs.Generate,
+		configClient,
+		nil,
+		os.Stdout,
+		os.Stderr,
+		util.FindUserIP,
+		certs.NewAcmeClient,
+		util.GeneratePasswordWithLength,
+		util.EightRandomLetters,
+		util.GenerateSSHKeyPair,
+		version,
+		versionFile,
+	)
+
+	return client, nil
+}
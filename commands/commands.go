@@ -1,18 +1,47 @@
 package commands
 
 import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/EngineerBetter/control-tower/config"
+	"github.com/EngineerBetter/control-tower/encryption"
+	"github.com/EngineerBetter/control-tower/events"
+	"github.com/EngineerBetter/control-tower/fly"
+	"github.com/EngineerBetter/control-tower/iaas"
+	"github.com/EngineerBetter/control-tower/store"
+	"github.com/EngineerBetter/control-tower/terraform"
+
 	cli "gopkg.in/urfave/cli.v1"
 )
 
 // Commands is a list of all supported CLI commands
 var Commands = []cli.Command{
+	autoscaleCmd,
+	backupCmd,
+	configCmd,
+	credentialsCmd,
 	deployCmd,
 	destroyCmd,
+	encryptCmd,
+	historyCmd,
 	infoCmd,
+	listCmd,
+	logsCmd,
 	maintainCmd,
+	planCmd,
+	restoreCmd,
+	rollbackCmd,
+	scaleCmd,
+	sshCmd,
+	unlockCmd,
 }
 
 var nonInteractive bool
+var commandContext = context.Background()
+var stateBackend store.Options
+var encryptionProvider, encryptionKeyID string
 
 // GlobalFlags are the global CLIflags
 var GlobalFlags = []cli.Flag{
@@ -22,9 +51,101 @@ var GlobalFlags = []cli.Flag{
 		Usage:       "Non interactive",
 		Destination: &nonInteractive,
 	},
+	cli.StringFlag{
+		Name:        "state-backend",
+		EnvVar:      "STATE_BACKEND",
+		Usage:       "(optional) Where to keep config and state files, can be iaas, s3, s3-compatible, gcs or local",
+		Value:       store.IAAS,
+		Destination: &stateBackend.Backend,
+	},
+	cli.StringFlag{
+		Name:        "state-backend-region",
+		EnvVar:      "STATE_BACKEND_REGION",
+		Usage:       "(optional) Region of the s3, s3-compatible or gcs state backend",
+		Destination: &stateBackend.Region,
+	},
+	cli.StringFlag{
+		Name:        "state-backend-endpoint",
+		EnvVar:      "STATE_BACKEND_ENDPOINT",
+		Usage:       "(optional) Endpoint of the s3-compatible state backend",
+		Destination: &stateBackend.Endpoint,
+	},
+	cli.StringFlag{
+		Name:        "state-backend-dir",
+		EnvVar:      "STATE_BACKEND_DIR",
+		Usage:       "(optional) Directory of the local state backend",
+		Destination: &stateBackend.Dir,
+	},
+	cli.StringFlag{
+		Name:        "encryption",
+		EnvVar:      "ENCRYPTION",
+		Usage:       "(optional) Encrypt config and state files with a key from aws-kms, gcp-kms or passphrase",
+		Destination: &encryptionProvider,
+	},
+	cli.StringFlag{
+		Name:        "encryption-key-id",
+		EnvVar:      "ENCRYPTION_KEY_ID",
+		Usage:       "(optional) ID, alias or ARN of the AWS KMS key, or resource name of the Cloud KMS key",
+		Destination: &encryptionKeyID,
+	},
+	cli.StringFlag{
+		Name:        "output-format",
+		EnvVar:      "OUTPUT_FORMAT",
+		Usage:       "(optional) Format of the progress output, text or json for a stream of events",
+		Value:       events.TextFormat,
+		Destination: &outputFormat,
+	},
+	cli.StringFlag{
+		Name:        "events-file",
+		EnvVar:      "EVENTS_FILE",
+		Usage:       "(optional) File to write the events of --output-format json to, instead of stderr",
+		Destination: &eventsFile,
+	},
 }
 
 // NonInteractiveModeEnabled returns true if --non-interactive true has been passed in
 func NonInteractiveModeEnabled() bool {
 	return nonInteractive
 }
+
+// SetContext sets the context of the long running operations started by commands,
+// which stop as soon as they safely can when it is cancelled
+func SetContext(ctx context.Context) {
+	commandContext = ctx
+}
+
+// newStateStore returns the store selected by the --state-backend flags
+func newStateStore(provider iaas.Provider) (store.Store, error) {
+	stateStore, err := store.New(provider, stateBackend)
+	if err != nil {
+		return nil, fmt.Errorf("error creating state backend: [%v]", err)
+	}
+	return stateStore, nil
+}
+
+// newFlyClient returns a fly client whose self-update pipeline uses the same state backend as this command
+func newFlyClient(provider iaas.Provider, creds fly.Credentials, stdout, stderr io.Writer, versionFile []byte) (fly.IClient, error) {
+	return fly.New(provider, creds, stdout, stderr, versionFile, stateBackend)
+}
+
+// newConfigClient returns a config client for the deployment which keeps its files in stateStore, encrypted if --encryption is set
+func newConfigClient(provider iaas.Provider, stateStore store.Store, name, namespace string) (*config.Client, error) {
+	configClient := config.New(provider, stateStore, name, namespace)
+	if encryptionProvider == "" {
+		return configClient, nil
+	}
+
+	keyProvider, err := encryption.New(encryptionProvider, encryptionKeyID, provider.Region())
+	if err != nil {
+		return nil, fmt.Errorf("error creating encryption key provider: [%v]", err)
+	}
+	configClient.KeyProvider = keyProvider
+
+	return configClient, nil
+}
+
+// terraformCache keeps the terraform working directory of a deployment between commands, so that
+// terraform init only runs when the config has changed
+func terraformCache(provider iaas.Provider, name, namespace string) terraform.Option {
+	return terraform.Cache(fmt.Sprintf("%s-%s-%s-%s", provider.IAAS(), provider.Region(), namespace, name))
+}
@@ -24,6 +24,115 @@ var _ = Describe("commands", func() {
 		CleanupBuildArtifacts()
 	})
 
+	Describe("autoscale", func() {
+		Context("When using --help", func() {
+			It("should display usage details", func() {
+				command := exec.Command(cliPath, "autoscale", "--help")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred(), "Error running CLI: "+cliPath)
+				Eventually(session).Should(Exit(0))
+				Expect(session.Out).To(Say("control-tower autoscale - Scales the Concourse workers between bounds based on their load"))
+			})
+		})
+
+		Context("When the IAAS is not specified", func() {
+			It("Should show a meaningful error", func() {
+				command := exec.Command(cliPath, "autoscale", "abc", "--max-workers", "4")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred())
+				Eventually(session).Should(Exit(1))
+				// Say takes a regexp so `[` and `]` need to be escaped
+				Expect(session.Err).To(Say("Error validating args on autoscale: \\[failed to validate Autoscale flags: \\[--iaas flag not set\\]\\]"))
+			})
+		})
+
+		Context("When max workers is not specified", func() {
+			It("Should show a meaningful error", func() {
+				command := exec.Command(cliPath, "autoscale", "abc", "--iaas", "AWS")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred())
+				Eventually(session).Should(Exit(1))
+				Expect(session.Err).To(Say("--max-workers flag not set"))
+			})
+		})
+
+		Context("When no name is passed in", func() {
+			It("should display correct usage", func() {
+				command := exec.Command(cliPath, "autoscale", "--iaas", "AWS", "--max-workers", "4")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred())
+				Eventually(session).Should(Exit(1))
+				Expect(session.Err).To(Say("Usage is `control-tower autoscale <name> --max-workers <count>`"))
+			})
+		})
+	})
+
+	Describe("backup", func() {
+		Context("When using --help", func() {
+			It("should display usage details", func() {
+				command := exec.Command(cliPath, "backup", "--help")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred(), "Error running CLI: "+cliPath)
+				Eventually(session).Should(Exit(0))
+				Expect(session.Out).To(Say("control-tower backup - Backs up the Concourse, CredHub and UAA databases"))
+			})
+		})
+
+		Context("When the IAAS is not specified", func() {
+			It("Should show a meaningful error", func() {
+				command := exec.Command(cliPath, "backup", "abc")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred())
+				Eventually(session).Should(Exit(1))
+				// Say takes a regexp so `[` and `]` need to be escaped
+				Expect(session.Err).To(Say("Error validating args on backup: \\[failed to validate Backup flags: \\[--iaas flag not set\\]\\]"))
+			})
+		})
+
+		Context("When no name is passed in", func() {
+			It("should display correct usage", func() {
+				command := exec.Command(cliPath, "backup", "--iaas", "AWS")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred())
+				Eventually(session).Should(Exit(1))
+				Expect(session.Err).To(Say("Usage is `control-tower backup <name>`"))
+			})
+		})
+	})
+
+	Describe("config init", func() {
+		Context("When using --help", func() {
+			It("should display usage details", func() {
+				command := exec.Command(cliPath, "config", "init", "--help")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred(), "Error running CLI: "+cliPath)
+				Eventually(session).Should(Exit(0))
+				Expect(session.Out).To(Say("config init - Writes a deploy settings file from an existing deployment"))
+			})
+		})
+
+		Context("When the IAAS is not specified", func() {
+			It("Should show a meaningful error", func() {
+				command := exec.Command(cliPath, "config", "init", "abc")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred())
+				Eventually(session).Should(Exit(1))
+				// Say takes a regexp so `[` and `]` need to be escaped
+				Expect(session.Err).To(Say("Error validating args on config init: \\[failed to validate Config Init flags: \\[--iaas flag not set\\]\\]"))
+			})
+		})
+
+		Context("When no name is passed in", func() {
+			It("should display correct usage", func() {
+				command := exec.Command(cliPath, "config", "init", "--iaas", "AWS")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred())
+				Eventually(session).Should(Exit(1))
+				Expect(session.Err).To(Say("Usage is `control-tower config init <name>`"))
+			})
+		})
+	})
+
 	Describe("deploy", func() {
 		Context("When using --help", func() {
 			It("should display usage details", func() {
@@ -56,6 +165,16 @@ var _ = Describe("commands", func() {
 			})
 		})
 
+		Context("When the config file does not exist", func() {
+			It("Should show a meaningful error", func() {
+				command := exec.Command(cliPath, "deploy", "--config", "does-not-exist.yml", "abc")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred())
+				Eventually(session).Should(Exit(1))
+				Expect(session.Err).To(Say("failed to load Deploy config file does-not-exist.yml"))
+			})
+		})
+
 		Context("When no name is passed in", func() {
 			It("should display correct usage", func() {
 				command := exec.Command(cliPath, "deploy", "--iaas", "AWS")
@@ -203,6 +322,72 @@ var _ = Describe("commands", func() {
 		})
 	})
 
+	Describe("list", func() {
+		Context("When using --help", func() {
+			It("should display usage details", func() {
+				command := exec.Command(cliPath, "list", "--help")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred(), "Error running CLI: "+cliPath)
+				Eventually(session).Should(Exit(0))
+				Expect(session.Out).To(Say("control-tower list - Lists all deployments found in config buckets"))
+			})
+		})
+
+		Context("When the IAAS is not specified", func() {
+			It("Should show a meaningful error", func() {
+				command := exec.Command(cliPath, "list")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred())
+				Eventually(session).Should(Exit(1))
+				// Say takes a regexp so `[` and `]` need to be escaped
+				Expect(session.Err).To(Say("Error validating args on list: \\[failed to validate List flags: \\[--iaas flag not set\\]\\]"))
+			})
+		})
+	})
+
+	Describe("logs", func() {
+		Context("When using --help", func() {
+			It("should display usage details", func() {
+				command := exec.Command(cliPath, "logs", "--help")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred(), "Error running CLI: "+cliPath)
+				Eventually(session).Should(Exit(0))
+				Expect(session.Out).To(Say("control-tower logs - Downloads or follows logs from the Concourse VMs or the BOSH director"))
+			})
+		})
+
+		Context("When the IAAS is not specified", func() {
+			It("Should show a meaningful error", func() {
+				command := exec.Command(cliPath, "logs", "abc")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred())
+				Eventually(session).Should(Exit(1))
+				// Say takes a regexp so `[` and `]` need to be escaped
+				Expect(session.Err).To(Say("Error validating args on logs: \\[failed to validate Logs flags: \\[--iaas flag not set\\]\\]"))
+			})
+		})
+
+		Context("When --dir is used with --follow", func() {
+			It("Should show a meaningful error", func() {
+				command := exec.Command(cliPath, "logs", "--iaas", "AWS", "--follow", "--dir", "/tmp", "abc")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred())
+				Eventually(session).Should(Exit(1))
+				Expect(session.Err).To(Say("--dir cannot be used with --follow"))
+			})
+		})
+
+		Context("When no name is passed in", func() {
+			It("should display correct usage", func() {
+				command := exec.Command(cliPath, "logs", "--iaas", "AWS")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred())
+				Eventually(session).Should(Exit(1))
+				Expect(session.Err).To(Say("Usage is `control-tower logs <name> \\[instance-group\\[/index\\]\\|director\\]`"))
+			})
+		})
+	})
+
 	Describe("maintain", func() {
 		Context("When using --help", func() {
 			It("should display usage details", func() {
@@ -235,4 +420,146 @@ var _ = Describe("commands", func() {
 			})
 		})
 	})
+
+	Describe("plan", func() {
+		Context("When using --help", func() {
+			It("should display usage details", func() {
+				command := exec.Command(cliPath, "plan", "--help")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred(), "Error running CLI: "+cliPath)
+				Eventually(session).Should(Exit(0))
+				Expect(session.Out).To(Say("control-tower plan - Previews the changes a deploy would make to a Concourse"))
+			})
+		})
+
+		Context("When the IAAS is not specified", func() {
+			It("Should show a meaningful error", func() {
+				command := exec.Command(cliPath, "plan", "abc")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred())
+				Eventually(session).Should(Exit(1))
+				// Say takes a regexp so `[` and `]` need to be escaped
+				Expect(session.Err).To(Say("Error validating args on plan: \\[failed to validate Deploy flags: \\[--iaas flag not set\\]\\]"))
+			})
+		})
+
+		Context("When no name is passed in", func() {
+			It("should display correct usage", func() {
+				command := exec.Command(cliPath, "plan", "--iaas", "AWS")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred())
+				Eventually(session).Should(Exit(1))
+				Expect(session.Err).To(Say("Usage is `control-tower plan <name>`"))
+			})
+		})
+	})
+
+	Describe("restore", func() {
+		Context("When using --help", func() {
+			It("should display usage details", func() {
+				command := exec.Command(cliPath, "restore", "--help")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred(), "Error running CLI: "+cliPath)
+				Eventually(session).Should(Exit(0))
+				Expect(session.Out).To(Say("control-tower restore - Restores the Concourse, CredHub and UAA databases from a backup"))
+			})
+		})
+
+		Context("When the IAAS is not specified", func() {
+			It("Should show a meaningful error", func() {
+				command := exec.Command(cliPath, "restore", "abc", "--from", "20200102T030405Z")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred())
+				Eventually(session).Should(Exit(1))
+				// Say takes a regexp so `[` and `]` need to be escaped
+				Expect(session.Err).To(Say("Error validating args on restore: \\[failed to validate Restore flags: \\[--iaas flag not set\\]\\]"))
+			})
+		})
+
+		Context("When no name is passed in", func() {
+			It("should display correct usage", func() {
+				command := exec.Command(cliPath, "restore", "--iaas", "AWS", "--from", "20200102T030405Z")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred())
+				Eventually(session).Should(Exit(1))
+				Expect(session.Err).To(Say("Usage is `control-tower restore <name> --from <backup-id>`"))
+			})
+		})
+	})
+
+	Describe("scale", func() {
+		Context("When using --help", func() {
+			It("should display usage details", func() {
+				command := exec.Command(cliPath, "scale", "--help")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred(), "Error running CLI: "+cliPath)
+				Eventually(session).Should(Exit(0))
+				Expect(session.Out).To(Say("control-tower scale - Changes the number or size of Concourse workers without a full deploy"))
+			})
+		})
+
+		Context("When the IAAS is not specified", func() {
+			It("Should show a meaningful error", func() {
+				command := exec.Command(cliPath, "scale", "abc", "--workers", "2")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred())
+				Eventually(session).Should(Exit(1))
+				// Say takes a regexp so `[` and `]` need to be escaped
+				Expect(session.Err).To(Say("Error validating args on scale: \\[failed to validate Scale flags: \\[--iaas flag not set\\]\\]"))
+			})
+		})
+
+		Context("When neither workers nor worker size is specified", func() {
+			It("Should show a meaningful error", func() {
+				command := exec.Command(cliPath, "scale", "abc", "--iaas", "AWS")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred())
+				Eventually(session).Should(Exit(1))
+				Expect(session.Err).To(Say("at least one of --workers or --worker-size must be set"))
+			})
+		})
+
+		Context("When no name is passed in", func() {
+			It("should display correct usage", func() {
+				command := exec.Command(cliPath, "scale", "--iaas", "AWS", "--workers", "2")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred())
+				Eventually(session).Should(Exit(1))
+				Expect(session.Err).To(Say("Usage is `control-tower scale <name> --workers <count>`"))
+			})
+		})
+	})
+
+	Describe("ssh", func() {
+		Context("When using --help", func() {
+			It("should display usage details", func() {
+				command := exec.Command(cliPath, "ssh", "--help")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred(), "Error running CLI: "+cliPath)
+				Eventually(session).Should(Exit(0))
+				Expect(session.Out).To(Say("control-tower ssh - Opens an SSH session on a Concourse VM or the BOSH director"))
+			})
+		})
+
+		Context("When the IAAS is not specified", func() {
+			It("Should show a meaningful error", func() {
+				command := exec.Command(cliPath, "ssh", "abc", "worker/0")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred())
+				Eventually(session).Should(Exit(1))
+				// Say takes a regexp so `[` and `]` need to be escaped
+				Expect(session.Err).To(Say("Error validating args on ssh: \\[failed to validate SSH flags: \\[--iaas flag not set\\]\\]"))
+			})
+		})
+
+		Context("When no target is passed in", func() {
+			It("should display correct usage", func() {
+				command := exec.Command(cliPath, "ssh", "--iaas", "AWS", "abc")
+				session, err := Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred())
+				Eventually(session).Should(Exit(1))
+				Expect(session.Err).To(Say("Usage is `control-tower ssh <name> <instance-group/index\\|director>`"))
+			})
+		})
+	})
 })
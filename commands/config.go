@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/EngineerBetter/control-tower/commands/configinit"
+	"github.com/EngineerBetter/control-tower/commands/deploy"
+	"github.com/EngineerBetter/control-tower/events"
+	"github.com/EngineerBetter/control-tower/iaas"
+	"gopkg.in/urfave/cli.v1"
+	"gopkg.in/yaml.v2"
+)
+
+var initialConfigInitArgs configinit.Args
+
+var configInitFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:        "region",
+		Usage:       "(optional) AWS region",
+		EnvVar:      "AWS_REGION",
+		Destination: &initialConfigInitArgs.Region,
+	},
+	cli.StringFlag{
+		Name:        "iaas",
+		Usage:       "(required) IAAS, can be AWS, GCP or Azure",
+		EnvVar:      "IAAS",
+		Destination: &initialConfigInitArgs.IAAS,
+	},
+	cli.StringFlag{
+		Name:        "namespace",
+		Usage:       "(optional) Specify a namespace for deployments in order to group them in a meaningful way",
+		EnvVar:      "NAMESPACE",
+		Destination: &initialConfigInitArgs.Namespace,
+	},
+	cli.StringFlag{
+		Name:        "output",
+		Usage:       "(optional) File to write deploy settings to",
+		Value:       "ct.yml",
+		Destination: &initialConfigInitArgs.Output,
+	},
+}
+
+const configFileHeader = `# Deploy settings for %s, use with control-tower deploy --config %s
+# Secrets and certificates are not included, pass them as flags or environment variables
+`
+
+func configInitAction(c *cli.Context, configInitArgs configinit.Args, provider iaas.Provider) error {
+	name := c.Args().Get(0)
+	if name == "" {
+		return errors.New("Usage is `control-tower config init <name>`")
+	}
+
+	if _, err := os.Stat(configInitArgs.Output); err == nil {
+		return fmt.Errorf("%s already exists", configInitArgs.Output)
+	}
+
+	stateStore, err := newStateStore(provider)
+	if err != nil {
+		return err
+	}
+
+	configClient, err := newConfigClient(provider, stateStore, name, configInitArgs.Namespace)
+	if err != nil {
+		return err
+	}
+
+	conf, err := configClient.Load()
+	if err != nil {
+		return fmt.Errorf("error loading config for deployment %s: [%v]", name, err)
+	}
+
+	contents, err := yaml.Marshal(deploy.NewFileFromConfig(conf))
+	if err != nil {
+		return err
+	}
+	contents = append([]byte(fmt.Sprintf(configFileHeader, name, configInitArgs.Output)), contents...)
+
+	err = ioutil.WriteFile(configInitArgs.Output, contents, 0644)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(os.Stdout, "Wrote deploy settings for %s to %s\n", name, configInitArgs.Output)
+	return err
+}
+
+func validateConfigInitArgs(c *cli.Context, configInitArgs configinit.Args) (configinit.Args, error) {
+	err := configInitArgs.MarkSetFlags(c)
+	if err != nil {
+		return configInitArgs, fmt.Errorf("failed to mark set Config Init flags: [%v]", err)
+	}
+
+	if err = configInitArgs.Validate(); err != nil {
+		return configInitArgs, fmt.Errorf("failed to validate Config Init flags: [%v]", err)
+	}
+
+	return configInitArgs, nil
+}
+
+var configCmd = cli.Command{
+	Name:  "config",
+	Usage: "Manages local deploy settings files",
+	Subcommands: []cli.Command{
+		{
+			Name:      "init",
+			Usage:     "Writes a deploy settings file from an existing deployment",
+			ArgsUsage: "<name>",
+			Flags:     configInitFlags,
+			Action: func(c *cli.Context) error {
+				configInitArgs, err := validateConfigInitArgs(c, initialConfigInitArgs)
+				if err != nil {
+					return events.Categorize(fmt.Errorf("Error validating args on config init: [%v]", err), events.UsageCategory)
+				}
+				iaasName, err := iaas.Validate(configInitArgs.IAAS)
+				if err != nil {
+					return fmt.Errorf("Error mapping to supported IAASes on config init: [%v]", err)
+				}
+				provider, err := iaas.New(iaasName, configInitArgs.Region)
+				if err != nil {
+					return fmt.Errorf("Error creating IAAS provider on config init: [%v]", err)
+				}
+				return configInitAction(c, configInitArgs, provider)
+			},
+		},
+	},
+}
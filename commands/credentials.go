@@ -0,0 +1,136 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/EngineerBetter/control-tower/commands/credentials"
+	"github.com/EngineerBetter/control-tower/config"
+	"github.com/EngineerBetter/control-tower/events"
+	"github.com/EngineerBetter/control-tower/iaas"
+	"gopkg.in/urfave/cli.v1"
+	"gopkg.in/yaml.v2"
+)
+
+var initialCredentialsArgs credentials.Args
+
+var credentialsFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:        "region",
+		Usage:       "(optional) AWS region",
+		EnvVar:      "AWS_REGION",
+		Destination: &initialCredentialsArgs.Region,
+	},
+	cli.StringFlag{
+		Name:        "iaas",
+		Usage:       "(required) IAAS, can be AWS, GCP or Azure",
+		EnvVar:      "IAAS",
+		Destination: &initialCredentialsArgs.IAAS,
+	},
+	cli.StringFlag{
+		Name:        "namespace",
+		Usage:       "(optional) Specify a namespace for deployments in order to group them in a meaningful way",
+		EnvVar:      "NAMESPACE",
+		Destination: &initialCredentialsArgs.Namespace,
+	},
+	cli.StringFlag{
+		Name:        "component",
+		Usage:       fmt.Sprintf("(optional) Only show the credentials of one component, can be one of %v", config.SecretComponents()),
+		Destination: &initialCredentialsArgs.Component,
+	},
+	cli.BoolFlag{
+		Name:        "json",
+		Usage:       "(optional) Output as json",
+		EnvVar:      "JSON",
+		Destination: &initialCredentialsArgs.JSON,
+	},
+}
+
+func credentialsAction(c *cli.Context, credentialsArgs credentials.Args, provider iaas.Provider) error {
+	name := c.Args().Get(0)
+	if name == "" {
+		return errors.New("Usage is `control-tower credentials <name>`")
+	}
+
+	stateStore, err := newStateStore(provider)
+	if err != nil {
+		return err
+	}
+
+	configClient, err := newConfigClient(provider, stateStore, name, credentialsArgs.Namespace)
+	if err != nil {
+		return err
+	}
+
+	conf, err := configClient.Load()
+	if err != nil {
+		return fmt.Errorf("error loading config for deployment %s: [%v]", name, err)
+	}
+
+	return writeCredentials(os.Stdout, conf, credentialsArgs.Component, credentialsArgs.JSON)
+}
+
+// writeCredentials writes the secrets of component, or of every component grouped by component when it is empty
+func writeCredentials(w io.Writer, conf config.Config, component string, asJSON bool) error {
+	var secrets interface{}
+	if component != "" {
+		secrets = conf.Secrets(component)
+	} else {
+		all := map[string]map[string]string{}
+		for _, c := range config.SecretComponents() {
+			if s := conf.Secrets(c); len(s) > 0 {
+				all[c] = s
+			}
+		}
+		secrets = all
+	}
+
+	if asJSON {
+		return json.NewEncoder(w).Encode(secrets)
+	}
+
+	contents, err := yaml.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(contents)
+	return err
+}
+
+func validateCredentialsArgs(c *cli.Context, credentialsArgs credentials.Args) (credentials.Args, error) {
+	err := credentialsArgs.MarkSetFlags(c)
+	if err != nil {
+		return credentialsArgs, fmt.Errorf("failed to mark set Credentials flags: [%v]", err)
+	}
+
+	if err = credentialsArgs.Validate(); err != nil {
+		return credentialsArgs, fmt.Errorf("failed to validate Credentials flags: [%v]", err)
+	}
+
+	return credentialsArgs, nil
+}
+
+var credentialsCmd = cli.Command{
+	Name:      "credentials",
+	Usage:     "Prints the passwords and keys of a deployment, which info redacts",
+	ArgsUsage: "<name>",
+	Flags:     credentialsFlags,
+	Action: func(c *cli.Context) error {
+		credentialsArgs, err := validateCredentialsArgs(c, initialCredentialsArgs)
+		if err != nil {
+			return events.Categorize(fmt.Errorf("Error validating args on credentials: [%v]", err), events.UsageCategory)
+		}
+		iaasName, err := iaas.Validate(credentialsArgs.IAAS)
+		if err != nil {
+			return fmt.Errorf("Error mapping to supported IAASes on credentials: [%v]", err)
+		}
+		provider, err := iaas.New(iaasName, credentialsArgs.Region)
+		if err != nil {
+			return fmt.Errorf("Error creating IAAS provider on credentials: [%v]", err)
+		}
+		return credentialsAction(c, credentialsArgs, provider)
+	},
+}
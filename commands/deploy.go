@@ -9,16 +9,14 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/EngineerBetter/control-tower/events"
 	"github.com/EngineerBetter/control-tower/terraform"
 
 	"github.com/EngineerBetter/control-tower/bosh"
 	"github.com/EngineerBetter/control-tower/certs"
 	"github.com/EngineerBetter/control-tower/commands/deploy"
 	"github.com/EngineerBetter/control-tower/concourse"
-	"github.com/EngineerBetter/control-tower/config"
-	"github.com/EngineerBetter/control-tower/fly"
 	"github.com/EngineerBetter/control-tower/iaas"
-	"github.com/EngineerBetter/control-tower/resource"
 	"github.com/EngineerBetter/control-tower/util"
 
 	cli "gopkg.in/urfave/cli.v1"
@@ -83,7 +81,7 @@ var deployFlags = []cli.Flag{
 	},
 	cli.StringFlag{
 		Name:        "iaas",
-		Usage:       "(required) IAAS, can be AWS or GCP",
+		Usage:       "(required) IAAS, can be AWS, GCP or Azure",
 		EnvVar:      "IAAS",
 		Destination: &initialDeployArgs.IAAS,
 	},
@@ -143,6 +141,54 @@ var deployFlags = []cli.Flag{
 		Usage: "(optional) Key=Value pair to tag EC2 instances with - Multiple tags can be applied with multiple uses of this flag",
 		Value: &initialDeployArgs.Tags,
 	},
+	cli.StringFlag{
+		Name:        "workers-off-schedule",
+		Usage:       "(optional) Cron expression for when to scale the workers down to --workers-off, eg \"0 19 * * 1-5\". Requires --workers-on-schedule",
+		Destination: &initialDeployArgs.WorkersOffSchedule,
+	},
+	cli.StringFlag{
+		Name:        "workers-on-schedule",
+		Usage:       "(optional) Cron expression for when to scale the workers back up to --workers, eg \"0 7 * * 1-5\". Requires --workers-off-schedule",
+		Destination: &initialDeployArgs.WorkersOnSchedule,
+	},
+	cli.StringFlag{
+		Name:        "schedule-timezone",
+		Usage:       "(optional) Timezone the worker schedules are in, eg Europe/London",
+		Value:       "UTC",
+		Destination: &initialDeployArgs.ScheduleTimezone,
+	},
+	cli.IntFlag{
+		Name:        "workers-off",
+		Usage:       "(optional) Number of Concourse worker instances to run outside of the worker schedule",
+		Value:       1,
+		Destination: &initialDeployArgs.WorkersOff,
+	},
+	cli.StringFlag{
+		Name:        "schedule-tag",
+		Usage:       "(optional) Worker tag the worker schedule jobs run on, so that they do not run on a worker they remove",
+		Destination: &initialDeployArgs.ScheduleTag,
+	},
+	cli.IntFlag{
+		Name:        "autoscale-max-workers",
+		Usage:       "(optional) Adds a job to the self-update pipeline that autoscales the default workers up to this many - 0 removes the job",
+		Destination: &initialDeployArgs.AutoscaleMaxWorkers,
+	},
+	cli.IntFlag{
+		Name:        "autoscale-min-workers",
+		Usage:       "(optional) Number of default workers the autoscale job keeps as a minimum",
+		Value:       1,
+		Destination: &initialDeployArgs.AutoscaleMinWorkers,
+	},
+	cli.StringFlag{
+		Name:        "autoscale-tag",
+		Usage:       "(optional) Worker tag the autoscale job runs on, so that it does not run on a worker it removes",
+		Destination: &initialDeployArgs.AutoscaleTag,
+	},
+	cli.GenericFlag{
+		Name:  "worker-pool",
+		Usage: "(optional) Adds a pool of tagged workers, eg `name=gpu,count=2,size=xlarge,spot=false,tag=gpu,team=ml` - Multiple pools can be added with multiple uses of this flag, `none` removes every pool",
+		Value: &initialDeployArgs.WorkerPools,
+	},
 	cli.StringFlag{
 		Name:        "namespace",
 		Usage:       "(optional) Specify a namespace for deployments in order to group them in a meaningful way",
@@ -185,6 +231,16 @@ var deployFlags = []cli.Flag{
 		EnvVar:      "RDS_SUBNET_RANGE2",
 		Destination: &initialDeployArgs.RDS2CIDR,
 	},
+	cli.StringFlag{
+		Name:        "config",
+		Usage:       "(optional) YAML or JSON file of deploy settings. Flags and environment variables take precedence",
+		Destination: &initialDeployArgs.ConfigFile,
+	},
+	cli.StringFlag{
+		Name:        "from-phase",
+		Usage:       "(optional) Restart the deploy from this phase instead of resuming a deploy that failed. Can be " + strings.Join(deploy.Phases, ", "),
+		Destination: &initialDeployArgs.FromPhase,
+	},
 }
 
 func deployAction(c *cli.Context, deployArgs deploy.Args, provider iaas.Provider) error {
@@ -215,7 +271,7 @@ func deployAction(c *cli.Context, deployArgs deploy.Args, provider iaas.Provider
 		return err
 	}
 
-	return client.Deploy()
+	return client.Deploy(commandContext)
 }
 
 func validateDeployArgs(c *cli.Context, deployArgs deploy.Args) (deploy.Args, error) {
@@ -224,6 +280,14 @@ func validateDeployArgs(c *cli.Context, deployArgs deploy.Args) (deploy.Args, er
 		return deployArgs, fmt.Errorf("failed to mark set Deploy flags: [%v]", err)
 	}
 
+	if deployArgs.ConfigFileIsSet {
+		file, err := deploy.LoadFile(deployArgs.ConfigFile)
+		if err != nil {
+			return deployArgs, fmt.Errorf("failed to load Deploy config file %s: [%v]", deployArgs.ConfigFile, err)
+		}
+		deployArgs.ApplyFile(file)
+	}
+
 	if err = deployArgs.Validate(); err != nil {
 		return deployArgs, fmt.Errorf("failed to validate Deploy flags: [%v]", err)
 	}
@@ -231,11 +295,18 @@ func validateDeployArgs(c *cli.Context, deployArgs deploy.Args) (deploy.Args, er
 	return deployArgs, nil
 }
 
+var numberedZone = regexp.MustCompile(`^\d+$`)
+
 func setZoneAndRegion(providerRegion string, deployArgs deploy.Args) (deploy.Args, error) {
 	if !deployArgs.RegionIsSet {
 		deployArgs.Region = providerRegion
 	}
 
+	// Azure numbers its availability zones within each region, so they cannot be paired with one
+	if numberedZone.MatchString(deployArgs.Zone) {
+		return deployArgs, nil
+	}
+
 	if deployArgs.ZoneIsSet && deployArgs.RegionIsSet {
 		if err := zoneBelongsToRegion(deployArgs.Zone, deployArgs.Region); err != nil {
 			return deployArgs, err
@@ -383,17 +454,27 @@ func validateRDSSubnetSize(cidr *net.IPNet) bool {
 }
 
 func buildClient(name, version string, deployArgs deploy.Args, provider iaas.Provider) (*concourse.Client, error) {
-	versionFile, _ := provider.Choose(iaas.Choice{
-		AWS: resource.AWSVersionFile,
-		GCP: resource.GCPVersionFile,
-	}).([]byte)
+	versionFile, err := loadVersionFile(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	stateStore, err := newStateStore(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	configClient, err := newConfigClient(provider, stateStore, name, deployArgs.Namespace)
+	if err != nil {
+		return nil, err
+	}
 
-	terraformClient, err := terraform.New(provider.IAAS(), terraform.DownloadTerraform(versionFile))
+	terraformClient, err := terraform.New(provider.IAAS(), terraform.DownloadTerraform(versionFile), terraformCache(provider, name, deployArgs.Namespace), terraform.EnsureLock(configClient.EnsureTerraformLock))
 	if err != nil {
 		return nil, err
 	}
 
-	tfInputVarsFactory, err := concourse.NewTFInputVarsFactory(provider)
+	tfInputVarsFactory, err := concourse.NewTFInputVarsFactory(provider, stateStore)
 	if err != nil {
 		return nil, fmt.Errorf("Error creating TFInputVarsFactory [%v]", err)
 	}
@@ -403,9 +484,9 @@ func buildClient(name, version string, deployArgs deploy.Args, provider iaas.Pro
 		terraformClient,
 		tfInputVarsFactory,
 		bosh.New,
-		fly.New,
+		newFlyClient,
 		certs.Generate,
-		config.New(provider, name, deployArgs.Namespace),
+		configClient,
 		&deployArgs,
 		os.Stdout,
 		os.Stderr,
@@ -430,7 +511,7 @@ var deployCmd = cli.Command{
 	Action: func(c *cli.Context) error {
 		deployArgs, err := validateDeployArgs(c, initialDeployArgs)
 		if err != nil {
-			return fmt.Errorf("Error validating args on deploy: [%v]", err)
+			return events.Categorize(fmt.Errorf("Error validating args on deploy: [%v]", err), events.UsageCategory)
 		}
 		iaasName, err := iaas.Validate(deployArgs.IAAS)
 		if err != nil {
@@ -62,6 +62,32 @@ type Args struct {
 	RDS1CIDRIsSet    bool
 	RDS2CIDR         string
 	RDS2CIDRIsSet    bool
+	ConfigFile       string
+	ConfigFileIsSet  bool
+	WorkerPools      WorkerPools
+	WorkerPoolsIsSet bool
+	// WorkersOffSchedule and WorkersOnSchedule are cron expressions for scaling the default
+	// workers down to WorkersOff and back up again
+	WorkersOffSchedule      string
+	WorkersOffScheduleIsSet bool
+	WorkersOnSchedule       string
+	WorkersOnScheduleIsSet  bool
+	ScheduleTimezone        string
+	ScheduleTimezoneIsSet   bool
+	WorkersOff              int
+	WorkersOffIsSet         bool
+	ScheduleTag             string
+	ScheduleTagIsSet        bool
+	// AutoscaleMaxWorkers adds an autoscale job to the self-update pipeline, or removes it when 0
+	AutoscaleMaxWorkers      int
+	AutoscaleMaxWorkersIsSet bool
+	AutoscaleMinWorkers      int
+	AutoscaleMinWorkersIsSet bool
+	AutoscaleTag             string
+	AutoscaleTagIsSet        bool
+	// FromPhase is the phase to restart the deploy from, instead of resuming a deploy that failed
+	FromPhase      string
+	FromPhaseIsSet bool
 }
 
 // MarkSetFlags is marking the IsSet DeployArgs
@@ -117,6 +143,28 @@ func (a *Args) MarkSetFlags(c FlagSetChecker) error {
 				a.RDS1CIDRIsSet = true
 			case "rds-subnet-range2":
 				a.RDS2CIDRIsSet = true
+			case "config":
+				a.ConfigFileIsSet = true
+			case "worker-pool":
+				a.WorkerPoolsIsSet = true
+			case "workers-off-schedule":
+				a.WorkersOffScheduleIsSet = true
+			case "workers-on-schedule":
+				a.WorkersOnScheduleIsSet = true
+			case "schedule-timezone":
+				a.ScheduleTimezoneIsSet = true
+			case "workers-off":
+				a.WorkersOffIsSet = true
+			case "schedule-tag":
+				a.ScheduleTagIsSet = true
+			case "autoscale-max-workers":
+				a.AutoscaleMaxWorkersIsSet = true
+			case "autoscale-min-workers":
+				a.AutoscaleMinWorkersIsSet = true
+			case "autoscale-tag":
+				a.AutoscaleTagIsSet = true
+			case "from-phase":
+				a.FromPhaseIsSet = true
 			default:
 				return fmt.Errorf("flag %q is not supported by deployment flags", f)
 			}
@@ -136,6 +184,9 @@ var WebSizes = []string{"small", "medium", "large", "xlarge", "2xlarge"}
 // AllowedDBSizes contains the valid values for --db-size flag
 var AllowedDBSizes = []string{"small", "medium", "large", "xlarge", "2xlarge", "4xlarge"}
 
+// Phases are the phases of a deploy in the order they run
+var Phases = []string{"terraform", "certs", "create-env", "cloud-config", "stemcell", "databases", "concourse", "pipeline"}
+
 // Validate validates that flag interdependencies
 func (a Args) Validate() error {
 	if !a.IAASIsSet {
@@ -170,6 +221,22 @@ func (a Args) Validate() error {
 		return err
 	}
 
+	if err := a.validateWorkerPools(); err != nil {
+		return err
+	}
+
+	if err := a.validateWorkerSchedule(); err != nil {
+		return err
+	}
+
+	if err := a.validateAutoscale(); err != nil {
+		return err
+	}
+
+	if err := a.validateFromPhase(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -239,6 +306,18 @@ func (a Args) validateNetworkRanges() error {
 	return nil
 }
 
+func (a Args) validateFromPhase() error {
+	if !a.FromPhaseIsSet {
+		return nil
+	}
+	for _, phase := range Phases {
+		if phase == a.FromPhase {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown phase: `%s`. Valid phases are: %v", a.FromPhase, Phases)
+}
+
 func (a Args) validateTags() error {
 	for _, tag := range a.Tags {
 		m, err := regexp.MatchString(`\w+=\w+`, tag)
@@ -182,6 +182,155 @@ func TestDeployArgs_Validate(t *testing.T) {
 			},
 			wantErr:     true,
 			expectedErr: "both --public-subnet-range and --private-subnet-range are required when either is provided",
+		},
+		{
+			name: "Worker pools with tags or a team are valid",
+			modification: func() Args {
+				args := defaultFields
+				args.WorkerPools = WorkerPools{
+					{Name: "gpu", Count: 2, Size: "xlarge", Tags: []string{"gpu"}},
+					{Name: "ml-team", Count: 1, Size: "large", Team: "ml"},
+				}
+				return args
+			},
+			wantErr: false,
+		},
+		{
+			name: "Worker pool names must be unique",
+			modification: func() Args {
+				args := defaultFields
+				args.WorkerPools = WorkerPools{
+					{Name: "gpu", Count: 1, Size: "xlarge", Tags: []string{"gpu"}},
+					{Name: "gpu", Count: 1, Size: "large", Tags: []string{"gpu"}},
+				}
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "worker pool `gpu` is defined more than once",
+		},
+		{
+			name: "Worker pool sizes must be valid",
+			modification: func() Args {
+				args := defaultFields
+				args.WorkerPools = WorkerPools{{Name: "gpu", Count: 1, Size: "huge", Tags: []string{"gpu"}}}
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "unknown worker size for pool `gpu`: `huge`",
+		},
+		{
+			name: "Worker pools need tags or a team",
+			modification: func() Args {
+				args := defaultFields
+				args.WorkerPools = WorkerPools{{Name: "gpu", Count: 1, Size: "xlarge"}}
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "worker pool `gpu` needs at least one tag or a team",
+		},
+		{
+			name: "Worker pool names must be valid instance group names",
+			modification: func() Args {
+				args := defaultFields
+				args.WorkerPools = WorkerPools{{Name: "GPU_pool", Count: 1, Size: "xlarge", Tags: []string{"gpu"}}}
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "worker pool name `GPU_pool` must start with a lowercase letter",
+		},
+		{
+			name: "Worker schedules with a timezone are valid",
+			modification: func() Args {
+				args := defaultFields
+				args.WorkersOffSchedule, args.WorkersOffScheduleIsSet = "0 19 * * 1-5", true
+				args.WorkersOnSchedule, args.WorkersOnScheduleIsSet = "0 7 * * 1-5", true
+				args.ScheduleTimezone = "Europe/London"
+				return args
+			},
+			wantErr: false,
+		},
+		{
+			name: "Both worker schedules are required",
+			modification: func() Args {
+				args := defaultFields
+				args.WorkersOffSchedule, args.WorkersOffScheduleIsSet = "0 19 * * 1-5", true
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "both --workers-off-schedule and --workers-on-schedule are required when either is provided",
+		},
+		{
+			name: "Worker schedules must be cron expressions",
+			modification: func() Args {
+				args := defaultFields
+				args.WorkersOffSchedule, args.WorkersOffScheduleIsSet = "7pm", true
+				args.WorkersOnSchedule, args.WorkersOnScheduleIsSet = "0 7 * * 1-5", true
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "--workers-off-schedule `7pm` is not a cron expression",
+		},
+		{
+			name: "Schedule timezone must be known",
+			modification: func() Args {
+				args := defaultFields
+				args.ScheduleTimezone = "Middle/Earth"
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "unknown --schedule-timezone `Middle/Earth`",
+		},
+		{
+			name: "Autoscale min workers cannot be more than max workers",
+			modification: func() Args {
+				args := defaultFields
+				args.AutoscaleMaxWorkers, args.AutoscaleMaxWorkersIsSet = 2, true
+				args.AutoscaleMinWorkers, args.AutoscaleMinWorkersIsSet = 3, true
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "--autoscale-min-workers cannot be more than --autoscale-max-workers",
+		},
+		{
+			name: "Autoscale cannot be combined with a worker schedule",
+			modification: func() Args {
+				args := defaultFields
+				args.AutoscaleMaxWorkers, args.AutoscaleMaxWorkersIsSet = 4, true
+				args.WorkersOffSchedule, args.WorkersOffScheduleIsSet = "0 19 * * 1-5", true
+				args.WorkersOnSchedule, args.WorkersOnScheduleIsSet = "0 7 * * 1-5", true
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "--autoscale-max-workers cannot be used with a worker schedule, as both scale the default workers",
+		},
+		{
+			name: "Workers off must leave a worker running",
+			modification: func() Args {
+				args := defaultFields
+				args.WorkersOff, args.WorkersOffIsSet = 0, true
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "--workers-off must be at least 1",
+		},
+		{
+			name: "From phase must be a phase of the deploy",
+			modification: func() Args {
+				args := defaultFields
+				args.FromPhase, args.FromPhaseIsSet = "bucket", true
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "unknown phase: `bucket`",
+		},
+		{
+			name: "From phase can be any phase of the deploy",
+			modification: func() Args {
+				args := defaultFields
+				args.FromPhase, args.FromPhaseIsSet = "stemcell", true
+				return args
+			},
+			wantErr: false,
 		}}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,203 @@
+package deploy
+
+import (
+	"io/ioutil"
+	"strings"
+
+	"github.com/EngineerBetter/control-tower/config"
+	"github.com/EngineerBetter/control-tower/iaas"
+	"github.com/asaskevich/govalidator"
+	"gopkg.in/yaml.v2"
+)
+
+// File holds deploy settings loaded with --config. Keys are the names of the equivalent
+// flags, apart from tags and worker-pools which replace repeated uses of --add-tag and
+// --worker-pool. JSON files are also accepted as JSON is valid YAML
+type File struct {
+	IAAS                   *string     `yaml:"iaas,omitempty"`
+	Region                 *string     `yaml:"region,omitempty"`
+	Namespace              *string     `yaml:"namespace,omitempty"`
+	Zone                   *string     `yaml:"zone,omitempty"`
+	Domain                 *string     `yaml:"domain,omitempty"`
+	TLSCert                *string     `yaml:"tls-cert,omitempty"`
+	TLSKey                 *string     `yaml:"tls-key,omitempty"`
+	WorkerCount            *int        `yaml:"workers,omitempty"`
+	WorkerSize             *string     `yaml:"worker-size,omitempty"`
+	WorkerType             *string     `yaml:"worker-type,omitempty"`
+	WebSize                *string     `yaml:"web-size,omitempty"`
+	DBSize                 *string     `yaml:"db-size,omitempty"`
+	Spot                   *bool       `yaml:"spot,omitempty"`
+	EnableGlobalResources  *bool       `yaml:"enable-global-resources,omitempty"`
+	AllowIPs               *string     `yaml:"allow-ips,omitempty"`
+	GithubAuthClientID     *string     `yaml:"github-auth-client-id,omitempty"`
+	GithubAuthClientSecret *string     `yaml:"github-auth-client-secret,omitempty"`
+	Tags                   []string    `yaml:"tags,omitempty"`
+	NetworkCIDR            *string     `yaml:"vpc-network-range,omitempty"`
+	PublicCIDR             *string     `yaml:"public-subnet-range,omitempty"`
+	PrivateCIDR            *string     `yaml:"private-subnet-range,omitempty"`
+	RDS1CIDR               *string     `yaml:"rds-subnet-range1,omitempty"`
+	RDS2CIDR               *string     `yaml:"rds-subnet-range2,omitempty"`
+	WorkerPools            WorkerPools `yaml:"worker-pools,omitempty"`
+	WorkersOffSchedule     *string     `yaml:"workers-off-schedule,omitempty"`
+	WorkersOnSchedule      *string     `yaml:"workers-on-schedule,omitempty"`
+	ScheduleTimezone       *string     `yaml:"schedule-timezone,omitempty"`
+	WorkersOff             *int        `yaml:"workers-off,omitempty"`
+	ScheduleTag            *string     `yaml:"schedule-tag,omitempty"`
+	AutoscaleMaxWorkers    *int        `yaml:"autoscale-max-workers,omitempty"`
+	AutoscaleMinWorkers    *int        `yaml:"autoscale-min-workers,omitempty"`
+	AutoscaleTag           *string     `yaml:"autoscale-tag,omitempty"`
+}
+
+// LoadFile reads deploy settings from path, rejecting unknown keys
+func LoadFile(path string) (File, error) {
+	var f File
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return f, err
+	}
+	err = yaml.UnmarshalStrict(contents, &f)
+	return f, err
+}
+
+// ApplyFile sets any Args that are in f but were not set by a flag or environment variable,
+// marking them as set in the same way as MarkSetFlags
+func (a *Args) ApplyFile(f File) {
+	applyString(&a.IAAS, &a.IAASIsSet, f.IAAS)
+	applyString(&a.Region, &a.RegionIsSet, f.Region)
+	applyString(&a.Namespace, &a.NamespaceIsSet, f.Namespace)
+	applyString(&a.Zone, &a.ZoneIsSet, f.Zone)
+	applyString(&a.Domain, &a.DomainIsSet, f.Domain)
+	applyString(&a.TLSCert, &a.TLSCertIsSet, f.TLSCert)
+	applyString(&a.TLSKey, &a.TLSKeyIsSet, f.TLSKey)
+	applyString(&a.WorkerSize, &a.WorkerSizeIsSet, f.WorkerSize)
+	applyString(&a.WorkerType, &a.WorkerTypeIsSet, f.WorkerType)
+	applyString(&a.WebSize, &a.WebSizeIsSet, f.WebSize)
+	applyString(&a.DBSize, &a.DBSizeIsSet, f.DBSize)
+	applyString(&a.AllowIPs, &a.AllowIPsIsSet, f.AllowIPs)
+	applyString(&a.GithubAuthClientID, &a.GithubAuthClientIDIsSet, f.GithubAuthClientID)
+	applyString(&a.GithubAuthClientSecret, &a.GithubAuthClientSecretIsSet, f.GithubAuthClientSecret)
+	applyString(&a.NetworkCIDR, &a.NetworkCIDRIsSet, f.NetworkCIDR)
+	applyString(&a.PublicCIDR, &a.PublicCIDRIsSet, f.PublicCIDR)
+	applyString(&a.PrivateCIDR, &a.PrivateCIDRIsSet, f.PrivateCIDR)
+	applyString(&a.RDS1CIDR, &a.RDS1CIDRIsSet, f.RDS1CIDR)
+	applyString(&a.RDS2CIDR, &a.RDS2CIDRIsSet, f.RDS2CIDR)
+	applyString(&a.WorkersOffSchedule, &a.WorkersOffScheduleIsSet, f.WorkersOffSchedule)
+	applyString(&a.WorkersOnSchedule, &a.WorkersOnScheduleIsSet, f.WorkersOnSchedule)
+	applyString(&a.ScheduleTimezone, &a.ScheduleTimezoneIsSet, f.ScheduleTimezone)
+	applyString(&a.ScheduleTag, &a.ScheduleTagIsSet, f.ScheduleTag)
+	applyString(&a.AutoscaleTag, &a.AutoscaleTagIsSet, f.AutoscaleTag)
+	applyBool(&a.Spot, &a.SpotIsSet, f.Spot)
+	applyBool(&a.EnableGlobalResources, &a.EnableGlobalResourcesIsSet, f.EnableGlobalResources)
+
+	applyInt(&a.WorkerCount, &a.WorkerCountIsSet, f.WorkerCount)
+	applyInt(&a.WorkersOff, &a.WorkersOffIsSet, f.WorkersOff)
+	applyInt(&a.AutoscaleMaxWorkers, &a.AutoscaleMaxWorkersIsSet, f.AutoscaleMaxWorkers)
+	applyInt(&a.AutoscaleMinWorkers, &a.AutoscaleMinWorkersIsSet, f.AutoscaleMinWorkers)
+	if f.Tags != nil && !a.TagsIsSet {
+		a.Tags = f.Tags
+		a.TagsIsSet = true
+	}
+	if f.WorkerPools != nil && !a.WorkerPoolsIsSet {
+		a.WorkerPools = f.WorkerPools
+		a.WorkerPoolsIsSet = true
+	}
+
+	a.GithubAuthIsSet = a.GithubAuthClientIDIsSet && a.GithubAuthClientSecretIsSet
+}
+
+func applyString(value *string, isSet *bool, fileValue *string) {
+	if fileValue != nil && !*isSet {
+		*value = *fileValue
+		*isSet = true
+	}
+}
+
+func applyInt(value *int, isSet *bool, fileValue *int) {
+	if fileValue != nil && !*isSet {
+		*value = *fileValue
+		*isSet = true
+	}
+}
+
+func applyBool(value *bool, isSet *bool, fileValue *bool) {
+	if fileValue != nil && !*isSet {
+		*value = *fileValue
+		*isSet = true
+	}
+}
+
+// NewFileFromConfig builds deploy settings that reproduce an existing deployment. Secrets and
+// certificates are left out so that the file can be checked in
+func NewFileFromConfig(conf config.Config) File {
+	f := File{
+		IAAS:                  stringOrNil(conf.IAAS),
+		Region:                stringOrNil(conf.Region),
+		Namespace:             stringOrNil(conf.Namespace),
+		Zone:                  stringOrNil(conf.AvailabilityZone),
+		WorkerSize:            stringOrNil(conf.ConcourseWorkerSize),
+		WebSize:               stringOrNil(conf.ConcourseWebSize),
+		EnableGlobalResources: &conf.EnableGlobalResources,
+		AllowIPs:              stringOrNil(strings.Replace(conf.AllowIPs, `"`, "", -1)),
+		GithubAuthClientID:    stringOrNil(conf.GithubClientID),
+		PublicCIDR:            stringOrNil(conf.PublicCIDR),
+		PrivateCIDR:           stringOrNil(conf.PrivateCIDR),
+		WorkerPools:           conf.WorkerPools,
+	}
+
+	if !govalidator.IsIPv4(conf.Domain) {
+		f.Domain = stringOrNil(conf.Domain)
+	}
+	if conf.ConcourseWorkerCount > 0 {
+		f.WorkerCount = &conf.ConcourseWorkerCount
+	}
+	if schedule := conf.WorkerSchedule; schedule != nil {
+		f.WorkersOffSchedule = stringOrNil(schedule.OffCron)
+		f.WorkersOnSchedule = stringOrNil(schedule.OnCron)
+		f.ScheduleTimezone = stringOrNil(schedule.Timezone)
+		f.WorkersOff = &schedule.OffWorkers
+		f.ScheduleTag = stringOrNil(schedule.Tag)
+		// outside of the schedule the deployment may have been scaled down
+		f.WorkerCount = &schedule.OnWorkers
+	}
+	if autoscale := conf.Autoscale; autoscale != nil {
+		f.AutoscaleMaxWorkers = &autoscale.MaxWorkers
+		f.AutoscaleMinWorkers = &autoscale.MinWorkers
+		f.AutoscaleTag = stringOrNil(autoscale.Tag)
+	}
+	spot := conf.VMProvisioningType != config.ON_DEMAND
+	f.Spot = &spot
+
+	for _, tag := range conf.Tags {
+		if !strings.HasPrefix(tag, "control-tower-version") {
+			f.Tags = append(f.Tags, tag)
+		}
+	}
+
+	dbSizes := iaas.GCPDBSizes
+	name, _ := iaas.Validate(conf.IAAS)
+	if name == iaas.Azure {
+		dbSizes = iaas.AzureDBSizes
+	}
+	if name == iaas.AWS {
+		dbSizes = iaas.AWSDBSizes
+		f.WorkerType = stringOrNil(conf.WorkerType)
+		f.NetworkCIDR = stringOrNil(conf.NetworkCIDR)
+		f.RDS1CIDR = stringOrNil(conf.RDS1CIDR)
+		f.RDS2CIDR = stringOrNil(conf.RDS2CIDR)
+	}
+	for size, instanceClass := range dbSizes {
+		if instanceClass == conf.RDSInstanceClass {
+			dbSize := size
+			f.DBSize = &dbSize
+		}
+	}
+
+	return f
+}
+
+func stringOrNil(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
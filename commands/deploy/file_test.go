@@ -0,0 +1,192 @@
+package deploy_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	. "github.com/EngineerBetter/control-tower/commands/deploy"
+	"github.com/EngineerBetter/control-tower/config"
+)
+
+func writeFile(t *testing.T, contents string) string {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "ct.yml")
+	if err = ioutil.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFile(t *testing.T) {
+	path := writeFile(t, `
+iaas: AWS
+workers: 3
+worker-size: large
+spot: false
+tags:
+- team=ci
+`)
+
+	f, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if *f.IAAS != "AWS" || *f.WorkerCount != 3 || *f.WorkerSize != "large" || *f.Spot || !reflect.DeepEqual(f.Tags, []string{"team=ci"}) {
+		t.Errorf("LoadFile() = %+v", f)
+	}
+}
+
+func TestLoadFileJSON(t *testing.T) {
+	path := writeFile(t, `{"iaas": "GCP", "workers": 2}`)
+
+	f, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if *f.IAAS != "GCP" || *f.WorkerCount != 2 {
+		t.Errorf("LoadFile() = %+v", f)
+	}
+}
+
+func TestLoadFileWorkerPools(t *testing.T) {
+	path := writeFile(t, `
+worker-pools:
+- name: gpu
+  size: 2xlarge
+  spot: false
+  tags: [gpu]
+- name: ml
+  count: 3
+  team: ml
+`)
+
+	f, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	want := WorkerPools{
+		{Name: "gpu", Count: 1, Size: "2xlarge", Spot: false, Tags: []string{"gpu"}},
+		{Name: "ml", Count: 3, Size: "xlarge", Spot: true, Team: "ml"},
+	}
+	if !reflect.DeepEqual(f.WorkerPools, want) {
+		t.Errorf("LoadFile() worker pools = %+v, want %+v", f.WorkerPools, want)
+	}
+
+	path = writeFile(t, "worker-pools:\n- name: gpu\n  colour: red\n")
+	if _, err = LoadFile(path); err == nil {
+		t.Errorf("LoadFile() expected an error for an unknown worker pool key")
+	}
+}
+
+func TestLoadFileUnknownKey(t *testing.T) {
+	path := writeFile(t, "wokers: 3\n")
+
+	_, err := LoadFile(path)
+	if err == nil {
+		t.Errorf("LoadFile() expected an error for an unknown key")
+	}
+}
+
+func TestApplyFile(t *testing.T) {
+	iaasName := "AWS"
+	workers := 3
+	workerSize := "large"
+	spot := false
+	githubID := "id"
+	f := File{
+		IAAS:               &iaasName,
+		WorkerCount:        &workers,
+		WorkerSize:         &workerSize,
+		Spot:               &spot,
+		GithubAuthClientID: &githubID,
+		Tags:               []string{"team=ci"},
+	}
+
+	args := Args{
+		WorkerCount:                 1,
+		WorkerSize:                  "2xlarge",
+		WorkerSizeIsSet:             true,
+		Spot:                        true,
+		GithubAuthClientSecret:      "secret",
+		GithubAuthClientSecretIsSet: true,
+	}
+	args.ApplyFile(f)
+
+	if args.IAAS != "AWS" || !args.IAASIsSet {
+		t.Errorf("expected iaas to be set from file, got %q %v", args.IAAS, args.IAASIsSet)
+	}
+	if args.WorkerCount != 3 || !args.WorkerCountIsSet {
+		t.Errorf("expected workers to be set from file, got %d %v", args.WorkerCount, args.WorkerCountIsSet)
+	}
+	if args.WorkerSize != "2xlarge" {
+		t.Errorf("expected flag to take precedence over file, got worker size %q", args.WorkerSize)
+	}
+	if args.Spot || !args.SpotIsSet {
+		t.Errorf("expected spot to be set to false from file, got %v %v", args.Spot, args.SpotIsSet)
+	}
+	if !args.GithubAuthIsSet {
+		t.Errorf("expected github auth to be set from a file client id and flag client secret")
+	}
+	if !reflect.DeepEqual([]string(args.Tags), []string{"team=ci"}) || !args.TagsIsSet {
+		t.Errorf("expected tags to be set from file, got %v %v", args.Tags, args.TagsIsSet)
+	}
+	if args.RegionIsSet || args.DomainIsSet {
+		t.Errorf("expected settings missing from the file to remain unset")
+	}
+}
+
+func TestNewFileFromConfig(t *testing.T) {
+	conf := config.Config{
+		AllowIPs:             `"10.0.0.0/8", "1.2.3.4/32"`,
+		AvailabilityZone:     "eu-west-1a",
+		ConcourseWebSize:     "small",
+		ConcourseWorkerCount: 2,
+		ConcourseWorkerSize:  "xlarge",
+		ConcourseKey:         "key",
+		Domain:               "1.2.3.4",
+		GithubClientID:       "id",
+		GithubClientSecret:   "secret",
+		IAAS:                 "AWS",
+		NetworkCIDR:          "10.0.0.0/16",
+		PrivateCIDR:          "10.0.1.0/24",
+		PublicCIDR:           "10.0.0.0/24",
+		RDS1CIDR:             "10.0.4.0/24",
+		RDS2CIDR:             "10.0.5.0/24",
+		RDSInstanceClass:     "db.m4.large",
+		Region:               "eu-west-1",
+		Tags:                 []string{"control-tower-version=1.0.0", "team=ci"},
+		VMProvisioningType:   config.ON_DEMAND,
+		WorkerType:           "m5",
+	}
+
+	f := NewFileFromConfig(conf)
+
+	if *f.AllowIPs != "10.0.0.0/8, 1.2.3.4/32" {
+		t.Errorf("AllowIPs = %q", *f.AllowIPs)
+	}
+	if f.Domain != nil {
+		t.Errorf("expected IP domain to be left out, got %q", *f.Domain)
+	}
+	if *f.DBSize != "large" {
+		t.Errorf("DBSize = %q, want large", *f.DBSize)
+	}
+	if *f.Spot {
+		t.Errorf("expected on-demand deployment to have spot false")
+	}
+	if *f.WorkerType != "m5" || *f.RDS1CIDR != "10.0.4.0/24" {
+		t.Errorf("expected AWS only settings to be set, got %+v", f)
+	}
+	if !reflect.DeepEqual(f.Tags, []string{"team=ci"}) {
+		t.Errorf("Tags = %v, want [team=ci]", f.Tags)
+	}
+	if f.GithubAuthClientSecret != nil || f.TLSKey != nil {
+		t.Errorf("expected secrets to be left out, got %+v", f)
+	}
+}
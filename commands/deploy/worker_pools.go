@@ -0,0 +1,153 @@
+package deploy
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/EngineerBetter/control-tower/config"
+)
+
+var workerPoolNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// WorkerPools holds the worker pools given with repeated uses of --worker-pool. Each use
+// takes comma separated key=value pairs, eg `name=gpu,count=2,size=xlarge,spot=false,tag=gpu,team=ml`.
+// tag may be given more than once. `--worker-pool none` removes every pool
+type WorkerPools []config.WorkerPool
+
+// noWorkerPools is the --worker-pool value that removes every pool
+const noWorkerPools = "none"
+
+func defaultWorkerPool() config.WorkerPool {
+	return config.WorkerPool{
+		Count: 1,
+		Size:  "xlarge",
+		Spot:  true,
+	}
+}
+
+// Set parses a single --worker-pool value and appends it
+func (w *WorkerPools) Set(value string) error {
+	// A non-nil empty list can only have come from `--worker-pool none`
+	removedAll := *w != nil && len(*w) == 0
+	if value == noWorkerPools {
+		if len(*w) > 0 {
+			return fmt.Errorf("`--worker-pool %s` cannot be combined with other worker pools", noWorkerPools)
+		}
+		*w = WorkerPools{}
+		return nil
+	}
+	if removedAll {
+		return fmt.Errorf("`--worker-pool %s` cannot be combined with other worker pools", noWorkerPools)
+	}
+
+	pool := defaultWorkerPool()
+
+	for _, field := range strings.Split(value, ",") {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("`%v` is not in the format `key=value`", field)
+		}
+		key, v := parts[0], parts[1]
+
+		switch key {
+		case "name":
+			pool.Name = v
+		case "count":
+			count, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("worker pool count `%v` is not a number", v)
+			}
+			pool.Count = count
+		case "size":
+			pool.Size = v
+		case "spot", "preemptible":
+			spot, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("worker pool %s `%v` is not true or false", key, v)
+			}
+			pool.Spot = spot
+		case "tag":
+			pool.Tags = append(pool.Tags, v)
+		case "team":
+			pool.Team = v
+		default:
+			return fmt.Errorf("unknown worker pool key `%v`", key)
+		}
+	}
+
+	*w = append(*w, pool)
+	return nil
+}
+
+// String returns the worker pool names
+func (w *WorkerPools) String() string {
+	var names []string
+	for _, pool := range *w {
+		names = append(names, pool.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+// UnmarshalYAML reads the worker-pools list of a settings file, giving each pool the same
+// defaults as --worker-pool
+func (w *WorkerPools) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var pools []filePool
+	if err := unmarshal(&pools); err != nil {
+		return err
+	}
+
+	*w = make(WorkerPools, 0, len(pools))
+	for _, pool := range pools {
+		*w = append(*w, config.WorkerPool(pool))
+	}
+	return nil
+}
+
+type filePool config.WorkerPool
+
+func (p *filePool) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*p = filePool(defaultWorkerPool())
+	return unmarshal((*config.WorkerPool)(p))
+}
+
+func (a Args) validateWorkerPools() error {
+	names := map[string]bool{}
+	for _, pool := range a.WorkerPools {
+		if !workerPoolNameRegexp.MatchString(pool.Name) || pool.Name == noWorkerPools {
+			return fmt.Errorf("worker pool name `%s` must start with a lowercase letter and contain only lowercase letters, numbers and hyphens, and cannot be `%s`", pool.Name, noWorkerPools)
+		}
+		if names[pool.Name] {
+			return fmt.Errorf("worker pool `%s` is defined more than once", pool.Name)
+		}
+		names[pool.Name] = true
+
+		if pool.Count < 1 {
+			return fmt.Errorf("minimum number of workers in pool `%s` is 1", pool.Name)
+		}
+		if !validWorkerSize(pool.Size) {
+			return fmt.Errorf("unknown worker size for pool `%s`: `%s`. Valid sizes are: %v", pool.Name, pool.Size, WorkerSizes)
+		}
+		if len(pool.Tags) == 0 && pool.Team == "" {
+			return fmt.Errorf("worker pool `%s` needs at least one tag or a team, otherwise it would behave like the default workers", pool.Name)
+		}
+		for _, tag := range pool.Tags {
+			if tag == "" {
+				return errors.New("worker pool tags cannot be empty")
+			}
+		}
+	}
+
+	return nil
+}
+
+func validWorkerSize(size string) bool {
+	for _, s := range WorkerSizes {
+		if s == size {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package deploy_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/EngineerBetter/control-tower/commands/deploy"
+	"github.com/EngineerBetter/control-tower/config"
+)
+
+func TestWorkerPools_Set(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		want        config.WorkerPool
+		expectedErr string
+	}{
+		{
+			name:  "all keys",
+			value: "name=gpu,count=2,size=2xlarge,spot=false,tag=gpu,tag=cuda,team=ml",
+			want:  config.WorkerPool{Name: "gpu", Count: 2, Size: "2xlarge", Spot: false, Tags: []string{"gpu", "cuda"}, Team: "ml"},
+		},
+		{
+			name:  "defaults",
+			value: "name=gpu,tag=gpu",
+			want:  config.WorkerPool{Name: "gpu", Count: 1, Size: "xlarge", Spot: true, Tags: []string{"gpu"}},
+		},
+		{
+			name:  "preemptible is an alias for spot",
+			value: "name=gpu,preemptible=false,tag=gpu",
+			want:  config.WorkerPool{Name: "gpu", Count: 1, Size: "xlarge", Spot: false, Tags: []string{"gpu"}},
+		},
+		{
+			name:        "unknown key",
+			value:       "name=gpu,colour=red",
+			expectedErr: "unknown worker pool key `colour`",
+		},
+		{
+			name:        "count is not a number",
+			value:       "name=gpu,count=two",
+			expectedErr: "worker pool count `two` is not a number",
+		},
+		{
+			name:        "not key value",
+			value:       "gpu",
+			expectedErr: "`gpu` is not in the format `key=value`",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pools WorkerPools
+			err := pools.Set(tt.value)
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Fatalf("WorkerPools.Set() error = %v, want %v", err, tt.expectedErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WorkerPools.Set() error = %v", err)
+			}
+			if !reflect.DeepEqual(pools, WorkerPools{tt.want}) {
+				t.Errorf("WorkerPools.Set() = %#v, want %#v", pools, WorkerPools{tt.want})
+			}
+		})
+	}
+}
+
+func TestWorkerPools_SetNone(t *testing.T) {
+	var pools WorkerPools
+	if err := pools.Set("none"); err != nil {
+		t.Fatalf("WorkerPools.Set() error = %v", err)
+	}
+	if pools == nil || len(pools) != 0 {
+		t.Errorf("WorkerPools.Set(none) = %#v, want an empty list of pools", pools)
+	}
+
+	wantErr := "`--worker-pool none` cannot be combined with other worker pools"
+	if err := pools.Set("name=gpu,tag=gpu"); err == nil || err.Error() != wantErr {
+		t.Errorf("WorkerPools.Set() after none error = %v, want %v", err, wantErr)
+	}
+
+	pools = nil
+	if err := pools.Set("name=gpu,tag=gpu"); err != nil {
+		t.Fatalf("WorkerPools.Set() error = %v", err)
+	}
+	if err := pools.Set("none"); err == nil || err.Error() != wantErr {
+		t.Errorf("WorkerPools.Set(none) after a pool error = %v, want %v", err, wantErr)
+	}
+}
@@ -0,0 +1,69 @@
+package deploy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RemovesWorkerSchedule is true if the user has passed empty schedules to turn off scheduled
+// scaling of the workers
+func (a Args) RemovesWorkerSchedule() bool {
+	return a.WorkersOffScheduleIsSet && a.WorkersOnScheduleIsSet && a.WorkersOffSchedule == "" && a.WorkersOnSchedule == ""
+}
+
+func (a Args) validateWorkerSchedule() error {
+	if a.WorkersOffScheduleIsSet != a.WorkersOnScheduleIsSet || (a.WorkersOffSchedule == "") != (a.WorkersOnSchedule == "") {
+		return errors.New("both --workers-off-schedule and --workers-on-schedule are required when either is provided")
+	}
+
+	if a.WorkersOffSchedule != "" {
+		if err := validateCron("--workers-off-schedule", a.WorkersOffSchedule); err != nil {
+			return err
+		}
+		if err := validateCron("--workers-on-schedule", a.WorkersOnSchedule); err != nil {
+			return err
+		}
+	}
+
+	if _, err := time.LoadLocation(a.ScheduleTimezone); err != nil {
+		return fmt.Errorf("unknown --schedule-timezone `%s`: [%v]", a.ScheduleTimezone, err)
+	}
+
+	if a.WorkersOffIsSet && a.WorkersOff < 1 {
+		return errors.New("--workers-off must be at least 1 so that there is a worker to run the job that scales the workers back up")
+	}
+
+	return nil
+}
+
+// RemovesAutoscale is true if the user has passed --autoscale-max-workers 0 to remove the autoscale job
+func (a Args) RemovesAutoscale() bool {
+	return a.AutoscaleMaxWorkersIsSet && a.AutoscaleMaxWorkers == 0
+}
+
+func (a Args) validateAutoscale() error {
+	if a.AutoscaleMaxWorkers < 0 {
+		return errors.New("--autoscale-max-workers cannot be negative")
+	}
+	if a.AutoscaleMinWorkersIsSet && a.AutoscaleMinWorkers < 1 {
+		return errors.New("--autoscale-min-workers must be at least 1 so that there is a worker to run the autoscale job")
+	}
+	if a.AutoscaleMaxWorkers > 0 && a.AutoscaleMinWorkers > a.AutoscaleMaxWorkers {
+		return errors.New("--autoscale-min-workers cannot be more than --autoscale-max-workers")
+	}
+	if a.AutoscaleMaxWorkers > 0 && a.WorkersOffSchedule != "" {
+		return errors.New("--autoscale-max-workers cannot be used with a worker schedule, as both scale the default workers")
+	}
+	return nil
+}
+
+// validateCron checks for the five fields of a cron expression, leaving the values
+// themselves to be checked by the cron resource
+func validateCron(flag, expression string) error {
+	if len(strings.Fields(expression)) != 5 {
+		return fmt.Errorf("%s `%s` is not a cron expression of the form `minute hour day-of-month month day-of-week`", flag, expression)
+	}
+	return nil
+}
@@ -115,12 +115,37 @@ func Test_setZoneAndRegion(t *testing.T) {
 			providerRegion: "eu-west-1",
 			expectedRegion: "us-east-1",
 		},
+		{
+			name: "numbered Azure zones are accepted in any region",
+			args: deploy.Args{
+				IAAS:        "Azure",
+				Region:      "uksouth",
+				RegionIsSet: true,
+				Zone:        "2",
+				ZoneIsSet:   true,
+			},
+			providerRegion: "westeurope",
+			expectedRegion: "uksouth",
+		},
+		{
+			name: "a zone must belong to the given region",
+			args: deploy.Args{
+				IAAS:        "GCP",
+				Region:      "europe-west1",
+				RegionIsSet: true,
+				Zone:        "us-east1-b",
+				ZoneIsSet:   true,
+			},
+			providerRegion: "europe-west1",
+			expectedRegion: "europe-west1",
+			wantErr:        true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			actual, err := setZoneAndRegion(tt.providerRegion, tt.args)
 
-			if err == nil && tt.wantErr {
+			if (err != nil) != tt.wantErr {
 				t.Errorf("setZoneAndRegion() error = %v, wantErr %v", err, tt.wantErr)
 			}
 
@@ -9,10 +9,8 @@ import (
 	"github.com/EngineerBetter/control-tower/certs"
 	"github.com/EngineerBetter/control-tower/commands/destroy"
 	"github.com/EngineerBetter/control-tower/concourse"
-	"github.com/EngineerBetter/control-tower/config"
-	"github.com/EngineerBetter/control-tower/fly"
+	"github.com/EngineerBetter/control-tower/events"
 	"github.com/EngineerBetter/control-tower/iaas"
-	"github.com/EngineerBetter/control-tower/resource"
 	"github.com/EngineerBetter/control-tower/terraform"
 	"github.com/EngineerBetter/control-tower/util"
 
@@ -31,7 +29,7 @@ var destroyFlags = []cli.Flag{
 	},
 	cli.StringFlag{
 		Name:        "iaas",
-		Usage:       "(required) IAAS, can be AWS or GCP",
+		Usage:       "(required) IAAS, can be AWS, GCP or Azure",
 		EnvVar:      "IAAS",
 		Destination: &initialDestroyArgs.IAAS,
 	},
@@ -67,7 +65,7 @@ func destroyAction(c *cli.Context, destroyArgs destroy.Args, provider iaas.Provi
 	if err != nil {
 		return err
 	}
-	return client.Destroy()
+	return client.Destroy(commandContext)
 }
 
 func validateDestroyArgs(c *cli.Context, destroyArgs destroy.Args) (destroy.Args, error) {
@@ -84,17 +82,27 @@ func validateDestroyArgs(c *cli.Context, destroyArgs destroy.Args) (destroy.Args
 }
 
 func buildDestroyClient(name, version string, destroyArgs destroy.Args, provider iaas.Provider) (*concourse.Client, error) {
-	versionFile, _ := provider.Choose(iaas.Choice{
-		AWS: resource.AWSVersionFile,
-		GCP: resource.GCPVersionFile,
-	}).([]byte)
+	versionFile, err := loadVersionFile(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	stateStore, err := newStateStore(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	configClient, err := newConfigClient(provider, stateStore, name, destroyArgs.Namespace)
+	if err != nil {
+		return nil, err
+	}
 
-	terraformClient, err := terraform.New(provider.IAAS(), terraform.DownloadTerraform(versionFile))
+	terraformClient, err := terraform.New(provider.IAAS(), terraform.DownloadTerraform(versionFile), terraformCache(provider, name, destroyArgs.Namespace), terraform.EnsureLock(configClient.EnsureTerraformLock))
 	if err != nil {
 		return nil, err
 	}
 
-	tfInputVarsFactory, err := concourse.NewTFInputVarsFactory(provider)
+	tfInputVarsFactory, err := concourse.NewTFInputVarsFactory(provider, stateStore)
 	if err != nil {
 		return nil, fmt.Errorf("Error creating TFInputVarsFactory [%v]", err)
 	}
@@ -104,9 +112,9 @@ func buildDestroyClient(name, version string, destroyArgs destroy.Args, provider
 		terraformClient,
 		tfInputVarsFactory,
 		bosh.New,
-		fly.New,
+		newFlyClient,
 		certs.Generate,
-		config.New(provider, name, destroyArgs.Namespace),
+		configClient,
 		nil,
 		os.Stdout,
 		os.Stderr,
@@ -131,7 +139,7 @@ var destroyCmd = cli.Command{
 	Action: func(c *cli.Context) error {
 		destroyArgs, err := validateDestroyArgs(c, initialDestroyArgs)
 		if err != nil {
-			return fmt.Errorf("Error validating args on destroy: [%v]", err)
+			return events.Categorize(fmt.Errorf("Error validating args on destroy: [%v]", err), events.UsageCategory)
 		}
 		iaasName, err := iaas.Validate(destroyArgs.IAAS)
 		if err != nil {
@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/EngineerBetter/control-tower/commands/encrypt"
+	"github.com/EngineerBetter/control-tower/events"
+	"github.com/EngineerBetter/control-tower/iaas"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+var initialEncryptArgs encrypt.Args
+
+var encryptFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:        "region",
+		Usage:       "(optional) AWS region",
+		EnvVar:      "AWS_REGION",
+		Destination: &initialEncryptArgs.Region,
+	},
+	cli.StringFlag{
+		Name:        "iaas",
+		Usage:       "(required) IAAS, can be AWS, GCP or Azure",
+		EnvVar:      "IAAS",
+		Destination: &initialEncryptArgs.IAAS,
+	},
+	cli.StringFlag{
+		Name:        "namespace",
+		Usage:       "(optional) Specify a namespace for deployments in order to group them in a meaningful way",
+		EnvVar:      "NAMESPACE",
+		Destination: &initialEncryptArgs.Namespace,
+	},
+	cli.BoolFlag{
+		Name:        "keep-history",
+		Usage:       "(optional) Keep the earlier, possibly unencrypted, versions of the files instead of deleting them",
+		Destination: &initialEncryptArgs.KeepHistory,
+	},
+}
+
+func encryptAction(c *cli.Context, encryptArgs encrypt.Args, provider iaas.Provider) error {
+	name := c.Args().Get(0)
+	if name == "" {
+		return errors.New("Usage is `control-tower --encryption <provider> encrypt <name>`")
+	}
+
+	if encryptionProvider == "" {
+		return errors.New("--encryption must be set to choose the key to encrypt with")
+	}
+
+	version := c.App.Version
+
+	client, err := buildExistingDeploymentClient(name, version, encryptArgs.Namespace, provider)
+	if err != nil {
+		return err
+	}
+
+	return client.EncryptAssets(commandContext, encryptArgs)
+}
+
+func validateEncryptArgs(c *cli.Context, encryptArgs encrypt.Args) (encrypt.Args, error) {
+	err := encryptArgs.MarkSetFlags(c)
+	if err != nil {
+		return encryptArgs, fmt.Errorf("failed to mark set Encrypt flags: [%v]", err)
+	}
+
+	if err = encryptArgs.Validate(); err != nil {
+		return encryptArgs, fmt.Errorf("failed to validate Encrypt flags: [%v]", err)
+	}
+
+	return encryptArgs, nil
+}
+
+var encryptCmd = cli.Command{
+	Name:      "encrypt",
+	Usage:     "Encrypts the config and state files of an existing deployment in place",
+	ArgsUsage: "<name>",
+	Flags:     encryptFlags,
+	Action: func(c *cli.Context) error {
+		encryptArgs, err := validateEncryptArgs(c, initialEncryptArgs)
+		if err != nil {
+			return events.Categorize(fmt.Errorf("Error validating args on encrypt: [%v]", err), events.UsageCategory)
+		}
+		iaasName, err := iaas.Validate(encryptArgs.IAAS)
+		if err != nil {
+			return fmt.Errorf("Error mapping to supported IAASes on encrypt: [%v]", err)
+		}
+		provider, err := iaas.New(iaasName, encryptArgs.Region)
+		if err != nil {
+			return fmt.Errorf("Error creating IAAS provider on encrypt: [%v]", err)
+		}
+		return encryptAction(c, encryptArgs, provider)
+	},
+}
@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/EngineerBetter/control-tower/bosh"
+	"github.com/EngineerBetter/control-tower/commands/history"
+	"github.com/EngineerBetter/control-tower/concourse"
+	"github.com/EngineerBetter/control-tower/events"
+	"github.com/EngineerBetter/control-tower/iaas"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var initialHistoryArgs history.Args
+
+var historyFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:        "region",
+		Usage:       "(optional) AWS region",
+		EnvVar:      "AWS_REGION",
+		Destination: &initialHistoryArgs.Region,
+	},
+	cli.StringFlag{
+		Name:        "iaas",
+		Usage:       "(required) IAAS, can be AWS, GCP or Azure",
+		EnvVar:      "IAAS",
+		Destination: &initialHistoryArgs.IAAS,
+	},
+	cli.StringFlag{
+		Name:        "namespace",
+		Usage:       "(optional) Specify a namespace for deployments in order to group them in a meaningful way",
+		EnvVar:      "NAMESPACE",
+		Destination: &initialHistoryArgs.Namespace,
+	},
+	cli.BoolFlag{
+		Name:        "json",
+		Usage:       "(optional) Output as json",
+		EnvVar:      "JSON",
+		Destination: &initialHistoryArgs.JSON,
+	},
+}
+
+func historyAction(c *cli.Context, historyArgs history.Args, provider iaas.Provider) error {
+	name := c.Args().Get(0)
+	if name == "" {
+		return errors.New("Usage is `control-tower history <name>`")
+	}
+
+	version := c.App.Version
+
+	client, err := buildExistingDeploymentClient(name, version, historyArgs.Namespace, provider)
+	if err != nil {
+		return err
+	}
+
+	revisions, err := client.History()
+	if err != nil {
+		return err
+	}
+
+	if historyArgs.JSON {
+		return json.NewEncoder(os.Stdout).Encode(revisions)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "REVISION\tWRITTEN\tVERSION\tDIRECTOR STATE\tDIRECTOR CREDS")
+	for _, r := range revisions {
+		number := fmt.Sprintf("%d", r.Number)
+		if r.Current {
+			number += " (current)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			number,
+			r.Time.Format("2006-01-02 15:04:05 MST"),
+			r.Version,
+			revisionFileWritten(r, bosh.StateFilename),
+			revisionFileWritten(r, bosh.CredsFilename),
+		)
+	}
+	return w.Flush()
+}
+
+// revisionFileWritten describes when the version of filename in a revision was written
+func revisionFileWritten(r concourse.Revision, filename string) string {
+	version, ok := r.Files[filename]
+	if !ok {
+		return "-"
+	}
+	return version.LastModified.Format(time.RFC3339)
+}
+
+func validateHistoryArgs(c *cli.Context, historyArgs history.Args) (history.Args, error) {
+	err := historyArgs.MarkSetFlags(c)
+	if err != nil {
+		return historyArgs, fmt.Errorf("failed to mark set History flags: [%v]", err)
+	}
+
+	if err = historyArgs.Validate(); err != nil {
+		return historyArgs, fmt.Errorf("failed to validate History flags: [%v]", err)
+	}
+
+	return historyArgs, nil
+}
+
+var historyCmd = cli.Command{
+	Name:      "history",
+	Usage:     "Lists the previous versions of the config, director state and creds of a deployment",
+	ArgsUsage: "<name>",
+	Flags:     historyFlags,
+	Action: func(c *cli.Context) error {
+		historyArgs, err := validateHistoryArgs(c, initialHistoryArgs)
+		if err != nil {
+			return events.Categorize(fmt.Errorf("Error validating args on history: [%v]", err), events.UsageCategory)
+		}
+		iaasName, err := iaas.Validate(historyArgs.IAAS)
+		if err != nil {
+			return fmt.Errorf("Error mapping to supported IAASes on history: [%v]", err)
+		}
+		provider, err := iaas.New(iaasName, historyArgs.Region)
+		if err != nil {
+			return fmt.Errorf("Error creating IAAS provider on history: [%v]", err)
+		}
+		return historyAction(c, historyArgs, provider)
+	},
+}
@@ -10,10 +10,8 @@ import (
 	"github.com/EngineerBetter/control-tower/certs"
 	"github.com/EngineerBetter/control-tower/commands/info"
 	"github.com/EngineerBetter/control-tower/concourse"
-	"github.com/EngineerBetter/control-tower/config"
-	"github.com/EngineerBetter/control-tower/fly"
+	"github.com/EngineerBetter/control-tower/events"
 	"github.com/EngineerBetter/control-tower/iaas"
-	"github.com/EngineerBetter/control-tower/resource"
 	"github.com/EngineerBetter/control-tower/terraform"
 	"github.com/EngineerBetter/control-tower/util"
 	"gopkg.in/urfave/cli.v1"
@@ -46,7 +44,7 @@ var infoFlags = []cli.Flag{
 	},
 	cli.StringFlag{
 		Name:        "iaas",
-		Usage:       "(required) IAAS, can be AWS or GCP",
+		Usage:       "(required) IAAS, can be AWS, GCP or Azure",
 		EnvVar:      "IAAS",
 		Destination: &initialInfoArgs.IAAS,
 	},
@@ -70,13 +68,14 @@ func infoAction(c *cli.Context, infoArgs info.Args, provider iaas.Provider) erro
 	if err != nil {
 		return err
 	}
-	i, err := client.FetchInfo()
+	i, err := client.FetchInfo(commandContext)
 	if err != nil {
 		return err
 	}
+	// Only --env prints secrets, as its output is meant to be evaluated by a shell rather than read
 	switch {
 	case infoArgs.JSON:
-		return json.NewEncoder(os.Stdout).Encode(i)
+		return json.NewEncoder(os.Stdout).Encode(i.Redacted())
 	case infoArgs.Env:
 		env, err := i.Env()
 		if err != nil {
@@ -88,7 +87,7 @@ func infoAction(c *cli.Context, infoArgs info.Args, provider iaas.Provider) erro
 		os.Stdout.WriteString(i.CertExpiry)
 		return nil
 	default:
-		_, err := fmt.Fprint(os.Stdout, i)
+		_, err := fmt.Fprint(os.Stdout, i.Redacted())
 		return err
 	}
 }
@@ -107,17 +106,27 @@ func validateInfoArgs(c *cli.Context, infoArgs info.Args) (info.Args, error) {
 }
 
 func buildInfoClient(name, version string, infoArgs info.Args, provider iaas.Provider) (*concourse.Client, error) {
-	versionFile, _ := provider.Choose(iaas.Choice{
-		AWS: resource.AWSVersionFile,
-		GCP: resource.GCPVersionFile,
-	}).([]byte)
+	versionFile, err := loadVersionFile(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	stateStore, err := newStateStore(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	configClient, err := newConfigClient(provider, stateStore, name, infoArgs.Namespace)
+	if err != nil {
+		return nil, err
+	}
 
-	terraformClient, err := terraform.New(provider.IAAS(), terraform.DownloadTerraform(versionFile))
+	terraformClient, err := terraform.New(provider.IAAS(), terraform.DownloadTerraform(versionFile), terraformCache(provider, name, infoArgs.Namespace), terraform.EnsureLock(configClient.EnsureTerraformLock))
 	if err != nil {
 		return nil, err
 	}
 
-	tfInputVarsFactory, err := concourse.NewTFInputVarsFactory(provider)
+	tfInputVarsFactory, err := concourse.NewTFInputVarsFactory(provider, stateStore)
 	if err != nil {
 		return nil, fmt.Errorf("Error creating TFInputVarsFactory [%v]", err)
 	}
@@ -127,9 +136,9 @@ func buildInfoClient(name, version string, infoArgs info.Args, provider iaas.Pro
 		terraformClient,
 		tfInputVarsFactory,
 		bosh.New,
-		fly.New,
+		newFlyClient,
 		certs.Generate,
-		config.New(provider, name, infoArgs.Namespace),
+		configClient,
 		nil,
 		os.Stdout,
 		os.Stderr,
@@ -154,7 +163,7 @@ var infoCmd = cli.Command{
 	Action: func(c *cli.Context) error {
 		infoArgs, err := validateInfoArgs(c, initialInfoArgs)
 		if err != nil {
-			return fmt.Errorf("Error validating args on info: [%v]", err)
+			return events.Categorize(fmt.Errorf("Error validating args on info: [%v]", err), events.UsageCategory)
 		}
 		iaasName, err := iaas.Validate(infoArgs.IAAS)
 		if err != nil {
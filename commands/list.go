@@ -0,0 +1,116 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/EngineerBetter/control-tower/commands/list"
+	"github.com/EngineerBetter/control-tower/config"
+	"github.com/EngineerBetter/control-tower/events"
+	"github.com/EngineerBetter/control-tower/iaas"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var initialListArgs list.Args
+
+var listFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:        "region",
+		Usage:       "(optional) AWS region",
+		EnvVar:      "AWS_REGION",
+		Destination: &initialListArgs.Region,
+	},
+	cli.BoolFlag{
+		Name:        "json",
+		Usage:       "(optional) Output as json",
+		EnvVar:      "JSON",
+		Destination: &initialListArgs.JSON,
+	},
+	cli.StringFlag{
+		Name:        "iaas",
+		Usage:       "(required) IAAS, can be AWS, GCP or Azure",
+		EnvVar:      "IAAS",
+		Destination: &initialListArgs.IAAS,
+	},
+}
+
+func listAction(listArgs list.Args, provider iaas.Provider) error {
+	deployments, err := listDeployments(provider)
+	if err != nil {
+		return err
+	}
+
+	if listArgs.JSON {
+		return json.NewEncoder(os.Stdout).Encode(deployments)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "PROJECT\tNAMESPACE\tREGION\tDOMAIN\tVERSION\tWORKERS\tLAST DEPLOYED")
+	for _, d := range deployments {
+		if d.Error != "" {
+			fmt.Fprintf(w, "?\t?\t%s\t\t\t\terror reading %s: %s\n", d.Region, d.ConfigBucket, d.Error)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d x %s\t%s\n",
+			d.Project,
+			d.Namespace,
+			d.Region,
+			d.Domain,
+			d.Version,
+			d.WorkerCount,
+			d.WorkerSize,
+			d.LastDeployed.Format("2006-01-02 15:04:05 MST"),
+		)
+	}
+	return w.Flush()
+}
+
+func validateListArgs(c *cli.Context, listArgs list.Args) (list.Args, error) {
+	err := listArgs.MarkSetFlags(c)
+	if err != nil {
+		return listArgs, fmt.Errorf("failed to mark set List flags: [%v]", err)
+	}
+
+	if err = listArgs.Validate(); err != nil {
+		return listArgs, fmt.Errorf("failed to validate List flags: [%v]", err)
+	}
+
+	return listArgs, nil
+}
+
+var listCmd = cli.Command{
+	Name:    "list",
+	Aliases: []string{"ls"},
+	Usage:   "Lists all deployments found in config buckets",
+	Flags:   listFlags,
+	Action: func(c *cli.Context) error {
+		listArgs, err := validateListArgs(c, initialListArgs)
+		if err != nil {
+			return events.Categorize(fmt.Errorf("Error validating args on list: [%v]", err), events.UsageCategory)
+		}
+		iaasName, err := iaas.Validate(listArgs.IAAS)
+		if err != nil {
+			return fmt.Errorf("Error mapping to supported IAASes on list: [%v]", err)
+		}
+		provider, err := iaas.New(iaasName, listArgs.Region)
+		if err != nil {
+			return fmt.Errorf("Error creating IAAS provider on list: [%v]", err)
+		}
+		return listAction(listArgs, provider)
+	},
+}
+
+// listDeployments searches every region of the IAAS when state is kept there, and the whole store otherwise
+func listDeployments(provider iaas.Provider) ([]config.Deployment, error) {
+	if stateBackend.IsIAAS() {
+		return config.List(provider, iaas.New)
+	}
+
+	stateStore, err := newStateStore(provider)
+	if err != nil {
+		return nil, err
+	}
+	return config.ListStore(stateStore)
+}
@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/EngineerBetter/control-tower/commands/logs"
+	"github.com/EngineerBetter/control-tower/events"
+	"github.com/EngineerBetter/control-tower/iaas"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var initialLogsArgs logs.Args
+
+var logsFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:        "region",
+		Usage:       "(optional) AWS region",
+		EnvVar:      "AWS_REGION",
+		Destination: &initialLogsArgs.Region,
+	},
+	cli.StringFlag{
+		Name:        "iaas",
+		Usage:       "(required) IAAS, can be AWS, GCP or Azure",
+		EnvVar:      "IAAS",
+		Destination: &initialLogsArgs.IAAS,
+	},
+	cli.StringFlag{
+		Name:        "namespace",
+		Usage:       "(optional) Specify a namespace for deployments in order to group them in a meaningful way",
+		EnvVar:      "NAMESPACE",
+		Destination: &initialLogsArgs.Namespace,
+	},
+	cli.StringFlag{
+		Name:        "job",
+		Usage:       "(optional) Only fetch logs for this job, eg atc, credhub, uaa or influxdb",
+		Destination: &initialLogsArgs.Job,
+	},
+	cli.BoolFlag{
+		Name:        "follow, f",
+		Usage:       "(optional) Stream logs instead of downloading them",
+		Destination: &initialLogsArgs.Follow,
+	},
+	cli.StringFlag{
+		Name:        "dir",
+		Usage:       "(optional) Directory to download logs to",
+		Value:       ".",
+		Destination: &initialLogsArgs.Dir,
+	},
+}
+
+func logsAction(c *cli.Context, logsArgs logs.Args, provider iaas.Provider) error {
+	name := c.Args().Get(0)
+	if name == "" {
+		return errors.New("Usage is `control-tower logs <name> [instance-group[/index]|director]`")
+	}
+	target := c.Args().Get(1)
+
+	version := c.App.Version
+
+	client, err := buildExistingDeploymentClient(name, version, logsArgs.Namespace, provider)
+	if err != nil {
+		return err
+	}
+
+	return client.Logs(commandContext, target, logsArgs.Job, logsArgs.Follow, logsArgs.Dir)
+}
+
+func validateLogsArgs(c *cli.Context, logsArgs logs.Args) (logs.Args, error) {
+	err := logsArgs.MarkSetFlags(c)
+	if err != nil {
+		return logsArgs, fmt.Errorf("failed to mark set Logs flags: [%v]", err)
+	}
+
+	if err = logsArgs.Validate(); err != nil {
+		return logsArgs, fmt.Errorf("failed to validate Logs flags: [%v]", err)
+	}
+
+	return logsArgs, nil
+}
+
+var logsCmd = cli.Command{
+	Name:      "logs",
+	Usage:     "Downloads or follows logs from the Concourse VMs or the BOSH director",
+	ArgsUsage: "<name> [instance-group[/index]|director]",
+	Flags:     logsFlags,
+	Action: func(c *cli.Context) error {
+		logsArgs, err := validateLogsArgs(c, initialLogsArgs)
+		if err != nil {
+			return events.Categorize(fmt.Errorf("Error validating args on logs: [%v]", err), events.UsageCategory)
+		}
+		iaasName, err := iaas.Validate(logsArgs.IAAS)
+		if err != nil {
+			return fmt.Errorf("Error mapping to supported IAASes on logs: [%v]", err)
+		}
+		provider, err := iaas.New(iaasName, logsArgs.Region)
+		if err != nil {
+			return fmt.Errorf("Error creating IAAS provider on logs: [%v]", err)
+		}
+		return logsAction(c, logsArgs, provider)
+	},
+}
@@ -3,16 +3,16 @@ package commands
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"text/tabwriter"
 
 	"github.com/EngineerBetter/control-tower/commands/maintain"
-	"github.com/EngineerBetter/control-tower/resource"
+	"github.com/EngineerBetter/control-tower/events"
 
 	"github.com/EngineerBetter/control-tower/bosh"
 	"github.com/EngineerBetter/control-tower/certs"
 	"github.com/EngineerBetter/control-tower/concourse"
-	"github.com/EngineerBetter/control-tower/config"
-	"github.com/EngineerBetter/control-tower/fly"
 	"github.com/EngineerBetter/control-tower/iaas"
 	"github.com/EngineerBetter/control-tower/terraform"
 	"github.com/EngineerBetter/control-tower/util"
@@ -36,7 +36,7 @@ var maintainFlags = []cli.Flag{
 	},
 	cli.StringFlag{
 		Name:        "iaas",
-		Usage:       "(required) IAAS, can be AWS or GCP",
+		Usage:       "(required) IAAS, can be AWS, GCP or Azure",
 		EnvVar:      "IAAS",
 		Destination: &initialMaintainArgs.IAAS,
 	},
@@ -46,12 +46,37 @@ var maintainFlags = []cli.Flag{
 		EnvVar:      "NAMESPACE",
 		Destination: &initialMaintainArgs.Namespace,
 	},
+	cli.BoolFlag{
+		Name:        "rotate-credentials",
+		Usage:       "(optional) Regenerate the passwords and secrets used by the deployment",
+		Destination: &initialMaintainArgs.RotateCredentials,
+	},
+	cli.StringFlag{
+		Name:        "credentials",
+		Usage:       "(optional) Comma separated list of credentials to rotate. Can be concourse, credhub, director and rds (default: all)",
+		Destination: &initialMaintainArgs.Credentials,
+	},
 	cli.IntFlag{
 		Name:        "stage",
-		Usage:       "(optional) Set the desired stage for nats rotation tasks",
+		Usage:       "(optional) Set the desired stage at which to start or resume the maintenance operation",
 		EnvVar:      "STAGE",
 		Destination: &initialMaintainArgs.Stage,
 	},
+	cli.StringFlag{
+		Name:        "operation",
+		Usage:       "(optional) Name of the maintenance operation to run, see --list",
+		Destination: &initialMaintainArgs.Operation,
+	},
+	cli.BoolFlag{
+		Name:        "list",
+		Usage:       "(optional) List the available maintenance operations and their stages",
+		Destination: &initialMaintainArgs.List,
+	},
+	cli.BoolFlag{
+		Name:        "status",
+		Usage:       "(optional) Show the progress of maintenance operations on the deployment",
+		Destination: &initialMaintainArgs.Status,
+	},
 }
 
 func maintainAction(c *cli.Context, maintainArgs maintain.Args, provider iaas.Provider) error {
@@ -66,7 +91,16 @@ func maintainAction(c *cli.Context, maintainArgs maintain.Args, provider iaas.Pr
 	if err != nil {
 		return err
 	}
-	err = client.Maintain(maintainArgs)
+
+	if maintainArgs.StatusIsSet {
+		statuses, err := client.MaintenanceStatus()
+		if err != nil {
+			return err
+		}
+		return writeMaintenanceStatus(os.Stdout, name, statuses)
+	}
+
+	err = client.Maintain(commandContext, maintainArgs)
 	if err != nil {
 		return err
 	}
@@ -74,6 +108,43 @@ func maintainAction(c *cli.Context, maintainArgs maintain.Args, provider iaas.Pr
 	return nil
 }
 
+// writeMaintenanceOperations lists each maintenance operation with its stages
+func writeMaintenanceOperations(w io.Writer, operations []concourse.MaintenanceOperation) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, operation := range operations {
+		if _, err := fmt.Fprintf(tw, "%s\t%s\n", operation.Name, operation.Description); err != nil {
+			return err
+		}
+		for i, step := range operation.Steps {
+			if _, err := fmt.Fprintf(tw, "  stage %d\t%s\n", i, step.Description); err != nil {
+				return err
+			}
+		}
+	}
+	return tw.Flush()
+}
+
+// writeMaintenanceStatus describes the maintenance operations in progress on the deployment
+func writeMaintenanceStatus(w io.Writer, name string, statuses []concourse.MaintenanceStatus) error {
+	inProgress := 0
+	for _, status := range statuses {
+		if !status.InProgress {
+			continue
+		}
+		inProgress++
+		_, err := fmt.Fprintf(w, "%s is in progress, %d of %d stages complete\nNext stage: %d %s\nRun `control-tower maintain --operation %s %s` to resume it\n",
+			status.Operation, status.NextStep, status.Steps, status.NextStep, status.NextStepDescription, status.Operation, name)
+		if err != nil {
+			return err
+		}
+	}
+	if inProgress == 0 {
+		_, err := fmt.Fprintf(w, "No maintenance operation is in progress on %s\n", name)
+		return err
+	}
+	return nil
+}
+
 func validateMaintainArgs(c *cli.Context, maintainArgs maintain.Args) (maintain.Args, error) {
 	err := maintainArgs.MarkSetFlags(c)
 	if err != nil {
@@ -88,17 +159,27 @@ func validateMaintainArgs(c *cli.Context, maintainArgs maintain.Args) (maintain.
 }
 
 func buildMaintainClient(name, version string, maintainArgs maintain.Args, provider iaas.Provider) (*concourse.Client, error) {
-	versionFile, _ := provider.Choose(iaas.Choice{
-		AWS: resource.AWSVersionFile,
-		GCP: resource.GCPVersionFile,
-	}).([]byte)
+	versionFile, err := loadVersionFile(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	stateStore, err := newStateStore(provider)
+	if err != nil {
+		return nil, err
+	}
 
-	terraformClient, err := terraform.New(provider.IAAS(), terraform.DownloadTerraform(versionFile))
+	configClient, err := newConfigClient(provider, stateStore, name, maintainArgs.Namespace)
 	if err != nil {
 		return nil, err
 	}
 
-	tfInputVarsFactory, err := concourse.NewTFInputVarsFactory(provider)
+	terraformClient, err := terraform.New(provider.IAAS(), terraform.DownloadTerraform(versionFile), terraformCache(provider, name, maintainArgs.Namespace), terraform.EnsureLock(configClient.EnsureTerraformLock))
+	if err != nil {
+		return nil, err
+	}
+
+	tfInputVarsFactory, err := concourse.NewTFInputVarsFactory(provider, stateStore)
 	if err != nil {
 		return nil, fmt.Errorf("Error creating TFInputVarsFactory [%v]", err)
 	}
@@ -108,9 +189,9 @@ func buildMaintainClient(name, version string, maintainArgs maintain.Args, provi
 		terraformClient,
 		tfInputVarsFactory,
 		bosh.New,
-		fly.New,
+		newFlyClient,
 		certs.Generate,
-		config.New(provider, name, maintainArgs.Namespace),
+		configClient,
 		nil,
 		os.Stdout,
 		os.Stderr,
@@ -135,7 +216,10 @@ var maintainCmd = cli.Command{
 	Action: func(c *cli.Context) error {
 		maintainArgs, err := validateMaintainArgs(c, initialMaintainArgs)
 		if err != nil {
-			return fmt.Errorf("Error validating args on maintain: [%v]", err)
+			return events.Categorize(fmt.Errorf("Error validating args on maintain: [%v]", err), events.UsageCategory)
+		}
+		if maintainArgs.ListIsSet {
+			return writeMaintenanceOperations(os.Stdout, concourse.MaintenanceOperations())
 		}
 		iaasName, err := iaas.Validate(maintainArgs.IAAS)
 		if err != nil {
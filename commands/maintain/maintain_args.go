@@ -2,6 +2,7 @@ package maintain
 
 import (
 	"fmt"
+	"strings"
 
 	cli "gopkg.in/urfave/cli.v1"
 )
@@ -18,8 +19,29 @@ type Args struct {
 	IAASIsSet          bool
 	Stage              int
 	StageIsSet         bool
+	// RotateCredentials regenerates the secrets of the components listed in Credentials
+	RotateCredentials      bool
+	RotateCredentialsIsSet bool
+	Credentials            string
+	CredentialsIsSet       bool
+	// Operation is the name of the maintenance operation to run
+	Operation      string
+	OperationIsSet bool
+	List           bool
+	ListIsSet      bool
+	Status         bool
+	StatusIsSet    bool
 }
 
+// Names of the maintenance operations that have their own flag
+const (
+	RenewNatsCertOperation     = "renew-nats-cert"
+	RotateCredentialsOperation = "rotate-credentials"
+)
+
+// RotatableCredentials are the components whose secrets can be rotated
+var RotatableCredentials = []string{"concourse", "credhub", "director", "rds"}
+
 //MarkSetFlags is marking which info Args have been set
 func (a *Args) MarkSetFlags(c FlagSetChecker) error {
 	for _, f := range c.FlagNames() {
@@ -33,8 +55,18 @@ func (a *Args) MarkSetFlags(c FlagSetChecker) error {
 				a.RenewNatsCertIsSet = true
 			case "stage":
 				a.StageIsSet = true
+			case "rotate-credentials":
+				a.RotateCredentialsIsSet = true
+			case "credentials":
+				a.CredentialsIsSet = true
 			case "iaas":
 				a.IAASIsSet = true
+			case "operation":
+				a.OperationIsSet = true
+			case "list":
+				a.ListIsSet = true
+			case "status":
+				a.StatusIsSet = true
 			default:
 				return fmt.Errorf("flag %q is not supported by maintain flags", f)
 			}
@@ -44,12 +76,74 @@ func (a *Args) MarkSetFlags(c FlagSetChecker) error {
 }
 
 func (a *Args) Validate() error {
+	modes := 0
+	for _, isSet := range []bool{a.OperationIsSet, a.RenewNatsCertIsSet, a.RotateCredentialsIsSet, a.ListIsSet, a.StatusIsSet} {
+		if isSet {
+			modes++
+		}
+	}
+	if modes > 1 {
+		return fmt.Errorf("--operation, --renew-nats-cert, --rotate-credentials, --list and --status cannot be used together")
+	}
+	if a.ListIsSet {
+		return nil
+	}
 	if !a.IAASIsSet {
 		return fmt.Errorf("--iaas flag not set")
 	}
+	if a.OperationIsSet && strings.TrimSpace(a.Operation) == "" {
+		return fmt.Errorf("--operation cannot be empty")
+	}
+	if a.StageIsSet && a.SelectedOperation() == "" {
+		return fmt.Errorf("--stage can only be used when running an operation")
+	}
+	if a.CredentialsIsSet && a.SelectedOperation() != RotateCredentialsOperation {
+		return fmt.Errorf("--credentials can only be used with --rotate-credentials")
+	}
+	for _, component := range a.CredentialComponents() {
+		if !isRotatable(component) {
+			return fmt.Errorf("unknown credentials %q, can be any of %s", component, strings.Join(RotatableCredentials, ", "))
+		}
+	}
 	return nil
 }
 
+// SelectedOperation returns the name of the maintenance operation to run, or an empty string if none was chosen
+func (a *Args) SelectedOperation() string {
+	switch {
+	case a.OperationIsSet:
+		return strings.TrimSpace(a.Operation)
+	case a.RenewNatsCertIsSet:
+		return RenewNatsCertOperation
+	case a.RotateCredentialsIsSet:
+		return RotateCredentialsOperation
+	}
+	return ""
+}
+
+// CredentialComponents returns the components to rotate, defaulting to all of them
+func (a *Args) CredentialComponents() []string {
+	if !a.CredentialsIsSet {
+		return RotatableCredentials
+	}
+	components := []string{}
+	for _, component := range strings.Split(a.Credentials, ",") {
+		if component = strings.TrimSpace(component); component != "" {
+			components = append(components, component)
+		}
+	}
+	return components
+}
+
+func isRotatable(component string) bool {
+	for _, c := range RotatableCredentials {
+		if c == component {
+			return true
+		}
+	}
+	return false
+}
+
 // FlagSetChecker allows us to find out if flags were set, adn what the names of all flags are
 type FlagSetChecker interface {
 	IsSet(name string) bool
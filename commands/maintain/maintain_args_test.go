@@ -37,6 +37,128 @@ func TestMaintainArgs_Validate(t *testing.T) {
 			wantErr:     true,
 			expectedErr: "--iaas flag not set",
 		},
+		{
+			name: "Rotating a subset of credentials",
+			modification: func() Args {
+				args := defaultFields
+				args.RotateCredentialsIsSet = true
+				args.Credentials = "concourse, rds"
+				args.CredentialsIsSet = true
+				return args
+			},
+			wantErr: false,
+		},
+		{
+			name: "Rotating unknown credentials",
+			modification: func() Args {
+				args := defaultFields
+				args.RotateCredentialsIsSet = true
+				args.Credentials = "concourse,grafana"
+				args.CredentialsIsSet = true
+				return args
+			},
+			wantErr:     true,
+			expectedErr: `unknown credentials "grafana"`,
+		},
+		{
+			name: "Credentials without rotate-credentials",
+			modification: func() Args {
+				args := defaultFields
+				args.Credentials = "concourse"
+				args.CredentialsIsSet = true
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "--credentials can only be used with --rotate-credentials",
+		},
+		{
+			name: "Renewing NATS cert and rotating credentials together",
+			modification: func() Args {
+				args := defaultFields
+				args.RenewNatsCertIsSet = true
+				args.RotateCredentialsIsSet = true
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "cannot be used together",
+		},
+		{
+			name: "Naming an operation and renewing NATS cert together",
+			modification: func() Args {
+				args := defaultFields
+				args.Operation = "renew-nats-cert"
+				args.OperationIsSet = true
+				args.RenewNatsCertIsSet = true
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "cannot be used together",
+		},
+		{
+			name: "Listing operations without IAAS",
+			modification: func() Args {
+				args := defaultFields
+				args.IAASIsSet = false
+				args.ListIsSet = true
+				return args
+			},
+			wantErr: false,
+		},
+		{
+			name: "Listing operations and showing status together",
+			modification: func() Args {
+				args := defaultFields
+				args.ListIsSet = true
+				args.StatusIsSet = true
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "cannot be used together",
+		},
+		{
+			name: "Showing status",
+			modification: func() Args {
+				args := defaultFields
+				args.StatusIsSet = true
+				return args
+			},
+			wantErr: false,
+		},
+		{
+			name: "Empty operation",
+			modification: func() Args {
+				args := defaultFields
+				args.Operation = " "
+				args.OperationIsSet = true
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "--operation cannot be empty",
+		},
+		{
+			name: "Stage without an operation",
+			modification: func() Args {
+				args := defaultFields
+				args.Stage = 2
+				args.StageIsSet = true
+				args.StatusIsSet = true
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "--stage can only be used when running an operation",
+		},
+		{
+			name: "Credentials with the rotate-credentials operation",
+			modification: func() Args {
+				args := defaultFields
+				args.Operation = "rotate-credentials"
+				args.OperationIsSet = true
+				args.Credentials = "director"
+				args.CredentialsIsSet = true
+				return args
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -58,6 +180,26 @@ func TestMaintainArgs_Validate(t *testing.T) {
 	}
 }
 
+func TestMaintainArgs_SelectedOperation(t *testing.T) {
+	tests := []struct {
+		name string
+		args Args
+		want string
+	}{
+		{name: "nothing", args: Args{}, want: ""},
+		{name: "renew-nats-cert flag", args: Args{RenewNatsCertIsSet: true}, want: RenewNatsCertOperation},
+		{name: "rotate-credentials flag", args: Args{RotateCredentialsIsSet: true}, want: RotateCredentialsOperation},
+		{name: "operation flag", args: Args{Operation: " rotate-ca ", OperationIsSet: true}, want: "rotate-ca"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.args.SelectedOperation(); got != tt.want {
+				t.Errorf("SelectedOperation() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 type FakeFlagSetChecker struct {
 	names          []string
 	specifiedFlags []string
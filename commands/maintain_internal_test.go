@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/EngineerBetter/control-tower/concourse"
+)
+
+func TestWriteMaintenanceOperations(t *testing.T) {
+	operations := []concourse.MaintenanceOperation{
+		{Name: "renew-nats-cert", Description: "Rotate the NATS certificate on the director", Steps: []concourse.MaintenanceStep{
+			{Description: "Adding new CA (create-env)"},
+			{Description: "Recreating VMs for the first time (recreate)"},
+		}},
+		{Name: "rotate-credentials", Description: "Regenerate the passwords and secrets used by the deployment", Steps: []concourse.MaintenanceStep{
+			{Description: "Generating new credentials"},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := writeMaintenanceOperations(&buf, operations); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `renew-nats-cert     Rotate the NATS certificate on the director
+  stage 0           Adding new CA (create-env)
+  stage 1           Recreating VMs for the first time (recreate)
+rotate-credentials  Regenerate the passwords and secrets used by the deployment
+  stage 0           Generating new credentials
+`
+	if buf.String() != want {
+		t.Errorf("writeMaintenanceOperations() = \n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteMaintenanceStatus(t *testing.T) {
+	statuses := []concourse.MaintenanceStatus{
+		{Operation: "renew-nats-cert", InProgress: true, NextStep: 2, NextStepDescription: "Removing old CA (create-env)", Steps: 5},
+		{Operation: "rotate-credentials", NextStep: 0, NextStepDescription: "Generating new credentials", Steps: 3},
+	}
+
+	var buf bytes.Buffer
+	if err := writeMaintenanceStatus(&buf, "my-deployment", statuses); err != nil {
+		t.Fatal(err)
+	}
+	want := "renew-nats-cert is in progress, 2 of 5 stages complete\nNext stage: 2 Removing old CA (create-env)\nRun `control-tower maintain --operation renew-nats-cert my-deployment` to resume it\n"
+	if buf.String() != want {
+		t.Errorf("writeMaintenanceStatus() = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	statuses[0].InProgress = false
+	if err := writeMaintenanceStatus(&buf, "my-deployment", statuses); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "No maintenance operation is in progress on my-deployment\n" {
+		t.Errorf("writeMaintenanceStatus() = %q", buf.String())
+	}
+}
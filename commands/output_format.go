@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/EngineerBetter/control-tower/events"
+
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+var outputFormat string
+var eventsFile string
+
+// WithEvents returns cmds, and their subcommands, reporting their progress in the format chosen by --output-format
+func WithEvents(cmds []cli.Command) []cli.Command {
+	wrapped := []cli.Command{}
+	for _, cmd := range cmds {
+		wrapped = append(wrapped, withEvents(cmd, cmd.Name))
+	}
+	return wrapped
+}
+
+func withEvents(cmd cli.Command, name string) cli.Command {
+	subcommands := []cli.Command{}
+	for _, subcommand := range cmd.Subcommands {
+		subcommands = append(subcommands, withEvents(subcommand, name+" "+subcommand.Name))
+	}
+	cmd.Subcommands = subcommands
+
+	action, ok := cmd.Action.(func(*cli.Context) error)
+	if !ok {
+		return cmd
+	}
+	cmd.Action = func(c *cli.Context) error {
+		switch outputFormat {
+		case events.TextFormat:
+			return action(c)
+		case events.JSONFormat:
+		default:
+			return fmt.Errorf("unknown --output-format `%s`. Valid formats are: %s", outputFormat, strings.Join(events.Formats, ", "))
+		}
+
+		// Events have their own writer, so that what commands print, such as `info --json`, stays on stdout
+		out, err := openEventsFile()
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+
+		events.Start(out, name)
+		err = action(c)
+		if err != nil && commandContext.Err() != nil {
+			err = events.Categorize(err, events.InterruptedCategory)
+		}
+		events.Finish(err)
+		return err
+	}
+	return cmd
+}
+
+// openEventsFile opens the file chosen by --events-file, or stderr when none was
+func openEventsFile() (io.WriteCloser, error) {
+	if eventsFile == "" {
+		return nopCloser{os.Stderr}, nil
+	}
+	f, err := os.OpenFile(eventsFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("error opening --events-file: [%v]", err)
+	}
+	return f, nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/EngineerBetter/control-tower/commands/deploy"
+	"github.com/EngineerBetter/control-tower/events"
+	"github.com/EngineerBetter/control-tower/iaas"
+
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+func planAction(c *cli.Context, deployArgs deploy.Args, provider iaas.Provider) error {
+	name := c.Args().Get(0)
+	if name == "" {
+		return errors.New("Usage is `control-tower plan <name>`")
+	}
+
+	version := c.App.Version
+
+	deployArgs, err := setZoneAndRegion(provider.Region(), deployArgs)
+	if err != nil {
+		return err
+	}
+
+	err = validateNameLength(name, provider.IAAS())
+	if err != nil {
+		return err
+	}
+
+	err = validateCidrRanges(provider, deployArgs.NetworkCIDR, deployArgs.PublicCIDR, deployArgs.PrivateCIDR, deployArgs.RDS1CIDR, deployArgs.RDS2CIDR)
+	if err != nil {
+		return err
+	}
+
+	client, err := buildClient(name, version, deployArgs, provider)
+	if err != nil {
+		return err
+	}
+
+	plan, err := client.Plan(commandContext)
+	if err != nil {
+		return err
+	}
+
+	err = plan.Write(os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	if plan.Destructive() {
+		return errors.New("plan would destroy or replace existing resources")
+	}
+	return nil
+}
+
+var planCmd = cli.Command{
+	Name:      "plan",
+	Usage:     "Previews the changes a deploy would make to a Concourse",
+	ArgsUsage: "<name>",
+	Flags:     deployFlags,
+	Action: func(c *cli.Context) error {
+		deployArgs, err := validateDeployArgs(c, initialDeployArgs)
+		if err != nil {
+			return events.Categorize(fmt.Errorf("Error validating args on plan: [%v]", err), events.UsageCategory)
+		}
+		iaasName, err := iaas.Validate(deployArgs.IAAS)
+		if err != nil {
+			return fmt.Errorf("Error mapping to supported IAASes on plan: [%v]", err)
+		}
+		provider, err := iaas.New(iaasName, deployArgs.Region)
+		if err != nil {
+			return fmt.Errorf("Error creating IAAS provider on plan: [%v]", err)
+		}
+		return planAction(c, deployArgs, provider)
+	},
+}
@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/EngineerBetter/control-tower/commands/restore"
+	"github.com/EngineerBetter/control-tower/events"
+	"github.com/EngineerBetter/control-tower/iaas"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var initialRestoreArgs restore.Args
+
+var restoreFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:        "region",
+		Usage:       "(optional) AWS region",
+		EnvVar:      "AWS_REGION",
+		Destination: &initialRestoreArgs.Region,
+	},
+	cli.StringFlag{
+		Name:        "iaas",
+		Usage:       "(required) IAAS, can be AWS, GCP or Azure",
+		EnvVar:      "IAAS",
+		Destination: &initialRestoreArgs.IAAS,
+	},
+	cli.StringFlag{
+		Name:        "namespace",
+		Usage:       "(optional) Specify a namespace for deployments in order to group them in a meaningful way",
+		EnvVar:      "NAMESPACE",
+		Destination: &initialRestoreArgs.Namespace,
+	},
+	cli.StringFlag{
+		Name:        "from",
+		Usage:       "(required) ID of the backup to restore",
+		Destination: &initialRestoreArgs.From,
+	},
+}
+
+func restoreAction(c *cli.Context, restoreArgs restore.Args, provider iaas.Provider) error {
+	name := c.Args().Get(0)
+	if name == "" {
+		return errors.New("Usage is `control-tower restore <name> --from <backup-id>`")
+	}
+
+	version := c.App.Version
+
+	client, err := buildExistingDeploymentClient(name, version, restoreArgs.Namespace, provider)
+	if err != nil {
+		return err
+	}
+
+	return client.Restore(commandContext, restoreArgs.From)
+}
+
+func validateRestoreArgs(c *cli.Context, restoreArgs restore.Args) (restore.Args, error) {
+	err := restoreArgs.MarkSetFlags(c)
+	if err != nil {
+		return restoreArgs, fmt.Errorf("failed to mark set Restore flags: [%v]", err)
+	}
+
+	if err = restoreArgs.Validate(); err != nil {
+		return restoreArgs, fmt.Errorf("failed to validate Restore flags: [%v]", err)
+	}
+
+	return restoreArgs, nil
+}
+
+var restoreCmd = cli.Command{
+	Name:      "restore",
+	Usage:     "Restores the Concourse, CredHub and UAA databases from a backup",
+	ArgsUsage: "<name>",
+	Flags:     restoreFlags,
+	Action: func(c *cli.Context) error {
+		restoreArgs, err := validateRestoreArgs(c, initialRestoreArgs)
+		if err != nil {
+			return events.Categorize(fmt.Errorf("Error validating args on restore: [%v]", err), events.UsageCategory)
+		}
+		iaasName, err := iaas.Validate(restoreArgs.IAAS)
+		if err != nil {
+			return fmt.Errorf("Error mapping to supported IAASes on restore: [%v]", err)
+		}
+		provider, err := iaas.New(iaasName, restoreArgs.Region)
+		if err != nil {
+			return fmt.Errorf("Error creating IAAS provider on restore: [%v]", err)
+		}
+		return restoreAction(c, restoreArgs, provider)
+	},
+}
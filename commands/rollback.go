@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/EngineerBetter/control-tower/commands/rollback"
+	"github.com/EngineerBetter/control-tower/events"
+	"github.com/EngineerBetter/control-tower/iaas"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var initialRollbackArgs rollback.Args
+
+var rollbackFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:        "region",
+		Usage:       "(optional) AWS region",
+		EnvVar:      "AWS_REGION",
+		Destination: &initialRollbackArgs.Region,
+	},
+	cli.StringFlag{
+		Name:        "iaas",
+		Usage:       "(required) IAAS, can be AWS, GCP or Azure",
+		EnvVar:      "IAAS",
+		Destination: &initialRollbackArgs.IAAS,
+	},
+	cli.StringFlag{
+		Name:        "namespace",
+		Usage:       "(optional) Specify a namespace for deployments in order to group them in a meaningful way",
+		EnvVar:      "NAMESPACE",
+		Destination: &initialRollbackArgs.Namespace,
+	},
+	cli.IntFlag{
+		Name:        "to",
+		Usage:       "(required) Revision to roll back to, as listed by history",
+		Destination: &initialRollbackArgs.To,
+	},
+}
+
+func rollbackAction(c *cli.Context, rollbackArgs rollback.Args, provider iaas.Provider) error {
+	name := c.Args().Get(0)
+	if name == "" {
+		return errors.New("Usage is `control-tower rollback <name> --to <revision>`")
+	}
+
+	version := c.App.Version
+
+	client, err := buildExistingDeploymentClient(name, version, rollbackArgs.Namespace, provider)
+	if err != nil {
+		return err
+	}
+
+	return client.Rollback(commandContext, rollbackArgs.To)
+}
+
+func validateRollbackArgs(c *cli.Context, rollbackArgs rollback.Args) (rollback.Args, error) {
+	err := rollbackArgs.MarkSetFlags(c)
+	if err != nil {
+		return rollbackArgs, fmt.Errorf("failed to mark set Rollback flags: [%v]", err)
+	}
+
+	if err = rollbackArgs.Validate(); err != nil {
+		return rollbackArgs, fmt.Errorf("failed to validate Rollback flags: [%v]", err)
+	}
+
+	return rollbackArgs, nil
+}
+
+var rollbackCmd = cli.Command{
+	Name:      "rollback",
+	Usage:     "Restores the config, director state and creds of a deployment to a previous revision",
+	ArgsUsage: "<name>",
+	Flags:     rollbackFlags,
+	Action: func(c *cli.Context) error {
+		rollbackArgs, err := validateRollbackArgs(c, initialRollbackArgs)
+		if err != nil {
+			return events.Categorize(fmt.Errorf("Error validating args on rollback: [%v]", err), events.UsageCategory)
+		}
+		iaasName, err := iaas.Validate(rollbackArgs.IAAS)
+		if err != nil {
+			return fmt.Errorf("Error mapping to supported IAASes on rollback: [%v]", err)
+		}
+		provider, err := iaas.New(iaasName, rollbackArgs.Region)
+		if err != nil {
+			return fmt.Errorf("Error creating IAAS provider on rollback: [%v]", err)
+		}
+		return rollbackAction(c, rollbackArgs, provider)
+	},
+}
@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/EngineerBetter/control-tower/commands/scale"
+	"github.com/EngineerBetter/control-tower/events"
+	"github.com/EngineerBetter/control-tower/iaas"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var initialScaleArgs scale.Args
+
+var scaleFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:        "region",
+		Usage:       "(optional) AWS region",
+		EnvVar:      "AWS_REGION",
+		Destination: &initialScaleArgs.Region,
+	},
+	cli.StringFlag{
+		Name:        "iaas",
+		Usage:       "(required) IAAS, can be AWS, GCP or Azure",
+		EnvVar:      "IAAS",
+		Destination: &initialScaleArgs.IAAS,
+	},
+	cli.StringFlag{
+		Name:        "namespace",
+		Usage:       "(optional) Specify a namespace for deployments in order to group them in a meaningful way",
+		EnvVar:      "NAMESPACE",
+		Destination: &initialScaleArgs.Namespace,
+	},
+	cli.IntFlag{
+		Name:        "workers",
+		Usage:       "(optional) Number of Concourse worker instances to scale to",
+		EnvVar:      "WORKERS",
+		Destination: &initialScaleArgs.WorkerCount,
+	},
+	cli.StringFlag{
+		Name:        "worker-size",
+		Usage:       "(optional) Size of Concourse workers. Can be medium, large, xlarge, 2xlarge, 4xlarge, 12xlarge or 24xlarge",
+		EnvVar:      "WORKER_SIZE",
+		Destination: &initialScaleArgs.WorkerSize,
+	},
+	cli.StringFlag{
+		Name:        "pool",
+		Usage:       "(optional) Name of the worker pool to scale instead of the default workers",
+		Destination: &initialScaleArgs.Pool,
+	},
+}
+
+func scaleAction(c *cli.Context, scaleArgs scale.Args, provider iaas.Provider) error {
+	name := c.Args().Get(0)
+	if name == "" {
+		return errors.New("Usage is `control-tower scale <name> --workers <count>`")
+	}
+
+	version := c.App.Version
+
+	client, err := buildExistingDeploymentClient(name, version, scaleArgs.Namespace, provider)
+	if err != nil {
+		return err
+	}
+
+	return client.Scale(commandContext, scaleArgs)
+}
+
+func validateScaleArgs(c *cli.Context, scaleArgs scale.Args) (scale.Args, error) {
+	err := scaleArgs.MarkSetFlags(c)
+	if err != nil {
+		return scaleArgs, fmt.Errorf("failed to mark set Scale flags: [%v]", err)
+	}
+
+	if err = scaleArgs.Validate(); err != nil {
+		return scaleArgs, fmt.Errorf("failed to validate Scale flags: [%v]", err)
+	}
+
+	return scaleArgs, nil
+}
+
+var scaleCmd = cli.Command{
+	Name:      "scale",
+	Usage:     "Changes the number or size of Concourse workers without a full deploy",
+	ArgsUsage: "<name>",
+	Flags:     scaleFlags,
+	Action: func(c *cli.Context) error {
+		scaleArgs, err := validateScaleArgs(c, initialScaleArgs)
+		if err != nil {
+			return events.Categorize(fmt.Errorf("Error validating args on scale: [%v]", err), events.UsageCategory)
+		}
+		iaasName, err := iaas.Validate(scaleArgs.IAAS)
+		if err != nil {
+			return fmt.Errorf("Error mapping to supported IAASes on scale: [%v]", err)
+		}
+		provider, err := iaas.New(iaasName, scaleArgs.Region)
+		if err != nil {
+			return fmt.Errorf("Error creating IAAS provider on scale: [%v]", err)
+		}
+		return scaleAction(c, scaleArgs, provider)
+	},
+}
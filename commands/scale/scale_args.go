@@ -0,0 +1,105 @@
+package scale
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/EngineerBetter/control-tower/commands/deploy"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+// Args are arguments passed to the scale command
+type Args struct {
+	Region           string
+	RegionIsSet      bool
+	Namespace        string
+	NamespaceIsSet   bool
+	IAAS             string
+	IAASIsSet        bool
+	WorkerCount      int
+	WorkerCountIsSet bool
+	WorkerSize       string
+	WorkerSizeIsSet  bool
+	Pool             string
+	PoolIsSet        bool
+}
+
+//MarkSetFlags is marking which scale Args have been set
+func (a *Args) MarkSetFlags(c FlagSetChecker) error {
+	for _, f := range c.FlagNames() {
+		if c.IsSet(f) {
+			switch f {
+			case "region":
+				a.RegionIsSet = true
+			case "namespace":
+				a.NamespaceIsSet = true
+			case "iaas":
+				a.IAASIsSet = true
+			case "workers":
+				a.WorkerCountIsSet = true
+			case "worker-size":
+				a.WorkerSizeIsSet = true
+			case "pool":
+				a.PoolIsSet = true
+			default:
+				return fmt.Errorf("flag %q is not supported by scale flags", f)
+			}
+		}
+	}
+	return nil
+}
+
+func (a *Args) Validate() error {
+	if !a.IAASIsSet {
+		return fmt.Errorf("--iaas flag not set")
+	}
+	if a.PoolIsSet && a.Pool == "" {
+		return errors.New("--pool cannot be empty")
+	}
+	if a.PoolIsSet && a.WorkerSizeIsSet {
+		return errors.New("--worker-size cannot be used with --pool, change the size of a worker pool with deploy")
+	}
+	if a.PoolIsSet && !a.WorkerCountIsSet {
+		return errors.New("--workers must be set with --pool")
+	}
+	if !a.WorkerCountIsSet && !a.WorkerSizeIsSet {
+		return errors.New("at least one of --workers or --worker-size must be set")
+	}
+	if a.WorkerCountIsSet && a.WorkerCount < 1 {
+		return errors.New("minimum number of workers is 1")
+	}
+	if a.WorkerSizeIsSet && !isWorkerSize(a.WorkerSize) {
+		return fmt.Errorf("unknown worker size: `%s`. Valid sizes are: %v", a.WorkerSize, deploy.WorkerSizes)
+	}
+	return nil
+}
+
+func isWorkerSize(size string) bool {
+	for _, s := range deploy.WorkerSizes {
+		if s == size {
+			return true
+		}
+	}
+	return false
+}
+
+// FlagSetChecker allows us to find out if flags were set, adn what the names of all flags are
+type FlagSetChecker interface {
+	IsSet(name string) bool
+	FlagNames() (names []string)
+}
+
+// ContextWrapper wraps a CLI context for testing
+type ContextWrapper struct {
+	c *cli.Context
+}
+
+// IsSet tells you if a user provided a flag
+func (t *ContextWrapper) IsSet(name string) bool {
+	return t.c.IsSet(name)
+}
+
+// FlagNames lists all flags it's possible for a user to provide
+func (t *ContextWrapper) FlagNames() (names []string) {
+	return t.c.FlagNames()
+}
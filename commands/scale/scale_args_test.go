@@ -0,0 +1,128 @@
+package scale_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/EngineerBetter/control-tower/commands/scale"
+)
+
+func TestScaleArgs_Validate(t *testing.T) {
+	defaultFields := Args{
+		Region:           "eu-west-1",
+		IAAS:             "AWS",
+		IAASIsSet:        true,
+		WorkerCount:      3,
+		WorkerCountIsSet: true,
+	}
+	tests := []struct {
+		name         string
+		modification func() Args
+		wantErr      bool
+		expectedErr  string
+	}{
+		{
+			name: "Default args",
+			modification: func() Args {
+				return defaultFields
+			},
+			wantErr: false,
+		},
+		{
+			name: "IAAS not set",
+			modification: func() Args {
+				args := defaultFields
+				args.IAASIsSet = false
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "--iaas flag not set",
+		},
+		{
+			name: "Neither workers nor worker size set",
+			modification: func() Args {
+				args := defaultFields
+				args.WorkerCountIsSet = false
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "at least one of --workers or --worker-size must be set",
+		},
+		{
+			name: "Worker count less than 1",
+			modification: func() Args {
+				args := defaultFields
+				args.WorkerCount = 0
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "minimum number of workers is 1",
+		},
+		{
+			name: "Pool with workers",
+			modification: func() Args {
+				args := defaultFields
+				args.Pool, args.PoolIsSet = "gpu", true
+				return args
+			},
+			wantErr: false,
+		},
+		{
+			name: "Pool with worker size",
+			modification: func() Args {
+				args := defaultFields
+				args.Pool, args.PoolIsSet = "gpu", true
+				args.WorkerSize, args.WorkerSizeIsSet = "large", true
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "--worker-size cannot be used with --pool",
+		},
+		{
+			name: "Pool without workers",
+			modification: func() Args {
+				args := defaultFields
+				args.Pool, args.PoolIsSet = "gpu", true
+				args.WorkerCountIsSet = false
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "--workers must be set with --pool",
+		},
+		{
+			name: "Only worker size set",
+			modification: func() Args {
+				args := defaultFields
+				args.WorkerCountIsSet = false
+				args.WorkerSize = "large"
+				args.WorkerSizeIsSet = true
+				return args
+			},
+			wantErr: false,
+		},
+		{
+			name: "Unknown worker size",
+			modification: func() Args {
+				args := defaultFields
+				args.WorkerSize = "huge"
+				args.WorkerSizeIsSet = true
+				return args
+			},
+			wantErr:     true,
+			expectedErr: "unknown worker size: `huge`",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := tt.modification()
+			err := args.Validate()
+			if (err != nil) != tt.wantErr || (err != nil && tt.wantErr && !strings.Contains(err.Error(), tt.expectedErr)) {
+				if err != nil {
+					t.Errorf("ScaleArgs.Validate() %v test failed.\nFailed with error = %v,\nExpected error = %v,\nShould fail %v\nWith args: %#v", tt.name, err.Error(), tt.expectedErr, tt.wantErr, args)
+				} else {
+					t.Errorf("ScaleArgs.Validate() %v test failed.\nShould fail %v\nWith args: %#v", tt.name, tt.wantErr, args)
+				}
+			}
+		})
+	}
+}
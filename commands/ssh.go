@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/EngineerBetter/control-tower/commands/ssh"
+	"github.com/EngineerBetter/control-tower/events"
+	"github.com/EngineerBetter/control-tower/iaas"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var initialSSHArgs ssh.Args
+
+var sshFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:        "region",
+		Usage:       "(optional) AWS region",
+		EnvVar:      "AWS_REGION",
+		Destination: &initialSSHArgs.Region,
+	},
+	cli.StringFlag{
+		Name:        "iaas",
+		Usage:       "(required) IAAS, can be AWS, GCP or Azure",
+		EnvVar:      "IAAS",
+		Destination: &initialSSHArgs.IAAS,
+	},
+	cli.StringFlag{
+		Name:        "namespace",
+		Usage:       "(optional) Specify a namespace for deployments in order to group them in a meaningful way",
+		EnvVar:      "NAMESPACE",
+		Destination: &initialSSHArgs.Namespace,
+	},
+	cli.StringFlag{
+		Name:        "command, c",
+		Usage:       "(optional) Command to run instead of starting an interactive session",
+		Destination: &initialSSHArgs.Command,
+	},
+}
+
+func sshAction(c *cli.Context, sshArgs ssh.Args, provider iaas.Provider) error {
+	name := c.Args().Get(0)
+	target := c.Args().Get(1)
+	if name == "" || target == "" {
+		return errors.New("Usage is `control-tower ssh <name> <instance-group/index|director>`")
+	}
+
+	version := c.App.Version
+
+	client, err := buildExistingDeploymentClient(name, version, sshArgs.Namespace, provider)
+	if err != nil {
+		return err
+	}
+
+	return client.SSH(commandContext, target, sshArgs.Command)
+}
+
+func validateSSHArgs(c *cli.Context, sshArgs ssh.Args) (ssh.Args, error) {
+	err := sshArgs.MarkSetFlags(c)
+	if err != nil {
+		return sshArgs, fmt.Errorf("failed to mark set SSH flags: [%v]", err)
+	}
+
+	if err = sshArgs.Validate(); err != nil {
+		return sshArgs, fmt.Errorf("failed to validate SSH flags: [%v]", err)
+	}
+
+	return sshArgs, nil
+}
+
+var sshCmd = cli.Command{
+	Name:      "ssh",
+	Usage:     "Opens an SSH session on a Concourse VM or the BOSH director",
+	ArgsUsage: "<name> <instance-group/index|director>",
+	Flags:     sshFlags,
+	Action: func(c *cli.Context) error {
+		sshArgs, err := validateSSHArgs(c, initialSSHArgs)
+		if err != nil {
+			return events.Categorize(fmt.Errorf("Error validating args on ssh: [%v]", err), events.UsageCategory)
+		}
+		iaasName, err := iaas.Validate(sshArgs.IAAS)
+		if err != nil {
+			return fmt.Errorf("Error mapping to supported IAASes on ssh: [%v]", err)
+		}
+		provider, err := iaas.New(iaasName, sshArgs.Region)
+		if err != nil {
+			return fmt.Errorf("Error creating IAAS provider on ssh: [%v]", err)
+		}
+		return sshAction(c, sshArgs, provider)
+	},
+}
@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/EngineerBetter/control-tower/commands/unlock"
+	"github.com/EngineerBetter/control-tower/config"
+	"github.com/EngineerBetter/control-tower/events"
+	"github.com/EngineerBetter/control-tower/iaas"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+var initialUnlockArgs unlock.Args
+
+var unlockFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:        "region",
+		Usage:       "(optional) AWS region",
+		EnvVar:      "AWS_REGION",
+		Destination: &initialUnlockArgs.Region,
+	},
+	cli.StringFlag{
+		Name:        "iaas",
+		Usage:       "(required) IAAS, can be AWS, GCP or Azure",
+		EnvVar:      "IAAS",
+		Destination: &initialUnlockArgs.IAAS,
+	},
+	cli.StringFlag{
+		Name:        "namespace",
+		Usage:       "(optional) Specify a namespace for deployments in order to group them in a meaningful way",
+		EnvVar:      "NAMESPACE",
+		Destination: &initialUnlockArgs.Namespace,
+	},
+	cli.BoolFlag{
+		Name:        "force",
+		Usage:       "(optional) Break the lock even if it has not expired",
+		Destination: &initialUnlockArgs.Force,
+	},
+}
+
+func unlockAction(c *cli.Context, unlockArgs unlock.Args, provider iaas.Provider) error {
+	name := c.Args().Get(0)
+	if name == "" {
+		return errors.New("Usage is `control-tower unlock <name>`")
+	}
+
+	stateStore, err := newStateStore(provider)
+	if err != nil {
+		return err
+	}
+
+	lock, err := config.New(provider, stateStore, name, unlockArgs.Namespace).BreakLock(unlockArgs.Force)
+	if err != nil {
+		return err
+	}
+
+	if lock.ID == "" {
+		fmt.Printf("Deployment %s is not locked\n", name)
+		return nil
+	}
+
+	fmt.Printf("Released lock held by %s\n", lock)
+	return nil
+}
+
+func validateUnlockArgs(c *cli.Context, unlockArgs unlock.Args) (unlock.Args, error) {
+	err := unlockArgs.MarkSetFlags(c)
+	if err != nil {
+		return unlockArgs, fmt.Errorf("failed to mark set Unlock flags: [%v]", err)
+	}
+
+	if err = unlockArgs.Validate(); err != nil {
+		return unlockArgs, fmt.Errorf("failed to validate Unlock flags: [%v]", err)
+	}
+
+	return unlockArgs, nil
+}
+
+var unlockCmd = cli.Command{
+	Name:      "unlock",
+	Usage:     "Releases the lock taken on a deployment by deploy, destroy and maintain",
+	ArgsUsage: "<name>",
+	Flags:     unlockFlags,
+	Action: func(c *cli.Context) error {
+		unlockArgs, err := validateUnlockArgs(c, initialUnlockArgs)
+		if err != nil {
+			return events.Categorize(fmt.Errorf("Error validating args on unlock: [%v]", err), events.UsageCategory)
+		}
+		iaasName, err := iaas.Validate(unlockArgs.IAAS)
+		if err != nil {
+			return fmt.Errorf("Error mapping to supported IAASes on unlock: [%v]", err)
+		}
+		provider, err := iaas.New(iaasName, unlockArgs.Region)
+		if err != nil {
+			return fmt.Errorf("Error creating IAAS provider on unlock: [%v]", err)
+		}
+		return unlockAction(c, unlockArgs, provider)
+	},
+}
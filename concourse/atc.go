@@ -0,0 +1,188 @@
+package concourse
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/EngineerBetter/control-tower/config"
+	"golang.org/x/oauth2"
+)
+
+// atcClient reads worker and build state from the Concourse API
+type atcClient struct {
+	url        string
+	httpClient *http.Client
+}
+
+// workerLoad summarises the load on the default workers, which are the only ones without
+// tags or a team
+type workerLoad struct {
+	Workers        int
+	Containers     int
+	LandingWorkers int
+	PendingBuilds  int
+}
+
+type atcWorker struct {
+	Name             string   `json:"name"`
+	State            string   `json:"state"`
+	ActiveContainers int      `json:"active_containers"`
+	Tags             []string `json:"tags"`
+	Team             string   `json:"team"`
+}
+
+type atcBuild struct {
+	Status string `json:"status"`
+}
+
+// newATCClient logs in to Concourse as the admin user using the same OAuth client as fly
+func newATCClient(conf config.Config) (*atcClient, error) {
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil {
+		rootCAs = x509.NewCertPool()
+	}
+	rootCAs.AppendCertsFromPEM([]byte(conf.ConcourseCACert))
+
+	httpClient := &http.Client{
+		Timeout: time.Minute,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: rootCAs},
+		},
+	}
+
+	return loginATC(fmt.Sprintf("https://%s", conf.Domain), conf.ConcourseUsername, conf.ConcoursePassword, httpClient)
+}
+
+func loginATC(url, username, password string, httpClient *http.Client) (*atcClient, error) {
+	oauthConfig := oauth2.Config{
+		ClientID:     "fly",
+		ClientSecret: "Zmx5",
+		Endpoint:     oauth2.Endpoint{TokenURL: url + "/sky/token"},
+		Scopes:       []string{"openid", "profile", "email", "federated:id", "groups"},
+	}
+
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, httpClient)
+	token, err := oauthConfig.PasswordCredentialsToken(ctx, username, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to log in to %s: [%v]", url, err)
+	}
+
+	return &atcClient{
+		url:        url,
+		httpClient: oauthConfig.Client(ctx, token),
+	}, nil
+}
+
+// workerLoad fetches the containers on the default workers and the number of builds waiting to start
+func (c *atcClient) workerLoad() (workerLoad, error) {
+	var load workerLoad
+
+	var workers []atcWorker
+	if err := c.get("/api/v1/workers", &workers); err != nil {
+		return load, err
+	}
+	for _, worker := range workers {
+		if len(worker.Tags) > 0 || worker.Team != "" {
+			continue
+		}
+		switch worker.State {
+		case "running":
+			load.Workers++
+			load.Containers += worker.ActiveContainers
+		case "landing", "retiring":
+			load.LandingWorkers++
+			load.Containers += worker.ActiveContainers
+		}
+	}
+
+	// Builds are listed newest first. Pages are fetched until one has no builds waiting to start or running,
+	// as builds older than that have all finished
+	for path := "/api/v1/builds?limit=100"; path != ""; {
+		var builds []atcBuild
+		next, err := c.getPage(path, &builds)
+		if err != nil {
+			return load, err
+		}
+
+		unfinished := 0
+		for _, build := range builds {
+			switch build.Status {
+			case "pending":
+				load.PendingBuilds++
+				unfinished++
+			case "started":
+				unfinished++
+			}
+		}
+		if unfinished == 0 {
+			break
+		}
+		path = next
+	}
+
+	return load, nil
+}
+
+// leavingWorkers returns the number of workers, including those in worker pools, that are landing or retiring
+func (c *atcClient) leavingWorkers() (int, error) {
+	var workers []atcWorker
+	if err := c.get("/api/v1/workers", &workers); err != nil {
+		return 0, err
+	}
+
+	leaving := 0
+	for _, worker := range workers {
+		if worker.State == "landing" || worker.State == "retiring" {
+			leaving++
+		}
+	}
+	return leaving, nil
+}
+
+func (c *atcClient) get(path string, v interface{}) error {
+	_, err := c.getPage(path, v)
+	return err
+}
+
+// getPage decodes the response from path into v and returns the path of the next page, or an
+// empty string if this is the last page
+func (c *atcClient) getPage(path string, v interface{}) (string, error) {
+	resp, err := c.httpClient.Get(c.url + path)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GET %s returned %s", path, resp.Status)
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return "", fmt.Errorf("failed to decode response from %s: [%v]", path, err)
+	}
+	return nextPage(resp.Header.Get("Link")), nil
+}
+
+// nextPage returns the path of the rel="next" link in a Link header such as
+// `<https://ci.example.com/api/v1/builds?to=100&limit=100>; rel="next"`
+func nextPage(header string) string {
+	for _, link := range strings.Split(header, ",") {
+		parts := strings.Split(link, ";")
+		if len(parts) < 2 || strings.TrimSpace(parts[1]) != `rel="next"` {
+			continue
+		}
+		target, err := url.Parse(strings.Trim(strings.TrimSpace(parts[0]), "<>"))
+		if err != nil {
+			return ""
+		}
+		return target.RequestURI()
+	}
+	return ""
+}
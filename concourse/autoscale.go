@@ -0,0 +1,214 @@
+package concourse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/EngineerBetter/control-tower/commands/autoscale"
+	"github.com/EngineerBetter/control-tower/commands/scale"
+	"github.com/EngineerBetter/control-tower/config"
+	"github.com/EngineerBetter/control-tower/util"
+)
+
+const autoscaleStateFilename = "autoscale-state.json"
+
+const landPollInterval = 10 * time.Second
+
+// autoscaleState is kept alongside the config so that the cooldown is honoured by
+// separate runs of `autoscale --once`
+type autoscaleState struct {
+	LastScaled time.Time `json:"last_scaled"`
+}
+
+// Autoscale scales the default workers between the given bounds based on their load,
+// checking every interval until it fails or, with --once, after a single check
+func (client *Client) Autoscale(ctx context.Context, args autoscale.Args) error {
+	for {
+		err := client.autoscaleOnce(ctx, args, time.Now())
+		if args.Once {
+			return err
+		}
+		if err != nil {
+			fmt.Fprintf(client.stderr, "Autoscale check failed: %v\n", err)
+		}
+		if err = util.Sleep(ctx, args.Interval); err != nil {
+			return err
+		}
+	}
+}
+
+func (client *Client) autoscaleOnce(ctx context.Context, args autoscale.Args, now time.Time) error {
+	conf, err := client.configClient.Load()
+	if err != nil {
+		return fmt.Errorf("error loading config before autoscale: [%v]", err)
+	}
+
+	atc, err := client.atcClientFactory(conf)
+	if err != nil {
+		return err
+	}
+	load, err := atc.workerLoad()
+	if err != nil {
+		return fmt.Errorf("failed to fetch worker load: [%v]", err)
+	}
+
+	current := conf.ConcourseWorkerCount
+	desired := desiredWorkers(current, load, args)
+	fmt.Fprintf(client.stdout, "%d workers, %d containers, %d pending builds\n", current, load.Containers, load.PendingBuilds)
+
+	if desired == current {
+		return nil
+	}
+
+	state, err := client.loadAutoscaleState()
+	if err != nil {
+		return err
+	}
+	if remaining := args.Cooldown - now.Sub(state.LastScaled); remaining > 0 {
+		fmt.Fprintf(client.stdout, "Not scaling to %d workers for another %s as workers were scaled at %s\n", desired, remaining.Round(time.Second), state.LastScaled.Format(time.RFC3339))
+		return nil
+	}
+
+	return client.withLock(ctx, "autoscale", func(ctx context.Context) error {
+		return client.scaleWorkersTo(ctx, atc, current, desired, args.LandTimeout, now)
+	})
+}
+
+// scaleWorkersTo lands any workers that will be removed and scales from current to desired workers.
+// It must be called while holding the lock.
+func (client *Client) scaleWorkersTo(ctx context.Context, atc *atcClient, current, desired int, landTimeout time.Duration, now time.Time) error {
+	// Another run may have scaled the workers while this one was deciding to
+	conf, err := client.configClient.Load()
+	if err != nil {
+		return fmt.Errorf("error loading config before autoscale: [%v]", err)
+	}
+	if conf.ConcourseWorkerCount != current {
+		fmt.Fprintf(client.stdout, "Not scaling as the workers have been scaled to %d since they were checked\n", conf.ConcourseWorkerCount)
+		return nil
+	}
+
+	if desired < current {
+		if err = client.landWorkers(ctx, conf, desired); err != nil {
+			return err
+		}
+		landingWorkers := func() (int, error) {
+			load, err := atc.workerLoad()
+			return load.LandingWorkers, err
+		}
+		if err = waitForLeavingWorkers(ctx, landingWorkers, landTimeout); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(client.stdout, "Scaling from %d to %d workers\n", current, desired)
+	err = client.scale(ctx, scale.Args{WorkerCount: desired, WorkerCountIsSet: true})
+	if err != nil {
+		if desired < current {
+			// Otherwise the landed workers would stay registered but take no builds until the next deploy
+			if unlandErr := client.unlandWorkers(ctx, conf, desired); unlandErr != nil {
+				return fmt.Errorf("%v, and failed to unland workers: [%v]", err, unlandErr)
+			}
+		}
+		return err
+	}
+
+	return client.storeAutoscaleState(autoscaleState{LastScaled: now})
+}
+
+// desiredWorkers returns enough workers to keep each under containersPerWorker, adding one
+// when builds are waiting to start. Scaling in happens one worker at a time to avoid
+// removing capacity that a short lull in builds doesn't reflect
+func desiredWorkers(current int, load workerLoad, args autoscale.Args) int {
+	desired := (load.Containers + args.ContainersPerWorker - 1) / args.ContainersPerWorker
+	if load.PendingBuilds > 0 && desired <= current {
+		desired = current + 1
+	}
+	if desired < current-1 {
+		desired = current - 1
+	}
+	if desired < args.MinWorkers {
+		desired = args.MinWorkers
+	}
+	if desired > args.MaxWorkers {
+		desired = args.MaxWorkers
+	}
+	return desired
+}
+
+func (client *Client) landWorkers(ctx context.Context, conf config.Config, workerCount int) error {
+	tfOutputs, err := client.tfCLI.BuildOutput(ctx, client.tfInputVarsFactory.NewInputVars(conf))
+	if err != nil {
+		return err
+	}
+
+	boshClient, err := client.buildBoshClient(conf, tfOutputs)
+	if err != nil {
+		return err
+	}
+	defer boshClient.Cleanup()
+
+	return boshClient.LandWorkers(ctx, workerCount)
+}
+
+func (client *Client) unlandWorkers(ctx context.Context, conf config.Config, workerCount int) error {
+	tfOutputs, err := client.tfCLI.BuildOutput(ctx, client.tfInputVarsFactory.NewInputVars(conf))
+	if err != nil {
+		return err
+	}
+
+	boshClient, err := client.buildBoshClient(conf, tfOutputs)
+	if err != nil {
+		return err
+	}
+	defer boshClient.Cleanup()
+
+	return boshClient.UnlandWorkers(ctx, workerCount)
+}
+
+// waitForLeavingWorkers waits for the workers counted by leaving, which are landing or retiring, to
+// finish their running builds
+func waitForLeavingWorkers(ctx context.Context, leaving func() (int, error), timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		count, err := leaving()
+		if err != nil {
+			return fmt.Errorf("failed to fetch workers: [%v]", err)
+		}
+		if count == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%d workers were still landing or retiring after %s", count, timeout)
+		}
+		if err = util.Sleep(ctx, landPollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+func (client *Client) loadAutoscaleState() (autoscaleState, error) {
+	var state autoscaleState
+
+	hasState, err := client.configClient.HasAsset(autoscaleStateFilename)
+	if err != nil || !hasState {
+		return state, err
+	}
+
+	stateBytes, err := client.configClient.LoadAsset(autoscaleStateFilename)
+	if err != nil {
+		return state, err
+	}
+
+	err = json.Unmarshal(stateBytes, &state)
+	return state, err
+}
+
+func (client *Client) storeAutoscaleState(state autoscaleState) error {
+	stateBytes, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return client.configClient.StoreAsset(autoscaleStateFilename, stateBytes)
+}
@@ -0,0 +1,164 @@
+package concourse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/EngineerBetter/control-tower/bosh/boshfakes"
+	"github.com/EngineerBetter/control-tower/commands/autoscale"
+	"github.com/EngineerBetter/control-tower/config"
+	"github.com/EngineerBetter/control-tower/config/configfakes"
+)
+
+func TestDesiredWorkers(t *testing.T) {
+	args := autoscale.Args{MinWorkers: 1, MaxWorkers: 6, ContainersPerWorker: 100}
+
+	tests := []struct {
+		name    string
+		current int
+		load    workerLoad
+		want    int
+	}{
+		{"steady", 3, workerLoad{Containers: 250}, 3},
+		{"scales out to fit containers", 2, workerLoad{Containers: 450}, 5},
+		{"scales out for pending builds", 3, workerLoad{Containers: 120, PendingBuilds: 2}, 4},
+		{"scales in one worker at a time", 5, workerLoad{Containers: 50}, 4},
+		{"never scales below min", 1, workerLoad{}, 1},
+		{"never scales above max", 6, workerLoad{Containers: 900, PendingBuilds: 1}, 6},
+		{"scales up to min", 0, workerLoad{}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := desiredWorkers(tt.current, tt.load, args); got != tt.want {
+				t.Errorf("desiredWorkers() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestATCClientWorkerLoad(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sky/token":
+			if user, pass, _ := r.BasicAuth(); user != "fly" || pass != "Zmx5" || r.FormValue("username") != "admin" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"access_token":"token","token_type":"bearer"}`)
+		case "/api/v1/workers":
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `[
+{"name":"a","state":"running","active_containers":40},
+{"name":"b","state":"running","active_containers":60},
+{"name":"c","state":"landing","active_containers":5},
+{"name":"d","state":"landed","active_containers":0},
+{"name":"gpu","state":"running","active_containers":30,"tags":["gpu"]},
+{"name":"ml","state":"running","active_containers":30,"team":"ml"}
+]`)
+		case "/api/v1/builds":
+			fmt.Fprint(w, `[{"status":"pending"},{"status":"started"},{"status":"pending"},{"status":"succeeded"}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	atc, err := loginATC(server.URL, "admin", "password", server.Client())
+	if err != nil {
+		t.Fatalf("loginATC() error = %v", err)
+	}
+
+	load, err := atc.workerLoad()
+	if err != nil {
+		t.Fatalf("workerLoad() error = %v", err)
+	}
+	want := workerLoad{Workers: 2, Containers: 105, LandingWorkers: 1, PendingBuilds: 2}
+	if load != want {
+		t.Errorf("workerLoad() = %+v, want %+v", load, want)
+	}
+}
+
+func TestATCClientWorkerLoadPaginatesBuilds(t *testing.T) {
+	var requested []string
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.RequestURI())
+		switch r.URL.RequestURI() {
+		case "/api/v1/workers":
+			fmt.Fprint(w, `[]`)
+		case "/api/v1/builds?limit=100":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/api/v1/builds?to=10&limit=100>; rel="next"`, server.URL))
+			fmt.Fprint(w, `[{"status":"pending"},{"status":"started"}]`)
+		case "/api/v1/builds?to=10&limit=100":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/api/v1/builds?from=11&limit=100>; rel="previous", <%s/api/v1/builds?to=5&limit=100>; rel="next"`, server.URL, server.URL))
+			fmt.Fprint(w, `[{"status":"pending"},{"status":"succeeded"}]`)
+		case "/api/v1/builds?to=5&limit=100":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/api/v1/builds?to=1&limit=100>; rel="next"`, server.URL))
+			fmt.Fprint(w, `[{"status":"failed"},{"status":"succeeded"}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	atc := &atcClient{url: server.URL, httpClient: server.Client()}
+	load, err := atc.workerLoad()
+	if err != nil {
+		t.Fatalf("workerLoad() error = %v", err)
+	}
+	if load.PendingBuilds != 2 {
+		t.Errorf("workerLoad() PendingBuilds = %d, want 2", load.PendingBuilds)
+	}
+	if len(requested) != 4 {
+		t.Errorf("requested %v, want the workers and three pages of builds", requested)
+	}
+}
+
+func TestAutoscaleUnlandsWorkersWhenScaleFails(t *testing.T) {
+	server := newWorkersServer(`[
+{"name":"a","state":"running","active_containers":5},
+{"name":"b","state":"running","active_containers":5},
+{"name":"c","state":"running","active_containers":0}
+]`)
+	defer server.Close()
+
+	configClient := &configfakes.FakeIClient{}
+	configClient.LoadReturns(config.Config{ConcourseWorkerCount: 3}, nil)
+	boshClient := &boshfakes.FakeIClient{}
+	boshClient.ScaleWorkersReturns(nil, errors.New("deploy failed"))
+	var deployedConfig config.ConfigView
+
+	client := newScaleClient(configClient, boshClient, &deployedConfig)
+	client.stdout = ioutil.Discard
+	client.atcClientFactory = func(config.Config) (*atcClient, error) {
+		return &atcClient{url: server.URL, httpClient: server.Client()}, nil
+	}
+	args := autoscale.Args{MinWorkers: 1, MaxWorkers: 6, ContainersPerWorker: 100, LandTimeout: time.Minute}
+	err := client.autoscaleOnce(context.Background(), args, time.Now())
+	if err == nil || err.Error() != "deploy failed" {
+		t.Fatalf("autoscaleOnce() error = %v, want deploy failed", err)
+	}
+
+	if _, workerCount := boshClient.LandWorkersArgsForCall(0); workerCount != 2 {
+		t.Errorf("LandWorkers() called with %d, want 2", workerCount)
+	}
+	if boshClient.UnlandWorkersCallCount() != 1 {
+		t.Fatalf("UnlandWorkers() called %d times, want 1", boshClient.UnlandWorkersCallCount())
+	}
+	if _, workerCount := boshClient.UnlandWorkersArgsForCall(0); workerCount != 2 {
+		t.Errorf("UnlandWorkers() called with %d, want 2", workerCount)
+	}
+	if configClient.StoreAssetCallCount() != 1 {
+		t.Errorf("expected only the director creds to be stored, not the autoscale state")
+	}
+}
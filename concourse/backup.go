@@ -0,0 +1,217 @@
+package concourse
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/EngineerBetter/control-tower/bosh"
+	"github.com/EngineerBetter/control-tower/encryption"
+	"github.com/EngineerBetter/control-tower/events"
+)
+
+const (
+	backupsDir              = "backups"
+	backupArchiveFilename   = "databases.tar.gz.enc"
+	backupMetadataFilename  = "metadata.json"
+	backupIDTimestampFormat = "20060102T150405Z"
+)
+
+// BackupMetadata describes a stored database backup
+type BackupMetadata struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Version   string    `json:"version"`
+	Databases []string  `json:"databases"`
+}
+
+func backupAssetPath(id, filename string) string {
+	return path.Join(backupsDir, id, filename)
+}
+
+// Backup dumps the Concourse, CredHub and UAA databases into an encrypted archive in the config bucket
+func (client *Client) Backup(ctx context.Context) (*BackupMetadata, error) {
+	conf, err := client.configClient.Load()
+	if err != nil {
+		return nil, fmt.Errorf("error loading config before backup: [%v]", err)
+	}
+
+	tfInputVars := client.tfInputVarsFactory.NewInputVars(conf)
+	tfOutputs, err := client.tfCLI.BuildOutput(ctx, tfInputVars)
+	if err != nil {
+		return nil, err
+	}
+
+	boshClient, err := client.buildBoshClient(conf, tfOutputs)
+	if err != nil {
+		return nil, err
+	}
+	defer boshClient.Cleanup()
+
+	dumps, err := boshClient.BackupDatabases(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error dumping databases: [%v]", err)
+	}
+
+	archive, err := archiveDumps(dumps)
+	if err != nil {
+		return nil, fmt.Errorf("error archiving database dumps: [%v]", err)
+	}
+
+	now := time.Now().UTC()
+	metadata := BackupMetadata{
+		ID:        now.Format(backupIDTimestampFormat),
+		CreatedAt: now,
+		Version:   client.version,
+		Databases: bosh.BackupDatabaseNames,
+	}
+	metadataBytes, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	// The archive is encrypted with the key provider of the config bucket, never with a key kept in the bucket
+	err = client.configClient.StoreEncryptedAsset(backupAssetPath(metadata.ID, backupArchiveFilename), archive)
+	if err != nil {
+		return nil, fmt.Errorf("error storing backup: [%v]", err)
+	}
+	err = client.configClient.StoreAsset(backupAssetPath(metadata.ID, backupMetadataFilename), metadataBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error storing backup metadata: [%v]", err)
+	}
+
+	return &metadata, nil
+}
+
+// Restore stops the web instances, loads the given backup into the databases and starts them again
+func (client *Client) Restore(ctx context.Context, id string) error {
+	conf, err := client.configClient.Load()
+	if err != nil {
+		return fmt.Errorf("error loading config before restore: [%v]", err)
+	}
+
+	exists, err := client.configClient.HasAsset(backupAssetPath(id, backupMetadataFilename))
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("backup %s not found", id)
+	}
+
+	metadataBytes, err := client.configClient.LoadAsset(backupAssetPath(id, backupMetadataFilename))
+	if err != nil {
+		return err
+	}
+	var metadata BackupMetadata
+	if err = json.Unmarshal(metadataBytes, &metadata); err != nil {
+		return fmt.Errorf("error reading backup metadata: [%v]", err)
+	}
+	if metadata.Version != client.version {
+		events.Warning(fmt.Sprintf("backup %s was taken with control-tower %s, this is %s", id, metadata.Version, client.version))
+		_, err = fmt.Fprintf(client.stderr, "\nWARNING: backup %s was taken with control-tower %s, this is %s\n\n", id, metadata.Version, client.version)
+		if err != nil {
+			return err
+		}
+	}
+
+	archive, err := client.configClient.LoadAsset(backupAssetPath(id, backupArchiveFilename))
+	if err != nil {
+		return err
+	}
+	if !isGzip(archive) {
+		archive, err = encryption.DecryptLegacyBackup(conf.EncryptionKey, archive)
+		if err != nil {
+			return fmt.Errorf("error decrypting backup: [%v]", err)
+		}
+	}
+	dumps, err := unarchiveDumps(archive)
+	if err != nil {
+		return fmt.Errorf("error reading backup archive: [%v]", err)
+	}
+
+	tfInputVars := client.tfInputVarsFactory.NewInputVars(conf)
+	tfOutputs, err := client.tfCLI.BuildOutput(ctx, tfInputVars)
+	if err != nil {
+		return err
+	}
+
+	boshClient, err := client.buildBoshClient(conf, tfOutputs)
+	if err != nil {
+		return err
+	}
+	defer boshClient.Cleanup()
+
+	return boshClient.RestoreDatabases(ctx, dumps)
+}
+
+// isGzip returns true if archive was decrypted by LoadAsset, rather than being taken before backups were
+// encrypted with a key provider
+func isGzip(archive []byte) bool {
+	return len(archive) > 2 && archive[0] == 0x1f && archive[1] == 0x8b
+}
+
+func archiveDumps(dumps map[string][]byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+
+	for _, dbName := range bosh.BackupDatabaseNames {
+		dump, ok := dumps[dbName]
+		if !ok {
+			return nil, fmt.Errorf("missing dump of database %s", dbName)
+		}
+		err := tw.WriteHeader(&tar.Header{
+			Name: dbName + ".dump",
+			Mode: 0600,
+			Size: int64(len(dump)),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, err = tw.Write(dump); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func unarchiveDumps(archive []byte) (map[string][]byte, error) {
+	gr, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+
+	dumps := make(map[string][]byte)
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		dump, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		dumps[strings.TrimSuffix(header.Name, ".dump")] = dump
+	}
+	return dumps, nil
+}
@@ -0,0 +1,51 @@
+package concourse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestArchiveDumps(t *testing.T) {
+	dumps := map[string][]byte{
+		"concourse_atc": []byte("atc dump"),
+		"credhub":       []byte("credhub dump"),
+		"uaa":           []byte("uaa dump"),
+	}
+
+	archive, err := archiveDumps(dumps)
+	if err != nil {
+		t.Fatalf("archiveDumps() error = %v", err)
+	}
+
+	got, err := unarchiveDumps(archive)
+	if err != nil {
+		t.Fatalf("unarchiveDumps() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, dumps) {
+		t.Errorf("unarchiveDumps() = %v, want %v", got, dumps)
+	}
+}
+
+func TestArchiveDumpsMissingDatabase(t *testing.T) {
+	_, err := archiveDumps(map[string][]byte{"concourse_atc": []byte("atc dump")})
+	if err == nil || err.Error() != "missing dump of database credhub" {
+		t.Errorf("archiveDumps() error = %v, want missing dump of database credhub", err)
+	}
+}
+
+func TestIsGzip(t *testing.T) {
+	archive, err := archiveDumps(map[string][]byte{
+		"concourse_atc": []byte("atc dump"),
+		"credhub":       []byte("credhub dump"),
+		"uaa":           []byte("uaa dump"),
+	})
+	if err != nil {
+		t.Fatalf("archiveDumps() error = %v", err)
+	}
+	if !isGzip(archive) {
+		t.Errorf("isGzip() = false for an archive")
+	}
+	if isGzip([]byte("legacy ciphertext")) {
+		t.Errorf("isGzip() = true for a legacy encrypted archive")
+	}
+}
@@ -1,14 +1,20 @@
 package concourse
 
 import (
+	"context"
+	"fmt"
 	"io"
 
+	"github.com/EngineerBetter/control-tower/commands/autoscale"
+	"github.com/EngineerBetter/control-tower/commands/encrypt"
 	"github.com/EngineerBetter/control-tower/commands/maintain"
+	"github.com/EngineerBetter/control-tower/commands/scale"
 
 	"github.com/EngineerBetter/control-tower/bosh"
 	"github.com/EngineerBetter/control-tower/certs"
 	"github.com/EngineerBetter/control-tower/commands/deploy"
 	"github.com/EngineerBetter/control-tower/config"
+	"github.com/EngineerBetter/control-tower/events"
 	"github.com/EngineerBetter/control-tower/fly"
 	"github.com/EngineerBetter/control-tower/iaas"
 	"github.com/EngineerBetter/control-tower/terraform"
@@ -19,8 +25,9 @@ import (
 // client is a concrete implementation of IClient interface
 type Client struct {
 	acmeClientConstructor func(u *certs.User) (*lego.Client, error)
+	atcClientFactory      func(config.Config) (*atcClient, error)
 	boshClientFactory     bosh.ClientFactory
-	certGenerator         func(constructor func(u *certs.User) (*lego.Client, error), caName string, provider iaas.Provider, ip ...string) (*certs.Certs, error)
+	certGenerator         func(ctx context.Context, constructor func(u *certs.User) (*lego.Client, error), caName string, provider iaas.Provider, ip ...string) (*certs.Certs, error)
 	configClient          config.IClient
 	deployArgs            *deploy.Args
 	eightRandomLetters    func() string
@@ -39,10 +46,21 @@ type Client struct {
 
 // IClient represents a control-tower client
 type IClient interface {
-	Deploy() error
-	Destroy() error
-	FetchInfo() (*Info, error)
-	Maintain(maintain.Args) error
+	Autoscale(context.Context, autoscale.Args) error
+	Backup(context.Context) (*BackupMetadata, error)
+	Deploy(context.Context) error
+	Destroy(context.Context) error
+	EncryptAssets(context.Context, encrypt.Args) error
+	FetchInfo(context.Context) (*Info, error)
+	History() ([]Revision, error)
+	Logs(context.Context, string, string, bool, string) error
+	Maintain(context.Context, maintain.Args) error
+	MaintenanceStatus() ([]MaintenanceStatus, error)
+	Plan(context.Context) (*Plan, error)
+	Restore(context.Context, string) error
+	Rollback(context.Context, int) error
+	Scale(context.Context, scale.Args) error
+	SSH(context.Context, string, string) error
 }
 
 // New returns a new client
@@ -52,7 +70,7 @@ func NewClient(
 	tfInputVarsFactory TFInputVarsFactory,
 	boshClientFactory bosh.ClientFactory,
 	flyClientFactory func(iaas.Provider, fly.Credentials, io.Writer, io.Writer, []byte) (fly.IClient, error),
-	certGenerator func(constructor func(u *certs.User) (*lego.Client, error), caName string, provider iaas.Provider, ip ...string) (*certs.Certs, error),
+	certGenerator func(ctx context.Context, constructor func(u *certs.User) (*lego.Client, error), caName string, provider iaas.Provider, ip ...string) (*certs.Certs, error),
 	configClient config.IClient,
 	deployArgs *deploy.Args,
 	stdout, stderr io.Writer,
@@ -65,6 +83,7 @@ func NewClient(
 	versionFile []byte) *Client {
 	return &Client{
 		acmeClientConstructor: acmeClientConstructor,
+		atcClientFactory:      newATCClient,
 		boshClientFactory:     boshClientFactory,
 		certGenerator:         certGenerator,
 		configClient:          configClient,
@@ -95,3 +114,39 @@ func (client *Client) buildBoshClient(config config.ConfigView, tfOutputs terraf
 		client.versionFile,
 	)
 }
+
+// withLock runs f while holding the lock on the config bucket, releasing it however f finishes.
+// The context passed to f is cancelled if the lock cannot be renewed.
+func (client *Client) withLock(ctx context.Context, command string, f func(ctx context.Context) error) (err error) {
+	lockCtx, release, err := client.acquireLock(ctx, command)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		releaseErr := release()
+		if releaseErr == nil {
+			return
+		}
+		// When the lock was lost f fails because it was cancelled, which hides why
+		if err == nil || (lockCtx.Err() != nil && ctx.Err() == nil) {
+			err = events.Categorize(fmt.Errorf("error releasing lock: [%v]", releaseErr), events.LockCategory)
+		}
+	}()
+
+	return f(lockCtx)
+}
+
+// acquireLock takes the deployment lock for command. Commands which take it change the deployment, so it
+// also stores the config upgraded to the current schema before they write to it.
+func (client *Client) acquireLock(ctx context.Context, command string) (context.Context, func() error, error) {
+	lockCtx, release, err := client.configClient.AcquireLock(ctx, command)
+	if err != nil {
+		return nil, nil, events.Categorize(fmt.Errorf("error acquiring lock: [%v]", err), events.LockCategory)
+	}
+	if err = client.configClient.UpgradeConfig(); err != nil {
+		release()
+		return nil, nil, fmt.Errorf("error upgrading config: [%v]", err)
+	}
+	return lockCtx, release, nil
+}
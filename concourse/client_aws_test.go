@@ -1,6 +1,7 @@
 package concourse_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -19,6 +20,7 @@ import (
 	"github.com/EngineerBetter/control-tower/fly/flyfakes"
 	"github.com/EngineerBetter/control-tower/iaas"
 	"github.com/EngineerBetter/control-tower/iaas/iaasfakes"
+	"github.com/EngineerBetter/control-tower/store"
 	"github.com/EngineerBetter/control-tower/terraform"
 	"github.com/EngineerBetter/control-tower/terraform/terraformfakes"
 	. "github.com/onsi/ginkgo"
@@ -55,7 +57,7 @@ var _ = Describe("client", func() {
 			}
 			return true, nil
 		}
-		provider.DeleteVMsInVPCStub = func(vpcID string) ([]string, error) {
+		provider.DeleteVMsInVPCStub = func(ctx context.Context, vpcID string) ([]string, error) {
 			actions = append(actions, fmt.Sprintf("deleting vms in %s", vpcID))
 			return nil, nil
 		}
@@ -74,7 +76,7 @@ var _ = Describe("client", func() {
 
 		provider, err := iaas.New(iaas.AWS, "eu-west-1")
 		Expect(err).ToNot(HaveOccurred())
-		awsInputVarsFactory, err := concourse.NewTFInputVarsFactory(provider)
+		awsInputVarsFactory, err := concourse.NewTFInputVarsFactory(provider, store.FromIAAS(provider))
 		Expect(err).ToNot(HaveOccurred())
 		tfInputVarsFactory.NewInputVarsStub = func(i config.ConfigView) terraform.InputVars {
 			actions = append(actions, "converting config.Config to TFInputVars")
@@ -85,6 +87,7 @@ var _ = Describe("client", func() {
 
 	var setupFakeConfigClient = func() *configfakes.FakeIClient {
 		configClient = &configfakes.FakeIClient{}
+		configClient.AcquireLockReturns(context.Background(), func() error { return nil }, nil)
 		configClient.LoadStub = func() (config.Config, error) {
 			actions = append(actions, "loading config file")
 			return configInBucket, nil
@@ -110,15 +113,15 @@ var _ = Describe("client", func() {
 
 	var setupFakeTerraformCLI = func(terraformOutputs terraform.AWSOutputs) *terraformfakes.FakeCLIInterface {
 		terraformCLI = &terraformfakes.FakeCLIInterface{}
-		terraformCLI.ApplyStub = func(inputVars terraform.InputVars) error {
+		terraformCLI.ApplyStub = func(ctx context.Context, inputVars terraform.InputVars) (terraform.Outputs, error) {
 			actions = append(actions, "applying terraform")
-			return nil
+			return &terraformOutputs, nil
 		}
-		terraformCLI.DestroyStub = func(conf terraform.InputVars) error {
+		terraformCLI.DestroyStub = func(ctx context.Context, conf terraform.InputVars) error {
 			actions = append(actions, "destroying terraform")
 			return nil
 		}
-		terraformCLI.BuildOutputStub = func(conf terraform.InputVars) (terraform.Outputs, error) {
+		terraformCLI.BuildOutputStub = func(ctx context.Context, conf terraform.InputVars) (terraform.Outputs, error) {
 			actions = append(actions, "initializing terraform outputs")
 			return &terraformOutputs, nil
 		}
@@ -132,7 +135,7 @@ var _ = Describe("client", func() {
 		directorCredsFixture, err = ioutil.ReadFile("fixtures/director-creds.yml")
 		Expect(err).ToNot(HaveOccurred())
 
-		certGenerator := func(c func(u *certs.User) (*lego.Client, error), caName string, provider iaas.Provider, ip ...string) (*certs.Certs, error) {
+		certGenerator := func(ctx context.Context, c func(u *certs.User) (*lego.Client, error), caName string, provider iaas.Provider, ip ...string) (*certs.Certs, error) {
 			actions = append(actions, fmt.Sprintf("generating cert ca: %s, cn: %s", caName, ip))
 			return &certs.Certs{
 				CACert: []byte("----EXAMPLE CERT----"),
@@ -144,7 +147,7 @@ var _ = Describe("client", func() {
 		configClient = setupFakeConfigClient()
 
 		flyClient = &flyfakes.FakeIClient{}
-		flyClient.SetDefaultPipelineStub = func(config config.ConfigView, allowFlyVersionDiscrepancy bool) error {
+		flyClient.SetDefaultPipelineStub = func(ctx context.Context, config config.ConfigView, allowFlyVersionDiscrepancy bool) error {
 			actions = append(actions, "setting default pipeline")
 			return nil
 		}
@@ -260,7 +263,7 @@ sWbB3FCIsym1FXB+eRnVF3Y15RwBWWKA5RfwUNpEXFxtv24tQ8jrdA==
 
 		boshClientFactory := func(config config.ConfigView, outputs terraform.Outputs, stdout, stderr io.Writer, provider iaas.Provider, versionFile []byte) (bosh.IClient, error) {
 			boshClient = &boshfakes.FakeIClient{}
-			boshClient.DeployStub = func(stateFileBytes, credsFileBytes []byte, detach bool) ([]byte, []byte, error) {
+			boshClient.DeployStub = func(ctx context.Context, stateFileBytes, credsFileBytes []byte, detach bool, checkpoint bosh.Checkpoint) ([]byte, []byte, error) {
 				if detach {
 					actions = append(actions, "deploying director in self-update mode")
 				} else {
@@ -272,7 +275,7 @@ sWbB3FCIsym1FXB+eRnVF3Y15RwBWWKA5RfwUNpEXFxtv24tQ8jrdA==
 				actions = append(actions, "cleaning up bosh init")
 				return nil
 			}
-			boshClient.InstancesStub = func() ([]bosh.Instance, error) {
+			boshClient.InstancesStub = func(ctx context.Context) ([]bosh.Instance, error) {
 				actions = append(actions, "listing bosh instances")
 				return nil, nil
 			}
@@ -317,27 +320,27 @@ sWbB3FCIsym1FXB+eRnVF3Y15RwBWWKA5RfwUNpEXFxtv24tQ8jrdA==
 	Describe("Destroy", func() {
 		It("Loads the config file", func() {
 			client := buildClient()
-			err := client.Destroy()
+			err := client.Destroy(context.Background())
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(actions).To(ContainElement("loading config file"))
 		})
 		It("Builds IAAS environment", func() {
 			client := buildClient()
-			err := client.Destroy()
+			err := client.Destroy(context.Background())
 			Expect(err).ToNot(HaveOccurred())
 			Expect(tfInputVarsFactory).To(HaveReceived("NewInputVars").With(configInBucket))
 		})
 		It("Loads terraform output", func() {
 			client := buildClient()
-			err := client.Destroy()
+			err := client.Destroy(context.Background())
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(actions).To(ContainElement("initializing terraform outputs"))
 		})
 		It("Deletes the vms in the vpcs", func() {
 			client := buildClient()
-			err := client.Destroy()
+			err := client.Destroy(context.Background())
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(actions).To(ContainElement("deleting vms in vpc-112233"))
@@ -345,7 +348,7 @@ sWbB3FCIsym1FXB+eRnVF3Y15RwBWWKA5RfwUNpEXFxtv24tQ8jrdA==
 
 		It("Destroys the terraform infrastructure", func() {
 			client := buildClient()
-			err := client.Destroy()
+			err := client.Destroy(context.Background())
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(actions).To(ContainElement("destroying terraform"))
@@ -353,7 +356,7 @@ sWbB3FCIsym1FXB+eRnVF3Y15RwBWWKA5RfwUNpEXFxtv24tQ8jrdA==
 
 		It("Deletes the config", func() {
 			client := buildClient()
-			err := client.Destroy()
+			err := client.Destroy(context.Background())
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(actions).To(ContainElement("deleting config"))
@@ -361,35 +364,90 @@ sWbB3FCIsym1FXB+eRnVF3Y15RwBWWKA5RfwUNpEXFxtv24tQ8jrdA==
 
 		It("Prints a destroy success message", func() {
 			client := buildClient()
-			err := client.Destroy()
+			err := client.Destroy(context.Background())
 			Expect(err).ToNot(HaveOccurred())
 
 			Eventually(stdout).Should(gbytes.Say("DESTROY SUCCESSFUL"))
 		})
+
+		It("Releases the lock before deleting the config", func() {
+			configClient.AcquireLockReturns(context.Background(), func() error {
+				actions = append(actions, "releasing lock")
+				return nil
+			}, nil)
+			client := buildClient()
+			err := client.Destroy(context.Background())
+			Expect(err).ToNot(HaveOccurred())
+
+			_, command := configClient.AcquireLockArgsForCall(0)
+			Expect(command).To(Equal("destroy"))
+			Expect(actions).To(ContainElement("deleting config"))
+			for _, action := range actions {
+				if action == "deleting config" {
+					Fail("config deleted while the lock was held")
+				}
+				if action == "releasing lock" {
+					break
+				}
+			}
+		})
+
+		Context("when the deployment is locked", func() {
+			BeforeEach(func() {
+				configClient.AcquireLockReturns(nil, nil, errors.New("deployment is locked by someone"))
+			})
+
+			It("does nothing", func() {
+				client := buildClient()
+				err := client.Destroy(context.Background())
+				Expect(err).To(MatchError("error acquiring lock: [deployment is locked by someone]"))
+				Expect(actions).ToNot(ContainElement("destroying terraform"))
+			})
+		})
+
+		Context("when destroying fails", func() {
+			BeforeEach(func() {
+				terraformCLI.DestroyStub = func(context.Context, terraform.InputVars) error {
+					return errors.New("some terraform error")
+				}
+			})
+
+			It("releases the lock", func() {
+				released := false
+				configClient.AcquireLockReturns(context.Background(), func() error {
+					released = true
+					return nil
+				}, nil)
+				client := buildClient()
+				err := client.Destroy(context.Background())
+				Expect(err).To(MatchError("some terraform error"))
+				Expect(released).To(BeTrue())
+			})
+		})
 	})
 
 	Describe("FetchInfo", func() {
 		BeforeEach(func() {
-			configClient.HasAssetReturnsOnCall(0, true, nil)
+			configClient.HasAssetReturnsOnCall(1, true, nil)
 			configClient.LoadAssetReturnsOnCall(0, directorCredsFixture, nil)
 		})
 		It("Loads the config file", func() {
 			client := buildClient()
-			_, err := client.FetchInfo()
+			_, err := client.FetchInfo(context.Background())
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(actions).To(ContainElement("loading config file"))
 		})
 		It("calls TFInputVarsFactory, having populated AllowIPs and SourceAccessIPs", func() {
 			client := buildClient()
-			err := client.Deploy()
+			err := client.Deploy(context.Background())
 			Expect(err).ToNot(HaveOccurred())
 			Expect(tfInputVarsFactory).To(HaveReceived("NewInputVars").With(configAfterLoad))
 		})
 
 		It("Loads terraform output", func() {
 			client := buildClient()
-			_, err := client.FetchInfo()
+			_, err := client.FetchInfo(context.Background())
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(actions).To(ContainElement("initializing terraform outputs"))
@@ -397,7 +455,7 @@ sWbB3FCIsym1FXB+eRnVF3Y15RwBWWKA5RfwUNpEXFxtv24tQ8jrdA==
 
 		It("Checks that the IP is whitelisted", func() {
 			client := buildClient()
-			_, err := client.FetchInfo()
+			_, err := client.FetchInfo(context.Background())
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(actions).To(ContainElement("checking security group for IP"))
@@ -405,7 +463,7 @@ sWbB3FCIsym1FXB+eRnVF3Y15RwBWWKA5RfwUNpEXFxtv24tQ8jrdA==
 
 		It("Retrieves the BOSH instances", func() {
 			client := buildClient()
-			_, err := client.FetchInfo()
+			_, err := client.FetchInfo(context.Background())
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(actions).To(ContainElement("listing bosh instances"))
@@ -420,7 +478,7 @@ sWbB3FCIsym1FXB+eRnVF3Y15RwBWWKA5RfwUNpEXFxtv24tQ8jrdA==
 
 			It("Returns a meaningful error", func() {
 				client := buildClient()
-				_, err := client.FetchInfo()
+				_, err := client.FetchInfo(context.Background())
 				Expect(err).To(MatchError("Do you need to add your IP 1.2.3.4 to the control-tower-happymeal-director security group/source range entry for director firewall (for ports 22, 6868, and 25555)?"))
 			})
 		})
@@ -1,6 +1,7 @@
 package concourse_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -19,6 +20,7 @@ import (
 	"github.com/EngineerBetter/control-tower/fly/flyfakes"
 	"github.com/EngineerBetter/control-tower/iaas"
 	"github.com/EngineerBetter/control-tower/iaas/iaasfakes"
+	"github.com/EngineerBetter/control-tower/store"
 	"github.com/EngineerBetter/control-tower/terraform"
 	"github.com/EngineerBetter/control-tower/terraform/terraformfakes"
 	. "github.com/onsi/ginkgo"
@@ -83,7 +85,7 @@ var _ = Describe("client", func() {
 
 		provider, err := iaas.New(iaas.AWS, "eu-west-1")
 		Expect(err).ToNot(HaveOccurred())
-		awsInputVarsFactory, err := concourse.NewTFInputVarsFactory(provider)
+		awsInputVarsFactory, err := concourse.NewTFInputVarsFactory(provider, store.FromIAAS(provider))
 		Expect(err).ToNot(HaveOccurred())
 		tfInputVarsFactory.NewInputVarsStub = func(i config.ConfigView) terraform.InputVars {
 			return awsInputVarsFactory.NewInputVars(i)
@@ -93,6 +95,7 @@ var _ = Describe("client", func() {
 
 	var setupFakeTerraformCLI = func(terraformOutputs terraform.AWSOutputs) *terraformfakes.FakeCLIInterface {
 		terraformCLI = &terraformfakes.FakeCLIInterface{}
+		terraformCLI.ApplyReturns(&terraformOutputs, nil)
 		terraformCLI.BuildOutputReturns(&terraformOutputs, nil)
 		return terraformCLI
 	}
@@ -210,7 +213,7 @@ sWbB3FCIsym1FXB+eRnVF3Y15RwBWWKA5RfwUNpEXFxtv24tQ8jrdA==
 	})
 
 	JustBeforeEach(func() {
-		certGenerator := func(c func(u *certs.User) (*lego.Client, error), caName string, provider iaas.Provider, ip ...string) (*certs.Certs, error) {
+		certGenerator := func(ctx context.Context, c func(u *certs.User) (*lego.Client, error), caName string, provider iaas.Provider, ip ...string) (*certs.Certs, error) {
 			certGenerationActions = append(certGenerationActions, fmt.Sprintf("generating cert ca: %s, cn: %s", caName, ip))
 			return &certs.Certs{
 				CACert: []byte("----EXAMPLE CERT----"),
@@ -222,6 +225,7 @@ sWbB3FCIsym1FXB+eRnVF3Y15RwBWWKA5RfwUNpEXFxtv24tQ8jrdA==
 		otherRegionClient := setupFakeOtherRegionProvider()
 		tfInputVarsFactory = setupFakeTfInputVarsFactory()
 		configClient = &configfakes.FakeIClient{}
+		configClient.AcquireLockReturns(context.Background(), func() error { return nil }, nil)
 		terraformCLI = setupFakeTerraformCLI(terraformOutputs)
 
 		boshClientFactory := func(config config.ConfigView, outputs terraform.Outputs, stdout, stderr io.Writer, provider iaas.Provider, versionFile []byte) (bosh.IClient, error) {
@@ -312,6 +316,7 @@ sWbB3FCIsym1FXB+eRnVF3Y15RwBWWKA5RfwUNpEXFxtv24tQ8jrdA==
 						Deployment:             configAfterLoad.Deployment,
 						HostedZoneID:           configAfterLoad.HostedZoneID,
 						HostedZoneRecordPrefix: configAfterLoad.HostedZoneRecordPrefix,
+						LockTable:              iaas.TerraformLockTable(configAfterLoad.ConfigBucket),
 						Namespace:              configAfterLoad.Namespace,
 						NetworkCIDR:            configAfterLoad.NetworkCIDR,
 						PrivateCIDR:            configAfterLoad.PrivateCIDR,
@@ -376,15 +381,23 @@ wEW5QkylaPEkbVDhJWeR1I8=
 				JustBeforeEach(func() {
 					configClient.LoadReturns(configInBucket, nil)
 					configClient.ConfigExistsReturns(true, nil)
-					configClient.HasAssetReturnsOnCall(0, true, nil)
-					configClient.LoadAssetReturnsOnCall(0, directorStateFixture, nil)
-					configClient.HasAssetReturnsOnCall(1, true, nil)
-					configClient.LoadAssetReturnsOnCall(1, directorCredsFixture, nil)
+					configClient.HasAssetStub = func(filename string) (bool, error) {
+						return filename == "director-state.json" || filename == "director-creds.yml", nil
+					}
+					configClient.LoadAssetStub = func(filename string) ([]byte, error) {
+						switch filename {
+						case "director-state.json":
+							return directorStateFixture, nil
+						case "director-creds.yml":
+							return directorCredsFixture, nil
+						}
+						return nil, nil
+					}
 				})
 
 				It("does all the things in the right order", func() {
 					client := buildClient()
-					err := client.Deploy()
+					err := client.Deploy(context.Background())
 					Expect(err).ToNot(HaveOccurred())
 
 					tfInputVarsFactory.NewInputVarsReturns(terraformInputVars)
@@ -393,33 +406,30 @@ wEW5QkylaPEkbVDhJWeR1I8=
 					Expect(configClient).To(HaveReceived("ConfigExists"))
 					Expect(configClient).To(HaveReceived("Load"))
 					Expect(tfInputVarsFactory).To(HaveReceived("NewInputVars").With(configAfterLoad))
-					Expect(terraformCLI).To(HaveReceived("Apply").With(terraformInputVars))
-					Expect(terraformCLI).To(HaveReceived("BuildOutput").With(terraformInputVars))
+					Expect(terraformCLI).To(HaveReceived("Apply").With(context.Background(), terraformInputVars))
+					Expect(terraformCLI).ToNot(HaveReceived("BuildOutput"))
 					Expect(configClient).To(HaveReceived("Update").With(configAfterLoad))
 
 					Expect(certGenerationActions[0]).To(Equal("generating cert ca: control-tower-happymeal, cn: [99.99.99.99 10.0.0.6]"))
 					Expect(certGenerationActions[1]).To(Equal("generating cert ca: control-tower-happymeal, cn: [77.77.77.77]"))
 
+					Expect(configClient.HasAssetArgsForCall(0)).To(Equal("deploy-checkpoint.json"))
 					Expect(configClient).To(HaveReceived("HasAsset").With("director-state.json"))
-					Expect(configClient.HasAssetArgsForCall(0)).To(Equal("director-state.json"))
 					Expect(configClient).To(HaveReceived("LoadAsset").With("director-state.json"))
-					Expect(configClient.LoadAssetArgsForCall(0)).To(Equal("director-state.json"))
 					Expect(configClient).To(HaveReceived("HasAsset").With("director-creds.yml"))
-					Expect(configClient.HasAssetArgsForCall(1)).To(Equal("director-creds.yml"))
 					Expect(configClient).To(HaveReceived("LoadAsset").With("director-creds.yml"))
-					Expect(configClient.LoadAssetArgsForCall(1)).To(Equal("director-creds.yml"))
-					Expect(boshClient).To(HaveReceived("Deploy").With(directorStateFixture, directorCredsFixture, false))
+					Expect(boshClient).To(HaveReceived("Deploy").With(context.Background(), directorStateFixture, directorCredsFixture, false, Not(BeNil())))
 
 					Expect(configClient).To(HaveReceived("StoreAsset").With("director-state.json", directorStateFixture))
 					Expect(configClient).To(HaveReceived("StoreAsset").With("director-creds.yml", directorCredsFixture))
 					Expect(boshClient).To(HaveReceived("Cleanup"))
-					Expect(flyClient).To(HaveReceived("SetDefaultPipeline").With(configAfterCreateEnv, false))
+					Expect(flyClient).To(HaveReceived("SetDefaultPipeline").With(context.Background(), configAfterCreateEnv, false))
 					Expect(configClient).To(HaveReceived("Update").With(configAfterConcourseDeploy))
 				})
 
 				It("Warns about access to local machine", func() {
 					client := buildClient()
-					err := client.Deploy()
+					err := client.Deploy(context.Background())
 					Expect(err).ToNot(HaveOccurred())
 
 					Eventually(stderr).Should(gbytes.Say("WARNING: allowing access from local machine"))
@@ -427,7 +437,7 @@ wEW5QkylaPEkbVDhJWeR1I8=
 
 				It("Prints the bosh credentials", func() {
 					client := buildClient()
-					err := client.Deploy()
+					err := client.Deploy(context.Background())
 					Expect(err).ToNot(HaveOccurred())
 					Eventually(stdout).Should(gbytes.Say("DEPLOY SUCCESSFUL"))
 					Eventually(stdout).Should(gbytes.Say("fly --target happymeal login --insecure --concourse-url https://77.77.77.77 --username admin --password s3cret"))
@@ -435,7 +445,7 @@ wEW5QkylaPEkbVDhJWeR1I8=
 
 				It("Notifies the user", func() {
 					client := buildClient()
-					err := client.Deploy()
+					err := client.Deploy(context.Background())
 					Expect(err).ToNot(HaveOccurred())
 
 					Eventually(stdout).Should(gbytes.Say("USING PREVIOUS DEPLOYMENT CONFIG"))
@@ -457,15 +467,23 @@ wEW5QkylaPEkbVDhJWeR1I8=
 				JustBeforeEach(func() {
 					configClient.LoadReturns(configInBucket, nil)
 					configClient.ConfigExistsReturns(true, nil)
-					configClient.HasAssetReturnsOnCall(0, true, nil)
-					configClient.LoadAssetReturnsOnCall(0, directorStateFixture, nil)
-					configClient.HasAssetReturnsOnCall(1, true, nil)
-					configClient.LoadAssetReturnsOnCall(1, directorCredsFixture, nil)
+					configClient.HasAssetStub = func(filename string) (bool, error) {
+						return filename == "director-state.json" || filename == "director-creds.yml", nil
+					}
+					configClient.LoadAssetStub = func(filename string) ([]byte, error) {
+						switch filename {
+						case "director-state.json":
+							return directorStateFixture, nil
+						case "director-creds.yml":
+							return directorCredsFixture, nil
+						}
+						return nil, nil
+					}
 				})
 
 				It("fails with a warning about not being able to specify CIDRs after first deploy", func() {
 					client := buildClient()
-					err := client.Deploy()
+					err := client.Deploy(context.Background())
 					Expect(err).To(HaveOccurred())
 					Expect(err.Error()).To(ContainSubstring("custom CIDRs cannot be applied after intial deploy"))
 				})
@@ -530,6 +548,7 @@ wEW5QkylaPEkbVDhJWeR1I8=
 						Deployment:             configAfterLoad.Deployment,
 						HostedZoneID:           configAfterLoad.HostedZoneID,
 						HostedZoneRecordPrefix: configAfterLoad.HostedZoneRecordPrefix,
+						LockTable:              iaas.TerraformLockTable(configAfterLoad.ConfigBucket),
 						Namespace:              configAfterLoad.Namespace,
 						NetworkCIDR:            configAfterLoad.NetworkCIDR,
 						PrivateCIDR:            configAfterLoad.PrivateCIDR,
@@ -562,39 +581,43 @@ wEW5QkylaPEkbVDhJWeR1I8=
 				JustBeforeEach(func() {
 					configClient.LoadReturns(configInBucket, nil)
 					configClient.ConfigExistsReturns(true, nil)
-					configClient.HasAssetReturnsOnCall(0, true, nil)
-					configClient.LoadAssetReturnsOnCall(0, directorStateFixture, nil)
-					configClient.HasAssetReturnsOnCall(1, true, nil)
-					configClient.LoadAssetReturnsOnCall(1, directorCredsFixture, nil)
+					configClient.HasAssetStub = func(filename string) (bool, error) {
+						return filename == "director-state.json" || filename == "director-creds.yml", nil
+					}
+					configClient.LoadAssetStub = func(filename string) ([]byte, error) {
+						switch filename {
+						case "director-state.json":
+							return directorStateFixture, nil
+						case "director-creds.yml":
+							return directorCredsFixture, nil
+						}
+						return nil, nil
+					}
 				})
 
 				It("updates config and calls collaborators with the current arguments", func() {
 					client := buildClient()
-					err := client.Deploy()
+					err := client.Deploy(context.Background())
 					Expect(err).ToNot(HaveOccurred())
 
 					Expect(configClient).To(HaveReceived("ConfigExists"))
 					Expect(configClient).To(HaveReceived("Load"))
 					Expect(tfInputVarsFactory).To(HaveReceived("NewInputVars").With(configAfterLoad))
 
-					Expect(terraformCLI).To(HaveReceived("Apply").With(terraformInputVars))
-					Expect(terraformCLI).To(HaveReceived("BuildOutput").With(terraformInputVars))
+					Expect(terraformCLI).To(HaveReceived("Apply").With(context.Background(), terraformInputVars))
+					Expect(terraformCLI).ToNot(HaveReceived("BuildOutput"))
 					Expect(configClient).To(HaveReceived("Update").With(configAfterLoad))
 
 					Expect(configClient).To(HaveReceived("HasAsset").With("director-state.json"))
-					Expect(configClient.HasAssetArgsForCall(0)).To(Equal("director-state.json"))
 					Expect(configClient).To(HaveReceived("LoadAsset").With("director-state.json"))
-					Expect(configClient.LoadAssetArgsForCall(0)).To(Equal("director-state.json"))
 					Expect(configClient).To(HaveReceived("HasAsset").With("director-creds.yml"))
-					Expect(configClient.HasAssetArgsForCall(1)).To(Equal("director-creds.yml"))
 					Expect(configClient).To(HaveReceived("LoadAsset").With("director-creds.yml"))
-					Expect(configClient.LoadAssetArgsForCall(1)).To(Equal("director-creds.yml"))
-					Expect(boshClient).To(HaveReceived("Deploy").With(directorStateFixture, directorCredsFixture, false))
+					Expect(boshClient).To(HaveReceived("Deploy").With(context.Background(), directorStateFixture, directorCredsFixture, false, Not(BeNil())))
 
 					Expect(configClient).To(HaveReceived("StoreAsset").With("director-state.json", directorStateFixture))
 					Expect(configClient).To(HaveReceived("StoreAsset").With("director-creds.yml", directorCredsFixture))
 					Expect(boshClient).To(HaveReceived("Cleanup"))
-					Expect(flyClient).To(HaveReceived("SetDefaultPipeline").With(configAfterCreateEnv, false))
+					Expect(flyClient).To(HaveReceived("SetDefaultPipeline").With(context.Background(), configAfterCreateEnv, false))
 					Expect(configClient).To(HaveReceived("Update").With(configAfterConcourseDeploy))
 				})
 			})
@@ -700,13 +723,11 @@ wEW5QkylaPEkbVDhJWeR1I8=
 					Region:       "eu-west-1",
 					TFStatePath:  "terraform.tfstate",
 				})
-				configClient.HasAssetReturnsOnCall(0, false, nil)
-				configClient.HasAssetReturnsOnCall(1, false, nil)
 			})
 
 			It("does the right things in the right order", func() {
 				client := buildClient()
-				err := client.Deploy()
+				err := client.Deploy(context.Background())
 				Expect(err).ToNot(HaveOccurred())
 
 				terraformInputVars := &terraform.AWSInputVars{
@@ -719,6 +740,7 @@ wEW5QkylaPEkbVDhJWeR1I8=
 					Deployment:             defaultGeneratedConfig.Deployment,
 					HostedZoneID:           defaultGeneratedConfig.HostedZoneID,
 					HostedZoneRecordPrefix: defaultGeneratedConfig.HostedZoneRecordPrefix,
+					LockTable:              iaas.TerraformLockTable(defaultGeneratedConfig.ConfigBucket),
 					Namespace:              defaultGeneratedConfig.Namespace,
 					Project:                defaultGeneratedConfig.Project,
 					PublicKey:              defaultGeneratedConfig.PublicKey,
@@ -740,30 +762,28 @@ wEW5QkylaPEkbVDhJWeR1I8=
 				Expect(configClient).ToNot(HaveReceived("Load"))
 				Expect(tfInputVarsFactory).To(HaveReceived("NewInputVars").With(defaultGeneratedConfig))
 				Expect(configClient).To(HaveReceived("Update").With(defaultGeneratedConfig))
-				Expect(terraformCLI).To(HaveReceived("Apply").With(terraformInputVars))
-				Expect(terraformCLI).To(HaveReceived("BuildOutput").With(terraformInputVars))
+				Expect(terraformCLI).To(HaveReceived("Apply").With(context.Background(), terraformInputVars))
+				Expect(terraformCLI).ToNot(HaveReceived("BuildOutput"))
 				Expect(configClient).To(HaveReceived("Update").With(configAfterLoad))
 
 				Expect(certGenerationActions[0]).To(Equal("generating cert ca: control-tower-initial-deployment, cn: [99.99.99.99 10.0.0.6]"))
 				Expect(certGenerationActions[1]).To(Equal("generating cert ca: control-tower-initial-deployment, cn: [77.77.77.77]"))
 
 				Expect(configClient).To(HaveReceived("HasAsset").With("director-state.json"))
-				Expect(configClient.HasAssetArgsForCall(0)).To(Equal("director-state.json"))
 				Expect(configClient).To(HaveReceived("HasAsset").With("director-creds.yml"))
-				Expect(configClient.HasAssetArgsForCall(1)).To(Equal("director-creds.yml"))
-				Expect(boshClient).To(HaveReceived("Deploy").With([]byte{}, []byte{}, false))
+				Expect(boshClient).To(HaveReceived("Deploy").With(context.Background(), []byte{}, []byte{}, false, Not(BeNil())))
 
 				Expect(configClient).To(HaveReceived("StoreAsset").With("director-state.json", directorStateFixture))
 				Expect(configClient).To(HaveReceived("StoreAsset").With("director-creds.yml", directorCredsFixture))
 				Expect(boshClient).To(HaveReceived("Cleanup"))
-				Expect(flyClient).To(HaveReceived("SetDefaultPipeline").With(configAfterCreateEnv, false))
+				Expect(flyClient).To(HaveReceived("SetDefaultPipeline").With(context.Background(), configAfterCreateEnv, false))
 				Expect(configClient).To(HaveReceived("Update").With(configAfterConcourseDeploy))
 			})
 		})
 
 		It("Prints a warning about changing the sourceIP", func() {
 			client := buildClient()
-			err := client.Deploy()
+			err := client.Deploy(context.Background())
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(stderr).To(gbytes.Say("WARNING: allowing access from local machine"))
@@ -781,7 +801,7 @@ wEW5QkylaPEkbVDhJWeR1I8=
 
 			It("Prints a warning about adding a DNS record", func() {
 				client := buildClient()
-				err := client.Deploy()
+				err := client.Deploy(context.Background())
 				Expect(err).ToNot(HaveOccurred())
 
 				Expect(stderr).To(gbytes.Say("WARNING: adding record ci.google.com to DNS zone google.com with name ABC123"))
@@ -789,7 +809,7 @@ wEW5QkylaPEkbVDhJWeR1I8=
 
 			It("Generates certificates for that domain and not the public IP", func() {
 				client := buildClient()
-				err := client.Deploy()
+				err := client.Deploy(context.Background())
 				Expect(err).ToNot(HaveOccurred())
 
 				Expect(certGenerationActions).To(ContainElement("generating cert ca: control-tower-happymeal, cn: [ci.google.com]"))
@@ -805,7 +825,7 @@ wEW5QkylaPEkbVDhJWeR1I8=
 
 				It("Prints the correct domain and not suggest using --insecure", func() {
 					client := buildClient()
-					err := client.Deploy()
+					err := client.Deploy(context.Background())
 					Expect(err).ToNot(HaveOccurred())
 					Eventually(stdout).Should(gbytes.Say("DEPLOY SUCCESSFUL"))
 					Eventually(stdout).Should(gbytes.Say("fly --target happymeal login --concourse-url https://ci.google.com --username admin --password s3cret"))
@@ -824,7 +844,7 @@ wEW5QkylaPEkbVDhJWeR1I8=
 			})
 			It("Returns a meaningful error message", func() {
 				client := buildClientOtherRegion()
-				err := client.Deploy()
+				err := client.Deploy(context.Background())
 				Expect(err).To(MatchError("found previous deployment in eu-west-1. Refusing to deploy to eu-central-1 as changing regions for existing deployments is not supported"))
 			})
 		})
@@ -841,7 +861,7 @@ wEW5QkylaPEkbVDhJWeR1I8=
 			})
 			It("Returns a meaningful error message", func() {
 				client := buildClient()
-				err := client.Deploy()
+				err := client.Deploy(context.Background())
 				Expect(err).To(MatchError("error getting initial config before deploy: [Existing deployment uses zone eu-west-1a and cannot change to zone eu-west-1c]"))
 			})
 		})
@@ -859,7 +879,7 @@ wEW5QkylaPEkbVDhJWeR1I8=
 			It("Does not override the existing DB size", func() {
 				provider, err := iaas.New(iaas.AWS, "eu-west-1")
 				Expect(err).ToNot(HaveOccurred())
-				awsInputVarsFactory, err := concourse.NewTFInputVarsFactory(provider)
+				awsInputVarsFactory, err := concourse.NewTFInputVarsFactory(provider, store.FromIAAS(provider))
 				Expect(err).ToNot(HaveOccurred())
 
 				var passedDBSize string
@@ -869,7 +889,7 @@ wEW5QkylaPEkbVDhJWeR1I8=
 				}
 
 				client := buildClient()
-				err = client.Deploy()
+				err = client.Deploy(context.Background())
 				Expect(err).ToNot(HaveOccurred())
 
 				Expect(passedDBSize).To(Equal(configInBucket.RDSInstanceClass))
@@ -878,16 +898,16 @@ wEW5QkylaPEkbVDhJWeR1I8=
 
 		Context("When running in self-update mode and the concourse is already deployed", func() {
 			It("Sets the default pipeline, before deploying the bosh director", func() {
-				flyClient.CanConnectStub = func() (bool, error) {
+				flyClient.CanConnectStub = func(ctx context.Context) (bool, error) {
 					return true, nil
 				}
 				args.SelfUpdate = true
 
 				client := buildClient()
-				err := client.Deploy()
+				err := client.Deploy(context.Background())
 				Expect(err).ToNot(HaveOccurred())
 
-				Expect(boshClient).To(HaveReceived("Deploy").With([]byte{}, []byte{}, true))
+				Expect(boshClient).To(HaveReceived("Deploy").With(context.Background(), []byte{}, []byte{}, true, Not(BeNil())))
 			})
 		})
 	})
@@ -14,9 +14,26 @@ import (
 )
 
 func (client *Client) getInitialConfig() (config.Config, bool, error) {
+	conf, isDomainUpdated, priorConfigExists, err := client.buildConfig()
+	if err != nil {
+		return config.Config{}, false, err
+	}
+
+	if !priorConfigExists {
+		err = client.configClient.Update(conf)
+		if err != nil {
+			return config.Config{}, false, fmt.Errorf("error persisting new config after setting values [%v]", err)
+		}
+	}
+
+	return conf, isDomainUpdated, nil
+}
+
+// buildConfig layers the deploy arguments on top of any stored config without persisting the result
+func (client *Client) buildConfig() (config.Config, bool, bool, error) {
 	priorConfigExists, err := client.configClient.ConfigExists()
 	if err != nil {
-		return config.Config{}, false, fmt.Errorf("error determining if config already exists [%v]", err)
+		return config.Config{}, false, false, fmt.Errorf("error determining if config already exists [%v]", err)
 	}
 
 	var isDomainUpdated bool
@@ -25,47 +42,42 @@ func (client *Client) getInitialConfig() (config.Config, bool, error) {
 	defaultConf := client.configClient.NewConfig()
 	defaultConf, err = populateConfigWithDefaults(defaultConf, client.provider, client.passwordGenerator, client.sshGenerator, client.eightRandomLetters)
 	if err != nil {
-		return config.Config{}, false, fmt.Errorf("error generating default config: [%v]", err)
+		return config.Config{}, false, false, fmt.Errorf("error generating default config: [%v]", err)
 	}
 
 	if priorConfigExists {
 		conf, err = client.configClient.Load()
 		if err != nil {
-			return config.Config{}, false, fmt.Errorf("error loading existing config [%v]", err)
+			return config.Config{}, false, false, fmt.Errorf("error loading existing config [%v]", err)
 		}
 		writeConfigLoadedSuccessMessage(client.stdout)
 
 		err = mergo.Merge(&conf, defaultConf)
 		if err != nil {
-			return config.Config{}, false, fmt.Errorf("error layering stored config on top default config [%v]", err)
+			return config.Config{}, false, false, fmt.Errorf("error layering stored config on top default config [%v]", err)
 		}
 
 		err = assertImmutableFieldsNotChanging(client.deployArgs, conf)
 		if err != nil {
-			return config.Config{}, false, err
+			return config.Config{}, false, false, err
 		}
 
 		conf, isDomainUpdated, err = applyArgumentsToConfig(conf, client.deployArgs, client.provider)
 		if err != nil {
-			return config.Config{}, false, fmt.Errorf("error merging new options with existing config: [%v]", err)
+			return config.Config{}, false, false, fmt.Errorf("error merging new options with existing config: [%v]", err)
 		}
 	} else {
 		conf, _, err = applyArgumentsToConfig(defaultConf, client.deployArgs, client.provider)
 		if err != nil {
-			return config.Config{}, false, fmt.Errorf("error applying arguments to default config: [%v]", err)
+			return config.Config{}, false, false, fmt.Errorf("error applying arguments to default config: [%v]", err)
 		}
 
 		conf = applyImmutableArgumentsToConfig(conf, client.deployArgs, client.provider)
 
-		err = client.configClient.Update(conf)
-		if err != nil {
-			return config.Config{}, false, fmt.Errorf("error persisting new config after setting values [%v]", err)
-		}
-
 		isDomainUpdated = true
 	}
 
-	return conf, isDomainUpdated, nil
+	return conf, isDomainUpdated, priorConfigExists, nil
 }
 
 func assertImmutableFieldsNotChanging(deployArgs *deploy.Args, conf config.ConfigView) error {
@@ -115,6 +127,11 @@ func populateConfigWithDefaults(conf config.Config, provider iaas.Provider, pass
 		conf.RDSDefaultDatabaseName = fmt.Sprintf("bosh_%s", eightRandomLetters())
 	case iaas.GCP:
 		conf.RDSDefaultDatabaseName = fmt.Sprintf("bosh-%s", eightRandomLetters())
+	case iaas.Azure:
+		conf.RDSDefaultDatabaseName = fmt.Sprintf("bosh-%s", eightRandomLetters())
+		// Azure Database for PostgreSQL only accepts passwords using three of
+		// upper case, lower case, digits and symbols
+		conf.RDSPassword = "A1" + conf.RDSPassword
 	}
 
 	return conf, nil
@@ -161,6 +178,11 @@ func applyArgumentsToConfig(conf config.Config, deployArgs *deploy.Args, provide
 	if deployArgs.WorkerTypeIsSet {
 		conf.WorkerType = deployArgs.WorkerType
 	}
+	if deployArgs.WorkerPoolsIsSet {
+		conf.WorkerPools = deployArgs.WorkerPools
+	}
+	conf.WorkerSchedule = applyWorkerSchedule(deployArgs, conf.WorkerSchedule, conf.ConcourseWorkerCount)
+	conf.Autoscale = applyAutoscale(deployArgs, conf.Autoscale)
 
 	if deployArgs.EnableGlobalResourcesIsSet {
 		conf.EnableGlobalResources = deployArgs.EnableGlobalResources
@@ -195,7 +217,7 @@ func hasCIDRFlagsSet(deployArgs *deploy.Args, provider iaas.Provider) bool {
 	switch provider.IAAS() {
 	case iaas.AWS:
 		return deployArgs.NetworkCIDRIsSet && deployArgs.PublicCIDRIsSet && deployArgs.PrivateCIDRIsSet
-	case iaas.GCP:
+	case iaas.GCP, iaas.Azure:
 		return deployArgs.PublicCIDRIsSet && deployArgs.PrivateCIDRIsSet
 	default:
 		return false
@@ -210,7 +232,7 @@ func populateConfigWithDeployArgsCIDRs(conf config.Config, deployArgs *deploy.Ar
 		conf.PrivateCIDR = deployArgs.PrivateCIDR
 		conf.RDS1CIDR = deployArgs.RDS1CIDR
 		conf.RDS2CIDR = deployArgs.RDS2CIDR
-	case iaas.GCP:
+	case iaas.GCP, iaas.Azure:
 		conf.PublicCIDR = deployArgs.PublicCIDR
 		conf.PrivateCIDR = deployArgs.PrivateCIDR
 	}
@@ -225,7 +247,7 @@ func populateConfigWithDefaultCIDRs(conf config.Config, provider iaas.Provider)
 		conf.PublicCIDR = "10.0.0.0/24"
 		conf.RDS1CIDR = "10.0.4.0/24"
 		conf.RDS2CIDR = "10.0.5.0/24"
-	case iaas.GCP:
+	case iaas.GCP, iaas.Azure:
 		conf.PrivateCIDR = "10.0.1.0/24"
 		conf.PublicCIDR = "10.0.0.0/24"
 	}
@@ -278,3 +300,73 @@ func (b cidrBlocks) String() (string, error) {
 	}
 	return buf.String(), nil
 }
+
+// applyWorkerSchedule creates, updates or removes the worker schedule. The workers are scaled
+// back up to the worker count at the time the schedule was created, or to --workers if given
+func applyWorkerSchedule(deployArgs *deploy.Args, existing *config.WorkerSchedule, workerCount int) *config.WorkerSchedule {
+	if deployArgs.RemovesWorkerSchedule() {
+		return nil
+	}
+
+	var schedule config.WorkerSchedule
+	switch {
+	case existing != nil:
+		schedule = *existing
+	case deployArgs.WorkersOffSchedule != "":
+		schedule = config.WorkerSchedule{
+			Timezone:   deployArgs.ScheduleTimezone,
+			OffWorkers: deployArgs.WorkersOff,
+			OnWorkers:  workerCount,
+		}
+	default:
+		return nil
+	}
+
+	if deployArgs.WorkersOffScheduleIsSet {
+		schedule.OffCron = deployArgs.WorkersOffSchedule
+		schedule.OnCron = deployArgs.WorkersOnSchedule
+	}
+	if deployArgs.ScheduleTimezoneIsSet {
+		schedule.Timezone = deployArgs.ScheduleTimezone
+	}
+	if deployArgs.WorkersOffIsSet {
+		schedule.OffWorkers = deployArgs.WorkersOff
+	}
+	if deployArgs.ScheduleTagIsSet {
+		schedule.Tag = deployArgs.ScheduleTag
+	}
+	if deployArgs.WorkerCountIsSet {
+		schedule.OnWorkers = deployArgs.WorkerCount
+	}
+
+	return &schedule
+}
+
+// applyAutoscale creates, updates or removes the autoscale job's settings
+func applyAutoscale(deployArgs *deploy.Args, existing *config.Autoscale) *config.Autoscale {
+	if deployArgs.RemovesAutoscale() {
+		return nil
+	}
+
+	var autoscale config.Autoscale
+	switch {
+	case existing != nil:
+		autoscale = *existing
+	case deployArgs.AutoscaleMaxWorkers > 0:
+		autoscale = config.Autoscale{MinWorkers: deployArgs.AutoscaleMinWorkers}
+	default:
+		return nil
+	}
+
+	if deployArgs.AutoscaleMaxWorkersIsSet {
+		autoscale.MaxWorkers = deployArgs.AutoscaleMaxWorkers
+	}
+	if deployArgs.AutoscaleMinWorkersIsSet {
+		autoscale.MinWorkers = deployArgs.AutoscaleMinWorkers
+	}
+	if deployArgs.AutoscaleTagIsSet {
+		autoscale.Tag = deployArgs.AutoscaleTag
+	}
+
+	return &autoscale
+}
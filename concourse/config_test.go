@@ -0,0 +1,131 @@
+package concourse
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/EngineerBetter/control-tower/commands/deploy"
+	"github.com/EngineerBetter/control-tower/config"
+	"github.com/EngineerBetter/control-tower/iaas"
+	"github.com/EngineerBetter/control-tower/iaas/iaasfakes"
+)
+
+func TestApplyWorkerSchedule(t *testing.T) {
+	existing := &config.WorkerSchedule{OffCron: "0 19 * * *", OnCron: "0 7 * * *", Timezone: "UTC", OffWorkers: 1, OnWorkers: 4}
+
+	tests := []struct {
+		name     string
+		args     deploy.Args
+		existing *config.WorkerSchedule
+		want     *config.WorkerSchedule
+	}{
+		{
+			name: "no schedule",
+			args: deploy.Args{ScheduleTimezone: "UTC", WorkersOff: 1},
+			want: nil,
+		},
+		{
+			name: "new schedule scales back up to the current worker count",
+			args: deploy.Args{
+				WorkersOffSchedule: "0 20 * * 1-5", WorkersOffScheduleIsSet: true,
+				WorkersOnSchedule: "0 8 * * 1-5", WorkersOnScheduleIsSet: true,
+				ScheduleTimezone: "Europe/London", WorkersOff: 2,
+			},
+			want: &config.WorkerSchedule{OffCron: "0 20 * * 1-5", OnCron: "0 8 * * 1-5", Timezone: "Europe/London", OffWorkers: 2, OnWorkers: 3},
+		},
+		{
+			name:     "existing schedule is kept",
+			args:     deploy.Args{ScheduleTimezone: "UTC", WorkersOff: 1},
+			existing: existing,
+			want:     existing,
+		},
+		{
+			name:     "existing schedule is updated",
+			args:     deploy.Args{ScheduleTimezone: "Europe/Paris", ScheduleTimezoneIsSet: true, WorkerCount: 6, WorkerCountIsSet: true, ScheduleTag: "scheduler", ScheduleTagIsSet: true},
+			existing: existing,
+			want:     &config.WorkerSchedule{OffCron: "0 19 * * *", OnCron: "0 7 * * *", Timezone: "Europe/Paris", OffWorkers: 1, OnWorkers: 6, Tag: "scheduler"},
+		},
+		{
+			name:     "empty schedules remove the schedule",
+			args:     deploy.Args{WorkersOffScheduleIsSet: true, WorkersOnScheduleIsSet: true},
+			existing: existing,
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := applyWorkerSchedule(&tt.args, tt.existing, 3); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("applyWorkerSchedule() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyAutoscale(t *testing.T) {
+	existing := &config.Autoscale{MinWorkers: 1, MaxWorkers: 5, Tag: "autoscaler"}
+
+	tests := []struct {
+		name     string
+		args     deploy.Args
+		existing *config.Autoscale
+		want     *config.Autoscale
+	}{
+		{
+			name: "no autoscaling",
+			args: deploy.Args{AutoscaleMinWorkers: 1},
+			want: nil,
+		},
+		{
+			name: "new autoscaling uses the default min workers",
+			args: deploy.Args{AutoscaleMaxWorkers: 4, AutoscaleMaxWorkersIsSet: true, AutoscaleMinWorkers: 1},
+			want: &config.Autoscale{MinWorkers: 1, MaxWorkers: 4},
+		},
+		{
+			name:     "existing autoscaling is kept",
+			args:     deploy.Args{AutoscaleMinWorkers: 1},
+			existing: existing,
+			want:     existing,
+		},
+		{
+			name:     "existing autoscaling is updated",
+			args:     deploy.Args{AutoscaleMinWorkers: 2, AutoscaleMinWorkersIsSet: true},
+			existing: existing,
+			want:     &config.Autoscale{MinWorkers: 2, MaxWorkers: 5, Tag: "autoscaler"},
+		},
+		{
+			name:     "zero max workers removes autoscaling",
+			args:     deploy.Args{AutoscaleMaxWorkersIsSet: true},
+			existing: existing,
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := applyAutoscale(&tt.args, tt.existing); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("applyAutoscale() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPopulateConfigWithDefaults_Azure(t *testing.T) {
+	provider := &iaasfakes.FakeProvider{}
+	provider.IAASReturns(iaas.Azure)
+
+	conf, err := populateConfigWithDefaults(config.Config{}, provider, func(n int) string { return "password" }, func() ([]byte, []byte, string, error) {
+		return []byte("private"), []byte("public"), "", nil
+	}, func() string { return "abcdefgh" })
+	if err != nil {
+		t.Fatalf("populateConfigWithDefaults() error = %v", err)
+	}
+
+	if conf.RDSDefaultDatabaseName != "bosh-abcdefgh" {
+		t.Errorf("RDSDefaultDatabaseName = %s, want bosh-abcdefgh", conf.RDSDefaultDatabaseName)
+	}
+	if conf.RDSPassword != "A1password" {
+		t.Errorf("RDSPassword = %s, want A1password", conf.RDSPassword)
+	}
+	if conf.PublicCIDR != "10.0.0.0/24" || conf.PrivateCIDR != "10.0.1.0/24" || conf.NetworkCIDR != "" {
+		t.Errorf("CIDRs = %s, %s, %s", conf.PublicCIDR, conf.PrivateCIDR, conf.NetworkCIDR)
+	}
+}
@@ -1,6 +1,7 @@
 package concourse
 
 import (
+	"context"
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
@@ -16,6 +17,7 @@ import (
 	"github.com/EngineerBetter/control-tower/bosh"
 	"github.com/EngineerBetter/control-tower/certs"
 	"github.com/EngineerBetter/control-tower/config"
+	"github.com/EngineerBetter/control-tower/events"
 	"github.com/EngineerBetter/control-tower/fly"
 	"github.com/EngineerBetter/control-tower/terraform"
 	"github.com/xenolf/lego/lego"
@@ -48,12 +50,21 @@ func stripVersion(tags []string) []string {
 }
 
 // Deploy deploys a concourse instance
-func (client *Client) Deploy() error {
+func (client *Client) Deploy(ctx context.Context) error {
 	err := client.configClient.EnsureBucketExists()
 	if err != nil {
 		return fmt.Errorf("error ensuring config bucket exists before deploy: [%v]", err)
 	}
 
+	command := "deploy"
+	if client.deployArgs.SelfUpdate {
+		command = "deploy --self-update"
+	}
+
+	return client.withLock(ctx, command, client.deploy)
+}
+
+func (client *Client) deploy(ctx context.Context) (err error) {
 	conf, isDomainUpdated, err := client.getInitialConfig()
 	if err != nil {
 		return fmt.Errorf("error getting initial config before deploy: [%v]", err)
@@ -69,18 +80,42 @@ func (client *Client) Deploy() error {
 	conf.HostedZoneRecordPrefix = r.HostedZoneRecordPrefix
 	conf.Domain = r.Domain
 
-	tfInputVars := client.tfInputVarsFactory.NewInputVars(conf)
-
-	err = client.tfCLI.Apply(tfInputVars)
+	checkpoint, err := client.loadDeployCheckpoint()
 	if err != nil {
 		return err
 	}
+	// A failed deploy still writes the config and creds after its last phase, so record them as it left them.
+	// Otherwise the next attempt would see them as changed by something else and start from the first phase.
+	defer func() {
+		if err == nil {
+			return
+		}
+		if storeErr := checkpoint.store(); storeErr != nil {
+			events.Warning(fmt.Sprintf("failed to record the progress of the deploy: %v", storeErr))
+			fmt.Fprintf(client.stderr, "WARNING: failed to record the progress of the deploy: %v\n", storeErr)
+		}
+	}()
 
-	tfOutputs, err := client.tfCLI.BuildOutput(tfInputVars)
+	tfInputVars := client.tfInputVarsFactory.NewInputVars(conf)
+
+	var tfOutputs terraform.Outputs
+	err = checkpoint.RunPhase(terraformPhase, func() error {
+		var err1 error
+		tfOutputs, err1 = client.tfCLI.Apply(ctx, tfInputVars)
+		return err1
+	})
 	if err != nil {
 		return err
 	}
 
+	// Apply returns the outputs, which are only missing when the phase was skipped
+	if tfOutputs == nil {
+		tfOutputs, err = client.tfCLI.BuildOutput(ctx, tfInputVars)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = client.configClient.Update(conf)
 	if err != nil {
 		return err
@@ -90,25 +125,34 @@ func (client *Client) Deploy() error {
 
 	conf.Version = client.version
 
-	cr, err := client.checkPreDeployConfigRequirements(client.acmeClientConstructor, isDomainUpdated, conf, tfOutputs)
+	// When skipped, the certificates generated by the previous deploy are already in conf
+	err = checkpoint.RunPhase(certsPhase, func() error {
+		cr, err1 := client.checkPreDeployConfigRequirements(ctx, client.acmeClientConstructor, isDomainUpdated, conf, tfOutputs)
+		if err1 != nil {
+			return err1
+		}
+
+		conf.Domain = cr.Domain
+		conf.DirectorPublicIP = cr.DirectorPublicIP
+		conf.DirectorCACert = cr.DirectorCerts.DirectorCACert
+		conf.DirectorCert = cr.DirectorCerts.DirectorCert
+		conf.DirectorKey = cr.DirectorCerts.DirectorKey
+		conf.ConcourseCert = cr.Certs.ConcourseCert
+		conf.ConcourseKey = cr.Certs.ConcourseKey
+		conf.ConcourseCACert = cr.Certs.ConcourseCACert
+
+		// Store the certificates before recording the phase as complete, so that a deploy resuming after it uses them
+		return client.configClient.Update(conf)
+	})
 	if err != nil {
 		return err
 	}
 
-	conf.Domain = cr.Domain
-	conf.DirectorPublicIP = cr.DirectorPublicIP
-	conf.DirectorCACert = cr.DirectorCerts.DirectorCACert
-	conf.DirectorCert = cr.DirectorCerts.DirectorCert
-	conf.DirectorKey = cr.DirectorCerts.DirectorKey
-	conf.ConcourseCert = cr.Certs.ConcourseCert
-	conf.ConcourseKey = cr.Certs.ConcourseKey
-	conf.ConcourseCACert = cr.Certs.ConcourseCACert
-
 	var bp BoshParams
 	if client.deployArgs.SelfUpdate {
-		bp, err = client.updateBoshAndPipeline(conf, tfOutputs)
+		bp, err = client.updateBoshAndPipeline(ctx, conf, tfOutputs, checkpoint)
 	} else {
-		bp, err = client.deployBoshAndPipeline(conf, tfOutputs)
+		bp, err = client.deployBoshAndPipeline(ctx, conf, tfOutputs, checkpoint)
 	}
 
 	conf.CredhubPassword = bp.CredhubPassword
@@ -127,10 +171,13 @@ func (client *Client) Deploy() error {
 	if err == nil {
 		err = err1
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	return checkpoint.clear()
 }
 
-func (client *Client) deployBoshAndPipeline(c config.ConfigView, tfOutputs terraform.Outputs) (BoshParams, error) {
+func (client *Client) deployBoshAndPipeline(ctx context.Context, c config.ConfigView, tfOutputs terraform.Outputs, checkpoint *deployCheckpoint) (BoshParams, error) {
 	// When we are deploying for the first time rather than updating
 	// ensure that the pipeline is set _after_ the concourse is deployed
 
@@ -149,7 +196,7 @@ func (client *Client) deployBoshAndPipeline(c config.ConfigView, tfOutputs terra
 		DirectorCACert:           c.GetDirectorCACert(),
 	}
 
-	bp, err := client.deployBosh(c, tfOutputs, false)
+	bp, err := client.deployBosh(ctx, c, tfOutputs, false, checkpoint)
 	if err != nil {
 		return bp, err
 	}
@@ -169,7 +216,10 @@ func (client *Client) deployBoshAndPipeline(c config.ConfigView, tfOutputs terra
 	}
 	defer flyClient.Cleanup()
 
-	if err := flyClient.SetDefaultPipeline(c, false); err != nil {
+	err = checkpoint.RunPhase(pipelinePhase, func() error {
+		return flyClient.SetDefaultPipeline(ctx, c, false)
+	})
+	if err != nil {
 		return bp, err
 	}
 
@@ -187,7 +237,7 @@ func (client *Client) deployBoshAndPipeline(c config.ConfigView, tfOutputs terra
 	return bp, writeDeploySuccessMessage(params, client.stdout)
 }
 
-func (client *Client) updateBoshAndPipeline(c config.ConfigView, tfOutputs terraform.Outputs) (BoshParams, error) {
+func (client *Client) updateBoshAndPipeline(ctx context.Context, c config.ConfigView, tfOutputs terraform.Outputs, checkpoint *deployCheckpoint) (BoshParams, error) {
 	// If concourse is already running this is an update rather than a fresh deploy
 	// When updating we need to deploy the BOSH as the final step in order to
 	// Detach from the update, so the update job can exit
@@ -221,7 +271,7 @@ func (client *Client) updateBoshAndPipeline(c config.ConfigView, tfOutputs terra
 	}
 	defer flyClient.Cleanup()
 
-	concourseAlreadyRunning, err := flyClient.CanConnect()
+	concourseAlreadyRunning, err := flyClient.CanConnect(ctx)
 	if err != nil {
 		return bp, err
 	}
@@ -231,11 +281,14 @@ func (client *Client) updateBoshAndPipeline(c config.ConfigView, tfOutputs terra
 	}
 
 	// Allow a fly version discrepancy since we might be targetting an older Concourse
-	if err = flyClient.SetDefaultPipeline(c, true); err != nil {
+	err = checkpoint.RunPhase(pipelinePhase, func() error {
+		return flyClient.SetDefaultPipeline(ctx, c, true)
+	})
+	if err != nil {
 		return bp, err
 	}
 
-	bp, err = client.deployBosh(c, tfOutputs, true)
+	bp, err = client.deployBosh(ctx, c, tfOutputs, true, checkpoint)
 	if err != nil {
 		return bp, err
 	}
@@ -314,7 +367,7 @@ type Requirements struct {
 	Certs            Certs
 }
 
-func (client *Client) checkPreDeployConfigRequirements(c func(u *certs.User) (*lego.Client, error), isDomainUpdated bool, cfg config.ConfigView, tfOutputs terraform.Outputs) (Requirements, error) {
+func (client *Client) checkPreDeployConfigRequirements(ctx context.Context, c func(u *certs.User) (*lego.Client, error), isDomainUpdated bool, cfg config.ConfigView, tfOutputs terraform.Outputs) (Requirements, error) {
 	cr := Requirements{
 		Domain:           cfg.GetDomain(),
 		DirectorPublicIP: cfg.GetDirectorPublicIP(),
@@ -334,7 +387,7 @@ func (client *Client) checkPreDeployConfigRequirements(c func(u *certs.User) (*l
 		DirectorKey:    cfg.GetDirectorKey(),
 	}
 
-	dc, err := client.ensureDirectorCerts(c, dc, cfg.GetDeployment(), tfOutputs, cfg.GetPublicCIDR())
+	dc, err := client.ensureDirectorCerts(ctx, c, dc, cfg.GetDeployment(), tfOutputs, cfg.GetPublicCIDR())
 	if err != nil {
 		return cr, err
 	}
@@ -347,7 +400,7 @@ func (client *Client) checkPreDeployConfigRequirements(c func(u *certs.User) (*l
 		ConcourseCACert: cfg.GetConcourseCACert(),
 	}
 
-	cc, err = client.ensureConcourseCerts(c, isDomainUpdated, cc, cfg.GetDeployment(), cr.Domain)
+	cc, err = client.ensureConcourseCerts(ctx, c, isDomainUpdated, cc, cfg.GetDeployment(), cr.Domain)
 	if err != nil {
 		return cr, err
 	}
@@ -362,7 +415,7 @@ func (client *Client) checkPreDeployConfigRequirements(c func(u *certs.User) (*l
 	return cr, nil
 }
 
-func (client *Client) ensureDirectorCerts(c func(u *certs.User) (*lego.Client, error), dc DirectorCerts, deployment string, tfOutputs terraform.Outputs, publicCIDR string) (DirectorCerts, error) {
+func (client *Client) ensureDirectorCerts(ctx context.Context, c func(u *certs.User) (*lego.Client, error), dc DirectorCerts, deployment string, tfOutputs terraform.Outputs, publicCIDR string) (DirectorCerts, error) {
 	// If we already have director certificates, don't regenerate as changing them will
 	// force a bosh director re-deploy even if there are no other changes
 	certs := dc
@@ -390,7 +443,7 @@ func (client *Client) ensureDirectorCerts(c func(u *certs.User) (*lego.Client, e
 		return certs, err
 	}
 
-	directorCerts, err := client.certGenerator(c, deployment, client.provider, ip, directorInternalIP.String())
+	directorCerts, err := client.certGenerator(ctx, c, deployment, client.provider, ip, directorInternalIP.String())
 	if err != nil {
 		return certs, err
 	}
@@ -414,7 +467,7 @@ func timeTillExpiry(cert string) time.Duration {
 	return time.Until(c.NotAfter)
 }
 
-func (client *Client) ensureConcourseCerts(c func(u *certs.User) (*lego.Client, error), domainUpdated bool, cc Certs, deployment, domain string) (Certs, error) {
+func (client *Client) ensureConcourseCerts(ctx context.Context, c func(u *certs.User) (*lego.Client, error), domainUpdated bool, cc Certs, deployment, domain string) (Certs, error) {
 	certs := cc
 
 	if client.deployArgs.TLSCert != "" {
@@ -430,7 +483,7 @@ func (client *Client) ensureConcourseCerts(c func(u *certs.User) (*lego.Client,
 	}
 
 	// If no domain has been provided by the user, the value of cfg.Domain is set to the ATC's public IP in checkPreDeployConfigRequirements
-	Certs, err := client.certGenerator(c, deployment, client.provider, domain)
+	Certs, err := client.certGenerator(ctx, c, deployment, client.provider, domain)
 	if err != nil {
 		return certs, err
 	}
@@ -442,7 +495,7 @@ func (client *Client) ensureConcourseCerts(c func(u *certs.User) (*lego.Client,
 	return certs, nil
 }
 
-func (client *Client) deployBosh(config config.ConfigView, tfOutputs terraform.Outputs, detach bool) (BoshParams, error) {
+func (client *Client) deployBosh(ctx context.Context, config config.ConfigView, tfOutputs terraform.Outputs, detach bool, checkpoint bosh.Checkpoint) (BoshParams, error) {
 	bp := BoshParams{
 		CredhubPassword:          config.GetCredhubPassword(),
 		CredhubAdminClientSecret: config.GetCredhubAdminClientSecret(),
@@ -472,7 +525,7 @@ func (client *Client) deployBosh(config config.ConfigView, tfOutputs terraform.O
 		return bp, err
 	}
 
-	boshStateBytes, boshCredsBytes, err = boshClient.Deploy(boshStateBytes, boshCredsBytes, detach)
+	boshStateBytes, boshCredsBytes, err = boshClient.Deploy(ctx, boshStateBytes, boshCredsBytes, detach, checkpoint)
 	err1 := client.configClient.StoreAsset(bosh.StateFilename, boshStateBytes)
 	if err == nil {
 		err = err1
@@ -522,6 +575,7 @@ func (client *Client) setUserIP(c config.ConfigView) (string, error) {
 
 	if sourceAccessIP != userIP {
 		sourceAccessIP = userIP
+		events.Warning(fmt.Sprintf("allowing access from local machine (address: %s)", userIP))
 		_, err = client.stderr.Write([]byte(fmt.Sprintf(
 			"\nWARNING: allowing access from local machine (address: %s)\n\n", userIP)))
 		if err != nil {
@@ -565,6 +619,7 @@ func (client *Client) setHostedZone(c config.ConfigView, domain string) (HostedZ
 	}
 	zone.Domain = domain
 
+	events.Warning(fmt.Sprintf("adding record %s to DNS zone %s with name %s", domain, hostedZoneName, hostedZoneID))
 	_, err = client.stderr.Write([]byte(fmt.Sprintf(
 		"\nWARNING: adding record %s to DNS zone %s with name %s\n\n", domain, hostedZoneName, hostedZoneID)))
 	if err != nil {
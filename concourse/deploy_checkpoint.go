@@ -0,0 +1,205 @@
+package concourse
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/EngineerBetter/control-tower/bosh"
+	"github.com/EngineerBetter/control-tower/commands/deploy"
+	"github.com/EngineerBetter/control-tower/events"
+)
+
+const deployCheckpointFilename = "deploy-checkpoint.json"
+
+// deployFiles are the files in the config bucket which a deploy resumes from. When something other than
+// a deploy changes them, such as a credential rotation, the phases already completed are out of date.
+var deployFiles = []string{"config.json", bosh.CredsFilename}
+
+// Phases of a deploy run outside of BOSH, see deploy.Phases for the order of every phase
+const (
+	terraformPhase = "terraform"
+	certsPhase     = "certs"
+	pipelinePhase  = "pipeline"
+)
+
+// deployCheckpoint records the phases of a deploy that have completed, so that a deploy which failed
+// part way through resumes from the phase that failed. It is cleared when a deploy succeeds.
+type deployCheckpoint struct {
+	// Inputs is a digest of the version and arguments of the deploy that completed the phases
+	Inputs string `json:"inputs"`
+	// Files is a digest of deployFiles as the deploy that completed the phases left them
+	Files     string   `json:"files"`
+	Completed []string `json:"completed"`
+
+	client *Client
+	// fromPhase is the first phase to run when the deploy was restarted with --from-phase
+	fromPhase string
+	// resumed is set once a phase has run, after which none are skipped
+	resumed bool
+}
+
+// loadDeployCheckpoint retrieves the checkpoint from the config bucket, discarding any progress it records
+// if the deploy's inputs, or the config and creds it left, have changed since
+func (client *Client) loadDeployCheckpoint() (*deployCheckpoint, error) {
+	inputs, err := client.deployInputs()
+	if err != nil {
+		return nil, err
+	}
+	var checkpoint deployCheckpoint
+	fileExists, err := client.configClient.HasAsset(deployCheckpointFilename)
+	if err != nil {
+		return nil, err
+	}
+	if fileExists {
+		fileContents, err := client.configClient.LoadAsset(deployCheckpointFilename)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(fileContents, &checkpoint); err != nil {
+			return nil, fmt.Errorf("error reading [%v]: [%v]", deployCheckpointFilename, err)
+		}
+	}
+
+	files, err := client.deployFilesDigest()
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint.Inputs != inputs || checkpoint.Files != files {
+		checkpoint.Completed = nil
+	}
+	checkpoint.Inputs = inputs
+	checkpoint.client = client
+	if client.deployArgs.FromPhaseIsSet {
+		checkpoint.fromPhase = client.deployArgs.FromPhase
+	}
+	return &checkpoint, nil
+}
+
+// begin returns true if phase completed during a previous attempt at this deploy, or comes before the
+// phase given to --from-phase. Otherwise phase is about to run, so it and the phases after it are no longer complete
+func (checkpoint *deployCheckpoint) begin(phase string) (bool, error) {
+	skip := false
+	if checkpoint.fromPhase != "" {
+		skip = phaseIndex(phase) < phaseIndex(checkpoint.fromPhase)
+	} else if !checkpoint.resumed {
+		skip = checkpoint.isCompleted(phase)
+	}
+
+	if !skip {
+		checkpoint.resumed = true
+		completed := []string{}
+		for _, c := range checkpoint.Completed {
+			if phaseIndex(c) < phaseIndex(phase) {
+				completed = append(completed, c)
+			}
+		}
+		checkpoint.Completed = completed
+		return false, checkpoint.store()
+	}
+
+	if !checkpoint.isCompleted(phase) {
+		checkpoint.Completed = append(checkpoint.Completed, phase)
+	}
+	reason := "completed during a previous deploy"
+	if checkpoint.fromPhase != "" {
+		reason = fmt.Sprintf("deploying from the %s phase", checkpoint.fromPhase)
+	}
+	events.PhaseSkipped(phase, reason)
+	_, err := fmt.Fprintf(checkpoint.client.stdout, "Skipping %s phase, %s\n", phase, reason)
+	return true, err
+}
+
+// complete records that phase has run successfully
+func (checkpoint *deployCheckpoint) complete(phase string) error {
+	if !checkpoint.isCompleted(phase) {
+		checkpoint.Completed = append(checkpoint.Completed, phase)
+	}
+	return checkpoint.store()
+}
+
+// clear forgets every completed phase, once the deploy has succeeded
+func (checkpoint *deployCheckpoint) clear() error {
+	checkpoint.Completed = nil
+	return checkpoint.store()
+}
+
+// store records the checkpoint in the config bucket, along with a digest of the files the deploy has written
+// so far. A deploy which fails stores it again once it has stopped writing them.
+func (checkpoint *deployCheckpoint) store() error {
+	files, err := checkpoint.client.deployFilesDigest()
+	if err != nil {
+		return err
+	}
+	checkpoint.Files = files
+
+	checkpointBytes, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	return checkpoint.client.configClient.StoreAsset(deployCheckpointFilename, checkpointBytes)
+}
+
+func (checkpoint *deployCheckpoint) isCompleted(phase string) bool {
+	for _, completed := range checkpoint.Completed {
+		if completed == phase {
+			return true
+		}
+	}
+	return false
+}
+
+// RunPhase runs phase unless the checkpoint says it can be skipped, recording it as complete if it succeeds
+func (checkpoint *deployCheckpoint) RunPhase(phase string, run func() error) error {
+	skip, err := checkpoint.begin(phase)
+	if err != nil || skip {
+		return err
+	}
+	if err = events.Phase(phase, run); err != nil {
+		return err
+	}
+	return checkpoint.complete(phase)
+}
+
+// deployInputs returns a digest of everything the user chose for this deploy, so that a deploy with
+// different settings or by a different version of control-tower starts again from the first phase
+func (client *Client) deployInputs() (string, error) {
+	args := *client.deployArgs
+	args.FromPhase, args.FromPhaseIsSet = "", false
+	argsBytes, err := json.Marshal(args)
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256(append([]byte(client.version+"\n"), argsBytes...))
+	return hex.EncodeToString(digest[:]), nil
+}
+
+// deployFilesDigest returns a digest of the contents of deployFiles in the config bucket
+func (client *Client) deployFilesDigest() (string, error) {
+	hash := sha256.New()
+	for _, filename := range deployFiles {
+		exists, err := client.configClient.HasAsset(filename)
+		if err != nil {
+			return "", err
+		}
+		contents := []byte{}
+		if exists {
+			if contents, err = client.configClient.LoadAsset(filename); err != nil {
+				return "", fmt.Errorf("error loading [%v]: [%v]", filename, err)
+			}
+		}
+		fmt.Fprintf(hash, "%s %d\n", filename, len(contents))
+		hash.Write(contents)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func phaseIndex(phase string) int {
+	for i, p := range deploy.Phases {
+		if p == phase {
+			return i
+		}
+	}
+	return len(deploy.Phases)
+}
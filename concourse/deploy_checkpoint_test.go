@@ -0,0 +1,141 @@
+package concourse
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/EngineerBetter/control-tower/bosh"
+	"github.com/EngineerBetter/control-tower/commands/deploy"
+)
+
+func newCheckpointClient(assets map[string][]byte, args deploy.Args) *Client {
+	client, _ := newMaintenanceClient(assets)
+	client.deployArgs = &args
+	client.version = "1.2.3"
+	return client
+}
+
+// runDeployPhases runs every phase in order until failAt, returning the phases that ran
+func runDeployPhases(t *testing.T, client *Client, failAt string) []string {
+	checkpoint, err := client.loadDeployCheckpoint()
+	if err != nil {
+		t.Fatalf("loadDeployCheckpoint() error = %v", err)
+	}
+	ran := []string{}
+	for _, phase := range deploy.Phases {
+		err = checkpoint.RunPhase(phase, func() error {
+			ran = append(ran, phase)
+			if phase == failAt {
+				return errors.New("failed")
+			}
+			return nil
+		})
+		if err != nil {
+			return ran
+		}
+	}
+	if err = checkpoint.clear(); err != nil {
+		t.Fatalf("clear() error = %v", err)
+	}
+	return ran
+}
+
+func TestDeployCheckpointResumesFromFailedPhase(t *testing.T) {
+	assets := map[string][]byte{}
+	args := deploy.Args{WorkerCount: 1}
+
+	ran := runDeployPhases(t, newCheckpointClient(assets, args), bosh.StemcellPhase)
+	if want := []string{"terraform", "certs", "create-env", "cloud-config", "stemcell"}; !reflect.DeepEqual(ran, want) {
+		t.Errorf("first deploy ran %v, want %v", ran, want)
+	}
+
+	client := newCheckpointClient(assets, args)
+	ran = runDeployPhases(t, client, "")
+	if want := []string{"stemcell", "databases", "concourse", "pipeline"}; !reflect.DeepEqual(ran, want) {
+		t.Errorf("resumed deploy ran %v, want %v", ran, want)
+	}
+	if !strings.Contains(client.stdout.(interface{ String() string }).String(), "Skipping terraform phase, completed during a previous deploy") {
+		t.Errorf("resumed deploy did not report the skipped phases")
+	}
+
+	// A successful deploy clears the checkpoint, so the next one runs every phase
+	ran = runDeployPhases(t, newCheckpointClient(assets, args), "")
+	if !reflect.DeepEqual(ran, deploy.Phases) {
+		t.Errorf("deploy after a successful deploy ran %v, want %v", ran, deploy.Phases)
+	}
+}
+
+func TestDeployCheckpointRestartsWhenInputsChange(t *testing.T) {
+	assets := map[string][]byte{}
+
+	runDeployPhases(t, newCheckpointClient(assets, deploy.Args{WorkerCount: 1}), bosh.ConcoursePhase)
+
+	ran := runDeployPhases(t, newCheckpointClient(assets, deploy.Args{WorkerCount: 2}), "")
+	if !reflect.DeepEqual(ran, deploy.Phases) {
+		t.Errorf("deploy with different arguments ran %v, want %v", ran, deploy.Phases)
+	}
+}
+
+func TestDeployCheckpointRestartsWhenConfigChanges(t *testing.T) {
+	for _, filename := range []string{"config.json", bosh.CredsFilename} {
+		t.Run(filename, func(t *testing.T) {
+			assets := map[string][]byte{"config.json": []byte(`{}`), bosh.CredsFilename: []byte("password: old")}
+			args := deploy.Args{WorkerCount: 1}
+
+			runDeployPhases(t, newCheckpointClient(assets, args), bosh.ConcoursePhase)
+			// such as a credential rotation between the two attempts
+			assets[filename] = []byte("rotated")
+
+			ran := runDeployPhases(t, newCheckpointClient(assets, args), "")
+			if !reflect.DeepEqual(ran, deploy.Phases) {
+				t.Errorf("deploy after %s changed ran %v, want %v", filename, ran, deploy.Phases)
+			}
+		})
+	}
+}
+
+func TestDeployCheckpointResumesAfterItsOwnWrites(t *testing.T) {
+	assets := map[string][]byte{"config.json": []byte(`{}`)}
+	args := deploy.Args{WorkerCount: 1}
+
+	client := newCheckpointClient(assets, args)
+	checkpoint, err := client.loadDeployCheckpoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = checkpoint.RunPhase(terraformPhase, func() error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	// A failed deploy stores the config, then records the checkpoint again
+	assets["config.json"] = []byte(`{"version":"1.2.3"}`)
+	if err = checkpoint.store(); err != nil {
+		t.Fatal(err)
+	}
+
+	ran := runDeployPhases(t, newCheckpointClient(assets, args), "")
+	if ran[0] != certsPhase {
+		t.Errorf("resumed deploy ran %v, want it to start from %s", ran, certsPhase)
+	}
+}
+
+func TestDeployCheckpointFromPhase(t *testing.T) {
+	assets := map[string][]byte{}
+	args := deploy.Args{WorkerCount: 1}
+
+	runDeployPhases(t, newCheckpointClient(assets, args), bosh.ConcoursePhase)
+
+	args.FromPhase, args.FromPhaseIsSet = certsPhase, true
+	ran := runDeployPhases(t, newCheckpointClient(assets, args), bosh.DatabasesPhase)
+	if want := []string{"certs", "create-env", "cloud-config", "stemcell", "databases"}; !reflect.DeepEqual(ran, want) {
+		t.Errorf("deploy --from-phase certs ran %v, want %v", ran, want)
+	}
+
+	// --from-phase does not change the inputs, so a plain rerun resumes where it failed
+	args.FromPhase, args.FromPhaseIsSet = "", false
+	ran = runDeployPhases(t, newCheckpointClient(assets, args), "")
+	if want := []string{"databases", "concourse", "pipeline"}; !reflect.DeepEqual(ran, want) {
+		t.Errorf("resumed deploy ran %v, want %v", ran, want)
+	}
+}
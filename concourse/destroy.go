@@ -1,6 +1,7 @@
 package concourse
 
 import (
+	"context"
 	"fmt"
 	"io"
 
@@ -8,7 +9,13 @@ import (
 )
 
 // Destroy destroys a concourse instance
-func (client *Client) Destroy() error {
+func (client *Client) Destroy(ctx context.Context) error {
+	ctx, release, err := client.acquireLock(ctx, "destroy")
+	if err != nil {
+		return err
+	}
+	// Releasing fails harmlessly once the bucket holding the lock has been deleted
+	defer release()
 
 	conf, err := client.configClient.Load()
 	if err != nil {
@@ -22,7 +29,7 @@ func (client *Client) Destroy() error {
 	switch client.provider.IAAS() {
 
 	case iaas.AWS:
-		tfOutputs, err1 := client.tfCLI.BuildOutput(tfInputVars)
+		tfOutputs, err1 := client.tfCLI.BuildOutput(ctx, tfInputVars)
 		if err1 != nil {
 			return err1
 		}
@@ -30,7 +37,7 @@ func (client *Client) Destroy() error {
 		if err2 != nil {
 			return err2
 		}
-		volumesToDelete, err1 = client.provider.DeleteVMsInVPC(vpcID)
+		volumesToDelete, err1 = client.provider.DeleteVMsInVPC(ctx, vpcID)
 		if err1 != nil {
 			return err1
 		}
@@ -41,13 +48,18 @@ func (client *Client) Destroy() error {
 			return err1
 		}
 		zone := client.provider.Zone("", "")
-		err1 = client.provider.DeleteVMsInDeployment(zone, project, conf.GetDeployment())
+		err1 = client.provider.DeleteVMsInDeployment(ctx, zone, project, conf.GetDeployment())
 		if err1 != nil {
 			return err1
 		}
+
+	case iaas.Azure:
+		if err1 := client.provider.DeleteVMsInDeployment(ctx, "", "", conf.GetDeployment()); err1 != nil {
+			return err1
+		}
 	}
 
-	err = client.tfCLI.Destroy(tfInputVars)
+	err = client.tfCLI.Destroy(ctx, tfInputVars)
 	if err != nil {
 		return err
 	}
@@ -56,11 +68,16 @@ func (client *Client) Destroy() error {
 		if len(volumesToDelete) > 0 {
 			fmt.Printf("Scheduling to delete %v volumes\n", len(volumesToDelete))
 		}
-		if err1 := client.provider.DeleteVolumes(volumesToDelete, iaas.DeleteVolume); err1 != nil {
+		if err1 := client.provider.DeleteVolumes(ctx, volumesToDelete, iaas.DeleteVolume); err1 != nil {
 			return err1
 		}
 	}
 
+	// The lock lives in the bucket, so stop renewing it before the bucket is deleted
+	if err = release(); err != nil {
+		return fmt.Errorf("error releasing lock: [%v]", err)
+	}
+
 	if err = client.configClient.DeleteAll(conf); err != nil {
 		return err
 	}
@@ -0,0 +1,53 @@
+package concourse
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/EngineerBetter/control-tower/bosh"
+	"github.com/EngineerBetter/control-tower/commands/encrypt"
+	"github.com/EngineerBetter/control-tower/config"
+)
+
+// encryptedAssets are the files in the config bucket which can hold secrets
+var encryptedAssets = []string{
+	"config.json",
+	bosh.StateFilename,
+	bosh.CredsFilename,
+	"director-creds-backup.yml",
+	maintenanceFilename,
+	autoscaleStateFilename,
+}
+
+// EncryptAssets encrypts the secret-bearing files in the config bucket in place. Unless args.KeepHistory is set,
+// it then deletes their earlier versions, which the bucket keeps and which may hold the secrets in plaintext.
+func (client *Client) EncryptAssets(ctx context.Context, args encrypt.Args) error {
+	return client.withLock(ctx, "encrypt", func(ctx context.Context) error {
+		encrypted, err := client.configClient.EncryptAssets(append(encryptedAssets, config.ConfigBackupFilenames()...))
+		for _, filename := range encrypted {
+			fmt.Fprintf(client.stdout, "Encrypted %s\n", filename)
+		}
+		if err != nil {
+			return fmt.Errorf("error encrypting config bucket: [%v]", err)
+		}
+
+		if args.KeepHistory {
+			return nil
+		}
+		for _, filename := range encrypted {
+			// Stop between files when interrupted or the lock is lost
+			if err = ctx.Err(); err != nil {
+				return err
+			}
+
+			deleted, err := client.configClient.DeleteOldAssetVersions(filename)
+			if err != nil {
+				return fmt.Errorf("error deleting earlier versions of [%v]: [%v]", filename, err)
+			}
+			if deleted > 0 {
+				fmt.Fprintf(client.stdout, "Deleted %d earlier versions of %s\n", deleted, filename)
+			}
+		}
+		return nil
+	})
+}
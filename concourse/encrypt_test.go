@@ -0,0 +1,117 @@
+package concourse
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/EngineerBetter/control-tower/bosh"
+	"github.com/EngineerBetter/control-tower/commands/encrypt"
+	"github.com/EngineerBetter/control-tower/config"
+	"github.com/EngineerBetter/control-tower/config/configfakes"
+	"github.com/EngineerBetter/control-tower/encryption"
+	"github.com/EngineerBetter/control-tower/iaas/iaasfakes"
+	"github.com/EngineerBetter/control-tower/store"
+)
+
+func newEncryptClient() (*Client, *configfakes.FakeIClient, *bytes.Buffer) {
+	configClient := &configfakes.FakeIClient{}
+	configClient.AcquireLockReturns(context.Background(), func() error { return nil }, nil)
+	configClient.EncryptAssetsReturns([]string{"config.json", "director-creds.yml"}, nil)
+	configClient.DeleteOldAssetVersionsReturns(2, nil)
+
+	stdout := &bytes.Buffer{}
+	return &Client{configClient: configClient, stdout: stdout, stderr: &bytes.Buffer{}}, configClient, stdout
+}
+
+func TestEncryptAssetsDeletesEarlierVersions(t *testing.T) {
+	client, configClient, stdout := newEncryptClient()
+
+	if err := client.EncryptAssets(context.Background(), encrypt.Args{}); err != nil {
+		t.Fatalf("EncryptAssets() error = %v", err)
+	}
+
+	if configClient.DeleteOldAssetVersionsCallCount() != 2 {
+		t.Fatalf("DeleteOldAssetVersions() called %d times, want 2", configClient.DeleteOldAssetVersionsCallCount())
+	}
+	for i, want := range []string{"config.json", "director-creds.yml"} {
+		if filename := configClient.DeleteOldAssetVersionsArgsForCall(i); filename != want {
+			t.Errorf("DeleteOldAssetVersions() call %d for %s, want %s", i, filename, want)
+		}
+	}
+	if !strings.Contains(stdout.String(), "Deleted 2 earlier versions of director-creds.yml") {
+		t.Errorf("output %q does not report the deleted versions", stdout.String())
+	}
+}
+
+func TestEncryptAssetsKeepHistory(t *testing.T) {
+	client, configClient, stdout := newEncryptClient()
+
+	if err := client.EncryptAssets(context.Background(), encrypt.Args{KeepHistory: true}); err != nil {
+		t.Fatalf("EncryptAssets() error = %v", err)
+	}
+
+	if configClient.DeleteOldAssetVersionsCallCount() != 0 {
+		t.Errorf("DeleteOldAssetVersions() called %d times with --keep-history", configClient.DeleteOldAssetVersionsCallCount())
+	}
+	if stdout.String() != "Encrypted config.json\nEncrypted director-creds.yml\n" {
+		t.Errorf("output = %q", stdout.String())
+	}
+}
+
+func TestEncryptAssetsStopsWhenTheLockIsLost(t *testing.T) {
+	client, configClient, _ := newEncryptClient()
+	lockCtx, loseLock := context.WithCancel(context.Background())
+	loseLock()
+	configClient.AcquireLockReturns(lockCtx, func() error { return nil }, nil)
+
+	if err := client.EncryptAssets(context.Background(), encrypt.Args{}); err != context.Canceled {
+		t.Errorf("EncryptAssets() error = %v, want %v", err, context.Canceled)
+	}
+	if configClient.DeleteOldAssetVersionsCallCount() != 0 {
+		t.Errorf("DeleteOldAssetVersions() called after the lock was lost")
+	}
+}
+
+func TestEncryptAssetsKeepsHistoryPaired(t *testing.T) {
+	stateStore := store.NewLocal(t.TempDir())
+	provider := &iaasfakes.FakeProvider{}
+	provider.RegionReturns("eu-west-1")
+	configClient := config.New(provider, stateStore, "test", "")
+	if err := stateStore.CreateBucket(configClient.BucketName); err != nil {
+		t.Fatal(err)
+	}
+	if err := configClient.StoreAsset(bosh.StateFilename, []byte(`{"state": "director"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := configClient.StoreAsset(bosh.CredsFilename, []byte("password: secret")); err != nil {
+		t.Fatal(err)
+	}
+	if err := configClient.Update(config.Config{Deployment: "control-tower-test", Version: "0.1.0"}); err != nil {
+		t.Fatal(err)
+	}
+
+	keyProvider, err := encryption.NewPassphrase("passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	configClient.KeyProvider = keyProvider
+	client := &Client{configClient: configClient, stdout: &bytes.Buffer{}, stderr: &bytes.Buffer{}}
+	if err = client.EncryptAssets(context.Background(), encrypt.Args{}); err != nil {
+		t.Fatalf("EncryptAssets() error = %v", err)
+	}
+
+	revisions, err := client.History()
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if len(revisions) != 1 {
+		t.Fatalf("History() returned %d revisions, want 1", len(revisions))
+	}
+	for _, filename := range historyFiles {
+		if _, ok := revisions[0].Files[filename]; !ok {
+			t.Errorf("revision after encrypt is missing %s", filename)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package concourse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/EngineerBetter/control-tower/bosh"
+	"github.com/EngineerBetter/control-tower/events"
+	"github.com/EngineerBetter/control-tower/iaas"
+)
+
+const historyConfigFilename = "config.json"
+
+// historyFiles are the files restored together by a rollback, in the order they are restored
+var historyFiles = []string{bosh.StateFilename, bosh.CredsFilename, historyConfigFilename}
+
+// Revision is the state of the config bucket just after a version of config.json was written
+type Revision struct {
+	Number  int       `json:"number"`
+	Time    time.Time `json:"time"`
+	Version string    `json:"version"`
+	Current bool      `json:"current"`
+	// Files holds the version of each of historyFiles that was current at Time, omitting those that did not exist yet
+	Files map[string]iaas.FileVersion `json:"files"`
+}
+
+// History returns every revision of the deployment's config, newest first
+func (client *Client) History() ([]Revision, error) {
+	versions := map[string][]iaas.FileVersion{}
+	for _, filename := range historyFiles {
+		fileVersions, err := client.configClient.AssetVersions(filename)
+		if err != nil {
+			return nil, fmt.Errorf("error listing versions of [%v]: [%v]", filename, err)
+		}
+		versions[filename] = fileVersions
+	}
+
+	records := map[string]configRecord{}
+	for i, configVersion := range versions[historyConfigFilename] {
+		record, err := client.loadConfigRecord(configVersion)
+		if err != nil {
+			return nil, fmt.Errorf("error reading revision %d of [%v]: [%v]", len(versions[historyConfigFilename])-i, historyConfigFilename, err)
+		}
+		records[configVersion.ID] = record
+	}
+
+	return buildRevisions(versions, records), nil
+}
+
+// Rollback restores config.json, the director state and the director creds to the versions in the given revision
+func (client *Client) Rollback(ctx context.Context, to int) error {
+	return client.withLock(ctx, "rollback", func(ctx context.Context) error {
+		revisions, err := client.History()
+		if err != nil {
+			return err
+		}
+
+		var revision *Revision
+		for i := range revisions {
+			if revisions[i].Number == to {
+				revision = &revisions[i]
+			}
+		}
+		if revision == nil {
+			return fmt.Errorf("revision %d not found, use `control-tower history` to list revisions", to)
+		}
+		if revision.Current {
+			return fmt.Errorf("revision %d is already the current revision", to)
+		}
+
+		for _, filename := range historyFiles {
+			// Stop between files when interrupted or the lock is lost
+			if err = ctx.Err(); err != nil {
+				return err
+			}
+
+			version, ok := revision.Files[filename]
+			if !ok {
+				events.Warning(fmt.Sprintf("%s did not exist at revision %d, leaving it unchanged", filename, to))
+				if _, err = fmt.Fprintf(client.stderr, "WARNING: %s did not exist at revision %d, leaving it unchanged\n", filename, to); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err = client.configClient.RestoreAssetVersion(filename, version.ID); err != nil {
+				return fmt.Errorf("error restoring [%v]: [%v]", filename, err)
+			}
+			if _, err = fmt.Fprintf(client.stdout, "Restored %s as of %s\n", filename, version.LastModified.Format(time.RFC3339)); err != nil {
+				return err
+			}
+		}
+
+		_, err = fmt.Fprintf(client.stdout, "\nRolled back to revision %d written by control-tower %s. Run `control-tower deploy` to apply it.\n", to, revision.Version)
+		return err
+	})
+}
+
+// configRecord is what history reads from each version of config.json
+type configRecord struct {
+	Version      string            `json:"version"`
+	FileVersions map[string]string `json:"file_versions"`
+}
+
+// buildRevisions makes a revision of each version of config.json, pairing it with the versions of the other files
+// that were current when it was written. Revisions are numbered from 1 for the oldest and returned newest first.
+func buildRevisions(versions map[string][]iaas.FileVersion, records map[string]configRecord) []Revision {
+	configVersions := versions[historyConfigFilename]
+
+	revisions := []Revision{}
+	for i, configVersion := range configVersions {
+		record := records[configVersion.ID]
+		revision := Revision{
+			Number:  len(configVersions) - i,
+			Time:    configVersion.LastModified,
+			Version: record.Version,
+			Current: i == 0,
+			Files:   map[string]iaas.FileVersion{historyConfigFilename: configVersion},
+		}
+
+		for _, filename := range historyFiles {
+			if filename == historyConfigFilename {
+				continue
+			}
+			if version, ok := pairedVersion(filename, versions[filename], configVersion, record); ok {
+				revision.Files[filename] = version
+			}
+		}
+
+		revisions = append(revisions, revision)
+	}
+
+	return revisions
+}
+
+// pairedVersion returns the version of a file that was current when configVersion was written. That is the version
+// recorded in the config, or for configs written before versions were recorded, the newest not written after it.
+func pairedVersion(filename string, versions []iaas.FileVersion, configVersion iaas.FileVersion, record configRecord) (iaas.FileVersion, bool) {
+	if record.FileVersions != nil {
+		id, ok := record.FileVersions[filename]
+		if !ok {
+			return iaas.FileVersion{}, false
+		}
+		for _, version := range versions {
+			if version.ID == id {
+				return version, true
+			}
+		}
+		return iaas.FileVersion{}, false
+	}
+
+	// Versions are newest first, so the first not written after config.json is the one that was current
+	for _, version := range versions {
+		if !version.LastModified.After(configVersion.LastModified) {
+			return version, true
+		}
+	}
+	return iaas.FileVersion{}, false
+}
+
+// loadConfigRecord reads the version of control-tower that wrote a version of config.json, and the versions of the
+// other files it recorded
+func (client *Client) loadConfigRecord(configVersion iaas.FileVersion) (configRecord, error) {
+	contents, err := client.configClient.LoadAssetVersion(historyConfigFilename, configVersion.ID)
+	if err != nil {
+		return configRecord{}, err
+	}
+
+	var record configRecord
+	err = json.Unmarshal(contents, &record)
+	return record, err
+}
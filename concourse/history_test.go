@@ -0,0 +1,182 @@
+package concourse
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EngineerBetter/control-tower/bosh"
+	"github.com/EngineerBetter/control-tower/config/configfakes"
+	"github.com/EngineerBetter/control-tower/iaas"
+)
+
+var historyEpoch = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func fileVersion(id string, minutes int) iaas.FileVersion {
+	return iaas.FileVersion{ID: id, LastModified: historyEpoch.Add(time.Duration(minutes) * time.Minute)}
+}
+
+func historyVersions() map[string][]iaas.FileVersion {
+	return map[string][]iaas.FileVersion{
+		"config.json":      {fileVersion("config-3", 30), fileVersion("config-2", 20), fileVersion("config-1", 0)},
+		bosh.StateFilename: {fileVersion("state-2", 25), fileVersion("state-1", 10)},
+		bosh.CredsFilename: {fileVersion("creds-1", 10)},
+	}
+}
+
+func TestBuildRevisions(t *testing.T) {
+	got := buildRevisions(historyVersions(), map[string]configRecord{})
+
+	want := []Revision{
+		{Number: 3, Time: historyEpoch.Add(30 * time.Minute), Current: true, Files: map[string]iaas.FileVersion{
+			"config.json":      fileVersion("config-3", 30),
+			bosh.StateFilename: fileVersion("state-2", 25),
+			bosh.CredsFilename: fileVersion("creds-1", 10),
+		}},
+		{Number: 2, Time: historyEpoch.Add(20 * time.Minute), Files: map[string]iaas.FileVersion{
+			"config.json":      fileVersion("config-2", 20),
+			bosh.StateFilename: fileVersion("state-1", 10),
+			bosh.CredsFilename: fileVersion("creds-1", 10),
+		}},
+		{Number: 1, Time: historyEpoch, Files: map[string]iaas.FileVersion{
+			"config.json": fileVersion("config-1", 0),
+		}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildRevisions() = %+v, want %+v", got, want)
+	}
+}
+
+func TestBuildRevisionsFromRecordedVersions(t *testing.T) {
+	// state-2 was written in the same instant as config-2 but after it, which the timestamps cannot tell
+	versions := map[string][]iaas.FileVersion{
+		"config.json":      {fileVersion("config-2", 20), fileVersion("config-1", 0)},
+		bosh.StateFilename: {fileVersion("state-2", 20), fileVersion("state-1", 10)},
+		bosh.CredsFilename: {fileVersion("creds-1", 10)},
+	}
+	records := map[string]configRecord{
+		"config-2": {Version: "0.2.0", FileVersions: map[string]string{bosh.StateFilename: "state-1", bosh.CredsFilename: "creds-1"}},
+		"config-1": {Version: "0.1.0", FileVersions: map[string]string{bosh.StateFilename: "deleted-by-encrypt"}},
+	}
+
+	got := buildRevisions(versions, records)
+
+	want := []Revision{
+		{Number: 2, Time: historyEpoch.Add(20 * time.Minute), Version: "0.2.0", Current: true, Files: map[string]iaas.FileVersion{
+			"config.json":      fileVersion("config-2", 20),
+			bosh.StateFilename: fileVersion("state-1", 10),
+			bosh.CredsFilename: fileVersion("creds-1", 10),
+		}},
+		{Number: 1, Time: historyEpoch, Version: "0.1.0", Files: map[string]iaas.FileVersion{
+			"config.json": fileVersion("config-1", 0),
+		}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildRevisions() = %+v, want %+v", got, want)
+	}
+}
+
+func newHistoryClient() (*Client, *configfakes.FakeIClient, *bytes.Buffer, *bytes.Buffer) {
+	versions := historyVersions()
+	configClient := &configfakes.FakeIClient{}
+	configClient.AcquireLockReturns(context.Background(), func() error { return nil }, nil)
+	configClient.AssetVersionsStub = func(filename string) ([]iaas.FileVersion, error) {
+		return versions[filename], nil
+	}
+	configClient.LoadAssetVersionStub = func(filename, versionID string) ([]byte, error) {
+		return []byte(`{"version":"0.` + strings.TrimPrefix(versionID, "config-") + `.0"}`), nil
+	}
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	return &Client{configClient: configClient, stdout: stdout, stderr: stderr}, configClient, stdout, stderr
+}
+
+func TestHistory(t *testing.T) {
+	client, _, _, _ := newHistoryClient()
+
+	revisions, err := client.History()
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if len(revisions) != 3 {
+		t.Fatalf("History() returned %d revisions", len(revisions))
+	}
+	for i, want := range []string{"0.3.0", "0.2.0", "0.1.0"} {
+		if revisions[i].Version != want {
+			t.Errorf("revision %d version = %s, want %s", revisions[i].Number, revisions[i].Version, want)
+		}
+	}
+}
+
+func TestRollback(t *testing.T) {
+	client, configClient, stdout, _ := newHistoryClient()
+
+	if err := client.Rollback(context.Background(), 2); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+
+	if _, command := configClient.AcquireLockArgsForCall(0); configClient.AcquireLockCallCount() != 1 || command != "rollback" {
+		t.Errorf("Rollback() did not lock the deployment")
+	}
+
+	restored := map[string]string{}
+	for i := 0; i < configClient.RestoreAssetVersionCallCount(); i++ {
+		filename, versionID := configClient.RestoreAssetVersionArgsForCall(i)
+		restored[filename] = versionID
+	}
+	want := map[string]string{"config.json": "config-2", bosh.StateFilename: "state-1", bosh.CredsFilename: "creds-1"}
+	if !reflect.DeepEqual(restored, want) {
+		t.Errorf("Rollback() restored %v, want %v", restored, want)
+	}
+	if filename, _ := configClient.RestoreAssetVersionArgsForCall(2); filename != "config.json" {
+		t.Errorf("config.json was not restored last")
+	}
+	if !strings.Contains(stdout.String(), "Rolled back to revision 2 written by control-tower 0.2.0") {
+		t.Errorf("Rollback() output = %s", stdout)
+	}
+}
+
+func TestRollbackSkipsMissingFiles(t *testing.T) {
+	client, configClient, _, stderr := newHistoryClient()
+
+	if err := client.Rollback(context.Background(), 1); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	if configClient.RestoreAssetVersionCallCount() != 1 {
+		t.Errorf("Rollback() restored %d files, want 1", configClient.RestoreAssetVersionCallCount())
+	}
+	if !strings.Contains(stderr.String(), bosh.StateFilename+" did not exist at revision 1") {
+		t.Errorf("Rollback() warnings = %s", stderr)
+	}
+}
+
+func TestRollbackStopsWhenTheLockIsLost(t *testing.T) {
+	client, configClient, _, _ := newHistoryClient()
+	lockCtx, loseLock := context.WithCancel(context.Background())
+	loseLock()
+	configClient.AcquireLockReturns(lockCtx, func() error { return nil }, nil)
+
+	if err := client.Rollback(context.Background(), 2); err != context.Canceled {
+		t.Errorf("Rollback() error = %v, want %v", err, context.Canceled)
+	}
+	if configClient.RestoreAssetVersionCallCount() != 0 {
+		t.Errorf("Rollback() restored files after the lock was lost")
+	}
+}
+
+func TestRollbackErrors(t *testing.T) {
+	client, configClient, _, _ := newHistoryClient()
+
+	if err := client.Rollback(context.Background(), 3); err == nil || !strings.Contains(err.Error(), "already the current revision") {
+		t.Errorf("Rollback() to the current revision error = %v", err)
+	}
+	if err := client.Rollback(context.Background(), 4); err == nil || !strings.Contains(err.Error(), "revision 4 not found") {
+		t.Errorf("Rollback() to a missing revision error = %v", err)
+	}
+	if configClient.RestoreAssetVersionCallCount() != 0 {
+		t.Errorf("failed rollbacks restored files")
+	}
+}
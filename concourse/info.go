@@ -2,6 +2,7 @@ package concourse
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -10,8 +11,6 @@ import (
 	"strings"
 	"text/template"
 
-	"github.com/EngineerBetter/control-tower/iaas"
-
 	"github.com/EngineerBetter/control-tower/bosh"
 	"github.com/EngineerBetter/control-tower/config"
 	"github.com/EngineerBetter/control-tower/util/yaml"
@@ -27,6 +26,13 @@ type Info struct {
 	GatewayUser string
 }
 
+// Redacted returns a copy of the info with the secrets in its config redacted
+func (info *Info) Redacted() *Info {
+	redacted := *info
+	redacted.Config = info.Config.Redacted()
+	return &redacted
+}
+
 // TerraformInfo represents the terraform output fields needed for the info templates
 type TerraformInfo struct {
 	DirectorPublicIP string
@@ -34,8 +40,7 @@ type TerraformInfo struct {
 }
 
 // FetchInfo fetches and builds the info
-func (client *Client) FetchInfo() (*Info, error) {
-	var gatewayUser string
+func (client *Client) FetchInfo(ctx context.Context) (*Info, error) {
 	conf, err := client.configClient.Load()
 	if err != nil {
 		return nil, err
@@ -72,14 +77,7 @@ func (client *Client) FetchInfo() (*Info, error) {
 
 	tfInputVars := client.tfInputVarsFactory.NewInputVars(conf)
 
-	switch client.provider.IAAS() {
-	case iaas.AWS:
-		gatewayUser = "vcap"
-	case iaas.GCP:
-		gatewayUser = "jumpbox"
-	}
-
-	tfOutputs, err := client.tfCLI.BuildOutput(tfInputVars)
+	tfOutputs, err := client.tfCLI.BuildOutput(ctx, tfInputVars)
 	if err != nil {
 		return nil, err
 	}
@@ -124,7 +122,7 @@ func (client *Client) FetchInfo() (*Info, error) {
 	}
 	defer boshClient.Cleanup()
 
-	instances, err := boshClient.Instances()
+	instances, err := boshClient.Instances(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("Error getting BOSH instances: %s", err)
 	}
@@ -133,7 +131,7 @@ func (client *Client) FetchInfo() (*Info, error) {
 		Terraform:   terraformInfo,
 		Config:      conf,
 		Instances:   instances,
-		GatewayUser: gatewayUser,
+		GatewayUser: bosh.GatewayUser(client.provider),
 		CertExpiry:  certExpiry,
 	}, nil
 }
@@ -147,7 +145,14 @@ Workers:
 	Count:              {{.Config.ConcourseWorkerCount}}
 	Size:               {{.Config.ConcourseWorkerSize}}
 	Outbound Public IP: {{.Terraform.NatGatewayIP}}
-
+{{range .Config.WorkerPools}}
+Worker pool {{.Name}}:
+	Count: {{.Count}}
+	Size:  {{.Size}}
+	Spot:  {{.Spot}}
+	Tags:  {{join "," .Tags}}
+	Team:  {{.Team}}
+{{end}}
 Instances:
 {{range .Instances}}
 	{{.Name}} {{.IP | replace "\n" ","}} {{.State}}
@@ -180,7 +185,9 @@ Bosh credentials:
 BOSH-generated NAT certs will expire on: {{ .CertExpiry }}
 
 Uses Control-Tower version {{.Config.Version}}
-
+{{if redacted .Config.ConcoursePassword}}
+Passwords and keys are redacted, use {{"control-tower credentials" | blue}} to show them
+{{end}}
 Built by {{"EngineerBetter http://engineerbetter.com" | blue}}
 `
 
@@ -189,7 +196,13 @@ func (info *Info) String() string {
 		"replace": func(old, new, s string) string {
 			return strings.Replace(s, old, new, -1)
 		},
+		"join": func(sep string, s []string) string {
+			return strings.Join(s, sep)
+		},
 		"blue": color.New(color.FgCyan, color.Bold).Sprint,
+		"redacted": func(s string) bool {
+			return s == config.RedactedValue
+		},
 	}).Parse(infoTemplate))
 	var buf bytes.Buffer
 	err := t.Execute(&buf, info)
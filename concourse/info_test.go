@@ -1,6 +1,8 @@
 package concourse
 
 import (
+	"encoding/json"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -66,3 +68,41 @@ func TestInfo_String(t *testing.T) {
 		})
 	}
 }
+
+func TestInfo_Redacted(t *testing.T) {
+	var conf config.Config
+	value := reflect.ValueOf(&conf).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		if value.Field(i).Kind() == reflect.String {
+			value.Field(i).SetString("value-of-" + value.Type().Field(i).Name)
+		}
+	}
+
+	info := &Info{Config: conf, Instances: []bosh.Instance{}}
+	redacted := info.Redacted()
+
+	infoJSON, err := json.Marshal(redacted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputs := map[string]string{
+		"String()": redacted.String(),
+		"JSON":     string(infoJSON),
+	}
+
+	secrets := conf.Secrets("")
+	for output, contents := range outputs {
+		for name, secret := range secrets {
+			if strings.Contains(contents, secret) {
+				t.Errorf("%s of redacted info contains %s", output, name)
+			}
+		}
+	}
+
+	if !strings.Contains(redacted.String(), "control-tower credentials") {
+		t.Errorf("String() of redacted info does not say how to show credentials")
+	}
+	if info.Config.DirectorPassword != "value-of-DirectorPassword" {
+		t.Errorf("Redacted() modified the original info")
+	}
+}
@@ -0,0 +1,24 @@
+package concourse
+
+import "context"
+
+// Logs downloads or follows the logs of target, which is an instance group, an instance or the director
+func (client *Client) Logs(ctx context.Context, target, job string, follow bool, dir string) error {
+	conf, err := client.configClient.Load()
+	if err != nil {
+		return err
+	}
+
+	tfOutputs, err := client.tfCLI.BuildOutput(ctx, client.tfInputVarsFactory.NewInputVars(conf))
+	if err != nil {
+		return err
+	}
+
+	boshClient, err := client.buildBoshClient(conf, tfOutputs)
+	if err != nil {
+		return err
+	}
+	defer boshClient.Cleanup()
+
+	return boshClient.Logs(ctx, target, job, follow, dir)
+}
@@ -1,27 +1,55 @@
 package concourse
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"strings"
 	"time"
 
+	"github.com/EngineerBetter/control-tower/events"
 	"github.com/EngineerBetter/control-tower/resource"
+	"github.com/EngineerBetter/control-tower/util"
 	"github.com/EngineerBetter/control-tower/util/yaml"
 
 	"github.com/EngineerBetter/control-tower/bosh"
 	"github.com/EngineerBetter/control-tower/commands/maintain"
 )
 
-type tasks struct {
-	description string
-	operation   string
-	action      func(string, string) error
+// MaintenanceStep is a single resumable step of a maintenance operation
+type MaintenanceStep struct {
+	Description string
+	action      func(ctx context.Context, client *Client, m maintain.Args) error
+}
+
+// MaintenanceOperation is a named sequence of steps run by `control-tower maintain`. Progress through the steps
+// is recorded in the config bucket so that an operation which fails part way through resumes from the failed step.
+type MaintenanceOperation struct {
+	Name        string
+	Description string
+	Steps       []MaintenanceStep
+	// stageFilename is the file in the config bucket recording progress through Steps
+	stageFilename string
+}
+
+// MaintenanceStatus describes the progress of a maintenance operation
+type MaintenanceStatus struct {
+	Operation  string `json:"operation"`
+	InProgress bool   `json:"in_progress"`
+	// NextStep is the index of the step that will run when the operation is resumed
+	NextStep            int    `json:"next_step"`
+	NextStepDescription string `json:"next_step_description"`
+	Steps               int    `json:"steps"`
 }
 
 // Maintenance is a struct representing values used by the maintenance command
 type Maintenance struct {
-	StatusIndex int `json:"status_index"`
+	// StatusIndex is the index of the last step to complete, or -1 when none has
+	StatusIndex int  `json:"status_index"`
+	InProgress  bool `json:"in_progress"`
+	// Credentials are generated by credential rotation and kept here until they have been stored
+	Credentials *RotatedCredentials `json:"credentials,omitempty"`
 }
 
 // Tables represents the output of bosh locks
@@ -37,63 +65,164 @@ type Table struct {
 
 const maintenanceFilename = "maintenance.json"
 
-// Maintain fetches and builds the info
-func (client *Client) Maintain(m maintain.Args) error {
-	switch {
-	case m.RenewNatsCertIsSet:
-		return client.renewCert(m)
+// maintenanceOperations are the operations available to `control-tower maintain`.
+// Add an operation here to make it available, no other changes to the command are needed.
+var maintenanceOperations = []MaintenanceOperation{
+	natsCertRenewal,
+	credentialRotation,
+	directorSSLRotation,
+	internalCARotation,
+	terraformStateMigration,
+}
+
+var natsCertRenewal = MaintenanceOperation{
+	Name:          maintain.RenewNatsCertOperation,
+	Description:   "Rotate the NATS certificate on the director",
+	stageFilename: maintenanceFilename,
+	Steps: []MaintenanceStep{
+		{"Adding new CA (create-env)", func(ctx context.Context, client *Client, m maintain.Args) error {
+			return client.createEnv(ctx, resource.AddNewCa)
+		}},
+		{"Recreating VMs for the first time (recreate)", func(ctx context.Context, client *Client, m maintain.Args) error { return client.recreate(ctx) }},
+		{"Removing old CA (create-env)", func(ctx context.Context, client *Client, m maintain.Args) error {
+			return client.createEnv(ctx, resource.RemoveOldCa)
+		}},
+		{"Recreating VMs for the second time (recreate)", func(ctx context.Context, client *Client, m maintain.Args) error { return client.recreate(ctx) }},
+		{"Cleaning up director-creds.yml", func(ctx context.Context, client *Client, m maintain.Args) error { return client.cleanup() }},
+	},
+}
+
+// MaintenanceOperations returns the operations available to `control-tower maintain`
+func MaintenanceOperations() []MaintenanceOperation {
+	return append([]MaintenanceOperation{}, maintenanceOperations...)
+}
+
+func findMaintenanceOperation(name string) (MaintenanceOperation, error) {
+	names := []string{}
+	for _, operation := range maintenanceOperations {
+		if operation.Name == name {
+			return operation, nil
+		}
+		names = append(names, operation.Name)
+	}
+	return MaintenanceOperation{}, fmt.Errorf("unknown maintenance operation [%v], can be any of %s", name, strings.Join(names, ", "))
+}
+
+// Maintain runs the maintenance operation selected by m, resuming it if it previously failed
+func (client *Client) Maintain(ctx context.Context, m maintain.Args) error {
+	name := m.SelectedOperation()
+	if name == "" {
+		return fmt.Errorf("no maintenance operation given, use `control-tower maintain --list` to see the available operations")
+	}
+	operation, err := findMaintenanceOperation(name)
+	if err != nil {
+		return err
+	}
+
+	return client.withLock(ctx, "maintain --"+operation.Name, func(ctx context.Context) error {
+		if err := client.checkNoOtherMaintenance(operation); err != nil {
+			return err
+		}
+		_ = client.waitForBOSHLocks(ctx, 10*time.Minute)
+		return client.runMaintenance(ctx, operation, m)
+	})
+}
+
+// checkNoOtherMaintenance fails if an operation other than operation stopped part way through, as running
+// another operation over a half-applied one can leave the deployment in a state that neither expects
+func (client *Client) checkNoOtherMaintenance(operation MaintenanceOperation) error {
+	statuses, err := client.MaintenanceStatus()
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		if status.Operation != operation.Name && status.InProgress {
+			return fmt.Errorf("maintenance operation [%v] is in progress at stage %d, finish it with `control-tower maintain --operation %v` before running [%v]", status.Operation, status.NextStep, status.Operation, operation.Name)
+		}
 	}
 	return nil
 }
 
-func (client *Client) renewCert(m maintain.Args) error {
+// MaintenanceStatus returns the progress of every maintenance operation recorded in the config bucket
+func (client *Client) MaintenanceStatus() ([]MaintenanceStatus, error) {
+	statuses := []MaintenanceStatus{}
+	for _, operation := range maintenanceOperations {
+		maintenance, err := client.retrieveStage(operation.stageFilename)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving the status of [%v]: [%v]", operation.Name, err)
+		}
 
-	_ = client.waitForBOSHLocks(10 * time.Minute)
+		status := MaintenanceStatus{
+			Operation: operation.Name,
+			// Stage files written before InProgress was recorded only reset StatusIndex on completion
+			InProgress: maintenance.InProgress || maintenance.StatusIndex >= 0,
+			NextStep:   client.determineStage(maintenance),
+			Steps:      len(operation.Steps),
+		}
+		if status.NextStep < len(operation.Steps) {
+			status.NextStepDescription = operation.Steps[status.NextStep].Description
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
 
-	maintenance, err := client.retrieveStage()
+// runMaintenance runs the steps of operation in order from the requested or next stage, recording progress in the config bucket
+func (client *Client) runMaintenance(ctx context.Context, operation MaintenanceOperation, m maintain.Args) error {
+	maintenance, err := client.retrieveStage(operation.stageFilename)
 	if err != nil {
 		return err
 	}
 
-	var stageIndex int
+	stageIndex := client.determineStage(maintenance)
 	if m.StageIsSet {
 		stageIndex = m.Stage
-	} else {
-		stageIndex, err = client.determineStage(maintenance)
-		if err != nil {
-			return err
-		}
 	}
 
-	tasks := []tasks{
-		{"Adding new CA", resource.AddNewCa, client.createEnv},
-		{"Recreating VMs for the first time", "first", client.recreate},
-		{"Removing old CA", resource.RemoveOldCa, client.createEnv},
-		{"Recreating VMs for the second time", "second", client.recreate},
-		{"Cleaning up director-creds.yml", "", client.cleanup},
+	if stageIndex < 0 || stageIndex >= len(operation.Steps) {
+		return fmt.Errorf("invalid stage %d, %s has stages 0 to %d", stageIndex, operation.Name, len(operation.Steps)-1)
 	}
 
-	if stageIndex >= len(tasks) {
-		return fmt.Errorf("Invalid stage index")
+	maintenance.InProgress = true
+	err = client.updateStage(operation.stageFilename, stageIndex-1, maintenance)
+	if err != nil {
+		return err
 	}
 
-	for i := stageIndex; i < len(tasks); i++ {
-		fmt.Printf("current action: %s\n", tasks[i].description)
-		err1 := tasks[i].action(tasks[i].description, tasks[i].operation)
-		if err1 != nil {
-			return err1
+	for i := stageIndex; i < len(operation.Steps); i++ {
+		// Stop between steps when interrupted, so that the operation resumes from the next one
+		if err = ctx.Err(); err != nil {
+			return err
 		}
-		err1 = client.updateStage(i, maintenance)
-		if err1 != nil {
-			return err1
+		_, err = fmt.Fprintf(client.stdout, "current action: %s\n", operation.Steps[i].Description)
+		if err != nil {
+			return err
+		}
+		err = events.Phase(operation.Steps[i].Description, func() error {
+			return operation.Steps[i].action(ctx, client, m)
+		})
+		if err != nil {
+			return err
+		}
+		// The step may have staged values in the stage file
+		maintenance, err = client.retrieveStage(operation.stageFilename)
+		if err != nil {
+			return err
+		}
+		maintenance.InProgress = true
+		err = client.updateStage(operation.stageFilename, i, maintenance)
+		if err != nil {
+			return err
 		}
 	}
-	err = client.updateStage(-1, maintenance)
-	return err
+
+	maintenance.InProgress = false
+	maintenance.Credentials = nil
+	return client.updateStage(operation.stageFilename, -1, maintenance)
 }
 
 // constructBoshClient creates a boshClient for use in this package
-func (client *Client) constructBoshClient() (*bosh.IClient, error) {
+func (client *Client) constructBoshClient(ctx context.Context) (*bosh.IClient, error) {
 	conf, err := client.configClient.Load()
 	if err != nil {
 		return nil, err
@@ -101,7 +230,7 @@ func (client *Client) constructBoshClient() (*bosh.IClient, error) {
 
 	tfInputVars := client.tfInputVarsFactory.NewInputVars(conf)
 
-	tfOutputs, err := client.tfCLI.BuildOutput(tfInputVars)
+	tfOutputs, err := client.tfCLI.BuildOutput(ctx, tfInputVars)
 	if err != nil {
 		return nil, err
 	}
@@ -115,15 +244,15 @@ func (client *Client) constructBoshClient() (*bosh.IClient, error) {
 
 // checkIfLocked checks if the lock is taken on the director
 // returns true if the lock is taken
-func (client *Client) checkIfLocked() (bool, error) {
+func (client *Client) checkIfLocked(ctx context.Context) (bool, error) {
 	var tables Tables
-	boshClientPointer, err := client.constructBoshClient()
+	boshClientPointer, err := client.constructBoshClient(ctx)
 	if err != nil {
 		return true, err
 	}
 	boshClient := *boshClientPointer
 	defer boshClient.Cleanup()
-	lockBytes, err := boshClient.Locks()
+	lockBytes, err := boshClient.Locks(ctx)
 	if err != nil {
 		return true, err
 	}
@@ -141,33 +270,38 @@ func (client *Client) checkIfLocked() (bool, error) {
 
 // waitForBOSHLocks will wait waitTime for BOSH to release its locks in order to proceed.
 // It will also printout a message to the user that the system is waiting for those locks.
-func (client *Client) waitForBOSHLocks(waitTime time.Duration) error {
-	start := time.Now().UTC()
+func (client *Client) waitForBOSHLocks(ctx context.Context, waitTime time.Duration) error {
+	waitCtx, cancel := context.WithTimeout(ctx, waitTime)
+	defer cancel()
+	backoff := util.NewBackoff(time.Second, 30*time.Second)
 	for {
 		fmt.Println("Waiting for BOSH lock to become available")
-		locked, err := client.checkIfLocked()
+		locked, err := client.checkIfLocked(waitCtx)
 		if err != nil {
 			return err
 		}
 		if !locked {
 			return nil
 		}
-		if time.Since(start) > waitTime {
-			return fmt.Errorf("BOSH lock failed to become available after %ds", waitTime/1000)
+		if err = backoff.Wait(waitCtx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("BOSH lock failed to become available after %v", waitTime)
 		}
 	}
 }
 
 // retrieveStage will retrieve the maintenance object from the config bucket
 // if the object is not found it will create one with statusIndex of -1
-func (client *Client) retrieveStage() (*Maintenance, error) {
+func (client *Client) retrieveStage(stageFilename string) (*Maintenance, error) {
 	var maintenance Maintenance
-	fileExists, err := client.configClient.HasAsset(maintenanceFilename)
+	fileExists, err := client.configClient.HasAsset(stageFilename)
 	if err != nil {
 		return nil, err
 	}
 	if fileExists {
-		fileContents, err := client.configClient.LoadAsset(maintenanceFilename)
+		fileContents, err := client.configClient.LoadAsset(stageFilename)
 		if err != nil {
 			return nil, err
 		}
@@ -182,23 +316,23 @@ func (client *Client) retrieveStage() (*Maintenance, error) {
 }
 
 // determineStage returns the index of the next operation to be run
-func (client *Client) determineStage(maintenance *Maintenance) (int, error) {
-	return maintenance.StatusIndex + 1, nil
+func (client *Client) determineStage(maintenance *Maintenance) int {
+	return maintenance.StatusIndex + 1
 }
 
 // updateStage stores the specified index in the maintenance object in the config bucket
-func (client *Client) updateStage(index int, maintenance *Maintenance) error {
+func (client *Client) updateStage(stageFilename string, index int, maintenance *Maintenance) error {
 	maintenance.StatusIndex = index
 	maintenanceBytes, err := json.Marshal(maintenance)
 	if err != nil {
 		return err
 	}
-	return client.configClient.StoreAsset(maintenanceFilename, maintenanceBytes)
+	return client.configClient.StoreAsset(stageFilename, maintenanceBytes)
 }
 
 // createEnv runs bosh create-env
-func (client *Client) createEnv(description, operation string) error {
-	boshClientPointer, err := client.constructBoshClient()
+func (client *Client) createEnv(ctx context.Context, operation string) error {
+	boshClientPointer, err := client.constructBoshClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -213,7 +347,7 @@ func (client *Client) createEnv(description, operation string) error {
 	if err != nil {
 		return err
 	}
-	boshStateBytes, boshCredsBytes, err = boshClient.CreateEnv(boshStateBytes, boshCredsBytes, operation)
+	boshStateBytes, boshCredsBytes, err = boshClient.CreateEnv(ctx, boshStateBytes, boshCredsBytes, operation)
 	err1 := client.configClient.StoreAsset(bosh.StateFilename, boshStateBytes)
 	if err == nil {
 		err = err1
@@ -230,15 +364,15 @@ func (client *Client) createEnv(description, operation string) error {
 }
 
 // recreate runs bosh recreate
-func (client *Client) recreate(description, operation string) error {
-	boshClientPointer, err := client.constructBoshClient()
+func (client *Client) recreate(ctx context.Context) error {
+	boshClientPointer, err := client.constructBoshClient(ctx)
 	if err != nil {
 		return err
 	}
 	boshClient := *boshClientPointer
 	defer boshClient.Cleanup()
 
-	err = boshClient.Recreate()
+	err = boshClient.Recreate(ctx)
 	if err != nil {
 		return err
 	}
@@ -246,7 +380,7 @@ func (client *Client) recreate(description, operation string) error {
 }
 
 // cleanup cleans up the director-creds.yml file
-func (client *Client) cleanup(description, operation string) error {
+func (client *Client) cleanup() error {
 	directorCredsBytes, err := loadDirectorCreds(client.configClient)
 	if err != nil {
 		return err
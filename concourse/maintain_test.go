@@ -0,0 +1,193 @@
+package concourse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/EngineerBetter/control-tower/commands/maintain"
+	"github.com/EngineerBetter/control-tower/config/configfakes"
+)
+
+func newMaintenanceClient(assets map[string][]byte) (*Client, *bytes.Buffer) {
+	configClient := &configfakes.FakeIClient{}
+	configClient.AcquireLockReturns(context.Background(), func() error { return nil }, nil)
+	configClient.HasAssetStub = func(filename string) (bool, error) {
+		_, ok := assets[filename]
+		return ok, nil
+	}
+	configClient.LoadAssetStub = func(filename string) ([]byte, error) {
+		return assets[filename], nil
+	}
+	configClient.StoreAssetStub = func(filename string, contents []byte) error {
+		assets[filename] = contents
+		return nil
+	}
+
+	stdout := &bytes.Buffer{}
+	return &Client{configClient: configClient, stdout: stdout, stderr: &bytes.Buffer{}}, stdout
+}
+
+func storedMaintenance(t *testing.T, assets map[string][]byte, filename string) Maintenance {
+	var maintenance Maintenance
+	if err := json.Unmarshal(assets[filename], &maintenance); err != nil {
+		t.Fatalf("%s is not valid: %v", filename, err)
+	}
+	return maintenance
+}
+
+func testOperation(ran *[]string, failAt string) MaintenanceOperation {
+	step := func(description string) MaintenanceStep {
+		return MaintenanceStep{description, func(ctx context.Context, client *Client, m maintain.Args) error {
+			*ran = append(*ran, description)
+			if description == failAt {
+				return errors.New("step failed")
+			}
+			return nil
+		}}
+	}
+	return MaintenanceOperation{
+		Name:          "test-operation",
+		stageFilename: "test-operation.json",
+		Steps:         []MaintenanceStep{step("first"), step("second"), step("third")},
+	}
+}
+
+func TestRunMaintenanceResumesFromFailedStep(t *testing.T) {
+	assets := map[string][]byte{}
+	client, stdout := newMaintenanceClient(assets)
+
+	var ran []string
+	err := client.runMaintenance(context.Background(), testOperation(&ran, "second"), maintain.Args{})
+	if err == nil || err.Error() != "step failed" {
+		t.Fatalf("runMaintenance() error = %v", err)
+	}
+	if got := storedMaintenance(t, assets, "test-operation.json"); got != (Maintenance{StatusIndex: 0, InProgress: true}) {
+		t.Errorf("stored maintenance after failure = %+v", got)
+	}
+
+	ran = nil
+	if err = client.runMaintenance(context.Background(), testOperation(&ran, ""), maintain.Args{}); err != nil {
+		t.Fatalf("runMaintenance() error = %v", err)
+	}
+	if strings.Join(ran, ",") != "second,third" {
+		t.Errorf("resumed run ran %v, want second and third", ran)
+	}
+	if got := storedMaintenance(t, assets, "test-operation.json"); got != (Maintenance{StatusIndex: -1}) {
+		t.Errorf("stored maintenance after completion = %+v", got)
+	}
+	if !strings.Contains(stdout.String(), "current action: third\n") {
+		t.Errorf("runMaintenance() output = %s", stdout)
+	}
+}
+
+func TestRunMaintenanceFromStage(t *testing.T) {
+	assets := map[string][]byte{}
+	client, _ := newMaintenanceClient(assets)
+
+	var ran []string
+	if err := client.runMaintenance(context.Background(), testOperation(&ran, ""), maintain.Args{Stage: 2, StageIsSet: true}); err != nil {
+		t.Fatalf("runMaintenance() error = %v", err)
+	}
+	if strings.Join(ran, ",") != "third" {
+		t.Errorf("runMaintenance() from stage 2 ran %v", ran)
+	}
+
+	err := client.runMaintenance(context.Background(), testOperation(&ran, ""), maintain.Args{Stage: 3, StageIsSet: true})
+	if err == nil || !strings.Contains(err.Error(), "invalid stage 3, test-operation has stages 0 to 2") {
+		t.Errorf("runMaintenance() from stage 3 error = %v", err)
+	}
+}
+
+func TestRunMaintenanceStopsBetweenStepsWhenInterrupted(t *testing.T) {
+	assets := map[string][]byte{}
+	client, _ := newMaintenanceClient(assets)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var ran []string
+	operation := testOperation(&ran, "")
+	first := operation.Steps[0].action
+	operation.Steps[0].action = func(ctx context.Context, client *Client, m maintain.Args) error {
+		cancel()
+		return first(ctx, client, m)
+	}
+
+	err := client.runMaintenance(ctx, operation, maintain.Args{})
+	if err != context.Canceled {
+		t.Fatalf("runMaintenance() error = %v, want %v", err, context.Canceled)
+	}
+	if strings.Join(ran, ",") != "first" {
+		t.Errorf("interrupted run ran %v, want only first", ran)
+	}
+	if got := storedMaintenance(t, assets, "test-operation.json"); got != (Maintenance{StatusIndex: 0, InProgress: true}) {
+		t.Errorf("stored maintenance after interrupt = %+v", got)
+	}
+}
+
+func TestMaintenanceStatus(t *testing.T) {
+	assets := map[string][]byte{
+		// written before in_progress was recorded
+		maintenanceFilename:        []byte(`{"status_index":1}`),
+		credentialRotationFilename: []byte(`{"status_index":-1}`),
+	}
+	client, _ := newMaintenanceClient(assets)
+
+	statuses, err := client.MaintenanceStatus()
+	if err != nil {
+		t.Fatalf("MaintenanceStatus() error = %v", err)
+	}
+	want := []MaintenanceStatus{
+		{Operation: maintain.RenewNatsCertOperation, InProgress: true, NextStep: 2, NextStepDescription: "Removing old CA (create-env)", Steps: 5},
+		{Operation: maintain.RotateCredentialsOperation, NextStep: 0, NextStepDescription: "Generating new credentials", Steps: 4},
+	}
+	if len(statuses) != len(MaintenanceOperations()) {
+		t.Fatalf("MaintenanceStatus() = %+v", statuses)
+	}
+	for i := range want {
+		if statuses[i] != want[i] {
+			t.Errorf("MaintenanceStatus()[%d] = %+v, want %+v", i, statuses[i], want[i])
+		}
+	}
+}
+
+func TestMaintainErrors(t *testing.T) {
+	client, _ := newMaintenanceClient(map[string][]byte{})
+
+	if err := client.Maintain(context.Background(), maintain.Args{}); err == nil || !strings.Contains(err.Error(), "--list") {
+		t.Errorf("Maintain() without an operation error = %v", err)
+	}
+	err := client.Maintain(context.Background(), maintain.Args{Operation: "defrag", OperationIsSet: true})
+	if err == nil || !strings.Contains(err.Error(), "unknown maintenance operation [defrag], can be any of renew-nats-cert, rotate-credentials") {
+		t.Errorf("Maintain() with an unknown operation error = %v", err)
+	}
+}
+
+func TestMaintainRefusesWhileAnotherOperationIsInProgress(t *testing.T) {
+	assets := map[string][]byte{
+		credentialRotationFilename: []byte(`{"status_index":1,"in_progress":true}`),
+	}
+	client, _ := newMaintenanceClient(assets)
+
+	err := client.Maintain(context.Background(), maintain.Args{RenewNatsCertIsSet: true})
+	if err == nil || !strings.Contains(err.Error(), "maintenance operation [rotate-credentials] is in progress at stage 2") {
+		t.Fatalf("Maintain() error = %v", err)
+	}
+	if _, ok := assets[maintenanceFilename]; ok {
+		t.Errorf("Maintain() started %s while another operation was in progress", maintain.RenewNatsCertOperation)
+	}
+}
+
+func TestMaintenanceOperationsAreDistinct(t *testing.T) {
+	names := map[string]bool{}
+	filenames := map[string]bool{}
+	for _, operation := range MaintenanceOperations() {
+		if names[operation.Name] || filenames[operation.stageFilename] {
+			t.Errorf("%s shares its name or stage file with another operation", operation.Name)
+		}
+		names[operation.Name] = true
+		filenames[operation.stageFilename] = true
+	}
+}
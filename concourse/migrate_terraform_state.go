@@ -0,0 +1,22 @@
+package concourse
+
+import (
+	"context"
+
+	"github.com/EngineerBetter/control-tower/commands/maintain"
+)
+
+const terraformStateMigrationFilename = "terraform-state-migration.json"
+
+// terraformStateMigration creates the terraform state lock of deployments made before state was locked. Commands that
+// change infrastructure with terraform create it themselves, so it only saves them doing so.
+var terraformStateMigration = MaintenanceOperation{
+	Name:          "migrate-terraform-state",
+	Description:   "Lock the terraform state of a deployment made before state locking was added",
+	stageFilename: terraformStateMigrationFilename,
+	Steps: []MaintenanceStep{
+		{"Creating the terraform state lock", func(ctx context.Context, client *Client, m maintain.Args) error {
+			return client.configClient.EnsureBucketExists()
+		}},
+	},
+}
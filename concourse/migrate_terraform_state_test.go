@@ -0,0 +1,30 @@
+package concourse
+
+import (
+	"context"
+	"testing"
+
+	"github.com/EngineerBetter/control-tower/commands/maintain"
+	"github.com/EngineerBetter/control-tower/config/configfakes"
+	"github.com/EngineerBetter/control-tower/terraform/terraformfakes"
+)
+
+func TestTerraformStateMigration(t *testing.T) {
+	assets := map[string][]byte{}
+	client, _ := newMaintenanceClient(assets)
+	configClient := client.configClient.(*configfakes.FakeIClient)
+	tfCLI := &terraformfakes.FakeCLIInterface{}
+	client.tfCLI = tfCLI
+
+	err := client.runMaintenance(context.Background(), terraformStateMigration, maintain.Args{})
+	if err != nil {
+		t.Fatalf("runMaintenance() error = %v", err)
+	}
+
+	if configClient.EnsureBucketExistsCallCount() != 1 {
+		t.Errorf("EnsureBucketExists() called %d times, want 1", configClient.EnsureBucketExistsCallCount())
+	}
+	if tfCLI.ApplyCallCount() != 0 || tfCLI.PlanCallCount() != 0 {
+		t.Errorf("expected the migration not to run terraform")
+	}
+}
@@ -0,0 +1,228 @@
+package concourse
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/EngineerBetter/control-tower/terraform"
+	"github.com/fatih/color"
+)
+
+// Plan represents the changes a deploy would make without making them
+type Plan struct {
+	Deployment    string
+	NewDeployment bool
+	Terraform     terraform.PlanSummary
+	BOSH          BOSHDiff
+}
+
+// BOSHDiff represents the changes a deploy would make to the concourse BOSH deployment
+type BOSHDiff struct {
+	Diff                  string
+	Additions             int
+	Removals              int
+	RemovedInstanceGroups []string
+	ScaledDown            []string
+}
+
+// Destructive returns true if applying the plan would destroy or replace infrastructure or VMs
+func (plan *Plan) Destructive() bool {
+	return plan.Terraform.IsDestructive() || plan.BOSH.IsDestructive()
+}
+
+// HasChanges returns true if applying the plan would change the deployment
+func (diff BOSHDiff) HasChanges() bool {
+	return diff.Additions+diff.Removals > 0
+}
+
+// IsDestructive returns true if the BOSH deploy would delete any VMs
+func (diff BOSHDiff) IsDestructive() bool {
+	return len(diff.RemovedInstanceGroups) > 0 || len(diff.ScaledDown) > 0
+}
+
+// Plan works out what a deploy would change without applying anything
+func (client *Client) Plan(ctx context.Context) (*Plan, error) {
+	bucketExists, err := client.configClient.ConfigBucketExists()
+	if err != nil {
+		return nil, fmt.Errorf("error determining if config bucket exists before plan: [%v]", err)
+	}
+
+	conf, _, priorConfigExists, err := client.buildConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error building config before plan: [%v]", err)
+	}
+
+	r, err := client.checkPreTerraformConfigRequirements(conf, false)
+	if err != nil {
+		return nil, err
+	}
+	conf.Region = r.Region
+	conf.SourceAccessIP = r.SourceAccessIP
+	conf.HostedZoneID = r.HostedZoneID
+	conf.HostedZoneRecordPrefix = r.HostedZoneRecordPrefix
+	conf.Domain = r.Domain
+
+	tfInputVars := client.tfInputVarsFactory.NewInputVars(conf)
+	if !bucketExists {
+		// Plan against empty state rather than create the bucket terraform keeps its state in
+		tfInputVars = terraform.WithLocalBackend(tfInputVars)
+	}
+
+	tfPlan, err := client.tfCLI.Plan(ctx, tfInputVars)
+	if err != nil {
+		return nil, fmt.Errorf("error running terraform plan: [%v]", err)
+	}
+
+	plan := &Plan{
+		Deployment: conf.Deployment,
+		Terraform:  tfPlan,
+	}
+
+	boshStateBytes, err := loadDirectorState(client.configClient)
+	if err != nil {
+		return nil, err
+	}
+	if !priorConfigExists || boshStateBytes == nil {
+		plan.NewDeployment = true
+		return plan, nil
+	}
+
+	tfOutputs, err := client.tfCLI.BuildOutput(ctx, tfInputVars)
+	if err != nil {
+		return nil, err
+	}
+
+	conf.Tags = stripVersion(conf.Tags)
+	conf.Tags = append([]string{fmt.Sprintf("control-tower-version=%s", client.version)}, conf.Tags...)
+	conf.Version = client.version
+
+	boshClient, err := client.buildBoshClient(conf, tfOutputs)
+	if err != nil {
+		return nil, err
+	}
+	defer boshClient.Cleanup()
+
+	boshCredsBytes, err := loadDirectorCreds(client.configClient)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := boshClient.Diff(ctx, boshCredsBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.BOSH = parseBOSHDiff(diff)
+
+	return plan, nil
+}
+
+var (
+	// bosh prefixes each line of a diff with a two character marker
+	boshDiffInstanceGroupRegexp = regexp.MustCompile(`^([ +-]) - name: (\S+)`)
+	boshDiffInstancesRegexp     = regexp.MustCompile(`^([+-])\s+instances: (\d+)`)
+)
+
+func parseBOSHDiff(diff string) BOSHDiff {
+	result := BOSHDiff{Diff: diff}
+
+	var section, instanceGroup string
+	removedInstances := -1
+
+	scanner := bufio.NewScanner(strings.NewReader(diff))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(line) < 2 {
+			continue
+		}
+
+		switch line[0] {
+		case '+':
+			result.Additions++
+		case '-':
+			result.Removals++
+		}
+
+		body := line[2:]
+		if len(body) > 0 && body[0] != ' ' && body[0] != '-' {
+			section = strings.TrimSuffix(body, ":")
+			continue
+		}
+		if section != "instance_groups" {
+			continue
+		}
+
+		if m := boshDiffInstanceGroupRegexp.FindStringSubmatch(line); m != nil {
+			instanceGroup = m[2]
+			removedInstances = -1
+			if m[1] == "-" {
+				result.RemovedInstanceGroups = append(result.RemovedInstanceGroups, instanceGroup)
+			}
+			continue
+		}
+
+		if m := boshDiffInstancesRegexp.FindStringSubmatch(line); m != nil {
+			count, _ := strconv.Atoi(m[2])
+			if m[1] == "-" {
+				removedInstances = count
+			} else if removedInstances > count {
+				result.ScaledDown = append(result.ScaledDown, fmt.Sprintf("%s (%d -> %d)", instanceGroup, removedInstances, count))
+			}
+		}
+	}
+
+	return result
+}
+
+const planTemplate = `Plan for deployment {{.Deployment}}:
+{{if .NewDeployment}}
+	This is a new deployment, BOSH changes will be calculated once the director exists
+{{end}}
+Infrastructure:
+{{- if .Terraform.HasChanges}}
+	{{.Terraform.ToAdd}} to add, {{.Terraform.ToChange}} to change, {{.Terraform.ToDestroy}} to destroy
+{{- range .Terraform.Replaced}}
+	{{"replace" | red}} {{.}}
+{{- end}}
+{{- range .Terraform.Destroyed}}
+	{{"destroy" | red}} {{.}}
+{{- end}}
+{{- else}}
+	No changes
+{{- end}}
+{{if not .NewDeployment}}
+Concourse:
+{{- if .BOSH.HasChanges}}
+	{{.BOSH.Additions}} lines added, {{.BOSH.Removals}} lines removed
+{{- range .BOSH.RemovedInstanceGroups}}
+	{{"delete instance group" | red}} {{.}}
+{{- end}}
+{{- range .BOSH.ScaledDown}}
+	{{"scale down" | red}} {{.}}
+{{- end}}
+
+{{.BOSH.Diff}}
+{{- else}}
+	No changes
+{{- end}}
+{{end}}
+{{- if .Destructive}}
+{{"This plan is destructive" | red}}
+{{end}}`
+
+// Write prints a human readable summary of plan to w
+func (plan *Plan) Write(w io.Writer) error {
+	t, err := template.New("plan").Funcs(template.FuncMap{
+		"red": color.New(color.FgRed, color.Bold).Sprint,
+	}).Parse(planTemplate)
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, plan)
+}
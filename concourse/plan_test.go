@@ -0,0 +1,73 @@
+package concourse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseBOSHDiff(t *testing.T) {
+	tests := []struct {
+		name                  string
+		diff                  string
+		additions             int
+		removals              int
+		removedInstanceGroups []string
+		scaledDown            []string
+	}{
+		{
+			name: "no changes",
+			diff: "",
+		},
+		{
+			name: "scaling workers up",
+			diff: `  instance_groups:
+  - name: worker
+-   instances: 1
++   instances: 2
+`,
+			additions: 1,
+			removals:  1,
+		},
+		{
+			name: "scaling workers down",
+			diff: `  instance_groups:
+  - name: web
+    instances: 1
+  - name: worker
+-   instances: 3
++   instances: 1
+`,
+			additions:  1,
+			removals:   1,
+			scaledDown: []string{"worker (3 -> 1)"},
+		},
+		{
+			name: "removing an instance group",
+			diff: `  instance_groups:
+- - name: grafana
+-   instances: 1
+  releases:
+- - name: grafana
+`,
+			removals:              3,
+			removedInstanceGroups: []string{"grafana"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseBOSHDiff(tt.diff)
+			if got.Additions != tt.additions || got.Removals != tt.removals {
+				t.Errorf("parseBOSHDiff() counted +%d -%d, want +%d -%d", got.Additions, got.Removals, tt.additions, tt.removals)
+			}
+			if !reflect.DeepEqual(got.RemovedInstanceGroups, tt.removedInstanceGroups) {
+				t.Errorf("parseBOSHDiff() RemovedInstanceGroups = %v, want %v", got.RemovedInstanceGroups, tt.removedInstanceGroups)
+			}
+			if !reflect.DeepEqual(got.ScaledDown, tt.scaledDown) {
+				t.Errorf("parseBOSHDiff() ScaledDown = %v, want %v", got.ScaledDown, tt.scaledDown)
+			}
+			if got.IsDestructive() != (len(tt.removedInstanceGroups)+len(tt.scaledDown) > 0) {
+				t.Errorf("parseBOSHDiff() IsDestructive = %v", got.IsDestructive())
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package concourse
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/EngineerBetter/control-tower/bosh"
+	"github.com/EngineerBetter/control-tower/certs"
+	"github.com/EngineerBetter/control-tower/commands/maintain"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	directorSSLRotationFilename = "director-ssl-rotation.json"
+	internalCARotationFilename  = "internal-ca-rotation.json"
+)
+
+// internalCAVar is the director-creds.yml variable holding the CA of the concourse deployment's internal
+// certificates, such as the internal_tls certificate used by the ATC, CredHub and UAA.
+// newInternalCAVar holds its replacement while it is being rotated.
+const (
+	internalCAVar    = "ca"
+	newInternalCAVar = "ca_2"
+)
+
+var directorSSLRotation = MaintenanceOperation{
+	Name:          "rotate-director-ssl",
+	Description:   "Rotate the CA and certificate of the director's API",
+	stageFilename: directorSSLRotationFilename,
+	Steps: []MaintenanceStep{
+		{"Generating new director CA and certificate", func(ctx context.Context, client *Client, m maintain.Args) error {
+			return client.generateDirectorCerts(ctx)
+		}},
+		{"Deploying director with the new certificate (create-env)", func(ctx context.Context, client *Client, m maintain.Args) error {
+			return client.createEnv(ctx, "")
+		}},
+		{"Removing old director CA", func(ctx context.Context, client *Client, m maintain.Args) error {
+			return client.removeOldDirectorCA()
+		}},
+		{"Deploying director without the old CA (create-env)", func(ctx context.Context, client *Client, m maintain.Args) error {
+			return client.createEnv(ctx, "")
+		}},
+	},
+}
+
+var internalCARotation = MaintenanceOperation{
+	Name:          "rotate-internal-ca",
+	Description:   "Rotate the CA of the internal certificates used by Concourse, CredHub and UAA",
+	stageFilename: internalCARotationFilename,
+	Steps: []MaintenanceStep{
+		{"Adding new CA", func(ctx context.Context, client *Client, m maintain.Args) error {
+			return client.addNewInternalCA()
+		}},
+		{"Deploying with both CAs trusted (create-env and deploy)", func(ctx context.Context, client *Client, m maintain.Args) error {
+			return client.deployCredentials(ctx)
+		}},
+		{"Reissuing internal certificates from the new CA", func(ctx context.Context, client *Client, m maintain.Args) error {
+			return client.reissueInternalCerts()
+		}},
+		{"Deploying the reissued certificates (create-env and deploy)", func(ctx context.Context, client *Client, m maintain.Args) error {
+			return client.deployCredentials(ctx)
+		}},
+		{"Removing old CA", func(ctx context.Context, client *Client, m maintain.Args) error {
+			return client.removeOldInternalCA()
+		}},
+		{"Deploying without the old CA (create-env and deploy)", func(ctx context.Context, client *Client, m maintain.Args) error {
+			return client.deployCredentials(ctx)
+		}},
+	},
+}
+
+// generateDirectorCerts replaces the director's certificate with one from a new CA, and trusts both the old and
+// new CAs until the director is serving the new certificate
+func (client *Client) generateDirectorCerts(ctx context.Context) error {
+	conf, err := client.configClient.Load()
+	if err != nil {
+		return err
+	}
+	// Already generated by an earlier attempt at this stage
+	if len(certs.SplitCertificates(conf.DirectorCACert)) > 1 {
+		return nil
+	}
+
+	tfOutputs, err := client.tfCLI.BuildOutput(ctx, client.tfInputVarsFactory.NewInputVars(conf))
+	if err != nil {
+		return err
+	}
+	dc, err := client.ensureDirectorCerts(ctx, client.acmeClientConstructor, DirectorCerts{}, conf.GetDeployment(), tfOutputs, conf.GetPublicCIDR())
+	if err != nil {
+		return err
+	}
+	if dc.DirectorCACert == "" {
+		return fmt.Errorf("failed to generate a new director certificate")
+	}
+
+	conf.DirectorCACert = certs.BundleCertificates(conf.DirectorCACert, dc.DirectorCACert)
+	conf.DirectorCert = dc.DirectorCert
+	conf.DirectorKey = dc.DirectorKey
+	return client.configClient.Update(conf)
+}
+
+// removeOldDirectorCA stops trusting the director CA that was replaced by generateDirectorCerts
+func (client *Client) removeOldDirectorCA() error {
+	conf, err := client.configClient.Load()
+	if err != nil {
+		return err
+	}
+	cas := certs.SplitCertificates(conf.DirectorCACert)
+	if len(cas) < 2 {
+		return nil
+	}
+
+	conf.DirectorCACert = cas[len(cas)-1]
+	return client.configClient.Update(conf)
+}
+
+// addNewInternalCA generates a replacement for the internal CA and adds it to the CAs trusted by the certificates it signed
+func (client *Client) addNewInternalCA() error {
+	return client.updateVarsStore(func(vars map[string]interface{}) error {
+		oldCA, err := certificateVar(vars, internalCAVar)
+		if err != nil {
+			return err
+		}
+
+		newCA, err := certificateVar(vars, newInternalCAVar)
+		if err != nil {
+			certPEM, keyPEM, err := certs.RenewCA(oldCA["certificate"])
+			if err != nil {
+				return fmt.Errorf("error generating a new [%v]: [%v]", internalCAVar, err)
+			}
+			newCA = map[string]string{"ca": string(certPEM), "certificate": string(certPEM), "private_key": string(keyPEM)}
+			vars[newInternalCAVar] = newCA
+		}
+
+		bundle := certs.BundleCertificates(oldCA["certificate"], newCA["certificate"])
+		for _, name := range internalCertVars(vars, oldCA["certificate"]) {
+			cert, _ := certificateVar(vars, name)
+			cert["ca"] = bundle
+			vars[name] = cert
+		}
+		return nil
+	})
+}
+
+// reissueInternalCerts replaces each certificate signed by the old internal CA with one signed by its replacement
+func (client *Client) reissueInternalCerts() error {
+	return client.updateVarsStore(func(vars map[string]interface{}) error {
+		oldCA, err := certificateVar(vars, internalCAVar)
+		if err != nil {
+			return err
+		}
+		newCA, err := certificateVar(vars, newInternalCAVar)
+		if err != nil {
+			return err
+		}
+
+		bundle := certs.BundleCertificates(oldCA["certificate"], newCA["certificate"])
+		for _, name := range internalCertVars(vars, bundle) {
+			cert, _ := certificateVar(vars, name)
+			certPEM, keyPEM, err := certs.Reissue(cert["certificate"], newCA["certificate"], newCA["private_key"])
+			if err != nil {
+				return fmt.Errorf("error reissuing [%v]: [%v]", name, err)
+			}
+			cert["certificate"] = string(certPEM)
+			cert["private_key"] = string(keyPEM)
+			vars[name] = cert
+		}
+		return nil
+	})
+}
+
+// removeOldInternalCA replaces the internal CA with its replacement and stops trusting the old one
+func (client *Client) removeOldInternalCA() error {
+	return client.updateVarsStore(func(vars map[string]interface{}) error {
+		newCA, err := certificateVar(vars, newInternalCAVar)
+		if err != nil {
+			// Already removed by an earlier attempt at this stage
+			return nil
+		}
+		oldCA, err := certificateVar(vars, internalCAVar)
+		if err != nil {
+			return err
+		}
+
+		bundle := certs.BundleCertificates(oldCA["certificate"], newCA["certificate"])
+		for _, name := range internalCertVars(vars, bundle) {
+			cert, _ := certificateVar(vars, name)
+			cert["ca"] = newCA["certificate"]
+			vars[name] = cert
+		}
+		vars[internalCAVar] = newCA
+		delete(vars, newInternalCAVar)
+		return nil
+	})
+}
+
+// updateVarsStore applies update to the variables in director-creds.yml and stores the result
+func (client *Client) updateVarsStore(update func(vars map[string]interface{}) error) error {
+	credsBytes, err := loadDirectorCreds(client.configClient)
+	if err != nil {
+		return err
+	}
+	vars := map[string]interface{}{}
+	if err = yaml.Unmarshal(credsBytes, &vars); err != nil {
+		return fmt.Errorf("error reading [%v]: [%v]", bosh.CredsFilename, err)
+	}
+
+	if err = update(vars); err != nil {
+		return err
+	}
+
+	credsBytes, err = yaml.Marshal(vars)
+	if err != nil {
+		return err
+	}
+	return client.configClient.StoreAsset(bosh.CredsFilename, credsBytes)
+}
+
+// certificateVar returns the fields of the certificate variable name
+func certificateVar(vars map[string]interface{}, name string) (map[string]string, error) {
+	cert := map[string]string{}
+	switch value := vars[name].(type) {
+	case map[interface{}]interface{}:
+		for k, v := range value {
+			key, _ := k.(string)
+			cert[key], _ = v.(string)
+		}
+	case map[string]string:
+		for k, v := range value {
+			cert[k] = v
+		}
+	default:
+		return nil, fmt.Errorf("certificate [%v] not found in [%v]", name, bosh.CredsFilename)
+	}
+	if cert["certificate"] == "" {
+		return nil, fmt.Errorf("[%v] in [%v] is not a certificate", name, bosh.CredsFilename)
+	}
+	return cert, nil
+}
+
+// internalCertVars returns the names of the certificate variables, other than the CAs themselves, that trust exactly ca
+func internalCertVars(vars map[string]interface{}, ca string) []string {
+	names := []string{}
+	for name := range vars {
+		if name == internalCAVar || name == newInternalCAVar {
+			continue
+		}
+		cert, err := certificateVar(vars, name)
+		if err != nil {
+			continue
+		}
+		if strings.TrimSpace(cert["ca"]) == strings.TrimSpace(ca) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
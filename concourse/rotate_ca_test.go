@@ -0,0 +1,170 @@
+package concourse
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/EngineerBetter/control-tower/bosh"
+	"github.com/EngineerBetter/control-tower/certs"
+	"github.com/EngineerBetter/control-tower/config"
+	"github.com/EngineerBetter/control-tower/config/configfakes"
+	"gopkg.in/yaml.v2"
+)
+
+// generateTestCert returns a PEM encoded certificate and key named commonName, self-signed if parent is nil
+func generateTestCert(t *testing.T, commonName string, parent *x509.Certificate, parentKey *rsa.PrivateKey) (string, string, *x509.Certificate, *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+		DNSNames:              []string{commonName},
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		template.KeyUsage = x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return string(certPEM), string(keyPEM), cert, key
+}
+
+func loadVars(t *testing.T, assets map[string][]byte) map[string]map[string]string {
+	raw := map[string]interface{}{}
+	if err := yaml.Unmarshal(assets[bosh.CredsFilename], &raw); err != nil {
+		t.Fatalf("%s is not valid: %v", bosh.CredsFilename, err)
+	}
+	vars := map[string]map[string]string{}
+	for name, value := range raw {
+		if cert, err := certificateVar(raw, name); err == nil {
+			vars[name] = cert
+		} else {
+			vars[name] = map[string]string{"value": value.(string)}
+		}
+	}
+	return vars
+}
+
+func verifiedBy(leaf, ca string) bool {
+	cert, err := certs.ParseCertificate(leaf)
+	if err != nil {
+		return false
+	}
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM([]byte(ca))
+	_, err = cert.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}})
+	return err == nil
+}
+
+func TestRotateInternalCA(t *testing.T) {
+	caPEM, caKeyPEM, ca, caKey := generateTestCert(t, "ca", nil, nil)
+	tlsPEM, tlsKeyPEM, _, _ := generateTestCert(t, "internal", ca, caKey)
+	natsPEM, natsKeyPEM, _, _ := generateTestCert(t, "nats", nil, nil)
+
+	creds, err := yaml.Marshal(map[string]interface{}{
+		"ca":              map[string]string{"ca": caPEM, "certificate": caPEM, "private_key": caKeyPEM},
+		"internal_tls":    map[string]string{"ca": caPEM, "certificate": tlsPEM, "private_key": tlsKeyPEM},
+		"nats_server_tls": map[string]string{"ca": natsPEM, "certificate": natsPEM, "private_key": natsKeyPEM},
+		"atc_password":    "password",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assets := map[string][]byte{bosh.CredsFilename: creds}
+	client, _ := newMaintenanceClient(assets)
+
+	if err = client.addNewInternalCA(); err != nil {
+		t.Fatalf("addNewInternalCA() error = %v", err)
+	}
+	vars := loadVars(t, assets)
+	newCA := vars[newInternalCAVar]["certificate"]
+	if newCA == "" || newCA == caPEM {
+		t.Fatalf("addNewInternalCA() did not generate %s", newInternalCAVar)
+	}
+	if vars["internal_tls"]["ca"] != certs.BundleCertificates(caPEM, newCA) {
+		t.Errorf("internal_tls does not trust both CAs")
+	}
+	if vars["internal_tls"]["certificate"] != tlsPEM || vars["nats_server_tls"]["ca"] != natsPEM {
+		t.Errorf("addNewInternalCA() changed certificates it should not have")
+	}
+
+	// Retrying the stage keeps the CA it already generated
+	if err = client.addNewInternalCA(); err != nil {
+		t.Fatalf("addNewInternalCA() error = %v", err)
+	}
+	if loadVars(t, assets)[newInternalCAVar]["certificate"] != newCA {
+		t.Errorf("retrying addNewInternalCA() generated another CA")
+	}
+
+	if err = client.reissueInternalCerts(); err != nil {
+		t.Fatalf("reissueInternalCerts() error = %v", err)
+	}
+	vars = loadVars(t, assets)
+	if !verifiedBy(vars["internal_tls"]["certificate"], newCA) {
+		t.Errorf("internal_tls was not reissued by the new CA")
+	}
+	if vars["nats_server_tls"]["certificate"] != natsPEM {
+		t.Errorf("reissueInternalCerts() reissued a certificate from another CA")
+	}
+
+	if err = client.removeOldInternalCA(); err != nil {
+		t.Fatalf("removeOldInternalCA() error = %v", err)
+	}
+	vars = loadVars(t, assets)
+	if _, ok := vars[newInternalCAVar]; ok {
+		t.Errorf("%s was not removed", newInternalCAVar)
+	}
+	if vars[internalCAVar]["certificate"] != newCA || vars["internal_tls"]["ca"] != newCA {
+		t.Errorf("the old CA is still trusted")
+	}
+	if vars["atc_password"]["value"] != "password" {
+		t.Errorf("removeOldInternalCA() changed atc_password to %v", vars["atc_password"])
+	}
+
+	// Retrying the final stage is a no-op
+	if err = client.removeOldInternalCA(); err != nil {
+		t.Fatalf("removeOldInternalCA() error = %v", err)
+	}
+}
+
+func TestRemoveOldDirectorCA(t *testing.T) {
+	oldCA, _, _, _ := generateTestCert(t, "old", nil, nil)
+	newCA, _, _, _ := generateTestCert(t, "new", nil, nil)
+
+	client, _ := newMaintenanceClient(map[string][]byte{})
+	configClient := client.configClient.(*configfakes.FakeIClient)
+	configClient.LoadReturns(config.Config{DirectorCACert: certs.BundleCertificates(oldCA, newCA)}, nil)
+
+	if err := client.removeOldDirectorCA(); err != nil {
+		t.Fatalf("removeOldDirectorCA() error = %v", err)
+	}
+	if got := configClient.UpdateArgsForCall(0).DirectorCACert; got != newCA {
+		t.Errorf("DirectorCACert = %s, want %s", got, newCA)
+	}
+
+	configClient.LoadReturns(config.Config{DirectorCACert: newCA}, nil)
+	if err := client.removeOldDirectorCA(); err != nil {
+		t.Fatalf("removeOldDirectorCA() error = %v", err)
+	}
+	if configClient.UpdateCallCount() != 1 {
+		t.Errorf("removeOldDirectorCA() updated a config with a single CA")
+	}
+}
@@ -0,0 +1,179 @@
+package concourse
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/EngineerBetter/control-tower/bosh"
+	"github.com/EngineerBetter/control-tower/commands/maintain"
+	"github.com/EngineerBetter/control-tower/util/yaml"
+)
+
+const credentialRotationFilename = "credential-rotation.json"
+
+const rotatedPasswordLength = 20
+
+// rotatedCredsVars are the director-creds.yml variables that BOSH regenerates for each component
+var rotatedCredsVars = map[string][]string{
+	"concourse": {"atc_password"},
+	"credhub":   {"credhub_cli_password", "credhub_admin_client_secret"},
+	"director":  {"hm_password", "mbus_bootstrap_password", "nats_password", "registry_password"},
+}
+
+// RotatedCredentials are the credentials generated by credential rotation. They are staged in the stage file
+// by generateCredentials and then stored in config.json and director-creds.yml by storeCredentials, which
+// can be repeated until both have been written.
+type RotatedCredentials struct {
+	Components               []string `json:"components"`
+	DirectorPassword         string   `json:"director_password,omitempty"`
+	DirectorMbusPassword     string   `json:"director_mbus_password,omitempty"`
+	DirectorNATSPassword     string   `json:"director_nats_password,omitempty"`
+	DirectorRegistryPassword string   `json:"director_registry_password,omitempty"`
+	RDSPassword              string   `json:"rds_password,omitempty"`
+}
+
+var credentialRotation = MaintenanceOperation{
+	Name:          maintain.RotateCredentialsOperation,
+	Description:   "Regenerate the passwords and secrets used by the deployment",
+	stageFilename: credentialRotationFilename,
+	Steps: []MaintenanceStep{
+		{"Generating new credentials", func(ctx context.Context, client *Client, m maintain.Args) error {
+			return client.generateCredentials(m.CredentialComponents())
+		}},
+		{"Storing new credentials", func(ctx context.Context, client *Client, m maintain.Args) error {
+			return client.storeCredentials()
+		}},
+		{"Applying new database password (terraform apply)", func(ctx context.Context, client *Client, m maintain.Args) error {
+			return client.applyDatabasePassword(ctx)
+		}},
+		{"Deploying new credentials (create-env and deploy)", func(ctx context.Context, client *Client, m maintain.Args) error {
+			return client.deployCredentials(ctx)
+		}},
+	},
+}
+
+// generateCredentials generates new secrets for each of components and stages them in the stage file,
+// without changing the deployment's config
+func (client *Client) generateCredentials(components []string) error {
+	maintenance, err := client.retrieveStage(credentialRotationFilename)
+	if err != nil {
+		return err
+	}
+
+	creds := &RotatedCredentials{Components: components}
+	for _, component := range components {
+		switch component {
+		case "director":
+			creds.DirectorPassword = client.passwordGenerator(rotatedPasswordLength)
+			creds.DirectorMbusPassword = client.passwordGenerator(rotatedPasswordLength)
+			creds.DirectorNATSPassword = client.passwordGenerator(rotatedPasswordLength)
+			creds.DirectorRegistryPassword = client.passwordGenerator(rotatedPasswordLength)
+		case "rds":
+			creds.RDSPassword = client.passwordGenerator(rotatedPasswordLength)
+		}
+	}
+
+	maintenance.Credentials = creds
+	return client.updateStage(credentialRotationFilename, maintenance.StatusIndex, maintenance)
+}
+
+// storeCredentials replaces the stored secrets of each staged component so that the following stages roll
+// them out. Both files are written from the staged values, so if either write fails the stage can be rerun.
+func (client *Client) storeCredentials() error {
+	maintenance, err := client.retrieveStage(credentialRotationFilename)
+	if err != nil {
+		return err
+	}
+	creds := maintenance.Credentials
+	if creds == nil {
+		return fmt.Errorf("no new credentials have been generated, rerun %s from stage 0", maintain.RotateCredentialsOperation)
+	}
+
+	conf, err := client.configClient.Load()
+	if err != nil {
+		return err
+	}
+	directorCredsBytes, err := loadDirectorCreds(client.configClient)
+	if err != nil {
+		return err
+	}
+
+	var ops strings.Builder
+	for _, component := range creds.Components {
+		switch component {
+		case "concourse":
+			conf.ConcoursePassword = ""
+			conf.GrafanaPassword = ""
+		case "director":
+			conf.DirectorPassword = creds.DirectorPassword
+			conf.DirectorMbusPassword = creds.DirectorMbusPassword
+			conf.DirectorNATSPassword = creds.DirectorNATSPassword
+			conf.DirectorRegistryPassword = creds.DirectorRegistryPassword
+		case "rds":
+			conf.RDSPassword = creds.RDSPassword
+		}
+		for _, name := range rotatedCredsVars[component] {
+			fmt.Fprintf(&ops, "- type: remove\n  path: /%s?\n", name)
+		}
+	}
+
+	if ops.Len() > 0 && directorCredsBytes != nil {
+		rotatedCreds, err := yaml.Interpolate(string(directorCredsBytes), ops.String(), nil)
+		if err != nil {
+			return fmt.Errorf("error removing credentials from %s: [%v]", bosh.CredsFilename, err)
+		}
+		err = client.configClient.StoreAsset(bosh.CredsFilename, []byte(rotatedCreds))
+		if err != nil {
+			return err
+		}
+	}
+
+	return client.configClient.Update(conf)
+}
+
+// applyDatabasePassword sets the new RDS password on the database instance
+func (client *Client) applyDatabasePassword(ctx context.Context) error {
+	conf, err := client.configClient.Load()
+	if err != nil {
+		return err
+	}
+
+	conf.SourceAccessIP, err = client.setUserIP(conf)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.tfCLI.Apply(ctx, client.tfInputVarsFactory.NewInputVars(conf))
+	if err != nil {
+		return err
+	}
+
+	return client.configClient.Update(conf)
+}
+
+// deployCredentials redeploys the director and concourse with the new credentials and certificates
+func (client *Client) deployCredentials(ctx context.Context) error {
+	conf, err := client.configClient.Load()
+	if err != nil {
+		return err
+	}
+
+	tfOutputs, err := client.tfCLI.BuildOutput(ctx, client.tfInputVarsFactory.NewInputVars(conf))
+	if err != nil {
+		return err
+	}
+
+	bp, err := client.deployBosh(ctx, conf, tfOutputs, false, nil)
+	if err != nil {
+		return err
+	}
+
+	conf.CredhubPassword = bp.CredhubPassword
+	conf.CredhubAdminClientSecret = bp.CredhubAdminClientSecret
+	conf.CredhubCACert = bp.CredhubCACert
+	conf.ConcoursePassword = bp.ConcoursePassword
+	conf.GrafanaPassword = bp.GrafanaPassword
+
+	return client.configClient.Update(conf)
+}
@@ -0,0 +1,74 @@
+package concourse
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/EngineerBetter/control-tower/bosh"
+	"github.com/EngineerBetter/control-tower/config"
+	"github.com/EngineerBetter/control-tower/config/configfakes"
+)
+
+func TestGenerateCredentials(t *testing.T) {
+	assets := map[string][]byte{
+		bosh.CredsFilename: []byte("atc_password: old-atc\ncredhub_cli_password: old-credhub\nhm_password: old-hm\nnats_password: nats\nmbus_bootstrap_password: mbus\n"),
+	}
+	client, _ := newMaintenanceClient(assets)
+	client.passwordGenerator = func(int) string { return "new-password" }
+	configClient := client.configClient.(*configfakes.FakeIClient)
+	configClient.LoadReturns(config.Config{
+		ConcoursePassword:    "old-atc",
+		GrafanaPassword:      "old-atc",
+		DirectorPassword:     "old-director",
+		DirectorMbusPassword: "old-mbus",
+		RDSPassword:          "old-rds",
+	}, nil)
+
+	err := client.generateCredentials([]string{"concourse", "director"})
+	if err != nil {
+		t.Fatalf("generateCredentials() error = %v", err)
+	}
+
+	if configClient.UpdateCallCount() != 0 || !strings.Contains(string(assets[bosh.CredsFilename]), "old-hm") {
+		t.Fatalf("expected generating credentials to only stage them")
+	}
+	staged := storedMaintenance(t, assets, credentialRotationFilename).Credentials
+	if staged == nil || staged.DirectorPassword != "new-password" || staged.DirectorNATSPassword != "new-password" {
+		t.Fatalf("staged credentials = %+v, want new director passwords", staged)
+	}
+
+	err = client.storeCredentials()
+	if err != nil {
+		t.Fatalf("storeCredentials() error = %v", err)
+	}
+
+	creds := string(assets[bosh.CredsFilename])
+	for _, removed := range []string{"atc_password", "hm_password", "nats_password", "mbus_bootstrap_password"} {
+		if strings.Contains(creds, removed) {
+			t.Errorf("expected %s to be removed from creds, got %s", removed, creds)
+		}
+	}
+	if !strings.Contains(creds, "credhub_cli_password") {
+		t.Errorf("expected credhub_cli_password to be kept in creds, got %s", creds)
+	}
+
+	conf := configClient.UpdateArgsForCall(0)
+	if conf.ConcoursePassword != "" || conf.GrafanaPassword != "" {
+		t.Errorf("expected concourse passwords to be cleared, got %q and %q", conf.ConcoursePassword, conf.GrafanaPassword)
+	}
+	if conf.DirectorPassword != "new-password" || conf.DirectorMbusPassword != "new-password" {
+		t.Errorf("director passwords = %q and %q, want new-password", conf.DirectorPassword, conf.DirectorMbusPassword)
+	}
+	if conf.RDSPassword != "old-rds" {
+		t.Errorf("RDSPassword = %q, want old-rds", conf.RDSPassword)
+	}
+}
+
+func TestStoreCredentialsWithoutStagedCredentials(t *testing.T) {
+	client, _ := newMaintenanceClient(map[string][]byte{})
+
+	err := client.storeCredentials()
+	if err == nil || !strings.Contains(err.Error(), "no new credentials have been generated") {
+		t.Errorf("storeCredentials() error = %v, want no new credentials have been generated", err)
+	}
+}
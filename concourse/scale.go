@@ -0,0 +1,121 @@
+package concourse
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/EngineerBetter/control-tower/bosh"
+	"github.com/EngineerBetter/control-tower/commands/scale"
+	"github.com/EngineerBetter/control-tower/config"
+)
+
+// retireTimeout is how long scale waits for retired workers to finish their running builds
+const retireTimeout = time.Hour
+
+// Scale changes the number and size of workers with a targeted deploy of the concourse manifest,
+// skipping the infrastructure and director steps of a full deploy
+func (client *Client) Scale(ctx context.Context, args scale.Args) error {
+	return client.withLock(ctx, "scale", func(ctx context.Context) error {
+		return client.scale(ctx, args)
+	})
+}
+
+// scale does the work of Scale, and must be called while holding the lock
+func (client *Client) scale(ctx context.Context, args scale.Args) error {
+	conf, err := client.configClient.Load()
+	if err != nil {
+		return fmt.Errorf("error loading config before scale: [%v]", err)
+	}
+
+	// Workers that BOSH deletes, or recreates with a new VM type, are retired first so that their running builds can
+	// finish. keepWorkers is how many of them, lowest index first, BOSH leaves alone
+	var retire bool
+	var keepWorkers int
+	if args.PoolIsSet {
+		pool, err := findWorkerPool(conf.WorkerPools, args.Pool)
+		if err != nil {
+			return err
+		}
+		retire, keepWorkers = args.WorkerCount < pool.Count, args.WorkerCount
+		pool.Count = args.WorkerCount
+	} else {
+		if args.WorkerCountIsSet && args.WorkerCount < conf.ConcourseWorkerCount {
+			retire, keepWorkers = true, args.WorkerCount
+		}
+		if args.WorkerSizeIsSet && args.WorkerSize != conf.ConcourseWorkerSize {
+			retire, keepWorkers = true, 0
+		}
+		if args.WorkerCountIsSet {
+			conf.ConcourseWorkerCount = args.WorkerCount
+		}
+		if args.WorkerSizeIsSet {
+			conf.ConcourseWorkerSize = args.WorkerSize
+		}
+	}
+
+	tfOutputs, err := client.tfCLI.BuildOutput(ctx, client.tfInputVarsFactory.NewInputVars(conf))
+	if err != nil {
+		return err
+	}
+
+	boshClient, err := client.buildBoshClient(conf, tfOutputs)
+	if err != nil {
+		return err
+	}
+	defer boshClient.Cleanup()
+
+	if retire {
+		if err = client.retireWorkers(ctx, conf, boshClient, args.Pool, keepWorkers); err != nil {
+			return err
+		}
+	}
+
+	boshCredsBytes, err := loadDirectorCreds(client.configClient)
+	if err != nil {
+		return err
+	}
+
+	boshCredsBytes, err = boshClient.ScaleWorkers(ctx, boshCredsBytes)
+	err1 := client.configClient.StoreAsset(bosh.CredsFilename, boshCredsBytes)
+	if err == nil {
+		err = err1
+	}
+	if err != nil {
+		return err
+	}
+
+	return client.configClient.Update(conf)
+}
+
+// findWorkerPool returns the worker pool called name, so that it can be changed in place
+func findWorkerPool(pools []config.WorkerPool, name string) (*config.WorkerPool, error) {
+	names := []string{}
+	for i := range pools {
+		if pools[i].Name == name {
+			return &pools[i], nil
+		}
+		names = append(names, pools[i].Name)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("unknown worker pool [%v], this deployment has no worker pools", name)
+	}
+	return nil, fmt.Errorf("unknown worker pool [%v], can be any of %s", name, strings.Join(names, ", "))
+}
+
+// retireWorkers retires the workers of pool, or the default workers when pool is empty, that BOSH will delete
+// or recreate, which are those from index workerCount up, and waits for them to finish their running builds and leave Concourse, so that the deploy does not delete
+// workers that are still running builds
+func (client *Client) retireWorkers(ctx context.Context, conf config.Config, boshClient bosh.IClient, pool string, workerCount int) error {
+	err := boshClient.RetireWorkers(ctx, pool, workerCount)
+	if err != nil {
+		return err
+	}
+
+	atc, err := client.atcClientFactory(conf)
+	if err != nil {
+		return err
+	}
+	return waitForLeavingWorkers(ctx, atc.leavingWorkers, retireTimeout)
+}
@@ -0,0 +1,245 @@
+package concourse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/EngineerBetter/control-tower/bosh"
+	"github.com/EngineerBetter/control-tower/bosh/boshfakes"
+	"github.com/EngineerBetter/control-tower/commands/scale"
+	"github.com/EngineerBetter/control-tower/config"
+	"github.com/EngineerBetter/control-tower/config/configfakes"
+	"github.com/EngineerBetter/control-tower/iaas"
+	"github.com/EngineerBetter/control-tower/terraform"
+	"github.com/EngineerBetter/control-tower/terraform/terraformfakes"
+)
+
+type stubInputVarsFactory struct{}
+
+func (stubInputVarsFactory) NewInputVars(config.ConfigView) terraform.InputVars {
+	return nil
+}
+
+func newScaleClient(configClient *configfakes.FakeIClient, boshClient *boshfakes.FakeIClient, deployedConfig *config.ConfigView) *Client {
+	configClient.AcquireLockReturns(context.Background(), func() error { return nil }, nil)
+	return &Client{
+		configClient:       configClient,
+		tfCLI:              &terraformfakes.FakeCLIInterface{},
+		tfInputVarsFactory: stubInputVarsFactory{},
+		boshClientFactory: func(c config.ConfigView, _ terraform.Outputs, _, _ io.Writer, _ iaas.Provider, _ []byte) (bosh.IClient, error) {
+			*deployedConfig = c
+			return boshClient, nil
+		},
+	}
+}
+
+// newWorkersServer serves workers from the Concourse API with no pending builds
+func newWorkersServer(workers string) *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/workers":
+			fmt.Fprint(w, workers)
+		case "/api/v1/builds":
+			fmt.Fprint(w, `[]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestScale(t *testing.T) {
+	configClient := &configfakes.FakeIClient{}
+	configClient.LoadReturns(config.Config{ConcourseWorkerCount: 3, ConcourseWorkerSize: "xlarge"}, nil)
+	configClient.HasAssetReturns(true, nil)
+	configClient.LoadAssetReturns([]byte("old creds"), nil)
+	boshClient := &boshfakes.FakeIClient{}
+	boshClient.ScaleWorkersReturns([]byte("new creds"), nil)
+	var deployedConfig config.ConfigView
+
+	server := newWorkersServer(`[{"name":"a","state":"running"}]`)
+	defer server.Close()
+
+	client := newScaleClient(configClient, boshClient, &deployedConfig)
+	client.atcClientFactory = func(config.Config) (*atcClient, error) {
+		return &atcClient{url: server.URL, httpClient: server.Client()}, nil
+	}
+	err := client.Scale(context.Background(), scale.Args{WorkerCount: 1, WorkerCountIsSet: true})
+	if err != nil {
+		t.Fatalf("Scale() error = %v", err)
+	}
+
+	if _, command := configClient.AcquireLockArgsForCall(0); command != "scale" {
+		t.Errorf("locked the deployment for %q, want scale", command)
+	}
+	if configClient.UpgradeConfigCallCount() != 1 {
+		t.Errorf("UpgradeConfig() called %d times under the lock, want 1", configClient.UpgradeConfigCallCount())
+	}
+	if boshClient.RetireWorkersCallCount() != 1 {
+		t.Fatalf("RetireWorkers() called %d times, want 1", boshClient.RetireWorkersCallCount())
+	}
+	if _, pool, workerCount := boshClient.RetireWorkersArgsForCall(0); pool != "" || workerCount != 1 {
+		t.Errorf("RetireWorkers() called with %q and %d, want the default workers and 1", pool, workerCount)
+	}
+
+	if deployedConfig.GetConcourseWorkerCount() != 1 || deployedConfig.GetConcourseWorkerSize() != "xlarge" {
+		t.Errorf("deployed %d %s workers, want 1 xlarge", deployedConfig.GetConcourseWorkerCount(), deployedConfig.GetConcourseWorkerSize())
+	}
+	if _, scaledCreds := boshClient.ScaleWorkersArgsForCall(0); string(scaledCreds) != "old creds" {
+		t.Errorf("ScaleWorkers() called with %s, want old creds", scaledCreds)
+	}
+	name, creds := configClient.StoreAssetArgsForCall(0)
+	if name != bosh.CredsFilename || string(creds) != "new creds" {
+		t.Errorf("stored %s as %s, want new creds as %s", creds, name, bosh.CredsFilename)
+	}
+	if configClient.UpdateArgsForCall(0).ConcourseWorkerCount != 1 {
+		t.Errorf("stored worker count %d, want 1", configClient.UpdateArgsForCall(0).ConcourseWorkerCount)
+	}
+}
+
+func TestScaleDeployFailure(t *testing.T) {
+	configClient := &configfakes.FakeIClient{}
+	configClient.HasAssetReturns(true, nil)
+	boshClient := &boshfakes.FakeIClient{}
+	boshClient.ScaleWorkersReturns([]byte("new creds"), errors.New("deploy failed"))
+	var deployedConfig config.ConfigView
+
+	client := newScaleClient(configClient, boshClient, &deployedConfig)
+	err := client.Scale(context.Background(), scale.Args{WorkerCount: 3, WorkerCountIsSet: true})
+	if err == nil || err.Error() != "deploy failed" {
+		t.Fatalf("Scale() error = %v, want deploy failed", err)
+	}
+
+	if configClient.StoreAssetCallCount() != 1 {
+		t.Errorf("expected creds to be stored after a failed deploy")
+	}
+	if configClient.UpdateCallCount() != 0 {
+		t.Errorf("expected config not to be updated after a failed deploy")
+	}
+}
+
+func TestScaleUpDoesNotRetireWorkers(t *testing.T) {
+	configClient := &configfakes.FakeIClient{}
+	configClient.LoadReturns(config.Config{ConcourseWorkerCount: 1}, nil)
+	configClient.HasAssetReturns(true, nil)
+	boshClient := &boshfakes.FakeIClient{}
+	var deployedConfig config.ConfigView
+
+	client := newScaleClient(configClient, boshClient, &deployedConfig)
+	err := client.Scale(context.Background(), scale.Args{WorkerCount: 3, WorkerCountIsSet: true})
+	if err != nil {
+		t.Fatalf("Scale() error = %v", err)
+	}
+
+	if boshClient.RetireWorkersCallCount() != 0 {
+		t.Errorf("expected no workers to be retired when scaling up")
+	}
+}
+
+func TestScaleWorkerSizeRetiresEveryWorker(t *testing.T) {
+	configClient := &configfakes.FakeIClient{}
+	configClient.LoadReturns(config.Config{ConcourseWorkerCount: 3, ConcourseWorkerSize: "xlarge"}, nil)
+	configClient.HasAssetReturns(true, nil)
+	boshClient := &boshfakes.FakeIClient{}
+	var deployedConfig config.ConfigView
+	server := newWorkersServer(`[{"name":"a","state":"running"}]`)
+	defer server.Close()
+
+	client := newScaleClient(configClient, boshClient, &deployedConfig)
+	client.atcClientFactory = func(config.Config) (*atcClient, error) {
+		return &atcClient{url: server.URL, httpClient: server.Client()}, nil
+	}
+	err := client.Scale(context.Background(), scale.Args{WorkerCount: 4, WorkerCountIsSet: true, WorkerSize: "large", WorkerSizeIsSet: true})
+	if err != nil {
+		t.Fatalf("Scale() error = %v", err)
+	}
+
+	// BOSH recreates every worker with the new VM type
+	if boshClient.RetireWorkersCallCount() != 1 {
+		t.Fatalf("RetireWorkers() called %d times, want 1", boshClient.RetireWorkersCallCount())
+	}
+	if _, pool, workerCount := boshClient.RetireWorkersArgsForCall(0); pool != "" || workerCount != 0 {
+		t.Errorf("RetireWorkers() called with %q and %d, want the default workers and 0", pool, workerCount)
+	}
+	if deployedConfig.GetConcourseWorkerCount() != 4 || deployedConfig.GetConcourseWorkerSize() != "large" {
+		t.Errorf("deployed %d %s workers, want 4 large", deployedConfig.GetConcourseWorkerCount(), deployedConfig.GetConcourseWorkerSize())
+	}
+
+	// An unchanged size recreates nothing
+	boshClient.RetireWorkersReturns(nil)
+	err = client.Scale(context.Background(), scale.Args{WorkerSize: "xlarge", WorkerSizeIsSet: true})
+	if err != nil {
+		t.Fatalf("Scale() error = %v", err)
+	}
+	if boshClient.RetireWorkersCallCount() != 1 {
+		t.Errorf("expected no workers to be retired when the size is unchanged")
+	}
+}
+
+func TestScaleRetireFailure(t *testing.T) {
+	configClient := &configfakes.FakeIClient{}
+	configClient.LoadReturns(config.Config{ConcourseWorkerCount: 3}, nil)
+	boshClient := &boshfakes.FakeIClient{}
+	boshClient.RetireWorkersReturns(errors.New("retire failed"))
+	var deployedConfig config.ConfigView
+
+	client := newScaleClient(configClient, boshClient, &deployedConfig)
+	err := client.Scale(context.Background(), scale.Args{WorkerCount: 1, WorkerCountIsSet: true})
+	if err == nil || err.Error() != "retire failed" {
+		t.Fatalf("Scale() error = %v, want retire failed", err)
+	}
+
+	if boshClient.ScaleWorkersCallCount() != 0 {
+		t.Errorf("expected no deploy after workers failed to retire")
+	}
+}
+
+func TestScalePool(t *testing.T) {
+	configClient := &configfakes.FakeIClient{}
+	configClient.LoadReturns(config.Config{
+		ConcourseWorkerCount: 3,
+		WorkerPools:          []config.WorkerPool{{Name: "gpu", Count: 4, Tags: []string{"gpu"}}, {Name: "ml", Count: 1, Team: "ml"}},
+	}, nil)
+	configClient.HasAssetReturns(true, nil)
+	boshClient := &boshfakes.FakeIClient{}
+	var deployedConfig config.ConfigView
+	server := newWorkersServer(`[{"name":"a","state":"running","tags":["gpu"]}]`)
+	defer server.Close()
+
+	client := newScaleClient(configClient, boshClient, &deployedConfig)
+	client.atcClientFactory = func(config.Config) (*atcClient, error) {
+		return &atcClient{url: server.URL, httpClient: server.Client()}, nil
+	}
+	err := client.Scale(context.Background(), scale.Args{WorkerCount: 2, WorkerCountIsSet: true, Pool: "gpu", PoolIsSet: true})
+	if err != nil {
+		t.Fatalf("Scale() error = %v", err)
+	}
+
+	if _, pool, workerCount := boshClient.RetireWorkersArgsForCall(0); pool != "gpu" || workerCount != 2 {
+		t.Errorf("RetireWorkers() called with %q and %d, want gpu and 2", pool, workerCount)
+	}
+	pools := deployedConfig.GetWorkerPools()
+	if deployedConfig.GetConcourseWorkerCount() != 3 || pools[0].Count != 2 || pools[1].Count != 1 {
+		t.Errorf("deployed %d default workers and pools %+v, want 3 default workers and 2 gpu workers", deployedConfig.GetConcourseWorkerCount(), pools)
+	}
+}
+
+func TestScaleUnknownPool(t *testing.T) {
+	configClient := &configfakes.FakeIClient{}
+	configClient.LoadReturns(config.Config{WorkerPools: []config.WorkerPool{{Name: "gpu", Count: 1}}}, nil)
+	boshClient := &boshfakes.FakeIClient{}
+	var deployedConfig config.ConfigView
+
+	client := newScaleClient(configClient, boshClient, &deployedConfig)
+	err := client.Scale(context.Background(), scale.Args{WorkerCount: 2, WorkerCountIsSet: true, Pool: "ml", PoolIsSet: true})
+	if err == nil || err.Error() != "unknown worker pool [ml], can be any of gpu" {
+		t.Fatalf("Scale() error = %v, want unknown worker pool [ml], can be any of gpu", err)
+	}
+	if boshClient.ScaleWorkersCallCount() != 0 {
+		t.Errorf("expected no deploy when the pool is unknown")
+	}
+}
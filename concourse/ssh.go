@@ -0,0 +1,27 @@
+package concourse
+
+import (
+	"context"
+	"os"
+)
+
+// SSH opens a session on target through the director gateway, running command if it is not empty
+func (client *Client) SSH(ctx context.Context, target, command string) error {
+	conf, err := client.configClient.Load()
+	if err != nil {
+		return err
+	}
+
+	tfOutputs, err := client.tfCLI.BuildOutput(ctx, client.tfInputVarsFactory.NewInputVars(conf))
+	if err != nil {
+		return err
+	}
+
+	boshClient, err := client.buildBoshClient(conf, tfOutputs)
+	if err != nil {
+		return err
+	}
+	defer boshClient.Cleanup()
+
+	return boshClient.SSH(ctx, target, command, os.Stdin)
+}
@@ -2,9 +2,11 @@ package concourse
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/EngineerBetter/control-tower/config"
 	"github.com/EngineerBetter/control-tower/iaas"
+	"github.com/EngineerBetter/control-tower/store"
 	"github.com/EngineerBetter/control-tower/terraform"
 )
 
@@ -13,9 +15,12 @@ type TFInputVarsFactory interface {
 	NewInputVars(conf config.ConfigView) terraform.InputVars
 }
 
-func NewTFInputVarsFactory(provider iaas.Provider) (TFInputVarsFactory, error) {
+// NewTFInputVarsFactory returns a factory for the IAAS of provider which keeps terraform state in stateStore
+func NewTFInputVarsFactory(provider iaas.Provider, stateStore store.Store) (TFInputVarsFactory, error) {
 	if provider.IAAS() == iaas.AWS {
-		return &AWSInputVarsFactory{}, nil
+		return &AWSInputVarsFactory{
+			stateStore: stateStore,
+		}, nil
 	} else if provider.IAAS() == iaas.GCP {
 		credentialsPath, err := provider.Attr("credentials_path")
 		if err != nil {
@@ -32,13 +37,39 @@ func NewTFInputVarsFactory(provider iaas.Provider) (TFInputVarsFactory, error) {
 			project:         project,
 			region:          provider.Region(),
 			zone:            provider.Zone("", ""),
+			stateStore:      stateStore,
+		}, nil
+	} else if provider.IAAS() == iaas.Azure {
+		attrs := map[string]string{}
+		for _, attr := range []string{"subscription_id", "tenant_id", "client_id", "client_secret", "storage_account", "storage_resource_group"} {
+			value, err := provider.Attr(attr)
+			if err != nil {
+				return &AzureInputVarsFactory{}, fmt.Errorf("Error finding attribute [%s]: [%v]", attr, err)
+			}
+			attrs[attr] = value
+		}
+
+		return &AzureInputVarsFactory{
+			attrs:      attrs,
+			region:     provider.Region(),
+			stateStore: stateStore,
 		}, nil
 	}
 
 	return nil, fmt.Errorf("IAAS not supported [%s]", provider.IAAS())
 }
 
-type AWSInputVarsFactory struct{}
+// terraformBackend returns the backend block for the terraform state of c, or an empty string for the IAAS default
+func terraformBackend(stateStore store.Store, c config.ConfigView) string {
+	if stateStore == nil {
+		return ""
+	}
+	return stateStore.TerraformBackend(c.GetConfigBucket(), c.GetTFStatePath())
+}
+
+type AWSInputVarsFactory struct {
+	stateStore store.Store
+}
 
 func (f *AWSInputVarsFactory) NewInputVars(c config.ConfigView) terraform.InputVars {
 	return &terraform.AWSInputVars{
@@ -47,10 +78,12 @@ func (f *AWSInputVarsFactory) NewInputVars(c config.ConfigView) terraform.InputV
 		PrivateCIDR:            c.GetPrivateCIDR(),
 		AllowIPs:               c.GetAllowIPs(),
 		AvailabilityZone:       c.GetAvailabilityZone(),
+		Backend:                terraformBackend(f.stateStore, c),
 		ConfigBucket:           c.GetConfigBucket(),
 		Deployment:             c.GetDeployment(),
 		HostedZoneID:           c.GetHostedZoneID(),
 		HostedZoneRecordPrefix: c.GetHostedZoneRecordPrefix(),
+		LockTable:              iaas.TerraformLockTable(c.GetConfigBucket()),
 		Namespace:              c.GetNamespace(),
 		Project:                c.GetProject(),
 		PublicKey:              c.GetPublicKey(),
@@ -71,11 +104,13 @@ type GCPInputVarsFactory struct {
 	project         string
 	region          string
 	zone            string
+	stateStore      store.Store
 }
 
 func (f *GCPInputVarsFactory) NewInputVars(c config.ConfigView) terraform.InputVars {
 	return &terraform.GCPInputVars{
 		AllowIPs:           c.GetAllowIPs(),
+		Backend:            terraformBackend(f.stateStore, c),
 		ConfigBucket:       c.GetConfigBucket(),
 		DBName:             c.GetRDSDefaultDatabaseName(),
 		DBPassword:         c.GetRDSPassword(),
@@ -95,3 +130,57 @@ func (f *GCPInputVarsFactory) NewInputVars(c config.ConfigView) terraform.InputV
 		PrivateCIDR:        c.GetPrivateCIDR(),
 	}
 }
+
+type AzureInputVarsFactory struct {
+	attrs      map[string]string
+	region     string
+	stateStore store.Store
+}
+
+func (f *AzureInputVarsFactory) NewInputVars(c config.ConfigView) terraform.InputVars {
+	var dnsZoneName, dnsZoneResourceGroup string
+	if c.GetHostedZoneID() != "" {
+		dnsZoneName, dnsZoneResourceGroup = azureDNSZone(c.GetHostedZoneID())
+	}
+
+	return &terraform.AzureInputVars{
+		AllowIPs:             c.GetAllowIPs(),
+		Backend:              terraformBackend(f.stateStore, c),
+		ClientID:             f.attrs["client_id"],
+		ClientSecret:         f.attrs["client_secret"],
+		ConfigBucket:         c.GetConfigBucket(),
+		DBName:               c.GetRDSDefaultDatabaseName(),
+		DBPassword:           c.GetRDSPassword(),
+		DBSKU:                c.GetRDSInstanceClass(),
+		DBUsername:           c.GetRDSUsername(),
+		Deployment:           c.GetDeployment(),
+		DNSRecordSetPrefix:   c.GetHostedZoneRecordPrefix(),
+		DNSZoneName:          dnsZoneName,
+		DNSZoneResourceGroup: dnsZoneResourceGroup,
+		ExternalIP:           c.GetSourceAccessIP(),
+		Namespace:            c.GetNamespace(),
+		PrivateCIDR:          c.GetPrivateCIDR(),
+		Project:              c.GetProject(),
+		PublicCIDR:           c.GetPublicCIDR(),
+		Region:               f.region,
+		StorageAccount:       f.attrs["storage_account"],
+		StorageResourceGroup: f.attrs["storage_resource_group"],
+		SubscriptionID:       f.attrs["subscription_id"],
+		TenantID:             f.attrs["tenant_id"],
+	}
+}
+
+// azureDNSZone returns the name and resource group of the DNS zone with the given resource ID, which
+// looks like /subscriptions/<id>/resourceGroups/<group>/providers/Microsoft.Network/dnszones/<name>
+func azureDNSZone(zoneID string) (name, resourceGroup string) {
+	parts := strings.Split(strings.Trim(zoneID, "/"), "/")
+	for i := 0; i+1 < len(parts); i++ {
+		switch strings.ToLower(parts[i]) {
+		case "resourcegroups":
+			resourceGroup = parts[i+1]
+		case "dnszones":
+			name = parts[i+1]
+		}
+	}
+	return name, resourceGroup
+}
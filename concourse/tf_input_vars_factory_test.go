@@ -0,0 +1,100 @@
+package concourse
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/EngineerBetter/control-tower/config"
+	"github.com/EngineerBetter/control-tower/iaas"
+	"github.com/EngineerBetter/control-tower/iaas/iaasfakes"
+	"github.com/EngineerBetter/control-tower/resource"
+	"github.com/EngineerBetter/control-tower/store"
+	"github.com/EngineerBetter/control-tower/terraform"
+)
+
+func TestAzureInputVarsFactory(t *testing.T) {
+	provider := &iaasfakes.FakeProvider{}
+	provider.IAASReturns(iaas.Azure)
+	provider.RegionReturns("westeurope")
+	provider.AttrStub = func(attr string) (string, error) {
+		return attr + "-value", nil
+	}
+
+	factory, err := NewTFInputVarsFactory(provider, store.FromIAAS(provider))
+	if err != nil {
+		t.Fatalf("NewTFInputVarsFactory() error = %v", err)
+	}
+
+	inputVars, ok := factory.NewInputVars(config.Config{
+		ConfigBucket:           "bucket",
+		HostedZoneID:           "/subscriptions/sub/resourceGroups/dns-group/providers/Microsoft.Network/dnszones/example.com",
+		HostedZoneRecordPrefix: "ci",
+	}).(*terraform.AzureInputVars)
+	if !ok {
+		t.Fatalf("NewInputVars() did not return AzureInputVars")
+	}
+
+	if inputVars.DNSZoneName != "example.com" || inputVars.DNSZoneResourceGroup != "dns-group" {
+		t.Errorf("DNS zone = %s in %s, want example.com in dns-group", inputVars.DNSZoneName, inputVars.DNSZoneResourceGroup)
+	}
+	if inputVars.StorageAccount != "storage_account-value" || inputVars.ClientSecret != "client_secret-value" {
+		t.Errorf("credentials not taken from provider attributes: %+v", inputVars)
+	}
+	if inputVars.Region != "westeurope" || inputVars.ConfigBucket != "bucket" {
+		t.Errorf("Region = %s, ConfigBucket = %s", inputVars.Region, inputVars.ConfigBucket)
+	}
+}
+
+func TestAzureInputVarsFactory_NoHostedZone(t *testing.T) {
+	factory := &AzureInputVarsFactory{}
+	inputVars := factory.NewInputVars(config.Config{}).(*terraform.AzureInputVars)
+	if inputVars.DNSZoneName != "" || inputVars.DNSZoneResourceGroup != "" {
+		t.Errorf("expected no DNS zone, got %s in %s", inputVars.DNSZoneName, inputVars.DNSZoneResourceGroup)
+	}
+}
+
+func TestNewTFInputVarsFactory_StateBackend(t *testing.T) {
+	provider := &iaasfakes.FakeProvider{}
+	provider.IAASReturns(iaas.AWS)
+	provider.RegionReturns("eu-west-1")
+
+	conf := config.Config{ConfigBucket: "bucket", TFStatePath: "terraform.tfstate", Region: "eu-west-1"}
+
+	factory, err := NewTFInputVarsFactory(provider, store.FromIAAS(provider))
+	if err != nil {
+		t.Fatalf("NewTFInputVarsFactory() error = %v", err)
+	}
+	rendered, err := factory.NewInputVars(conf).ConfigureTerraform(resource.AWSTerraformConfig)
+	if err != nil {
+		t.Fatalf("ConfigureTerraform() error = %v", err)
+	}
+	if !strings.Contains(rendered, `backend "s3"`) {
+		t.Errorf("expected the IAAS store to keep the s3 backend")
+	}
+	if !strings.Contains(rendered, `dynamodb_table = "bucket-terraform-lock"`) {
+		t.Errorf("expected the s3 backend to lock state, got:\n%s", rendered[:200])
+	}
+
+	rendered, err = terraform.WithLocalBackend(factory.NewInputVars(conf)).ConfigureTerraform(resource.AWSTerraformConfig)
+	if err != nil {
+		t.Fatalf("ConfigureTerraform() error = %v", err)
+	}
+	if !strings.Contains(rendered, `backend "local" {}`) || strings.Contains(rendered, `backend "s3"`) {
+		t.Errorf("expected WithLocalBackend to replace the s3 backend, got:\n%s", rendered[:200])
+	}
+
+	factory, err = NewTFInputVarsFactory(provider, store.NewLocal("/state"))
+	if err != nil {
+		t.Fatalf("NewTFInputVarsFactory() error = %v", err)
+	}
+	rendered, err = factory.NewInputVars(conf).ConfigureTerraform(resource.AWSTerraformConfig)
+	if err != nil {
+		t.Fatalf("ConfigureTerraform() error = %v", err)
+	}
+	if !strings.Contains(rendered, `backend "local"`) || strings.Contains(rendered, `backend "s3"`) {
+		t.Errorf("expected the local store to replace the s3 backend, got:\n%s", rendered[:200])
+	}
+	if !strings.Contains(rendered, `path = "/state/bucket/terraform.tfstate"`) {
+		t.Errorf("expected state to be kept in the local bucket, got:\n%s", rendered[:200])
+	}
+}
@@ -1,10 +1,13 @@
 package config
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
+	"github.com/EngineerBetter/control-tower/encryption"
 	"github.com/EngineerBetter/control-tower/iaas"
+	"github.com/EngineerBetter/control-tower/store"
 )
 
 const terraformStateFileName = "terraform.tfstate"
@@ -16,57 +19,189 @@ type IClient interface {
 	DeleteAll(config ConfigView) error
 	Update(Config) error
 	StoreAsset(filename string, contents []byte) error
+	StoreEncryptedAsset(filename string, contents []byte) error
 	HasAsset(filename string) (bool, error)
 	ConfigExists() (bool, error)
 	LoadAsset(filename string) ([]byte, error)
 	NewConfig() Config
 	EnsureBucketExists() error
+	ConfigBucketExists() (bool, error)
+	EncryptAssets(filenames []string) ([]string, error)
+	AssetVersions(filename string) ([]iaas.FileVersion, error)
+	LoadAssetVersion(filename, versionID string) ([]byte, error)
+	RestoreAssetVersion(filename, versionID string) error
+	DeleteOldAssetVersions(filename string) (int, error)
+	AcquireLock(ctx context.Context, command string) (context.Context, func() error, error)
+	BreakLock(force bool) (Lock, error)
+	UpgradeConfig() error
 }
 
-// Client is a client for loading the config file  from S3
+// Client is a client for loading the config file from the state store
 type Client struct {
 	Iaas         iaas.Provider
+	Store        store.Store
 	Project      string
 	Namespace    string
 	BucketName   string
 	BucketExists bool
 	BucketError  error
+	// KeyProvider encrypts files before they are stored, which are otherwise stored in plaintext
+	KeyProvider encryption.KeyProvider
 }
 
-// New instantiates a new client
-func New(iaas iaas.Provider, project, namespace string) *Client {
+// New instantiates a new client which keeps its files in stateStore
+func New(iaas iaas.Provider, stateStore store.Store, project, namespace string) *Client {
 	namespace = determineNamespace(namespace, iaas.Region())
-	bucketName, exists, err := determineBucketName(iaas, namespace, project)
+	bucketName, exists, err := determineBucketName(stateStore, iaas.Region(), namespace, project)
 
 	return &Client{
 		iaas,
+		stateStore,
 		project,
 		namespace,
 		bucketName,
 		exists,
 		err,
+		nil,
 	}
 }
 
 // StoreAsset stores an associated configuration file
 func (client *Client) StoreAsset(filename string, contents []byte) error {
-	return client.Iaas.WriteFile(client.configBucket(),
+	sealed, err := client.seal(contents)
+	if err != nil {
+		return fmt.Errorf("error encrypting [%v]: [%v]", filename, err)
+	}
+
+	return client.Store.WriteFile(client.configBucket(),
 		filename,
-		contents,
+		sealed,
 	)
 }
 
+// StoreEncryptedAsset stores an associated file which must not be kept in plaintext, so fails rather than storing
+// it when no key provider is configured
+func (client *Client) StoreEncryptedAsset(filename string, contents []byte) error {
+	if client.KeyProvider == nil {
+		return fmt.Errorf("no encryption key provider configured, use --encryption")
+	}
+	return client.StoreAsset(filename, contents)
+}
+
 // LoadAsset loads an associated configuration file
 func (client *Client) LoadAsset(filename string) ([]byte, error) {
-	return client.Iaas.LoadFile(
+	contents, err := client.Store.LoadFile(
 		client.configBucket(),
 		filename,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.open(filename, contents)
+}
+
+// AssetVersions returns every stored version of an associated configuration file, newest first
+func (client *Client) AssetVersions(filename string) ([]iaas.FileVersion, error) {
+	return client.Store.ListFileVersions(
+		client.configBucket(),
+		filename,
+	)
+}
+
+// LoadAssetVersion loads and decrypts a previous version of an associated configuration file
+func (client *Client) LoadAssetVersion(filename, versionID string) ([]byte, error) {
+	contents, err := client.Store.LoadFileVersion(
+		client.configBucket(),
+		filename,
+		versionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.open(filename, contents)
+}
+
+// DeleteOldAssetVersions permanently deletes every version of an associated configuration file other than the
+// current one, returning how many it deleted. It deletes nothing where previous versions are not kept.
+func (client *Client) DeleteOldAssetVersions(filename string) (int, error) {
+	versions, err := client.Store.ListFileVersions(client.configBucket(), filename)
+	if err == iaas.ErrVersioningUnsupported {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error listing versions of [%v]: [%v]", filename, err)
+	}
+
+	deleted := 0
+	for _, version := range versions {
+		if version.Current {
+			continue
+		}
+		if err = client.Store.DeleteFileVersion(client.configBucket(), filename, version.ID); err != nil {
+			return deleted, fmt.Errorf("error deleting version [%v] of [%v]: [%v]", version.ID, filename, err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// currentFileVersions returns the ID of the current version of each of RevisionFiles that exists,
+// or nil where previous versions are not kept
+func (client *Client) currentFileVersions() (map[string]string, error) {
+	fileVersions := map[string]string{}
+	for _, filename := range RevisionFiles {
+		versions, err := client.Store.ListFileVersions(client.configBucket(), filename)
+		if err == iaas.ErrVersioningUnsupported {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error listing versions of [%v]: [%v]", filename, err)
+		}
+		for _, version := range versions {
+			if version.Current {
+				fileVersions[filename] = version.ID
+			}
+		}
+	}
+	return fileVersions, nil
+}
+
+// RestoreAssetVersion makes a previous version of an associated configuration file the current one. The version is
+// decrypted and stored again, so it is encrypted with the key the bucket uses now rather than however it was stored.
+func (client *Client) RestoreAssetVersion(filename, versionID string) error {
+	// Loading the current file picks up the key the bucket uses now, for when no key provider is configured
+	exists, err := client.HasAsset(filename)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if _, err = client.LoadAsset(filename); err != nil {
+			return fmt.Errorf("error loading [%v]: [%v]", filename, err)
+		}
+	}
+
+	contents, err := client.LoadAssetVersion(filename, versionID)
+	if err != nil {
+		return fmt.Errorf("error loading version [%v] of [%v]: [%v]", versionID, filename, err)
+	}
+
+	// Storing the config with Update records the versions of the files restored alongside it
+	if filename == configFilePath {
+		conf, _, err := migrateConfig(contents)
+		if err != nil {
+			return fmt.Errorf("error reading version [%v] of [%v]: [%v]", versionID, filename, err)
+		}
+		return client.Update(conf)
+	}
+
+	return client.StoreAsset(filename, contents)
 }
 
 // HasAsset returns true if an associated configuration file exists
 func (client *Client) HasAsset(filename string) (bool, error) {
-	return client.Iaas.HasFile(
+	return client.Store.HasFile(
 		client.configBucket(),
 		filename,
 	)
@@ -77,74 +212,227 @@ func (client *Client) ConfigExists() (bool, error) {
 	return client.HasAsset(configFilePath)
 }
 
-// Update stores the control-tower config file to S3
+// Update stores the control-tower config file in the state store, recording the current version of each of
+// RevisionFiles so that history knows which versions of them belong with this version of the config
 func (client *Client) Update(config Config) error {
+	config.SchemaVersion = CurrentSchemaVersion()
+	fileVersions, err := client.currentFileVersions()
+	if err != nil {
+		return err
+	}
+	config.FileVersions = fileVersions
+
 	bytes, err := json.Marshal(config)
 	if err != nil {
 		return err
 	}
 
-	return client.Iaas.WriteFile(client.configBucket(), configFilePath, bytes)
+	return client.StoreAsset(configFilePath, bytes)
 }
 
-// DeleteAll deletes the entire configuration bucket
+// DeleteAll deletes the terraform state lock, then the entire configuration bucket. The lock goes first
+// so that an interrupted destroy, which is run again while the bucket still exists, does not leave it behind
 func (client *Client) DeleteAll(config ConfigView) error {
-	return client.Iaas.DeleteVersionedBucket(config.GetConfigBucket())
+	if err := client.Store.DeleteTerraformLock(config.GetConfigBucket()); err != nil {
+		return err
+	}
+	return client.Store.DeleteVersionedBucket(config.GetConfigBucket())
 }
 
-// Load loads an existing config file from S3
+// Load loads an existing config file from the state store. Configs written with an older schema are
+// upgraded in memory only, so that commands which just read the config never write to the bucket.
 func (client *Client) Load() (Config, error) {
 	if client.BucketError != nil {
 		return Config{}, client.BucketError
 	}
 
-	configBytes, err := client.Iaas.LoadFile(
-		client.configBucket(),
-		configFilePath,
-	)
+	configBytes, err := client.LoadAsset(configFilePath)
 	if err != nil {
 		return Config{}, err
 	}
 
-	conf := Config{}
-	if err := json.Unmarshal(configBytes, &conf); err != nil {
-		return Config{}, err
+	conf, _, err := migrateConfig(configBytes)
+	return conf, err
+}
+
+// UpgradeConfig stores a config file written with an older schema upgraded to the current one, keeping a
+// backup of the original alongside. It does nothing if there is no config file or it is already current.
+// Only call it while holding the lock, as it is a read followed by a write.
+func (client *Client) UpgradeConfig() error {
+	if client.BucketError != nil {
+		return client.BucketError
 	}
 
-	conf = populateMandatoryFieldsAddedSinceLastSave(conf)
+	exists, err := client.ConfigExists()
+	if err != nil || !exists {
+		return err
+	}
+
+	configBytes, err := client.LoadAsset(configFilePath)
+	if err != nil {
+		return err
+	}
 
-	return conf, nil
+	conf, version, err := migrateConfig(configBytes)
+	if err != nil {
+		return err
+	}
+	if version == CurrentSchemaVersion() {
+		return nil
+	}
+
+	if err = client.StoreAsset(ConfigBackupFilename(version), configBytes); err != nil {
+		return fmt.Errorf("error backing up config before upgrading it from schema version %d: [%v]", version, err)
+	}
+	if err = client.Update(conf); err != nil {
+		return fmt.Errorf("error storing config upgraded from schema version %d: [%v]", version, err)
+	}
+	return nil
 }
 
 func (client *Client) NewConfig() Config {
 	return Config{
-		ConfigBucket: client.configBucket(),
-		Deployment:   deployment(client.Project),
-		Namespace:    client.Namespace,
-		Project:      client.Project,
-		Region:       client.Iaas.Region(),
-		TFStatePath:  terraformStateFileName,
+		ConfigBucket:  client.configBucket(),
+		Deployment:    deployment(client.Project),
+		Namespace:     client.Namespace,
+		Project:       client.Project,
+		Region:        client.Iaas.Region(),
+		SchemaVersion: CurrentSchemaVersion(),
+		TFStatePath:   terraformStateFileName,
 	}
 }
 
 func (client *Client) EnsureBucketExists() error {
-	exists, err := client.Iaas.BucketExists(client.BucketName)
+	exists, err := client.Store.BucketExists(client.BucketName)
 
 	if err != nil {
 		return fmt.Errorf("error determining if bucket [%v] exists: [%v]", client.BucketName, err)
 	}
 
 	if !exists {
-		err = client.Iaas.CreateBucket(client.BucketName)
+		err = client.Store.CreateBucket(client.BucketName)
 
 		if err != nil {
 			return fmt.Errorf("error creating config bucket [%v]: [%v]", client.BucketName, err)
 		}
 	}
 
+	return client.EnsureTerraformLock()
+}
+
+// EnsureTerraformLock creates anything terraform needs to lock the state kept in the config bucket. Deployments
+// made before the state was locked do not have it, so it is called before every terraform init
+func (client *Client) EnsureTerraformLock() error {
+	if client.BucketError != nil {
+		return client.BucketError
+	}
+
+	err := client.Store.EnsureTerraformLock(client.BucketName)
+	if err != nil {
+		return fmt.Errorf("error creating terraform state lock for [%v]: [%v]", client.BucketName, err)
+	}
+
 	return nil
 }
 
+// ConfigBucketExists returns true if the config bucket has been created, without creating it
+func (client *Client) ConfigBucketExists() (bool, error) {
+	return client.Store.BucketExists(client.BucketName)
+}
+
+// EncryptAssets encrypts each of the named files that exist with KeyProvider, re-encrypting any that were encrypted with
+// another key, and returns the names of the files it rewrote. config.json is rewritten last, through Update, so that the
+// file versions it records are the newly encrypted ones rather than those about to become earlier versions
+func (client *Client) EncryptAssets(filenames []string) ([]string, error) {
+	if client.KeyProvider == nil {
+		return nil, fmt.Errorf("no encryption key provider configured, use --encryption")
+	}
+
+	encrypted := []string{}
+	encryptConfig := false
+	for _, filename := range filenames {
+		if filename == configFilePath {
+			encryptConfig = true
+			continue
+		}
+
+		exists, err := client.HasAsset(filename)
+		if err != nil {
+			return encrypted, err
+		}
+		if !exists {
+			continue
+		}
+
+		contents, err := client.LoadAsset(filename)
+		if err != nil {
+			return encrypted, fmt.Errorf("error loading [%v]: [%v]", filename, err)
+		}
+
+		if err = client.StoreAsset(filename, contents); err != nil {
+			return encrypted, err
+		}
+		encrypted = append(encrypted, filename)
+	}
+
+	if !encryptConfig {
+		return encrypted, nil
+	}
+	exists, err := client.HasAsset(configFilePath)
+	if err != nil || !exists {
+		return encrypted, err
+	}
+	conf, err := client.Load()
+	if err != nil {
+		return encrypted, fmt.Errorf("error loading [%v]: [%v]", configFilePath, err)
+	}
+	if err = client.Update(conf); err != nil {
+		return encrypted, err
+	}
+	return append(encrypted, configFilePath), nil
+}
+
+// seal encrypts contents when a KeyProvider is configured
+func (client *Client) seal(contents []byte) ([]byte, error) {
+	if client.KeyProvider == nil {
+		return contents, nil
+	}
+	return encryption.Encrypt(client.KeyProvider, contents)
+}
+
+// open decrypts contents if they were encrypted, and returns plaintext contents unchanged
+func (client *Client) open(filename string, contents []byte) ([]byte, error) {
+	if !encryption.IsEncrypted(contents) {
+		return contents, nil
+	}
+
+	plaintext, keyProvider, err := encryption.Decrypt(contents, client.resolveKey)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting [%v]: [%v]", filename, err)
+	}
+
+	// Keep encrypting with the key the bucket already uses, so that a run without
+	// --encryption never writes secrets back in plaintext
+	if client.KeyProvider == nil {
+		client.KeyProvider = keyProvider
+	}
+
+	return plaintext, nil
+}
+
+// resolveKey returns the KeyProvider for an envelope, reusing the configured one where it matches
+func (client *Client) resolveKey(name, keyID string) (encryption.KeyProvider, error) {
+	if kp := client.KeyProvider; kp != nil && kp.Name() == name && (kp.KeyID() == keyID || name == encryption.Passphrase) {
+		return kp, nil
+	}
+
+	region := ""
+	if client.Iaas != nil {
+		region = client.Iaas.Region()
+	}
+	return encryption.New(name, keyID, region)
+}
+
 func (client *Client) configBucket() string {
 	return client.BucketName
 }
@@ -157,14 +445,14 @@ func createBucketName(deployment, extension string) string {
 	return fmt.Sprintf("%s-%s-config", deployment, extension)
 }
 
-func determineBucketName(iaas iaas.Provider, namespace, project string) (string, bool, error) {
-	regionBucketName := createBucketName(deployment(project), iaas.Region())
+func determineBucketName(stateStore store.Store, region, namespace, project string) (string, bool, error) {
+	regionBucketName := createBucketName(deployment(project), region)
 	namespaceBucketName := createBucketName(deployment(project), namespace)
 
-	foundRegionNamedBucket, err := iaas.BucketExists(regionBucketName)
+	foundRegionNamedBucket, err := stateStore.BucketExists(regionBucketName)
 	var foundNamespacedBucket bool
 	if err != nil {
-		foundNamespacedBucket, err = iaas.BucketExists(namespaceBucketName)
+		foundNamespacedBucket, err = stateStore.BucketExists(namespaceBucketName)
 		if err != nil {
 			return "", false, err
 		}
@@ -188,12 +476,3 @@ func determineNamespace(namespace, region string) string {
 	}
 	return namespace
 }
-
-// Allow new mandatory fields to be populated based on old fields that are now deprecated
-func populateMandatoryFieldsAddedSinceLastSave(oldConf Config) Config {
-	if oldConf.VMProvisioningType == "" {
-		oldConf.VMProvisioningType = ConvertSpotBoolToVMProvisioningType(oldConf.Spot)
-	}
-
-	return oldConf
-}
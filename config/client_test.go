@@ -3,13 +3,17 @@ package config_test
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"reflect"
 	"testing"
 
 	"github.com/EngineerBetter/control-tower/iaas/iaasfakes"
 
 	. "github.com/EngineerBetter/control-tower/config"
+	"github.com/EngineerBetter/control-tower/encryption"
 	"github.com/EngineerBetter/control-tower/iaas"
+	"github.com/EngineerBetter/control-tower/store"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -26,7 +30,7 @@ var _ = Describe("Client", func() {
 			return defaultContents, true, nil
 		}
 
-		client = New(provider, "test", "")
+		client = New(provider, store.FromIAAS(provider), "test", "")
 	})
 
 	Describe("NewConfig", func() {
@@ -41,11 +45,31 @@ var _ = Describe("Client", func() {
 		})
 	})
 
+	Describe("with a state store separate from the IAAS", func() {
+		var stateStore *iaasfakes.FakeProvider
+
+		BeforeEach(func() {
+			provider = &iaasfakes.FakeProvider{}
+			provider.RegionReturns("eu-west-1")
+			stateStore = &iaasfakes.FakeProvider{}
+			stateStore.BucketExistsReturns(true, nil)
+			client = New(provider, store.FromIAAS(stateStore), "test", "")
+		})
+
+		It("keeps files in the store", func() {
+			Expect(client.BucketExists).To(BeTrue())
+			Expect(client.StoreAsset("director-state.json", []byte("{}"))).To(Succeed())
+			Expect(stateStore.WriteFileCallCount()).To(Equal(1))
+			Expect(provider.WriteFileCallCount()).To(Equal(0))
+			Expect(provider.BucketExistsCallCount()).To(Equal(0))
+		})
+	})
+
 	Describe("EnsureBucketExists", func() {
 		BeforeEach(func() {
 			provider = &iaasfakes.FakeProvider{}
 			provider.RegionReturns("eu-west-1")
-			client = New(provider, "test", "")
+			client = New(provider, store.FromIAAS(provider), "test", "")
 		})
 
 		Context("when the bucket exists", func() {
@@ -72,6 +96,13 @@ var _ = Describe("Client", func() {
 				Expect(provider.CreateBucketCallCount()).To(Equal(1))
 			})
 
+			It("creates the terraform state lock", func() {
+				err := client.EnsureBucketExists()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(provider.EnsureTerraformLockCallCount()).To(Equal(1))
+				Expect(provider.EnsureTerraformLockArgsForCall(0)).To(Equal("control-tower-test-eu-west-1-config"))
+			})
+
 			Context("and it cannot be created", func() {
 				JustBeforeEach(func() {
 					provider.BucketExistsReturns(false, nil)
@@ -86,6 +117,43 @@ var _ = Describe("Client", func() {
 			})
 		})
 	})
+
+	Describe("EnsureTerraformLock", func() {
+		It("creates the terraform state lock of the config bucket", func() {
+			Expect(client.EnsureTerraformLock()).To(Succeed())
+			Expect(provider.EnsureTerraformLockCallCount()).To(Equal(1))
+			Expect(provider.EnsureTerraformLockArgsForCall(0)).To(Equal("control-tower-test-eu-west-1-config"))
+		})
+
+		It("returns a useful error message", func() {
+			provider.EnsureTerraformLockReturns(fmt.Errorf("SOME IAAS ERROR"))
+			err := client.EnsureTerraformLock()
+			Expect(err).To(MatchError("error creating terraform state lock for [control-tower-test-eu-west-1-config]: [SOME IAAS ERROR]"))
+		})
+	})
+
+	Describe("DeleteAll", func() {
+		It("deletes the terraform state lock before the bucket", func() {
+			var deleted []string
+			provider.DeleteTerraformLockStub = func(bucket string) error {
+				deleted = append(deleted, "lock "+bucket)
+				return nil
+			}
+			provider.DeleteVersionedBucketStub = func(bucket string) error {
+				deleted = append(deleted, "bucket "+bucket)
+				return nil
+			}
+
+			Expect(client.DeleteAll(Config{ConfigBucket: "some-bucket"})).To(Succeed())
+			Expect(deleted).To(Equal([]string{"lock some-bucket", "bucket some-bucket"}))
+		})
+
+		It("keeps the bucket when the lock cannot be deleted", func() {
+			provider.DeleteTerraformLockReturns(fmt.Errorf("SOME IAAS ERROR"))
+			Expect(client.DeleteAll(Config{ConfigBucket: "some-bucket"})).To(MatchError("SOME IAAS ERROR"))
+			Expect(provider.DeleteVersionedBucketCallCount()).To(Equal(0))
+		})
+	})
 })
 
 func TestNew(t *testing.T) {
@@ -117,6 +185,7 @@ func TestNew(t *testing.T) {
 			},
 			want: &Client{
 				Iaas:         provider,
+				Store:        store.FromIAAS(provider),
 				Project:      "aProject",
 				Namespace:    "eu-west-1",
 				BucketName:   "control-tower-aProject-eu-west-1-config",
@@ -136,6 +205,7 @@ func TestNew(t *testing.T) {
 			},
 			want: &Client{
 				Iaas:         provider,
+				Store:        store.FromIAAS(provider),
 				Project:      "aProject",
 				Namespace:    "someNamespace",
 				BucketName:   "control-tower-aProject-someNamespace-config",
@@ -155,6 +225,7 @@ func TestNew(t *testing.T) {
 			},
 			want: &Client{
 				Iaas:         provider,
+				Store:        store.FromIAAS(provider),
 				Project:      "aProject",
 				Namespace:    "someNamespace",
 				BucketName:   "control-tower-aProject-eu-west-1-config",
@@ -177,6 +248,7 @@ func TestNew(t *testing.T) {
 			},
 			want: &Client{
 				Iaas:         provider,
+				Store:        store.FromIAAS(provider),
 				Project:      "aProject",
 				Namespace:    "someNamespace",
 				BucketName:   "control-tower-aProject-someNamespace-config",
@@ -199,6 +271,7 @@ func TestNew(t *testing.T) {
 			},
 			want: &Client{
 				Iaas:         provider,
+				Store:        store.FromIAAS(provider),
 				Project:      "aProject",
 				Namespace:    "eu-west-1",
 				BucketName:   "control-tower-aProject-eu-west-1-config",
@@ -213,7 +286,7 @@ func TestNew(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			provider.BucketExistsStub = tt.FakeBucketExists
-			if got := New(tt.args.iaas, tt.args.project, tt.args.namespace); !reflect.DeepEqual(got, tt.want) {
+			if got := New(tt.args.iaas, store.FromIAAS(tt.args.iaas), tt.args.project, tt.args.namespace); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("New() = %v,\n want %v", got, tt.want)
 			}
 		})
@@ -244,6 +317,7 @@ func TestClient_Load(t *testing.T) {
 			prepare: func() *Client {
 				return &Client{
 					Iaas:         provider,
+					Store:        store.FromIAAS(provider),
 					Project:      "",
 					Namespace:    "",
 					BucketName:   "",
@@ -259,6 +333,7 @@ func TestClient_Load(t *testing.T) {
 			prepare: func() *Client {
 				return &Client{
 					Iaas:         provider,
+					Store:        store.FromIAAS(provider),
 					Project:      "",
 					Namespace:    "",
 					BucketName:   "",
@@ -267,6 +342,7 @@ func TestClient_Load(t *testing.T) {
 				}
 			},
 			want: Config{
+				SchemaVersion:      CurrentSchemaVersion(),
 				Spot:               true,
 				VMProvisioningType: SPOT,
 			},
@@ -312,6 +388,7 @@ func TestClient_HasConfig(t *testing.T) {
 			prepare: func() *Client {
 				return &Client{
 					Iaas:         provider,
+					Store:        store.FromIAAS(provider),
 					Project:      "",
 					Namespace:    "",
 					BucketName:   "",
@@ -327,6 +404,7 @@ func TestClient_HasConfig(t *testing.T) {
 			prepare: func() *Client {
 				return &Client{
 					Iaas:         provider,
+					Store:        store.FromIAAS(provider),
 					Project:      "",
 					Namespace:    "",
 					BucketName:   "",
@@ -335,6 +413,7 @@ func TestClient_HasConfig(t *testing.T) {
 				}
 			},
 			want: Config{
+				SchemaVersion:      CurrentSchemaVersion(),
 				VMProvisioningType: ON_DEMAND,
 			},
 			wantErr: false,
@@ -354,3 +433,220 @@ func TestClient_HasConfig(t *testing.T) {
 		})
 	}
 }
+
+var _ = Describe("Encryption", func() {
+	var dir string
+	var stateStore *store.LocalStore
+	var provider *iaasfakes.FakeProvider
+	var keyProvider encryption.KeyProvider
+	var client *Client
+
+	const bucket = "control-tower-test-eu-west-1-config"
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "config-encryption")
+		Expect(err).ToNot(HaveOccurred())
+		stateStore = store.NewLocal(dir)
+		Expect(stateStore.CreateBucket(bucket)).To(Succeed())
+
+		provider = &iaasfakes.FakeProvider{}
+		provider.RegionReturns("eu-west-1")
+
+		keyProvider, err = encryption.NewPassphrase("passphrase")
+		Expect(err).ToNot(HaveOccurred())
+
+		client = New(provider, stateStore, "test", "")
+		client.KeyProvider = keyProvider
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("encrypts assets in the store and decrypts them on load", func() {
+		Expect(client.StoreAsset("director-creds.yml", []byte("password: secret"))).To(Succeed())
+
+		stored, err := stateStore.LoadFile(bucket, "director-creds.yml")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(encryption.IsEncrypted(stored)).To(BeTrue())
+		Expect(string(stored)).ToNot(ContainSubstring("secret"))
+
+		contents, err := client.LoadAsset("director-creds.yml")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(contents)).To(Equal("password: secret"))
+	})
+
+	It("loads assets stored in plaintext", func() {
+		Expect(stateStore.WriteFile(bucket, "director-creds.yml", []byte("password: secret"))).To(Succeed())
+
+		contents, err := client.LoadAsset("director-creds.yml")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(contents)).To(Equal("password: secret"))
+	})
+
+	It("keeps encrypting with the key the bucket uses when no key provider is configured", func() {
+		Expect(client.StoreAsset("config.json", []byte("{}"))).To(Succeed())
+
+		os.Setenv("ENCRYPTION_PASSPHRASE", "passphrase")
+		defer os.Unsetenv("ENCRYPTION_PASSPHRASE")
+
+		unconfigured := New(provider, stateStore, "test", "")
+		contents, err := unconfigured.LoadAsset("config.json")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(contents)).To(Equal("{}"))
+
+		Expect(unconfigured.StoreAsset("director-creds.yml", []byte("password: secret"))).To(Succeed())
+		stored, err := stateStore.LoadFile(bucket, "director-creds.yml")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(encryption.IsEncrypted(stored)).To(BeTrue())
+	})
+
+	Describe("EncryptAssets", func() {
+		It("encrypts the named assets that exist", func() {
+			Expect(stateStore.WriteFile(bucket, "config.json", []byte("{}"))).To(Succeed())
+			Expect(stateStore.WriteFile(bucket, "director-creds.yml", []byte("password: secret"))).To(Succeed())
+
+			encrypted, err := client.EncryptAssets([]string{"config.json", "director-creds.yml", "director-state.json"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(encrypted).To(Equal([]string{"director-creds.yml", "config.json"}))
+
+			for _, filename := range encrypted {
+				stored, err := stateStore.LoadFile(bucket, filename)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(encryption.IsEncrypted(stored)).To(BeTrue())
+			}
+		})
+
+		It("records the encrypted versions of the other files in config.json", func() {
+			Expect(stateStore.WriteFile(bucket, "director-creds.yml", []byte("password: secret"))).To(Succeed())
+			Expect(stateStore.WriteFile(bucket, "config.json", []byte(`{"deployment": "test"}`))).To(Succeed())
+
+			_, err := client.EncryptAssets([]string{"config.json", "director-creds.yml"})
+			Expect(err).ToNot(HaveOccurred())
+
+			versions, err := client.AssetVersions("director-creds.yml")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(versions[0].Current).To(BeTrue())
+			conf, err := client.Load()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(conf.Deployment).To(Equal("test"))
+			Expect(conf.FileVersions).To(HaveKeyWithValue("director-creds.yml", versions[0].ID))
+		})
+
+		It("requires a key provider", func() {
+			client.KeyProvider = nil
+			_, err := client.EncryptAssets([]string{"config.json"})
+			Expect(err).To(MatchError(ContainSubstring("use --encryption")))
+		})
+	})
+})
+
+var _ = Describe("Asset versions", func() {
+	var dir string
+	var stateStore *store.LocalStore
+	var client *Client
+
+	const bucket = "control-tower-test-eu-west-1-config"
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "config-versions")
+		Expect(err).ToNot(HaveOccurred())
+		stateStore = store.NewLocal(dir)
+		Expect(stateStore.CreateBucket(bucket)).To(Succeed())
+
+		provider := &iaasfakes.FakeProvider{}
+		provider.RegionReturns("eu-west-1")
+		client = New(provider, stateStore, "test", "")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("restores a previous version as the current one", func() {
+		Expect(client.StoreAsset("director-creds.yml", []byte("password: old"))).To(Succeed())
+		Expect(client.StoreAsset("director-creds.yml", []byte("password: new"))).To(Succeed())
+
+		versions, err := client.AssetVersions("director-creds.yml")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(versions).To(HaveLen(2))
+
+		contents, err := client.LoadAssetVersion("director-creds.yml", versions[1].ID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(contents)).To(Equal("password: old"))
+
+		Expect(client.RestoreAssetVersion("director-creds.yml", versions[1].ID)).To(Succeed())
+		contents, err = client.LoadAsset("director-creds.yml")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(contents)).To(Equal("password: old"))
+
+		versions, err = client.AssetVersions("director-creds.yml")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(versions).To(HaveLen(3))
+	})
+
+	It("records the current versions of the director files in the config", func() {
+		Expect(client.StoreAsset("director-creds.yml", []byte("password: secret"))).To(Succeed())
+		Expect(client.Update(Config{Project: "test"})).To(Succeed())
+
+		creds, err := client.AssetVersions("director-creds.yml")
+		Expect(err).ToNot(HaveOccurred())
+		conf, err := client.Load()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(conf.FileVersions).To(Equal(map[string]string{"director-creds.yml": creds[0].ID}))
+	})
+
+	It("encrypts a version stored before the bucket was encrypted when restoring it", func() {
+		Expect(client.StoreAsset("director-creds.yml", []byte("password: old"))).To(Succeed())
+		versions, err := client.AssetVersions("director-creds.yml")
+		Expect(err).ToNot(HaveOccurred())
+
+		keyProvider, err := encryption.NewPassphrase("passphrase")
+		Expect(err).ToNot(HaveOccurred())
+		client.KeyProvider = keyProvider
+		Expect(client.StoreAsset("director-creds.yml", []byte("password: new"))).To(Succeed())
+
+		unconfigured := New(client.Iaas, stateStore, "test", "")
+		os.Setenv("ENCRYPTION_PASSPHRASE", "passphrase")
+		defer os.Unsetenv("ENCRYPTION_PASSPHRASE")
+		Expect(unconfigured.RestoreAssetVersion("director-creds.yml", versions[0].ID)).To(Succeed())
+
+		stored, err := stateStore.LoadFile(bucket, "director-creds.yml")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(encryption.IsEncrypted(stored)).To(BeTrue())
+		contents, err := client.LoadAsset("director-creds.yml")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(contents)).To(Equal("password: old"))
+	})
+
+	It("deletes every version but the current one", func() {
+		Expect(client.StoreAsset("director-creds.yml", []byte("password: old"))).To(Succeed())
+		Expect(client.StoreAsset("director-creds.yml", []byte("password: older"))).To(Succeed())
+		Expect(client.StoreAsset("director-creds.yml", []byte("password: new"))).To(Succeed())
+
+		deleted, err := client.DeleteOldAssetVersions("director-creds.yml")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(Equal(2))
+
+		versions, err := client.AssetVersions("director-creds.yml")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(versions).To(HaveLen(1))
+		contents, err := client.LoadAsset("director-creds.yml")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(contents)).To(Equal("password: new"))
+	})
+
+	It("deletes nothing where previous versions are not kept", func() {
+		provider := &iaasfakes.FakeProvider{}
+		provider.RegionReturns("eu-west-1")
+		provider.ListFileVersionsReturns(nil, iaas.ErrVersioningUnsupported)
+		client = New(provider, store.FromIAAS(provider), "test", "")
+
+		deleted, err := client.DeleteOldAssetVersions("director-creds.yml")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(BeZero())
+		Expect(provider.DeleteFileVersionCallCount()).To(BeZero())
+	})
+})
@@ -11,48 +11,49 @@ func ConvertSpotBoolToVMProvisioningType(spot bool) string {
 	}
 }
 
-// Config represents a control-tower configuration file
+// Config represents a control-tower configuration file.
+// Fields holding secrets are tagged with the component they belong to, eg `secret:"bosh"`
 type Config struct {
 	AllowIPs                 string `json:"allow_ips"`
 	AvailabilityZone         string `json:"availability_zone"`
 	ConcourseCACert          string `json:"concourse_ca_cert"`
 	ConcourseCert            string `json:"concourse_cert"`
-	ConcourseKey             string `json:"concourse_key"`
-	ConcoursePassword        string `json:"concourse_password"`
+	ConcourseKey             string `json:"concourse_key" secret:"concourse"`
+	ConcoursePassword        string `json:"concourse_password" secret:"concourse"`
 	ConcourseUsername        string `json:"concourse_username"`
 	ConcourseWebSize         string `json:"concourse_web_size"`
 	ConcourseWorkerCount     int    `json:"concourse_worker_count"`
 	ConcourseWorkerSize      string `json:"concourse_worker_size"`
 	ConfigBucket             string `json:"config_bucket"`
-	CredhubAdminClientSecret string `json:"credhub_admin_client_secret"`
+	CredhubAdminClientSecret string `json:"credhub_admin_client_secret" secret:"credhub"`
 	CredhubCACert            string `json:"credhub_ca_cert"`
-	CredhubPassword          string `json:"credhub_password"`
+	CredhubPassword          string `json:"credhub_password" secret:"credhub"`
 	CredhubURL               string `json:"credhub_url"`
 	CredhubUsername          string `json:"credhub_username"`
 	Deployment               string `json:"deployment"`
 	DirectorCACert           string `json:"director_ca_cert"`
 	DirectorCert             string `json:"director_cert"`
-	DirectorHMUserPassword   string `json:"director_hm_user_password"`
-	DirectorKey              string `json:"director_key"`
-	DirectorMbusPassword     string `json:"director_mbus_password"`
-	DirectorNATSPassword     string `json:"director_nats_password"`
-	DirectorPassword         string `json:"director_password"`
+	DirectorHMUserPassword   string `json:"director_hm_user_password" secret:"bosh"`
+	DirectorKey              string `json:"director_key" secret:"bosh"`
+	DirectorMbusPassword     string `json:"director_mbus_password" secret:"bosh"`
+	DirectorNATSPassword     string `json:"director_nats_password" secret:"bosh"`
+	DirectorPassword         string `json:"director_password" secret:"bosh"`
 	DirectorPublicIP         string `json:"director_public_ip"`
-	DirectorRegistryPassword string `json:"director_registry_password"`
+	DirectorRegistryPassword string `json:"director_registry_password" secret:"bosh"`
 	DirectorUsername         string `json:"director_username"`
 	Domain                   string `json:"domain"`
 	EnableGlobalResources    bool   `json:"enable_global_resources"`
-	EncryptionKey            string `json:"encryption_key"`
+	EncryptionKey            string `json:"encryption_key" secret:"concourse"`
 	GithubClientID           string `json:"github_client_id"`
-	GithubClientSecret       string `json:"github_client_secret"`
-	GrafanaPassword          string `json:"grafana_password"`
+	GithubClientSecret       string `json:"github_client_secret" secret:"concourse"`
+	GrafanaPassword          string `json:"grafana_password" secret:"grafana"`
 	HostedZoneID             string `json:"hosted_zone_id"`
 	HostedZoneRecordPrefix   string `json:"hosted_zone_record_prefix"`
 	IAAS                     string `json:"iaas"`
 	Namespace                string `json:"namespace"`
 	NetworkCIDR              string `json:"network_cidr"`
 	PrivateCIDR              string `json:"private_cidr"`
-	PrivateKey               string `json:"private_key"`
+	PrivateKey               string `json:"private_key" secret:"bosh"`
 	Project                  string `json:"project"`
 	PublicCIDR               string `json:"public_cidr"`
 	PublicKey                string `json:"public_key"`
@@ -60,17 +61,60 @@ type Config struct {
 	RDS2CIDR                 string `json:"rds2_cidr"`
 	RDSDefaultDatabaseName   string `json:"rds_default_database_name"`
 	RDSInstanceClass         string `json:"rds_instance_class"`
-	RDSPassword              string `json:"rds_password"`
+	RDSPassword              string `json:"rds_password" secret:"database"`
 	RDSUsername              string `json:"rds_username"`
 	Region                   string `json:"region"`
+	SchemaVersion            int    `json:"schema_version"`
 	SourceAccessIP           string `json:"source_access_ip"`
 	//Spot is deprecated, exists only as we need to migrate old configs to VMProvisioningType
-	Spot               bool     `json:"spot"`
-	Tags               []string `json:"tags"`
-	TFStatePath        string   `json:"tf_state_path"`
-	Version            string   `json:"version"`
-	VMProvisioningType string   `json:"vm_provisioning_type"`
-	WorkerType         string   `json:"worker_type"`
+	Spot               bool            `json:"spot"`
+	Tags               []string        `json:"tags"`
+	TFStatePath        string          `json:"tf_state_path"`
+	Version            string          `json:"version"`
+	VMProvisioningType string          `json:"vm_provisioning_type"`
+	WorkerPools        []WorkerPool    `json:"worker_pools"`
+	WorkerSchedule     *WorkerSchedule `json:"worker_schedule"`
+	WorkerType         string          `json:"worker_type"`
+	Autoscale          *Autoscale      `json:"autoscale"`
+	// FileVersions holds the ID of the version of each of RevisionFiles that was current when this config was
+	// written. It is empty where previous versions are not kept, and in configs written before it was recorded.
+	FileVersions map[string]string `json:"file_versions,omitempty"`
+}
+
+// RevisionFiles are the files in the config bucket which keep the BOSH director, and which are
+// restored together with config.json by a rollback
+var RevisionFiles = []string{"director-state.json", "director-creds.yml"}
+
+// Autoscale adds a job to the self-update pipeline that scales the default workers between
+// MinWorkers and MaxWorkers with `autoscale --once`. The job runs on workers tagged with Tag
+// when it is set, so that it is not run on a worker it may remove
+type Autoscale struct {
+	MinWorkers int    `json:"min_workers"`
+	MaxWorkers int    `json:"max_workers"`
+	Tag        string `json:"tag"`
+}
+
+// WorkerSchedule scales the default workers down to OffWorkers at OffCron and back up to
+// OnWorkers at OnCron, with both cron expressions evaluated in Timezone. The jobs run on workers
+// tagged with Tag when it is set, so that they are not run on a worker they may remove
+type WorkerSchedule struct {
+	OffCron    string `json:"off_cron"`
+	OnCron     string `json:"on_cron"`
+	Timezone   string `json:"timezone"`
+	OffWorkers int    `json:"off_workers"`
+	OnWorkers  int    `json:"on_workers"`
+	Tag        string `json:"tag"`
+}
+
+// WorkerPool describes a group of Concourse workers deployed alongside the default workers.
+// Each pool becomes its own instance group and only runs steps matching its tags or team
+type WorkerPool struct {
+	Name  string   `json:"name" yaml:"name"`
+	Count int      `json:"count" yaml:"count"`
+	Size  string   `json:"size" yaml:"size"`
+	Spot  bool     `json:"spot" yaml:"spot"`
+	Tags  []string `json:"tags" yaml:"tags,omitempty"`
+	Team  string   `json:"team" yaml:"team,omitempty"`
 }
 
 type ConfigView interface {
@@ -128,6 +172,9 @@ type ConfigView interface {
 	GetTags() []string
 	GetTFStatePath() string
 	GetVersion() string
+	GetWorkerPools() []WorkerPool
+	GetWorkerSchedule() *WorkerSchedule
+	GetAutoscale() *Autoscale
 	GetWorkerType() string
 	IsGithubAuthSet() bool
 	IsSpot() bool
@@ -349,6 +396,18 @@ func (c Config) GetVersion() string {
 	return c.Version
 }
 
+func (c Config) GetWorkerPools() []WorkerPool {
+	return c.WorkerPools
+}
+
+func (c Config) GetWorkerSchedule() *WorkerSchedule {
+	return c.WorkerSchedule
+}
+
+func (c Config) GetAutoscale() *Autoscale {
+	return c.Autoscale
+}
+
 func (c Config) GetWorkerType() string {
 	return c.WorkerType
 }
@@ -2,12 +2,68 @@
 package configfakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/EngineerBetter/control-tower/config"
+	"github.com/EngineerBetter/control-tower/iaas"
 )
 
 type FakeIClient struct {
+	AcquireLockStub        func(context.Context, string) (context.Context, func() error, error)
+	acquireLockMutex       sync.RWMutex
+	acquireLockArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	acquireLockReturns struct {
+		result1 context.Context
+		result2 func() error
+		result3 error
+	}
+	acquireLockReturnsOnCall map[int]struct {
+		result1 context.Context
+		result2 func() error
+		result3 error
+	}
+	AssetVersionsStub        func(string) ([]iaas.FileVersion, error)
+	assetVersionsMutex       sync.RWMutex
+	assetVersionsArgsForCall []struct {
+		arg1 string
+	}
+	assetVersionsReturns struct {
+		result1 []iaas.FileVersion
+		result2 error
+	}
+	assetVersionsReturnsOnCall map[int]struct {
+		result1 []iaas.FileVersion
+		result2 error
+	}
+	BreakLockStub        func(bool) (config.Lock, error)
+	breakLockMutex       sync.RWMutex
+	breakLockArgsForCall []struct {
+		arg1 bool
+	}
+	breakLockReturns struct {
+		result1 config.Lock
+		result2 error
+	}
+	breakLockReturnsOnCall map[int]struct {
+		result1 config.Lock
+		result2 error
+	}
+	ConfigBucketExistsStub        func() (bool, error)
+	configBucketExistsMutex       sync.RWMutex
+	configBucketExistsArgsForCall []struct {
+	}
+	configBucketExistsReturns struct {
+		result1 bool
+		result2 error
+	}
+	configBucketExistsReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
 	ConfigExistsStub        func() (bool, error)
 	configExistsMutex       sync.RWMutex
 	configExistsArgsForCall []struct {
@@ -31,6 +87,32 @@ type FakeIClient struct {
 	deleteAllReturnsOnCall map[int]struct {
 		result1 error
 	}
+	DeleteOldAssetVersionsStub        func(string) (int, error)
+	deleteOldAssetVersionsMutex       sync.RWMutex
+	deleteOldAssetVersionsArgsForCall []struct {
+		arg1 string
+	}
+	deleteOldAssetVersionsReturns struct {
+		result1 int
+		result2 error
+	}
+	deleteOldAssetVersionsReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	EncryptAssetsStub        func([]string) ([]string, error)
+	encryptAssetsMutex       sync.RWMutex
+	encryptAssetsArgsForCall []struct {
+		arg1 []string
+	}
+	encryptAssetsReturns struct {
+		result1 []string
+		result2 error
+	}
+	encryptAssetsReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
 	EnsureBucketExistsStub        func() error
 	ensureBucketExistsMutex       sync.RWMutex
 	ensureBucketExistsArgsForCall []struct {
@@ -79,6 +161,20 @@ type FakeIClient struct {
 		result1 []byte
 		result2 error
 	}
+	LoadAssetVersionStub        func(string, string) ([]byte, error)
+	loadAssetVersionMutex       sync.RWMutex
+	loadAssetVersionArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	loadAssetVersionReturns struct {
+		result1 []byte
+		result2 error
+	}
+	loadAssetVersionReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
 	NewConfigStub        func() config.Config
 	newConfigMutex       sync.RWMutex
 	newConfigArgsForCall []struct {
@@ -89,6 +185,18 @@ type FakeIClient struct {
 	newConfigReturnsOnCall map[int]struct {
 		result1 config.Config
 	}
+	RestoreAssetVersionStub        func(string, string) error
+	restoreAssetVersionMutex       sync.RWMutex
+	restoreAssetVersionArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	restoreAssetVersionReturns struct {
+		result1 error
+	}
+	restoreAssetVersionReturnsOnCall map[int]struct {
+		result1 error
+	}
 	StoreAssetStub        func(string, []byte) error
 	storeAssetMutex       sync.RWMutex
 	storeAssetArgsForCall []struct {
@@ -101,6 +209,18 @@ type FakeIClient struct {
 	storeAssetReturnsOnCall map[int]struct {
 		result1 error
 	}
+	StoreEncryptedAssetStub        func(string, []byte) error
+	storeEncryptedAssetMutex       sync.RWMutex
+	storeEncryptedAssetArgsForCall []struct {
+		arg1 string
+		arg2 []byte
+	}
+	storeEncryptedAssetReturns struct {
+		result1 error
+	}
+	storeEncryptedAssetReturnsOnCall map[int]struct {
+		result1 error
+	}
 	UpdateStub        func(config.Config) error
 	updateMutex       sync.RWMutex
 	updateArgsForCall []struct {
@@ -112,10 +232,268 @@ type FakeIClient struct {
 	updateReturnsOnCall map[int]struct {
 		result1 error
 	}
+	UpgradeConfigStub        func() error
+	upgradeConfigMutex       sync.RWMutex
+	upgradeConfigArgsForCall []struct {
+	}
+	upgradeConfigReturns struct {
+		result1 error
+	}
+	upgradeConfigReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeIClient) AcquireLock(arg1 context.Context, arg2 string) (context.Context, func() error, error) {
+	fake.acquireLockMutex.Lock()
+	ret, specificReturn := fake.acquireLockReturnsOnCall[len(fake.acquireLockArgsForCall)]
+	fake.acquireLockArgsForCall = append(fake.acquireLockArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("AcquireLock", []interface{}{arg1, arg2})
+	fake.acquireLockMutex.Unlock()
+	if fake.AcquireLockStub != nil {
+		return fake.AcquireLockStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	fakeReturns := fake.acquireLockReturns
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeIClient) AcquireLockCallCount() int {
+	fake.acquireLockMutex.RLock()
+	defer fake.acquireLockMutex.RUnlock()
+	return len(fake.acquireLockArgsForCall)
+}
+
+func (fake *FakeIClient) AcquireLockCalls(stub func(context.Context, string) (context.Context, func() error, error)) {
+	fake.acquireLockMutex.Lock()
+	defer fake.acquireLockMutex.Unlock()
+	fake.AcquireLockStub = stub
+}
+
+func (fake *FakeIClient) AcquireLockArgsForCall(i int) (context.Context, string) {
+	fake.acquireLockMutex.RLock()
+	defer fake.acquireLockMutex.RUnlock()
+	argsForCall := fake.acquireLockArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeIClient) AcquireLockReturns(result1 context.Context, result2 func() error, result3 error) {
+	fake.acquireLockMutex.Lock()
+	defer fake.acquireLockMutex.Unlock()
+	fake.AcquireLockStub = nil
+	fake.acquireLockReturns = struct {
+		result1 context.Context
+		result2 func() error
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeIClient) AcquireLockReturnsOnCall(i int, result1 context.Context, result2 func() error, result3 error) {
+	fake.acquireLockMutex.Lock()
+	defer fake.acquireLockMutex.Unlock()
+	fake.AcquireLockStub = nil
+	if fake.acquireLockReturnsOnCall == nil {
+		fake.acquireLockReturnsOnCall = make(map[int]struct {
+			result1 context.Context
+			result2 func() error
+			result3 error
+		})
+	}
+	fake.acquireLockReturnsOnCall[i] = struct {
+		result1 context.Context
+		result2 func() error
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeIClient) AssetVersions(arg1 string) ([]iaas.FileVersion, error) {
+	fake.assetVersionsMutex.Lock()
+	ret, specificReturn := fake.assetVersionsReturnsOnCall[len(fake.assetVersionsArgsForCall)]
+	fake.assetVersionsArgsForCall = append(fake.assetVersionsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("AssetVersions", []interface{}{arg1})
+	fake.assetVersionsMutex.Unlock()
+	if fake.AssetVersionsStub != nil {
+		return fake.AssetVersionsStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.assetVersionsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeIClient) AssetVersionsCallCount() int {
+	fake.assetVersionsMutex.RLock()
+	defer fake.assetVersionsMutex.RUnlock()
+	return len(fake.assetVersionsArgsForCall)
+}
+
+func (fake *FakeIClient) AssetVersionsCalls(stub func(string) ([]iaas.FileVersion, error)) {
+	fake.assetVersionsMutex.Lock()
+	defer fake.assetVersionsMutex.Unlock()
+	fake.AssetVersionsStub = stub
+}
+
+func (fake *FakeIClient) AssetVersionsArgsForCall(i int) string {
+	fake.assetVersionsMutex.RLock()
+	defer fake.assetVersionsMutex.RUnlock()
+	argsForCall := fake.assetVersionsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeIClient) AssetVersionsReturns(result1 []iaas.FileVersion, result2 error) {
+	fake.assetVersionsMutex.Lock()
+	defer fake.assetVersionsMutex.Unlock()
+	fake.AssetVersionsStub = nil
+	fake.assetVersionsReturns = struct {
+		result1 []iaas.FileVersion
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeIClient) AssetVersionsReturnsOnCall(i int, result1 []iaas.FileVersion, result2 error) {
+	fake.assetVersionsMutex.Lock()
+	defer fake.assetVersionsMutex.Unlock()
+	fake.AssetVersionsStub = nil
+	if fake.assetVersionsReturnsOnCall == nil {
+		fake.assetVersionsReturnsOnCall = make(map[int]struct {
+			result1 []iaas.FileVersion
+			result2 error
+		})
+	}
+	fake.assetVersionsReturnsOnCall[i] = struct {
+		result1 []iaas.FileVersion
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeIClient) BreakLock(arg1 bool) (config.Lock, error) {
+	fake.breakLockMutex.Lock()
+	ret, specificReturn := fake.breakLockReturnsOnCall[len(fake.breakLockArgsForCall)]
+	fake.breakLockArgsForCall = append(fake.breakLockArgsForCall, struct {
+		arg1 bool
+	}{arg1})
+	fake.recordInvocation("BreakLock", []interface{}{arg1})
+	fake.breakLockMutex.Unlock()
+	if fake.BreakLockStub != nil {
+		return fake.BreakLockStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.breakLockReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeIClient) BreakLockCallCount() int {
+	fake.breakLockMutex.RLock()
+	defer fake.breakLockMutex.RUnlock()
+	return len(fake.breakLockArgsForCall)
+}
+
+func (fake *FakeIClient) BreakLockCalls(stub func(bool) (config.Lock, error)) {
+	fake.breakLockMutex.Lock()
+	defer fake.breakLockMutex.Unlock()
+	fake.BreakLockStub = stub
+}
+
+func (fake *FakeIClient) BreakLockArgsForCall(i int) bool {
+	fake.breakLockMutex.RLock()
+	defer fake.breakLockMutex.RUnlock()
+	argsForCall := fake.breakLockArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeIClient) BreakLockReturns(result1 config.Lock, result2 error) {
+	fake.breakLockMutex.Lock()
+	defer fake.breakLockMutex.Unlock()
+	fake.BreakLockStub = nil
+	fake.breakLockReturns = struct {
+		result1 config.Lock
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeIClient) BreakLockReturnsOnCall(i int, result1 config.Lock, result2 error) {
+	fake.breakLockMutex.Lock()
+	defer fake.breakLockMutex.Unlock()
+	fake.BreakLockStub = nil
+	if fake.breakLockReturnsOnCall == nil {
+		fake.breakLockReturnsOnCall = make(map[int]struct {
+			result1 config.Lock
+			result2 error
+		})
+	}
+	fake.breakLockReturnsOnCall[i] = struct {
+		result1 config.Lock
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeIClient) ConfigBucketExists() (bool, error) {
+	fake.configBucketExistsMutex.Lock()
+	ret, specificReturn := fake.configBucketExistsReturnsOnCall[len(fake.configBucketExistsArgsForCall)]
+	fake.configBucketExistsArgsForCall = append(fake.configBucketExistsArgsForCall, struct {
+	}{})
+	fake.recordInvocation("ConfigBucketExists", []interface{}{})
+	fake.configBucketExistsMutex.Unlock()
+	if fake.ConfigBucketExistsStub != nil {
+		return fake.ConfigBucketExistsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.configBucketExistsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeIClient) ConfigBucketExistsCallCount() int {
+	fake.configBucketExistsMutex.RLock()
+	defer fake.configBucketExistsMutex.RUnlock()
+	return len(fake.configBucketExistsArgsForCall)
+}
+
+func (fake *FakeIClient) ConfigBucketExistsCalls(stub func() (bool, error)) {
+	fake.configBucketExistsMutex.Lock()
+	defer fake.configBucketExistsMutex.Unlock()
+	fake.ConfigBucketExistsStub = stub
+}
+
+func (fake *FakeIClient) ConfigBucketExistsReturns(result1 bool, result2 error) {
+	fake.configBucketExistsMutex.Lock()
+	defer fake.configBucketExistsMutex.Unlock()
+	fake.ConfigBucketExistsStub = nil
+	fake.configBucketExistsReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeIClient) ConfigBucketExistsReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.configBucketExistsMutex.Lock()
+	defer fake.configBucketExistsMutex.Unlock()
+	fake.ConfigBucketExistsStub = nil
+	if fake.configBucketExistsReturnsOnCall == nil {
+		fake.configBucketExistsReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.configBucketExistsReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeIClient) ConfigExists() (bool, error) {
 	fake.configExistsMutex.Lock()
 	ret, specificReturn := fake.configExistsReturnsOnCall[len(fake.configExistsArgsForCall)]
@@ -231,6 +609,137 @@ func (fake *FakeIClient) DeleteAllReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeIClient) DeleteOldAssetVersions(arg1 string) (int, error) {
+	fake.deleteOldAssetVersionsMutex.Lock()
+	ret, specificReturn := fake.deleteOldAssetVersionsReturnsOnCall[len(fake.deleteOldAssetVersionsArgsForCall)]
+	fake.deleteOldAssetVersionsArgsForCall = append(fake.deleteOldAssetVersionsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("DeleteOldAssetVersions", []interface{}{arg1})
+	fake.deleteOldAssetVersionsMutex.Unlock()
+	if fake.DeleteOldAssetVersionsStub != nil {
+		return fake.DeleteOldAssetVersionsStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.deleteOldAssetVersionsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeIClient) DeleteOldAssetVersionsCallCount() int {
+	fake.deleteOldAssetVersionsMutex.RLock()
+	defer fake.deleteOldAssetVersionsMutex.RUnlock()
+	return len(fake.deleteOldAssetVersionsArgsForCall)
+}
+
+func (fake *FakeIClient) DeleteOldAssetVersionsCalls(stub func(string) (int, error)) {
+	fake.deleteOldAssetVersionsMutex.Lock()
+	defer fake.deleteOldAssetVersionsMutex.Unlock()
+	fake.DeleteOldAssetVersionsStub = stub
+}
+
+func (fake *FakeIClient) DeleteOldAssetVersionsArgsForCall(i int) string {
+	fake.deleteOldAssetVersionsMutex.RLock()
+	defer fake.deleteOldAssetVersionsMutex.RUnlock()
+	argsForCall := fake.deleteOldAssetVersionsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeIClient) DeleteOldAssetVersionsReturns(result1 int, result2 error) {
+	fake.deleteOldAssetVersionsMutex.Lock()
+	defer fake.deleteOldAssetVersionsMutex.Unlock()
+	fake.DeleteOldAssetVersionsStub = nil
+	fake.deleteOldAssetVersionsReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeIClient) DeleteOldAssetVersionsReturnsOnCall(i int, result1 int, result2 error) {
+	fake.deleteOldAssetVersionsMutex.Lock()
+	defer fake.deleteOldAssetVersionsMutex.Unlock()
+	fake.DeleteOldAssetVersionsStub = nil
+	if fake.deleteOldAssetVersionsReturnsOnCall == nil {
+		fake.deleteOldAssetVersionsReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.deleteOldAssetVersionsReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeIClient) EncryptAssets(arg1 []string) ([]string, error) {
+	var arg1Copy []string
+	if arg1 != nil {
+		arg1Copy = make([]string, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.encryptAssetsMutex.Lock()
+	ret, specificReturn := fake.encryptAssetsReturnsOnCall[len(fake.encryptAssetsArgsForCall)]
+	fake.encryptAssetsArgsForCall = append(fake.encryptAssetsArgsForCall, struct {
+		arg1 []string
+	}{arg1Copy})
+	fake.recordInvocation("EncryptAssets", []interface{}{arg1Copy})
+	fake.encryptAssetsMutex.Unlock()
+	if fake.EncryptAssetsStub != nil {
+		return fake.EncryptAssetsStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.encryptAssetsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeIClient) EncryptAssetsCallCount() int {
+	fake.encryptAssetsMutex.RLock()
+	defer fake.encryptAssetsMutex.RUnlock()
+	return len(fake.encryptAssetsArgsForCall)
+}
+
+func (fake *FakeIClient) EncryptAssetsCalls(stub func([]string) ([]string, error)) {
+	fake.encryptAssetsMutex.Lock()
+	defer fake.encryptAssetsMutex.Unlock()
+	fake.EncryptAssetsStub = stub
+}
+
+func (fake *FakeIClient) EncryptAssetsArgsForCall(i int) []string {
+	fake.encryptAssetsMutex.RLock()
+	defer fake.encryptAssetsMutex.RUnlock()
+	argsForCall := fake.encryptAssetsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeIClient) EncryptAssetsReturns(result1 []string, result2 error) {
+	fake.encryptAssetsMutex.Lock()
+	defer fake.encryptAssetsMutex.Unlock()
+	fake.EncryptAssetsStub = nil
+	fake.encryptAssetsReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeIClient) EncryptAssetsReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.encryptAssetsMutex.Lock()
+	defer fake.encryptAssetsMutex.Unlock()
+	fake.EncryptAssetsStub = nil
+	if fake.encryptAssetsReturnsOnCall == nil {
+		fake.encryptAssetsReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.encryptAssetsReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeIClient) EnsureBucketExists() error {
 	fake.ensureBucketExistsMutex.Lock()
 	ret, specificReturn := fake.ensureBucketExistsReturnsOnCall[len(fake.ensureBucketExistsArgsForCall)]
@@ -464,6 +973,70 @@ func (fake *FakeIClient) LoadAssetReturnsOnCall(i int, result1 []byte, result2 e
 	}{result1, result2}
 }
 
+func (fake *FakeIClient) LoadAssetVersion(arg1 string, arg2 string) ([]byte, error) {
+	fake.loadAssetVersionMutex.Lock()
+	ret, specificReturn := fake.loadAssetVersionReturnsOnCall[len(fake.loadAssetVersionArgsForCall)]
+	fake.loadAssetVersionArgsForCall = append(fake.loadAssetVersionArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("LoadAssetVersion", []interface{}{arg1, arg2})
+	fake.loadAssetVersionMutex.Unlock()
+	if fake.LoadAssetVersionStub != nil {
+		return fake.LoadAssetVersionStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.loadAssetVersionReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeIClient) LoadAssetVersionCallCount() int {
+	fake.loadAssetVersionMutex.RLock()
+	defer fake.loadAssetVersionMutex.RUnlock()
+	return len(fake.loadAssetVersionArgsForCall)
+}
+
+func (fake *FakeIClient) LoadAssetVersionCalls(stub func(string, string) ([]byte, error)) {
+	fake.loadAssetVersionMutex.Lock()
+	defer fake.loadAssetVersionMutex.Unlock()
+	fake.LoadAssetVersionStub = stub
+}
+
+func (fake *FakeIClient) LoadAssetVersionArgsForCall(i int) (string, string) {
+	fake.loadAssetVersionMutex.RLock()
+	defer fake.loadAssetVersionMutex.RUnlock()
+	argsForCall := fake.loadAssetVersionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeIClient) LoadAssetVersionReturns(result1 []byte, result2 error) {
+	fake.loadAssetVersionMutex.Lock()
+	defer fake.loadAssetVersionMutex.Unlock()
+	fake.LoadAssetVersionStub = nil
+	fake.loadAssetVersionReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeIClient) LoadAssetVersionReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.loadAssetVersionMutex.Lock()
+	defer fake.loadAssetVersionMutex.Unlock()
+	fake.LoadAssetVersionStub = nil
+	if fake.loadAssetVersionReturnsOnCall == nil {
+		fake.loadAssetVersionReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.loadAssetVersionReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeIClient) NewConfig() config.Config {
 	fake.newConfigMutex.Lock()
 	ret, specificReturn := fake.newConfigReturnsOnCall[len(fake.newConfigArgsForCall)]
@@ -516,6 +1089,67 @@ func (fake *FakeIClient) NewConfigReturnsOnCall(i int, result1 config.Config) {
 	}{result1}
 }
 
+func (fake *FakeIClient) RestoreAssetVersion(arg1 string, arg2 string) error {
+	fake.restoreAssetVersionMutex.Lock()
+	ret, specificReturn := fake.restoreAssetVersionReturnsOnCall[len(fake.restoreAssetVersionArgsForCall)]
+	fake.restoreAssetVersionArgsForCall = append(fake.restoreAssetVersionArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("RestoreAssetVersion", []interface{}{arg1, arg2})
+	fake.restoreAssetVersionMutex.Unlock()
+	if fake.RestoreAssetVersionStub != nil {
+		return fake.RestoreAssetVersionStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.restoreAssetVersionReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeIClient) RestoreAssetVersionCallCount() int {
+	fake.restoreAssetVersionMutex.RLock()
+	defer fake.restoreAssetVersionMutex.RUnlock()
+	return len(fake.restoreAssetVersionArgsForCall)
+}
+
+func (fake *FakeIClient) RestoreAssetVersionCalls(stub func(string, string) error) {
+	fake.restoreAssetVersionMutex.Lock()
+	defer fake.restoreAssetVersionMutex.Unlock()
+	fake.RestoreAssetVersionStub = stub
+}
+
+func (fake *FakeIClient) RestoreAssetVersionArgsForCall(i int) (string, string) {
+	fake.restoreAssetVersionMutex.RLock()
+	defer fake.restoreAssetVersionMutex.RUnlock()
+	argsForCall := fake.restoreAssetVersionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeIClient) RestoreAssetVersionReturns(result1 error) {
+	fake.restoreAssetVersionMutex.Lock()
+	defer fake.restoreAssetVersionMutex.Unlock()
+	fake.RestoreAssetVersionStub = nil
+	fake.restoreAssetVersionReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeIClient) RestoreAssetVersionReturnsOnCall(i int, result1 error) {
+	fake.restoreAssetVersionMutex.Lock()
+	defer fake.restoreAssetVersionMutex.Unlock()
+	fake.RestoreAssetVersionStub = nil
+	if fake.restoreAssetVersionReturnsOnCall == nil {
+		fake.restoreAssetVersionReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.restoreAssetVersionReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeIClient) StoreAsset(arg1 string, arg2 []byte) error {
 	var arg2Copy []byte
 	if arg2 != nil {
@@ -582,6 +1216,72 @@ func (fake *FakeIClient) StoreAssetReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeIClient) StoreEncryptedAsset(arg1 string, arg2 []byte) error {
+	var arg2Copy []byte
+	if arg2 != nil {
+		arg2Copy = make([]byte, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.storeEncryptedAssetMutex.Lock()
+	ret, specificReturn := fake.storeEncryptedAssetReturnsOnCall[len(fake.storeEncryptedAssetArgsForCall)]
+	fake.storeEncryptedAssetArgsForCall = append(fake.storeEncryptedAssetArgsForCall, struct {
+		arg1 string
+		arg2 []byte
+	}{arg1, arg2Copy})
+	fake.recordInvocation("StoreEncryptedAsset", []interface{}{arg1, arg2Copy})
+	fake.storeEncryptedAssetMutex.Unlock()
+	if fake.StoreEncryptedAssetStub != nil {
+		return fake.StoreEncryptedAssetStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.storeEncryptedAssetReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeIClient) StoreEncryptedAssetCallCount() int {
+	fake.storeEncryptedAssetMutex.RLock()
+	defer fake.storeEncryptedAssetMutex.RUnlock()
+	return len(fake.storeEncryptedAssetArgsForCall)
+}
+
+func (fake *FakeIClient) StoreEncryptedAssetCalls(stub func(string, []byte) error) {
+	fake.storeEncryptedAssetMutex.Lock()
+	defer fake.storeEncryptedAssetMutex.Unlock()
+	fake.StoreEncryptedAssetStub = stub
+}
+
+func (fake *FakeIClient) StoreEncryptedAssetArgsForCall(i int) (string, []byte) {
+	fake.storeEncryptedAssetMutex.RLock()
+	defer fake.storeEncryptedAssetMutex.RUnlock()
+	argsForCall := fake.storeEncryptedAssetArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeIClient) StoreEncryptedAssetReturns(result1 error) {
+	fake.storeEncryptedAssetMutex.Lock()
+	defer fake.storeEncryptedAssetMutex.Unlock()
+	fake.StoreEncryptedAssetStub = nil
+	fake.storeEncryptedAssetReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeIClient) StoreEncryptedAssetReturnsOnCall(i int, result1 error) {
+	fake.storeEncryptedAssetMutex.Lock()
+	defer fake.storeEncryptedAssetMutex.Unlock()
+	fake.StoreEncryptedAssetStub = nil
+	if fake.storeEncryptedAssetReturnsOnCall == nil {
+		fake.storeEncryptedAssetReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.storeEncryptedAssetReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeIClient) Update(arg1 config.Config) error {
 	fake.updateMutex.Lock()
 	ret, specificReturn := fake.updateReturnsOnCall[len(fake.updateArgsForCall)]
@@ -642,13 +1342,77 @@ func (fake *FakeIClient) UpdateReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeIClient) UpgradeConfig() error {
+	fake.upgradeConfigMutex.Lock()
+	ret, specificReturn := fake.upgradeConfigReturnsOnCall[len(fake.upgradeConfigArgsForCall)]
+	fake.upgradeConfigArgsForCall = append(fake.upgradeConfigArgsForCall, struct {
+	}{})
+	fake.recordInvocation("UpgradeConfig", []interface{}{})
+	fake.upgradeConfigMutex.Unlock()
+	if fake.UpgradeConfigStub != nil {
+		return fake.UpgradeConfigStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.upgradeConfigReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeIClient) UpgradeConfigCallCount() int {
+	fake.upgradeConfigMutex.RLock()
+	defer fake.upgradeConfigMutex.RUnlock()
+	return len(fake.upgradeConfigArgsForCall)
+}
+
+func (fake *FakeIClient) UpgradeConfigCalls(stub func() error) {
+	fake.upgradeConfigMutex.Lock()
+	defer fake.upgradeConfigMutex.Unlock()
+	fake.UpgradeConfigStub = stub
+}
+
+func (fake *FakeIClient) UpgradeConfigReturns(result1 error) {
+	fake.upgradeConfigMutex.Lock()
+	defer fake.upgradeConfigMutex.Unlock()
+	fake.UpgradeConfigStub = nil
+	fake.upgradeConfigReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeIClient) UpgradeConfigReturnsOnCall(i int, result1 error) {
+	fake.upgradeConfigMutex.Lock()
+	defer fake.upgradeConfigMutex.Unlock()
+	fake.UpgradeConfigStub = nil
+	if fake.upgradeConfigReturnsOnCall == nil {
+		fake.upgradeConfigReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.upgradeConfigReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeIClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.acquireLockMutex.RLock()
+	defer fake.acquireLockMutex.RUnlock()
+	fake.assetVersionsMutex.RLock()
+	defer fake.assetVersionsMutex.RUnlock()
+	fake.breakLockMutex.RLock()
+	defer fake.breakLockMutex.RUnlock()
+	fake.configBucketExistsMutex.RLock()
+	defer fake.configBucketExistsMutex.RUnlock()
 	fake.configExistsMutex.RLock()
 	defer fake.configExistsMutex.RUnlock()
 	fake.deleteAllMutex.RLock()
 	defer fake.deleteAllMutex.RUnlock()
+	fake.deleteOldAssetVersionsMutex.RLock()
+	defer fake.deleteOldAssetVersionsMutex.RUnlock()
+	fake.encryptAssetsMutex.RLock()
+	defer fake.encryptAssetsMutex.RUnlock()
 	fake.ensureBucketExistsMutex.RLock()
 	defer fake.ensureBucketExistsMutex.RUnlock()
 	fake.hasAssetMutex.RLock()
@@ -657,12 +1421,20 @@ func (fake *FakeIClient) Invocations() map[string][][]interface{} {
 	defer fake.loadMutex.RUnlock()
 	fake.loadAssetMutex.RLock()
 	defer fake.loadAssetMutex.RUnlock()
+	fake.loadAssetVersionMutex.RLock()
+	defer fake.loadAssetVersionMutex.RUnlock()
 	fake.newConfigMutex.RLock()
 	defer fake.newConfigMutex.RUnlock()
+	fake.restoreAssetVersionMutex.RLock()
+	defer fake.restoreAssetVersionMutex.RUnlock()
 	fake.storeAssetMutex.RLock()
 	defer fake.storeAssetMutex.RUnlock()
+	fake.storeEncryptedAssetMutex.RLock()
+	defer fake.storeEncryptedAssetMutex.RUnlock()
 	fake.updateMutex.RLock()
 	defer fake.updateMutex.RUnlock()
+	fake.upgradeConfigMutex.RLock()
+	defer fake.upgradeConfigMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/EngineerBetter/control-tower/iaas"
+	"github.com/EngineerBetter/control-tower/store"
+)
+
+// Deployment summarises a deployment discovered in a config bucket
+type Deployment struct {
+	Project      string    `json:"project"`
+	Namespace    string    `json:"namespace"`
+	Region       string    `json:"region"`
+	Domain       string    `json:"domain"`
+	Version      string    `json:"version"`
+	WorkerCount  int       `json:"worker_count"`
+	WorkerSize   string    `json:"worker_size"`
+	LastDeployed time.Time `json:"last_deployed"`
+	ConfigBucket string    `json:"config_bucket"`
+	// Error is set, and the other fields except ConfigBucket and Region may be empty, when the bucket could not be read
+	Error string `json:"error,omitempty"`
+}
+
+// ProviderFactory returns a provider for the given IAAS and region
+type ProviderFactory func(iaas.Name, string) (iaas.Provider, error)
+
+// List finds every deployment with a config bucket visible to the provider
+func List(provider iaas.Provider, providerFactory ProviderFactory) ([]Deployment, error) {
+	bucketNames, err := provider.ListBuckets()
+	if err != nil {
+		return nil, fmt.Errorf("error listing buckets: [%v]", err)
+	}
+
+	deployments := []Deployment{}
+	for _, bucketName := range bucketNames {
+		if !isConfigBucket(bucketName) {
+			continue
+		}
+
+		region, err := provider.BucketRegion(bucketName)
+		if err != nil {
+			deployments = append(deployments, unreadableDeployment(bucketName, "", fmt.Errorf("error determining region of bucket [%v]: [%v]", bucketName, err)))
+			continue
+		}
+
+		bucketProvider := provider
+		if region != provider.Region() {
+			bucketProvider, err = providerFactory(provider.IAAS(), region)
+			if err != nil {
+				deployments = append(deployments, unreadableDeployment(bucketName, region, fmt.Errorf("error creating provider for region [%v]: [%v]", region, err)))
+				continue
+			}
+		}
+
+		deployment, found, err := loadDeployment(store.FromIAAS(bucketProvider), bucketName)
+		if err != nil {
+			deployments = append(deployments, unreadableDeployment(bucketName, region, err))
+			continue
+		}
+		if found {
+			deployments = append(deployments, deployment)
+		}
+	}
+
+	sortDeployments(deployments)
+
+	return deployments, nil
+}
+
+// ListStore finds every deployment with a config bucket in a state store which is not that of the IAAS
+func ListStore(stateStore store.Store) ([]Deployment, error) {
+	bucketNames, err := stateStore.ListBuckets()
+	if err != nil {
+		return nil, fmt.Errorf("error listing buckets: [%v]", err)
+	}
+
+	deployments := []Deployment{}
+	for _, bucketName := range bucketNames {
+		if !isConfigBucket(bucketName) {
+			continue
+		}
+
+		deployment, found, err := loadDeployment(stateStore, bucketName)
+		if err != nil {
+			deployments = append(deployments, unreadableDeployment(bucketName, "", err))
+			continue
+		}
+		if found {
+			deployments = append(deployments, deployment)
+		}
+	}
+
+	sortDeployments(deployments)
+
+	return deployments, nil
+}
+
+// unreadableDeployment reports err in place of the deployment in bucketName, so that one unreadable bucket
+// does not stop the others being listed
+func unreadableDeployment(bucketName, region string, err error) Deployment {
+	return Deployment{Region: region, ConfigBucket: bucketName, Error: err.Error()}
+}
+
+func sortDeployments(deployments []Deployment) {
+	sort.Slice(deployments, func(i, j int) bool {
+		if deployments[i].Project != deployments[j].Project {
+			return deployments[i].Project < deployments[j].Project
+		}
+		if deployments[i].Namespace != deployments[j].Namespace {
+			return deployments[i].Namespace < deployments[j].Namespace
+		}
+		return deployments[i].ConfigBucket < deployments[j].ConfigBucket
+	})
+}
+
+func loadDeployment(stateStore store.Store, bucketName string) (Deployment, bool, error) {
+	client := &Client{
+		Store:        stateStore,
+		BucketName:   bucketName,
+		BucketExists: true,
+	}
+
+	exists, err := client.ConfigExists()
+	if err != nil {
+		return Deployment{}, false, fmt.Errorf("error checking for config in bucket [%v]: [%v]", bucketName, err)
+	}
+	if !exists {
+		return Deployment{}, false, nil
+	}
+
+	conf, err := client.Load()
+	if err != nil {
+		return Deployment{}, false, fmt.Errorf("error loading config from bucket [%v]: [%v]", bucketName, err)
+	}
+
+	lastDeployed, err := stateStore.FileLastModified(bucketName, configFilePath)
+	if err != nil {
+		return Deployment{}, false, fmt.Errorf("error finding when config in bucket [%v] was last updated: [%v]", bucketName, err)
+	}
+
+	return Deployment{
+		Project:      conf.Project,
+		Namespace:    conf.Namespace,
+		Region:       conf.Region,
+		Domain:       conf.Domain,
+		Version:      conf.Version,
+		WorkerCount:  conf.ConcourseWorkerCount,
+		WorkerSize:   conf.ConcourseWorkerSize,
+		LastDeployed: lastDeployed,
+		ConfigBucket: bucketName,
+	}, true, nil
+}
+
+func isConfigBucket(name string) bool {
+	return strings.HasPrefix(name, deployment("")) && strings.HasSuffix(name, "-config")
+}
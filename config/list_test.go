@@ -0,0 +1,170 @@
+package config_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"time"
+
+	. "github.com/EngineerBetter/control-tower/config"
+	"github.com/EngineerBetter/control-tower/iaas"
+	"github.com/EngineerBetter/control-tower/iaas/iaasfakes"
+	"github.com/EngineerBetter/control-tower/store"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("List", func() {
+	var provider, otherRegionProvider *iaasfakes.FakeProvider
+	var factoryRegions []string
+	var lastDeployed time.Time
+
+	factory := func(name iaas.Name, region string) (iaas.Provider, error) {
+		factoryRegions = append(factoryRegions, region)
+		return otherRegionProvider, nil
+	}
+
+	configFor := func(project, namespace, region string) []byte {
+		b, err := json.Marshal(Config{
+			Project:              project,
+			Namespace:            namespace,
+			Region:               region,
+			Domain:               project + ".example.com",
+			Version:              "1.2.3",
+			ConcourseWorkerCount: 2,
+			ConcourseWorkerSize:  "xlarge",
+		})
+		Expect(err).ToNot(HaveOccurred())
+		return b
+	}
+
+	BeforeEach(func() {
+		factoryRegions = nil
+		lastDeployed = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		provider = &iaasfakes.FakeProvider{}
+		provider.RegionReturns("eu-west-1")
+		provider.IAASReturns(iaas.AWS)
+		provider.ListBucketsReturns([]string{
+			"control-tower-zeta-eu-west-1-config",
+			"unrelated-bucket",
+			"control-tower-alpha-team-config",
+			"control-tower-empty-eu-west-1-config",
+		}, nil)
+		provider.BucketRegionStub = func(name string) (string, error) {
+			if name == "control-tower-alpha-team-config" {
+				return "us-east-1", nil
+			}
+			return "eu-west-1", nil
+		}
+		provider.HasFileStub = func(bucket, path string) (bool, error) {
+			return bucket != "control-tower-empty-eu-west-1-config", nil
+		}
+		provider.LoadFileReturns(configFor("zeta", "eu-west-1", "eu-west-1"), nil)
+		provider.FileLastModifiedReturns(lastDeployed, nil)
+
+		otherRegionProvider = &iaasfakes.FakeProvider{}
+		otherRegionProvider.HasFileReturns(true, nil)
+		otherRegionProvider.LoadFileReturns(configFor("alpha", "team", "us-east-1"), nil)
+		otherRegionProvider.FileLastModifiedReturns(lastDeployed, nil)
+	})
+
+	It("loads each deployment's config, sorted by project", func() {
+		deployments, err := List(provider, factory)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deployments).To(Equal([]Deployment{
+			{
+				Project:      "alpha",
+				Namespace:    "team",
+				Region:       "us-east-1",
+				Domain:       "alpha.example.com",
+				Version:      "1.2.3",
+				WorkerCount:  2,
+				WorkerSize:   "xlarge",
+				LastDeployed: lastDeployed,
+				ConfigBucket: "control-tower-alpha-team-config",
+			},
+			{
+				Project:      "zeta",
+				Namespace:    "eu-west-1",
+				Region:       "eu-west-1",
+				Domain:       "zeta.example.com",
+				Version:      "1.2.3",
+				WorkerCount:  2,
+				WorkerSize:   "xlarge",
+				LastDeployed: lastDeployed,
+				ConfigBucket: "control-tower-zeta-eu-west-1-config",
+			},
+		}))
+	})
+
+	It("reads buckets in other regions using a provider for that region", func() {
+		_, err := List(provider, factory)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(factoryRegions).To(Equal([]string{"us-east-1"}))
+		bucket, path := otherRegionProvider.LoadFileArgsForCall(0)
+		Expect(bucket).To(Equal("control-tower-alpha-team-config"))
+		Expect(path).To(Equal("config.json"))
+	})
+
+	Context("when a config cannot be read", func() {
+		BeforeEach(func() {
+			otherRegionProvider.LoadFileReturns(nil, errors.New("access denied"))
+		})
+
+		It("reports the error in that bucket's row and lists the rest", func() {
+			deployments, err := List(provider, factory)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deployments).To(HaveLen(2))
+			Expect(deployments[0].ConfigBucket).To(Equal("control-tower-alpha-team-config"))
+			Expect(deployments[0].Region).To(Equal("us-east-1"))
+			Expect(deployments[0].Error).To(Equal("error loading config from bucket [control-tower-alpha-team-config]: [access denied]"))
+			Expect(deployments[1].Project).To(Equal("zeta"))
+			Expect(deployments[1].Error).To(BeEmpty())
+		})
+	})
+
+	Context("when listing buckets fails", func() {
+		BeforeEach(func() {
+			provider.ListBucketsReturns(nil, errors.New("access denied"))
+		})
+
+		It("returns a useful error message", func() {
+			_, err := List(provider, factory)
+			Expect(err).To(MatchError("error listing buckets: [access denied]"))
+		})
+	})
+})
+
+var _ = Describe("ListStore", func() {
+	var dir string
+	var stateStore *store.LocalStore
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "list-store")
+		Expect(err).ToNot(HaveOccurred())
+		stateStore = store.NewLocal(dir)
+
+		for _, bucket := range []string{"control-tower-zeta-eu-west-1-config", "control-tower-empty-eu-west-1-config", "unrelated-bucket"} {
+			Expect(stateStore.CreateBucket(bucket)).To(Succeed())
+		}
+		contents, err := json.Marshal(Config{Project: "zeta", Namespace: "eu-west-1", Region: "eu-west-1"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stateStore.WriteFile("control-tower-zeta-eu-west-1-config", "config.json", contents)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("loads the config of every deployment in the store", func() {
+		deployments, err := ListStore(stateStore)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deployments).To(HaveLen(1))
+		Expect(deployments[0].Project).To(Equal("zeta"))
+		Expect(deployments[0].ConfigBucket).To(Equal("control-tower-zeta-eu-west-1-config"))
+		Expect(deployments[0].LastDeployed).ToNot(BeZero())
+	})
+})
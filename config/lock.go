@@ -0,0 +1,268 @@
+package config
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"sync"
+	"time"
+)
+
+const lockFilePath = "lock.json"
+
+// LockTTL is how long a lock is held for without being renewed
+const LockTTL = 5 * time.Minute
+
+var lockHeartbeatInterval = time.Minute
+var lockSettleDelay = 2 * time.Second
+
+// Lock is a lease on the config bucket held by a single run of a command
+type Lock struct {
+	ID        string    `json:"id"`
+	User      string    `json:"user"`
+	Host      string    `json:"host"`
+	Command   string    `json:"command"`
+	StartedAt time.Time `json:"started_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Held returns true if the lease has not expired at the given time
+func (l Lock) Held(at time.Time) bool {
+	return l.ID != "" && at.Before(l.ExpiresAt)
+}
+
+func (l Lock) String() string {
+	return fmt.Sprintf("%s@%s running %s since %s", l.User, l.Host, l.Command, l.StartedAt.Format(time.RFC3339))
+}
+
+// AcquireLock takes the lock on the config bucket for command and renews it in the background until the
+// returned release function is called. It fails if another run holds an unexpired lock. The returned context
+// is cancelled if the lock cannot be renewed, so that the run stops rather than carrying on without it.
+func (client *Client) AcquireLock(ctx context.Context, command string) (context.Context, func() error, error) {
+	lock, err := newLock(command)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = client.createLock(lock); err != nil {
+		return nil, nil, err
+	}
+
+	// A run which removed an expired lock may have removed ours, which it saw as that expired lock,
+	// so give it a chance to land and check that the lock we wrote is the one that stayed
+	time.Sleep(lockSettleDelay)
+	current, err := client.loadLock()
+	if err != nil {
+		return nil, nil, err
+	}
+	if current.ID != lock.ID {
+		return nil, nil, lockedError(current)
+	}
+
+	lockCtx, cancel := context.WithCancel(ctx)
+	var renewErr error
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(lockHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				lock.ExpiresAt = time.Now().Add(LockTTL)
+				if renewErr = client.renewLock(lock); renewErr != nil {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	release := func() error {
+		var err error
+		once.Do(func() {
+			close(stop)
+			wg.Wait()
+			cancel()
+			if renewErr != nil {
+				err = fmt.Errorf("failed to renew lock: [%v]", renewErr)
+				return
+			}
+			err = client.deleteLock(lock.ID)
+		})
+		return err
+	}
+
+	return lockCtx, release, nil
+}
+
+// BreakLock releases the lock on the config bucket regardless of who holds it, unless it is still held and force is false.
+// It returns the lock that was broken, if any.
+func (client *Client) BreakLock(force bool) (Lock, error) {
+	existing, err := client.loadLock()
+	if err != nil {
+		return Lock{}, err
+	}
+	if existing.ID == "" {
+		return Lock{}, nil
+	}
+	if existing.Held(time.Now()) && !force {
+		return existing, fmt.Errorf("deployment is locked by %s, use --force to break the lock", existing)
+	}
+
+	if err = client.deleteLock(existing.ID); err != nil {
+		return existing, err
+	}
+	return existing, nil
+}
+
+// createLock writes lock only if there is no lock, or the lock there has expired. The write is conditional,
+// so that of several runs which find the lock free only one takes it.
+func (client *Client) createLock(lock Lock) error {
+	contents, err := json.Marshal(lock)
+	if err != nil {
+		return err
+	}
+
+	created, err := client.Store.CreateFile(client.configBucket(), lockFilePath, contents)
+	if err != nil {
+		return fmt.Errorf("error writing lock: [%v]", err)
+	}
+	if created {
+		return nil
+	}
+
+	existing, err := client.loadLock()
+	if err != nil {
+		return err
+	}
+	if existing.Held(time.Now()) {
+		return lockedError(existing)
+	}
+
+	// The holder of an expired lock has stopped renewing it, so remove it and try again
+	if err = client.deleteLock(existing.ID); err != nil {
+		return err
+	}
+	created, err = client.Store.CreateFile(client.configBucket(), lockFilePath, contents)
+	if err != nil {
+		return fmt.Errorf("error writing lock: [%v]", err)
+	}
+	if !created {
+		current, err := client.loadLock()
+		if err != nil {
+			return err
+		}
+		return lockedError(current)
+	}
+	return nil
+}
+
+// renewLock extends lock, unless another run has taken it since it was last renewed
+func (client *Client) renewLock(lock Lock) error {
+	current, err := client.loadLock()
+	if err != nil {
+		return err
+	}
+	if current.ID != lock.ID {
+		return lostError(current)
+	}
+	return client.writeLock(lock)
+}
+
+// deleteLock removes the lock, unless it is no longer the one with the given id
+func (client *Client) deleteLock(id string) error {
+	current, err := client.loadLock()
+	if err != nil {
+		return err
+	}
+	if current.ID == "" {
+		return nil
+	}
+	if current.ID != id {
+		return lostError(current)
+	}
+	if err = client.Store.DeleteFile(client.configBucket(), lockFilePath); err != nil {
+		return fmt.Errorf("error deleting lock: [%v]", err)
+	}
+	return nil
+}
+
+func lockedError(holder Lock) error {
+	return fmt.Errorf("deployment is locked by %s, use `control-tower unlock --force` if that run is no longer active", holder)
+}
+
+func lostError(holder Lock) error {
+	if holder.ID == "" {
+		return fmt.Errorf("the lock has been removed, probably by `control-tower unlock --force`")
+	}
+	return fmt.Errorf("the lock has been taken by %s", holder)
+}
+
+func (client *Client) loadLock() (Lock, error) {
+	// The lock holds no secrets, so it is read and written directly to avoid encrypting it on every heartbeat
+	exists, err := client.Store.HasFile(client.configBucket(), lockFilePath)
+	if err != nil {
+		return Lock{}, fmt.Errorf("error checking for lock: [%v]", err)
+	}
+	if !exists {
+		return Lock{}, nil
+	}
+
+	contents, err := client.Store.LoadFile(client.configBucket(), lockFilePath)
+	if err != nil {
+		return Lock{}, fmt.Errorf("error loading lock: [%v]", err)
+	}
+
+	var lock Lock
+	if err = json.Unmarshal(contents, &lock); err != nil {
+		return Lock{}, fmt.Errorf("error parsing lock: [%v]", err)
+	}
+	return lock, nil
+}
+
+func (client *Client) writeLock(lock Lock) error {
+	contents, err := json.Marshal(lock)
+	if err != nil {
+		return err
+	}
+	if err = client.Store.WriteFile(client.configBucket(), lockFilePath, contents); err != nil {
+		return fmt.Errorf("error writing lock: [%v]", err)
+	}
+	return nil
+}
+
+func newLock(command string) (Lock, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return Lock{}, err
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	username := os.Getenv("USER")
+	if u, err := user.Current(); err == nil {
+		username = u.Username
+	}
+
+	now := time.Now()
+	return Lock{
+		ID:        hex.EncodeToString(id),
+		User:      username,
+		Host:      host,
+		Command:   command,
+		StartedAt: now,
+		ExpiresAt: now.Add(LockTTL),
+	}, nil
+}
@@ -0,0 +1,188 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EngineerBetter/control-tower/store"
+)
+
+func newLockTestClient(t *testing.T) (*Client, func()) {
+	dir, err := ioutil.TempDir("", "lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lockSettleDelay = 0
+	s := store.NewLocal(dir)
+	client := &Client{Store: s, BucketName: "control-tower-test-eu-west-1-config"}
+	if err = s.CreateBucket(client.BucketName); err != nil {
+		t.Fatal(err)
+	}
+
+	return client, func() { os.RemoveAll(dir) }
+}
+
+func TestAcquireLock(t *testing.T) {
+	client, cleanup := newLockTestClient(t)
+	defer cleanup()
+
+	_, release, err := client.AcquireLock(context.Background(), "deploy")
+	if err != nil {
+		t.Fatalf("AcquireLock() error = %v", err)
+	}
+
+	lock, err := client.loadLock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !lock.Held(time.Now()) || lock.Command != "deploy" || lock.Host == "" || lock.StartedAt.IsZero() {
+		t.Errorf("lock not written with holder metadata: %+v", lock)
+	}
+
+	if _, _, err = client.AcquireLock(context.Background(), "destroy"); err == nil || !strings.Contains(err.Error(), "deployment is locked by") {
+		t.Errorf("second AcquireLock() error = %v, want the lock to be held", err)
+	}
+
+	if err = release(); err != nil {
+		t.Fatalf("release() error = %v", err)
+	}
+	if err = release(); err != nil {
+		t.Fatalf("second release() error = %v", err)
+	}
+	if lock, _ = client.loadLock(); lock.ID != "" {
+		t.Errorf("release() left the lock behind: %+v", lock)
+	}
+
+	_, release, err = client.AcquireLock(context.Background(), "destroy")
+	if err != nil {
+		t.Fatalf("AcquireLock() after release error = %v", err)
+	}
+	release()
+}
+
+func TestAcquireLock_Heartbeat(t *testing.T) {
+	client, cleanup := newLockTestClient(t)
+	defer cleanup()
+
+	lockHeartbeatInterval = 10 * time.Millisecond
+	defer func() { lockHeartbeatInterval = time.Minute }()
+
+	_, release, err := client.AcquireLock(context.Background(), "maintain")
+	if err != nil {
+		t.Fatalf("AcquireLock() error = %v", err)
+	}
+	defer release()
+
+	first, _ := client.loadLock()
+	time.Sleep(50 * time.Millisecond)
+	renewed, _ := client.loadLock()
+
+	if !renewed.ExpiresAt.After(first.ExpiresAt) {
+		t.Errorf("lock was not renewed: expiry %v then %v", first.ExpiresAt, renewed.ExpiresAt)
+	}
+}
+
+func TestAcquireLock_Expired(t *testing.T) {
+	client, cleanup := newLockTestClient(t)
+	defer cleanup()
+
+	stale := Lock{ID: "stale", Command: "deploy", ExpiresAt: time.Now().Add(-time.Minute)}
+	contents, _ := json.Marshal(stale)
+	if err := client.StoreAsset(lockFilePath, contents); err != nil {
+		t.Fatal(err)
+	}
+
+	_, release, err := client.AcquireLock(context.Background(), "deploy")
+	if err != nil {
+		t.Fatalf("AcquireLock() over an expired lock error = %v", err)
+	}
+	release()
+}
+
+func TestBreakLock(t *testing.T) {
+	client, cleanup := newLockTestClient(t)
+	defer cleanup()
+
+	if lock, err := client.BreakLock(false); err != nil || lock.ID != "" {
+		t.Errorf("BreakLock() without a lock = %+v, %v", lock, err)
+	}
+
+	if _, _, err := client.AcquireLock(context.Background(), "deploy"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.BreakLock(false); err == nil || !strings.Contains(err.Error(), "use --force") {
+		t.Errorf("BreakLock(false) error = %v, want a refusal", err)
+	}
+
+	lock, err := client.BreakLock(true)
+	if err != nil || lock.Command != "deploy" {
+		t.Fatalf("BreakLock(true) = %+v, %v", lock, err)
+	}
+
+	_, release, err := client.AcquireLock(context.Background(), "deploy")
+	if err != nil {
+		t.Fatalf("AcquireLock() after BreakLock error = %v", err)
+	}
+	release()
+}
+
+func TestAcquireLock_Lost(t *testing.T) {
+	client, cleanup := newLockTestClient(t)
+	defer cleanup()
+
+	lockHeartbeatInterval = 10 * time.Millisecond
+	defer func() { lockHeartbeatInterval = time.Minute }()
+
+	ctx, release, err := client.AcquireLock(context.Background(), "deploy")
+	if err != nil {
+		t.Fatalf("AcquireLock() error = %v", err)
+	}
+
+	other := Lock{ID: "other", User: "someone", Host: "elsewhere", Command: "destroy", ExpiresAt: time.Now().Add(LockTTL)}
+	if err = client.writeLock(other); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context was not cancelled after the lock was taken")
+	}
+
+	if err = release(); err == nil || !strings.Contains(err.Error(), "the lock has been taken by someone@elsewhere") {
+		t.Errorf("release() error = %v, want the lock to have been lost", err)
+	}
+	if lock, _ := client.loadLock(); lock.ID != "other" {
+		t.Errorf("release() removed a lock it did not hold: %+v", lock)
+	}
+}
+
+func TestAcquireLock_Removed(t *testing.T) {
+	client, cleanup := newLockTestClient(t)
+	defer cleanup()
+
+	_, release, err := client.AcquireLock(context.Background(), "deploy")
+	if err != nil {
+		t.Fatalf("AcquireLock() error = %v", err)
+	}
+	if _, err = client.BreakLock(true); err != nil {
+		t.Fatal(err)
+	}
+
+	_, other, err := client.AcquireLock(context.Background(), "destroy")
+	if err != nil {
+		t.Fatalf("AcquireLock() after BreakLock error = %v", err)
+	}
+	defer other()
+
+	if err = release(); err == nil || !strings.Contains(err.Error(), "the lock has been taken by") {
+		t.Errorf("release() error = %v, want the lock to have been lost", err)
+	}
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const schemaVersionField = "schema_version"
+
+// migration upgrades a stored config by one schema version. It operates on the raw JSON document
+// so that fields which have since been renamed or removed from Config are still available to it.
+type migration struct {
+	description string
+	migrate     func(conf map[string]interface{}) error
+}
+
+// migrations are applied in order, so that migrations[i] upgrades a config from schema version i to i+1.
+// Add a migration to the end whenever a change to Config means older configs would load with the wrong
+// values. Never reorder or remove migrations, as stored configs record how many have been applied to them.
+var migrations = []migration{
+	{"set vm_provisioning_type from the deprecated spot flag", migrateSpotToVMProvisioningType},
+}
+
+// CurrentSchemaVersion returns the version of the config schema written by this version of control-tower
+func CurrentSchemaVersion() int {
+	return len(migrations)
+}
+
+// ConfigBackupFilename returns the name of the backup taken of config.json before it is upgraded from version
+func ConfigBackupFilename(version int) string {
+	return fmt.Sprintf("config.schema-v%d.json", version)
+}
+
+// ConfigBackupFilenames returns the names of every backup that upgrading config.json may have taken
+func ConfigBackupFilenames() []string {
+	filenames := []string{}
+	for version := 0; version < CurrentSchemaVersion(); version++ {
+		filenames = append(filenames, ConfigBackupFilename(version))
+	}
+	return filenames
+}
+
+// migrateConfig upgrades the stored config in configBytes to the current schema version,
+// returning the upgraded config and the version it was stored with
+func migrateConfig(configBytes []byte) (Config, int, error) {
+	raw := map[string]interface{}{}
+	if err := json.Unmarshal(configBytes, &raw); err != nil {
+		return Config{}, 0, err
+	}
+
+	version, err := schemaVersion(raw)
+	if err != nil {
+		return Config{}, 0, err
+	}
+	if version > CurrentSchemaVersion() {
+		return Config{}, version, fmt.Errorf("config was written with schema version %d but this version of control-tower only understands up to %d, upgrade control-tower to manage this deployment", version, CurrentSchemaVersion())
+	}
+
+	for v := version; v < CurrentSchemaVersion(); v++ {
+		if err = migrations[v].migrate(raw); err != nil {
+			return Config{}, version, fmt.Errorf("error upgrading config to schema version %d (%s): [%v]", v+1, migrations[v].description, err)
+		}
+	}
+	raw[schemaVersionField] = CurrentSchemaVersion()
+
+	migratedBytes, err := json.Marshal(raw)
+	if err != nil {
+		return Config{}, version, err
+	}
+
+	var conf Config
+	if err = json.Unmarshal(migratedBytes, &conf); err != nil {
+		return Config{}, version, err
+	}
+	return conf, version, nil
+}
+
+// schemaVersion returns the schema version of a raw config, which is 0 for configs written before it was recorded
+func schemaVersion(raw map[string]interface{}) (int, error) {
+	value, ok := raw[schemaVersionField]
+	if !ok || value == nil {
+		return 0, nil
+	}
+	version, ok := value.(float64)
+	if !ok || version < 0 || version != float64(int(version)) {
+		return 0, fmt.Errorf("invalid config schema version [%v]", value)
+	}
+	return int(version), nil
+}
+
+// migrateSpotToVMProvisioningType replaces the spot boolean with the vm_provisioning_type it was superseded by
+func migrateSpotToVMProvisioningType(conf map[string]interface{}) error {
+	if provisioningType, _ := conf["vm_provisioning_type"].(string); provisioningType != "" {
+		return nil
+	}
+
+	spot := false
+	if value, ok := conf["spot"]; ok && value != nil {
+		if spot, ok = value.(bool); !ok {
+			return fmt.Errorf("spot must be a boolean, got [%v]", value)
+		}
+	}
+	conf["vm_provisioning_type"] = ConvertSpotBoolToVMProvisioningType(spot)
+	return nil
+}
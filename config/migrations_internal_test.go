@@ -0,0 +1,212 @@
+package config
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/EngineerBetter/control-tower/store"
+)
+
+func TestMigrateSpotToVMProvisioningType(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    map[string]interface{}
+		want    string
+		wantErr bool
+	}{
+		{name: "spot", conf: map[string]interface{}{"spot": true}, want: SPOT},
+		{name: "on demand", conf: map[string]interface{}{"spot": false}, want: ON_DEMAND},
+		{name: "neither", conf: map[string]interface{}{}, want: ON_DEMAND},
+		{name: "already set", conf: map[string]interface{}{"spot": false, "vm_provisioning_type": SPOT}, want: SPOT},
+		{name: "invalid spot", conf: map[string]interface{}{"spot": "yes"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := migrateSpotToVMProvisioningType(tt.conf)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("migrateSpotToVMProvisioningType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && tt.conf["vm_provisioning_type"] != tt.want {
+				t.Errorf("vm_provisioning_type = %v, want %v", tt.conf["vm_provisioning_type"], tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrateConfig(t *testing.T) {
+	defer func(original []migration) { migrations = original }(migrations)
+	migrations = []migration{
+		{"first", func(conf map[string]interface{}) error {
+			conf["deployment"] = "migrated-" + conf["old_deployment"].(string)
+			return nil
+		}},
+		{"second", func(conf map[string]interface{}) error {
+			conf["domain"] = conf["deployment"].(string) + ".example.com"
+			return nil
+		}},
+	}
+
+	tests := []struct {
+		name        string
+		stored      string
+		want        Config
+		wantVersion int
+		wantErr     string
+	}{
+		{
+			name:        "unversioned",
+			stored:      `{"old_deployment":"a"}`,
+			want:        Config{Deployment: "migrated-a", Domain: "migrated-a.example.com", SchemaVersion: 2},
+			wantVersion: 0,
+		},
+		{
+			name:        "partly migrated",
+			stored:      `{"schema_version":1,"deployment":"b"}`,
+			want:        Config{Deployment: "b", Domain: "b.example.com", SchemaVersion: 2},
+			wantVersion: 1,
+		},
+		{
+			name:        "current",
+			stored:      `{"schema_version":2,"deployment":"c"}`,
+			want:        Config{Deployment: "c", SchemaVersion: 2},
+			wantVersion: 2,
+		},
+		{
+			name:    "newer",
+			stored:  `{"schema_version":3,"deployment":"d"}`,
+			wantErr: "written with schema version 3 but this version of control-tower only understands up to 2",
+		},
+		{
+			name:    "invalid version",
+			stored:  `{"schema_version":"two"}`,
+			wantErr: "invalid config schema version",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, version, err := migrateConfig([]byte(tt.stored))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("migrateConfig() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("migrateConfig() error = %v", err)
+			}
+			if version != tt.wantVersion {
+				t.Errorf("migrateConfig() version = %d, want %d", version, tt.wantVersion)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("migrateConfig() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpgradeConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := store.NewLocal(dir)
+	client := &Client{Store: s, BucketName: "control-tower-test-eu-west-1-config"}
+	if err = s.CreateBucket(client.BucketName); err != nil {
+		t.Fatal(err)
+	}
+
+	original := []byte(`{"project":"test","spot":true}`)
+	if err = s.WriteFile(client.BucketName, configFilePath, original); err != nil {
+		t.Fatal(err)
+	}
+
+	conf, err := client.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if conf.VMProvisioningType != SPOT || conf.SchemaVersion != CurrentSchemaVersion() {
+		t.Errorf("Load() = %+v", conf)
+	}
+
+	// Loading upgrades the config in memory only
+	stored, err := s.LoadFile(client.BucketName, configFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(stored) != string(original) {
+		t.Errorf("Load() stored the upgraded config: %s", stored)
+	}
+	if exists, _ := s.HasFile(client.BucketName, ConfigBackupFilename(0)); exists {
+		t.Errorf("Load() took a backup of the config")
+	}
+
+	if err = client.UpgradeConfig(); err != nil {
+		t.Fatalf("UpgradeConfig() error = %v", err)
+	}
+
+	backup, err := s.LoadFile(client.BucketName, ConfigBackupFilename(0))
+	if err != nil {
+		t.Fatalf("loading backup error = %v", err)
+	}
+	if string(backup) != string(original) {
+		t.Errorf("backup = %s, want %s", backup, original)
+	}
+
+	stored, err = s.LoadFile(client.BucketName, configFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var storedConf Config
+	if err = json.Unmarshal(stored, &storedConf); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(storedConf, conf) {
+		t.Errorf("stored config = %+v, want %+v", storedConf, conf)
+	}
+
+	// Upgrading the upgraded config leaves it and the backup alone
+	if err = s.WriteFile(client.BucketName, ConfigBackupFilename(0), []byte("untouched")); err != nil {
+		t.Fatal(err)
+	}
+	if err = client.UpgradeConfig(); err != nil {
+		t.Fatalf("UpgradeConfig() error = %v", err)
+	}
+	backup, _ = s.LoadFile(client.BucketName, ConfigBackupFilename(0))
+	if string(backup) != "untouched" {
+		t.Errorf("backup was rewritten when the config was already current")
+	}
+}
+
+func TestLoadRejectsNewerSchema(t *testing.T) {
+	dir, err := ioutil.TempDir("", "migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := store.NewLocal(dir)
+	client := &Client{Store: s, BucketName: "control-tower-test-eu-west-1-config"}
+	if err = s.CreateBucket(client.BucketName); err != nil {
+		t.Fatal(err)
+	}
+
+	newer := []byte(`{"schema_version":999,"project":"test","field_from_the_future":"value"}`)
+	if err = s.WriteFile(client.BucketName, configFilePath, newer); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = client.Load(); err == nil || !strings.Contains(err.Error(), "upgrade control-tower") {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	stored, _ := s.LoadFile(client.BucketName, configFilePath)
+	if string(stored) != string(newer) {
+		t.Errorf("config written by a newer schema was modified")
+	}
+}
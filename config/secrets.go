@@ -0,0 +1,78 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const secretTag = "secret"
+
+// RedactedValue replaces the value of secret fields in redacted configs
+const RedactedValue = "<redacted>"
+
+// Redacted returns a copy of the config with the value of every secret field replaced by RedactedValue.
+// Secrets that are not set are left empty.
+func (c Config) Redacted() Config {
+	value := reflect.ValueOf(&c).Elem()
+	for _, field := range secretFields() {
+		if value.Field(field.index).String() != "" {
+			value.Field(field.index).SetString(RedactedValue)
+		}
+	}
+	return c
+}
+
+// Secrets returns the secret fields belonging to component keyed by their JSON name, or every secret field
+// when component is empty. Secrets that are not set are omitted.
+func (c Config) Secrets(component string) map[string]string {
+	value := reflect.ValueOf(c)
+	secrets := map[string]string{}
+	for _, field := range secretFields() {
+		if component != "" && field.component != component {
+			continue
+		}
+		if s := value.Field(field.index).String(); s != "" {
+			secrets[field.name] = s
+		}
+	}
+	return secrets
+}
+
+// SecretComponents returns the components that config fields hold secrets for
+func SecretComponents() []string {
+	seen := map[string]bool{}
+	components := []string{}
+	for _, field := range secretFields() {
+		if !seen[field.component] {
+			seen[field.component] = true
+			components = append(components, field.component)
+		}
+	}
+	sort.Strings(components)
+	return components
+}
+
+type secretField struct {
+	index     int
+	name      string
+	component string
+}
+
+// secretFields returns every field of Config with a secret tag
+func secretFields() []secretField {
+	t := reflect.TypeOf(Config{})
+	fields := []secretField{}
+	for i := 0; i < t.NumField(); i++ {
+		component, ok := t.Field(i).Tag.Lookup(secretTag)
+		if !ok {
+			continue
+		}
+		fields = append(fields, secretField{
+			index:     i,
+			name:      strings.Split(t.Field(i).Tag.Get("json"), ",")[0],
+			component: component,
+		})
+	}
+	return fields
+}
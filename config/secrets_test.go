@@ -0,0 +1,104 @@
+package config_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/EngineerBetter/control-tower/config"
+)
+
+// configWithEveryField returns a config with every string field set to a value naming the field
+func configWithEveryField() Config {
+	var conf Config
+	value := reflect.ValueOf(&conf).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		if value.Field(i).Kind() == reflect.String {
+			value.Field(i).SetString("value-of-" + value.Type().Field(i).Name)
+		}
+	}
+	return conf
+}
+
+func TestSecretFieldsAreTagged(t *testing.T) {
+	notSecret := map[string]bool{"PublicKey": true}
+
+	configType := reflect.TypeOf(Config{})
+	for i := 0; i < configType.NumField(); i++ {
+		field := configType.Field(i)
+		_, tagged := field.Tag.Lookup("secret")
+
+		if tagged && field.Type.Kind() != reflect.String {
+			t.Errorf("%s is tagged secret but is not a string", field.Name)
+		}
+
+		looksSecret := strings.Contains(field.Name, "Password") || strings.Contains(field.Name, "Secret") || strings.HasSuffix(field.Name, "Key")
+		if looksSecret && !tagged && !notSecret[field.Name] {
+			t.Errorf("%s looks like it holds a secret but is not tagged secret", field.Name)
+		}
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	conf := configWithEveryField()
+	redacted := conf.Redacted()
+
+	redactedJSON, err := json.Marshal(redacted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secrets := conf.Secrets("")
+	if len(secrets) == 0 {
+		t.Fatal("no secrets found")
+	}
+	for name, secret := range secrets {
+		if strings.Contains(string(redactedJSON), secret) {
+			t.Errorf("redacted config contains %s", name)
+		}
+	}
+
+	if redacted.DirectorPassword != RedactedValue {
+		t.Errorf("DirectorPassword = %s, want %s", redacted.DirectorPassword, RedactedValue)
+	}
+	if redacted.DirectorUsername != conf.DirectorUsername || redacted.PublicKey != conf.PublicKey {
+		t.Errorf("Redacted() changed fields which are not secret")
+	}
+	if conf.DirectorPassword == RedactedValue {
+		t.Errorf("Redacted() modified the original config")
+	}
+
+	if empty := (Config{}).Redacted(); empty.DirectorPassword != "" {
+		t.Errorf("Redacted() of an unset secret = %s, want empty", empty.DirectorPassword)
+	}
+}
+
+func TestSecrets(t *testing.T) {
+	conf := configWithEveryField()
+
+	got := conf.Secrets("credhub")
+	want := map[string]string{
+		"credhub_admin_client_secret": "value-of-CredhubAdminClientSecret",
+		"credhub_password":            "value-of-CredhubPassword",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Secrets(credhub) = %v, want %v", got, want)
+	}
+
+	total := 0
+	for _, component := range SecretComponents() {
+		total += len(conf.Secrets(component))
+	}
+	if total != len(conf.Secrets("")) {
+		t.Errorf("components hold %d secrets, want %d", total, len(conf.Secrets("")))
+	}
+
+	if got := (Config{}).Secrets("bosh"); len(got) != 0 {
+		t.Errorf("Secrets() of an empty config = %v", got)
+	}
+
+	if components := SecretComponents(); !reflect.DeepEqual(components, []string{"bosh", "concourse", "credhub", "database", "grafana"}) {
+		t.Errorf("SecretComponents() = %v", components)
+	}
+}
@@ -0,0 +1,86 @@
+package encryption
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+)
+
+// AWSKMSKeyProvider wraps data keys with an AWS KMS key
+type AWSKMSKeyProvider struct {
+	keyID string
+	kms   *kms.KMS
+}
+
+// NewAWSKMS returns an AWSKMSKeyProvider for keyID, which may be a key ID, alias or ARN.
+// The region of an ARN takes precedence over region.
+func NewAWSKMS(keyID, region string) (*AWSKMSKeyProvider, error) {
+	if arnRegion := regionFromARN(keyID); arnRegion != "" {
+		region = arnRegion
+	}
+	if region == "" {
+		return nil, fmt.Errorf("cannot determine the region of KMS key [%s]", keyID)
+	}
+
+	sess, err := session.NewSession(&aws.Config{Region: &region})
+	if err != nil {
+		return nil, err
+	}
+
+	return &AWSKMSKeyProvider{
+		keyID: keyID,
+		kms:   kms.New(sess),
+	}, nil
+}
+
+// Name returns the name of the provider
+func (p *AWSKMSKeyProvider) Name() string {
+	return AWSKMS
+}
+
+// KeyID returns the ARN of the key once a data key has been wrapped, and the key ID it was created with before that
+func (p *AWSKMSKeyProvider) KeyID() string {
+	return p.keyID
+}
+
+// WrapKey encrypts dataKey with the KMS key
+func (p *AWSKMSKeyProvider) WrapKey(dataKey []byte) ([]byte, error) {
+	output, err := p.kms.Encrypt(&kms.EncryptInput{
+		KeyId:     aws.String(p.keyID),
+		Plaintext: dataKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Record the ARN so that the region can be found again when decrypting
+	p.keyID = aws.StringValue(output.KeyId)
+	return output.CiphertextBlob, nil
+}
+
+// UnwrapKey decrypts a data key wrapped by WrapKey
+func (p *AWSKMSKeyProvider) UnwrapKey(wrappedKey []byte) ([]byte, error) {
+	output, err := p.kms.Decrypt(&kms.DecryptInput{
+		CiphertextBlob: wrappedKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(output.Plaintext) != dataKeySize {
+		return nil, errors.New("unexpected data key length")
+	}
+	return output.Plaintext, nil
+}
+
+// regionFromARN returns the region of arn:aws:kms:<region>:<account>:key/<id>, or an empty string if keyID is not an ARN
+func regionFromARN(keyID string) string {
+	parts := strings.Split(keyID, ":")
+	if len(parts) < 6 || parts[0] != "arn" || parts[2] != "kms" {
+		return ""
+	}
+	return parts[3]
+}
@@ -0,0 +1,165 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Names of the supported key providers, accepted by --encryption
+const (
+	AWSKMS     = "aws-kms"
+	GCPKMS     = "gcp-kms"
+	Passphrase = "passphrase"
+)
+
+// Providers lists every supported key provider
+var Providers = []string{AWSKMS, GCPKMS, Passphrase}
+
+// header marks an object as an envelope so that plaintext objects written before encryption was enabled can still be read
+var header = []byte("control-tower-envelope-v1\n")
+
+const dataKeySize = 32
+
+// KeyProvider wraps and unwraps the data keys which encrypt each object
+type KeyProvider interface {
+	Name() string
+	KeyID() string
+	WrapKey(dataKey []byte) ([]byte, error)
+	UnwrapKey(wrappedKey []byte) ([]byte, error)
+}
+
+// Resolver returns the KeyProvider which can unwrap data keys wrapped by the named provider with keyID
+type Resolver func(name, keyID string) (KeyProvider, error)
+
+type envelope struct {
+	KeyProvider string `json:"key_provider"`
+	KeyID       string `json:"key_id"`
+	WrappedKey  []byte `json:"wrapped_key"`
+	Ciphertext  []byte `json:"ciphertext"`
+}
+
+// New returns the named KeyProvider. region is used by AWS KMS when keyID is not an ARN.
+func New(name, keyID, region string) (KeyProvider, error) {
+	switch name {
+	case AWSKMS:
+		if keyID == "" {
+			return nil, errors.New("--encryption-key-id is required for AWS KMS")
+		}
+		return NewAWSKMS(keyID, region)
+	case GCPKMS:
+		if keyID == "" {
+			return nil, errors.New("--encryption-key-id is required for Cloud KMS")
+		}
+		return NewGCPKMS(keyID)
+	case Passphrase:
+		return NewPassphraseFromEnv()
+	}
+
+	return nil, fmt.Errorf("unknown encryption key provider [%s], must be one of %v", name, Providers)
+}
+
+// IsEncrypted returns true if data is an envelope produced by Encrypt
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, header)
+}
+
+// Encrypt seals plaintext with a new data key, which is itself wrapped by kp and stored alongside
+func Encrypt(kp KeyProvider, plaintext []byte) ([]byte, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := seal(dataKey, plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	wrappedKey, err := kp.WrapKey(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("error wrapping data key with %s: [%v]", kp.Name(), err)
+	}
+
+	contents, err := json.Marshal(envelope{
+		KeyProvider: kp.Name(),
+		KeyID:       kp.KeyID(),
+		WrappedKey:  wrappedKey,
+		Ciphertext:  ciphertext,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return append(append([]byte{}, header...), contents...), nil
+}
+
+// Decrypt opens an envelope produced by Encrypt, returning the plaintext and the KeyProvider that unwrapped its data key
+func Decrypt(data []byte, resolve Resolver) ([]byte, KeyProvider, error) {
+	if !IsEncrypted(data) {
+		return nil, nil, errors.New("data is not encrypted")
+	}
+
+	var e envelope
+	if err := json.Unmarshal(data[len(header):], &e); err != nil {
+		return nil, nil, fmt.Errorf("error parsing envelope: [%v]", err)
+	}
+
+	kp, err := resolve(e.KeyProvider, e.KeyID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dataKey, err := kp.UnwrapKey(e.WrappedKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error unwrapping data key with %s: [%v]", e.KeyProvider, err)
+	}
+
+	plaintext, err := open(dataKey, e.Ciphertext)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error decrypting: [%v]", err)
+	}
+
+	return plaintext, kp, nil
+}
+
+func seal(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(key, ciphertext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, sealed, nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
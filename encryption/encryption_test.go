@@ -0,0 +1,179 @@
+package encryption_test
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/EngineerBetter/control-tower/encryption"
+)
+
+// xorKeyProvider is a trivially reversible KeyProvider for testing the envelope format
+type xorKeyProvider struct {
+	keyID string
+}
+
+func (p *xorKeyProvider) Name() string  { return "xor" }
+func (p *xorKeyProvider) KeyID() string { return p.keyID }
+func (p *xorKeyProvider) WrapKey(dataKey []byte) ([]byte, error) {
+	wrapped := make([]byte, len(dataKey))
+	for i, b := range dataKey {
+		wrapped[i] = b ^ 0xff
+	}
+	return wrapped, nil
+}
+func (p *xorKeyProvider) UnwrapKey(wrappedKey []byte) ([]byte, error) {
+	return p.WrapKey(wrappedKey)
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	kp := &xorKeyProvider{keyID: "key-1"}
+	plaintext := []byte(`{"director_password":"secret"}`)
+
+	sealed, err := encryption.Encrypt(kp, plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if !encryption.IsEncrypted(sealed) {
+		t.Errorf("IsEncrypted() = false for an envelope")
+	}
+	if bytes.Contains(sealed, []byte("secret")) {
+		t.Errorf("envelope contains the plaintext")
+	}
+
+	var resolvedName, resolvedKeyID string
+	opened, resolved, err := encryption.Decrypt(sealed, func(name, keyID string) (encryption.KeyProvider, error) {
+		resolvedName, resolvedKeyID = name, keyID
+		return kp, nil
+	})
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if !bytes.Equal(opened, plaintext) {
+		t.Errorf("Decrypt() = %s, want %s", opened, plaintext)
+	}
+	if resolvedName != "xor" || resolvedKeyID != "key-1" || resolved != kp {
+		t.Errorf("resolved %s/%s to %v", resolvedName, resolvedKeyID, resolved)
+	}
+}
+
+func TestDecrypt_Errors(t *testing.T) {
+	kp := &xorKeyProvider{}
+	sealed, err := encryption.Encrypt(kp, []byte("plaintext"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resolve := func(name, keyID string) (encryption.KeyProvider, error) { return kp, nil }
+
+	if _, _, err = encryption.Decrypt([]byte("plaintext"), resolve); err == nil {
+		t.Error("Decrypt() of plaintext should fail")
+	}
+
+	tampered := append([]byte{}, sealed...)
+	tampered[len(tampered)-5] ^= 0x01
+	if _, _, err = encryption.Decrypt(tampered, resolve); err == nil {
+		t.Error("Decrypt() of a tampered envelope should fail")
+	}
+
+	_, _, err = encryption.Decrypt(sealed, func(name, keyID string) (encryption.KeyProvider, error) {
+		return nil, errors.New("no such key")
+	})
+	if err == nil || err.Error() != "no such key" {
+		t.Errorf("Decrypt() error = %v, want the resolver's error", err)
+	}
+}
+
+func TestDecryptLegacyBackup(t *testing.T) {
+	key := sha256.Sum256([]byte("atc-encryption-key"))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	ciphertext := gcm.Seal(nonce, nonce, []byte("archive"), nil)
+
+	plaintext, err := encryption.DecryptLegacyBackup("atc-encryption-key", ciphertext)
+	if err != nil || string(plaintext) != "archive" {
+		t.Errorf("DecryptLegacyBackup() = %q, %v", plaintext, err)
+	}
+	if _, err = encryption.DecryptLegacyBackup("wrong", ciphertext); err == nil {
+		t.Errorf("DecryptLegacyBackup() with the wrong key should fail")
+	}
+}
+
+func TestPassphraseKeyProvider(t *testing.T) {
+	kp, err := encryption.NewPassphrase("correct horse battery staple")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sealed, err := encryption.Encrypt(kp, []byte("plaintext"))
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	opened, _, err := encryption.Decrypt(sealed, func(name, keyID string) (encryption.KeyProvider, error) {
+		if name != encryption.Passphrase {
+			t.Errorf("envelope names key provider %s", name)
+		}
+		return kp, nil
+	})
+	if err != nil || string(opened) != "plaintext" {
+		t.Fatalf("Decrypt() = %s, %v", opened, err)
+	}
+
+	wrong, _ := encryption.NewPassphrase("wrong")
+	_, _, err = encryption.Decrypt(sealed, func(name, keyID string) (encryption.KeyProvider, error) { return wrong, nil })
+	if err == nil || !strings.Contains(err.Error(), "incorrect passphrase") {
+		t.Errorf("Decrypt() with the wrong passphrase error = %v", err)
+	}
+}
+
+func TestNew(t *testing.T) {
+	os.Unsetenv("ENCRYPTION_PASSPHRASE")
+
+	tests := []struct {
+		name    string
+		keyID   string
+		wantErr string
+	}{
+		{name: encryption.Passphrase, wantErr: "ENCRYPTION_PASSPHRASE must be set"},
+		{name: encryption.AWSKMS, wantErr: "--encryption-key-id is required"},
+		{name: encryption.AWSKMS, keyID: "alias/control-tower", wantErr: "cannot determine the region"},
+		{name: encryption.GCPKMS, wantErr: "--encryption-key-id is required"},
+		{name: encryption.GCPKMS, keyID: "my-key", wantErr: "must be a resource name"},
+		{name: "rot13", wantErr: "unknown encryption key provider [rot13]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+" "+tt.keyID, func(t *testing.T) {
+			_, err := encryption.New(tt.name, tt.keyID, "")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("New() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	kp, err := encryption.New(encryption.AWSKMS, "arn:aws:kms:us-east-1:123456789012:key/abcd", "")
+	if err != nil {
+		t.Fatalf("New() with an ARN error = %v", err)
+	}
+	if kp.KeyID() != "arn:aws:kms:us-east-1:123456789012:key/abcd" {
+		t.Errorf("KeyID() = %s", kp.KeyID())
+	}
+
+	os.Setenv("ENCRYPTION_PASSPHRASE", "passphrase")
+	defer os.Unsetenv("ENCRYPTION_PASSPHRASE")
+	if _, err = encryption.New(encryption.Passphrase, "", ""); err != nil {
+		t.Errorf("New() with ENCRYPTION_PASSPHRASE error = %v", err)
+	}
+}
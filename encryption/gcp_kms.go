@@ -0,0 +1,74 @@
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	cloudkms "google.golang.org/api/cloudkms/v1"
+)
+
+// GCPKMSKeyProvider wraps data keys with a Cloud KMS crypto key
+type GCPKMSKeyProvider struct {
+	keyName string
+	kms     *cloudkms.Service
+}
+
+// NewGCPKMS returns a GCPKMSKeyProvider for the crypto key with the resource name
+// projects/<project>/locations/<location>/keyRings/<ring>/cryptoKeys/<key>, using GOOGLE_APPLICATION_CREDENTIALS
+func NewGCPKMS(keyName string) (*GCPKMSKeyProvider, error) {
+	if !strings.HasPrefix(keyName, "projects/") || !strings.Contains(keyName, "/cryptoKeys/") {
+		return nil, errors.New("Cloud KMS key must be a resource name like projects/<project>/locations/<location>/keyRings/<ring>/cryptoKeys/<key>")
+	}
+
+	service, err := cloudkms.NewService(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return &GCPKMSKeyProvider{
+		keyName: keyName,
+		kms:     service,
+	}, nil
+}
+
+// Name returns the name of the provider
+func (p *GCPKMSKeyProvider) Name() string {
+	return GCPKMS
+}
+
+// KeyID returns the resource name of the crypto key
+func (p *GCPKMSKeyProvider) KeyID() string {
+	return p.keyName
+}
+
+// WrapKey encrypts dataKey with the primary version of the crypto key
+func (p *GCPKMSKeyProvider) WrapKey(dataKey []byte) ([]byte, error) {
+	response, err := p.kms.Projects.Locations.KeyRings.CryptoKeys.Encrypt(p.keyName, &cloudkms.EncryptRequest{
+		Plaintext: base64.StdEncoding.EncodeToString(dataKey),
+	}).Do()
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(response.Ciphertext)
+}
+
+// UnwrapKey decrypts a data key wrapped by WrapKey
+func (p *GCPKMSKeyProvider) UnwrapKey(wrappedKey []byte) ([]byte, error) {
+	response, err := p.kms.Projects.Locations.KeyRings.CryptoKeys.Decrypt(p.keyName, &cloudkms.DecryptRequest{
+		Ciphertext: base64.StdEncoding.EncodeToString(wrappedKey),
+	}).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := base64.StdEncoding.DecodeString(response.Plaintext)
+	if err != nil {
+		return nil, err
+	}
+	if len(dataKey) != dataKeySize {
+		return nil, errors.New("unexpected data key length")
+	}
+	return dataKey, nil
+}
@@ -0,0 +1,10 @@
+package encryption
+
+import "crypto/sha256"
+
+// DecryptLegacyBackup opens a database backup archive taken before backups were encrypted with a KeyProvider,
+// which sealed it with a key hashed from the deployment's ATC encryption key. Nothing is encrypted this way any more.
+func DecryptLegacyBackup(passphrase string, ciphertext []byte) ([]byte, error) {
+	key := sha256.Sum256([]byte(passphrase))
+	return open(key[:], ciphertext)
+}
@@ -0,0 +1,86 @@
+package encryption
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"os"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const saltSize = 16
+
+// PassphraseKeyProvider wraps data keys with a key derived from a passphrase. It is intended for
+// local testing, where no KMS is available.
+type PassphraseKeyProvider struct {
+	passphrase string
+}
+
+// NewPassphrase returns a PassphraseKeyProvider for passphrase
+func NewPassphrase(passphrase string) (*PassphraseKeyProvider, error) {
+	if passphrase == "" {
+		return nil, errors.New("passphrase must not be empty")
+	}
+	return &PassphraseKeyProvider{passphrase: passphrase}, nil
+}
+
+// NewPassphraseFromEnv returns a PassphraseKeyProvider for the passphrase in ENCRYPTION_PASSPHRASE
+func NewPassphraseFromEnv() (*PassphraseKeyProvider, error) {
+	passphrase := os.Getenv("ENCRYPTION_PASSPHRASE")
+	if passphrase == "" {
+		return nil, errors.New("ENCRYPTION_PASSPHRASE must be set to use passphrase encryption")
+	}
+	return NewPassphrase(passphrase)
+}
+
+// Name returns the name of the provider
+func (p *PassphraseKeyProvider) Name() string {
+	return Passphrase
+}
+
+// KeyID is empty as the passphrase is never stored
+func (p *PassphraseKeyProvider) KeyID() string {
+	return ""
+}
+
+// WrapKey seals dataKey with a key derived from the passphrase and a new salt, which prefixes the result
+func (p *PassphraseKeyProvider) WrapKey(dataKey []byte) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+
+	kek, err := p.deriveKey(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := seal(kek, dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return append(salt, sealed...), nil
+}
+
+// UnwrapKey opens a data key sealed by WrapKey
+func (p *PassphraseKeyProvider) UnwrapKey(wrappedKey []byte) ([]byte, error) {
+	if len(wrappedKey) < saltSize {
+		return nil, errors.New("wrapped key is too short")
+	}
+
+	kek, err := p.deriveKey(wrappedKey[:saltSize])
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := open(kek, wrappedKey[saltSize:])
+	if err != nil {
+		return nil, errors.New("incorrect passphrase")
+	}
+	return dataKey, nil
+}
+
+func (p *PassphraseKeyProvider) deriveKey(salt []byte) ([]byte, error) {
+	return scrypt.Key([]byte(p.passphrase), salt, 1<<15, 8, 1, dataKeySize)
+}
@@ -0,0 +1,182 @@
+// Package events reports the progress of a command as a stream of newline delimited JSON objects, for
+// CI pipelines that need to know which phase of a command failed and how long each took.
+// Nothing is reported until Start is called, so the text output of commands is unaffected by default.
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// Formats of the progress output of commands
+const (
+	TextFormat = "text"
+	JSONFormat = "json"
+)
+
+// Formats are the permitted values of --output-format
+var Formats = []string{TextFormat, JSONFormat}
+
+// Types of event
+const (
+	PhaseStartEvent = "phase_start"
+	PhaseEndEvent   = "phase_end"
+	WarningEvent    = "warning"
+	ErrorEvent      = "error"
+	ResultEvent     = "result"
+)
+
+// Statuses of phase_end and result events
+const (
+	Succeeded = "succeeded"
+	Failed    = "failed"
+	Skipped   = "skipped"
+)
+
+// Categories of the error event
+const (
+	// UsageCategory is for invalid flags or arguments
+	UsageCategory = "usage"
+	// LockCategory is for failing to take the deployment lock, usually because another command holds it
+	LockCategory = "lock"
+	// InterruptedCategory is for commands stopped by Ctrl-C or SIGTERM
+	InterruptedCategory = "interrupted"
+	// PhaseCategory is for a phase that failed, which is named by the phase field
+	PhaseCategory = "phase"
+	// OtherCategory is for any other error
+	OtherCategory = "other"
+)
+
+// Event is a single line of the json output format
+type Event struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`
+	Command string    `json:"command"`
+	Phase   string    `json:"phase,omitempty"`
+	Status  string    `json:"status,omitempty"`
+	// DurationSeconds is set on phase_end and result events
+	DurationSeconds *float64 `json:"duration_seconds,omitempty"`
+	Message         string   `json:"message,omitempty"`
+	Category        string   `json:"category,omitempty"`
+}
+
+// Error is an error with the category reported in its error event
+type Error struct {
+	Category string
+	Err      error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the categorised error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Categorize returns err with the category reported in its error event
+func Categorize(err error, category string) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Category: category, Err: err}
+}
+
+type stream struct {
+	sync.Mutex
+	w       io.Writer
+	command string
+	started time.Time
+	// failedPhase is the last phase to fail, which is blamed for the error the command returns
+	failedPhase string
+}
+
+var (
+	current *stream
+	now     = time.Now
+)
+
+// Start begins reporting the events of command to w
+func Start(w io.Writer, command string) {
+	current = &stream{w: w, command: command, started: now()}
+}
+
+// Phase reports the start of phase, runs it, then reports how it ended
+func Phase(phase string, run func() error) error {
+	started := now()
+	emit(Event{Type: PhaseStartEvent, Phase: phase})
+	err := run()
+
+	end := Event{Type: PhaseEndEvent, Phase: phase, Status: Succeeded, DurationSeconds: since(started)}
+	if err != nil {
+		end.Status = Failed
+		end.Message = err.Error()
+		if current != nil {
+			current.failedPhase = phase
+		}
+	}
+	emit(end)
+	return err
+}
+
+// PhaseSkipped reports that phase did not need to run
+func PhaseSkipped(phase, reason string) {
+	emit(Event{Type: PhaseEndEvent, Phase: phase, Status: Skipped, Message: reason})
+}
+
+// Warning reports something the user should know about that did not stop the command
+func Warning(message string) {
+	emit(Event{Type: WarningEvent, Message: message})
+}
+
+// Finish reports err, if there was one, and the result of the command, then stops reporting events
+func Finish(err error) {
+	if current == nil {
+		return
+	}
+
+	result := Event{Type: ResultEvent, Status: Succeeded, DurationSeconds: since(current.started)}
+	if err != nil {
+		emit(errorEvent(err))
+		result.Status = Failed
+		result.Message = err.Error()
+	}
+	emit(result)
+	current = nil
+}
+
+func errorEvent(err error) Event {
+	event := Event{Type: ErrorEvent, Message: err.Error(), Category: OtherCategory, Phase: current.failedPhase}
+	var categorized *Error
+	if errors.As(err, &categorized) {
+		event.Category = categorized.Category
+	} else if current.failedPhase != "" {
+		event.Category = PhaseCategory
+	}
+	return event
+}
+
+func emit(event Event) {
+	if current == nil {
+		return
+	}
+	current.Lock()
+	defer current.Unlock()
+
+	event.Time = now().UTC()
+	event.Command = current.command
+	eventBytes, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	current.w.Write(append(eventBytes, '\n'))
+}
+
+func since(started time.Time) *float64 {
+	seconds := now().Sub(started).Seconds()
+	return &seconds
+}
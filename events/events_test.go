@@ -0,0 +1,127 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func readEvents(t *testing.T, buf *bytes.Buffer) []Event {
+	decoder := json.NewDecoder(buf)
+	result := []Event{}
+	for decoder.More() {
+		var event Event
+		if err := decoder.Decode(&event); err != nil {
+			t.Fatalf("output is not newline delimited JSON: %v", err)
+		}
+		result = append(result, event)
+	}
+	return result
+}
+
+// withClock stops time, apart from when the returned function moves it on
+func withClock(t *testing.T) func(time.Duration) {
+	clock := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	now = func() time.Time { return clock }
+	t.Cleanup(func() { now = time.Now })
+	return func(d time.Duration) { clock = clock.Add(d) }
+}
+
+func TestEvents(t *testing.T) {
+	advance := withClock(t)
+	buf := &bytes.Buffer{}
+
+	Start(buf, "deploy")
+	PhaseSkipped("terraform", "completed during a previous deploy")
+	Warning("adding record ci.example.com to DNS zone example.com")
+	err := Phase("certs", func() error {
+		advance(3 * time.Second)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Phase() error = %v", err)
+	}
+	phaseErr := errors.New("create-env failed")
+	if err := Phase("create-env", func() error { return phaseErr }); err != phaseErr {
+		t.Fatalf("Phase() error = %v, want %v", err, phaseErr)
+	}
+	Finish(fmt.Errorf("deploy failed: [%v]", phaseErr))
+
+	got := readEvents(t, buf)
+	types := []string{}
+	for _, event := range got {
+		if event.Command != "deploy" {
+			t.Errorf("%s event command = %s", event.Type, event.Command)
+		}
+		types = append(types, event.Type+" "+event.Phase+" "+event.Status)
+	}
+	want := []string{
+		"phase_end terraform skipped",
+		"warning  ",
+		"phase_start certs ",
+		"phase_end certs succeeded",
+		"phase_start create-env ",
+		"phase_end create-env failed",
+		"error create-env ",
+		"result  failed",
+	}
+	if !reflect.DeepEqual(types, want) {
+		t.Fatalf("events = %v, want %v", types, want)
+	}
+
+	if got[3].DurationSeconds == nil || *got[3].DurationSeconds != 3 {
+		t.Errorf("certs duration = %v, want 3", got[3].DurationSeconds)
+	}
+	if got[6].Category != PhaseCategory || got[6].Message != "deploy failed: [create-env failed]" {
+		t.Errorf("error event = %+v", got[6])
+	}
+	if got[7].DurationSeconds == nil || *got[7].DurationSeconds != 3 {
+		t.Errorf("result duration = %v", got[7].DurationSeconds)
+	}
+}
+
+func TestFinishCategorizesErrors(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	Start(buf, "destroy")
+	Finish(fmt.Errorf("wrapped: %w", Categorize(errors.New("deployment is locked"), LockCategory)))
+	Start(buf, "destroy")
+	Finish(errors.New("something else"))
+	Start(buf, "destroy")
+	Finish(nil)
+
+	got := readEvents(t, buf)
+	if len(got) != 5 {
+		t.Fatalf("got %d events, want 5", len(got))
+	}
+	if got[0].Category != LockCategory || got[0].Message != "wrapped: deployment is locked" {
+		t.Errorf("categorized error event = %+v", got[0])
+	}
+	if got[2].Category != OtherCategory {
+		t.Errorf("uncategorized error event = %+v", got[2])
+	}
+	if got[4].Type != ResultEvent || got[4].Status != Succeeded {
+		t.Errorf("result event = %+v", got[4])
+	}
+}
+
+func TestNothingReportedUntilStarted(t *testing.T) {
+	ran := false
+	err := Phase("terraform", func() error {
+		ran = true
+		return nil
+	})
+	Warning("not reported")
+	Finish(errors.New("not reported"))
+
+	if err != nil || !ran {
+		t.Errorf("Phase() ran = %v, error = %v", ran, err)
+	}
+	if current != nil {
+		t.Errorf("Finish() started reporting events")
+	}
+}
@@ -3,6 +3,7 @@ package fly
 import (
 	"strings"
 
+	"github.com/EngineerBetter/control-tower/config"
 	"github.com/aws/aws-sdk-go/aws/session"
 )
 
@@ -36,7 +37,7 @@ var getCredsFromSession = func() (string, string, error) {
 }
 
 //BuildPipelineParams builds params for AWS control-tower self update pipeline
-func (a AWSPipeline) BuildPipelineParams(deployment, namespace, region, domain, iaas string) (Pipeline, error) {
+func (a AWSPipeline) BuildPipelineParams(deployment, namespace, region, domain, iaas string, workerSchedule *config.WorkerSchedule, autoscale *config.Autoscale, stateBackend *StateBackend) (Pipeline, error) {
 	accessKeyID, secretAccessKey, err := a.credsGetter()
 	if err != nil {
 		return nil, err
@@ -50,6 +51,9 @@ func (a AWSPipeline) BuildPipelineParams(deployment, namespace, region, domain,
 			Namespace:           namespace,
 			Region:              region,
 			IaaS:                iaas,
+			WorkerSchedule:      workerSchedule,
+			Autoscale:           autoscale,
+			StateBackend:        stateBackend,
 		},
 		AWSAccessKeyID:     accessKeyID,
 		AWSSecretAccessKey: secretAccessKey,
@@ -62,8 +66,8 @@ func (a AWSPipeline) GetConfigTemplate() string {
 
 }
 
-const awsPipelineTemplate = `
----` + selfUpdateResources + `
+var awsPipelineTemplate = `
+---{{ if .WorkerSchedule }}` + workerScheduleResourceTypes + `{{ end }}` + selfUpdateResources + `{{ if .WorkerSchedule }}` + workerScheduleResources + `{{ end }}{{ if .Autoscale }}` + autoscaleResources + `{{ end }}
 jobs:
 - name: self-update
   serial_groups: [cup]
@@ -80,7 +84,7 @@ jobs:
       IAAS: "{{ .IaaS }}"
       NAMESPACE: "{{ .Namespace }}"
       SELF_UPDATE: true
-    config:
+` + stateBackendTaskParams + `    config:
       platform: linux
       image_resource:
         type: docker-image
@@ -96,7 +100,7 @@ jobs:
           set -eux
 
           cd control-tower-release
-          chmod +x control-tower-linux-amd64
+` + stateBackendTaskSetup + `          chmod +x control-tower-linux-amd64
           ./control-tower-linux-amd64 deploy $DEPLOYMENT
 - name: renew-https-cert
   serial_groups: [cup]
@@ -115,7 +119,7 @@ jobs:
       IAAS: "{{ .IaaS }}"
       NAMESPACE: "{{ .Namespace }}"
       SELF_UPDATE: true
-    config:
+` + stateBackendTaskParams + `    config:
       platform: linux
       image_resource:
         type: docker-image
@@ -130,8 +134,21 @@ jobs:
         - |
           set -euxo pipefail
           cd control-tower-release
-          chmod +x control-tower-linux-amd64
+` + stateBackendTaskSetup + `          chmod +x control-tower-linux-amd64
 ` + renewCertsDateCheck + `
           echo Certificates expire in $days_until_expiry days, redeploying to renew them
           ./control-tower-linux-amd64 deploy $DEPLOYMENT
+{{ if .WorkerSchedule }}` + workerScheduleJobs(awsScheduleTaskParams, awsScheduleTaskSetup) + `{{ end }}{{ if .Autoscale }}` + autoscaleJob(awsScheduleTaskParams, awsScheduleTaskSetup) + `{{ end }}`
+
+const awsScheduleTaskParams = `      AWS_ACCESS_KEY_ID: "{{ .AWSAccessKeyID }}"
+      AWS_REGION: "{{ .Region }}"
+      AWS_SECRET_ACCESS_KEY: "{{ .AWSSecretAccessKey }}"
+      DEPLOYMENT: "{{ .Deployment }}"
+      IAAS: "{{ .IaaS }}"
+      NAMESPACE: "{{ .Namespace }}"
+` + stateBackendTaskParams
+
+const awsScheduleTaskSetup = `          set -eux
+          cd control-tower-release
+` + stateBackendTaskSetup + `          chmod +x control-tower-linux-amd64
 `
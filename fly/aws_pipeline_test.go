@@ -1,8 +1,10 @@
 package fly_test
 
 import (
+	"github.com/EngineerBetter/control-tower/config"
 	. "github.com/EngineerBetter/control-tower/fly"
 	"github.com/EngineerBetter/control-tower/util"
+	"gopkg.in/yaml.v2"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -114,7 +116,7 @@ jobs:
 
 			pipeline := NewAWSPipeline(fakeCredsGetter)
 
-			params, err := pipeline.BuildPipelineParams("my-deployment", "prod", "eu-west-1", "ci.engineerbetter.com", "AWS")
+			params, err := pipeline.BuildPipelineParams("my-deployment", "prod", "eu-west-1", "ci.engineerbetter.com", "AWS", nil, nil, nil)
 			Expect(err).ToNot(HaveOccurred())
 
 			yamlBytes, err := util.RenderTemplate("self-update pipeline", pipeline.GetConfigTemplate(), params)
@@ -123,6 +125,99 @@ jobs:
 			actual := string(yamlBytes)
 			Expect(actual).To(Equal(expected))
 		})
+
+		It("Adds jobs to scale the workers when there is a worker schedule", func() {
+			fakeCredsGetter := func() (string, string, error) {
+				return "access-key", "secret-key", nil
+			}
+
+			pipeline := NewAWSPipeline(fakeCredsGetter)
+
+			schedule := &config.WorkerSchedule{
+				OffCron:    "0 19 * * 1-5",
+				OnCron:     "0 7 * * 1-5",
+				Timezone:   "Europe/London",
+				OffWorkers: 1,
+				OnWorkers:  6,
+				Tag:        "scheduler",
+			}
+			params, err := pipeline.BuildPipelineParams("my-deployment", "prod", "eu-west-1", "ci.engineerbetter.com", "AWS", schedule, nil, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			yamlBytes, err := util.RenderTemplate("self-update pipeline", pipeline.GetConfigTemplate(), params)
+			Expect(err).ToNot(HaveOccurred())
+
+			var parsed struct {
+				ResourceTypes []struct {
+					Name string `yaml:"name"`
+				} `yaml:"resource_types"`
+				Resources []struct {
+					Name   string            `yaml:"name"`
+					Source map[string]string `yaml:"source"`
+				} `yaml:"resources"`
+				Jobs []struct {
+					Name string `yaml:"name"`
+					Plan []struct {
+						Tags   []string          `yaml:"tags"`
+						Params map[string]string `yaml:"params"`
+					} `yaml:"plan"`
+				} `yaml:"jobs"`
+			}
+			Expect(yaml.Unmarshal(yamlBytes, &parsed)).To(Succeed())
+
+			Expect(parsed.ResourceTypes[0].Name).To(Equal("cron-resource"))
+			Expect(parsed.Resources[2].Name).To(Equal("workers-off-schedule"))
+			Expect(parsed.Resources[2].Source).To(Equal(map[string]string{"expression": "0 19 * * 1-5", "location": "Europe/London"}))
+			Expect(parsed.Resources[3].Name).To(Equal("workers-on-schedule"))
+			Expect(parsed.Jobs[2].Name).To(Equal("workers-off"))
+			Expect(parsed.Jobs[2].Plan[2].Params).To(HaveKeyWithValue("WORKERS", "1"))
+			Expect(parsed.Jobs[2].Plan[2].Params).To(HaveKeyWithValue("AWS_SECRET_ACCESS_KEY", "secret-key"))
+			Expect(parsed.Jobs[3].Name).To(Equal("workers-on"))
+			Expect(parsed.Jobs[3].Plan[2].Params).To(HaveKeyWithValue("WORKERS", "6"))
+			// The jobs must not run on a default worker that workers-off removes
+			Expect(parsed.Jobs[2].Plan[2].Tags).To(Equal([]string{"scheduler"}))
+			Expect(parsed.Jobs[3].Plan[2].Tags).To(Equal([]string{"scheduler"}))
+			Expect(string(yamlBytes)).To(ContainSubstring("./control-tower-linux-amd64 scale $DEPLOYMENT"))
+		})
+
+		It("Adds a job to autoscale the workers when autoscaling is configured", func() {
+			fakeCredsGetter := func() (string, string, error) {
+				return "access-key", "secret-key", nil
+			}
+
+			pipeline := NewAWSPipeline(fakeCredsGetter)
+
+			autoscale := &config.Autoscale{MinWorkers: 2, MaxWorkers: 8, Tag: "autoscaler"}
+			params, err := pipeline.BuildPipelineParams("my-deployment", "prod", "eu-west-1", "ci.engineerbetter.com", "AWS", nil, autoscale, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			yamlBytes, err := util.RenderTemplate("self-update pipeline", pipeline.GetConfigTemplate(), params)
+			Expect(err).ToNot(HaveOccurred())
+
+			var parsed struct {
+				Resources []struct {
+					Name string `yaml:"name"`
+				} `yaml:"resources"`
+				Jobs []struct {
+					Name string `yaml:"name"`
+					Plan []struct {
+						Tags   []string          `yaml:"tags"`
+						Params map[string]string `yaml:"params"`
+					} `yaml:"plan"`
+				} `yaml:"jobs"`
+			}
+			Expect(yaml.Unmarshal(yamlBytes, &parsed)).To(Succeed())
+
+			Expect(parsed.Resources[2].Name).To(Equal("autoscale-interval"))
+			Expect(parsed.Jobs).To(HaveLen(3))
+			Expect(parsed.Jobs[2].Name).To(Equal("autoscale"))
+			Expect(parsed.Jobs[2].Plan[2].Tags).To(Equal([]string{"autoscaler"}))
+			Expect(parsed.Jobs[2].Plan[2].Params).To(HaveKeyWithValue("MIN_WORKERS", "2"))
+			Expect(parsed.Jobs[2].Plan[2].Params).To(HaveKeyWithValue("MAX_WORKERS", "8"))
+			Expect(parsed.Jobs[2].Plan[2].Params).To(HaveKeyWithValue("ONCE", "true"))
+			Expect(parsed.Jobs[2].Plan[2].Params).To(HaveKeyWithValue("AWS_SECRET_ACCESS_KEY", "secret-key"))
+			Expect(string(yamlBytes)).To(ContainSubstring("./control-tower-linux-amd64 autoscale $DEPLOYMENT"))
+		})
 	})
 })
 
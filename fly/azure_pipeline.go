@@ -0,0 +1,144 @@
+package fly
+
+import (
+	"strings"
+
+	"github.com/EngineerBetter/control-tower/config"
+)
+
+// AzurePipeline is Azure specific implementation of Pipeline interface
+type AzurePipeline struct {
+	PipelineTemplateParams
+	ClientID             string
+	ClientSecret         string
+	StorageAccount       string
+	StorageResourceGroup string
+	SubscriptionID       string
+	TenantID             string
+}
+
+// NewAzurePipeline return AzurePipeline
+func NewAzurePipeline(attr func(string) (string, error)) (Pipeline, error) {
+	values := map[string]string{}
+	for _, name := range []string{"subscription_id", "tenant_id", "client_id", "client_secret", "storage_account", "storage_resource_group"} {
+		value, err := attr(name)
+		if err != nil {
+			return nil, err
+		}
+		values[name] = value
+	}
+	return AzurePipeline{
+		ClientID:             values["client_id"],
+		ClientSecret:         values["client_secret"],
+		StorageAccount:       values["storage_account"],
+		StorageResourceGroup: values["storage_resource_group"],
+		SubscriptionID:       values["subscription_id"],
+		TenantID:             values["tenant_id"],
+	}, nil
+}
+
+//BuildPipelineParams builds params for Azure control-tower self update pipeline
+func (a AzurePipeline) BuildPipelineParams(deployment, namespace, region, domain, iaas string, workerSchedule *config.WorkerSchedule, autoscale *config.Autoscale, stateBackend *StateBackend) (Pipeline, error) {
+	return AzurePipeline{
+		PipelineTemplateParams: PipelineTemplateParams{
+			ControlTowerVersion: ControlTowerVersion,
+			Deployment:          strings.TrimPrefix(deployment, "control-tower-"),
+			Domain:              domain,
+			Namespace:           namespace,
+			Region:              region,
+			IaaS:                iaas,
+			WorkerSchedule:      workerSchedule,
+			Autoscale:           autoscale,
+			StateBackend:        stateBackend,
+		},
+		ClientID:             a.ClientID,
+		ClientSecret:         a.ClientSecret,
+		StorageAccount:       a.StorageAccount,
+		StorageResourceGroup: a.StorageResourceGroup,
+		SubscriptionID:       a.SubscriptionID,
+		TenantID:             a.TenantID,
+	}, nil
+}
+
+// GetConfigTemplate returns template for Azure Control-Tower self update pipeline
+func (a AzurePipeline) GetConfigTemplate() string {
+	return azurePipelineTemplate
+}
+
+var azurePipelineTemplate = `
+---{{ if .WorkerSchedule }}` + workerScheduleResourceTypes + `{{ end }}` + selfUpdateResources + `{{ if .WorkerSchedule }}` + workerScheduleResources + `{{ end }}{{ if .Autoscale }}` + autoscaleResources + `{{ end }}
+jobs:
+- name: self-update
+  serial_groups: [cup]
+  serial: true
+  plan:
+  - get: control-tower-release
+    trigger: true
+  - task: update
+    params:
+` + azureTaskParams + `      SELF_UPDATE: true
+    config:
+      platform: linux
+      image_resource:
+        type: docker-image
+        source:
+          repository: engineerbetter/pcf-ops
+      inputs:
+      - name: control-tower-release
+      run:
+        path: bash
+        args:
+        - -c
+        - |
+          cd control-tower-release
+          set -eux
+` + stateBackendTaskSetup + `          chmod +x control-tower-linux-amd64
+          ./control-tower-linux-amd64 deploy $DEPLOYMENT
+- name: renew-https-cert
+  serial_groups: [cup]
+  serial: true
+  plan:
+  - get: control-tower-release
+    version: {tag: "{{ .ControlTowerVersion }}" }
+  - get: every-day
+    trigger: true
+  - task: update
+    params:
+` + azureTaskParams + `      SELF_UPDATE: true
+    config:
+      platform: linux
+      image_resource:
+        type: docker-image
+        source:
+          repository: engineerbetter/pcf-ops
+      inputs:
+      - name: control-tower-release
+      run:
+        path: bash
+        args:
+        - -c
+        - |
+          set -euxo pipefail
+          cd control-tower-release
+` + stateBackendTaskSetup + `          chmod +x control-tower-linux-amd64
+` + renewCertsDateCheck + `
+          echo Certificates expire in $days_until_expiry days, redeploying to renew them
+          ./control-tower-linux-amd64 deploy $DEPLOYMENT
+{{ if .WorkerSchedule }}` + workerScheduleJobs(azureTaskParams, azureScheduleTaskSetup) + `{{ end }}{{ if .Autoscale }}` + autoscaleJob(azureTaskParams, azureScheduleTaskSetup) + `{{ end }}`
+
+const azureTaskParams = `      AWS_REGION: "{{ .Region }}"
+      AZURE_CLIENT_ID: "{{ .ClientID }}"
+      AZURE_CLIENT_SECRET: "{{ .ClientSecret }}"
+      AZURE_STORAGE_ACCOUNT: "{{ .StorageAccount }}"
+      AZURE_STORAGE_RESOURCE_GROUP: "{{ .StorageResourceGroup }}"
+      AZURE_SUBSCRIPTION_ID: "{{ .SubscriptionID }}"
+      AZURE_TENANT_ID: "{{ .TenantID }}"
+      DEPLOYMENT: "{{ .Deployment }}"
+      IAAS: "{{ .IaaS }}"
+      NAMESPACE: "{{ .Namespace }}"
+` + stateBackendTaskParams
+
+const azureScheduleTaskSetup = `          cd control-tower-release
+          set -eux
+` + stateBackendTaskSetup + `          chmod +x control-tower-linux-amd64
+`
@@ -0,0 +1,85 @@
+package fly_test
+
+import (
+	"errors"
+
+	"github.com/EngineerBetter/control-tower/config"
+	. "github.com/EngineerBetter/control-tower/fly"
+	"github.com/EngineerBetter/control-tower/util"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+)
+
+var _ = Describe("AzurePipeline", func() {
+	attrs := map[string]string{
+		"subscription_id":        "subscription",
+		"tenant_id":              "tenant",
+		"client_id":              "client",
+		"client_secret":          "secret",
+		"storage_account":        "account",
+		"storage_resource_group": "storage-group",
+	}
+	attr := func(name string) (string, error) {
+		value, ok := attrs[name]
+		if !ok {
+			return "", errors.New("unknown attribute " + name)
+		}
+		return value, nil
+	}
+
+	Describe("Generating a pipeline YAML", func() {
+		It("passes the Azure credentials to every task", func() {
+			pipeline, err := NewAzurePipeline(attr)
+			Expect(err).ToNot(HaveOccurred())
+
+			params, err := pipeline.BuildPipelineParams("control-tower-my-deployment", "prod", "westeurope", "ci.engineerbetter.com", "Azure", &config.WorkerSchedule{
+				OffCron: "0 19 * * *", OnCron: "0 7 * * *", Timezone: "UTC", OffWorkers: 1, OnWorkers: 2,
+			}, nil, &StateBackend{Backend: "gcs", Region: "europe-west2", GoogleCredentials: `{"project_id": "state"}`})
+			Expect(err).ToNot(HaveOccurred())
+
+			yamlBytes, err := util.RenderTemplate("self-update pipeline", pipeline.GetConfigTemplate(), params)
+			Expect(err).ToNot(HaveOccurred())
+
+			var parsed struct {
+				Jobs []struct {
+					Name string `yaml:"name"`
+					Plan []struct {
+						Task   string            `yaml:"task"`
+						Params map[string]string `yaml:"params"`
+					} `yaml:"plan"`
+				} `yaml:"jobs"`
+			}
+			Expect(yaml.Unmarshal(yamlBytes, &parsed)).To(Succeed())
+			Expect(parsed.Jobs).To(HaveLen(4))
+			Expect(string(yamlBytes)).To(ContainSubstring("export STATE_BACKEND_GOOGLE_APPLICATION_CREDENTIALS=$PWD/state-backend-creds.json"))
+
+			for _, job := range parsed.Jobs {
+				for _, step := range job.Plan {
+					if step.Task == "" {
+						continue
+					}
+					Expect(step.Params).To(HaveKeyWithValue("AZURE_SUBSCRIPTION_ID", "subscription"), job.Name)
+					Expect(step.Params).To(HaveKeyWithValue("AZURE_TENANT_ID", "tenant"), job.Name)
+					Expect(step.Params).To(HaveKeyWithValue("AZURE_CLIENT_ID", "client"), job.Name)
+					Expect(step.Params).To(HaveKeyWithValue("AZURE_CLIENT_SECRET", "secret"), job.Name)
+					Expect(step.Params).To(HaveKeyWithValue("AZURE_STORAGE_ACCOUNT", "account"), job.Name)
+					Expect(step.Params).To(HaveKeyWithValue("AZURE_STORAGE_RESOURCE_GROUP", "storage-group"), job.Name)
+					Expect(step.Params).To(HaveKeyWithValue("DEPLOYMENT", "my-deployment"), job.Name)
+					Expect(step.Params).To(HaveKeyWithValue("IAAS", "Azure"), job.Name)
+					Expect(step.Params).To(HaveKeyWithValue("STATE_BACKEND", "gcs"), job.Name)
+					Expect(step.Params).To(HaveKeyWithValue("STATE_BACKEND_REGION", "europe-west2"), job.Name)
+					Expect(step.Params).To(HaveKeyWithValue("STATE_BACKEND_GOOGLE_CREDENTIALS", `{"project_id": "state"}`), job.Name)
+				}
+			}
+		})
+
+		It("returns an error when a credential is missing", func() {
+			delete(attrs, "client_secret")
+			defer func() { attrs["client_secret"] = "secret" }()
+
+			_, err := NewAzurePipeline(attr)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -2,6 +2,7 @@ package fly
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"errors"
 	"fmt"
@@ -17,6 +18,7 @@ import (
 	"github.com/EngineerBetter/control-tower/iaas"
 
 	"github.com/EngineerBetter/control-tower/config"
+	"github.com/EngineerBetter/control-tower/store"
 	"github.com/EngineerBetter/control-tower/util"
 )
 
@@ -25,8 +27,8 @@ var ControlTowerVersion = "COMPILE_TIME_VARIABLE_fly_control_tower_version"
 
 //go:generate counterfeiter . IClient
 type IClient interface {
-	CanConnect() (bool, error)
-	SetDefaultPipeline(config config.ConfigView, allowFlyVersionDiscrepancy bool) error
+	CanConnect(ctx context.Context) (bool, error)
+	SetDefaultPipeline(ctx context.Context, config config.ConfigView, allowFlyVersionDiscrepancy bool) error
 	Cleanup() error
 }
 
@@ -38,6 +40,10 @@ type Client struct {
 	stdout      io.Writer
 	stderr      io.Writer
 	versionFile []byte
+	// stateBackend is passed to the pipeline jobs, which would otherwise look for config in the IAAS
+	stateBackend *StateBackend
+	// setsPipeline is false for a local state backend, which pipeline jobs cannot reach
+	setsPipeline bool
 }
 
 // Credentials represents credentials needed to connect to concourse
@@ -50,7 +56,17 @@ type Credentials struct {
 }
 
 // New returns a new fly client
-func New(provider iaas.Provider, creds Credentials, stdout, stderr io.Writer, versionFile []byte) (IClient, error) {
+func New(provider iaas.Provider, creds Credentials, stdout, stderr io.Writer, versionFile []byte, stateBackendOpts store.Options) (IClient, error) {
+	setsPipeline := stateBackendOpts.Backend != store.Local
+	var stateBackend *StateBackend
+	if setsPipeline {
+		var err error
+		stateBackend, err = NewStateBackend(stateBackendOpts, getCredsFromSession)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	tempDir, err := util.NewTempDir()
 	if err != nil {
 		return nil, err
@@ -103,6 +119,11 @@ func New(provider iaas.Provider, creds Credentials, stdout, stderr io.Writer, ve
 		if err != nil {
 			return nil, errors.New("fly.go: failed to read credentials file")
 		}
+	case iaas.Azure:
+		pipeline, err = NewAzurePipeline(provider.Attr)
+		if err != nil {
+			return nil, fmt.Errorf("fly.go: failed to read Azure credentials: [%v]", err)
+		}
 	default:
 		return nil, errors.New("fly.go: IAAS not recognised")
 
@@ -114,20 +135,26 @@ func New(provider iaas.Provider, creds Credentials, stdout, stderr io.Writer, ve
 		stdout,
 		stderr,
 		versionFile,
+		stateBackend,
+		setsPipeline,
 	}, nil
 }
 
 var (
-	execCommand = exec.Command
+	execCommand = util.CommandContext
 )
 
-func (client *Client) runFly(args ...string) *exec.Cmd {
-	return execCommand(client.tempDir.Path("fly"), args...)
+// loginTimeout is how long to wait for the ATC to accept a login
+const loginTimeout = 200 * time.Second
+
+func (client *Client) runFly(ctx context.Context, args ...string) *exec.Cmd {
+	return execCommand(ctx, client.tempDir.Path("fly"), args...)
 }
 
 // CanConnect returns true if it can connect to the concourse
-func (client *Client) CanConnect() (bool, error) {
+func (client *Client) CanConnect(ctx context.Context) (bool, error) {
 	cmd := client.runFly(
+		ctx,
 		"--target",
 		client.creds.Target,
 		"login",
@@ -167,16 +194,21 @@ func (client *Client) CanConnect() (bool, error) {
 }
 
 // SetDefaultPipeline sets the default pipeline against a given concourse
-func (client *Client) SetDefaultPipeline(config config.ConfigView, allowFlyVersionDiscrepancy bool) error {
-	if err := client.login(); err != nil {
+func (client *Client) SetDefaultPipeline(ctx context.Context, config config.ConfigView, allowFlyVersionDiscrepancy bool) error {
+	if !client.setsPipeline {
+		_, err := fmt.Fprintf(client.stderr, "Not setting the self-update pipeline, as its jobs cannot reach the [%s] state backend\n", store.Local)
+		return err
+	}
+
+	if err := client.login(ctx); err != nil {
 		return err
 	}
 
 	if allowFlyVersionDiscrepancy {
-		if err := client.sync(); err != nil {
+		if err := client.sync(ctx); err != nil {
 			return err
 		}
-		if err := client.login(); err != nil {
+		if err := client.login(ctx); err != nil {
 			return err
 		}
 	}
@@ -188,7 +220,7 @@ func (client *Client) SetDefaultPipeline(config config.ConfigView, allowFlyVersi
 		return err
 	}
 
-	if err := client.run("set-pipeline", "--pipeline", pipelineName, "--config", pipelinePath, "--non-interactive"); err != nil {
+	if err := client.run(ctx, "set-pipeline", "--pipeline", pipelineName, "--config", pipelinePath, "--non-interactive"); err != nil {
 		return err
 	}
 
@@ -196,11 +228,11 @@ func (client *Client) SetDefaultPipeline(config config.ConfigView, allowFlyVersi
 		return err
 	}
 
-	if err := client.run("pause-job", "--job", pipelineName+"/self-update"); err != nil {
+	if err := client.run(ctx, "pause-job", "--job", pipelineName+"/self-update"); err != nil {
 		return err
 	}
 
-	return client.run("unpause-pipeline", "--pipeline", pipelineName)
+	return client.run(ctx, "unpause-pipeline", "--pipeline", pipelineName)
 }
 
 func (client *Client) writePipelineConfig(pipelinePath string, config config.ConfigView) error {
@@ -210,7 +242,7 @@ func (client *Client) writePipelineConfig(pipelinePath string, config config.Con
 	}
 	defer fileHandler.Close()
 
-	params, err := client.pipeline.BuildPipelineParams(config.GetDeployment(), config.GetNamespace(), config.GetRegion(), config.GetDomain(), config.GetIAAS())
+	params, err := client.pipeline.BuildPipelineParams(config.GetDeployment(), config.GetNamespace(), config.GetRegion(), config.GetDomain(), config.GetIAAS(), config.GetWorkerSchedule(), config.GetAutoscale(), client.stateBackend)
 	if err != nil {
 		return err
 	}
@@ -236,36 +268,39 @@ func (client *Client) Cleanup() error {
 	return client.tempDir.Cleanup()
 }
 
-func (client *Client) login() error {
-	attempts := 50
-	secondsBetweenAttempts := 4
-
+func (client *Client) login(ctx context.Context) error {
 	if _, err := client.stdout.Write([]byte("Waiting for Concourse ATC to start... \n")); err != nil {
 		return err
 	}
 
-	for i := 0; i < attempts; i++ {
-		canConnect, err := client.CanConnect()
-		if err != nil {
-			return err
-		}
+	ctx, cancel := context.WithTimeout(ctx, loginTimeout)
+	defer cancel()
+	backoff := util.NewBackoff(time.Second, 10*time.Second)
+
+	for {
+		canConnect, err := client.CanConnect(ctx)
 		if canConnect {
 			return nil
 		}
-
-		time.Sleep(time.Second * time.Duration(secondsBetweenAttempts))
+		if err == nil {
+			err = backoff.Wait(ctx)
+		}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("failed to log in to %s after %v", client.creds.API, loginTimeout)
+		}
+		if err != nil {
+			return err
+		}
 	}
-
-	return fmt.Errorf("failed to log in to %s after %d seconds", client.creds.API, attempts*secondsBetweenAttempts)
 }
 
-func (client *Client) sync() error {
-	return client.run("sync")
+func (client *Client) sync(ctx context.Context) error {
+	return client.run(ctx, "sync")
 }
 
-func (client *Client) run(args ...string) error {
+func (client *Client) run(ctx context.Context, args ...string) error {
 	args = append([]string{"--target", client.creds.Target}, args...)
-	cmd := client.runFly(args...)
+	cmd := client.runFly(ctx, args...)
 	cmd.Stdout = client.stdout
 	cmd.Stderr = client.stderr
 	return cmd.Run()
@@ -1,6 +1,7 @@
 package fly
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -11,10 +12,10 @@ import (
 	"github.com/EngineerBetter/control-tower/util"
 )
 
-func fakeExecCommand(command string, args ...string) *exec.Cmd {
+func fakeExecCommand(ctx context.Context, command string, args ...string) *exec.Cmd {
 	cs := []string{"-test.run=TestHelperProcess", "--", command}
 	cs = append(cs, args...)
-	cmd := exec.Command(os.Args[0], cs...)
+	cmd := exec.CommandContext(ctx, os.Args[0], cs...)
 	cmd.Env = append(os.Environ(), "GO_WANT_HELPER_PROCESS=1")
 	return cmd
 }
@@ -63,7 +64,7 @@ func TestClient_runFly(t *testing.T) {
 	}}
 
 	execCommand = fakeExecCommand
-	defer func() { execCommand = exec.Command }()
+	defer func() { execCommand = util.CommandContext }()
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -76,7 +77,7 @@ func TestClient_runFly(t *testing.T) {
 			}
 			os.Setenv("TEST_HELPER_OUTPUT", tt.cmdOutput)
 
-			if got, _ := client.runFly(tt.args.args...).CombinedOutput(); !reflect.DeepEqual(string(got), tt.want) {
+			if got, _ := client.runFly(context.Background(), tt.args.args...).CombinedOutput(); !reflect.DeepEqual(string(got), tt.want) {
 				t.Errorf("Client.runFly() = %v, want %v", string(got), tt.want)
 			}
 		})
@@ -2,6 +2,7 @@
 package flyfakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/EngineerBetter/control-tower/config"
@@ -9,9 +10,10 @@ import (
 )
 
 type FakeIClient struct {
-	CanConnectStub        func() (bool, error)
+	CanConnectStub        func(context.Context) (bool, error)
 	canConnectMutex       sync.RWMutex
 	canConnectArgsForCall []struct {
+		arg1 context.Context
 	}
 	canConnectReturns struct {
 		result1 bool
@@ -31,11 +33,12 @@ type FakeIClient struct {
 	cleanupReturnsOnCall map[int]struct {
 		result1 error
 	}
-	SetDefaultPipelineStub        func(config.ConfigView, bool) error
+	SetDefaultPipelineStub        func(context.Context, config.ConfigView, bool) error
 	setDefaultPipelineMutex       sync.RWMutex
 	setDefaultPipelineArgsForCall []struct {
-		arg1 config.ConfigView
-		arg2 bool
+		arg1 context.Context
+		arg2 config.ConfigView
+		arg3 bool
 	}
 	setDefaultPipelineReturns struct {
 		result1 error
@@ -47,15 +50,16 @@ type FakeIClient struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeIClient) CanConnect() (bool, error) {
+func (fake *FakeIClient) CanConnect(arg1 context.Context) (bool, error) {
 	fake.canConnectMutex.Lock()
 	ret, specificReturn := fake.canConnectReturnsOnCall[len(fake.canConnectArgsForCall)]
 	fake.canConnectArgsForCall = append(fake.canConnectArgsForCall, struct {
-	}{})
-	fake.recordInvocation("CanConnect", []interface{}{})
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("CanConnect", []interface{}{arg1})
 	fake.canConnectMutex.Unlock()
 	if fake.CanConnectStub != nil {
-		return fake.CanConnectStub()
+		return fake.CanConnectStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -70,12 +74,19 @@ func (fake *FakeIClient) CanConnectCallCount() int {
 	return len(fake.canConnectArgsForCall)
 }
 
-func (fake *FakeIClient) CanConnectCalls(stub func() (bool, error)) {
+func (fake *FakeIClient) CanConnectCalls(stub func(context.Context) (bool, error)) {
 	fake.canConnectMutex.Lock()
 	defer fake.canConnectMutex.Unlock()
 	fake.CanConnectStub = stub
 }
 
+func (fake *FakeIClient) CanConnectArgsForCall(i int) context.Context {
+	fake.canConnectMutex.RLock()
+	defer fake.canConnectMutex.RUnlock()
+	argsForCall := fake.canConnectArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeIClient) CanConnectReturns(result1 bool, result2 error) {
 	fake.canConnectMutex.Lock()
 	defer fake.canConnectMutex.Unlock()
@@ -154,17 +165,18 @@ func (fake *FakeIClient) CleanupReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeIClient) SetDefaultPipeline(arg1 config.ConfigView, arg2 bool) error {
+func (fake *FakeIClient) SetDefaultPipeline(arg1 context.Context, arg2 config.ConfigView, arg3 bool) error {
 	fake.setDefaultPipelineMutex.Lock()
 	ret, specificReturn := fake.setDefaultPipelineReturnsOnCall[len(fake.setDefaultPipelineArgsForCall)]
 	fake.setDefaultPipelineArgsForCall = append(fake.setDefaultPipelineArgsForCall, struct {
-		arg1 config.ConfigView
-		arg2 bool
-	}{arg1, arg2})
-	fake.recordInvocation("SetDefaultPipeline", []interface{}{arg1, arg2})
+		arg1 context.Context
+		arg2 config.ConfigView
+		arg3 bool
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("SetDefaultPipeline", []interface{}{arg1, arg2, arg3})
 	fake.setDefaultPipelineMutex.Unlock()
 	if fake.SetDefaultPipelineStub != nil {
-		return fake.SetDefaultPipelineStub(arg1, arg2)
+		return fake.SetDefaultPipelineStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -179,17 +191,17 @@ func (fake *FakeIClient) SetDefaultPipelineCallCount() int {
 	return len(fake.setDefaultPipelineArgsForCall)
 }
 
-func (fake *FakeIClient) SetDefaultPipelineCalls(stub func(config.ConfigView, bool) error) {
+func (fake *FakeIClient) SetDefaultPipelineCalls(stub func(context.Context, config.ConfigView, bool) error) {
 	fake.setDefaultPipelineMutex.Lock()
 	defer fake.setDefaultPipelineMutex.Unlock()
 	fake.SetDefaultPipelineStub = stub
 }
 
-func (fake *FakeIClient) SetDefaultPipelineArgsForCall(i int) (config.ConfigView, bool) {
+func (fake *FakeIClient) SetDefaultPipelineArgsForCall(i int) (context.Context, config.ConfigView, bool) {
 	fake.setDefaultPipelineMutex.RLock()
 	defer fake.setDefaultPipelineMutex.RUnlock()
 	argsForCall := fake.setDefaultPipelineArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeIClient) SetDefaultPipelineReturns(result1 error) {
@@ -3,6 +3,8 @@ package fly
 import (
 	"io/ioutil"
 	"strings"
+
+	"github.com/EngineerBetter/control-tower/config"
 )
 
 // GCPPipeline is GCP specific implementation of Pipeline interface
@@ -23,7 +25,7 @@ func NewGCPPipeline(credsPath string) (Pipeline, error) {
 }
 
 //BuildPipelineParams builds params for AWS control-tower self update pipeline
-func (a GCPPipeline) BuildPipelineParams(deployment, namespace, region, domain, iaas string) (Pipeline, error) {
+func (a GCPPipeline) BuildPipelineParams(deployment, namespace, region, domain, iaas string, workerSchedule *config.WorkerSchedule, autoscale *config.Autoscale, stateBackend *StateBackend) (Pipeline, error) {
 	return GCPPipeline{
 		PipelineTemplateParams: PipelineTemplateParams{
 			ControlTowerVersion: ControlTowerVersion,
@@ -32,6 +34,9 @@ func (a GCPPipeline) BuildPipelineParams(deployment, namespace, region, domain,
 			Namespace:           namespace,
 			Region:              region,
 			IaaS:                iaas,
+			WorkerSchedule:      workerSchedule,
+			Autoscale:           autoscale,
+			StateBackend:        stateBackend,
 		},
 		GCPCreds: a.GCPCreds,
 	}, nil
@@ -51,8 +56,8 @@ func readFileContents(path string) (string, error) {
 	return string(content), nil
 }
 
-const gcpPipelineTemplate = `
----` + selfUpdateResources + `
+var gcpPipelineTemplate = `
+---{{ if .WorkerSchedule }}` + workerScheduleResourceTypes + `{{ end }}` + selfUpdateResources + `{{ if .WorkerSchedule }}` + workerScheduleResources + `{{ end }}{{ if .Autoscale }}` + autoscaleResources + `{{ end }}
 jobs:
 - name: self-update
   serial_groups: [cup]
@@ -68,7 +73,7 @@ jobs:
       IAAS: "{{ .IaaS }}"
       NAMESPACE: "{{ .Namespace }}"
       SELF_UPDATE: true
-    config:
+` + stateBackendTaskParams + `    config:
       platform: linux
       image_resource:
         type: docker-image
@@ -85,7 +90,7 @@ jobs:
           echo "${GCPCreds}" > googlecreds.json
           export GOOGLE_APPLICATION_CREDENTIALS=$PWD/googlecreds.json
           set -eux
-          chmod +x control-tower-linux-amd64
+` + stateBackendTaskSetup + `          chmod +x control-tower-linux-amd64
           ./control-tower-linux-amd64 deploy $DEPLOYMENT
 - name: renew-https-cert
   serial_groups: [cup]
@@ -103,7 +108,7 @@ jobs:
       IAAS: "{{ .IaaS }}"
       NAMESPACE: "{{ .Namespace }}"
       SELF_UPDATE: true
-    config:
+` + stateBackendTaskParams + `    config:
       platform: linux
       image_resource:
         type: docker-image
@@ -120,8 +125,22 @@ jobs:
           export GOOGLE_APPLICATION_CREDENTIALS=$PWD/googlecreds.json
           set -euxo pipefail
           cd control-tower-release
-          chmod +x control-tower-linux-amd64
+` + stateBackendTaskSetup + `          chmod +x control-tower-linux-amd64
 ` + renewCertsDateCheck + `
           echo Certificates expire in $days_until_expiry days, redeploying to renew them
           ./control-tower-linux-amd64 deploy $DEPLOYMENT
+{{ if .WorkerSchedule }}` + workerScheduleJobs(gcpScheduleTaskParams, gcpScheduleTaskSetup) + `{{ end }}{{ if .Autoscale }}` + autoscaleJob(gcpScheduleTaskParams, gcpScheduleTaskSetup) + `{{ end }}`
+
+const gcpScheduleTaskParams = `      AWS_REGION: "{{ .Region }}"
+      DEPLOYMENT: "{{ .Deployment }}"
+      GCPCreds: '{{ .GCPCreds }}'
+      IAAS: "{{ .IaaS }}"
+      NAMESPACE: "{{ .Namespace }}"
+` + stateBackendTaskParams
+
+const gcpScheduleTaskSetup = `          cd control-tower-release
+          echo "${GCPCreds}" > googlecreds.json
+          export GOOGLE_APPLICATION_CREDENTIALS=$PWD/googlecreds.json
+          set -eux
+` + stateBackendTaskSetup + `          chmod +x control-tower-linux-amd64
 `
@@ -1,8 +1,10 @@
 package fly
 
+import "github.com/EngineerBetter/control-tower/config"
+
 // Pipeline is interface for self update pipeline
 type Pipeline interface {
-	BuildPipelineParams(deployment, namespace, region, domain, iaas string) (Pipeline, error)
+	BuildPipelineParams(deployment, namespace, region, domain, iaas string, workerSchedule *config.WorkerSchedule, autoscale *config.Autoscale, stateBackend *StateBackend) (Pipeline, error)
 	GetConfigTemplate() string
 }
 
@@ -13,6 +15,9 @@ type PipelineTemplateParams struct {
 	Namespace           string
 	Region              string
 	IaaS                string
+	WorkerSchedule      *config.WorkerSchedule
+	Autoscale           *config.Autoscale
+	StateBackend        *StateBackend
 }
 
 const selfUpdateResources = `
@@ -42,3 +47,102 @@ const renewCertsDateCheck = `
             exit 0
           fi
 `
+
+const workerScheduleResourceTypes = `
+resource_types:
+- name: cron-resource
+  type: docker-image
+  source:
+    repository: cftoolsuite/cron-resource
+`
+
+const workerScheduleResources = `- name: workers-off-schedule
+  type: cron-resource
+  icon: weather-night
+  source:
+    expression: "{{ .WorkerSchedule.OffCron }}"
+    location: "{{ .WorkerSchedule.Timezone }}"
+- name: workers-on-schedule
+  type: cron-resource
+  icon: weather-sunny
+  source:
+    expression: "{{ .WorkerSchedule.OnCron }}"
+    location: "{{ .WorkerSchedule.Timezone }}"
+`
+
+// workerScheduleJobs returns the jobs that scale the workers down and back up on a schedule.
+// taskParams and taskSetup are the IAAS specific params and script lines used by every job
+func workerScheduleJobs(taskParams, taskSetup string) string {
+	return workerScheduleJob("workers-off", "{{ .WorkerSchedule.OffWorkers }}", taskParams, taskSetup) +
+		workerScheduleJob("workers-on", "{{ .WorkerSchedule.OnWorkers }}", taskParams, taskSetup)
+}
+
+func workerScheduleJob(name, workers, taskParams, taskSetup string) string {
+	return `- name: ` + name + `
+  serial_groups: [cup]
+  serial: true
+  plan:
+  - get: control-tower-release
+    version: {tag: "{{ .ControlTowerVersion }}" }
+  - get: ` + name + `-schedule
+    trigger: true
+  - task: scale{{ if .WorkerSchedule.Tag }}
+    tags: ["{{ .WorkerSchedule.Tag }}"]{{ end }}
+    params:
+` + taskParams + `      WORKERS: "` + workers + `"
+    config:
+      platform: linux
+      image_resource:
+        type: docker-image
+        source:
+          repository: engineerbetter/pcf-ops
+      inputs:
+      - name: control-tower-release
+      run:
+        path: bash
+        args:
+        - -c
+        - |
+` + taskSetup + `          ./control-tower-linux-amd64 scale $DEPLOYMENT
+`
+}
+
+const autoscaleResources = `- name: autoscale-interval
+  type: time
+  icon: timer
+  source: {interval: 5m}
+`
+
+// autoscaleJob returns the job that periodically scales the default workers to fit the build load.
+// taskParams and taskSetup are the IAAS specific params and script lines used by every job
+func autoscaleJob(taskParams, taskSetup string) string {
+	return `- name: autoscale
+  serial_groups: [cup]
+  serial: true
+  plan:
+  - get: control-tower-release
+    version: {tag: "{{ .ControlTowerVersion }}" }
+  - get: autoscale-interval
+    trigger: true
+  - task: autoscale{{ if .Autoscale.Tag }}
+    tags: ["{{ .Autoscale.Tag }}"]{{ end }}
+    params:
+` + taskParams + `      MAX_WORKERS: "{{ .Autoscale.MaxWorkers }}"
+      MIN_WORKERS: "{{ .Autoscale.MinWorkers }}"
+      ONCE: true
+    config:
+      platform: linux
+      image_resource:
+        type: docker-image
+        source:
+          repository: engineerbetter/pcf-ops
+      inputs:
+      - name: control-tower-release
+      run:
+        path: bash
+        args:
+        - -c
+        - |
+` + taskSetup + `          ./control-tower-linux-amd64 autoscale $DEPLOYMENT
+`
+}
@@ -0,0 +1,74 @@
+package fly
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/EngineerBetter/control-tower/store"
+)
+
+// StateBackend holds the --state-backend settings, and credentials for them, which the jobs of the
+// self-update pipeline need to find the deployment's config when it is not kept by the IAAS
+type StateBackend struct {
+	Backend           string
+	Region            string
+	Endpoint          string
+	AccessKeyID       string
+	SecretAccessKey   string
+	GoogleCredentials string
+}
+
+// NewStateBackend returns the settings for the pipeline jobs, taking credentials from the same places as the store.
+// It returns nil when state is kept by the IAAS, whose credentials the jobs already have
+func NewStateBackend(opts store.Options, getAWSCreds AWSCredsGetter) (*StateBackend, error) {
+	stateBackend := &StateBackend{
+		Backend:  opts.Backend,
+		Region:   opts.Region,
+		Endpoint: opts.Endpoint,
+	}
+
+	switch opts.Backend {
+	case store.S3, store.S3Compatible:
+		stateBackend.AccessKeyID = os.Getenv("STATE_BACKEND_ACCESS_KEY_ID")
+		stateBackend.SecretAccessKey = os.Getenv("STATE_BACKEND_SECRET_ACCESS_KEY")
+		if stateBackend.AccessKeyID != "" {
+			return stateBackend, nil
+		}
+		accessKeyID, secretAccessKey, err := getAWSCreds()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get AWS credentials for the state backend: [%v]", err)
+		}
+		stateBackend.AccessKeyID, stateBackend.SecretAccessKey = accessKeyID, secretAccessKey
+		return stateBackend, nil
+	case store.GCS:
+		path := os.Getenv("STATE_BACKEND_GOOGLE_APPLICATION_CREDENTIALS")
+		if path == "" {
+			path = os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+		}
+		contents, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read credentials for the state backend: [%v]", err)
+		}
+		stateBackend.GoogleCredentials = string(contents)
+		return stateBackend, nil
+	case store.Local:
+		return nil, fmt.Errorf("the jobs of the self-update pipeline cannot reach the [%s] state backend", store.Local)
+	}
+
+	return nil, nil
+}
+
+// stateBackendTaskParams passes the state backend to a task, so that control-tower finds the deployment's config
+const stateBackendTaskParams = `{{ with .StateBackend }}      STATE_BACKEND: "{{ .Backend }}"
+      STATE_BACKEND_ACCESS_KEY_ID: "{{ .AccessKeyID }}"
+      STATE_BACKEND_ENDPOINT: "{{ .Endpoint }}"
+      STATE_BACKEND_GOOGLE_CREDENTIALS: '{{ .GoogleCredentials }}'
+      STATE_BACKEND_REGION: "{{ .Region }}"
+      STATE_BACKEND_SECRET_ACCESS_KEY: "{{ .SecretAccessKey }}"
+{{ end }}`
+
+// stateBackendTaskSetup writes the GCS credentials of the state backend to a file, which is where control-tower reads them from
+const stateBackendTaskSetup = `{{ with .StateBackend }}{{ if .GoogleCredentials }}          printenv STATE_BACKEND_GOOGLE_CREDENTIALS > state-backend-creds.json
+          export STATE_BACKEND_GOOGLE_APPLICATION_CREDENTIALS=$PWD/state-backend-creds.json
+{{ end }}{{ end }}`
@@ -0,0 +1,55 @@
+package fly_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/EngineerBetter/control-tower/fly"
+	"github.com/EngineerBetter/control-tower/store"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StateBackend", func() {
+	sessionCreds := func() (string, string, error) {
+		return "session-key", "session-secret", nil
+	}
+
+	It("is nil when state is kept by the IAAS", func() {
+		stateBackend, err := NewStateBackend(store.Options{Backend: store.IAAS}, sessionCreds)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stateBackend).To(BeNil())
+	})
+
+	It("uses the AWS session credentials for S3 when no state backend credentials are set", func() {
+		stateBackend, err := NewStateBackend(store.Options{Backend: store.S3, Region: "eu-west-2"}, sessionCreds)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*stateBackend).To(Equal(StateBackend{
+			Backend:         store.S3,
+			Region:          "eu-west-2",
+			AccessKeyID:     "session-key",
+			SecretAccessKey: "session-secret",
+		}))
+	})
+
+	It("reads the GCS credentials file", func() {
+		dir, err := ioutil.TempDir("", "state-backend")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "creds.json")
+		Expect(ioutil.WriteFile(path, []byte(`{"project_id": "state"}`), 0600)).To(Succeed())
+		os.Setenv("STATE_BACKEND_GOOGLE_APPLICATION_CREDENTIALS", path)
+		defer os.Unsetenv("STATE_BACKEND_GOOGLE_APPLICATION_CREDENTIALS")
+
+		stateBackend, err := NewStateBackend(store.Options{Backend: store.GCS}, sessionCreds)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stateBackend.GoogleCredentials).To(Equal(`{"project_id": "state"}`))
+	})
+
+	It("cannot be a local directory", func() {
+		_, err := NewStateBackend(store.Options{Backend: store.Local, Dir: "/tmp/state"}, sessionCreds)
+		Expect(err).To(MatchError("the jobs of the self-update pipeline cannot reach the [local] state backend"))
+	})
+})
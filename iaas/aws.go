@@ -1,11 +1,13 @@
 package iaas
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"strings"
 	"time"
 
+	"github.com/EngineerBetter/control-tower/util"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
@@ -190,11 +192,11 @@ func between(value, lower, upper int64) bool {
 	return (value <= upper && value >= lower)
 }
 
-func checkInUseVolumes(ec2Client *ec2.EC2, volumes []*string) error {
+func checkInUseVolumes(ctx context.Context, ec2Client *ec2.EC2, volumes []*string) error {
 	volumesInUse := 1
 	volumesFound := []*ec2.Volume{}
 	for i := 0; i < 3 && volumesInUse != 0; i++ {
-		volumesOutput, err := ec2Client.DescribeVolumes(&ec2.DescribeVolumesInput{
+		volumesOutput, err := ec2Client.DescribeVolumesWithContext(ctx, &ec2.DescribeVolumesInput{
 			Filters: []*ec2.Filter{
 				{
 					Name: aws.String("status"),
@@ -216,7 +218,9 @@ func checkInUseVolumes(ec2Client *ec2.EC2, volumes []*string) error {
 		volumesInUse = len(volumesFound)
 		if volumesInUse > 0 && i < 2 {
 			fmt.Printf("There are %v volumes in use. Sleeping for 10 seconds.\n", volumesInUse)
-			time.Sleep(10 * time.Second)
+			if err = util.Sleep(ctx, 10*time.Second); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -232,7 +236,7 @@ func checkInUseVolumes(ec2Client *ec2.EC2, volumes []*string) error {
 }
 
 // DeleteVolumes deletes the specified EBS volumes
-func (a *AWSProvider) DeleteVolumes(volumes []string, deleteVolume func(ec2Client IEC2, volumeID *string) error) error {
+func (a *AWSProvider) DeleteVolumes(ctx context.Context, volumes []string, deleteVolume func(ec2Client IEC2, volumeID *string) error) error {
 	if len(volumes) == 0 {
 		return nil
 	}
@@ -243,12 +247,12 @@ func (a *AWSProvider) DeleteVolumes(volumes []string, deleteVolume func(ec2Clien
 	for i := range volumes {
 		pvolumes = append(pvolumes, &volumes[i])
 	}
-	err := checkInUseVolumes(ec2Client, pvolumes)
+	err := checkInUseVolumes(ctx, ec2Client, pvolumes)
 	if err != nil {
 		return err
 	}
 
-	volumesOutput, err := ec2Client.DescribeVolumes(&ec2.DescribeVolumesInput{
+	volumesOutput, err := ec2Client.DescribeVolumesWithContext(ctx, &ec2.DescribeVolumesInput{
 		Filters: []*ec2.Filter{
 			{
 				Name: aws.String("status"),
@@ -288,17 +292,17 @@ func DeleteVolume(ec2Client IEC2, volumeID *string) error {
 }
 
 // DeleteVMsInDeployment is a placeholder for a function used with GCP deployments
-func (a *AWSProvider) DeleteVMsInDeployment(zone, project, deployment string) error {
+func (a *AWSProvider) DeleteVMsInDeployment(ctx context.Context, zone, project, deployment string) error {
 	return nil
 }
 
 // DeleteVMsInVPC deletes all the VMs in the given VPC
-func (a *AWSProvider) DeleteVMsInVPC(vpcID string) ([]string, error) {
+func (a *AWSProvider) DeleteVMsInVPC(ctx context.Context, vpcID string) ([]string, error) {
 
 	filterName := "vpc-id"
 	ec2Client := ec2.New(a.sess)
 
-	resp, err := ec2Client.DescribeInstances(&ec2.DescribeInstancesInput{
+	resp, err := ec2Client.DescribeInstancesWithContext(ctx, &ec2.DescribeInstancesInput{
 		Filters: []*ec2.Filter{
 			&ec2.Filter{
 				Name: &filterName,
@@ -328,7 +332,7 @@ func (a *AWSProvider) DeleteVMsInVPC(vpcID string) ([]string, error) {
 		return nil, nil
 	}
 
-	_, err = ec2Client.TerminateInstances(&ec2.TerminateInstancesInput{
+	_, err = ec2Client.TerminateInstancesWithContext(ctx, &ec2.TerminateInstancesInput{
 		InstanceIds: instancesToTerminate,
 	})
 	if err != nil {
@@ -3,6 +3,7 @@
 package iaas
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"testing"
@@ -56,7 +57,7 @@ func TestAWSProvider_DeleteVolumes(t *testing.T) {
 				sess: sess,
 			}
 
-			if err := a.DeleteVolumes(tt.args.volumes, DeleteVolume); (err != nil) != tt.wantErr {
+			if err := a.DeleteVolumes(context.Background(), tt.args.volumes, DeleteVolume); (err != nil) != tt.wantErr {
 				t.Errorf("AWSProvider.DeleteVolumes() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
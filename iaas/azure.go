@@ -0,0 +1,544 @@
+package iaas
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/EngineerBetter/control-tower/util"
+	"golang.org/x/net/context"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	azureManagementURL     = "https://management.azure.com"
+	azureStorageAPIVersion = "2019-02-02"
+	azureDNSAPIVersion     = "2018-05-01"
+	azureNetworkAPIVersion = "2019-11-01"
+	azureGroupsAPIVersion  = "2019-10-01"
+)
+
+// AzureVMsResourceGroupSuffix is appended to the deployment name to give the resource group
+// that the director and the VMs it creates are placed in. Keeping them apart from the
+// terraform managed resources lets destroy remove them all by deleting the group
+const AzureVMsResourceGroupSuffix = "-vms"
+
+// AzureProvider keeps config in Blob Storage containers of an existing storage account
+// and manages everything else through Azure Resource Manager
+type AzureProvider struct {
+	ctx           context.Context
+	management    *http.Client
+	storage       *http.Client
+	managementURL string
+	storageURL    string
+	region        string
+	attrs         map[string]string
+}
+
+type AzureOption func(*AzureProvider) error
+
+var azureEnvVars = map[string]string{
+	"subscription_id":        "AZURE_SUBSCRIPTION_ID",
+	"tenant_id":              "AZURE_TENANT_ID",
+	"client_id":              "AZURE_CLIENT_ID",
+	"client_secret":          "AZURE_CLIENT_SECRET",
+	"storage_account":        "AZURE_STORAGE_ACCOUNT",
+	"storage_resource_group": "AZURE_STORAGE_RESOURCE_GROUP",
+}
+
+func newAzure(region string, ops ...AzureOption) (Provider, error) {
+	attrs := make(map[string]string)
+	for attr, envVar := range azureEnvVars {
+		value, exists := os.LookupEnv(envVar)
+		if !exists || value == "" {
+			return nil, fmt.Errorf("%s is not set", envVar)
+		}
+		attrs[attr] = value
+	}
+
+	ctx := context.Background()
+
+	a := &AzureProvider{
+		ctx:           ctx,
+		management:    azureClient(ctx, attrs, "https://management.azure.com/.default"),
+		storage:       azureClient(ctx, attrs, "https://storage.azure.com/.default"),
+		managementURL: azureManagementURL,
+		storageURL:    fmt.Sprintf("https://%s.blob.core.windows.net", attrs["storage_account"]),
+		region:        region,
+		attrs:         attrs,
+	}
+	for _, op := range ops {
+		if err := op(a); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// azureClient returns an HTTP client that authenticates as the service principal for the given scope
+func azureClient(ctx context.Context, attrs map[string]string, scope string) *http.Client {
+	conf := clientcredentials.Config{
+		ClientID:     attrs["client_id"],
+		ClientSecret: attrs["client_secret"],
+		TokenURL:     fmt.Sprintf("https://login.microsoftonline.com/%s/oauth2/v2.0/token", attrs["tenant_id"]),
+		Scopes:       []string{scope},
+	}
+	return conf.Client(ctx)
+}
+
+// AzureDBSizes maps user set size to Azure Database for PostgreSQL SKU
+var AzureDBSizes = map[string]string{
+	"small":   "B_Gen5_1",
+	"medium":  "B_Gen5_2",
+	"large":   "GP_Gen5_2",
+	"xlarge":  "GP_Gen5_4",
+	"2xlarge": "GP_Gen5_8",
+	"4xlarge": "GP_Gen5_16",
+}
+
+// DBType gets the correct Azure Database for PostgreSQL SKU
+func (a *AzureProvider) DBType(name string) string {
+	return AzureDBSizes[name]
+}
+
+// Attr returns Azure specific attribute
+func (a *AzureProvider) Attr(key string) (string, error) {
+	v, ok := a.attrs[key]
+	if !ok {
+		return "", fmt.Errorf("iaas:azure: key %s not found", key)
+	}
+	return v, nil
+}
+
+// Choose for the consumer the appropriate output based on the provider
+func (a *AzureProvider) Choose(c Choice) interface{} {
+	return c.Azure
+}
+
+func (a *AzureProvider) IAAS() Name {
+	return Azure
+}
+
+func (a *AzureProvider) Region() string {
+	return a.region
+}
+
+// Zone returns the requested availability zone. VMs are not placed in a zone by default
+// as not every Azure region has them
+func (a *AzureProvider) Zone(requestedZone, workerSizeNotUsedInAzure string) string {
+	return requestedZone
+}
+
+// azureError is returned for any unexpected response from an Azure API
+type azureError struct {
+	method     string
+	url        string
+	statusCode int
+	status     string
+	body       string
+}
+
+func (e *azureError) Error() string {
+	return fmt.Sprintf("%s %s returned %s: %s", e.method, e.url, e.status, e.body)
+}
+
+// ErrVersioningUnsupported is returned when asking for previous versions of files, which Azure does not keep
+var ErrVersioningUnsupported = errors.New("previous versions of files are not kept in Azure Blob Storage, use --state-backend to keep state in a versioned store")
+
+func isAzureNotFound(err error) bool {
+	azErr, ok := err.(*azureError)
+	return ok && azErr.statusCode == http.StatusNotFound
+}
+
+// do sends a request and returns the response if it has one of the expected status codes
+func (a *AzureProvider) do(client *http.Client, method, requestURL string, body []byte, headers map[string]string, expected ...int) (*http.Response, error) {
+	req, err := http.NewRequest(method, requestURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(a.ctx)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, code := range expected {
+		if resp.StatusCode == code {
+			return resp, nil
+		}
+	}
+
+	defer resp.Body.Close()
+	respBody, _ := ioutil.ReadAll(resp.Body)
+	return nil, &azureError{method: method, url: requestURL, statusCode: resp.StatusCode, status: resp.Status, body: string(respBody)}
+}
+
+func (a *AzureProvider) doStorage(method, path string, query url.Values, body []byte, headers map[string]string, expected ...int) (*http.Response, error) {
+	requestURL := a.storageURL + "/" + path
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+
+	allHeaders := map[string]string{"x-ms-version": azureStorageAPIVersion}
+	for k, v := range headers {
+		allHeaders[k] = v
+	}
+
+	return a.do(a.storage, method, requestURL, body, allHeaders, expected...)
+}
+
+func (a *AzureProvider) doManagement(method, path, apiVersion string, expected ...int) (*http.Response, error) {
+	requestURL := path
+	if !strings.HasPrefix(path, "https://") {
+		requestURL = fmt.Sprintf("%s%s?api-version=%s", a.managementURL, path, apiVersion)
+	}
+	return a.do(a.management, method, requestURL, nil, nil, expected...)
+}
+
+func containerQuery() url.Values {
+	return url.Values{"restype": []string{"container"}}
+}
+
+// CreateBucket creates a Blob Storage container in the storage account
+func (a *AzureProvider) CreateBucket(name string) error {
+	resp, err := a.doStorage(http.MethodPut, name, containerQuery(), nil, nil, http.StatusCreated)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// BucketExists checks whether the storage account has a container with the given name
+func (a *AzureProvider) BucketExists(name string) (bool, error) {
+	resp, err := a.doStorage(http.MethodHead, name, containerQuery(), nil, nil, http.StatusOK)
+	if isAzureNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, resp.Body.Close()
+}
+
+// EnsureTerraformLock does nothing as terraform's azurerm backend locks state with blob leases
+func (a *AzureProvider) EnsureTerraformLock(bucket string) error {
+	return nil
+}
+
+// DeleteTerraformLock does nothing as EnsureTerraformLock creates nothing
+func (a *AzureProvider) DeleteTerraformLock(bucket string) error {
+	return nil
+}
+
+// DeleteVersionedBucket deletes a container along with all of its blobs
+func (a *AzureProvider) DeleteVersionedBucket(name string) error {
+	resp, err := a.doStorage(http.MethodDelete, name, containerQuery(), nil, nil, http.StatusAccepted)
+	if err != nil {
+		return fmt.Errorf("error deleting container [%v]: [%v]", name, err)
+	}
+	return resp.Body.Close()
+}
+
+type azureContainerList struct {
+	Containers []struct {
+		Name string `xml:"Name"`
+	} `xml:"Containers>Container"`
+	NextMarker string `xml:"NextMarker"`
+}
+
+// ListBuckets returns the names of all containers in the storage account
+func (a *AzureProvider) ListBuckets() ([]string, error) {
+	names := []string{}
+	marker := ""
+	for {
+		query := url.Values{"comp": []string{"list"}}
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+
+		resp, err := a.doStorage(http.MethodGet, "", query, nil, nil, http.StatusOK)
+		if err != nil {
+			return nil, err
+		}
+
+		var list azureContainerList
+		err = xml.NewDecoder(resp.Body).Decode(&list)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, container := range list.Containers {
+			names = append(names, container.Name)
+		}
+		if list.NextMarker == "" {
+			return names, nil
+		}
+		marker = list.NextMarker
+	}
+}
+
+// BucketRegion returns the provider's region, as containers can be read from any region
+func (a *AzureProvider) BucketRegion(name string) (string, error) {
+	return a.region, nil
+}
+
+func (a *AzureProvider) HasFile(bucket, path string) (bool, error) {
+	resp, err := a.doStorage(http.MethodHead, bucket+"/"+path, nil, nil, nil, http.StatusOK)
+	if isAzureNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, resp.Body.Close()
+}
+
+func (a *AzureProvider) LoadFile(bucket, path string) ([]byte, error) {
+	resp, err := a.doStorage(http.MethodGet, bucket+"/"+path, nil, nil, nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+func (a *AzureProvider) WriteFile(bucket, path string, contents []byte) error {
+	resp, err := a.doStorage(http.MethodPut, bucket+"/"+path, nil, contents, map[string]string{"x-ms-blob-type": "BlockBlob"}, http.StatusCreated)
+	if err != nil {
+		return fmt.Errorf("failed to write %s to container: [%s]", path, err)
+	}
+	return resp.Body.Close()
+}
+
+// CreateFile writes the blob with If-None-Match: *, unless it already exists, returning false if it did
+func (a *AzureProvider) CreateFile(bucket, path string, contents []byte) (bool, error) {
+	resp, err := a.doStorage(http.MethodPut, bucket+"/"+path, nil, contents, map[string]string{"x-ms-blob-type": "BlockBlob", "If-None-Match": "*"}, http.StatusCreated)
+	if azErr, ok := err.(*azureError); ok && azErr.statusCode == http.StatusConflict {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to write %s to container: [%s]", path, err)
+	}
+	return true, resp.Body.Close()
+}
+
+// DeleteFile deletes the blob, and does nothing if it does not exist
+func (a *AzureProvider) DeleteFile(bucket, path string) error {
+	resp, err := a.doStorage(http.MethodDelete, bucket+"/"+path, nil, nil, nil, http.StatusAccepted)
+	if isAzureNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// EnsureFileExists checks for the named blob and creates it if it doesn't exist
+// Second argument is true if new file was created
+func (a *AzureProvider) EnsureFileExists(bucket, path string, defaultContents []byte) ([]byte, bool, error) {
+	contents, err := a.LoadFile(bucket, path)
+	if err == nil {
+		return contents, false, nil
+	}
+
+	if !isAzureNotFound(err) {
+		return nil, false, err
+	}
+
+	err = a.WriteFile(bucket, path, defaultContents)
+	if err != nil {
+		return nil, false, err
+	}
+	return defaultContents, true, nil
+}
+
+// FileLastModified returns the time the specified blob was last written
+func (a *AzureProvider) FileLastModified(bucket, path string) (time.Time, error) {
+	resp, err := a.doStorage(http.MethodHead, bucket+"/"+path, nil, nil, nil, http.StatusOK)
+	if err != nil {
+		return time.Time{}, err
+	}
+	resp.Body.Close()
+
+	return http.ParseTime(resp.Header.Get("Last-Modified"))
+}
+
+// ListFileVersions fails as containers created by Control Tower do not keep previous versions of blobs
+func (a *AzureProvider) ListFileVersions(bucket, path string) ([]FileVersion, error) {
+	return nil, ErrVersioningUnsupported
+}
+
+// LoadFileVersion fails as containers created by Control Tower do not keep previous versions of blobs
+func (a *AzureProvider) LoadFileVersion(bucket, path, versionID string) ([]byte, error) {
+	return nil, ErrVersioningUnsupported
+}
+
+// DeleteFileVersion fails as containers created by Control Tower do not keep previous versions of blobs
+func (a *AzureProvider) DeleteFileVersion(bucket, path, versionID string) error {
+	return ErrVersioningUnsupported
+}
+
+type azureDNSZoneList struct {
+	Value []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"value"`
+	NextLink string `json:"nextLink"`
+}
+
+// FindLongestMatchingHostedZone returns the name and resource ID of the Azure DNS zone
+// that most closely matches the domain
+func (a *AzureProvider) FindLongestMatchingHostedZone(domain string) (string, string, error) {
+	var zoneName, zoneID string
+
+	path := fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Network/dnszones", a.attrs["subscription_id"])
+	for path != "" {
+		resp, err := a.doManagement(http.MethodGet, path, azureDNSAPIVersion, http.StatusOK)
+		if err != nil {
+			return "", "", err
+		}
+
+		var list azureDNSZoneList
+		err = json.NewDecoder(resp.Body).Decode(&list)
+		resp.Body.Close()
+		if err != nil {
+			return "", "", err
+		}
+
+		for _, zone := range list.Value {
+			if strings.HasSuffix(domain, zone.Name) && len(zone.Name) > len(zoneName) {
+				zoneName = zone.Name
+				zoneID = zone.ID
+			}
+		}
+		path = list.NextLink
+	}
+
+	if zoneName == "" {
+		return "", "", fmt.Errorf("dns zone for domain '%s' was not found in Azure DNS", domain)
+	}
+
+	return zoneName, zoneID, nil
+}
+
+type azureSecurityGroup struct {
+	Properties struct {
+		SecurityRules []struct {
+			Properties struct {
+				Access                string   `json:"access"`
+				Direction             string   `json:"direction"`
+				SourceAddressPrefix   string   `json:"sourceAddressPrefix"`
+				SourceAddressPrefixes []string `json:"sourceAddressPrefixes"`
+			} `json:"properties"`
+		} `json:"securityRules"`
+	} `json:"properties"`
+}
+
+// CheckForWhitelistedIP checks if the specified IP is allowed in by the network security group
+// with the given resource ID
+func (a *AzureProvider) CheckForWhitelistedIP(ip, securityGroupID string) (bool, error) {
+	resp, err := a.doManagement(http.MethodGet, securityGroupID, azureNetworkAPIVersion, http.StatusOK)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	var securityGroup azureSecurityGroup
+	if err = json.NewDecoder(resp.Body).Decode(&securityGroup); err != nil {
+		return false, err
+	}
+
+	parsedIP := net.ParseIP(ip)
+	for _, rule := range securityGroup.Properties.SecurityRules {
+		if rule.Properties.Access != "Allow" || rule.Properties.Direction != "Inbound" {
+			continue
+		}
+		prefixes := rule.Properties.SourceAddressPrefixes
+		if rule.Properties.SourceAddressPrefix != "" {
+			prefixes = append(prefixes, rule.Properties.SourceAddressPrefix)
+		}
+		for _, prefix := range prefixes {
+			if !strings.Contains(prefix, "/") {
+				prefix += "/32"
+			}
+			_, cidr, err := net.ParseCIDR(prefix)
+			if err != nil {
+				continue
+			}
+			if cidr.Contains(parsedIP) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// DeleteVMsInDeployment deletes the resource group holding the director and the VMs it created,
+// so that the terraform managed network can be destroyed
+func (a *AzureProvider) DeleteVMsInDeployment(ctx context.Context, zone, project, deployment string) error {
+	path := fmt.Sprintf("/subscriptions/%s/resourcegroups/%s%s", a.attrs["subscription_id"], deployment, AzureVMsResourceGroupSuffix)
+
+	resp, err := a.doManagement(http.MethodDelete, path, azureGroupsAPIVersion, http.StatusOK, http.StatusAccepted, http.StatusNoContent)
+	if isAzureNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	fmt.Printf("Deleting resource group %s%s\n", deployment, AzureVMsResourceGroupSuffix)
+
+	waitCtx, cancel := context.WithTimeout(ctx, 30*time.Minute)
+	defer cancel()
+	backoff := util.NewBackoff(10*time.Second, time.Minute)
+	for {
+		resp, err = a.doManagement(http.MethodGet, path, azureGroupsAPIVersion, http.StatusOK)
+		if isAzureNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		if err = backoff.Wait(waitCtx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("resource group %s%s not deleted after 30 minutes", deployment, AzureVMsResourceGroupSuffix)
+		}
+	}
+}
+
+// DeleteVMsInVPC is a placeholder function used with AWS deployments
+func (a *AzureProvider) DeleteVMsInVPC(ctx context.Context, vpcID string) ([]string, error) {
+	return []string{}, nil
+}
+
+// DeleteVolumes is a placeholder function used with AWS deployments. Azure disks are
+// deleted along with the resource group of the VMs
+func (a *AzureProvider) DeleteVolumes(ctx context.Context, volumesToDelete []string, deleteVolume func(ec2Client IEC2, volumeID *string) error) error {
+	return errors.New("DeleteVolumes is not used on Azure")
+}
+
+// CreateDatabases is a no-op as terraform creates the databases on Azure Database for PostgreSQL
+func (a *AzureProvider) CreateDatabases(name, username, password string) error {
+	return nil
+}
@@ -0,0 +1,175 @@
+package iaas
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func newTestAzureProvider(handler http.HandlerFunc) (*AzureProvider, func()) {
+	server := httptest.NewServer(handler)
+	return &AzureProvider{
+		ctx:           context.Background(),
+		management:    server.Client(),
+		storage:       server.Client(),
+		managementURL: server.URL,
+		storageURL:    server.URL,
+		region:        "westeurope",
+		attrs:         map[string]string{"subscription_id": "sub"},
+	}, server.Close
+}
+
+func TestAzureProvider_IAAS(t *testing.T) {
+	a := &AzureProvider{}
+	if got := a.IAAS(); got != Azure {
+		t.Errorf("AzureProvider.IAAS() = %v, want %v", got, Azure)
+	}
+	if got := a.Choose(Choice{AWS: "aws", GCP: "gcp", Azure: "azure"}); got != "azure" {
+		t.Errorf("AzureProvider.Choose() = %v, want azure", got)
+	}
+}
+
+func TestAzureProvider_BucketExists(t *testing.T) {
+	a, done := newTestAzureProvider(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-ms-version") == "" || r.URL.Query().Get("restype") != "container" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.URL.Path == "/exists" {
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer done()
+
+	for name, want := range map[string]bool{"exists": true, "missing": false} {
+		got, err := a.BucketExists(name)
+		if err != nil {
+			t.Fatalf("BucketExists(%s) error = %v", name, err)
+		}
+		if got != want {
+			t.Errorf("BucketExists(%s) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestAzureProvider_Files(t *testing.T) {
+	blobs := map[string][]byte{}
+	a, done := newTestAzureProvider(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			if r.Header.Get("x-ms-blob-type") != "BlockBlob" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			blobs[r.URL.Path], _ = ioutil.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodGet, http.MethodHead:
+			contents, ok := blobs[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+			w.Write(contents)
+		}
+	})
+	defer done()
+
+	contents, created, err := a.EnsureFileExists("bucket", "config.json", []byte("default"))
+	if err != nil || !created || string(contents) != "default" {
+		t.Fatalf("EnsureFileExists() = %s, %v, %v; want default, true, nil", contents, created, err)
+	}
+
+	if err = a.WriteFile("bucket", "config.json", []byte("updated")); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	contents, created, err = a.EnsureFileExists("bucket", "config.json", []byte("default"))
+	if err != nil || created || string(contents) != "updated" {
+		t.Fatalf("EnsureFileExists() = %s, %v, %v; want updated, false, nil", contents, created, err)
+	}
+
+	hasFile, err := a.HasFile("bucket", "missing.json")
+	if err != nil || hasFile {
+		t.Errorf("HasFile() = %v, %v; want false, nil", hasFile, err)
+	}
+
+	modified, err := a.FileLastModified("bucket", "config.json")
+	if err != nil || modified.Year() != 2015 {
+		t.Errorf("FileLastModified() = %v, %v; want 2015-10-21", modified, err)
+	}
+}
+
+func TestAzureProvider_ListBuckets(t *testing.T) {
+	a, done := newTestAzureProvider(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("comp") != "list" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.URL.Query().Get("marker") == "" {
+			fmt.Fprint(w, `<?xml version="1.0" encoding="utf-8"?><EnumerationResults><Containers><Container><Name>one</Name></Container></Containers><NextMarker>next</NextMarker></EnumerationResults>`)
+			return
+		}
+		fmt.Fprint(w, `<?xml version="1.0" encoding="utf-8"?><EnumerationResults><Containers><Container><Name>two</Name></Container></Containers><NextMarker/></EnumerationResults>`)
+	})
+	defer done()
+
+	got, err := a.ListBuckets()
+	if err != nil {
+		t.Fatalf("ListBuckets() error = %v", err)
+	}
+	if want := []string{"one", "two"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListBuckets() = %v, want %v", got, want)
+	}
+}
+
+func TestAzureProvider_FindLongestMatchingHostedZone(t *testing.T) {
+	a, done := newTestAzureProvider(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/subscriptions/sub/providers/Microsoft.Network/dnszones" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"value": [
+			{"id": "/subscriptions/sub/resourceGroups/dns/providers/Microsoft.Network/dnszones/example.com", "name": "example.com"},
+			{"id": "/subscriptions/sub/resourceGroups/dns/providers/Microsoft.Network/dnszones/ci.example.com", "name": "ci.example.com"}
+		]}`)
+	})
+	defer done()
+
+	name, id, err := a.FindLongestMatchingHostedZone("concourse.ci.example.com")
+	if err != nil {
+		t.Fatalf("FindLongestMatchingHostedZone() error = %v", err)
+	}
+	if name != "ci.example.com" || id != "/subscriptions/sub/resourceGroups/dns/providers/Microsoft.Network/dnszones/ci.example.com" {
+		t.Errorf("FindLongestMatchingHostedZone() = %s, %s", name, id)
+	}
+
+	if _, _, err = a.FindLongestMatchingHostedZone("concourse.example.org"); err == nil {
+		t.Error("FindLongestMatchingHostedZone() expected an error for an unknown domain")
+	}
+}
+
+func TestAzureProvider_CheckForWhitelistedIP(t *testing.T) {
+	a, done := newTestAzureProvider(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"properties": {"securityRules": [
+			{"properties": {"access": "Allow", "direction": "Inbound", "sourceAddressPrefixes": ["10.0.0.0/24", "1.2.3.4"]}},
+			{"properties": {"access": "Deny", "direction": "Inbound", "sourceAddressPrefix": "5.6.7.8"}}
+		]}}`)
+	})
+	defer done()
+
+	for ip, want := range map[string]bool{"1.2.3.4": true, "10.0.0.9": true, "5.6.7.8": false} {
+		got, err := a.CheckForWhitelistedIP(ip, "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/networkSecurityGroups/director")
+		if err != nil {
+			t.Fatalf("CheckForWhitelistedIP(%s) error = %v", ip, err)
+		}
+		if got != want {
+			t.Errorf("CheckForWhitelistedIP(%s) = %v, want %v", ip, got, want)
+		}
+	}
+}
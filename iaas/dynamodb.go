@@ -0,0 +1,72 @@
+package iaas
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// TerraformLockTable returns the name of the DynamoDB table terraform locks the state kept in bucket with
+func TerraformLockTable(bucket string) string {
+	return bucket + "-terraform-lock"
+}
+
+// EnsureTerraformLock creates the DynamoDB table terraform locks the state kept in bucket with
+func (a *AWSProvider) EnsureTerraformLock(bucket string) error {
+	return EnsureDynamoDBLockTable(a.sess, TerraformLockTable(bucket))
+}
+
+// DeleteTerraformLock deletes the DynamoDB table created by EnsureTerraformLock
+func (a *AWSProvider) DeleteTerraformLock(bucket string) error {
+	return DeleteDynamoDBLockTable(a.sess, TerraformLockTable(bucket))
+}
+
+// EnsureDynamoDBLockTable creates the named table, with the LockID key expected by terraform's s3 backend,
+// unless it already exists
+func EnsureDynamoDBLockTable(sess *session.Session, name string) error {
+	dynamoDBClient := dynamodb.New(sess)
+
+	_, err := dynamoDBClient.DescribeTable(&dynamodb.DescribeTableInput{TableName: &name})
+	if err == nil {
+		return nil
+	}
+	if !isDynamoDBError(err, dynamodb.ErrCodeResourceNotFoundException) {
+		return fmt.Errorf("error checking for terraform lock table [%v]: [%v]", name, err)
+	}
+
+	_, err = dynamoDBClient.CreateTable(&dynamodb.CreateTableInput{
+		TableName:   &name,
+		BillingMode: aws.String(dynamodb.BillingModePayPerRequest),
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{{
+			AttributeName: aws.String("LockID"),
+			AttributeType: aws.String(dynamodb.ScalarAttributeTypeS),
+		}},
+		KeySchema: []*dynamodb.KeySchemaElement{{
+			AttributeName: aws.String("LockID"),
+			KeyType:       aws.String(dynamodb.KeyTypeHash),
+		}},
+	})
+	// Another command may have created the table since it was checked for
+	if err != nil && !isDynamoDBError(err, dynamodb.ErrCodeResourceInUseException) {
+		return fmt.Errorf("error creating terraform lock table [%v]: [%v]", name, err)
+	}
+
+	return dynamoDBClient.WaitUntilTableExists(&dynamodb.DescribeTableInput{TableName: &name})
+}
+
+// DeleteDynamoDBLockTable deletes the named table if it exists
+func DeleteDynamoDBLockTable(sess *session.Session, name string) error {
+	_, err := dynamodb.New(sess).DeleteTable(&dynamodb.DeleteTableInput{TableName: &name})
+	if err != nil && !isDynamoDBError(err, dynamodb.ErrCodeResourceNotFoundException) {
+		return fmt.Errorf("error deleting terraform lock table [%v]: [%v]", name, err)
+	}
+	return nil
+}
+
+func isDynamoDBError(err error, code string) bool {
+	awsErr, ok := err.(awserr.Error)
+	return ok && awsErr.Code() == code
+}
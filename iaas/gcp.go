@@ -8,15 +8,19 @@ import (
 	"io/ioutil"
 	"log"
 	"net"
+	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"cloud.google.com/go/storage"
+	"github.com/EngineerBetter/control-tower/util"
 	"golang.org/x/net/context"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/compute/v1"
 	clouddns "google.golang.org/api/dns/v1"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/iterator"
 
 	// PostgreSQL driver required at runtime
@@ -98,6 +102,16 @@ func (g *GCPProvider) Choose(c Choice) interface{} {
 	return c.GCP
 }
 
+// EnsureTerraformLock does nothing as terraform's gcs backend locks state natively
+func (g *GCPProvider) EnsureTerraformLock(bucket string) error {
+	return nil
+}
+
+// DeleteTerraformLock does nothing as EnsureTerraformLock creates nothing
+func (g *GCPProvider) DeleteTerraformLock(bucket string) error {
+	return nil
+}
+
 func (g *GCPProvider) DeleteVersionedBucket(name string) error {
 	bucket := g.storage.Bucket(name)
 	it := bucket.Objects(g.ctx, &storage.Query{Versions: true})
@@ -206,6 +220,40 @@ func (g *GCPProvider) WriteFile(bucket, path string, contents []byte) error {
 	return nil
 }
 
+// CreateFile writes the object unless it already exists, returning false if it did
+func (g *GCPProvider) CreateFile(bucket, path string, contents []byte) (bool, error) {
+	return CreateGCSObject(g.ctx, g.storage.Bucket(bucket), path, contents)
+}
+
+// CreateGCSObject writes an object with ifGenerationMatch=0, so that of several concurrent writers only one
+// creates it. It returns false if the object already exists.
+func CreateGCSObject(ctx context.Context, bucket *storage.BucketHandle, path string, contents []byte) (bool, error) {
+	wc := bucket.Object(path).If(storage.Conditions{DoesNotExist: true}).NewWriter(ctx)
+
+	if _, err := wc.Write(contents); err != nil {
+		return false, fmt.Errorf("failed to write %s to bucket: [%s]", path, err)
+	}
+
+	err := wc.Close()
+	if apiErr, ok := err.(*googleapi.Error); ok && apiErr.Code == http.StatusPreconditionFailed {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to close writer for %s: [%s]", path, err)
+	}
+
+	return true, nil
+}
+
+// DeleteFile deletes an object
+func (g *GCPProvider) DeleteFile(bucket, path string) error {
+	err := g.storage.Bucket(bucket).Object(path).Delete(g.ctx)
+	if err == storage.ErrObjectNotExist {
+		return nil
+	}
+	return err
+}
+
 func (g *GCPProvider) Region() string {
 	return g.region
 }
@@ -243,7 +291,7 @@ func (g *GCPProvider) EnsureFileExists(bucket, path string, defaultContents []by
 	return defaultContents, true, nil
 }
 
-func (g *GCPProvider) DeleteVolumes(volumesToDelete []string, deleteVolume func(ec2Client IEC2, volumeID *string) error) error {
+func (g *GCPProvider) DeleteVolumes(ctx context.Context, volumesToDelete []string, deleteVolume func(ec2Client IEC2, volumeID *string) error) error {
 	// @note: This will be covered in a later iteration as we need a deployment to try it
 	return errors.New("DeleteVolumes Not Implemented Yet")
 }
@@ -294,13 +342,13 @@ func (g *GCPProvider) CheckForWhitelistedIP(ip, firewallName string) (bool, erro
 }
 
 // DeleteVMsInVPC is a placeholder function used with AWS deployments
-func (g *GCPProvider) DeleteVMsInVPC(vpcID string) ([]string, error) {
+func (g *GCPProvider) DeleteVMsInVPC(ctx context.Context, vpcID string) ([]string, error) {
 	return []string{}, nil
 }
 
 //DeleteVMsInDeployment will delete all vms in a deployment apart from nat instance
-func (g *GCPProvider) DeleteVMsInDeployment(zone, project, deployment string) error {
-	c, err := google.DefaultClient(g.ctx, compute.CloudPlatformScope)
+func (g *GCPProvider) DeleteVMsInDeployment(ctx context.Context, zone, project, deployment string) error {
+	c, err := google.DefaultClient(ctx, compute.CloudPlatformScope)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -312,7 +360,7 @@ func (g *GCPProvider) DeleteVMsInDeployment(zone, project, deployment string) er
 
 	// gets all compute instances for the project
 	req := computeService.Instances.List(project, zone)
-	if err := req.Pages(g.ctx, func(page *compute.InstanceList) error {
+	if err := req.Pages(ctx, func(page *compute.InstanceList) error {
 		for _, instance := range page.Items {
 			name := instance.Name
 			networkName := instance.NetworkInterfaces[0].Network
@@ -320,11 +368,11 @@ func (g *GCPProvider) DeleteVMsInDeployment(zone, project, deployment string) er
 			if strings.HasSuffix(networkName, deployment) {
 				for _, disk := range instance.Disks {
 					fmt.Printf("Marking instance %s volume for deletion\n", name)
-					computeService.Instances.SetDiskAutoDelete(project, zone, name, true, disk.DeviceName).Context(g.ctx).Do()
+					computeService.Instances.SetDiskAutoDelete(project, zone, name, true, disk.DeviceName).Context(ctx).Do()
 				}
 				if !strings.HasSuffix(name, "nat-instance") {
 					fmt.Printf("Deleting instance %+v\n", name)
-					_, err := computeService.Instances.Delete(project, zone, name).Context(g.ctx).Do()
+					_, err := computeService.Instances.Delete(project, zone, name).Context(ctx).Do()
 					if err != nil {
 						return err
 					}
@@ -336,12 +384,14 @@ func (g *GCPProvider) DeleteVMsInDeployment(zone, project, deployment string) er
 		return err
 	}
 
-	start := time.Now().UTC()
+	waitCtx, cancel := context.WithTimeout(ctx, 3*time.Minute)
+	defer cancel()
+	backoff := util.NewBackoff(5*time.Second, 30*time.Second)
 	for {
 		found := false
 		req = computeService.Instances.List(project, zone)
 
-		if err := req.Pages(g.ctx, func(page *compute.InstanceList) error {
+		if err := req.Pages(waitCtx, func(page *compute.InstanceList) error {
 			for _, instance := range page.Items {
 				name := instance.Name
 				networkName := instance.NetworkInterfaces[0].Network
@@ -351,16 +401,18 @@ func (g *GCPProvider) DeleteVMsInDeployment(zone, project, deployment string) er
 				}
 			}
 			return nil
-		}); err != nil {
+		}); err != nil && waitCtx.Err() == nil {
 			return err
 		}
-		if !found {
+		if !found && waitCtx.Err() == nil {
 			return nil
 		}
-		if time.Since(start) > time.Second*180 {
+		if err := backoff.Wait(waitCtx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 			return fmt.Errorf("Instances not deleted after 3 minutes")
 		}
-		time.Sleep(time.Second * 10)
 	}
 }
 
@@ -445,3 +497,94 @@ func (g *GCPProvider) CreateDatabases(name, username, password string) error {
 	}
 	return nil
 }
+
+// ListBuckets returns the names of all buckets in the GCP project
+func (g *GCPProvider) ListBuckets() ([]string, error) {
+	project, err := g.Attr("project")
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	it := g.storage.Buckets(g.ctx, project)
+	for {
+		battrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, battrs.Name)
+	}
+
+	return names, nil
+}
+
+// BucketRegion returns the provider's region, as GCS buckets can be read from any region
+func (g *GCPProvider) BucketRegion(name string) (string, error) {
+	return g.region, nil
+}
+
+// FileLastModified returns the time the specified object was last written
+func (g *GCPProvider) FileLastModified(bucket, path string) (time.Time, error) {
+	attrs, err := g.storage.Bucket(bucket).Object(path).Attrs(g.ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return attrs.Updated, nil
+}
+
+// ListFileVersions returns every stored generation of the specified object, newest first
+func (g *GCPProvider) ListFileVersions(bucket, path string) ([]FileVersion, error) {
+	versions := []FileVersion{}
+	it := g.storage.Bucket(bucket).Objects(g.ctx, &storage.Query{Prefix: path, Versions: true})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if attrs.Name != path {
+			continue
+		}
+
+		versions = append(versions, FileVersion{
+			ID:           strconv.FormatInt(attrs.Generation, 10),
+			LastModified: attrs.Updated,
+			Current:      attrs.Deleted.IsZero(),
+		})
+	}
+
+	SortFileVersions(versions)
+	return versions, nil
+}
+
+// LoadFileVersion loads a specific generation of an object
+func (g *GCPProvider) LoadFileVersion(bucket, path, versionID string) ([]byte, error) {
+	generation, err := strconv.ParseInt(versionID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid generation [%v] of %v: [%v]", versionID, path, err)
+	}
+
+	rc, err := g.storage.Bucket(bucket).Object(path).Generation(generation).NewReader(g.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return ioutil.ReadAll(rc)
+}
+
+// DeleteFileVersion permanently deletes a specific generation of an object
+func (g *GCPProvider) DeleteFileVersion(bucket, path, versionID string) error {
+	generation, err := strconv.ParseInt(versionID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid generation [%v] of %v: [%v]", versionID, path, err)
+	}
+
+	return g.storage.Bucket(bucket).Object(path).Generation(generation).Delete(g.ctx)
+}
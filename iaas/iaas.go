@@ -1,15 +1,19 @@
 package iaas
 
 import (
+	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 )
 
 // Choice is an interface which can help on the abstraction of provider data
 // by defining any kind of data mapped against the available providers
 type Choice struct {
-	AWS interface{}
-	GCP interface{}
+	AWS   interface{}
+	GCP   interface{}
+	Azure interface{}
 }
 
 type Name int
@@ -18,12 +22,14 @@ const (
 	Unknown = iota
 	AWS
 	GCP
+	Azure
 )
 
 var names = []string{
 	"Unknown",
 	"AWS",
 	"GCP",
+	"Azure",
 }
 
 func (n Name) String() string {
@@ -33,31 +39,60 @@ func (n Name) String() string {
 func Validate(name string) (Name, error) {
 	name = strings.ToUpper(name)
 	for n := len(names) - 1; n > 0; n-- {
-		if name == names[n] {
+		if name == strings.ToUpper(names[n]) {
 			return Name(n), nil
 		}
 	}
 	return Unknown, fmt.Errorf("cannot map iaas [%s] as any of %+v", name, names[1:])
 }
 
+// FileVersion identifies one of the stored versions of a file in a versioned bucket
+type FileVersion struct {
+	ID           string    `json:"id"`
+	LastModified time.Time `json:"last_modified"`
+	// Current is true for the version which is read when the file is loaded
+	Current bool `json:"-"`
+}
+
+// SortFileVersions orders versions newest first, putting the current version first when versions were written in the same instant
+func SortFileVersions(versions []FileVersion) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		if versions[i].LastModified.Equal(versions[j].LastModified) {
+			return versions[i].Current && !versions[j].Current
+		}
+		return versions[i].LastModified.After(versions[j].LastModified)
+	})
+}
+
 //go:generate counterfeiter . Provider
 // Provider represents actions taken against AWS
 type Provider interface {
 	Attr(string) (string, error)
 	BucketExists(name string) (bool, error)
+	BucketRegion(name string) (string, error)
 	CheckForWhitelistedIP(ip, securityGroup string) (bool, error)
 	CreateBucket(name string) error
 	CreateDatabases(name, username, password string) error
+	// CreateFile writes a file unless it already exists, returning false if it did
+	CreateFile(bucket, path string, contents []byte) (bool, error)
+	DeleteFile(bucket, path string) error
+	DeleteFileVersion(bucket, path, versionID string) error
+	DeleteTerraformLock(bucket string) error
 	DeleteVersionedBucket(name string) error
-	DeleteVMsInDeployment(zone, project, deployment string) error
-	DeleteVMsInVPC(vpcID string) ([]string, error)
-	DeleteVolumes(volumesToDelete []string, deleteVolume func(ec2Client IEC2, volumeID *string) error) error
+	DeleteVMsInDeployment(ctx context.Context, zone, project, deployment string) error
+	DeleteVMsInVPC(ctx context.Context, vpcID string) ([]string, error)
+	DeleteVolumes(ctx context.Context, volumesToDelete []string, deleteVolume func(ec2Client IEC2, volumeID *string) error) error
 	EnsureFileExists(bucket, path string, defaultContents []byte) ([]byte, bool, error)
+	EnsureTerraformLock(bucket string) error
+	FileLastModified(bucket, path string) (time.Time, error)
 	FindLongestMatchingHostedZone(subdomain string) (string, string, error)
 	HasFile(bucket, path string) (bool, error)
 	DBType(name string) string
 	IAAS() Name
+	ListBuckets() ([]string, error)
+	ListFileVersions(bucket, path string) ([]FileVersion, error)
 	LoadFile(bucket, path string) ([]byte, error)
+	LoadFileVersion(bucket, path, versionID string) ([]byte, error)
 	Region() string
 	WriteFile(bucket, path string, contents []byte) error
 	Zone(string, string) string
@@ -78,6 +113,11 @@ func New(iaasName Name, region string) (Provider, error) {
 			region = "europe-west1"
 		}
 		return newGCP(region, GCPStorage())
+	case Azure:
+		if region == "" {
+			region = "westeurope"
+		}
+		return newAzure(region)
 	}
 
 	return nil, fmt.Errorf("IAAS not supported: [%s]", iaasName)
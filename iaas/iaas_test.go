@@ -56,6 +56,26 @@ func TestNew(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "return azure provider",
+			args: args{
+				iaas:   iaas.Azure,
+				region: "aRegion",
+			},
+			want:    iaas.Azure,
+			wantErr: false,
+			setup: func(t *testing.T) string {
+				for _, envVar := range []string{"AZURE_SUBSCRIPTION_ID", "AZURE_TENANT_ID", "AZURE_CLIENT_ID", "AZURE_CLIENT_SECRET", "AZURE_STORAGE_ACCOUNT", "AZURE_STORAGE_RESOURCE_GROUP"} {
+					os.Setenv(envVar, "a-value")
+				}
+				return ""
+			},
+			cleanup: func(t *testing.T, s string) {
+				for _, envVar := range []string{"AZURE_SUBSCRIPTION_ID", "AZURE_TENANT_ID", "AZURE_CLIENT_ID", "AZURE_CLIENT_SECRET", "AZURE_STORAGE_ACCOUNT", "AZURE_STORAGE_RESOURCE_GROUP"} {
+					os.Unsetenv(envVar)
+				}
+			},
+		},
 		{
 			name: "does not care about case",
 			args: args{
@@ -110,6 +130,12 @@ func TestAssosiate(t *testing.T) {
 			want:    iaas.AWS,
 			wantErr: false,
 		},
+		{
+			name:    "get the Azure Name successfully case insensitive",
+			arg:     "azure",
+			want:    iaas.Azure,
+			wantErr: false,
+		},
 		{
 			name:    "fail on unknown iaas name",
 			arg:     "aProvider",
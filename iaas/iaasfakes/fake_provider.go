@@ -2,7 +2,9 @@
 package iaasfakes
 
 import (
+	"context"
 	"sync"
+	"time"
 
 	"github.com/EngineerBetter/control-tower/iaas"
 )
@@ -34,6 +36,19 @@ type FakeProvider struct {
 		result1 bool
 		result2 error
 	}
+	BucketRegionStub        func(string) (string, error)
+	bucketRegionMutex       sync.RWMutex
+	bucketRegionArgsForCall []struct {
+		arg1 string
+	}
+	bucketRegionReturns struct {
+		result1 string
+		result2 error
+	}
+	bucketRegionReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	CheckForWhitelistedIPStub        func(string, string) (bool, error)
 	checkForWhitelistedIPMutex       sync.RWMutex
 	checkForWhitelistedIPArgsForCall []struct {
@@ -83,6 +98,21 @@ type FakeProvider struct {
 	createDatabasesReturnsOnCall map[int]struct {
 		result1 error
 	}
+	CreateFileStub        func(string, string, []byte) (bool, error)
+	createFileMutex       sync.RWMutex
+	createFileArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 []byte
+	}
+	createFileReturns struct {
+		result1 bool
+		result2 error
+	}
+	createFileReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
 	DBTypeStub        func(string) string
 	dBTypeMutex       sync.RWMutex
 	dBTypeArgsForCall []struct {
@@ -94,12 +124,49 @@ type FakeProvider struct {
 	dBTypeReturnsOnCall map[int]struct {
 		result1 string
 	}
-	DeleteVMsInDeploymentStub        func(string, string, string) error
+	DeleteFileStub        func(string, string) error
+	deleteFileMutex       sync.RWMutex
+	deleteFileArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	deleteFileReturns struct {
+		result1 error
+	}
+	deleteFileReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteFileVersionStub        func(string, string, string) error
+	deleteFileVersionMutex       sync.RWMutex
+	deleteFileVersionArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	deleteFileVersionReturns struct {
+		result1 error
+	}
+	deleteFileVersionReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteTerraformLockStub        func(string) error
+	deleteTerraformLockMutex       sync.RWMutex
+	deleteTerraformLockArgsForCall []struct {
+		arg1 string
+	}
+	deleteTerraformLockReturns struct {
+		result1 error
+	}
+	deleteTerraformLockReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteVMsInDeploymentStub        func(context.Context, string, string, string) error
 	deleteVMsInDeploymentMutex       sync.RWMutex
 	deleteVMsInDeploymentArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
 		arg3 string
+		arg4 string
 	}
 	deleteVMsInDeploymentReturns struct {
 		result1 error
@@ -107,10 +174,11 @@ type FakeProvider struct {
 	deleteVMsInDeploymentReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DeleteVMsInVPCStub        func(string) ([]string, error)
+	DeleteVMsInVPCStub        func(context.Context, string) ([]string, error)
 	deleteVMsInVPCMutex       sync.RWMutex
 	deleteVMsInVPCArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	deleteVMsInVPCReturns struct {
 		result1 []string
@@ -131,11 +199,12 @@ type FakeProvider struct {
 	deleteVersionedBucketReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DeleteVolumesStub        func([]string, func(ec2Client iaas.IEC2, volumeID *string) error) error
+	DeleteVolumesStub        func(context.Context, []string, func(ec2Client iaas.IEC2, volumeID *string) error) error
 	deleteVolumesMutex       sync.RWMutex
 	deleteVolumesArgsForCall []struct {
-		arg1 []string
-		arg2 func(ec2Client iaas.IEC2, volumeID *string) error
+		arg1 context.Context
+		arg2 []string
+		arg3 func(ec2Client iaas.IEC2, volumeID *string) error
 	}
 	deleteVolumesReturns struct {
 		result1 error
@@ -160,6 +229,31 @@ type FakeProvider struct {
 		result2 bool
 		result3 error
 	}
+	EnsureTerraformLockStub        func(string) error
+	ensureTerraformLockMutex       sync.RWMutex
+	ensureTerraformLockArgsForCall []struct {
+		arg1 string
+	}
+	ensureTerraformLockReturns struct {
+		result1 error
+	}
+	ensureTerraformLockReturnsOnCall map[int]struct {
+		result1 error
+	}
+	FileLastModifiedStub        func(string, string) (time.Time, error)
+	fileLastModifiedMutex       sync.RWMutex
+	fileLastModifiedArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	fileLastModifiedReturns struct {
+		result1 time.Time
+		result2 error
+	}
+	fileLastModifiedReturnsOnCall map[int]struct {
+		result1 time.Time
+		result2 error
+	}
 	FindLongestMatchingHostedZoneStub        func(string) (string, string, error)
 	findLongestMatchingHostedZoneMutex       sync.RWMutex
 	findLongestMatchingHostedZoneArgsForCall []struct {
@@ -199,6 +293,32 @@ type FakeProvider struct {
 	iAASReturnsOnCall map[int]struct {
 		result1 iaas.Name
 	}
+	ListBucketsStub        func() ([]string, error)
+	listBucketsMutex       sync.RWMutex
+	listBucketsArgsForCall []struct {
+	}
+	listBucketsReturns struct {
+		result1 []string
+		result2 error
+	}
+	listBucketsReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
+	ListFileVersionsStub        func(string, string) ([]iaas.FileVersion, error)
+	listFileVersionsMutex       sync.RWMutex
+	listFileVersionsArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	listFileVersionsReturns struct {
+		result1 []iaas.FileVersion
+		result2 error
+	}
+	listFileVersionsReturnsOnCall map[int]struct {
+		result1 []iaas.FileVersion
+		result2 error
+	}
 	LoadFileStub        func(string, string) ([]byte, error)
 	loadFileMutex       sync.RWMutex
 	loadFileArgsForCall []struct {
@@ -213,6 +333,21 @@ type FakeProvider struct {
 		result1 []byte
 		result2 error
 	}
+	LoadFileVersionStub        func(string, string, string) ([]byte, error)
+	loadFileVersionMutex       sync.RWMutex
+	loadFileVersionArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	loadFileVersionReturns struct {
+		result1 []byte
+		result2 error
+	}
+	loadFileVersionReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
 	RegionStub        func() string
 	regionMutex       sync.RWMutex
 	regionArgsForCall []struct {
@@ -378,6 +513,69 @@ func (fake *FakeProvider) BucketExistsReturnsOnCall(i int, result1 bool, result2
 	}{result1, result2}
 }
 
+func (fake *FakeProvider) BucketRegion(arg1 string) (string, error) {
+	fake.bucketRegionMutex.Lock()
+	ret, specificReturn := fake.bucketRegionReturnsOnCall[len(fake.bucketRegionArgsForCall)]
+	fake.bucketRegionArgsForCall = append(fake.bucketRegionArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("BucketRegion", []interface{}{arg1})
+	fake.bucketRegionMutex.Unlock()
+	if fake.BucketRegionStub != nil {
+		return fake.BucketRegionStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.bucketRegionReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeProvider) BucketRegionCallCount() int {
+	fake.bucketRegionMutex.RLock()
+	defer fake.bucketRegionMutex.RUnlock()
+	return len(fake.bucketRegionArgsForCall)
+}
+
+func (fake *FakeProvider) BucketRegionCalls(stub func(string) (string, error)) {
+	fake.bucketRegionMutex.Lock()
+	defer fake.bucketRegionMutex.Unlock()
+	fake.BucketRegionStub = stub
+}
+
+func (fake *FakeProvider) BucketRegionArgsForCall(i int) string {
+	fake.bucketRegionMutex.RLock()
+	defer fake.bucketRegionMutex.RUnlock()
+	argsForCall := fake.bucketRegionArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeProvider) BucketRegionReturns(result1 string, result2 error) {
+	fake.bucketRegionMutex.Lock()
+	defer fake.bucketRegionMutex.Unlock()
+	fake.BucketRegionStub = nil
+	fake.bucketRegionReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) BucketRegionReturnsOnCall(i int, result1 string, result2 error) {
+	fake.bucketRegionMutex.Lock()
+	defer fake.bucketRegionMutex.Unlock()
+	fake.BucketRegionStub = nil
+	if fake.bucketRegionReturnsOnCall == nil {
+		fake.bucketRegionReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.bucketRegionReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeProvider) CheckForWhitelistedIP(arg1 string, arg2 string) (bool, error) {
 	fake.checkForWhitelistedIPMutex.Lock()
 	ret, specificReturn := fake.checkForWhitelistedIPReturnsOnCall[len(fake.checkForWhitelistedIPArgsForCall)]
@@ -624,6 +822,76 @@ func (fake *FakeProvider) CreateDatabasesReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeProvider) CreateFile(arg1 string, arg2 string, arg3 []byte) (bool, error) {
+	var arg3Copy []byte
+	if arg3 != nil {
+		arg3Copy = make([]byte, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.createFileMutex.Lock()
+	ret, specificReturn := fake.createFileReturnsOnCall[len(fake.createFileArgsForCall)]
+	fake.createFileArgsForCall = append(fake.createFileArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 []byte
+	}{arg1, arg2, arg3Copy})
+	fake.recordInvocation("CreateFile", []interface{}{arg1, arg2, arg3Copy})
+	fake.createFileMutex.Unlock()
+	if fake.CreateFileStub != nil {
+		return fake.CreateFileStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.createFileReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeProvider) CreateFileCallCount() int {
+	fake.createFileMutex.RLock()
+	defer fake.createFileMutex.RUnlock()
+	return len(fake.createFileArgsForCall)
+}
+
+func (fake *FakeProvider) CreateFileCalls(stub func(string, string, []byte) (bool, error)) {
+	fake.createFileMutex.Lock()
+	defer fake.createFileMutex.Unlock()
+	fake.CreateFileStub = stub
+}
+
+func (fake *FakeProvider) CreateFileArgsForCall(i int) (string, string, []byte) {
+	fake.createFileMutex.RLock()
+	defer fake.createFileMutex.RUnlock()
+	argsForCall := fake.createFileArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeProvider) CreateFileReturns(result1 bool, result2 error) {
+	fake.createFileMutex.Lock()
+	defer fake.createFileMutex.Unlock()
+	fake.CreateFileStub = nil
+	fake.createFileReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) CreateFileReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.createFileMutex.Lock()
+	defer fake.createFileMutex.Unlock()
+	fake.CreateFileStub = nil
+	if fake.createFileReturnsOnCall == nil {
+		fake.createFileReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.createFileReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeProvider) DBType(arg1 string) string {
 	fake.dBTypeMutex.Lock()
 	ret, specificReturn := fake.dBTypeReturnsOnCall[len(fake.dBTypeArgsForCall)]
@@ -684,18 +952,202 @@ func (fake *FakeProvider) DBTypeReturnsOnCall(i int, result1 string) {
 	}{result1}
 }
 
-func (fake *FakeProvider) DeleteVMsInDeployment(arg1 string, arg2 string, arg3 string) error {
+func (fake *FakeProvider) DeleteFile(arg1 string, arg2 string) error {
+	fake.deleteFileMutex.Lock()
+	ret, specificReturn := fake.deleteFileReturnsOnCall[len(fake.deleteFileArgsForCall)]
+	fake.deleteFileArgsForCall = append(fake.deleteFileArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("DeleteFile", []interface{}{arg1, arg2})
+	fake.deleteFileMutex.Unlock()
+	if fake.DeleteFileStub != nil {
+		return fake.DeleteFileStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deleteFileReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeProvider) DeleteFileCallCount() int {
+	fake.deleteFileMutex.RLock()
+	defer fake.deleteFileMutex.RUnlock()
+	return len(fake.deleteFileArgsForCall)
+}
+
+func (fake *FakeProvider) DeleteFileCalls(stub func(string, string) error) {
+	fake.deleteFileMutex.Lock()
+	defer fake.deleteFileMutex.Unlock()
+	fake.DeleteFileStub = stub
+}
+
+func (fake *FakeProvider) DeleteFileArgsForCall(i int) (string, string) {
+	fake.deleteFileMutex.RLock()
+	defer fake.deleteFileMutex.RUnlock()
+	argsForCall := fake.deleteFileArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProvider) DeleteFileReturns(result1 error) {
+	fake.deleteFileMutex.Lock()
+	defer fake.deleteFileMutex.Unlock()
+	fake.DeleteFileStub = nil
+	fake.deleteFileReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) DeleteFileReturnsOnCall(i int, result1 error) {
+	fake.deleteFileMutex.Lock()
+	defer fake.deleteFileMutex.Unlock()
+	fake.DeleteFileStub = nil
+	if fake.deleteFileReturnsOnCall == nil {
+		fake.deleteFileReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteFileReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) DeleteFileVersion(arg1 string, arg2 string, arg3 string) error {
+	fake.deleteFileVersionMutex.Lock()
+	ret, specificReturn := fake.deleteFileVersionReturnsOnCall[len(fake.deleteFileVersionArgsForCall)]
+	fake.deleteFileVersionArgsForCall = append(fake.deleteFileVersionArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("DeleteFileVersion", []interface{}{arg1, arg2, arg3})
+	fake.deleteFileVersionMutex.Unlock()
+	if fake.DeleteFileVersionStub != nil {
+		return fake.DeleteFileVersionStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deleteFileVersionReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeProvider) DeleteFileVersionCallCount() int {
+	fake.deleteFileVersionMutex.RLock()
+	defer fake.deleteFileVersionMutex.RUnlock()
+	return len(fake.deleteFileVersionArgsForCall)
+}
+
+func (fake *FakeProvider) DeleteFileVersionCalls(stub func(string, string, string) error) {
+	fake.deleteFileVersionMutex.Lock()
+	defer fake.deleteFileVersionMutex.Unlock()
+	fake.DeleteFileVersionStub = stub
+}
+
+func (fake *FakeProvider) DeleteFileVersionArgsForCall(i int) (string, string, string) {
+	fake.deleteFileVersionMutex.RLock()
+	defer fake.deleteFileVersionMutex.RUnlock()
+	argsForCall := fake.deleteFileVersionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeProvider) DeleteFileVersionReturns(result1 error) {
+	fake.deleteFileVersionMutex.Lock()
+	defer fake.deleteFileVersionMutex.Unlock()
+	fake.DeleteFileVersionStub = nil
+	fake.deleteFileVersionReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) DeleteFileVersionReturnsOnCall(i int, result1 error) {
+	fake.deleteFileVersionMutex.Lock()
+	defer fake.deleteFileVersionMutex.Unlock()
+	fake.DeleteFileVersionStub = nil
+	if fake.deleteFileVersionReturnsOnCall == nil {
+		fake.deleteFileVersionReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteFileVersionReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) DeleteTerraformLock(arg1 string) error {
+	fake.deleteTerraformLockMutex.Lock()
+	ret, specificReturn := fake.deleteTerraformLockReturnsOnCall[len(fake.deleteTerraformLockArgsForCall)]
+	fake.deleteTerraformLockArgsForCall = append(fake.deleteTerraformLockArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("DeleteTerraformLock", []interface{}{arg1})
+	fake.deleteTerraformLockMutex.Unlock()
+	if fake.DeleteTerraformLockStub != nil {
+		return fake.DeleteTerraformLockStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deleteTerraformLockReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeProvider) DeleteTerraformLockCallCount() int {
+	fake.deleteTerraformLockMutex.RLock()
+	defer fake.deleteTerraformLockMutex.RUnlock()
+	return len(fake.deleteTerraformLockArgsForCall)
+}
+
+func (fake *FakeProvider) DeleteTerraformLockCalls(stub func(string) error) {
+	fake.deleteTerraformLockMutex.Lock()
+	defer fake.deleteTerraformLockMutex.Unlock()
+	fake.DeleteTerraformLockStub = stub
+}
+
+func (fake *FakeProvider) DeleteTerraformLockArgsForCall(i int) string {
+	fake.deleteTerraformLockMutex.RLock()
+	defer fake.deleteTerraformLockMutex.RUnlock()
+	argsForCall := fake.deleteTerraformLockArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeProvider) DeleteTerraformLockReturns(result1 error) {
+	fake.deleteTerraformLockMutex.Lock()
+	defer fake.deleteTerraformLockMutex.Unlock()
+	fake.DeleteTerraformLockStub = nil
+	fake.deleteTerraformLockReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) DeleteTerraformLockReturnsOnCall(i int, result1 error) {
+	fake.deleteTerraformLockMutex.Lock()
+	defer fake.deleteTerraformLockMutex.Unlock()
+	fake.DeleteTerraformLockStub = nil
+	if fake.deleteTerraformLockReturnsOnCall == nil {
+		fake.deleteTerraformLockReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteTerraformLockReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) DeleteVMsInDeployment(arg1 context.Context, arg2 string, arg3 string, arg4 string) error {
 	fake.deleteVMsInDeploymentMutex.Lock()
 	ret, specificReturn := fake.deleteVMsInDeploymentReturnsOnCall[len(fake.deleteVMsInDeploymentArgsForCall)]
 	fake.deleteVMsInDeploymentArgsForCall = append(fake.deleteVMsInDeploymentArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
 		arg3 string
-	}{arg1, arg2, arg3})
-	fake.recordInvocation("DeleteVMsInDeployment", []interface{}{arg1, arg2, arg3})
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("DeleteVMsInDeployment", []interface{}{arg1, arg2, arg3, arg4})
 	fake.deleteVMsInDeploymentMutex.Unlock()
 	if fake.DeleteVMsInDeploymentStub != nil {
-		return fake.DeleteVMsInDeploymentStub(arg1, arg2, arg3)
+		return fake.DeleteVMsInDeploymentStub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
@@ -710,17 +1162,17 @@ func (fake *FakeProvider) DeleteVMsInDeploymentCallCount() int {
 	return len(fake.deleteVMsInDeploymentArgsForCall)
 }
 
-func (fake *FakeProvider) DeleteVMsInDeploymentCalls(stub func(string, string, string) error) {
+func (fake *FakeProvider) DeleteVMsInDeploymentCalls(stub func(context.Context, string, string, string) error) {
 	fake.deleteVMsInDeploymentMutex.Lock()
 	defer fake.deleteVMsInDeploymentMutex.Unlock()
 	fake.DeleteVMsInDeploymentStub = stub
 }
 
-func (fake *FakeProvider) DeleteVMsInDeploymentArgsForCall(i int) (string, string, string) {
+func (fake *FakeProvider) DeleteVMsInDeploymentArgsForCall(i int) (context.Context, string, string, string) {
 	fake.deleteVMsInDeploymentMutex.RLock()
 	defer fake.deleteVMsInDeploymentMutex.RUnlock()
 	argsForCall := fake.deleteVMsInDeploymentArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeProvider) DeleteVMsInDeploymentReturns(result1 error) {
@@ -746,16 +1198,17 @@ func (fake *FakeProvider) DeleteVMsInDeploymentReturnsOnCall(i int, result1 erro
 	}{result1}
 }
 
-func (fake *FakeProvider) DeleteVMsInVPC(arg1 string) ([]string, error) {
+func (fake *FakeProvider) DeleteVMsInVPC(arg1 context.Context, arg2 string) ([]string, error) {
 	fake.deleteVMsInVPCMutex.Lock()
 	ret, specificReturn := fake.deleteVMsInVPCReturnsOnCall[len(fake.deleteVMsInVPCArgsForCall)]
 	fake.deleteVMsInVPCArgsForCall = append(fake.deleteVMsInVPCArgsForCall, struct {
-		arg1 string
-	}{arg1})
-	fake.recordInvocation("DeleteVMsInVPC", []interface{}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("DeleteVMsInVPC", []interface{}{arg1, arg2})
 	fake.deleteVMsInVPCMutex.Unlock()
 	if fake.DeleteVMsInVPCStub != nil {
-		return fake.DeleteVMsInVPCStub(arg1)
+		return fake.DeleteVMsInVPCStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -770,17 +1223,17 @@ func (fake *FakeProvider) DeleteVMsInVPCCallCount() int {
 	return len(fake.deleteVMsInVPCArgsForCall)
 }
 
-func (fake *FakeProvider) DeleteVMsInVPCCalls(stub func(string) ([]string, error)) {
+func (fake *FakeProvider) DeleteVMsInVPCCalls(stub func(context.Context, string) ([]string, error)) {
 	fake.deleteVMsInVPCMutex.Lock()
 	defer fake.deleteVMsInVPCMutex.Unlock()
 	fake.DeleteVMsInVPCStub = stub
 }
 
-func (fake *FakeProvider) DeleteVMsInVPCArgsForCall(i int) string {
+func (fake *FakeProvider) DeleteVMsInVPCArgsForCall(i int) (context.Context, string) {
 	fake.deleteVMsInVPCMutex.RLock()
 	defer fake.deleteVMsInVPCMutex.RUnlock()
 	argsForCall := fake.deleteVMsInVPCArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeProvider) DeleteVMsInVPCReturns(result1 []string, result2 error) {
@@ -869,22 +1322,23 @@ func (fake *FakeProvider) DeleteVersionedBucketReturnsOnCall(i int, result1 erro
 	}{result1}
 }
 
-func (fake *FakeProvider) DeleteVolumes(arg1 []string, arg2 func(ec2Client iaas.IEC2, volumeID *string) error) error {
-	var arg1Copy []string
-	if arg1 != nil {
-		arg1Copy = make([]string, len(arg1))
-		copy(arg1Copy, arg1)
+func (fake *FakeProvider) DeleteVolumes(arg1 context.Context, arg2 []string, arg3 func(ec2Client iaas.IEC2, volumeID *string) error) error {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
 	}
 	fake.deleteVolumesMutex.Lock()
 	ret, specificReturn := fake.deleteVolumesReturnsOnCall[len(fake.deleteVolumesArgsForCall)]
 	fake.deleteVolumesArgsForCall = append(fake.deleteVolumesArgsForCall, struct {
-		arg1 []string
-		arg2 func(ec2Client iaas.IEC2, volumeID *string) error
-	}{arg1Copy, arg2})
-	fake.recordInvocation("DeleteVolumes", []interface{}{arg1Copy, arg2})
+		arg1 context.Context
+		arg2 []string
+		arg3 func(ec2Client iaas.IEC2, volumeID *string) error
+	}{arg1, arg2Copy, arg3})
+	fake.recordInvocation("DeleteVolumes", []interface{}{arg1, arg2Copy, arg3})
 	fake.deleteVolumesMutex.Unlock()
 	if fake.DeleteVolumesStub != nil {
-		return fake.DeleteVolumesStub(arg1, arg2)
+		return fake.DeleteVolumesStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -899,17 +1353,17 @@ func (fake *FakeProvider) DeleteVolumesCallCount() int {
 	return len(fake.deleteVolumesArgsForCall)
 }
 
-func (fake *FakeProvider) DeleteVolumesCalls(stub func([]string, func(ec2Client iaas.IEC2, volumeID *string) error) error) {
+func (fake *FakeProvider) DeleteVolumesCalls(stub func(context.Context, []string, func(ec2Client iaas.IEC2, volumeID *string) error) error) {
 	fake.deleteVolumesMutex.Lock()
 	defer fake.deleteVolumesMutex.Unlock()
 	fake.DeleteVolumesStub = stub
 }
 
-func (fake *FakeProvider) DeleteVolumesArgsForCall(i int) ([]string, func(ec2Client iaas.IEC2, volumeID *string) error) {
+func (fake *FakeProvider) DeleteVolumesArgsForCall(i int) (context.Context, []string, func(ec2Client iaas.IEC2, volumeID *string) error) {
 	fake.deleteVolumesMutex.RLock()
 	defer fake.deleteVolumesMutex.RUnlock()
 	argsForCall := fake.deleteVolumesArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeProvider) DeleteVolumesReturns(result1 error) {
@@ -1008,6 +1462,130 @@ func (fake *FakeProvider) EnsureFileExistsReturnsOnCall(i int, result1 []byte, r
 	}{result1, result2, result3}
 }
 
+func (fake *FakeProvider) EnsureTerraformLock(arg1 string) error {
+	fake.ensureTerraformLockMutex.Lock()
+	ret, specificReturn := fake.ensureTerraformLockReturnsOnCall[len(fake.ensureTerraformLockArgsForCall)]
+	fake.ensureTerraformLockArgsForCall = append(fake.ensureTerraformLockArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("EnsureTerraformLock", []interface{}{arg1})
+	fake.ensureTerraformLockMutex.Unlock()
+	if fake.EnsureTerraformLockStub != nil {
+		return fake.EnsureTerraformLockStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.ensureTerraformLockReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeProvider) EnsureTerraformLockCallCount() int {
+	fake.ensureTerraformLockMutex.RLock()
+	defer fake.ensureTerraformLockMutex.RUnlock()
+	return len(fake.ensureTerraformLockArgsForCall)
+}
+
+func (fake *FakeProvider) EnsureTerraformLockCalls(stub func(string) error) {
+	fake.ensureTerraformLockMutex.Lock()
+	defer fake.ensureTerraformLockMutex.Unlock()
+	fake.EnsureTerraformLockStub = stub
+}
+
+func (fake *FakeProvider) EnsureTerraformLockArgsForCall(i int) string {
+	fake.ensureTerraformLockMutex.RLock()
+	defer fake.ensureTerraformLockMutex.RUnlock()
+	argsForCall := fake.ensureTerraformLockArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeProvider) EnsureTerraformLockReturns(result1 error) {
+	fake.ensureTerraformLockMutex.Lock()
+	defer fake.ensureTerraformLockMutex.Unlock()
+	fake.EnsureTerraformLockStub = nil
+	fake.ensureTerraformLockReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) EnsureTerraformLockReturnsOnCall(i int, result1 error) {
+	fake.ensureTerraformLockMutex.Lock()
+	defer fake.ensureTerraformLockMutex.Unlock()
+	fake.EnsureTerraformLockStub = nil
+	if fake.ensureTerraformLockReturnsOnCall == nil {
+		fake.ensureTerraformLockReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.ensureTerraformLockReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) FileLastModified(arg1 string, arg2 string) (time.Time, error) {
+	fake.fileLastModifiedMutex.Lock()
+	ret, specificReturn := fake.fileLastModifiedReturnsOnCall[len(fake.fileLastModifiedArgsForCall)]
+	fake.fileLastModifiedArgsForCall = append(fake.fileLastModifiedArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("FileLastModified", []interface{}{arg1, arg2})
+	fake.fileLastModifiedMutex.Unlock()
+	if fake.FileLastModifiedStub != nil {
+		return fake.FileLastModifiedStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.fileLastModifiedReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeProvider) FileLastModifiedCallCount() int {
+	fake.fileLastModifiedMutex.RLock()
+	defer fake.fileLastModifiedMutex.RUnlock()
+	return len(fake.fileLastModifiedArgsForCall)
+}
+
+func (fake *FakeProvider) FileLastModifiedCalls(stub func(string, string) (time.Time, error)) {
+	fake.fileLastModifiedMutex.Lock()
+	defer fake.fileLastModifiedMutex.Unlock()
+	fake.FileLastModifiedStub = stub
+}
+
+func (fake *FakeProvider) FileLastModifiedArgsForCall(i int) (string, string) {
+	fake.fileLastModifiedMutex.RLock()
+	defer fake.fileLastModifiedMutex.RUnlock()
+	argsForCall := fake.fileLastModifiedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProvider) FileLastModifiedReturns(result1 time.Time, result2 error) {
+	fake.fileLastModifiedMutex.Lock()
+	defer fake.fileLastModifiedMutex.Unlock()
+	fake.FileLastModifiedStub = nil
+	fake.fileLastModifiedReturns = struct {
+		result1 time.Time
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) FileLastModifiedReturnsOnCall(i int, result1 time.Time, result2 error) {
+	fake.fileLastModifiedMutex.Lock()
+	defer fake.fileLastModifiedMutex.Unlock()
+	fake.FileLastModifiedStub = nil
+	if fake.fileLastModifiedReturnsOnCall == nil {
+		fake.fileLastModifiedReturnsOnCall = make(map[int]struct {
+			result1 time.Time
+			result2 error
+		})
+	}
+	fake.fileLastModifiedReturnsOnCall[i] = struct {
+		result1 time.Time
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeProvider) FindLongestMatchingHostedZone(arg1 string) (string, string, error) {
 	fake.findLongestMatchingHostedZoneMutex.Lock()
 	ret, specificReturn := fake.findLongestMatchingHostedZoneReturnsOnCall[len(fake.findLongestMatchingHostedZoneArgsForCall)]
@@ -1190,6 +1768,125 @@ func (fake *FakeProvider) IAASReturnsOnCall(i int, result1 iaas.Name) {
 	}{result1}
 }
 
+func (fake *FakeProvider) ListBuckets() ([]string, error) {
+	fake.listBucketsMutex.Lock()
+	ret, specificReturn := fake.listBucketsReturnsOnCall[len(fake.listBucketsArgsForCall)]
+	fake.listBucketsArgsForCall = append(fake.listBucketsArgsForCall, struct {
+	}{})
+	fake.recordInvocation("ListBuckets", []interface{}{})
+	fake.listBucketsMutex.Unlock()
+	if fake.ListBucketsStub != nil {
+		return fake.ListBucketsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listBucketsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeProvider) ListBucketsCallCount() int {
+	fake.listBucketsMutex.RLock()
+	defer fake.listBucketsMutex.RUnlock()
+	return len(fake.listBucketsArgsForCall)
+}
+
+func (fake *FakeProvider) ListBucketsCalls(stub func() ([]string, error)) {
+	fake.listBucketsMutex.Lock()
+	defer fake.listBucketsMutex.Unlock()
+	fake.ListBucketsStub = stub
+}
+
+func (fake *FakeProvider) ListBucketsReturns(result1 []string, result2 error) {
+	fake.listBucketsMutex.Lock()
+	defer fake.listBucketsMutex.Unlock()
+	fake.ListBucketsStub = nil
+	fake.listBucketsReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) ListBucketsReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.listBucketsMutex.Lock()
+	defer fake.listBucketsMutex.Unlock()
+	fake.ListBucketsStub = nil
+	if fake.listBucketsReturnsOnCall == nil {
+		fake.listBucketsReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.listBucketsReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) ListFileVersions(arg1 string, arg2 string) ([]iaas.FileVersion, error) {
+	fake.listFileVersionsMutex.Lock()
+	ret, specificReturn := fake.listFileVersionsReturnsOnCall[len(fake.listFileVersionsArgsForCall)]
+	fake.listFileVersionsArgsForCall = append(fake.listFileVersionsArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("ListFileVersions", []interface{}{arg1, arg2})
+	fake.listFileVersionsMutex.Unlock()
+	if fake.ListFileVersionsStub != nil {
+		return fake.ListFileVersionsStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listFileVersionsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeProvider) ListFileVersionsCallCount() int {
+	fake.listFileVersionsMutex.RLock()
+	defer fake.listFileVersionsMutex.RUnlock()
+	return len(fake.listFileVersionsArgsForCall)
+}
+
+func (fake *FakeProvider) ListFileVersionsCalls(stub func(string, string) ([]iaas.FileVersion, error)) {
+	fake.listFileVersionsMutex.Lock()
+	defer fake.listFileVersionsMutex.Unlock()
+	fake.ListFileVersionsStub = stub
+}
+
+func (fake *FakeProvider) ListFileVersionsArgsForCall(i int) (string, string) {
+	fake.listFileVersionsMutex.RLock()
+	defer fake.listFileVersionsMutex.RUnlock()
+	argsForCall := fake.listFileVersionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProvider) ListFileVersionsReturns(result1 []iaas.FileVersion, result2 error) {
+	fake.listFileVersionsMutex.Lock()
+	defer fake.listFileVersionsMutex.Unlock()
+	fake.ListFileVersionsStub = nil
+	fake.listFileVersionsReturns = struct {
+		result1 []iaas.FileVersion
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) ListFileVersionsReturnsOnCall(i int, result1 []iaas.FileVersion, result2 error) {
+	fake.listFileVersionsMutex.Lock()
+	defer fake.listFileVersionsMutex.Unlock()
+	fake.ListFileVersionsStub = nil
+	if fake.listFileVersionsReturnsOnCall == nil {
+		fake.listFileVersionsReturnsOnCall = make(map[int]struct {
+			result1 []iaas.FileVersion
+			result2 error
+		})
+	}
+	fake.listFileVersionsReturnsOnCall[i] = struct {
+		result1 []iaas.FileVersion
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeProvider) LoadFile(arg1 string, arg2 string) ([]byte, error) {
 	fake.loadFileMutex.Lock()
 	ret, specificReturn := fake.loadFileReturnsOnCall[len(fake.loadFileArgsForCall)]
@@ -1254,6 +1951,71 @@ func (fake *FakeProvider) LoadFileReturnsOnCall(i int, result1 []byte, result2 e
 	}{result1, result2}
 }
 
+func (fake *FakeProvider) LoadFileVersion(arg1 string, arg2 string, arg3 string) ([]byte, error) {
+	fake.loadFileVersionMutex.Lock()
+	ret, specificReturn := fake.loadFileVersionReturnsOnCall[len(fake.loadFileVersionArgsForCall)]
+	fake.loadFileVersionArgsForCall = append(fake.loadFileVersionArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("LoadFileVersion", []interface{}{arg1, arg2, arg3})
+	fake.loadFileVersionMutex.Unlock()
+	if fake.LoadFileVersionStub != nil {
+		return fake.LoadFileVersionStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.loadFileVersionReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeProvider) LoadFileVersionCallCount() int {
+	fake.loadFileVersionMutex.RLock()
+	defer fake.loadFileVersionMutex.RUnlock()
+	return len(fake.loadFileVersionArgsForCall)
+}
+
+func (fake *FakeProvider) LoadFileVersionCalls(stub func(string, string, string) ([]byte, error)) {
+	fake.loadFileVersionMutex.Lock()
+	defer fake.loadFileVersionMutex.Unlock()
+	fake.LoadFileVersionStub = stub
+}
+
+func (fake *FakeProvider) LoadFileVersionArgsForCall(i int) (string, string, string) {
+	fake.loadFileVersionMutex.RLock()
+	defer fake.loadFileVersionMutex.RUnlock()
+	argsForCall := fake.loadFileVersionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeProvider) LoadFileVersionReturns(result1 []byte, result2 error) {
+	fake.loadFileVersionMutex.Lock()
+	defer fake.loadFileVersionMutex.Unlock()
+	fake.LoadFileVersionStub = nil
+	fake.loadFileVersionReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) LoadFileVersionReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.loadFileVersionMutex.Lock()
+	defer fake.loadFileVersionMutex.Unlock()
+	fake.LoadFileVersionStub = nil
+	if fake.loadFileVersionReturnsOnCall == nil {
+		fake.loadFileVersionReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.loadFileVersionReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeProvider) Region() string {
 	fake.regionMutex.Lock()
 	ret, specificReturn := fake.regionReturnsOnCall[len(fake.regionArgsForCall)]
@@ -1441,6 +2203,8 @@ func (fake *FakeProvider) Invocations() map[string][][]interface{} {
 	defer fake.attrMutex.RUnlock()
 	fake.bucketExistsMutex.RLock()
 	defer fake.bucketExistsMutex.RUnlock()
+	fake.bucketRegionMutex.RLock()
+	defer fake.bucketRegionMutex.RUnlock()
 	fake.checkForWhitelistedIPMutex.RLock()
 	defer fake.checkForWhitelistedIPMutex.RUnlock()
 	fake.chooseMutex.RLock()
@@ -1449,8 +2213,16 @@ func (fake *FakeProvider) Invocations() map[string][][]interface{} {
 	defer fake.createBucketMutex.RUnlock()
 	fake.createDatabasesMutex.RLock()
 	defer fake.createDatabasesMutex.RUnlock()
+	fake.createFileMutex.RLock()
+	defer fake.createFileMutex.RUnlock()
 	fake.dBTypeMutex.RLock()
 	defer fake.dBTypeMutex.RUnlock()
+	fake.deleteFileMutex.RLock()
+	defer fake.deleteFileMutex.RUnlock()
+	fake.deleteFileVersionMutex.RLock()
+	defer fake.deleteFileVersionMutex.RUnlock()
+	fake.deleteTerraformLockMutex.RLock()
+	defer fake.deleteTerraformLockMutex.RUnlock()
 	fake.deleteVMsInDeploymentMutex.RLock()
 	defer fake.deleteVMsInDeploymentMutex.RUnlock()
 	fake.deleteVMsInVPCMutex.RLock()
@@ -1461,14 +2233,24 @@ func (fake *FakeProvider) Invocations() map[string][][]interface{} {
 	defer fake.deleteVolumesMutex.RUnlock()
 	fake.ensureFileExistsMutex.RLock()
 	defer fake.ensureFileExistsMutex.RUnlock()
+	fake.ensureTerraformLockMutex.RLock()
+	defer fake.ensureTerraformLockMutex.RUnlock()
+	fake.fileLastModifiedMutex.RLock()
+	defer fake.fileLastModifiedMutex.RUnlock()
 	fake.findLongestMatchingHostedZoneMutex.RLock()
 	defer fake.findLongestMatchingHostedZoneMutex.RUnlock()
 	fake.hasFileMutex.RLock()
 	defer fake.hasFileMutex.RUnlock()
 	fake.iAASMutex.RLock()
 	defer fake.iAASMutex.RUnlock()
+	fake.listBucketsMutex.RLock()
+	defer fake.listBucketsMutex.RUnlock()
+	fake.listFileVersionsMutex.RLock()
+	defer fake.listFileVersionsMutex.RUnlock()
 	fake.loadFileMutex.RLock()
 	defer fake.loadFileMutex.RUnlock()
+	fake.loadFileVersionMutex.RLock()
+	defer fake.loadFileVersionMutex.RUnlock()
 	fake.regionMutex.RLock()
 	defer fake.regionMutex.RUnlock()
 	fake.writeFileMutex.RLock()
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/aws/aws-sdk-go/aws"
 	"io/ioutil"
+	"net/http"
 
 	"time"
 
@@ -37,11 +38,16 @@ func (client *AWSProvider) DeleteVersionedBucket(name string) error {
 
 	s3Client := s3.New(client.sess)
 
-	// Delete all objects
-	objects := []*s3.ObjectVersion{}
+	// Delete all objects, and the delete markers left by deleting files such as the deployment lock
+	objects := []*s3.ObjectIdentifier{}
 	err := s3Client.ListObjectVersionsPages(&s3.ListObjectVersionsInput{Bucket: &name},
 		func(output *s3.ListObjectVersionsOutput, _ bool) bool {
-			objects = append(objects, output.Versions...)
+			for _, version := range output.Versions {
+				objects = append(objects, &s3.ObjectIdentifier{Key: version.Key, VersionId: version.VersionId})
+			}
+			for _, marker := range output.DeleteMarkers {
+				objects = append(objects, &s3.ObjectIdentifier{Key: marker.Key, VersionId: marker.VersionId})
+			}
 
 			return true
 		})
@@ -56,7 +62,7 @@ func (client *AWSProvider) DeleteVersionedBucket(name string) error {
 			VersionId: object.VersionId,
 		})
 		if err != nil {
-			return nil
+			return fmt.Errorf("error deleting [%v] from bucket [%v]: [%v]", aws.StringValue(object.Key), name, err)
 		}
 	}
 
@@ -135,6 +141,35 @@ func (client *AWSProvider) WriteFile(bucket, path string, contents []byte) error
 	return err
 }
 
+// CreateFile writes the specified S3 object unless it already exists, returning false if it did
+func (client *AWSProvider) CreateFile(bucket, path string, contents []byte) (bool, error) {
+	return CreateS3Object(s3.New(client.sess), bucket, path, contents)
+}
+
+// CreateS3Object writes an S3 object with If-None-Match: *, so that of several concurrent writers only one
+// creates it. It returns false if the object already exists.
+func CreateS3Object(s3Client *s3.S3, bucket, path string, contents []byte) (bool, error) {
+	req, _ := s3Client.PutObjectRequest(&s3.PutObjectInput{
+		Bucket: &bucket,
+		Key:    &path,
+		Body:   bytes.NewReader(contents),
+	})
+	// The pinned SDK predates conditional writes, so the header is set on the request directly
+	req.HTTPRequest.Header.Set("If-None-Match", "*")
+
+	err := req.Send()
+	if aerr, ok := err.(awserr.RequestFailure); ok {
+		// 409 is returned when a concurrent conditional write of the same key is in progress
+		if aerr.StatusCode() == http.StatusPreconditionFailed || aerr.StatusCode() == http.StatusConflict {
+			return false, nil
+		}
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // HasFile returns true if the specified S3 object exists
 func (client *AWSProvider) HasFile(bucket, path string) (bool, error) {
 	s3Client := s3.New(client.sess)
@@ -214,3 +249,100 @@ func (client *AWSProvider) DeleteFile(bucket, path string) error {
 
 	return err
 }
+
+// ListBuckets returns the names of all S3 buckets visible to the account
+func (client *AWSProvider) ListBuckets() ([]string, error) {
+
+	s3Client := s3.New(client.sess)
+
+	output, err := s3Client.ListBuckets(&s3.ListBucketsInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, bucket := range output.Buckets {
+		names = append(names, aws.StringValue(bucket.Name))
+	}
+
+	return names, nil
+}
+
+// BucketRegion returns the region the named S3 bucket was created in
+func (client *AWSProvider) BucketRegion(name string) (string, error) {
+
+	s3Client := s3.New(client.sess)
+
+	output, err := s3Client.GetBucketLocationWithContext(aws.BackgroundContext(),
+		&s3.GetBucketLocationInput{Bucket: &name},
+		s3.WithNormalizeBucketLocation,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return aws.StringValue(output.LocationConstraint), nil
+}
+
+// FileLastModified returns the time the specified S3 object was last written
+func (client *AWSProvider) FileLastModified(bucket, path string) (time.Time, error) {
+
+	s3Client := s3.New(client.sess)
+
+	output, err := s3Client.HeadObject(&s3.HeadObjectInput{Bucket: &bucket, Key: &path})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return aws.TimeValue(output.LastModified), nil
+}
+
+// ListFileVersions returns every stored version of the specified S3 object, newest first
+func (client *AWSProvider) ListFileVersions(bucket, path string) ([]FileVersion, error) {
+
+	s3Client := s3.New(client.sess)
+
+	versions := []FileVersion{}
+	err := s3Client.ListObjectVersionsPages(&s3.ListObjectVersionsInput{Bucket: &bucket, Prefix: &path},
+		func(output *s3.ListObjectVersionsOutput, _ bool) bool {
+			for _, version := range output.Versions {
+				if aws.StringValue(version.Key) == path {
+					versions = append(versions, FileVersion{
+						ID:           aws.StringValue(version.VersionId),
+						LastModified: aws.TimeValue(version.LastModified),
+						Current:      aws.BoolValue(version.IsLatest),
+					})
+				}
+			}
+			return true
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	SortFileVersions(versions)
+	return versions, nil
+}
+
+// LoadFileVersion loads a specific version of a file from S3
+func (client *AWSProvider) LoadFileVersion(bucket, path, versionID string) ([]byte, error) {
+
+	s3Client := s3.New(client.sess)
+
+	output, err := s3Client.GetObject(&s3.GetObjectInput{Bucket: &bucket, Key: &path, VersionId: &versionID})
+	if err != nil {
+		return nil, err
+	}
+	defer output.Body.Close()
+
+	return ioutil.ReadAll(output.Body)
+}
+
+// DeleteFileVersion permanently deletes a specific version of a file from S3
+func (client *AWSProvider) DeleteFileVersion(bucket, path, versionID string) error {
+
+	s3Client := s3.New(client.sess)
+
+	_, err := s3Client.DeleteObject(&s3.DeleteObjectInput{Bucket: &bucket, Key: &path, VersionId: &versionID})
+	return err
+}
@@ -13,6 +13,7 @@ The following function must be included in a *_test.go file.
 package fakeexec
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -98,6 +99,15 @@ func (e *E) Cmd() func(command string, args ...string) *exec.Cmd {
 	}
 }
 
+// CmdContext returns a function with a signiture that matches (os/exec).CommandContext
+func (e *E) CmdContext() func(ctx context.Context, command string, args ...string) *exec.Cmd {
+	cmd := e.Cmd()
+	return func(ctx context.Context, command string, args ...string) *exec.Cmd {
+		e.t.Helper()
+		return cmd(command, args...)
+	}
+}
+
 // Finish checks all expectations have been met
 func (e *E) Finish() {
 	e.t.Helper()
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/EngineerBetter/control-tower/commands"
 	"github.com/fatih/color"
@@ -19,7 +22,7 @@ func main() {
 	app.Name = "Control-Tower"
 	app.Usage = "A CLI tool to deploy Concourse CI"
 	app.Version = ControlTowerVersion
-	app.Commands = commands.Commands
+	app.Commands = commands.WithEvents(commands.Commands)
 	app.Flags = commands.GlobalFlags
 	cli.AppHelpTemplate = fmt.Sprintf(`%s
 
@@ -29,8 +32,33 @@ Built by %s %s
 
 `, cli.AppHelpTemplate, blue("EngineerBetter"), blue("http://engineerbetter.com"))
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cancelOnInterrupt(cancel)
+	commands.SetContext(ctx)
+
 	if err := app.Run(os.Args); err != nil {
 		fmt.Fprintln(os.Stderr, err)
+		if ctx.Err() != nil {
+			os.Exit(130)
+		}
 		os.Exit(1)
 	}
 }
+
+// cancelOnInterrupt cancels the running command on the first SIGINT or SIGTERM, so that it can wait for
+// terraform and bosh to exit and save their state. A second signal exits immediately
+func cancelOnInterrupt(cancel context.CancelFunc) {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-signals
+		fmt.Fprintln(os.Stderr, "\nInterrupted, waiting for running commands to stop and save their state. Press Ctrl-C again to exit immediately")
+		cancel()
+
+		<-signals
+		fmt.Fprintln(os.Stderr, "\nExiting without waiting, state files of running commands may not have been saved")
+		os.Exit(130)
+	}()
+}
@@ -1,9 +1,10 @@
 terraform {
-	backend "s3" {
+	{{ if .Backend }}{{ .Backend }}{{ else }}backend "s3" {
 		bucket = "{{ .ConfigBucket }}"
 		key    = "{{ .TFStatePath }}"
 		region = "{{ .Region }}"
-	}
+		dynamodb_table = "{{ .LockTable }}"
+	}{{ end }}
 }
 
 data "aws_availability_zones" "available" {
@@ -0,0 +1,371 @@
+variable "deployment" {
+  type = "string"
+  default = "{{ .Deployment }}"
+}
+
+variable "region" {
+  type = "string"
+  default = "{{ .Region }}"
+}
+
+variable "namespace" {
+  type = "string"
+  default = "{{ .Namespace }}"
+}
+
+variable "project" {
+  type = "string"
+  default = "{{ .Project }}"
+}
+
+variable "source_access_ip" {
+  type = "string"
+  default = "{{ .ExternalIP }}"
+}
+
+variable "public_cidr" {
+  type = "string"
+  default = "{{ .PublicCIDR }}"
+}
+
+variable "private_cidr" {
+  type = "string"
+  default = "{{ .PrivateCIDR }}"
+}
+
+variable "db_sku" {
+  type = "string"
+  default = "{{ .DBSKU }}"
+}
+
+variable "db_username" {
+  type = "string"
+  default = "{{ .DBUsername }}"
+}
+
+variable "db_password" {
+  type = "string"
+  default = "{{ .DBPassword }}"
+}
+
+variable "db_name" {
+  type = "string"
+  default = "{{ .DBName }}"
+}
+
+{{if .DNSZoneName }}
+variable "dns_zone_name" {
+  type = "string"
+  default = "{{ .DNSZoneName }}"
+}
+
+variable "dns_zone_resource_group" {
+  type = "string"
+  default = "{{ .DNSZoneResourceGroup }}"
+}
+
+variable "dns_record_set_prefix" {
+  type = "string"
+  default = "{{ .DNSRecordSetPrefix }}"
+}
+{{end}}
+
+provider "azurerm" {
+  subscription_id = "{{ .SubscriptionID }}"
+  tenant_id       = "{{ .TenantID }}"
+  client_id       = "{{ .ClientID }}"
+  client_secret   = "{{ .ClientSecret }}"
+  version         = "~> 1.44.0"
+}
+
+terraform {
+  {{ if .Backend }}{{ .Backend }}{{ else }}backend "azurerm" {
+    subscription_id      = "{{ .SubscriptionID }}"
+    tenant_id            = "{{ .TenantID }}"
+    client_id            = "{{ .ClientID }}"
+    client_secret        = "{{ .ClientSecret }}"
+    resource_group_name  = "{{ .StorageResourceGroup }}"
+    storage_account_name = "{{ .StorageAccount }}"
+    container_name       = "{{ .ConfigBucket }}"
+    key                  = "terraform.tfstate"
+  }{{ end }}
+}
+
+locals {
+  tags = {
+    control-tower-project   = "${var.project}"
+    control-tower-component = "concourse"
+  }
+}
+
+resource "azurerm_resource_group" "default" {
+  name     = "${var.deployment}"
+  location = "${var.region}"
+  tags     = "${local.tags}"
+}
+
+// The director and the VMs it creates are kept in their own resource group so that
+// destroy can delete them all before the network is destroyed
+resource "azurerm_resource_group" "vms" {
+  name     = "${var.deployment}-vms"
+  location = "${var.region}"
+  tags     = "${local.tags}"
+}
+
+{{if .DNSZoneName }}
+resource "azurerm_dns_a_record" "dns" {
+  name                = "${var.dns_record_set_prefix == "" ? "@" : var.dns_record_set_prefix}"
+  zone_name           = "${var.dns_zone_name}"
+  resource_group_name = "${var.dns_zone_resource_group}"
+  ttl                 = 60
+  records             = ["${azurerm_public_ip.atc.ip_address}"]
+}
+{{end}}
+
+resource "azurerm_virtual_network" "default" {
+  name                = "${var.deployment}"
+  resource_group_name = "${azurerm_resource_group.default.name}"
+  location            = "${var.region}"
+  address_space       = ["${var.public_cidr}", "${var.private_cidr}"]
+  tags                = "${local.tags}"
+}
+
+resource "azurerm_subnet" "public" {
+  name                 = "${var.deployment}-${var.namespace}-public"
+  resource_group_name  = "${azurerm_resource_group.default.name}"
+  virtual_network_name = "${azurerm_virtual_network.default.name}"
+  address_prefix       = "${var.public_cidr}"
+}
+
+resource "azurerm_subnet" "private" {
+  name                 = "${var.deployment}-${var.namespace}-private"
+  resource_group_name  = "${azurerm_resource_group.default.name}"
+  virtual_network_name = "${azurerm_virtual_network.default.name}"
+  address_prefix       = "${var.private_cidr}"
+}
+
+resource "azurerm_public_ip" "nat" {
+  name                = "${var.deployment}-nat-ip"
+  resource_group_name = "${azurerm_resource_group.default.name}"
+  location            = "${var.region}"
+  allocation_method   = "Static"
+  sku                 = "Standard"
+  tags                = "${local.tags}"
+}
+
+resource "azurerm_nat_gateway" "nat" {
+  name                  = "${var.deployment}-nat"
+  resource_group_name   = "${azurerm_resource_group.default.name}"
+  location              = "${var.region}"
+  sku_name              = "Standard"
+  public_ip_address_ids = ["${azurerm_public_ip.nat.id}"]
+  tags                  = "${local.tags}"
+}
+
+resource "azurerm_subnet_nat_gateway_association" "private" {
+  subnet_id      = "${azurerm_subnet.private.id}"
+  nat_gateway_id = "${azurerm_nat_gateway.nat.id}"
+}
+
+resource "azurerm_public_ip" "director" {
+  name                = "${var.deployment}-director-ip"
+  resource_group_name = "${azurerm_resource_group.default.name}"
+  location            = "${var.region}"
+  allocation_method   = "Static"
+  sku                 = "Standard"
+  tags                = "${local.tags}"
+}
+
+resource "azurerm_public_ip" "atc" {
+  name                = "${var.deployment}-atc-ip"
+  resource_group_name = "${azurerm_resource_group.default.name}"
+  location            = "${var.region}"
+  allocation_method   = "Static"
+  sku                 = "Standard"
+  tags                = "${local.tags}"
+}
+
+resource "azurerm_network_security_group" "director" {
+  name                = "${var.deployment}-director"
+  resource_group_name = "${azurerm_resource_group.default.name}"
+  location            = "${var.region}"
+  tags                = "${local.tags}"
+
+  security_rule {
+    name                       = "director"
+    description                = "External access to BOSH director"
+    priority                   = 100
+    direction                  = "Inbound"
+    access                     = "Allow"
+    protocol                   = "Tcp"
+    source_port_range          = "*"
+    destination_port_ranges    = ["22", "6868", "25555"]
+    source_address_prefixes    = ["${var.source_access_ip}/32", "${azurerm_public_ip.nat.ip_address}/32"]
+    destination_address_prefix = "*"
+  }
+}
+
+resource "azurerm_network_security_group" "atc" {
+  name                = "${var.deployment}-atc"
+  resource_group_name = "${azurerm_resource_group.default.name}"
+  location            = "${var.region}"
+  tags                = "${local.tags}"
+
+  security_rule {
+    name                       = "atc-http"
+    description                = "External access to concourse atc"
+    priority                   = 100
+    direction                  = "Inbound"
+    access                     = "Allow"
+    protocol                   = "Tcp"
+    source_port_range          = "*"
+    destination_port_range     = "80"
+    source_address_prefixes    = [{{ .AllowIPs }}]
+    destination_address_prefix = "*"
+  }
+
+  security_rule {
+    name                       = "atc-https"
+    description                = "External access to concourse atc, grafana and credhub"
+    priority                   = 110
+    direction                  = "Inbound"
+    access                     = "Allow"
+    protocol                   = "Tcp"
+    source_port_range          = "*"
+    destination_port_ranges    = ["443", "8443", "3000", "8844"]
+    source_address_prefixes    = ["${azurerm_public_ip.nat.ip_address}/32", "${azurerm_public_ip.atc.ip_address}/32", {{ .AllowIPs }}]
+    destination_address_prefix = "*"
+  }
+}
+
+// Traffic within the virtual network is allowed by the default rules
+resource "azurerm_network_security_group" "vms" {
+  name                = "${var.deployment}-vms"
+  resource_group_name = "${azurerm_resource_group.default.name}"
+  location            = "${var.region}"
+  tags                = "${local.tags}"
+}
+
+resource "azurerm_postgresql_server" "director" {
+  name                = "${var.db_name}"
+  resource_group_name = "${azurerm_resource_group.default.name}"
+  location            = "${var.region}"
+  sku_name            = "${var.db_sku}"
+  version             = "9.6"
+  ssl_enforcement     = "Enabled"
+
+  administrator_login          = "${var.db_username}"
+  administrator_login_password = "${var.db_password}"
+
+  storage_profile {
+    storage_mb            = 51200
+    backup_retention_days = 7
+    geo_redundant_backup  = "Disabled"
+  }
+
+  tags = "${local.tags}"
+}
+
+resource "azurerm_postgresql_firewall_rule" "atc" {
+  name                = "atc"
+  resource_group_name = "${azurerm_resource_group.default.name}"
+  server_name         = "${azurerm_postgresql_server.director.name}"
+  start_ip_address    = "${azurerm_public_ip.atc.ip_address}"
+  end_ip_address      = "${azurerm_public_ip.atc.ip_address}"
+}
+
+resource "azurerm_postgresql_firewall_rule" "director" {
+  name                = "director"
+  resource_group_name = "${azurerm_resource_group.default.name}"
+  server_name         = "${azurerm_postgresql_server.director.name}"
+  start_ip_address    = "${azurerm_public_ip.director.ip_address}"
+  end_ip_address      = "${azurerm_public_ip.director.ip_address}"
+}
+
+resource "azurerm_postgresql_firewall_rule" "nat" {
+  name                = "nat"
+  resource_group_name = "${azurerm_resource_group.default.name}"
+  server_name         = "${azurerm_postgresql_server.director.name}"
+  start_ip_address    = "${azurerm_public_ip.nat.ip_address}"
+  end_ip_address      = "${azurerm_public_ip.nat.ip_address}"
+}
+
+resource "azurerm_postgresql_database" "concourse_atc" {
+  name                = "concourse_atc"
+  resource_group_name = "${azurerm_resource_group.default.name}"
+  server_name         = "${azurerm_postgresql_server.director.name}"
+  charset             = "UTF8"
+  collation           = "English_United States.1252"
+}
+
+resource "azurerm_postgresql_database" "uaa" {
+  name                = "uaa"
+  resource_group_name = "${azurerm_resource_group.default.name}"
+  server_name         = "${azurerm_postgresql_server.director.name}"
+  charset             = "UTF8"
+  collation           = "English_United States.1252"
+}
+
+resource "azurerm_postgresql_database" "credhub" {
+  name                = "credhub"
+  resource_group_name = "${azurerm_resource_group.default.name}"
+  server_name         = "${azurerm_postgresql_server.director.name}"
+  charset             = "UTF8"
+  collation           = "English_United States.1252"
+}
+
+output "network" {
+  value = "${azurerm_virtual_network.default.name}"
+}
+
+output "network_resource_group" {
+  value = "${azurerm_resource_group.default.name}"
+}
+
+output "vms_resource_group" {
+  value = "${azurerm_resource_group.vms.name}"
+}
+
+output "public_subnetwork_name" {
+  value = "${azurerm_subnet.public.name}"
+}
+
+output "private_subnetwork_name" {
+  value = "${azurerm_subnet.private.name}"
+}
+
+output "director_public_ip" {
+  value = "${azurerm_public_ip.director.ip_address}"
+}
+
+output "atc_public_ip" {
+  value = "${azurerm_public_ip.atc.ip_address}"
+}
+
+output "nat_gateway_ip" {
+  value = "${azurerm_public_ip.nat.ip_address}"
+}
+
+output "director_security_group_id" {
+  value = "${azurerm_network_security_group.director.id}"
+}
+
+output "director_security_group_name" {
+  value = "${azurerm_network_security_group.director.name}"
+}
+
+output "atc_security_group_name" {
+  value = "${azurerm_network_security_group.atc.name}"
+}
+
+output "vms_security_group_name" {
+  value = "${azurerm_network_security_group.vms.name}"
+}
+
+output "bosh_db_address" {
+  value = "${azurerm_postgresql_server.director.fqdn}"
+}
+
+output "db_name" {
+  value = "${azurerm_postgresql_server.director.name}"
+}
@@ -88,10 +88,10 @@ provider "google" {
 
 
 terraform {
-	backend "gcs" {
+	{{ if .Backend }}{{ .Backend }}{{ else }}backend "gcs" {
 		bucket = "{{ .ConfigBucket }}"
 		region = "{{ .Region }}"
-	}
+	}{{ end }}
 }
 
 {{if .DNSManagedZoneName }}
@@ -29,12 +29,23 @@ var (
 	GCPDirectorCustomOps = file.MustAssetString("assets/gcp/custom-ops.yml")
 	//GCPJumpboxUserOps statically defines gcp jumpbox-user.yml
 	GCPJumpboxUserOps = file.MustAssetString("assets/gcp/jumpbox-user.yml")
+	// AzureDirectorCloudConfig statically defines azure cloud-config.yml
+	AzureDirectorCloudConfig = file.MustAssetString("assets/azure/cloud-config.yml")
+	// AzureCPIOps statically defines azure cpi.yml contents
+	AzureCPIOps = file.MustAssetString("assets/azure/cpi.yml")
+	// AzureExternalIPOps statically defines external-ip.yml contents
+	AzureExternalIPOps = file.MustAssetString("assets/azure/external-ip.yml")
+	// AzureDirectorCustomOps statically defines custom-ops.yml contents
+	AzureDirectorCustomOps = file.MustAssetString("assets/azure/custom-ops.yml")
 	// AWSTerraformConfig holds the terraform conf for AWS
 	AWSTerraformConfig = file.MustAssetString("assets/aws/infrastructure.tf")
 
 	// GCPTerraformConfig holds the terraform conf for GCP
 	GCPTerraformConfig = file.MustAssetString("assets/gcp/infrastructure.tf")
 
+	// AzureTerraformConfig holds the terraform conf for Azure
+	AzureTerraformConfig = file.MustAssetString("assets/azure/infrastructure.tf")
+
 	// AWSReleaseVersions carries all versions of releases
 	AWSReleaseVersions = file.MustAssetString("../../control-tower-ops/ops/versions-aws.json")
 
@@ -54,3 +65,18 @@ var (
 
 	GCPVersionFile = file.MustAsset("../../control-tower-ops/createenv-dependencies-and-cli-versions-gcp.json")
 )
+
+// The Azure files from control-tower-ops are loaded on first use rather than at init, so that
+// a control-tower-ops without them only fails Azure deployments
+var (
+	// AzureReleaseVersions returns all versions of releases for Azure
+	AzureReleaseVersions = func() (string, error) {
+		versions, err := file.Asset("../../control-tower-ops/ops/versions-azure.json")
+		return string(versions), err
+	}
+
+	// AzureVersionFile returns the createenv dependencies and CLI versions for Azure
+	AzureVersionFile = func() ([]byte, error) {
+		return file.Asset("../../control-tower-ops/createenv-dependencies-and-cli-versions-azure.json")
+	}
+)
@@ -0,0 +1,265 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/EngineerBetter/control-tower/iaas"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+// GCSStore keeps buckets in Google Cloud Storage
+type GCSStore struct {
+	ctx             context.Context
+	region          string
+	project         string
+	credentialsPath string
+	storage         *storage.Client
+}
+
+// NewGCS returns a GCSStore which creates buckets in region.
+//
+// Credentials are read from the file named by STATE_BACKEND_GOOGLE_APPLICATION_CREDENTIALS
+// when set, so that state can live in a different project to the deployment, and from
+// GOOGLE_APPLICATION_CREDENTIALS otherwise. Buckets are created in the project the credentials belong to.
+func NewGCS(region string) (*GCSStore, error) {
+	path := os.Getenv("STATE_BACKEND_GOOGLE_APPLICATION_CREDENTIALS")
+	if path == "" {
+		path = os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	}
+	if path == "" {
+		return nil, fmt.Errorf("GOOGLE_APPLICATION_CREDENTIALS is not set")
+	}
+
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading credentials file [%v]: [%v]", path, err)
+	}
+
+	var creds struct {
+		ProjectID string `json:"project_id"`
+	}
+	if err = json.Unmarshal(contents, &creds); err != nil || creds.ProjectID == "" {
+		return nil, fmt.Errorf("project_id not found in %v", path)
+	}
+
+	ctx := context.Background()
+	client, err := storage.NewClient(ctx, option.WithCredentialsFile(path))
+	if err != nil {
+		return nil, fmt.Errorf("error creating GCS client: [%v]", err)
+	}
+
+	return &GCSStore{
+		ctx:             ctx,
+		region:          region,
+		project:         creds.ProjectID,
+		credentialsPath: path,
+		storage:         client,
+	}, nil
+}
+
+// BucketExists checks if the named bucket exists
+func (s *GCSStore) BucketExists(name string) (bool, error) {
+	_, err := s.storage.Bucket(name).Attrs(s.ctx)
+	if err == storage.ErrBucketNotExist {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateBucket creates the named bucket with versioning enabled
+func (s *GCSStore) CreateBucket(name string) error {
+	attrs := &storage.BucketAttrs{
+		Location:          s.region,
+		VersioningEnabled: true,
+	}
+	if err := s.storage.Bucket(name).Create(s.ctx, s.project, attrs); err != nil {
+		return fmt.Errorf("error creating bucket [%v]: [%v]", name, err)
+	}
+	return nil
+}
+
+// DeleteVersionedBucket deletes every generation of every object in the bucket, then the bucket
+func (s *GCSStore) DeleteVersionedBucket(name string) error {
+	bucket := s.storage.Bucket(name)
+	it := bucket.Objects(s.ctx, &storage.Query{Versions: true})
+
+	for {
+		objAttrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error iterating over contents of bucket [%v]: [%v]", name, err)
+		}
+
+		if err = bucket.Object(objAttrs.Name).Generation(objAttrs.Generation).Delete(s.ctx); err != nil {
+			return fmt.Errorf("error deleting [%v] from bucket [%v]: [%v]", objAttrs.Name, name, err)
+		}
+	}
+
+	if err := bucket.Delete(s.ctx); err != nil {
+		return fmt.Errorf("error deleting bucket [%v]: [%v]", name, err)
+	}
+	return nil
+}
+
+// FileLastModified returns the time the object was last written
+func (s *GCSStore) FileLastModified(bucket, path string) (time.Time, error) {
+	attrs, err := s.storage.Bucket(bucket).Object(path).Attrs(s.ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return attrs.Updated, nil
+}
+
+// HasFile returns true if the object exists
+func (s *GCSStore) HasFile(bucket, path string) (bool, error) {
+	_, err := s.storage.Bucket(bucket).Object(path).Attrs(s.ctx)
+	if err == storage.ErrObjectNotExist {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ListBuckets returns the names of all buckets in the project
+func (s *GCSStore) ListBuckets() ([]string, error) {
+	names := []string{}
+	it := s.storage.Buckets(s.ctx, s.project)
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, attrs.Name)
+	}
+	return names, nil
+}
+
+// LoadFile reads an object
+func (s *GCSStore) LoadFile(bucket, path string) ([]byte, error) {
+	rc, err := s.storage.Bucket(bucket).Object(path).NewReader(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return ioutil.ReadAll(rc)
+}
+
+// ListFileVersions returns every stored generation of an object, newest first
+func (s *GCSStore) ListFileVersions(bucket, path string) ([]iaas.FileVersion, error) {
+	versions := []iaas.FileVersion{}
+	it := s.storage.Bucket(bucket).Objects(s.ctx, &storage.Query{Prefix: path, Versions: true})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if attrs.Name != path {
+			continue
+		}
+
+		versions = append(versions, iaas.FileVersion{
+			ID:           strconv.FormatInt(attrs.Generation, 10),
+			LastModified: attrs.Updated,
+			Current:      attrs.Deleted.IsZero(),
+		})
+	}
+
+	iaas.SortFileVersions(versions)
+	return versions, nil
+}
+
+// LoadFileVersion reads a specific generation of an object
+func (s *GCSStore) LoadFileVersion(bucket, path, versionID string) ([]byte, error) {
+	generation, err := strconv.ParseInt(versionID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid generation [%v] of %v: [%v]", versionID, path, err)
+	}
+
+	rc, err := s.storage.Bucket(bucket).Object(path).Generation(generation).NewReader(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return ioutil.ReadAll(rc)
+}
+
+// DeleteFileVersion permanently deletes a specific generation of an object
+func (s *GCSStore) DeleteFileVersion(bucket, path, versionID string) error {
+	generation, err := strconv.ParseInt(versionID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid generation [%v] of %v: [%v]", versionID, path, err)
+	}
+
+	return s.storage.Bucket(bucket).Object(path).Generation(generation).Delete(s.ctx)
+}
+
+// WriteFile writes an object
+func (s *GCSStore) WriteFile(bucket, path string, contents []byte) error {
+	wc := s.storage.Bucket(bucket).Object(path).NewWriter(s.ctx)
+
+	if _, err := wc.Write(contents); err != nil {
+		return fmt.Errorf("failed to write %s to bucket: [%s]", path, err)
+	}
+
+	if err := wc.Close(); err != nil {
+		return fmt.Errorf("failed to close writer for %s: [%s]", path, err)
+	}
+
+	return nil
+}
+
+// CreateFile writes an object unless it already exists, returning false if it did
+func (s *GCSStore) CreateFile(bucket, path string, contents []byte) (bool, error) {
+	return iaas.CreateGCSObject(s.ctx, s.storage.Bucket(bucket), path, contents)
+}
+
+// DeleteFile deletes an object
+func (s *GCSStore) DeleteFile(bucket, path string) error {
+	err := s.storage.Bucket(bucket).Object(path).Delete(s.ctx)
+	if err == storage.ErrObjectNotExist {
+		return nil
+	}
+	return err
+}
+
+// TerraformBackend returns a gcs backend using the same credentials as the store
+func (s *GCSStore) TerraformBackend(bucket, key string) string {
+	return fmt.Sprintf(`backend "gcs" {
+		bucket      = "%s"
+		prefix      = "%s"
+		credentials = "%s"
+	}`, bucket, key, s.credentialsPath)
+}
+
+// EnsureTerraformLock does nothing as terraform's gcs backend locks state natively
+func (s *GCSStore) EnsureTerraformLock(bucket string) error {
+	return nil
+}
+
+// DeleteTerraformLock does nothing as EnsureTerraformLock creates nothing
+func (s *GCSStore) DeleteTerraformLock(bucket string) error {
+	return nil
+}
@@ -0,0 +1,259 @@
+package store
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/EngineerBetter/control-tower/iaas"
+)
+
+// LocalStore keeps each bucket as a directory beneath Dir. Every version of every file
+// written is also kept beneath the .versions directory of its bucket.
+type LocalStore struct {
+	Dir string
+}
+
+// NewLocal returns a LocalStore rooted at dir
+func NewLocal(dir string) *LocalStore {
+	return &LocalStore{Dir: dir}
+}
+
+// BucketExists returns true if the bucket directory exists
+func (s *LocalStore) BucketExists(name string) (bool, error) {
+	return exists(s.path(name))
+}
+
+// CreateBucket creates the bucket directory
+func (s *LocalStore) CreateBucket(name string) error {
+	if err := os.MkdirAll(s.path(name), 0700); err != nil {
+		return fmt.Errorf("error creating bucket directory [%v]: [%v]", name, err)
+	}
+	return nil
+}
+
+// DeleteVersionedBucket deletes the bucket directory and everything in it
+func (s *LocalStore) DeleteVersionedBucket(name string) error {
+	return os.RemoveAll(s.path(name))
+}
+
+// FileLastModified returns the modification time of the file
+func (s *LocalStore) FileLastModified(bucket, path string) (time.Time, error) {
+	info, err := os.Stat(s.path(bucket, path))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
+
+// HasFile returns true if the file exists in the bucket
+func (s *LocalStore) HasFile(bucket, path string) (bool, error) {
+	return exists(s.path(bucket, path))
+}
+
+// ListBuckets returns the names of every bucket directory
+func (s *LocalStore) ListBuckets() ([]string, error) {
+	infos, err := ioutil.ReadDir(s.Dir)
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, info := range infos {
+		if info.IsDir() {
+			names = append(names, info.Name())
+		}
+	}
+	return names, nil
+}
+
+// LoadFile reads a file from the bucket
+func (s *LocalStore) LoadFile(bucket, path string) ([]byte, error) {
+	return ioutil.ReadFile(s.path(bucket, path))
+}
+
+// WriteFile atomically writes a file to the bucket, which must already exist
+func (s *LocalStore) WriteFile(bucket, path string, contents []byte) error {
+	bucketExists, err := s.BucketExists(bucket)
+	if err != nil {
+		return err
+	}
+	if !bucketExists {
+		return fmt.Errorf("bucket [%v] does not exist in [%v]", bucket, s.Dir)
+	}
+
+	filePath := s.path(bucket, path)
+	if err = os.MkdirAll(filepath.Dir(filePath), 0700); err != nil {
+		return err
+	}
+
+	// Write then rename so that readers never see a partially written file
+	tmp, err := ioutil.TempFile(filepath.Dir(filePath), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(contents); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), filePath); err != nil {
+		return err
+	}
+
+	return s.writeVersion(bucket, path, contents)
+}
+
+// ListFileVersions returns every version of the file that has been written, newest first
+func (s *LocalStore) ListFileVersions(bucket, path string) ([]iaas.FileVersion, error) {
+	infos, err := ioutil.ReadDir(s.versionsPath(bucket, path))
+	if os.IsNotExist(err) {
+		return []iaas.FileVersion{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	versions := []iaas.FileVersion{}
+	for _, info := range infos {
+		lastModified, err := time.Parse(localVersionIDFormat, info.Name())
+		if err != nil {
+			continue
+		}
+		versions = append(versions, iaas.FileVersion{ID: info.Name(), LastModified: lastModified})
+	}
+
+	iaas.SortFileVersions(versions)
+
+	// Every write adds a version, so the newest is the one in the bucket unless the file has since been deleted
+	current, err := exists(s.path(bucket, path))
+	if err != nil {
+		return nil, err
+	}
+	if current && len(versions) > 0 {
+		versions[0].Current = true
+	}
+	return versions, nil
+}
+
+// LoadFileVersion reads a version of a file from the bucket
+func (s *LocalStore) LoadFileVersion(bucket, path, versionID string) ([]byte, error) {
+	if _, err := time.Parse(localVersionIDFormat, versionID); err != nil {
+		return nil, fmt.Errorf("invalid version [%v] of %v", versionID, path)
+	}
+	return ioutil.ReadFile(filepath.Join(s.versionsPath(bucket, path), versionID))
+}
+
+// DeleteFileVersion permanently deletes a version of a file from the bucket
+func (s *LocalStore) DeleteFileVersion(bucket, path, versionID string) error {
+	if _, err := time.Parse(localVersionIDFormat, versionID); err != nil {
+		return fmt.Errorf("invalid version [%v] of %v", versionID, path)
+	}
+	return os.Remove(filepath.Join(s.versionsPath(bucket, path), versionID))
+}
+
+// TerraformBackend keeps terraform state alongside the other files in the bucket
+func (s *LocalStore) TerraformBackend(bucket, key string) string {
+	return fmt.Sprintf(`backend "local" {
+		path = "%s"
+	}`, s.path(bucket, key))
+}
+
+// EnsureTerraformLock does nothing as terraform's local backend locks state with a file lock
+func (s *LocalStore) EnsureTerraformLock(bucket string) error {
+	return nil
+}
+
+// CreateFile writes a file to the bucket unless it already exists, returning false if it did
+func (s *LocalStore) CreateFile(bucket, path string, contents []byte) (bool, error) {
+	bucketExists, err := s.BucketExists(bucket)
+	if err != nil {
+		return false, err
+	}
+	if !bucketExists {
+		return false, fmt.Errorf("bucket [%v] does not exist in [%v]", bucket, s.Dir)
+	}
+
+	filePath := s.path(bucket, path)
+	if err = os.MkdirAll(filepath.Dir(filePath), 0700); err != nil {
+		return false, err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(filePath), ".tmp-")
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(contents); err != nil {
+		tmp.Close()
+		return false, err
+	}
+	if err = tmp.Close(); err != nil {
+		return false, err
+	}
+	// Unlike rename, link fails if the file exists, so only one of several concurrent writers creates it
+	if err = os.Link(tmp.Name(), filePath); os.IsExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, s.writeVersion(bucket, path, contents)
+}
+
+// DeleteFile deletes a file from the bucket, keeping its versions
+func (s *LocalStore) DeleteFile(bucket, path string) error {
+	err := os.Remove(s.path(bucket, path))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// DeleteTerraformLock does nothing as EnsureTerraformLock creates nothing
+func (s *LocalStore) DeleteTerraformLock(bucket string) error {
+	return nil
+}
+
+// localVersionIDFormat names each version after when it was written, so that versions sort by name
+const localVersionIDFormat = "20060102T150405.000000000Z"
+
+func (s *LocalStore) writeVersion(bucket, path string, contents []byte) error {
+	dir := s.versionsPath(bucket, path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	id := time.Now().UTC().Format(localVersionIDFormat)
+	return ioutil.WriteFile(filepath.Join(dir, id), contents, 0600)
+}
+
+func (s *LocalStore) versionsPath(bucket, path string) string {
+	return s.path(bucket, ".versions", path)
+}
+
+func (s *LocalStore) path(elem ...string) string {
+	return filepath.Join(append([]string{s.Dir}, elem...)...)
+}
+
+func exists(path string) (bool, error) {
+	_, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,192 @@
+package store_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/EngineerBetter/control-tower/store"
+)
+
+func TestLocalStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "local-store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := store.NewLocal(dir)
+	bucket := "control-tower-test-eu-west-1-config"
+
+	exists, err := s.BucketExists(bucket)
+	if err != nil || exists {
+		t.Fatalf("BucketExists() before creation = %v, %v", exists, err)
+	}
+
+	if err = s.WriteFile(bucket, "config.json", []byte("{}")); err == nil {
+		t.Fatal("WriteFile() to a missing bucket should fail")
+	}
+
+	if err = s.CreateBucket(bucket); err != nil {
+		t.Fatalf("CreateBucket() error = %v", err)
+	}
+
+	if err = s.WriteFile(bucket, "config.json", []byte(`{"project":"test"}`)); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	has, err := s.HasFile(bucket, "config.json")
+	if err != nil || !has {
+		t.Fatalf("HasFile() = %v, %v", has, err)
+	}
+
+	has, err = s.HasFile(bucket, "director-state.json")
+	if err != nil || has {
+		t.Fatalf("HasFile() for a missing file = %v, %v", has, err)
+	}
+
+	contents, err := s.LoadFile(bucket, "config.json")
+	if err != nil || string(contents) != `{"project":"test"}` {
+		t.Fatalf("LoadFile() = %s, %v", contents, err)
+	}
+
+	if _, err = s.FileLastModified(bucket, "config.json"); err != nil {
+		t.Fatalf("FileLastModified() error = %v", err)
+	}
+
+	if err = ioutil.WriteFile(filepath.Join(dir, "not-a-bucket"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	buckets, err := s.ListBuckets()
+	if err != nil || !reflect.DeepEqual(buckets, []string{bucket}) {
+		t.Fatalf("ListBuckets() = %v, %v", buckets, err)
+	}
+
+	backend := s.TerraformBackend(bucket, "terraform.tfstate")
+	if !strings.Contains(backend, `backend "local"`) || !strings.Contains(backend, filepath.Join(dir, bucket, "terraform.tfstate")) {
+		t.Errorf("TerraformBackend() = %s", backend)
+	}
+
+	if err = s.DeleteVersionedBucket(bucket); err != nil {
+		t.Fatalf("DeleteVersionedBucket() error = %v", err)
+	}
+
+	exists, err = s.BucketExists(bucket)
+	if err != nil || exists {
+		t.Fatalf("BucketExists() after deletion = %v, %v", exists, err)
+	}
+}
+
+func TestLocalStore_ListBucketsWithoutDir(t *testing.T) {
+	s := store.NewLocal(filepath.Join(os.TempDir(), "control-tower-store-does-not-exist"))
+
+	buckets, err := s.ListBuckets()
+	if err != nil || len(buckets) != 0 {
+		t.Errorf("ListBuckets() = %v, %v", buckets, err)
+	}
+}
+
+func TestLocalStoreVersions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "local-store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := store.NewLocal(dir)
+	bucket := "control-tower-test-eu-west-1-config"
+	if err = s.CreateBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	versions, err := s.ListFileVersions(bucket, "config.json")
+	if err != nil || len(versions) != 0 {
+		t.Fatalf("ListFileVersions() before writing = %v, %v", versions, err)
+	}
+
+	for _, contents := range []string{"first", "second", "third"} {
+		if err = s.WriteFile(bucket, "config.json", []byte(contents)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	versions, err = s.ListFileVersions(bucket, "config.json")
+	if err != nil || len(versions) != 3 {
+		t.Fatalf("ListFileVersions() = %v, %v", versions, err)
+	}
+	for i, want := range []string{"third", "second", "first"} {
+		contents, err := s.LoadFileVersion(bucket, "config.json", versions[i].ID)
+		if err != nil || string(contents) != want {
+			t.Errorf("LoadFileVersion() of version %d = %s, %v, want %s", i, contents, err, want)
+		}
+	}
+	if versions[0].LastModified.Before(versions[2].LastModified) {
+		t.Errorf("ListFileVersions() is not newest first: %v", versions)
+	}
+	if !versions[0].Current || versions[1].Current || versions[2].Current {
+		t.Errorf("ListFileVersions() should only mark the newest version as current: %v", versions)
+	}
+
+	if err = s.DeleteFileVersion(bucket, "config.json", versions[2].ID); err != nil {
+		t.Fatalf("DeleteFileVersion() error = %v", err)
+	}
+	if versions, err = s.ListFileVersions(bucket, "config.json"); err != nil || len(versions) != 2 {
+		t.Errorf("ListFileVersions() after DeleteFileVersion() = %v, %v", versions, err)
+	}
+	if err = s.DeleteFileVersion(bucket, "config.json", "../../etc/passwd"); err == nil {
+		t.Error("DeleteFileVersion() of an invalid version should fail")
+	}
+
+	if _, err = s.LoadFileVersion(bucket, "config.json", "../../etc/passwd"); err == nil {
+		t.Error("LoadFileVersion() of an invalid version should fail")
+	}
+
+	buckets, err := s.ListBuckets()
+	if err != nil || !reflect.DeepEqual(buckets, []string{bucket}) {
+		t.Fatalf("ListBuckets() = %v, %v", buckets, err)
+	}
+}
+
+func TestLocalStoreCreateFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "local-store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := store.NewLocal(dir)
+	bucket := "control-tower-test-eu-west-1-config"
+	if err = s.CreateBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := s.CreateFile(bucket, "lock.json", []byte("first"))
+	if err != nil || !created {
+		t.Fatalf("CreateFile() = %v, %v, want the file created", created, err)
+	}
+
+	created, err = s.CreateFile(bucket, "lock.json", []byte("second"))
+	if err != nil || created {
+		t.Fatalf("CreateFile() of an existing file = %v, %v, want it left alone", created, err)
+	}
+	contents, err := s.LoadFile(bucket, "lock.json")
+	if err != nil || string(contents) != "first" {
+		t.Errorf("LoadFile() = %s, %v, want first", contents, err)
+	}
+
+	if err = s.DeleteFile(bucket, "lock.json"); err != nil {
+		t.Fatalf("DeleteFile() error = %v", err)
+	}
+	if err = s.DeleteFile(bucket, "lock.json"); err != nil {
+		t.Fatalf("DeleteFile() of a missing file error = %v", err)
+	}
+
+	created, err = s.CreateFile(bucket, "lock.json", []byte("third"))
+	if err != nil || !created {
+		t.Fatalf("CreateFile() after DeleteFile() = %v, %v, want the file created", created, err)
+	}
+}
@@ -0,0 +1,307 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/EngineerBetter/control-tower/iaas"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// S3Store keeps buckets in S3 or in any S3-compatible object store such as MinIO
+type S3Store struct {
+	region    string
+	endpoint  string
+	accessKey string
+	secretKey string
+	sess      *session.Session
+	s3        *s3.S3
+}
+
+// NewS3 returns an S3Store for region. A non-empty endpoint selects an S3-compatible
+// object store, which is addressed path-style.
+//
+// Credentials are taken from STATE_BACKEND_ACCESS_KEY_ID and STATE_BACKEND_SECRET_ACCESS_KEY
+// when set, so that state can live in a different account to the deployment, and from
+// the usual AWS credential chain otherwise.
+func NewS3(region, endpoint string) (*S3Store, error) {
+	s := &S3Store{
+		region:    region,
+		endpoint:  endpoint,
+		accessKey: os.Getenv("STATE_BACKEND_ACCESS_KEY_ID"),
+		secretKey: os.Getenv("STATE_BACKEND_SECRET_ACCESS_KEY"),
+	}
+
+	if (s.accessKey == "") != (s.secretKey == "") {
+		return nil, fmt.Errorf("STATE_BACKEND_ACCESS_KEY_ID and STATE_BACKEND_SECRET_ACCESS_KEY must be set together")
+	}
+
+	awsConfig := &aws.Config{Region: &region}
+	if s.accessKey != "" {
+		awsConfig.Credentials = credentials.NewStaticCredentials(s.accessKey, s.secretKey, "")
+	}
+	if endpoint != "" {
+		awsConfig.Endpoint = aws.String(endpoint)
+		awsConfig.S3ForcePathStyle = aws.Bool(true)
+	}
+
+	sess, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error creating session for S3 state backend: [%v]", err)
+	}
+	s.sess = sess
+	s.s3 = s3.New(sess)
+
+	return s, nil
+}
+
+// BucketExists checks if the named bucket exists
+func (s *S3Store) BucketExists(name string) (bool, error) {
+	_, err := s.s3.HeadBucket(&s3.HeadBucketInput{Bucket: &name})
+	if err == nil {
+		return true, nil
+	}
+	if isNotFound(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+// CreateBucket creates the named bucket with versioning enabled
+func (s *S3Store) CreateBucket(name string) error {
+	bucketInput := &s3.CreateBucketInput{
+		Bucket: &name,
+	}
+	// NOTE the location constraint should only be set if using a bucket OTHER than us-east-1
+	if s.region != "us-east-1" {
+		bucketInput.CreateBucketConfiguration = &s3.CreateBucketConfiguration{
+			LocationConstraint: aws.String(s.region),
+		}
+	}
+
+	if _, err := s.s3.CreateBucket(bucketInput); err != nil {
+		return fmt.Errorf("error creating bucket [%v]: [%v]", name, err)
+	}
+
+	_, err := s.s3.PutBucketVersioning(&s3.PutBucketVersioningInput{
+		Bucket: &name,
+		VersioningConfiguration: &s3.VersioningConfiguration{
+			Status: aws.String(s3.BucketVersioningStatusEnabled),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error enabling versioning on bucket [%v]: [%v]", name, err)
+	}
+
+	return nil
+}
+
+// DeleteVersionedBucket deletes every version of every object in the bucket, then the bucket
+func (s *S3Store) DeleteVersionedBucket(name string) error {
+	// Deleting a file, such as releasing the deployment lock, leaves a delete marker, which keeps the bucket
+	// from being deleted just as a version does
+	objects := []*s3.ObjectIdentifier{}
+	err := s.s3.ListObjectVersionsPages(&s3.ListObjectVersionsInput{Bucket: &name},
+		func(output *s3.ListObjectVersionsOutput, _ bool) bool {
+			for _, version := range output.Versions {
+				objects = append(objects, &s3.ObjectIdentifier{Key: version.Key, VersionId: version.VersionId})
+			}
+			for _, marker := range output.DeleteMarkers {
+				objects = append(objects, &s3.ObjectIdentifier{Key: marker.Key, VersionId: marker.VersionId})
+			}
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	for _, object := range objects {
+		_, err = s.s3.DeleteObject(&s3.DeleteObjectInput{
+			Bucket:    &name,
+			Key:       object.Key,
+			VersionId: object.VersionId,
+		})
+		if err != nil {
+			return fmt.Errorf("error deleting [%v] from bucket [%v]: [%v]", aws.StringValue(object.Key), name, err)
+		}
+	}
+
+	_, err = s.s3.DeleteBucket(&s3.DeleteBucketInput{Bucket: &name})
+	return err
+}
+
+// FileLastModified returns the time the object was last written
+func (s *S3Store) FileLastModified(bucket, path string) (time.Time, error) {
+	output, err := s.s3.HeadObject(&s3.HeadObjectInput{Bucket: &bucket, Key: &path})
+	if err != nil {
+		return time.Time{}, err
+	}
+	return aws.TimeValue(output.LastModified), nil
+}
+
+// HasFile returns true if the object exists
+func (s *S3Store) HasFile(bucket, path string) (bool, error) {
+	_, err := s.s3.HeadObject(&s3.HeadObjectInput{Bucket: &bucket, Key: &path})
+	if err == nil {
+		return true, nil
+	}
+	if isNotFound(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+// ListBuckets returns the names of all buckets visible to the credentials
+func (s *S3Store) ListBuckets() ([]string, error) {
+	output, err := s.s3.ListBuckets(&s3.ListBucketsInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, bucket := range output.Buckets {
+		names = append(names, aws.StringValue(bucket.Name))
+	}
+	return names, nil
+}
+
+// LoadFile reads an object
+func (s *S3Store) LoadFile(bucket, path string) ([]byte, error) {
+	output, err := s.s3.GetObject(&s3.GetObjectInput{Bucket: &bucket, Key: &path})
+	if err != nil {
+		return nil, err
+	}
+	defer output.Body.Close()
+
+	return ioutil.ReadAll(output.Body)
+}
+
+// ListFileVersions returns every stored version of an object, newest first
+func (s *S3Store) ListFileVersions(bucket, path string) ([]iaas.FileVersion, error) {
+	versions := []iaas.FileVersion{}
+	err := s.s3.ListObjectVersionsPages(&s3.ListObjectVersionsInput{Bucket: &bucket, Prefix: &path},
+		func(output *s3.ListObjectVersionsOutput, _ bool) bool {
+			for _, version := range output.Versions {
+				if aws.StringValue(version.Key) == path {
+					versions = append(versions, iaas.FileVersion{
+						ID:           aws.StringValue(version.VersionId),
+						LastModified: aws.TimeValue(version.LastModified),
+						Current:      aws.BoolValue(version.IsLatest),
+					})
+				}
+			}
+			return true
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	iaas.SortFileVersions(versions)
+	return versions, nil
+}
+
+// LoadFileVersion reads a specific version of an object
+func (s *S3Store) LoadFileVersion(bucket, path, versionID string) ([]byte, error) {
+	output, err := s.s3.GetObject(&s3.GetObjectInput{Bucket: &bucket, Key: &path, VersionId: &versionID})
+	if err != nil {
+		return nil, err
+	}
+	defer output.Body.Close()
+
+	return ioutil.ReadAll(output.Body)
+}
+
+// DeleteFileVersion permanently deletes a specific version of an object
+func (s *S3Store) DeleteFileVersion(bucket, path, versionID string) error {
+	_, err := s.s3.DeleteObject(&s3.DeleteObjectInput{Bucket: &bucket, Key: &path, VersionId: &versionID})
+	return err
+}
+
+// WriteFile writes an object
+func (s *S3Store) WriteFile(bucket, path string, contents []byte) error {
+	_, err := s.s3.PutObject(&s3.PutObjectInput{
+		Bucket: &bucket,
+		Key:    &path,
+		Body:   bytes.NewReader(contents),
+	})
+	return err
+}
+
+// CreateFile writes an object unless it already exists, returning false if it did
+func (s *S3Store) CreateFile(bucket, path string, contents []byte) (bool, error) {
+	return iaas.CreateS3Object(s.s3, bucket, path, contents)
+}
+
+// DeleteFile deletes an object
+func (s *S3Store) DeleteFile(bucket, path string) error {
+	_, err := s.s3.DeleteObject(&s3.DeleteObjectInput{Bucket: &bucket, Key: &path})
+	return err
+}
+
+// TerraformBackend returns an s3 backend pointing at the same endpoint and credentials as the store
+func (s *S3Store) TerraformBackend(bucket, key string) string {
+	backend := fmt.Sprintf(`backend "s3" {
+		bucket = "%s"
+		key    = "%s"
+		region = "%s"`, bucket, key, s.region)
+
+	if s.endpoint == "" {
+		backend += fmt.Sprintf(`
+		dynamodb_table = "%s"`, iaas.TerraformLockTable(bucket))
+	}
+
+	if s.accessKey != "" {
+		backend += fmt.Sprintf(`
+		access_key = "%s"
+		secret_key = "%s"`, s.accessKey, s.secretKey)
+	}
+
+	if s.endpoint != "" {
+		backend += fmt.Sprintf(`
+		endpoint                    = "%s"
+		force_path_style            = true
+		skip_credentials_validation = true
+		skip_metadata_api_check     = true
+		skip_region_validation      = true`, s.endpoint)
+	}
+
+	return backend + `
+	}`
+}
+
+// EnsureTerraformLock creates the DynamoDB table terraform locks the state kept in bucket with. S3-compatible
+// stores have no DynamoDB, so state kept in them is not locked.
+func (s *S3Store) EnsureTerraformLock(bucket string) error {
+	if s.endpoint != "" {
+		return nil
+	}
+	return iaas.EnsureDynamoDBLockTable(s.sess, iaas.TerraformLockTable(bucket))
+}
+
+// DeleteTerraformLock deletes the DynamoDB table created by EnsureTerraformLock
+func (s *S3Store) DeleteTerraformLock(bucket string) error {
+	if s.endpoint != "" {
+		return nil
+	}
+	return iaas.DeleteDynamoDBLockTable(s.sess, iaas.TerraformLockTable(bucket))
+}
+
+func isNotFound(err error) bool {
+	awsErr, ok := err.(awserr.Error)
+	if !ok {
+		return false
+	}
+
+	switch awsErr.Code() {
+	case "NotFound", s3.ErrCodeNoSuchBucket, s3.ErrCodeNoSuchKey:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package store
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/EngineerBetter/control-tower/iaas"
+)
+
+// Store keeps the config bucket of a deployment and tells terraform where to keep its state
+type Store interface {
+	BucketExists(name string) (bool, error)
+	CreateBucket(name string) error
+	DeleteVersionedBucket(name string) error
+	FileLastModified(bucket, path string) (time.Time, error)
+	HasFile(bucket, path string) (bool, error)
+	ListBuckets() ([]string, error)
+	ListFileVersions(bucket, path string) ([]iaas.FileVersion, error)
+	LoadFile(bucket, path string) ([]byte, error)
+	LoadFileVersion(bucket, path, versionID string) ([]byte, error)
+	DeleteFileVersion(bucket, path, versionID string) error
+	WriteFile(bucket, path string, contents []byte) error
+	// CreateFile writes a file unless it already exists, returning false if it did. Of several concurrent
+	// calls for the same file, only one returns true.
+	CreateFile(bucket, path string, contents []byte) (bool, error)
+	DeleteFile(bucket, path string) error
+	// TerraformBackend returns a terraform backend block which keeps state at key in bucket
+	// An empty string means the default backend of the IAAS should be used
+	TerraformBackend(bucket, key string) string
+	// EnsureTerraformLock creates anything terraform needs to lock the state kept in bucket
+	EnsureTerraformLock(bucket string) error
+	// DeleteTerraformLock deletes what EnsureTerraformLock created
+	DeleteTerraformLock(bucket string) error
+}
+
+// Backend names accepted by --state-backend
+const (
+	IAAS         = "iaas"
+	S3           = "s3"
+	S3Compatible = "s3-compatible"
+	GCS          = "gcs"
+	Local        = "local"
+)
+
+// Backends lists every supported backend
+var Backends = []string{IAAS, S3, S3Compatible, GCS, Local}
+
+// Options selects and configures a state backend
+type Options struct {
+	Backend  string
+	Region   string
+	Endpoint string
+	Dir      string
+}
+
+// Validate checks the options are sufficient for the chosen backend
+func (o Options) Validate() error {
+	switch o.Backend {
+	case "", IAAS, S3, GCS:
+		return nil
+	case S3Compatible:
+		if o.Endpoint == "" {
+			return fmt.Errorf("--state-backend-endpoint is required for the [%s] state backend", S3Compatible)
+		}
+		return nil
+	case Local:
+		if o.Dir == "" {
+			return fmt.Errorf("--state-backend-dir is required for the [%s] state backend", Local)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unknown state backend [%s], must be one of %v", o.Backend, Backends)
+}
+
+// IsIAAS returns true if state is kept in the object storage of the IAAS being deployed to
+func (o Options) IsIAAS() bool {
+	return o.Backend == "" || o.Backend == IAAS
+}
+
+// New returns the Store described by opts, falling back to the object storage of provider
+func New(provider iaas.Provider, opts Options) (Store, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch opts.Backend {
+	case S3:
+		return NewS3(regionFor(opts, provider, iaas.AWS, "eu-west-1"), "")
+	case S3Compatible:
+		return NewS3(regionFor(opts, nil, iaas.AWS, "us-east-1"), opts.Endpoint)
+	case GCS:
+		return NewGCS(regionFor(opts, provider, iaas.GCP, "europe-west1"))
+	case Local:
+		dir, err := filepath.Abs(opts.Dir)
+		if err != nil {
+			return nil, fmt.Errorf("error resolving state backend directory [%v]: [%v]", opts.Dir, err)
+		}
+		return NewLocal(dir), nil
+	}
+
+	return FromIAAS(provider), nil
+}
+
+// regionFor picks the region of the backend, preferring the region being deployed to when it is on the same IAAS
+func regionFor(opts Options, provider iaas.Provider, name iaas.Name, defaultRegion string) string {
+	switch {
+	case opts.Region != "":
+		return opts.Region
+	case provider != nil && provider.IAAS() == name:
+		return provider.Region()
+	}
+	return defaultRegion
+}
+
+// FromIAAS returns a Store which keeps state in the object storage of provider
+func FromIAAS(provider iaas.Provider) Store {
+	return &iaasStore{provider}
+}
+
+type iaasStore struct {
+	iaas.Provider
+}
+
+// TerraformBackend returns an empty string as the terraform templates already configure the backend of each IAAS
+func (s *iaasStore) TerraformBackend(bucket, key string) string {
+	return ""
+}
@@ -0,0 +1,124 @@
+package store_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/EngineerBetter/control-tower/iaas"
+	"github.com/EngineerBetter/control-tower/iaas/iaasfakes"
+	"github.com/EngineerBetter/control-tower/store"
+)
+
+func TestOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    store.Options
+		wantErr string
+	}{
+		{name: "default", opts: store.Options{}},
+		{name: "iaas", opts: store.Options{Backend: store.IAAS}},
+		{name: "s3", opts: store.Options{Backend: store.S3}},
+		{name: "gcs", opts: store.Options{Backend: store.GCS}},
+		{name: "s3-compatible", opts: store.Options{Backend: store.S3Compatible, Endpoint: "http://localhost:9000"}},
+		{name: "s3-compatible without endpoint", opts: store.Options{Backend: store.S3Compatible}, wantErr: "--state-backend-endpoint is required"},
+		{name: "local", opts: store.Options{Backend: store.Local, Dir: "state"}},
+		{name: "local without dir", opts: store.Options{Backend: store.Local}, wantErr: "--state-backend-dir is required"},
+		{name: "unknown", opts: store.Options{Backend: "ftp"}, wantErr: "unknown state backend [ftp]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	provider := &iaasfakes.FakeProvider{}
+	provider.IAASReturns(iaas.AWS)
+	provider.RegionReturns("eu-west-2")
+
+	t.Run("defaults to the IAAS", func(t *testing.T) {
+		s, err := store.New(provider, store.Options{})
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		if backend := s.TerraformBackend("bucket", "terraform.tfstate"); backend != "" {
+			t.Errorf("TerraformBackend() = %q, want the IAAS default", backend)
+		}
+	})
+
+	t.Run("local", func(t *testing.T) {
+		s, err := store.New(provider, store.Options{Backend: store.Local, Dir: "state"})
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		if _, ok := s.(*store.LocalStore); !ok {
+			t.Errorf("New() = %T, want *store.LocalStore", s)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if _, err := store.New(provider, store.Options{Backend: store.Local}); err == nil {
+			t.Error("New() should fail without a directory")
+		}
+	})
+}
+
+func TestS3Store_TerraformBackend(t *testing.T) {
+	os.Unsetenv("STATE_BACKEND_ACCESS_KEY_ID")
+	os.Unsetenv("STATE_BACKEND_SECRET_ACCESS_KEY")
+
+	s, err := store.NewS3("eu-west-2", "")
+	if err != nil {
+		t.Fatalf("NewS3() error = %v", err)
+	}
+	backend := s.TerraformBackend("bucket", "terraform.tfstate")
+	for _, want := range []string{`backend "s3"`, `bucket = "bucket"`, `key    = "terraform.tfstate"`, `region = "eu-west-2"`, `dynamodb_table = "bucket-terraform-lock"`} {
+		if !strings.Contains(backend, want) {
+			t.Errorf("TerraformBackend() = %s, want it to contain %s", backend, want)
+		}
+	}
+	if strings.Contains(backend, "endpoint") || strings.Contains(backend, "access_key") {
+		t.Errorf("TerraformBackend() = %s, want no endpoint or credentials", backend)
+	}
+
+	os.Setenv("STATE_BACKEND_ACCESS_KEY_ID", "access")
+	os.Setenv("STATE_BACKEND_SECRET_ACCESS_KEY", "secret")
+	defer os.Unsetenv("STATE_BACKEND_ACCESS_KEY_ID")
+	defer os.Unsetenv("STATE_BACKEND_SECRET_ACCESS_KEY")
+
+	s, err = store.NewS3("us-east-1", "http://minio:9000")
+	if err != nil {
+		t.Fatalf("NewS3() error = %v", err)
+	}
+	backend = s.TerraformBackend("bucket", "terraform.tfstate")
+	for _, want := range []string{`endpoint                    = "http://minio:9000"`, "force_path_style            = true", `access_key = "access"`, `secret_key = "secret"`} {
+		if !strings.Contains(backend, want) {
+			t.Errorf("TerraformBackend() = %s, want it to contain %s", backend, want)
+		}
+	}
+	// S3-compatible stores have no DynamoDB to lock state with
+	if strings.Contains(backend, "dynamodb_table") {
+		t.Errorf("TerraformBackend() = %s, want no lock table", backend)
+	}
+}
+
+func TestNewS3_PartialCredentials(t *testing.T) {
+	os.Setenv("STATE_BACKEND_ACCESS_KEY_ID", "access")
+	os.Unsetenv("STATE_BACKEND_SECRET_ACCESS_KEY")
+	defer os.Unsetenv("STATE_BACKEND_ACCESS_KEY_ID")
+
+	if _, err := store.NewS3("eu-west-1", ""); err == nil {
+		t.Error("NewS3() should fail when only one of the credentials is set")
+	}
+}
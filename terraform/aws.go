@@ -14,10 +14,13 @@ import (
 type AWSInputVars struct {
 	AllowIPs               string
 	AvailabilityZone       string
+	Backend                string
 	ConfigBucket           string
 	Deployment             string
 	HostedZoneID           string
 	HostedZoneRecordPrefix string
+	// LockTable is the DynamoDB table the default s3 backend locks state with
+	LockTable              string
 	Namespace              string
 	NetworkCIDR            string
 	PrivateCIDR            string
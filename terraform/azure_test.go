@@ -0,0 +1,84 @@
+package terraform_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/EngineerBetter/control-tower/resource"
+	. "github.com/EngineerBetter/control-tower/terraform"
+)
+
+func TestAzureInputVars_ConfigureTerraform(t *testing.T) {
+	tests := []struct {
+		name      string
+		inputVars AzureInputVars
+		contents  string
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name: "Success",
+			inputVars: AzureInputVars{
+				AllowIPs:             `"1.2.3.4/32"`,
+				ConfigBucket:         "control-tower-project-westeurope-config",
+				Deployment:           "control-tower-project",
+				DNSZoneName:          "example.com",
+				DNSZoneResourceGroup: "dns",
+				StorageAccount:       "storage",
+			},
+			contents: resource.AzureTerraformConfig,
+			want: []string{
+				`container_name       = "control-tower-project-westeurope-config"`,
+				`source_address_prefixes    = ["1.2.3.4/32"]`,
+				`resource "azurerm_dns_a_record" "dns"`,
+			},
+		},
+		{
+			name:     "Failure",
+			contents: "{{ .FakeKey }} \n",
+			wantErr:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.inputVars.ConfigureTerraform(test.contents)
+			if (err != nil) != test.wantErr {
+				t.Errorf("AzureInputVars.ConfigureTerraform() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+			for _, want := range test.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("AzureInputVars.ConfigureTerraform() output does not contain %q", want)
+				}
+			}
+		})
+	}
+}
+
+func TestAzureMetadata_Get(t *testing.T) {
+	outputs := &AzureOutputs{
+		VMsResourceGroup: MetadataStringValue{Value: "control-tower-project-vms"},
+	}
+	got, err := outputs.Get("VMsResourceGroup")
+	if err != nil || got != "control-tower-project-vms" {
+		t.Errorf("AzureOutputs.Get() = %v, %v; want control-tower-project-vms, nil", got, err)
+	}
+	if _, err = outputs.Get("FakeKey"); err == nil {
+		t.Error("AzureOutputs.Get() expected an error for an unknown key")
+	}
+}
+
+func TestAzureMetadata_Init(t *testing.T) {
+	outputs := &AzureOutputs{}
+	buffer := bytes.NewBufferString(`{"nat_gateway_ip":{"sensitive":false,"type":"string","value":"fakeIP"}}`)
+	if err := outputs.Init(buffer); err != nil {
+		t.Fatalf("AzureOutputs.Init() error = %v", err)
+	}
+	if outputs.NatGatewayIP.Value != "fakeIP" {
+		t.Errorf("AzureOutputs.Init() NatGatewayIP = %v, want fakeIP", outputs.NatGatewayIP.Value)
+	}
+	if err := outputs.AssertValid(); err == nil {
+		t.Error("AzureOutputs.AssertValid() expected an error for missing outputs")
+	}
+}
@@ -13,6 +13,7 @@ import (
 // InputVars holds all the parameters GCP IAAS needs
 type GCPInputVars struct {
 	AllowIPs           string
+	Backend            string
 	ConfigBucket       string
 	DBName             string
 	DBPassword         string
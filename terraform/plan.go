@@ -0,0 +1,84 @@
+package terraform
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PlanSummary holds the changes terraform would make when applying a config
+type PlanSummary struct {
+	ToAdd     int
+	ToChange  int
+	ToDestroy int
+	// Replaced lists the addresses of resources that would be destroyed and recreated
+	Replaced []string
+	// Destroyed lists the addresses of resources that would be destroyed outright
+	Destroyed []string
+}
+
+var (
+	planCountsRegexp = regexp.MustCompile(`Plan: (\d+) to add, (\d+) to change, (\d+) to destroy`)
+	// terraform >= 0.12 describes each resource change in a comment
+	replacedRegexp  = regexp.MustCompile(`^\s*# (\S+) must be replaced`)
+	destroyedRegexp = regexp.MustCompile(`^\s*# (\S+) will be destroyed`)
+	// terraform 0.11 prefixes each resource with its action symbol
+	legacyReplacedRegexp  = regexp.MustCompile(`^\s*-/\+ (\S+\.\S+)`)
+	legacyDestroyedRegexp = regexp.MustCompile(`^\s*- (\S+\.\S+)$`)
+)
+
+// ParsePlan extracts a PlanSummary from the human readable output of terraform plan
+func ParsePlan(output string) (PlanSummary, error) {
+	var summary PlanSummary
+	var foundCounts bool
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if m := planCountsRegexp.FindStringSubmatch(line); m != nil {
+			counts := make([]int, 3)
+			for i := range counts {
+				n, err := strconv.Atoi(m[i+1])
+				if err != nil {
+					return summary, fmt.Errorf("could not parse terraform plan summary %q: [%v]", line, err)
+				}
+				counts[i] = n
+			}
+			summary.ToAdd, summary.ToChange, summary.ToDestroy = counts[0], counts[1], counts[2]
+			foundCounts = true
+			continue
+		}
+
+		if m := replacedRegexp.FindStringSubmatch(line); m != nil {
+			summary.Replaced = append(summary.Replaced, m[1])
+		} else if m := legacyReplacedRegexp.FindStringSubmatch(line); m != nil {
+			summary.Replaced = append(summary.Replaced, m[1])
+		} else if m := destroyedRegexp.FindStringSubmatch(line); m != nil {
+			summary.Destroyed = append(summary.Destroyed, m[1])
+		} else if m := legacyDestroyedRegexp.FindStringSubmatch(line); m != nil {
+			summary.Destroyed = append(summary.Destroyed, m[1])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return summary, err
+	}
+
+	if !foundCounts && !strings.Contains(output, "No changes.") {
+		return summary, fmt.Errorf("could not find a change summary in terraform plan output")
+	}
+
+	return summary, nil
+}
+
+// HasChanges returns true if terraform would make any change at all
+func (s PlanSummary) HasChanges() bool {
+	return s.ToAdd+s.ToChange+s.ToDestroy > 0
+}
+
+// IsDestructive returns true if terraform would destroy or replace any resource
+func (s PlanSummary) IsDestructive() bool {
+	return s.ToDestroy > 0 || len(s.Replaced) > 0 || len(s.Destroyed) > 0
+}
@@ -0,0 +1,87 @@
+package terraform_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/EngineerBetter/control-tower/terraform"
+)
+
+func TestParsePlan(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    terraform.PlanSummary
+		wantErr bool
+	}{
+		{
+			name:   "No changes",
+			output: "No changes. Infrastructure is up-to-date.\n",
+			want:   terraform.PlanSummary{},
+		},
+		{
+			name: "Additions and in-place changes",
+			output: `  # aws_instance.web will be updated in-place
+  ~ resource "aws_instance" "web" {
+    }
+
+  # aws_eip.atc will be created
+  + resource "aws_eip" "atc" {
+    }
+
+Plan: 1 to add, 1 to change, 0 to destroy.
+`,
+			want: terraform.PlanSummary{ToAdd: 1, ToChange: 1},
+		},
+		{
+			name: "Replacement and destruction",
+			output: `  # aws_db_instance.default must be replaced
+-/+ resource "aws_db_instance" "default" {
+    }
+
+  # aws_eip.director will be destroyed
+  - resource "aws_eip" "director" {
+    }
+
+Plan: 1 to add, 0 to change, 2 to destroy.
+`,
+			want: terraform.PlanSummary{
+				ToAdd:     1,
+				ToDestroy: 2,
+				Replaced:  []string{"aws_db_instance.default"},
+				Destroyed: []string{"aws_eip.director"},
+			},
+		},
+		{
+			name: "Legacy terraform output",
+			output: `-/+ aws_db_instance.default (new resource required)
+- aws_eip.director
+
+Plan: 1 to add, 0 to change, 2 to destroy.
+`,
+			want: terraform.PlanSummary{
+				ToAdd:     1,
+				ToDestroy: 2,
+				Replaced:  []string{"aws_db_instance.default"},
+				Destroyed: []string{"aws_eip.director"},
+			},
+		},
+		{
+			name:    "Unrecognised output",
+			output:  "Error: something went wrong\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := terraform.ParsePlan(tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParsePlan() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParsePlan() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,14 +2,20 @@ package terraform
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"os/exec"
 	"path"
+	"path/filepath"
+	"syscall"
 
 	"github.com/EngineerBetter/control-tower/iaas"
 	"github.com/EngineerBetter/control-tower/resource"
@@ -32,18 +38,59 @@ type Outputs interface {
 //go:generate counterfeiter . CLIInterface
 //CLIInterface is the abstraction of execCmd
 type CLIInterface interface {
-	Apply(InputVars) error
-	Destroy(InputVars) error
-	BuildOutput(InputVars) (Outputs, error)
+	Apply(context.Context, InputVars) (Outputs, error)
+	Destroy(context.Context, InputVars) error
+	BuildOutput(context.Context, InputVars) (Outputs, error)
+	Plan(context.Context, InputVars) (PlanSummary, error)
+}
+
+// localBackend keeps state in the working directory
+const localBackend = `backend "local" {}`
+
+// WithLocalBackend returns config with its state kept in the working directory rather than the config bucket,
+// so that a deployment can be planned before its config bucket exists
+func WithLocalBackend(config InputVars) InputVars {
+	switch vars := config.(type) {
+	case *AWSInputVars:
+		local := *vars
+		local.Backend = localBackend
+		return &local
+	case *GCPInputVars:
+		local := *vars
+		local.Backend = localBackend
+		return &local
+	case *AzureInputVars:
+		local := *vars
+		local.Backend = localBackend
+		return &local
+	}
+	return config
 }
 
 // CLI struct holds the abstraction of execCmd
 type CLI struct {
-	execCmd func(string, ...string) *exec.Cmd
+	execCmd func(context.Context, string, ...string) *exec.Cmd
 	Path    string
 	iaas    iaas.Name
+	// workingDir keeps the .terraform directory of a deployment between calls when set, otherwise each
+	// call initialises a new temporary directory
+	workingDir string
+	// pluginCacheDir is shared by every deployment, so that providers are only downloaded once
+	pluginCacheDir string
+	// ensureLock creates what the backend locks the state with, if anything
+	ensureLock func() error
 }
 
+// configFilename is the name of the rendered config in the working directory
+const configFilename = "main.tf"
+
+// initFilename records the digest of the config and terraform binary that the working directory was initialised with
+const initFilename = ".control-tower-init"
+
+// lockFilename is locked by the call using the working directory, so that concurrent commands against the same
+// deployment, such as info during a deploy, cannot change the config or re-initialise it under each other
+const lockFilename = ".control-tower-lock"
+
 //Factory function to return iaas-specific outputs
 func outputsFor(name iaas.Name) (Outputs, error) {
 	switch name {
@@ -51,6 +98,8 @@ func outputsFor(name iaas.Name) (Outputs, error) {
 		return &AWSOutputs{}, nil
 	case iaas.GCP:
 		return &GCPOutputs{}, nil
+	case iaas.Azure:
+		return &AzureOutputs{}, nil
 	}
 	return &NullOutputs{}, errors.New("terraform: " + name.String() + " not a valid iaas provider")
 }
@@ -82,10 +131,35 @@ func DownloadTerraform(versionFile []byte) Option {
 	}
 }
 
+// Cache returns an Option keeping the working directory of deployment, and the providers downloaded by
+// terraform init, under the user's cache directory so that later calls can skip terraform init
+func Cache(deployment string) Option {
+	return func(c *CLI) error {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			// Without a cache directory every call initialises a temporary directory, as it always used to
+			return nil
+		}
+		root := filepath.Join(cacheDir, "control-tower", "terraform")
+		c.workingDir = filepath.Join(root, "deployments", deployment)
+		c.pluginCacheDir = filepath.Join(root, "plugins")
+		return nil
+	}
+}
+
+// EnsureLock returns an Option calling ensureLock before terraform init for every command that locks the state,
+// so that what the backend locks the state with exists even for deployments made before it was needed
+func EnsureLock(ensureLock func() error) Option {
+	return func(c *CLI) error {
+		c.ensureLock = ensureLock
+		return nil
+	}
+}
+
 // New provides a new CLI
 func New(iaas iaas.Name, ops ...Option) (*CLI, error) {
 	cli := &CLI{
-		execCmd: exec.Command,
+		execCmd: util.CommandContext,
 		Path:    "terraform",
 		iaas:    iaas,
 	}
@@ -111,7 +185,10 @@ func (n *NullOutputs) Init(*bytes.Buffer) error { return nil }
 
 func (n *NullOutputs) Get(string) (string, error) { return "", nil }
 
-func (c *CLI) init(config InputVars) (string, error) {
+// init returns a directory initialised with the rendered config, and a function to call once it is no longer
+// needed. terraform init is skipped when the deployment's working directory was initialised with the same config.
+// lock is false for commands which do not lock the state
+func (c *CLI) init(ctx context.Context, config InputVars, lock bool) (string, func(), error) {
 	var (
 		tfConfig string
 		err      error
@@ -120,79 +197,220 @@ func (c *CLI) init(config InputVars) (string, error) {
 	case iaas.AWS:
 		tfConfig, err = config.ConfigureTerraform(resource.AWSTerraformConfig)
 		if err != nil {
-			return "", err
+			return "", nil, err
 		}
 	case iaas.GCP:
 		tfConfig, err = config.ConfigureTerraform(resource.GCPTerraformConfig)
 		if err != nil {
-			return "", err
+			return "", nil, err
+		}
+	case iaas.Azure:
+		tfConfig, err = config.ConfigureTerraform(resource.AzureTerraformConfig)
+		if err != nil {
+			return "", nil, err
 		}
 	}
 
-	terraformConfigPath, err := writeTempFile([]byte(tfConfig))
+	if lock && c.ensureLock != nil {
+		if err = c.ensureLock(); err != nil {
+			return "", nil, err
+		}
+	}
+
+	unlock, err := c.lockWorkingDir()
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
-	cmd := c.execCmd(c.Path, "init")
-	cmd.Dir = terraformConfigPath
-	cmd.Stderr = os.Stderr
-	err = cmd.Run()
+	if unlock == nil {
+		// Either there is no working directory, or another command is using it
+		return c.initTempDir(ctx, tfConfig)
+	}
+
+	// The rendered config holds secrets such as database passwords, so it only exists while terraform runs
+	configPath := filepath.Join(c.workingDir, configFilename)
+	cleanup := func() {
+		os.Remove(configPath)
+		unlock()
+	}
+	if err = ioutil.WriteFile(configPath, []byte(tfConfig), 0600); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	// Provider versions are part of the config, so an unchanged config needs no new providers
+	digest := sha256.Sum256([]byte(c.Path + "\n" + tfConfig))
+	initialised := hex.EncodeToString(digest[:])
+	initPath := filepath.Join(c.workingDir, initFilename)
+	if previous, err1 := ioutil.ReadFile(initPath); err1 == nil && string(previous) == initialised {
+		return c.workingDir, cleanup, nil
+	}
+
+	os.Remove(initPath)
+	if err = c.runInit(ctx, c.workingDir); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	if err = ioutil.WriteFile(initPath, []byte(initialised), 0600); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return c.workingDir, cleanup, nil
+}
+
+// lockWorkingDir creates the working directory and locks it, returning a function to unlock it. It returns
+// nil if there is no working directory or another command holds the lock
+func (c *CLI) lockWorkingDir() (func(), error) {
+	if c.workingDir == "" {
+		return nil, nil
+	}
+	if err := os.MkdirAll(c.workingDir, 0700); err != nil {
+		return nil, err
+	}
+	// Directories made by earlier versions may be more permissive
+	if err := os.Chmod(c.workingDir, 0700); err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(filepath.Join(c.workingDir, lockFilename), os.O_RDWR|os.O_CREATE, 0600)
 	if err != nil {
-		os.RemoveAll(terraformConfigPath)
-		return "", err
+		return nil, err
 	}
-	return terraformConfigPath, nil
+	err = syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if err == syscall.EWOULDBLOCK {
+		f.Close()
+		return nil, nil
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	// Closing the file releases the lock
+	return func() { f.Close() }, nil
 }
 
-// Apply runs terraform apply for a given config
-func (c *CLI) Apply(config InputVars) error {
-	terraformConfigPath, err := c.init(config)
+// initTempDir returns a new temporary directory initialised with tfConfig, and a function removing it
+func (c *CLI) initTempDir(ctx context.Context, tfConfig string) (string, func(), error) {
+	terraformConfigPath, err := writeTempFile([]byte(tfConfig))
 	if err != nil {
-		return err
+		return "", nil, err
 	}
+	cleanup := func() { os.RemoveAll(terraformConfigPath) }
+	if err = c.runInit(ctx, terraformConfigPath); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return terraformConfigPath, cleanup, nil
+}
 
-	defer os.RemoveAll(terraformConfigPath)
+func (c *CLI) runInit(ctx context.Context, dir string) error {
+	// -reconfigure uses the backend in the config as it is, rather than offering to migrate state from the one
+	// the directory was last initialised with
+	cmd := c.command(ctx, dir, "init", "-input=false", "-reconfigure")
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
 
-	cmd := c.execCmd(c.Path, "apply", "-input=false", "-auto-approve")
-	cmd.Dir = terraformConfigPath
+// command returns terraform with args, to be run in dir
+func (c *CLI) command(ctx context.Context, dir string, args ...string) *exec.Cmd {
+	cmd := c.execCmd(ctx, c.Path, args...)
+	cmd.Dir = dir
+	if c.pluginCacheDir != "" && os.MkdirAll(c.pluginCacheDir, 0700) == nil {
+		if cmd.Env == nil {
+			cmd.Env = os.Environ()
+		}
+		cmd.Env = append(cmd.Env, "TF_PLUGIN_CACHE_DIR="+c.pluginCacheDir)
+	}
+	return cmd
+}
+
+// Apply runs terraform apply for a given config, and returns the outputs of the applied config
+func (c *CLI) Apply(ctx context.Context, config InputVars) (Outputs, error) {
+	terraformConfigPath, cleanup, err := c.init(ctx, config, true)
+	if err != nil {
+		return nil, err
+	}
 
+	defer cleanup()
+
+	cmd := c.command(ctx, terraformConfigPath, "apply", "-input=false", "-auto-approve")
 	cmd.Stderr = os.Stderr
 	cmd.Stdout = os.Stdout
+	if err = cmd.Run(); err != nil {
+		return nil, err
+	}
 
-	return cmd.Run()
+	return c.outputs(ctx, terraformConfigPath)
 }
 
-// Destroy destroys terraform resources specified in a config file
-func (c *CLI) Destroy(config InputVars) error {
-	terraformConfigPath, err := c.init(config)
+// Plan runs terraform plan for a given config and summarises the changes it would make
+func (c *CLI) Plan(ctx context.Context, config InputVars) (PlanSummary, error) {
+	// A plan changes nothing, so it does not lock the state nor create anything to lock it with
+	terraformConfigPath, cleanup, err := c.init(ctx, config, false)
+	if err != nil {
+		return PlanSummary{}, err
+	}
+
+	defer cleanup()
+
+	stdoutBuffer := bytes.NewBuffer(nil)
+	cmd := c.command(ctx, terraformConfigPath, "plan", "-input=false", "-lock=false", "-no-color", "-detailed-exitcode")
+	cmd.Stderr = os.Stderr
+	cmd.Stdout = io.MultiWriter(os.Stderr, stdoutBuffer)
+
+	// -detailed-exitcode exits 2 when the plan succeeded and contains changes
+	err = cmd.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 2 {
+		err = nil
+	}
+	if err != nil {
+		return PlanSummary{}, err
+	}
+
+	return ParsePlan(stdoutBuffer.String())
+}
+
+// Destroy destroys terraform resources specified in a config file, then removes the working directory
+func (c *CLI) Destroy(ctx context.Context, config InputVars) error {
+	terraformConfigPath, cleanup, err := c.init(ctx, config, true)
 	if err != nil {
 		return err
 	}
 
-	defer os.RemoveAll(terraformConfigPath)
+	defer cleanup()
 
-	cmd := c.execCmd(c.Path, "destroy", "-auto-approve")
-	cmd.Dir = terraformConfigPath
+	cmd := c.command(ctx, terraformConfigPath, "destroy", "-auto-approve")
 	cmd.Stderr = os.Stderr
 	cmd.Stdout = os.Stdout
-	return cmd.Run()
+	if err = cmd.Run(); err != nil {
+		return err
+	}
+
+	if terraformConfigPath == c.workingDir {
+		return os.RemoveAll(c.workingDir)
+	}
+	return nil
 }
 
 // BuildOutput builds the terraform output
-func (c *CLI) BuildOutput(config InputVars) (Outputs, error) {
-	terraformConfigPath, err := c.init(config)
+func (c *CLI) BuildOutput(ctx context.Context, config InputVars) (Outputs, error) {
+	// terraform output only reads the state, so it does not lock it nor create anything to lock it with
+	terraformConfigPath, cleanup, err := c.init(ctx, config, false)
 	if err != nil {
 		return nil, err
 	}
 
-	defer os.RemoveAll(terraformConfigPath)
+	defer cleanup()
 
+	return c.outputs(ctx, terraformConfigPath)
+}
+
+// outputs runs terraform output in an initialised directory
+func (c *CLI) outputs(ctx context.Context, terraformConfigPath string) (Outputs, error) {
 	stdoutBuffer := bytes.NewBuffer(nil)
-	cmd := c.execCmd(c.Path, "output", "-json")
-	cmd.Dir = terraformConfigPath
+	cmd := c.command(ctx, terraformConfigPath, "output", "-json")
 	cmd.Stderr = os.Stderr
 	cmd.Stdout = stdoutBuffer
-	if err = cmd.Run(); err != nil {
+	if err := cmd.Run(); err != nil {
 		return nil, err
 	}
 
@@ -208,7 +426,8 @@ func (c *CLI) BuildOutput(config InputVars) (Outputs, error) {
 }
 
 func writeTempFile(data []byte) (string, error) {
-	mode := int(0740)
+	// The rendered config holds secrets such as database passwords
+	mode := int(0700)
 	perm := os.FileMode(mode)
 	dirName := randomString()
 	filePath := path.Join(os.TempDir(), dirName)
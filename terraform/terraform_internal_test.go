@@ -1,10 +1,25 @@
 package terraform
 
-import "os/exec"
+import (
+	"context"
+	"os/exec"
+)
 
-func FakeExec(execCmd func(string, ...string) *exec.Cmd) Option {
+func FakeExec(execCmd func(context.Context, string, ...string) *exec.Cmd) Option {
 	return func(c *CLI) error {
 		c.execCmd = execCmd
 		return nil
 	}
 }
+
+func PluginCacheDir(pluginCacheDir string) Option {
+	return WorkingDir("", pluginCacheDir)
+}
+
+func WorkingDir(workingDir, pluginCacheDir string) Option {
+	return func(c *CLI) error {
+		c.workingDir = workingDir
+		c.pluginCacheDir = pluginCacheDir
+		return nil
+	}
+}
@@ -2,9 +2,18 @@ package terraform_test
 
 import (
 	"bytes"
-	"github.com/EngineerBetter/control-tower/iaas"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"syscall"
 	"testing"
 
+	"github.com/EngineerBetter/control-tower/iaas"
+
 	"github.com/EngineerBetter/control-tower/internal/fakeexec"
 	"github.com/EngineerBetter/control-tower/terraform"
 	"github.com/stretchr/testify/require"
@@ -30,10 +39,19 @@ func (mockInputVars *mockTerraformInputVars) ConfigureTerraform(terraformContent
 func (mockInputVars *mockTerraformInputVars) Build(data map[string]interface{}) error {
 	return nil
 }
+func TestExecCommandHelper(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	fmt.Print(os.Getenv("STDOUT"))
+	i, _ := strconv.Atoi(os.Getenv("EXIT_STATUS"))
+	os.Exit(i)
+}
+
 func TestCLI_Apply(t *testing.T) {
 	e := fakeexec.New(t)
 	defer e.Finish()
-	mockCLIent, err := terraform.New(iaas.AWS, terraform.FakeExec(e.Cmd()))
+	mockCLIent, err := terraform.New(iaas.AWS, terraform.FakeExec(e.CmdContext()))
 	require.NoError(t, err)
 
 	config := &mockTerraformInputVars{}
@@ -50,14 +68,18 @@ func TestCLI_Apply(t *testing.T) {
 		require.Equal(t, args[2], "-auto-approve")
 
 	})
-	err = mockCLIent.Apply(config)
+	e.Expect("terraform", "output", "-json").Outputs(`{"director_public_ip":{"value":"1.2.3.4"}}`)
+	outputs, err := mockCLIent.Apply(context.Background(), config)
+	require.NoError(t, err)
+	ip, err := outputs.Get("DirectorPublicIP")
 	require.NoError(t, err)
+	require.Equal(t, "1.2.3.4", ip)
 }
 
 func TestCLI_ApplyPlan(t *testing.T) {
 	e := fakeexec.New(t)
 	defer e.Finish()
-	mockCLIent, err := terraform.New(iaas.AWS, terraform.FakeExec(e.Cmd()))
+	mockCLIent, err := terraform.New(iaas.AWS, terraform.FakeExec(e.CmdContext()))
 	require.NoError(t, err)
 
 	config := &mockTerraformInputVars{}
@@ -71,14 +93,181 @@ func TestCLI_ApplyPlan(t *testing.T) {
 		require.Equal(t, "terraform", command)
 		require.Equal(t, args[0], "apply")
 	})
-	err = mockCLIent.Apply(config)
+	e.Expect("terraform", "output", "-json").Outputs("{}")
+	_, err = mockCLIent.Apply(context.Background(), config)
+	require.NoError(t, err)
+}
+
+func TestCLI_ApplyUsesItsOwnDirectory(t *testing.T) {
+	e := fakeexec.New(t)
+	defer e.Finish()
+	pluginCacheDir := filepath.Join(t.TempDir(), "plugins")
+	var cmds []*exec.Cmd
+	execCmd := e.CmdContext()
+	recordCmd := func(ctx context.Context, command string, args ...string) *exec.Cmd {
+		cmd := execCmd(ctx, command, args...)
+		cmds = append(cmds, cmd)
+		return cmd
+	}
+	mockCLIent, err := terraform.New(iaas.AWS, terraform.FakeExec(recordCmd), terraform.PluginCacheDir(pluginCacheDir))
+	require.NoError(t, err)
+
+	config := &mockTerraformInputVars{}
+
+	e.Expect("terraform", "init", "-input=false", "-reconfigure")
+	e.Expect("terraform", "apply", "-input=false", "-auto-approve")
+	e.Expect("terraform", "output", "-json").Outputs("{}")
+	_, err = mockCLIent.Apply(context.Background(), config)
+	require.NoError(t, err)
+	require.DirExists(t, pluginCacheDir)
+
+	require.Len(t, cmds, 3)
+	for _, cmd := range cmds {
+		require.Equal(t, cmds[0].Dir, cmd.Dir)
+		require.Contains(t, cmd.Env, "TF_PLUGIN_CACHE_DIR="+pluginCacheDir)
+	}
+	// The rendered config holds secrets, so it is removed after each call
+	_, err = os.Stat(cmds[0].Dir)
+	require.True(t, os.IsNotExist(err))
+
+	// Every call initialises a directory of its own
+	e.Expect("terraform", "init", "-input=false", "-reconfigure")
+	e.Expect("terraform", "output", "-json").Outputs("{}")
+	_, err = mockCLIent.BuildOutput(context.Background(), config)
+	require.NoError(t, err)
+	require.Len(t, cmds, 5)
+	require.NotEqual(t, cmds[0].Dir, cmds[3].Dir)
+}
+
+func TestCLI_ApplyReusesWorkingDir(t *testing.T) {
+	e := fakeexec.New(t)
+	defer e.Finish()
+	cacheDir := t.TempDir()
+	workingDir := filepath.Join(cacheDir, "deployments", "aws-eu-west-1-concourse")
+	mockCLIent, err := terraform.New(iaas.AWS, terraform.FakeExec(e.CmdContext()), terraform.WorkingDir(workingDir, filepath.Join(cacheDir, "plugins")))
+	require.NoError(t, err)
+
+	config := &mockTerraformInputVars{}
+
+	e.Expect("terraform", "init", "-input=false", "-reconfigure")
+	e.Expect("terraform", "apply", "-input=false", "-auto-approve")
+	e.Expect("terraform", "output", "-json").Outputs("{}")
+	_, err = mockCLIent.Apply(context.Background(), config)
+	require.NoError(t, err)
+	require.DirExists(t, filepath.Join(cacheDir, "plugins"))
+	info, err := os.Stat(workingDir)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0700), info.Mode().Perm())
+	// The rendered config holds secrets, so it is removed after each call
+	_, err = os.Stat(filepath.Join(workingDir, "main.tf"))
+	require.True(t, os.IsNotExist(err))
+
+	// The config has not changed, so the directory does not need initialising again
+	e.Expect("terraform", "output", "-json").Outputs("{}")
+	_, err = mockCLIent.BuildOutput(context.Background(), config)
+	require.NoError(t, err)
+
+	e.Expect("terraform", "destroy", "-auto-approve")
+	err = mockCLIent.Destroy(context.Background(), config)
+	require.NoError(t, err)
+	_, err = os.Stat(workingDir)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestCLI_UsesTempDirWhileWorkingDirIsInUse(t *testing.T) {
+	e := fakeexec.New(t)
+	defer e.Finish()
+	cacheDir := t.TempDir()
+	workingDir := filepath.Join(cacheDir, "deployments", "aws-eu-west-1-concourse")
+	var cmds []*exec.Cmd
+	execCmd := e.CmdContext()
+	recordCmd := func(ctx context.Context, command string, args ...string) *exec.Cmd {
+		cmd := execCmd(ctx, command, args...)
+		cmds = append(cmds, cmd)
+		return cmd
+	}
+	mockCLIent, err := terraform.New(iaas.AWS, terraform.FakeExec(recordCmd), terraform.WorkingDir(workingDir, filepath.Join(cacheDir, "plugins")))
+	require.NoError(t, err)
+
+	// Another command, such as a deploy, is running terraform in the working directory
+	require.NoError(t, os.MkdirAll(workingDir, 0700))
+	lock, err := os.Create(filepath.Join(workingDir, ".control-tower-lock"))
 	require.NoError(t, err)
+	defer lock.Close()
+	require.NoError(t, syscall.Flock(int(lock.Fd()), syscall.LOCK_EX))
+
+	e.Expect("terraform", "init", "-input=false", "-reconfigure")
+	e.Expect("terraform", "output", "-json").Outputs("{}")
+	_, err = mockCLIent.BuildOutput(context.Background(), &mockTerraformInputVars{})
+	require.NoError(t, err)
+
+	require.Len(t, cmds, 2)
+	require.NotEqual(t, workingDir, cmds[0].Dir)
+	require.Equal(t, cmds[0].Dir, cmds[1].Dir)
+	_, err = os.Stat(filepath.Join(workingDir, "main.tf"))
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(cmds[0].Dir)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestCLI_EnsuresLockBeforeInit(t *testing.T) {
+	e := fakeexec.New(t)
+	defer e.Finish()
+	var ensured int
+	mockCLIent, err := terraform.New(iaas.AWS, terraform.FakeExec(e.CmdContext()), terraform.EnsureLock(func() error {
+		ensured++
+		return nil
+	}))
+	require.NoError(t, err)
+
+	config := &terraform.AWSInputVars{}
+
+	e.Expect("terraform", "init", "-input=false", "-reconfigure")
+	e.Expect("terraform", "destroy", "-auto-approve")
+	err = mockCLIent.Destroy(context.Background(), config)
+	require.NoError(t, err)
+	require.Equal(t, 1, ensured)
+}
+
+func TestCLI_ReadOnlyCommandsDoNotEnsureLock(t *testing.T) {
+	e := fakeexec.New(t)
+	defer e.Finish()
+	mockCLIent, err := terraform.New(iaas.AWS, terraform.FakeExec(e.CmdContext()), terraform.EnsureLock(func() error {
+		t.Fatal("read-only commands must not create anything to lock the state with")
+		return nil
+	}))
+	require.NoError(t, err)
+
+	config := &terraform.AWSInputVars{}
+
+	// control-tower plan runs both of these, and must not create anything
+	e.Expect("terraform", "init", "-input=false", "-reconfigure")
+	e.Expect("terraform", "plan", "-input=false", "-lock=false", "-no-color", "-detailed-exitcode").Outputs("No changes.")
+	_, err = mockCLIent.Plan(context.Background(), config)
+	require.NoError(t, err)
+
+	e.Expect("terraform", "init", "-input=false", "-reconfigure")
+	e.Expect("terraform", "output", "-json").Outputs("{}")
+	_, err = mockCLIent.BuildOutput(context.Background(), config)
+	require.NoError(t, err)
+}
+
+func TestCLI_InitFailsWithoutLock(t *testing.T) {
+	e := fakeexec.New(t)
+	defer e.Finish()
+	mockCLIent, err := terraform.New(iaas.AWS, terraform.FakeExec(e.CmdContext()), terraform.EnsureLock(func() error {
+		return errors.New("no dynamodb")
+	}))
+	require.NoError(t, err)
+
+	_, err = mockCLIent.Apply(context.Background(), &terraform.AWSInputVars{})
+	require.EqualError(t, err, "no dynamodb")
 }
 
 func TestCLI_Destroy(t *testing.T) {
 	e := fakeexec.New(t)
 	defer e.Finish()
-	mockCLIent, err := terraform.New(iaas.AWS, terraform.FakeExec(e.Cmd()))
+	mockCLIent, err := terraform.New(iaas.AWS, terraform.FakeExec(e.CmdContext()))
 	require.NoError(t, err)
 
 	config := &mockTerraformInputVars{}
@@ -94,6 +283,54 @@ func TestCLI_Destroy(t *testing.T) {
 		require.Equal(t, args[1], "-auto-approve")
 
 	})
-	err = mockCLIent.Destroy(config)
+	err = mockCLIent.Destroy(context.Background(), config)
 	require.NoError(t, err)
 }
+
+func TestCLI_Plan(t *testing.T) {
+	e := fakeexec.New(t)
+	defer e.Finish()
+	mockCLIent, err := terraform.New(iaas.AWS, terraform.FakeExec(e.CmdContext()))
+	require.NoError(t, err)
+
+	config := &mockTerraformInputVars{}
+
+	e.ExpectFunc(func(t testing.TB, command string, args ...string) {
+		require.Equal(t, "terraform", command)
+		require.Equal(t, args[0], "init")
+	})
+	plan := e.ExpectFunc(func(t testing.TB, command string, args ...string) {
+		require.Equal(t, "terraform", command)
+		require.Equal(t, args[0], "plan")
+		require.Contains(t, args, "-detailed-exitcode")
+		require.Contains(t, args, "-lock=false")
+	})
+	plan.Outputs("  # aws_db_instance.default must be replaced\nPlan: 1 to add, 0 to change, 1 to destroy.\n")
+	plan.Exits(2)
+
+	summary, err := mockCLIent.Plan(context.Background(), config)
+	require.NoError(t, err)
+	require.Equal(t, 1, summary.ToAdd)
+	require.Equal(t, 1, summary.ToDestroy)
+	require.Equal(t, []string{"aws_db_instance.default"}, summary.Replaced)
+	require.True(t, summary.IsDestructive())
+}
+
+func TestCLI_PlanFailure(t *testing.T) {
+	e := fakeexec.New(t)
+	defer e.Finish()
+	mockCLIent, err := terraform.New(iaas.AWS, terraform.FakeExec(e.CmdContext()))
+	require.NoError(t, err)
+
+	config := &mockTerraformInputVars{}
+
+	e.ExpectFunc(func(t testing.TB, command string, args ...string) {
+		require.Equal(t, args[0], "init")
+	})
+	e.ExpectFunc(func(t testing.TB, command string, args ...string) {
+		require.Equal(t, args[0], "plan")
+	}).Exits(1)
+
+	_, err = mockCLIent.Plan(context.Background(), config)
+	require.Error(t, err)
+}
@@ -2,27 +2,32 @@
 package terraformfakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/EngineerBetter/control-tower/terraform"
 )
 
 type FakeCLIInterface struct {
-	ApplyStub        func(terraform.InputVars) error
+	ApplyStub        func(context.Context, terraform.InputVars) (terraform.Outputs, error)
 	applyMutex       sync.RWMutex
 	applyArgsForCall []struct {
-		arg1 terraform.InputVars
+		arg1 context.Context
+		arg2 terraform.InputVars
 	}
 	applyReturns struct {
-		result1 error
+		result1 terraform.Outputs
+		result2 error
 	}
 	applyReturnsOnCall map[int]struct {
-		result1 error
+		result1 terraform.Outputs
+		result2 error
 	}
-	BuildOutputStub        func(terraform.InputVars) (terraform.Outputs, error)
+	BuildOutputStub        func(context.Context, terraform.InputVars) (terraform.Outputs, error)
 	buildOutputMutex       sync.RWMutex
 	buildOutputArgsForCall []struct {
-		arg1 terraform.InputVars
+		arg1 context.Context
+		arg2 terraform.InputVars
 	}
 	buildOutputReturns struct {
 		result1 terraform.Outputs
@@ -32,10 +37,11 @@ type FakeCLIInterface struct {
 		result1 terraform.Outputs
 		result2 error
 	}
-	DestroyStub        func(terraform.InputVars) error
+	DestroyStub        func(context.Context, terraform.InputVars) error
 	destroyMutex       sync.RWMutex
 	destroyArgsForCall []struct {
-		arg1 terraform.InputVars
+		arg1 context.Context
+		arg2 terraform.InputVars
 	}
 	destroyReturns struct {
 		result1 error
@@ -43,26 +49,41 @@ type FakeCLIInterface struct {
 	destroyReturnsOnCall map[int]struct {
 		result1 error
 	}
+	PlanStub        func(context.Context, terraform.InputVars) (terraform.PlanSummary, error)
+	planMutex       sync.RWMutex
+	planArgsForCall []struct {
+		arg1 context.Context
+		arg2 terraform.InputVars
+	}
+	planReturns struct {
+		result1 terraform.PlanSummary
+		result2 error
+	}
+	planReturnsOnCall map[int]struct {
+		result1 terraform.PlanSummary
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeCLIInterface) Apply(arg1 terraform.InputVars) error {
+func (fake *FakeCLIInterface) Apply(arg1 context.Context, arg2 terraform.InputVars) (terraform.Outputs, error) {
 	fake.applyMutex.Lock()
 	ret, specificReturn := fake.applyReturnsOnCall[len(fake.applyArgsForCall)]
 	fake.applyArgsForCall = append(fake.applyArgsForCall, struct {
-		arg1 terraform.InputVars
-	}{arg1})
-	fake.recordInvocation("Apply", []interface{}{arg1})
+		arg1 context.Context
+		arg2 terraform.InputVars
+	}{arg1, arg2})
+	fake.recordInvocation("Apply", []interface{}{arg1, arg2})
 	fake.applyMutex.Unlock()
 	if fake.ApplyStub != nil {
-		return fake.ApplyStub(arg1)
+		return fake.ApplyStub(arg1, arg2)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
 	fakeReturns := fake.applyReturns
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeCLIInterface) ApplyCallCount() int {
@@ -71,52 +92,56 @@ func (fake *FakeCLIInterface) ApplyCallCount() int {
 	return len(fake.applyArgsForCall)
 }
 
-func (fake *FakeCLIInterface) ApplyCalls(stub func(terraform.InputVars) error) {
+func (fake *FakeCLIInterface) ApplyCalls(stub func(context.Context, terraform.InputVars) (terraform.Outputs, error)) {
 	fake.applyMutex.Lock()
 	defer fake.applyMutex.Unlock()
 	fake.ApplyStub = stub
 }
 
-func (fake *FakeCLIInterface) ApplyArgsForCall(i int) terraform.InputVars {
+func (fake *FakeCLIInterface) ApplyArgsForCall(i int) (context.Context, terraform.InputVars) {
 	fake.applyMutex.RLock()
 	defer fake.applyMutex.RUnlock()
 	argsForCall := fake.applyArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeCLIInterface) ApplyReturns(result1 error) {
+func (fake *FakeCLIInterface) ApplyReturns(result1 terraform.Outputs, result2 error) {
 	fake.applyMutex.Lock()
 	defer fake.applyMutex.Unlock()
 	fake.ApplyStub = nil
 	fake.applyReturns = struct {
-		result1 error
-	}{result1}
+		result1 terraform.Outputs
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeCLIInterface) ApplyReturnsOnCall(i int, result1 error) {
+func (fake *FakeCLIInterface) ApplyReturnsOnCall(i int, result1 terraform.Outputs, result2 error) {
 	fake.applyMutex.Lock()
 	defer fake.applyMutex.Unlock()
 	fake.ApplyStub = nil
 	if fake.applyReturnsOnCall == nil {
 		fake.applyReturnsOnCall = make(map[int]struct {
-			result1 error
+			result1 terraform.Outputs
+			result2 error
 		})
 	}
 	fake.applyReturnsOnCall[i] = struct {
-		result1 error
-	}{result1}
+		result1 terraform.Outputs
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeCLIInterface) BuildOutput(arg1 terraform.InputVars) (terraform.Outputs, error) {
+func (fake *FakeCLIInterface) BuildOutput(arg1 context.Context, arg2 terraform.InputVars) (terraform.Outputs, error) {
 	fake.buildOutputMutex.Lock()
 	ret, specificReturn := fake.buildOutputReturnsOnCall[len(fake.buildOutputArgsForCall)]
 	fake.buildOutputArgsForCall = append(fake.buildOutputArgsForCall, struct {
-		arg1 terraform.InputVars
-	}{arg1})
-	fake.recordInvocation("BuildOutput", []interface{}{arg1})
+		arg1 context.Context
+		arg2 terraform.InputVars
+	}{arg1, arg2})
+	fake.recordInvocation("BuildOutput", []interface{}{arg1, arg2})
 	fake.buildOutputMutex.Unlock()
 	if fake.BuildOutputStub != nil {
-		return fake.BuildOutputStub(arg1)
+		return fake.BuildOutputStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -131,17 +156,17 @@ func (fake *FakeCLIInterface) BuildOutputCallCount() int {
 	return len(fake.buildOutputArgsForCall)
 }
 
-func (fake *FakeCLIInterface) BuildOutputCalls(stub func(terraform.InputVars) (terraform.Outputs, error)) {
+func (fake *FakeCLIInterface) BuildOutputCalls(stub func(context.Context, terraform.InputVars) (terraform.Outputs, error)) {
 	fake.buildOutputMutex.Lock()
 	defer fake.buildOutputMutex.Unlock()
 	fake.BuildOutputStub = stub
 }
 
-func (fake *FakeCLIInterface) BuildOutputArgsForCall(i int) terraform.InputVars {
+func (fake *FakeCLIInterface) BuildOutputArgsForCall(i int) (context.Context, terraform.InputVars) {
 	fake.buildOutputMutex.RLock()
 	defer fake.buildOutputMutex.RUnlock()
 	argsForCall := fake.buildOutputArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeCLIInterface) BuildOutputReturns(result1 terraform.Outputs, result2 error) {
@@ -170,16 +195,17 @@ func (fake *FakeCLIInterface) BuildOutputReturnsOnCall(i int, result1 terraform.
 	}{result1, result2}
 }
 
-func (fake *FakeCLIInterface) Destroy(arg1 terraform.InputVars) error {
+func (fake *FakeCLIInterface) Destroy(arg1 context.Context, arg2 terraform.InputVars) error {
 	fake.destroyMutex.Lock()
 	ret, specificReturn := fake.destroyReturnsOnCall[len(fake.destroyArgsForCall)]
 	fake.destroyArgsForCall = append(fake.destroyArgsForCall, struct {
-		arg1 terraform.InputVars
-	}{arg1})
-	fake.recordInvocation("Destroy", []interface{}{arg1})
+		arg1 context.Context
+		arg2 terraform.InputVars
+	}{arg1, arg2})
+	fake.recordInvocation("Destroy", []interface{}{arg1, arg2})
 	fake.destroyMutex.Unlock()
 	if fake.DestroyStub != nil {
-		return fake.DestroyStub(arg1)
+		return fake.DestroyStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -194,17 +220,17 @@ func (fake *FakeCLIInterface) DestroyCallCount() int {
 	return len(fake.destroyArgsForCall)
 }
 
-func (fake *FakeCLIInterface) DestroyCalls(stub func(terraform.InputVars) error) {
+func (fake *FakeCLIInterface) DestroyCalls(stub func(context.Context, terraform.InputVars) error) {
 	fake.destroyMutex.Lock()
 	defer fake.destroyMutex.Unlock()
 	fake.DestroyStub = stub
 }
 
-func (fake *FakeCLIInterface) DestroyArgsForCall(i int) terraform.InputVars {
+func (fake *FakeCLIInterface) DestroyArgsForCall(i int) (context.Context, terraform.InputVars) {
 	fake.destroyMutex.RLock()
 	defer fake.destroyMutex.RUnlock()
 	argsForCall := fake.destroyArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeCLIInterface) DestroyReturns(result1 error) {
@@ -230,6 +256,70 @@ func (fake *FakeCLIInterface) DestroyReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeCLIInterface) Plan(arg1 context.Context, arg2 terraform.InputVars) (terraform.PlanSummary, error) {
+	fake.planMutex.Lock()
+	ret, specificReturn := fake.planReturnsOnCall[len(fake.planArgsForCall)]
+	fake.planArgsForCall = append(fake.planArgsForCall, struct {
+		arg1 context.Context
+		arg2 terraform.InputVars
+	}{arg1, arg2})
+	fake.recordInvocation("Plan", []interface{}{arg1, arg2})
+	fake.planMutex.Unlock()
+	if fake.PlanStub != nil {
+		return fake.PlanStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.planReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCLIInterface) PlanCallCount() int {
+	fake.planMutex.RLock()
+	defer fake.planMutex.RUnlock()
+	return len(fake.planArgsForCall)
+}
+
+func (fake *FakeCLIInterface) PlanCalls(stub func(context.Context, terraform.InputVars) (terraform.PlanSummary, error)) {
+	fake.planMutex.Lock()
+	defer fake.planMutex.Unlock()
+	fake.PlanStub = stub
+}
+
+func (fake *FakeCLIInterface) PlanArgsForCall(i int) (context.Context, terraform.InputVars) {
+	fake.planMutex.RLock()
+	defer fake.planMutex.RUnlock()
+	argsForCall := fake.planArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCLIInterface) PlanReturns(result1 terraform.PlanSummary, result2 error) {
+	fake.planMutex.Lock()
+	defer fake.planMutex.Unlock()
+	fake.PlanStub = nil
+	fake.planReturns = struct {
+		result1 terraform.PlanSummary
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCLIInterface) PlanReturnsOnCall(i int, result1 terraform.PlanSummary, result2 error) {
+	fake.planMutex.Lock()
+	defer fake.planMutex.Unlock()
+	fake.PlanStub = nil
+	if fake.planReturnsOnCall == nil {
+		fake.planReturnsOnCall = make(map[int]struct {
+			result1 terraform.PlanSummary
+			result2 error
+		})
+	}
+	fake.planReturnsOnCall[i] = struct {
+		result1 terraform.PlanSummary
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeCLIInterface) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -239,6 +329,8 @@ func (fake *FakeCLIInterface) Invocations() map[string][][]interface{} {
 	defer fake.buildOutputMutex.RUnlock()
 	fake.destroyMutex.RLock()
 	defer fake.destroyMutex.RUnlock()
+	fake.planMutex.RLock()
+	defer fake.planMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
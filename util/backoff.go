@@ -0,0 +1,42 @@
+package util
+
+import (
+	"context"
+	"time"
+)
+
+// Backoff spaces out the attempts of a polling loop, doubling the delay after each attempt up to Max
+type Backoff struct {
+	Initial time.Duration
+	Max     time.Duration
+	next    time.Duration
+}
+
+// NewBackoff returns a Backoff that first waits initial and never waits longer than max
+func NewBackoff(initial, max time.Duration) *Backoff {
+	return &Backoff{Initial: initial, Max: max}
+}
+
+// Wait sleeps for the next delay, returning early with the context's error if it is cancelled or its deadline passes
+func (b *Backoff) Wait(ctx context.Context) error {
+	if b.next == 0 {
+		b.next = b.Initial
+	}
+	delay := b.next
+	if b.next *= 2; b.next > b.Max {
+		b.next = b.Max
+	}
+	return Sleep(ctx, delay)
+}
+
+// Sleep pauses for d, returning early with the context's error if it is cancelled or its deadline passes
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
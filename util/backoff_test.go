@@ -0,0 +1,72 @@
+package util_test
+
+import (
+	"context"
+	"syscall"
+	"time"
+
+	"github.com/EngineerBetter/control-tower/util"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Backoff", func() {
+	It("doubles the delay up to the maximum", func() {
+		backoff := util.NewBackoff(time.Millisecond, 3*time.Millisecond)
+
+		for _, want := range []time.Duration{time.Millisecond, 2 * time.Millisecond, 3 * time.Millisecond, 3 * time.Millisecond} {
+			start := time.Now()
+			Expect(backoff.Wait(context.Background())).To(Succeed())
+			Expect(time.Since(start)).To(BeNumerically(">=", want))
+		}
+	})
+
+	It("returns early when the context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		start := time.Now()
+		err := util.NewBackoff(time.Hour, time.Hour).Wait(ctx)
+		Expect(err).To(Equal(context.Canceled))
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+	})
+
+	It("returns when the deadline passes", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		Expect(util.Sleep(ctx, time.Hour)).To(Equal(context.DeadlineExceeded))
+	})
+})
+
+var _ = Describe("CommandContext", func() {
+	It("interrupts the command when the context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cmd := util.CommandContext(ctx, "sh", "-c", "trap 'exit 3' INT; sleep 10 & wait")
+		Expect(cmd.Start()).To(Succeed())
+
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+		err := cmd.Wait()
+
+		Expect(err).To(HaveOccurred())
+		Expect(cmd.ProcessState.ExitCode()).To(Equal(3))
+	})
+
+	It("interrupts the command only once when control-tower is interrupted from the terminal", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cmd := util.CommandContext(ctx, "sh", "-c", "trap 'exit 3' INT; sleep 10 & wait")
+		Expect(cmd.Start()).To(Succeed())
+
+		// The terminal sends Ctrl-C to its foreground process group, which the command is not in
+		pgid, err := syscall.Getpgid(cmd.Process.Pid)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pgid).To(Equal(cmd.Process.Pid))
+		Expect(pgid).ToNot(Equal(syscall.Getpgrp()))
+
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+		Expect(cmd.Wait()).To(HaveOccurred())
+		Expect(cmd.ProcessState.ExitCode()).To(Equal(3))
+	})
+})
@@ -0,0 +1,38 @@
+package util
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// InterruptGracePeriod is how long a command is given to exit after being interrupted before it is killed.
+// It is long enough for bosh create-env and terraform to finish writing their state.
+const InterruptGracePeriod = 5 * time.Minute
+
+// CommandContext returns a command which is sent SIGINT rather than killed when ctx is cancelled,
+// so that it can exit cleanly and leave its state files intact. The command runs in its own process
+// group, so that Ctrl-C in the terminal does not also interrupt it: terraform exits immediately on a
+// second interrupt, which would lose its state
+func CommandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = InterruptGracePeriod
+	return cmd
+}
+
+// Interactive keeps cmd, returned by CommandContext, in the terminal's process group so that it can read
+// from the terminal. The terminal interrupts it on Ctrl-C, so it is not sent a second interrupt
+func Interactive(cmd *exec.Cmd) {
+	cmd.SysProcAttr = nil
+	if cmd.Cancel != nil {
+		cmd.Cancel = func() error {
+			return nil
+		}
+	}
+}